@@ -41,6 +41,18 @@ const (
 	// AdHocProfileServiceListProcedure is the fully-qualified name of the AdHocProfileService's List
 	// RPC.
 	AdHocProfileServiceListProcedure = "/adhocprofiles.v1.AdHocProfileService/List"
+	// AdHocProfileServiceDeleteProcedure is the fully-qualified name of the AdHocProfileService's
+	// Delete RPC.
+	AdHocProfileServiceDeleteProcedure = "/adhocprofiles.v1.AdHocProfileService/Delete"
+	// AdHocProfileServiceUpdateProcedure is the fully-qualified name of the AdHocProfileService's
+	// Update RPC.
+	AdHocProfileServiceUpdateProcedure = "/adhocprofiles.v1.AdHocProfileService/Update"
+	// AdHocProfileServiceDiffProcedure is the fully-qualified name of the AdHocProfileService's Diff
+	// RPC.
+	AdHocProfileServiceDiffProcedure = "/adhocprofiles.v1.AdHocProfileService/Diff"
+	// AdHocProfileServiceIngestProcedure is the fully-qualified name of the AdHocProfileService's
+	// Ingest RPC.
+	AdHocProfileServiceIngestProcedure = "/adhocprofiles.v1.AdHocProfileService/Ingest"
 )
 
 // These variables are the protoreflect.Descriptor objects for the RPCs defined in this package.
@@ -49,6 +61,10 @@ var (
 	adHocProfileServiceUploadMethodDescriptor = adHocProfileServiceServiceDescriptor.Methods().ByName("Upload")
 	adHocProfileServiceGetMethodDescriptor    = adHocProfileServiceServiceDescriptor.Methods().ByName("Get")
 	adHocProfileServiceListMethodDescriptor   = adHocProfileServiceServiceDescriptor.Methods().ByName("List")
+	adHocProfileServiceDeleteMethodDescriptor = adHocProfileServiceServiceDescriptor.Methods().ByName("Delete")
+	adHocProfileServiceUpdateMethodDescriptor = adHocProfileServiceServiceDescriptor.Methods().ByName("Update")
+	adHocProfileServiceDiffMethodDescriptor   = adHocProfileServiceServiceDescriptor.Methods().ByName("Diff")
+	adHocProfileServiceIngestMethodDescriptor = adHocProfileServiceServiceDescriptor.Methods().ByName("Ingest")
 )
 
 // AdHocProfileServiceClient is a client for the adhocprofiles.v1.AdHocProfileService service.
@@ -59,8 +75,19 @@ type AdHocProfileServiceClient interface {
 	// Retrieves a profile from the underlying store by id and an optional sample type. The response is similar to the one
 	// for the upload method.
 	Get(context.Context, *connect.Request[v1.AdHocProfilesGetRequest]) (*connect.Response[v1.AdHocProfilesGetResponse], error)
-	// Retrieves a list of profiles found in the underlying store.
+	// Retrieves a list of profiles found in the underlying store. The list is ordered by upload time, newest first, and
+	// can be filtered and paginated.
 	List(context.Context, *connect.Request[v1.AdHocProfilesListRequest]) (*connect.Response[v1.AdHocProfilesListResponse], error)
+	// Deletes a profile from the underlying store by id.
+	Delete(context.Context, *connect.Request[v1.AdHocProfilesDeleteRequest]) (*connect.Response[v1.AdHocProfilesDeleteResponse], error)
+	// Updates the metadata (name and tags) of a profile. The profile id does not change.
+	Update(context.Context, *connect.Request[v1.AdHocProfilesUpdateRequest]) (*connect.Response[v1.AdHocProfilesUpdateResponse], error)
+	// Compares two profiles by id and returns a diff flame graph, where the profile identified by left_id is the
+	// baseline.
+	Diff(context.Context, *connect.Request[v1.AdHocProfilesDiffRequest]) (*connect.Response[v1.AdHocProfilesDiffResponse], error)
+	// Ingests a profile into the time-series store using the regular write path, so that it can be queried with label
+	// selectors and compared against other profiles. The profile stays available as an ad hoc profile.
+	Ingest(context.Context, *connect.Request[v1.AdHocProfilesIngestRequest]) (*connect.Response[v1.AdHocProfilesIngestResponse], error)
 }
 
 // NewAdHocProfileServiceClient constructs a client for the adhocprofiles.v1.AdHocProfileService
@@ -91,6 +118,30 @@ func NewAdHocProfileServiceClient(httpClient connect.HTTPClient, baseURL string,
 			connect.WithSchema(adHocProfileServiceListMethodDescriptor),
 			connect.WithClientOptions(opts...),
 		),
+		delete: connect.NewClient[v1.AdHocProfilesDeleteRequest, v1.AdHocProfilesDeleteResponse](
+			httpClient,
+			baseURL+AdHocProfileServiceDeleteProcedure,
+			connect.WithSchema(adHocProfileServiceDeleteMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		update: connect.NewClient[v1.AdHocProfilesUpdateRequest, v1.AdHocProfilesUpdateResponse](
+			httpClient,
+			baseURL+AdHocProfileServiceUpdateProcedure,
+			connect.WithSchema(adHocProfileServiceUpdateMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		diff: connect.NewClient[v1.AdHocProfilesDiffRequest, v1.AdHocProfilesDiffResponse](
+			httpClient,
+			baseURL+AdHocProfileServiceDiffProcedure,
+			connect.WithSchema(adHocProfileServiceDiffMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		ingest: connect.NewClient[v1.AdHocProfilesIngestRequest, v1.AdHocProfilesIngestResponse](
+			httpClient,
+			baseURL+AdHocProfileServiceIngestProcedure,
+			connect.WithSchema(adHocProfileServiceIngestMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
@@ -99,6 +150,10 @@ type adHocProfileServiceClient struct {
 	upload *connect.Client[v1.AdHocProfilesUploadRequest, v1.AdHocProfilesGetResponse]
 	get    *connect.Client[v1.AdHocProfilesGetRequest, v1.AdHocProfilesGetResponse]
 	list   *connect.Client[v1.AdHocProfilesListRequest, v1.AdHocProfilesListResponse]
+	delete *connect.Client[v1.AdHocProfilesDeleteRequest, v1.AdHocProfilesDeleteResponse]
+	update *connect.Client[v1.AdHocProfilesUpdateRequest, v1.AdHocProfilesUpdateResponse]
+	diff   *connect.Client[v1.AdHocProfilesDiffRequest, v1.AdHocProfilesDiffResponse]
+	ingest *connect.Client[v1.AdHocProfilesIngestRequest, v1.AdHocProfilesIngestResponse]
 }
 
 // Upload calls adhocprofiles.v1.AdHocProfileService.Upload.
@@ -116,6 +171,26 @@ func (c *adHocProfileServiceClient) List(ctx context.Context, req *connect.Reque
 	return c.list.CallUnary(ctx, req)
 }
 
+// Delete calls adhocprofiles.v1.AdHocProfileService.Delete.
+func (c *adHocProfileServiceClient) Delete(ctx context.Context, req *connect.Request[v1.AdHocProfilesDeleteRequest]) (*connect.Response[v1.AdHocProfilesDeleteResponse], error) {
+	return c.delete.CallUnary(ctx, req)
+}
+
+// Update calls adhocprofiles.v1.AdHocProfileService.Update.
+func (c *adHocProfileServiceClient) Update(ctx context.Context, req *connect.Request[v1.AdHocProfilesUpdateRequest]) (*connect.Response[v1.AdHocProfilesUpdateResponse], error) {
+	return c.update.CallUnary(ctx, req)
+}
+
+// Diff calls adhocprofiles.v1.AdHocProfileService.Diff.
+func (c *adHocProfileServiceClient) Diff(ctx context.Context, req *connect.Request[v1.AdHocProfilesDiffRequest]) (*connect.Response[v1.AdHocProfilesDiffResponse], error) {
+	return c.diff.CallUnary(ctx, req)
+}
+
+// Ingest calls adhocprofiles.v1.AdHocProfileService.Ingest.
+func (c *adHocProfileServiceClient) Ingest(ctx context.Context, req *connect.Request[v1.AdHocProfilesIngestRequest]) (*connect.Response[v1.AdHocProfilesIngestResponse], error) {
+	return c.ingest.CallUnary(ctx, req)
+}
+
 // AdHocProfileServiceHandler is an implementation of the adhocprofiles.v1.AdHocProfileService
 // service.
 type AdHocProfileServiceHandler interface {
@@ -125,8 +200,19 @@ type AdHocProfileServiceHandler interface {
 	// Retrieves a profile from the underlying store by id and an optional sample type. The response is similar to the one
 	// for the upload method.
 	Get(context.Context, *connect.Request[v1.AdHocProfilesGetRequest]) (*connect.Response[v1.AdHocProfilesGetResponse], error)
-	// Retrieves a list of profiles found in the underlying store.
+	// Retrieves a list of profiles found in the underlying store. The list is ordered by upload time, newest first, and
+	// can be filtered and paginated.
 	List(context.Context, *connect.Request[v1.AdHocProfilesListRequest]) (*connect.Response[v1.AdHocProfilesListResponse], error)
+	// Deletes a profile from the underlying store by id.
+	Delete(context.Context, *connect.Request[v1.AdHocProfilesDeleteRequest]) (*connect.Response[v1.AdHocProfilesDeleteResponse], error)
+	// Updates the metadata (name and tags) of a profile. The profile id does not change.
+	Update(context.Context, *connect.Request[v1.AdHocProfilesUpdateRequest]) (*connect.Response[v1.AdHocProfilesUpdateResponse], error)
+	// Compares two profiles by id and returns a diff flame graph, where the profile identified by left_id is the
+	// baseline.
+	Diff(context.Context, *connect.Request[v1.AdHocProfilesDiffRequest]) (*connect.Response[v1.AdHocProfilesDiffResponse], error)
+	// Ingests a profile into the time-series store using the regular write path, so that it can be queried with label
+	// selectors and compared against other profiles. The profile stays available as an ad hoc profile.
+	Ingest(context.Context, *connect.Request[v1.AdHocProfilesIngestRequest]) (*connect.Response[v1.AdHocProfilesIngestResponse], error)
 }
 
 // NewAdHocProfileServiceHandler builds an HTTP handler from the service implementation. It returns
@@ -153,6 +239,30 @@ func NewAdHocProfileServiceHandler(svc AdHocProfileServiceHandler, opts ...conne
 		connect.WithSchema(adHocProfileServiceListMethodDescriptor),
 		connect.WithHandlerOptions(opts...),
 	)
+	adHocProfileServiceDeleteHandler := connect.NewUnaryHandler(
+		AdHocProfileServiceDeleteProcedure,
+		svc.Delete,
+		connect.WithSchema(adHocProfileServiceDeleteMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	adHocProfileServiceUpdateHandler := connect.NewUnaryHandler(
+		AdHocProfileServiceUpdateProcedure,
+		svc.Update,
+		connect.WithSchema(adHocProfileServiceUpdateMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	adHocProfileServiceDiffHandler := connect.NewUnaryHandler(
+		AdHocProfileServiceDiffProcedure,
+		svc.Diff,
+		connect.WithSchema(adHocProfileServiceDiffMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	adHocProfileServiceIngestHandler := connect.NewUnaryHandler(
+		AdHocProfileServiceIngestProcedure,
+		svc.Ingest,
+		connect.WithSchema(adHocProfileServiceIngestMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/adhocprofiles.v1.AdHocProfileService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case AdHocProfileServiceUploadProcedure:
@@ -161,6 +271,14 @@ func NewAdHocProfileServiceHandler(svc AdHocProfileServiceHandler, opts ...conne
 			adHocProfileServiceGetHandler.ServeHTTP(w, r)
 		case AdHocProfileServiceListProcedure:
 			adHocProfileServiceListHandler.ServeHTTP(w, r)
+		case AdHocProfileServiceDeleteProcedure:
+			adHocProfileServiceDeleteHandler.ServeHTTP(w, r)
+		case AdHocProfileServiceUpdateProcedure:
+			adHocProfileServiceUpdateHandler.ServeHTTP(w, r)
+		case AdHocProfileServiceDiffProcedure:
+			adHocProfileServiceDiffHandler.ServeHTTP(w, r)
+		case AdHocProfileServiceIngestProcedure:
+			adHocProfileServiceIngestHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -181,3 +299,19 @@ func (UnimplementedAdHocProfileServiceHandler) Get(context.Context, *connect.Req
 func (UnimplementedAdHocProfileServiceHandler) List(context.Context, *connect.Request[v1.AdHocProfilesListRequest]) (*connect.Response[v1.AdHocProfilesListResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("adhocprofiles.v1.AdHocProfileService.List is not implemented"))
 }
+
+func (UnimplementedAdHocProfileServiceHandler) Delete(context.Context, *connect.Request[v1.AdHocProfilesDeleteRequest]) (*connect.Response[v1.AdHocProfilesDeleteResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("adhocprofiles.v1.AdHocProfileService.Delete is not implemented"))
+}
+
+func (UnimplementedAdHocProfileServiceHandler) Update(context.Context, *connect.Request[v1.AdHocProfilesUpdateRequest]) (*connect.Response[v1.AdHocProfilesUpdateResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("adhocprofiles.v1.AdHocProfileService.Update is not implemented"))
+}
+
+func (UnimplementedAdHocProfileServiceHandler) Diff(context.Context, *connect.Request[v1.AdHocProfilesDiffRequest]) (*connect.Response[v1.AdHocProfilesDiffResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("adhocprofiles.v1.AdHocProfileService.Diff is not implemented"))
+}
+
+func (UnimplementedAdHocProfileServiceHandler) Ingest(context.Context, *connect.Request[v1.AdHocProfilesIngestRequest]) (*connect.Response[v1.AdHocProfilesIngestResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("adhocprofiles.v1.AdHocProfileService.Ingest is not implemented"))
+}
@@ -0,0 +1,178 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: annotations/v1/annotations.proto
+
+package annotationsv1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/grafana/pyroscope/api/gen/proto/go/annotations/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// AnnotationServiceName is the fully-qualified name of the AnnotationService service.
+	AnnotationServiceName = "annotations.v1.AnnotationService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// AnnotationServiceCreateProcedure is the fully-qualified name of the AnnotationService's Create
+	// RPC.
+	AnnotationServiceCreateProcedure = "/annotations.v1.AnnotationService/Create"
+	// AnnotationServiceListProcedure is the fully-qualified name of the AnnotationService's List RPC.
+	AnnotationServiceListProcedure = "/annotations.v1.AnnotationService/List"
+	// AnnotationServiceDeleteProcedure is the fully-qualified name of the AnnotationService's Delete
+	// RPC.
+	AnnotationServiceDeleteProcedure = "/annotations.v1.AnnotationService/Delete"
+)
+
+// These variables are the protoreflect.Descriptor objects for the RPCs defined in this package.
+var (
+	annotationServiceServiceDescriptor      = v1.File_annotations_v1_annotations_proto.Services().ByName("AnnotationService")
+	annotationServiceCreateMethodDescriptor = annotationServiceServiceDescriptor.Methods().ByName("Create")
+	annotationServiceListMethodDescriptor   = annotationServiceServiceDescriptor.Methods().ByName("List")
+	annotationServiceDeleteMethodDescriptor = annotationServiceServiceDescriptor.Methods().ByName("Delete")
+)
+
+// AnnotationServiceClient is a client for the annotations.v1.AnnotationService service.
+type AnnotationServiceClient interface {
+	// Create an annotation, for example a deploy marker or an incident, for the tenant.
+	Create(context.Context, *connect.Request[v1.CreateAnnotationRequest]) (*connect.Response[v1.CreateAnnotationResponse], error)
+	// List the annotations of the tenant overlapping the requested time range. The list is ordered by start time.
+	List(context.Context, *connect.Request[v1.ListAnnotationsRequest]) (*connect.Response[v1.ListAnnotationsResponse], error)
+	// Delete an annotation by id.
+	Delete(context.Context, *connect.Request[v1.DeleteAnnotationRequest]) (*connect.Response[v1.DeleteAnnotationResponse], error)
+}
+
+// NewAnnotationServiceClient constructs a client for the annotations.v1.AnnotationService service.
+// By default, it uses the Connect protocol with the binary Protobuf Codec, asks for gzipped
+// responses, and sends uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the
+// connect.WithGRPC() or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewAnnotationServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) AnnotationServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	return &annotationServiceClient{
+		create: connect.NewClient[v1.CreateAnnotationRequest, v1.CreateAnnotationResponse](
+			httpClient,
+			baseURL+AnnotationServiceCreateProcedure,
+			connect.WithSchema(annotationServiceCreateMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		list: connect.NewClient[v1.ListAnnotationsRequest, v1.ListAnnotationsResponse](
+			httpClient,
+			baseURL+AnnotationServiceListProcedure,
+			connect.WithSchema(annotationServiceListMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		delete: connect.NewClient[v1.DeleteAnnotationRequest, v1.DeleteAnnotationResponse](
+			httpClient,
+			baseURL+AnnotationServiceDeleteProcedure,
+			connect.WithSchema(annotationServiceDeleteMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// annotationServiceClient implements AnnotationServiceClient.
+type annotationServiceClient struct {
+	create *connect.Client[v1.CreateAnnotationRequest, v1.CreateAnnotationResponse]
+	list   *connect.Client[v1.ListAnnotationsRequest, v1.ListAnnotationsResponse]
+	delete *connect.Client[v1.DeleteAnnotationRequest, v1.DeleteAnnotationResponse]
+}
+
+// Create calls annotations.v1.AnnotationService.Create.
+func (c *annotationServiceClient) Create(ctx context.Context, req *connect.Request[v1.CreateAnnotationRequest]) (*connect.Response[v1.CreateAnnotationResponse], error) {
+	return c.create.CallUnary(ctx, req)
+}
+
+// List calls annotations.v1.AnnotationService.List.
+func (c *annotationServiceClient) List(ctx context.Context, req *connect.Request[v1.ListAnnotationsRequest]) (*connect.Response[v1.ListAnnotationsResponse], error) {
+	return c.list.CallUnary(ctx, req)
+}
+
+// Delete calls annotations.v1.AnnotationService.Delete.
+func (c *annotationServiceClient) Delete(ctx context.Context, req *connect.Request[v1.DeleteAnnotationRequest]) (*connect.Response[v1.DeleteAnnotationResponse], error) {
+	return c.delete.CallUnary(ctx, req)
+}
+
+// AnnotationServiceHandler is an implementation of the annotations.v1.AnnotationService service.
+type AnnotationServiceHandler interface {
+	// Create an annotation, for example a deploy marker or an incident, for the tenant.
+	Create(context.Context, *connect.Request[v1.CreateAnnotationRequest]) (*connect.Response[v1.CreateAnnotationResponse], error)
+	// List the annotations of the tenant overlapping the requested time range. The list is ordered by start time.
+	List(context.Context, *connect.Request[v1.ListAnnotationsRequest]) (*connect.Response[v1.ListAnnotationsResponse], error)
+	// Delete an annotation by id.
+	Delete(context.Context, *connect.Request[v1.DeleteAnnotationRequest]) (*connect.Response[v1.DeleteAnnotationResponse], error)
+}
+
+// NewAnnotationServiceHandler builds an HTTP handler from the service implementation. It returns
+// the path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewAnnotationServiceHandler(svc AnnotationServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	annotationServiceCreateHandler := connect.NewUnaryHandler(
+		AnnotationServiceCreateProcedure,
+		svc.Create,
+		connect.WithSchema(annotationServiceCreateMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	annotationServiceListHandler := connect.NewUnaryHandler(
+		AnnotationServiceListProcedure,
+		svc.List,
+		connect.WithSchema(annotationServiceListMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	annotationServiceDeleteHandler := connect.NewUnaryHandler(
+		AnnotationServiceDeleteProcedure,
+		svc.Delete,
+		connect.WithSchema(annotationServiceDeleteMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/annotations.v1.AnnotationService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case AnnotationServiceCreateProcedure:
+			annotationServiceCreateHandler.ServeHTTP(w, r)
+		case AnnotationServiceListProcedure:
+			annotationServiceListHandler.ServeHTTP(w, r)
+		case AnnotationServiceDeleteProcedure:
+			annotationServiceDeleteHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedAnnotationServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedAnnotationServiceHandler struct{}
+
+func (UnimplementedAnnotationServiceHandler) Create(context.Context, *connect.Request[v1.CreateAnnotationRequest]) (*connect.Response[v1.CreateAnnotationResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("annotations.v1.AnnotationService.Create is not implemented"))
+}
+
+func (UnimplementedAnnotationServiceHandler) List(context.Context, *connect.Request[v1.ListAnnotationsRequest]) (*connect.Response[v1.ListAnnotationsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("annotations.v1.AnnotationService.List is not implemented"))
+}
+
+func (UnimplementedAnnotationServiceHandler) Delete(context.Context, *connect.Request[v1.DeleteAnnotationRequest]) (*connect.Response[v1.DeleteAnnotationResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("annotations.v1.AnnotationService.Delete is not implemented"))
+}
@@ -0,0 +1,37 @@
+// Code generated by protoc-gen-connect-go-mux. DO NOT EDIT.
+//
+// Source: annotations/v1/annotations.proto
+
+package annotationsv1connect
+
+import (
+	connect "connectrpc.com/connect"
+	mux "github.com/gorilla/mux"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion0_1_0
+
+// RegisterAnnotationServiceHandler register an HTTP handler to a mux.Router from the service
+// implementation.
+func RegisterAnnotationServiceHandler(mux *mux.Router, svc AnnotationServiceHandler, opts ...connect.HandlerOption) {
+	mux.Handle("/annotations.v1.AnnotationService/Create", connect.NewUnaryHandler(
+		"/annotations.v1.AnnotationService/Create",
+		svc.Create,
+		opts...,
+	))
+	mux.Handle("/annotations.v1.AnnotationService/List", connect.NewUnaryHandler(
+		"/annotations.v1.AnnotationService/List",
+		svc.List,
+		opts...,
+	))
+	mux.Handle("/annotations.v1.AnnotationService/Delete", connect.NewUnaryHandler(
+		"/annotations.v1.AnnotationService/Delete",
+		svc.Delete,
+		opts...,
+	))
+}
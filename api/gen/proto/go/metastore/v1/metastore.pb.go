@@ -236,6 +236,9 @@ type Dataset struct {
 	//   - 0: profiles.parquet
 	//   - 1: index.tsdb
 	//   - 2: symbols.symdb
+	// Format version 2 adds the downsampled profile tables:
+	//   - 3: profiles_5m_sum.parquet
+	//   - 4: profiles_1h_sum.parquet
 	TableOfContents []uint64 `protobuf:"varint,5,rep,packed,name=table_of_contents,json=tableOfContents,proto3" json:"table_of_contents,omitempty"`
 	// Size of the section in bytes.
 	Size uint64 `protobuf:"varint,6,opt,name=size,proto3" json:"size,omitempty"`
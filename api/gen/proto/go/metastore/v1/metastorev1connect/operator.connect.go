@@ -35,17 +35,48 @@ const (
 const (
 	// OperatorServiceInfoProcedure is the fully-qualified name of the OperatorService's Info RPC.
 	OperatorServiceInfoProcedure = "/metastore.v1.OperatorService/Info"
+	// OperatorServiceAddVoterProcedure is the fully-qualified name of the OperatorService's AddVoter
+	// RPC.
+	OperatorServiceAddVoterProcedure = "/metastore.v1.OperatorService/AddVoter"
+	// OperatorServiceAddNonvoterProcedure is the fully-qualified name of the OperatorService's
+	// AddNonvoter RPC.
+	OperatorServiceAddNonvoterProcedure = "/metastore.v1.OperatorService/AddNonvoter"
+	// OperatorServiceRemoveServerProcedure is the fully-qualified name of the OperatorService's
+	// RemoveServer RPC.
+	OperatorServiceRemoveServerProcedure = "/metastore.v1.OperatorService/RemoveServer"
+	// OperatorServiceTransferLeadershipProcedure is the fully-qualified name of the OperatorService's
+	// TransferLeadership RPC.
+	OperatorServiceTransferLeadershipProcedure = "/metastore.v1.OperatorService/TransferLeadership"
+	// OperatorServiceSnapshotProcedure is the fully-qualified name of the OperatorService's Snapshot
+	// RPC.
+	OperatorServiceSnapshotProcedure = "/metastore.v1.OperatorService/Snapshot"
 )
 
 // These variables are the protoreflect.Descriptor objects for the RPCs defined in this package.
 var (
-	operatorServiceServiceDescriptor    = v1.File_metastore_v1_operator_proto.Services().ByName("OperatorService")
-	operatorServiceInfoMethodDescriptor = operatorServiceServiceDescriptor.Methods().ByName("Info")
+	operatorServiceServiceDescriptor                  = v1.File_metastore_v1_operator_proto.Services().ByName("OperatorService")
+	operatorServiceInfoMethodDescriptor               = operatorServiceServiceDescriptor.Methods().ByName("Info")
+	operatorServiceAddVoterMethodDescriptor           = operatorServiceServiceDescriptor.Methods().ByName("AddVoter")
+	operatorServiceAddNonvoterMethodDescriptor        = operatorServiceServiceDescriptor.Methods().ByName("AddNonvoter")
+	operatorServiceRemoveServerMethodDescriptor       = operatorServiceServiceDescriptor.Methods().ByName("RemoveServer")
+	operatorServiceTransferLeadershipMethodDescriptor = operatorServiceServiceDescriptor.Methods().ByName("TransferLeadership")
+	operatorServiceSnapshotMethodDescriptor           = operatorServiceServiceDescriptor.Methods().ByName("Snapshot")
 )
 
 // OperatorServiceClient is a client for the metastore.v1.OperatorService service.
 type OperatorServiceClient interface {
 	Info(context.Context, *connect.Request[v1.InfoRequest]) (*connect.Response[v1.InfoResponse], error)
+	// AddVoter adds a new voter to the cluster, or promotes a non-voter.
+	AddVoter(context.Context, *connect.Request[v1.AddVoterRequest]) (*connect.Response[v1.AddVoterResponse], error)
+	// AddNonvoter adds a new non-voter to the cluster, or demotes a voter.
+	AddNonvoter(context.Context, *connect.Request[v1.AddNonvoterRequest]) (*connect.Response[v1.AddNonvoterResponse], error)
+	// RemoveServer removes the server from the cluster.
+	RemoveServer(context.Context, *connect.Request[v1.RemoveServerRequest]) (*connect.Response[v1.RemoveServerResponse], error)
+	// TransferLeadership transfers the leadership to the given voter,
+	// or to the most up-to-date one, if not specified.
+	TransferLeadership(context.Context, *connect.Request[v1.TransferLeadershipRequest]) (*connect.Response[v1.TransferLeadershipResponse], error)
+	// Snapshot takes a snapshot of the node state.
+	Snapshot(context.Context, *connect.Request[v1.SnapshotRequest]) (*connect.Response[v1.SnapshotResponse], error)
 }
 
 // NewOperatorServiceClient constructs a client for the metastore.v1.OperatorService service. By
@@ -64,12 +95,47 @@ func NewOperatorServiceClient(httpClient connect.HTTPClient, baseURL string, opt
 			connect.WithSchema(operatorServiceInfoMethodDescriptor),
 			connect.WithClientOptions(opts...),
 		),
+		addVoter: connect.NewClient[v1.AddVoterRequest, v1.AddVoterResponse](
+			httpClient,
+			baseURL+OperatorServiceAddVoterProcedure,
+			connect.WithSchema(operatorServiceAddVoterMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		addNonvoter: connect.NewClient[v1.AddNonvoterRequest, v1.AddNonvoterResponse](
+			httpClient,
+			baseURL+OperatorServiceAddNonvoterProcedure,
+			connect.WithSchema(operatorServiceAddNonvoterMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		removeServer: connect.NewClient[v1.RemoveServerRequest, v1.RemoveServerResponse](
+			httpClient,
+			baseURL+OperatorServiceRemoveServerProcedure,
+			connect.WithSchema(operatorServiceRemoveServerMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		transferLeadership: connect.NewClient[v1.TransferLeadershipRequest, v1.TransferLeadershipResponse](
+			httpClient,
+			baseURL+OperatorServiceTransferLeadershipProcedure,
+			connect.WithSchema(operatorServiceTransferLeadershipMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		snapshot: connect.NewClient[v1.SnapshotRequest, v1.SnapshotResponse](
+			httpClient,
+			baseURL+OperatorServiceSnapshotProcedure,
+			connect.WithSchema(operatorServiceSnapshotMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
 // operatorServiceClient implements OperatorServiceClient.
 type operatorServiceClient struct {
-	info *connect.Client[v1.InfoRequest, v1.InfoResponse]
+	info               *connect.Client[v1.InfoRequest, v1.InfoResponse]
+	addVoter           *connect.Client[v1.AddVoterRequest, v1.AddVoterResponse]
+	addNonvoter        *connect.Client[v1.AddNonvoterRequest, v1.AddNonvoterResponse]
+	removeServer       *connect.Client[v1.RemoveServerRequest, v1.RemoveServerResponse]
+	transferLeadership *connect.Client[v1.TransferLeadershipRequest, v1.TransferLeadershipResponse]
+	snapshot           *connect.Client[v1.SnapshotRequest, v1.SnapshotResponse]
 }
 
 // Info calls metastore.v1.OperatorService.Info.
@@ -77,9 +143,45 @@ func (c *operatorServiceClient) Info(ctx context.Context, req *connect.Request[v
 	return c.info.CallUnary(ctx, req)
 }
 
+// AddVoter calls metastore.v1.OperatorService.AddVoter.
+func (c *operatorServiceClient) AddVoter(ctx context.Context, req *connect.Request[v1.AddVoterRequest]) (*connect.Response[v1.AddVoterResponse], error) {
+	return c.addVoter.CallUnary(ctx, req)
+}
+
+// AddNonvoter calls metastore.v1.OperatorService.AddNonvoter.
+func (c *operatorServiceClient) AddNonvoter(ctx context.Context, req *connect.Request[v1.AddNonvoterRequest]) (*connect.Response[v1.AddNonvoterResponse], error) {
+	return c.addNonvoter.CallUnary(ctx, req)
+}
+
+// RemoveServer calls metastore.v1.OperatorService.RemoveServer.
+func (c *operatorServiceClient) RemoveServer(ctx context.Context, req *connect.Request[v1.RemoveServerRequest]) (*connect.Response[v1.RemoveServerResponse], error) {
+	return c.removeServer.CallUnary(ctx, req)
+}
+
+// TransferLeadership calls metastore.v1.OperatorService.TransferLeadership.
+func (c *operatorServiceClient) TransferLeadership(ctx context.Context, req *connect.Request[v1.TransferLeadershipRequest]) (*connect.Response[v1.TransferLeadershipResponse], error) {
+	return c.transferLeadership.CallUnary(ctx, req)
+}
+
+// Snapshot calls metastore.v1.OperatorService.Snapshot.
+func (c *operatorServiceClient) Snapshot(ctx context.Context, req *connect.Request[v1.SnapshotRequest]) (*connect.Response[v1.SnapshotResponse], error) {
+	return c.snapshot.CallUnary(ctx, req)
+}
+
 // OperatorServiceHandler is an implementation of the metastore.v1.OperatorService service.
 type OperatorServiceHandler interface {
 	Info(context.Context, *connect.Request[v1.InfoRequest]) (*connect.Response[v1.InfoResponse], error)
+	// AddVoter adds a new voter to the cluster, or promotes a non-voter.
+	AddVoter(context.Context, *connect.Request[v1.AddVoterRequest]) (*connect.Response[v1.AddVoterResponse], error)
+	// AddNonvoter adds a new non-voter to the cluster, or demotes a voter.
+	AddNonvoter(context.Context, *connect.Request[v1.AddNonvoterRequest]) (*connect.Response[v1.AddNonvoterResponse], error)
+	// RemoveServer removes the server from the cluster.
+	RemoveServer(context.Context, *connect.Request[v1.RemoveServerRequest]) (*connect.Response[v1.RemoveServerResponse], error)
+	// TransferLeadership transfers the leadership to the given voter,
+	// or to the most up-to-date one, if not specified.
+	TransferLeadership(context.Context, *connect.Request[v1.TransferLeadershipRequest]) (*connect.Response[v1.TransferLeadershipResponse], error)
+	// Snapshot takes a snapshot of the node state.
+	Snapshot(context.Context, *connect.Request[v1.SnapshotRequest]) (*connect.Response[v1.SnapshotResponse], error)
 }
 
 // NewOperatorServiceHandler builds an HTTP handler from the service implementation. It returns the
@@ -94,10 +196,50 @@ func NewOperatorServiceHandler(svc OperatorServiceHandler, opts ...connect.Handl
 		connect.WithSchema(operatorServiceInfoMethodDescriptor),
 		connect.WithHandlerOptions(opts...),
 	)
+	operatorServiceAddVoterHandler := connect.NewUnaryHandler(
+		OperatorServiceAddVoterProcedure,
+		svc.AddVoter,
+		connect.WithSchema(operatorServiceAddVoterMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	operatorServiceAddNonvoterHandler := connect.NewUnaryHandler(
+		OperatorServiceAddNonvoterProcedure,
+		svc.AddNonvoter,
+		connect.WithSchema(operatorServiceAddNonvoterMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	operatorServiceRemoveServerHandler := connect.NewUnaryHandler(
+		OperatorServiceRemoveServerProcedure,
+		svc.RemoveServer,
+		connect.WithSchema(operatorServiceRemoveServerMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	operatorServiceTransferLeadershipHandler := connect.NewUnaryHandler(
+		OperatorServiceTransferLeadershipProcedure,
+		svc.TransferLeadership,
+		connect.WithSchema(operatorServiceTransferLeadershipMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	operatorServiceSnapshotHandler := connect.NewUnaryHandler(
+		OperatorServiceSnapshotProcedure,
+		svc.Snapshot,
+		connect.WithSchema(operatorServiceSnapshotMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/metastore.v1.OperatorService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case OperatorServiceInfoProcedure:
 			operatorServiceInfoHandler.ServeHTTP(w, r)
+		case OperatorServiceAddVoterProcedure:
+			operatorServiceAddVoterHandler.ServeHTTP(w, r)
+		case OperatorServiceAddNonvoterProcedure:
+			operatorServiceAddNonvoterHandler.ServeHTTP(w, r)
+		case OperatorServiceRemoveServerProcedure:
+			operatorServiceRemoveServerHandler.ServeHTTP(w, r)
+		case OperatorServiceTransferLeadershipProcedure:
+			operatorServiceTransferLeadershipHandler.ServeHTTP(w, r)
+		case OperatorServiceSnapshotProcedure:
+			operatorServiceSnapshotHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -110,3 +252,23 @@ type UnimplementedOperatorServiceHandler struct{}
 func (UnimplementedOperatorServiceHandler) Info(context.Context, *connect.Request[v1.InfoRequest]) (*connect.Response[v1.InfoResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("metastore.v1.OperatorService.Info is not implemented"))
 }
+
+func (UnimplementedOperatorServiceHandler) AddVoter(context.Context, *connect.Request[v1.AddVoterRequest]) (*connect.Response[v1.AddVoterResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("metastore.v1.OperatorService.AddVoter is not implemented"))
+}
+
+func (UnimplementedOperatorServiceHandler) AddNonvoter(context.Context, *connect.Request[v1.AddNonvoterRequest]) (*connect.Response[v1.AddNonvoterResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("metastore.v1.OperatorService.AddNonvoter is not implemented"))
+}
+
+func (UnimplementedOperatorServiceHandler) RemoveServer(context.Context, *connect.Request[v1.RemoveServerRequest]) (*connect.Response[v1.RemoveServerResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("metastore.v1.OperatorService.RemoveServer is not implemented"))
+}
+
+func (UnimplementedOperatorServiceHandler) TransferLeadership(context.Context, *connect.Request[v1.TransferLeadershipRequest]) (*connect.Response[v1.TransferLeadershipResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("metastore.v1.OperatorService.TransferLeadership is not implemented"))
+}
+
+func (UnimplementedOperatorServiceHandler) Snapshot(context.Context, *connect.Request[v1.SnapshotRequest]) (*connect.Response[v1.SnapshotResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("metastore.v1.OperatorService.Snapshot is not implemented"))
+}
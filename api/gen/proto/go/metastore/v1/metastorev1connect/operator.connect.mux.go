@@ -24,4 +24,29 @@ func RegisterOperatorServiceHandler(mux *mux.Router, svc OperatorServiceHandler,
 		svc.Info,
 		opts...,
 	))
+	mux.Handle("/metastore.v1.OperatorService/AddVoter", connect.NewUnaryHandler(
+		"/metastore.v1.OperatorService/AddVoter",
+		svc.AddVoter,
+		opts...,
+	))
+	mux.Handle("/metastore.v1.OperatorService/AddNonvoter", connect.NewUnaryHandler(
+		"/metastore.v1.OperatorService/AddNonvoter",
+		svc.AddNonvoter,
+		opts...,
+	))
+	mux.Handle("/metastore.v1.OperatorService/RemoveServer", connect.NewUnaryHandler(
+		"/metastore.v1.OperatorService/RemoveServer",
+		svc.RemoveServer,
+		opts...,
+	))
+	mux.Handle("/metastore.v1.OperatorService/TransferLeadership", connect.NewUnaryHandler(
+		"/metastore.v1.OperatorService/TransferLeadership",
+		svc.TransferLeadership,
+		opts...,
+	))
+	mux.Handle("/metastore.v1.OperatorService/Snapshot", connect.NewUnaryHandler(
+		"/metastore.v1.OperatorService/Snapshot",
+		svc.Snapshot,
+		opts...,
+	))
 }
@@ -0,0 +1,155 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: serviceversions/v1/serviceversions.proto
+
+package serviceversionsv1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/grafana/pyroscope/api/gen/proto/go/serviceversions/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// ServiceVersionsServiceName is the fully-qualified name of the ServiceVersionsService service.
+	ServiceVersionsServiceName = "serviceversions.v1.ServiceVersionsService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// ServiceVersionsServiceListProcedure is the fully-qualified name of the ServiceVersionsService's
+	// List RPC.
+	ServiceVersionsServiceListProcedure = "/serviceversions.v1.ServiceVersionsService/List"
+	// ServiceVersionsServiceDiffProcedure is the fully-qualified name of the ServiceVersionsService's
+	// Diff RPC.
+	ServiceVersionsServiceDiffProcedure = "/serviceversions.v1.ServiceVersionsService/Diff"
+)
+
+// These variables are the protoreflect.Descriptor objects for the RPCs defined in this package.
+var (
+	serviceVersionsServiceServiceDescriptor    = v1.File_serviceversions_v1_serviceversions_proto.Services().ByName("ServiceVersionsService")
+	serviceVersionsServiceListMethodDescriptor = serviceVersionsServiceServiceDescriptor.Methods().ByName("List")
+	serviceVersionsServiceDiffMethodDescriptor = serviceVersionsServiceServiceDescriptor.Methods().ByName("Diff")
+)
+
+// ServiceVersionsServiceClient is a client for the serviceversions.v1.ServiceVersionsService
+// service.
+type ServiceVersionsServiceClient interface {
+	// List the versions of a service seen within the requested time range, with the time span each version was seen
+	// for. Versions are identified by the service_version label, or by the service_git_ref label if the former is
+	// missing.
+	List(context.Context, *connect.Request[v1.ListServiceVersionsRequest]) (*connect.Response[v1.ListServiceVersionsResponse], error)
+	// Diff the profiles of two versions of a service. The time range of each version is resolved automatically from
+	// the time span it was seen for. Commit information is included for versions with a repository and a git ref.
+	Diff(context.Context, *connect.Request[v1.DiffServiceVersionsRequest]) (*connect.Response[v1.DiffServiceVersionsResponse], error)
+}
+
+// NewServiceVersionsServiceClient constructs a client for the
+// serviceversions.v1.ServiceVersionsService service. By default, it uses the Connect protocol with
+// the binary Protobuf Codec, asks for gzipped responses, and sends uncompressed requests. To use
+// the gRPC or gRPC-Web protocols, supply the connect.WithGRPC() or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewServiceVersionsServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) ServiceVersionsServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	return &serviceVersionsServiceClient{
+		list: connect.NewClient[v1.ListServiceVersionsRequest, v1.ListServiceVersionsResponse](
+			httpClient,
+			baseURL+ServiceVersionsServiceListProcedure,
+			connect.WithSchema(serviceVersionsServiceListMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		diff: connect.NewClient[v1.DiffServiceVersionsRequest, v1.DiffServiceVersionsResponse](
+			httpClient,
+			baseURL+ServiceVersionsServiceDiffProcedure,
+			connect.WithSchema(serviceVersionsServiceDiffMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// serviceVersionsServiceClient implements ServiceVersionsServiceClient.
+type serviceVersionsServiceClient struct {
+	list *connect.Client[v1.ListServiceVersionsRequest, v1.ListServiceVersionsResponse]
+	diff *connect.Client[v1.DiffServiceVersionsRequest, v1.DiffServiceVersionsResponse]
+}
+
+// List calls serviceversions.v1.ServiceVersionsService.List.
+func (c *serviceVersionsServiceClient) List(ctx context.Context, req *connect.Request[v1.ListServiceVersionsRequest]) (*connect.Response[v1.ListServiceVersionsResponse], error) {
+	return c.list.CallUnary(ctx, req)
+}
+
+// Diff calls serviceversions.v1.ServiceVersionsService.Diff.
+func (c *serviceVersionsServiceClient) Diff(ctx context.Context, req *connect.Request[v1.DiffServiceVersionsRequest]) (*connect.Response[v1.DiffServiceVersionsResponse], error) {
+	return c.diff.CallUnary(ctx, req)
+}
+
+// ServiceVersionsServiceHandler is an implementation of the
+// serviceversions.v1.ServiceVersionsService service.
+type ServiceVersionsServiceHandler interface {
+	// List the versions of a service seen within the requested time range, with the time span each version was seen
+	// for. Versions are identified by the service_version label, or by the service_git_ref label if the former is
+	// missing.
+	List(context.Context, *connect.Request[v1.ListServiceVersionsRequest]) (*connect.Response[v1.ListServiceVersionsResponse], error)
+	// Diff the profiles of two versions of a service. The time range of each version is resolved automatically from
+	// the time span it was seen for. Commit information is included for versions with a repository and a git ref.
+	Diff(context.Context, *connect.Request[v1.DiffServiceVersionsRequest]) (*connect.Response[v1.DiffServiceVersionsResponse], error)
+}
+
+// NewServiceVersionsServiceHandler builds an HTTP handler from the service implementation. It
+// returns the path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewServiceVersionsServiceHandler(svc ServiceVersionsServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	serviceVersionsServiceListHandler := connect.NewUnaryHandler(
+		ServiceVersionsServiceListProcedure,
+		svc.List,
+		connect.WithSchema(serviceVersionsServiceListMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	serviceVersionsServiceDiffHandler := connect.NewUnaryHandler(
+		ServiceVersionsServiceDiffProcedure,
+		svc.Diff,
+		connect.WithSchema(serviceVersionsServiceDiffMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/serviceversions.v1.ServiceVersionsService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case ServiceVersionsServiceListProcedure:
+			serviceVersionsServiceListHandler.ServeHTTP(w, r)
+		case ServiceVersionsServiceDiffProcedure:
+			serviceVersionsServiceDiffHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedServiceVersionsServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedServiceVersionsServiceHandler struct{}
+
+func (UnimplementedServiceVersionsServiceHandler) List(context.Context, *connect.Request[v1.ListServiceVersionsRequest]) (*connect.Response[v1.ListServiceVersionsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("serviceversions.v1.ServiceVersionsService.List is not implemented"))
+}
+
+func (UnimplementedServiceVersionsServiceHandler) Diff(context.Context, *connect.Request[v1.DiffServiceVersionsRequest]) (*connect.Response[v1.DiffServiceVersionsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("serviceversions.v1.ServiceVersionsService.Diff is not implemented"))
+}
@@ -46,6 +46,12 @@ const (
 	VCSServiceGetCommitProcedure = "/vcs.v1.VCSService/GetCommit"
 	// VCSServiceGetCommitsProcedure is the fully-qualified name of the VCSService's GetCommits RPC.
 	VCSServiceGetCommitsProcedure = "/vcs.v1.VCSService/GetCommits"
+	// VCSServiceOAuthAppProcedure is the fully-qualified name of the VCSService's OAuthApp RPC.
+	VCSServiceOAuthAppProcedure = "/vcs.v1.VCSService/OAuthApp"
+	// VCSServiceOAuthLoginProcedure is the fully-qualified name of the VCSService's OAuthLogin RPC.
+	VCSServiceOAuthLoginProcedure = "/vcs.v1.VCSService/OAuthLogin"
+	// VCSServiceOAuthRefreshProcedure is the fully-qualified name of the VCSService's OAuthRefresh RPC.
+	VCSServiceOAuthRefreshProcedure = "/vcs.v1.VCSService/OAuthRefresh"
 )
 
 // These variables are the protoreflect.Descriptor objects for the RPCs defined in this package.
@@ -57,6 +63,9 @@ var (
 	vCSServiceGetFileMethodDescriptor       = vCSServiceServiceDescriptor.Methods().ByName("GetFile")
 	vCSServiceGetCommitMethodDescriptor     = vCSServiceServiceDescriptor.Methods().ByName("GetCommit")
 	vCSServiceGetCommitsMethodDescriptor    = vCSServiceServiceDescriptor.Methods().ByName("GetCommits")
+	vCSServiceOAuthAppMethodDescriptor      = vCSServiceServiceDescriptor.Methods().ByName("OAuthApp")
+	vCSServiceOAuthLoginMethodDescriptor    = vCSServiceServiceDescriptor.Methods().ByName("OAuthLogin")
+	vCSServiceOAuthRefreshMethodDescriptor  = vCSServiceServiceDescriptor.Methods().ByName("OAuthRefresh")
 )
 
 // VCSServiceClient is a client for the vcs.v1.VCSService service.
@@ -67,6 +76,11 @@ type VCSServiceClient interface {
 	GetFile(context.Context, *connect.Request[v1.GetFileRequest]) (*connect.Response[v1.GetFileResponse], error)
 	GetCommit(context.Context, *connect.Request[v1.GetCommitRequest]) (*connect.Response[v1.GetCommitResponse], error)
 	GetCommits(context.Context, *connect.Request[v1.GetCommitsRequest]) (*connect.Response[v1.GetCommitsResponse], error)
+	// OAuthApp returns the OAuth application of the VCS provider
+	// hosting the repository.
+	OAuthApp(context.Context, *connect.Request[v1.OAuthAppRequest]) (*connect.Response[v1.OAuthAppResponse], error)
+	OAuthLogin(context.Context, *connect.Request[v1.OAuthLoginRequest]) (*connect.Response[v1.OAuthLoginResponse], error)
+	OAuthRefresh(context.Context, *connect.Request[v1.OAuthRefreshRequest]) (*connect.Response[v1.OAuthRefreshResponse], error)
 }
 
 // NewVCSServiceClient constructs a client for the vcs.v1.VCSService service. By default, it uses
@@ -115,6 +129,24 @@ func NewVCSServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...
 			connect.WithSchema(vCSServiceGetCommitsMethodDescriptor),
 			connect.WithClientOptions(opts...),
 		),
+		oAuthApp: connect.NewClient[v1.OAuthAppRequest, v1.OAuthAppResponse](
+			httpClient,
+			baseURL+VCSServiceOAuthAppProcedure,
+			connect.WithSchema(vCSServiceOAuthAppMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		oAuthLogin: connect.NewClient[v1.OAuthLoginRequest, v1.OAuthLoginResponse](
+			httpClient,
+			baseURL+VCSServiceOAuthLoginProcedure,
+			connect.WithSchema(vCSServiceOAuthLoginMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		oAuthRefresh: connect.NewClient[v1.OAuthRefreshRequest, v1.OAuthRefreshResponse](
+			httpClient,
+			baseURL+VCSServiceOAuthRefreshProcedure,
+			connect.WithSchema(vCSServiceOAuthRefreshMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
@@ -126,6 +158,9 @@ type vCSServiceClient struct {
 	getFile       *connect.Client[v1.GetFileRequest, v1.GetFileResponse]
 	getCommit     *connect.Client[v1.GetCommitRequest, v1.GetCommitResponse]
 	getCommits    *connect.Client[v1.GetCommitsRequest, v1.GetCommitsResponse]
+	oAuthApp      *connect.Client[v1.OAuthAppRequest, v1.OAuthAppResponse]
+	oAuthLogin    *connect.Client[v1.OAuthLoginRequest, v1.OAuthLoginResponse]
+	oAuthRefresh  *connect.Client[v1.OAuthRefreshRequest, v1.OAuthRefreshResponse]
 }
 
 // GithubApp calls vcs.v1.VCSService.GithubApp.
@@ -158,6 +193,21 @@ func (c *vCSServiceClient) GetCommits(ctx context.Context, req *connect.Request[
 	return c.getCommits.CallUnary(ctx, req)
 }
 
+// OAuthApp calls vcs.v1.VCSService.OAuthApp.
+func (c *vCSServiceClient) OAuthApp(ctx context.Context, req *connect.Request[v1.OAuthAppRequest]) (*connect.Response[v1.OAuthAppResponse], error) {
+	return c.oAuthApp.CallUnary(ctx, req)
+}
+
+// OAuthLogin calls vcs.v1.VCSService.OAuthLogin.
+func (c *vCSServiceClient) OAuthLogin(ctx context.Context, req *connect.Request[v1.OAuthLoginRequest]) (*connect.Response[v1.OAuthLoginResponse], error) {
+	return c.oAuthLogin.CallUnary(ctx, req)
+}
+
+// OAuthRefresh calls vcs.v1.VCSService.OAuthRefresh.
+func (c *vCSServiceClient) OAuthRefresh(ctx context.Context, req *connect.Request[v1.OAuthRefreshRequest]) (*connect.Response[v1.OAuthRefreshResponse], error) {
+	return c.oAuthRefresh.CallUnary(ctx, req)
+}
+
 // VCSServiceHandler is an implementation of the vcs.v1.VCSService service.
 type VCSServiceHandler interface {
 	GithubApp(context.Context, *connect.Request[v1.GithubAppRequest]) (*connect.Response[v1.GithubAppResponse], error)
@@ -166,6 +216,11 @@ type VCSServiceHandler interface {
 	GetFile(context.Context, *connect.Request[v1.GetFileRequest]) (*connect.Response[v1.GetFileResponse], error)
 	GetCommit(context.Context, *connect.Request[v1.GetCommitRequest]) (*connect.Response[v1.GetCommitResponse], error)
 	GetCommits(context.Context, *connect.Request[v1.GetCommitsRequest]) (*connect.Response[v1.GetCommitsResponse], error)
+	// OAuthApp returns the OAuth application of the VCS provider
+	// hosting the repository.
+	OAuthApp(context.Context, *connect.Request[v1.OAuthAppRequest]) (*connect.Response[v1.OAuthAppResponse], error)
+	OAuthLogin(context.Context, *connect.Request[v1.OAuthLoginRequest]) (*connect.Response[v1.OAuthLoginResponse], error)
+	OAuthRefresh(context.Context, *connect.Request[v1.OAuthRefreshRequest]) (*connect.Response[v1.OAuthRefreshResponse], error)
 }
 
 // NewVCSServiceHandler builds an HTTP handler from the service implementation. It returns the path
@@ -210,6 +265,24 @@ func NewVCSServiceHandler(svc VCSServiceHandler, opts ...connect.HandlerOption)
 		connect.WithSchema(vCSServiceGetCommitsMethodDescriptor),
 		connect.WithHandlerOptions(opts...),
 	)
+	vCSServiceOAuthAppHandler := connect.NewUnaryHandler(
+		VCSServiceOAuthAppProcedure,
+		svc.OAuthApp,
+		connect.WithSchema(vCSServiceOAuthAppMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	vCSServiceOAuthLoginHandler := connect.NewUnaryHandler(
+		VCSServiceOAuthLoginProcedure,
+		svc.OAuthLogin,
+		connect.WithSchema(vCSServiceOAuthLoginMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	vCSServiceOAuthRefreshHandler := connect.NewUnaryHandler(
+		VCSServiceOAuthRefreshProcedure,
+		svc.OAuthRefresh,
+		connect.WithSchema(vCSServiceOAuthRefreshMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/vcs.v1.VCSService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case VCSServiceGithubAppProcedure:
@@ -224,6 +297,12 @@ func NewVCSServiceHandler(svc VCSServiceHandler, opts ...connect.HandlerOption)
 			vCSServiceGetCommitHandler.ServeHTTP(w, r)
 		case VCSServiceGetCommitsProcedure:
 			vCSServiceGetCommitsHandler.ServeHTTP(w, r)
+		case VCSServiceOAuthAppProcedure:
+			vCSServiceOAuthAppHandler.ServeHTTP(w, r)
+		case VCSServiceOAuthLoginProcedure:
+			vCSServiceOAuthLoginHandler.ServeHTTP(w, r)
+		case VCSServiceOAuthRefreshProcedure:
+			vCSServiceOAuthRefreshHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -256,3 +335,15 @@ func (UnimplementedVCSServiceHandler) GetCommit(context.Context, *connect.Reques
 func (UnimplementedVCSServiceHandler) GetCommits(context.Context, *connect.Request[v1.GetCommitsRequest]) (*connect.Response[v1.GetCommitsResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("vcs.v1.VCSService.GetCommits is not implemented"))
 }
+
+func (UnimplementedVCSServiceHandler) OAuthApp(context.Context, *connect.Request[v1.OAuthAppRequest]) (*connect.Response[v1.OAuthAppResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("vcs.v1.VCSService.OAuthApp is not implemented"))
+}
+
+func (UnimplementedVCSServiceHandler) OAuthLogin(context.Context, *connect.Request[v1.OAuthLoginRequest]) (*connect.Response[v1.OAuthLoginResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("vcs.v1.VCSService.OAuthLogin is not implemented"))
+}
+
+func (UnimplementedVCSServiceHandler) OAuthRefresh(context.Context, *connect.Request[v1.OAuthRefreshRequest]) (*connect.Response[v1.OAuthRefreshResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("vcs.v1.VCSService.OAuthRefresh is not implemented"))
+}
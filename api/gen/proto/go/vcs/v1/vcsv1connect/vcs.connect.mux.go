@@ -49,4 +49,19 @@ func RegisterVCSServiceHandler(mux *mux.Router, svc VCSServiceHandler, opts ...c
 		svc.GetCommits,
 		opts...,
 	))
+	mux.Handle("/vcs.v1.VCSService/OAuthApp", connect.NewUnaryHandler(
+		"/vcs.v1.VCSService/OAuthApp",
+		svc.OAuthApp,
+		opts...,
+	))
+	mux.Handle("/vcs.v1.VCSService/OAuthLogin", connect.NewUnaryHandler(
+		"/vcs.v1.VCSService/OAuthLogin",
+		svc.OAuthLogin,
+		opts...,
+	))
+	mux.Handle("/vcs.v1.VCSService/OAuthRefresh", connect.NewUnaryHandler(
+		"/vcs.v1.VCSService/OAuthRefresh",
+		svc.OAuthRefresh,
+		opts...,
+	))
 }
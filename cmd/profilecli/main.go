@@ -95,6 +95,16 @@ func main() {
 	raftCmd := adminCmd.Command("raft", "Operate on Raft cluster.")
 	raftInfoCmd := raftCmd.Command("info", "Print info about a Raft node.")
 	raftInfoParams := addRaftInfoParams(raftInfoCmd)
+	raftAddVoterCmd := raftCmd.Command("add-voter", "Add a voter to the Raft cluster, or promote a non-voter. The request must be sent to the leader.")
+	raftAddVoterParams := addRaftAddServerParams(raftAddVoterCmd)
+	raftAddNonvoterCmd := raftCmd.Command("add-nonvoter", "Add a non-voter to the Raft cluster, or demote a voter. The request must be sent to the leader.")
+	raftAddNonvoterParams := addRaftAddServerParams(raftAddNonvoterCmd)
+	raftRemoveServerCmd := raftCmd.Command("remove-server", "Remove a server from the Raft cluster. The request must be sent to the leader.")
+	raftRemoveServerParams := addRaftRemoveServerParams(raftRemoveServerCmd)
+	raftTransferLeadershipCmd := raftCmd.Command("transfer-leadership", "Transfer the Raft leadership to another voter. The request must be sent to the leader.")
+	raftTransferLeadershipParams := addRaftTransferLeadershipParams(raftTransferLeadershipCmd)
+	raftSnapshotCmd := raftCmd.Command("snapshot", "Take a snapshot of the Raft node state.")
+	raftSnapshotParams := addRaftSnapshotParams(raftSnapshotCmd)
 
 	// parse command line arguments
 	parsedCmd := kingpin.MustParse(app.Parse(os.Args[1:]))
@@ -166,6 +176,26 @@ func main() {
 		if err := raftInfo(ctx, raftInfoParams); err != nil {
 			os.Exit(checkError(err))
 		}
+	case raftAddVoterCmd.FullCommand():
+		if err := raftAddVoter(ctx, raftAddVoterParams); err != nil {
+			os.Exit(checkError(err))
+		}
+	case raftAddNonvoterCmd.FullCommand():
+		if err := raftAddNonvoter(ctx, raftAddNonvoterParams); err != nil {
+			os.Exit(checkError(err))
+		}
+	case raftRemoveServerCmd.FullCommand():
+		if err := raftRemoveServer(ctx, raftRemoveServerParams); err != nil {
+			os.Exit(checkError(err))
+		}
+	case raftTransferLeadershipCmd.FullCommand():
+		if err := raftTransferLeadership(ctx, raftTransferLeadershipParams); err != nil {
+			os.Exit(checkError(err))
+		}
+	case raftSnapshotCmd.FullCommand():
+		if err := raftSnapshot(ctx, raftSnapshotParams); err != nil {
+			os.Exit(checkError(err))
+		}
 	default:
 		level.Error(logger).Log("msg", "unknown command", "cmd", parsedCmd)
 	}
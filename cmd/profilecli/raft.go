@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"connectrpc.com/connect"
+	"github.com/go-kit/log/level"
 	"google.golang.org/protobuf/encoding/protojson"
 
 	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
@@ -156,3 +157,133 @@ func formatJSONRaftInfo(msg *metastorev1.InfoResponse) (string, error) {
 
 	return string(bytes), nil
 }
+
+type raftServerParams struct {
+	*phlareClient
+
+	ID      string
+	Address string
+	Force   bool
+}
+
+func addRaftAddServerParams(cmd commander) *raftServerParams {
+	params := &raftServerParams{}
+	params.phlareClient = addPhlareClient(cmd)
+
+	cmd.Flag("id", "Raft server ID.").Required().StringVar(&params.ID)
+	cmd.Flag("address", "Raft server address.").StringVar(&params.Address)
+	cmd.Flag("force", "Skip the quorum preservation checks.").BoolVar(&params.Force)
+
+	return params
+}
+
+func addRaftRemoveServerParams(cmd commander) *raftServerParams {
+	params := &raftServerParams{}
+	params.phlareClient = addPhlareClient(cmd)
+
+	cmd.Flag("id", "Raft server ID.").Required().StringVar(&params.ID)
+	cmd.Flag("force", "Skip the quorum preservation checks.").BoolVar(&params.Force)
+
+	return params
+}
+
+func raftAddVoter(ctx context.Context, params *raftServerParams) error {
+	client := params.phlareClient.metadataOperatorClient()
+
+	res, err := client.AddVoter(ctx, connect.NewRequest(&metastorev1.AddVoterRequest{
+		Id:      params.ID,
+		Address: params.Address,
+		Force:   params.Force,
+	}))
+	if err != nil {
+		return err
+	}
+
+	level.Info(logger).Log("msg", "voter added", "id", params.ID, "index", res.Msg.Index)
+	return nil
+}
+
+func raftAddNonvoter(ctx context.Context, params *raftServerParams) error {
+	client := params.phlareClient.metadataOperatorClient()
+
+	res, err := client.AddNonvoter(ctx, connect.NewRequest(&metastorev1.AddNonvoterRequest{
+		Id:      params.ID,
+		Address: params.Address,
+		Force:   params.Force,
+	}))
+	if err != nil {
+		return err
+	}
+
+	level.Info(logger).Log("msg", "non-voter added", "id", params.ID, "index", res.Msg.Index)
+	return nil
+}
+
+func raftRemoveServer(ctx context.Context, params *raftServerParams) error {
+	client := params.phlareClient.metadataOperatorClient()
+
+	res, err := client.RemoveServer(ctx, connect.NewRequest(&metastorev1.RemoveServerRequest{
+		Id:    params.ID,
+		Force: params.Force,
+	}))
+	if err != nil {
+		return err
+	}
+
+	level.Info(logger).Log("msg", "server removed", "id", params.ID, "index", res.Msg.Index)
+	return nil
+}
+
+type raftTransferLeadershipParams struct {
+	*phlareClient
+
+	ID string
+}
+
+func addRaftTransferLeadershipParams(cmd commander) *raftTransferLeadershipParams {
+	params := &raftTransferLeadershipParams{}
+	params.phlareClient = addPhlareClient(cmd)
+
+	cmd.Flag("id", "Raft server ID of the new leader. If not specified, the most up-to-date voter is chosen.").StringVar(&params.ID)
+
+	return params
+}
+
+func raftTransferLeadership(ctx context.Context, params *raftTransferLeadershipParams) error {
+	client := params.phlareClient.metadataOperatorClient()
+
+	res, err := client.TransferLeadership(ctx, connect.NewRequest(&metastorev1.TransferLeadershipRequest{
+		Id: params.ID,
+	}))
+	if err != nil {
+		return err
+	}
+
+	level.Info(logger).Log("msg", "leadership transferred", "leader_id", res.Msg.LeaderId)
+	return nil
+}
+
+type raftSnapshotParams struct {
+	*phlareClient
+}
+
+func addRaftSnapshotParams(cmd commander) *raftSnapshotParams {
+	params := &raftSnapshotParams{}
+	params.phlareClient = addPhlareClient(cmd)
+
+	return params
+}
+
+func raftSnapshot(ctx context.Context, params *raftSnapshotParams) error {
+	client := params.phlareClient.metadataOperatorClient()
+
+	res, err := client.Snapshot(ctx, connect.NewRequest(&metastorev1.SnapshotRequest{}))
+	if err != nil {
+		return err
+	}
+
+	level.Info(logger).Log("msg", "snapshot taken",
+		"last_index", res.Msg.Snapshot.GetLastIndex(),
+		"last_term", res.Msg.Snapshot.GetLastTerm())
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 	"io"
 	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"connectrpc.com/connect"
@@ -19,21 +20,62 @@ import (
 	"github.com/grafana/dskit/tenant"
 	"github.com/oklog/ulid"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
 
 	v1 "github.com/grafana/pyroscope/api/gen/proto/go/adhocprofiles/v1"
-	"github.com/grafana/pyroscope/pkg/frontend"
+	phlaremodel "github.com/grafana/pyroscope/pkg/model"
 	"github.com/grafana/pyroscope/pkg/objstore"
+	"github.com/grafana/pyroscope/pkg/og/storage/tree"
 	"github.com/grafana/pyroscope/pkg/og/structs/flamebearer"
 	"github.com/grafana/pyroscope/pkg/og/structs/flamebearer/convert"
+	"github.com/grafana/pyroscope/pkg/phlaredb/bucket"
 	"github.com/grafana/pyroscope/pkg/validation"
+	"github.com/grafana/pyroscope/pkg/validation/exporter"
 )
 
+const (
+	// metadataPrefix is the prefix (within the tenant ad hoc profiles
+	// directory) under which profile metadata objects are stored.
+	metadataPrefix = "meta/"
+
+	// retentionInterval is how often expired profiles are deleted.
+	retentionInterval = time.Hour
+
+	// usageRefreshInterval is how often the storage used by a tenant is
+	// recalculated from the bucket, to account for the profiles uploaded
+	// and deleted by other replicas.
+	usageRefreshInterval = 5 * time.Minute
+)
+
+type Limits interface {
+	validation.FlameGraphLimits
+	AdHocProfilesMaxTotalSizeBytes(tenantID string) int
+	AdHocProfilesRetentionPeriod(tenantID string) time.Duration
+}
+
 type AdHocProfiles struct {
 	services.Service
 
 	logger log.Logger
-	limits frontend.Limits
+	limits Limits
 	bucket objstore.Bucket
+	pusher PushService
+
+	// ring is used to elect the replica that deletes expired profiles.
+	// If nil, the instance is considered the leader.
+	ring *exporter.LeaderRing
+
+	usageMu sync.Mutex
+	usage   map[string]*tenantUsage
+}
+
+// tenantUsage tracks the storage used by the tenant profiles. Uploads
+// reserve the profile size before the profile is stored, so that
+// concurrent uploads can not exceed the limit together.
+type tenantUsage struct {
+	sync.Mutex
+	size      int64
+	updatedAt time.Time
 }
 
 type AdHocProfile struct {
@@ -42,6 +84,15 @@ type AdHocProfile struct {
 	UploadedAt time.Time `json:"uploadedAt"`
 }
 
+// AdHocProfileMetadata holds the mutable attributes of a profile. It is
+// stored separately, so that profiles can be listed, renamed and tagged
+// without reading or rewriting the profile data.
+type AdHocProfileMetadata struct {
+	Name string   `json:"name"`
+	Tags []string `json:"tags,omitempty"`
+	Size int64    `json:"size"`
+}
+
 func validRunes(r rune) bool {
 	if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' || r == '_' {
 		return true
@@ -69,19 +120,59 @@ func replaceInvalidRunes(id string) string {
 	}, id)
 }
 
-func NewAdHocProfiles(bucket objstore.Bucket, logger log.Logger, limits frontend.Limits) *AdHocProfiles {
+func NewAdHocProfiles(cfg Config, bucket objstore.Bucket, logger log.Logger, limits Limits, pusher PushService, reg prometheus.Registerer) (*AdHocProfiles, error) {
 	a := &AdHocProfiles{
 		logger: logger,
 		bucket: bucket,
 		limits: limits,
+		pusher: pusher,
 	}
-	a.Service = services.NewBasicService(nil, a.running, nil)
-	return a
+	var err error
+	if a.ring, err = exporter.NewLeaderRing(ringName, cfg.Ring, logger, reg); err != nil {
+		return nil, errors.Wrap(err, "failed to create ad hoc profiles ring")
+	}
+	a.Service = services.NewBasicService(a.starting, a.running, a.stopping)
+	return a, nil
+}
+
+func (a *AdHocProfiles) starting(ctx context.Context) error {
+	return services.StartAndAwaitRunning(ctx, a.ring)
 }
 
 func (a *AdHocProfiles) running(ctx context.Context) error {
-	<-ctx.Done()
-	return nil
+	ticker := time.NewTicker(retentionInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if !a.isLeader() {
+				continue
+			}
+			if err := a.deleteExpired(ctx); err != nil {
+				level.Warn(a.logger).Log("msg", "failed to delete expired ad hoc profiles", "err", err)
+			}
+		}
+	}
+}
+
+func (a *AdHocProfiles) stopping(_ error) error {
+	return services.StopAndAwaitTerminated(context.Background(), a.ring)
+}
+
+// isLeader reports whether the instance is responsible for deleting
+// expired profiles.
+func (a *AdHocProfiles) isLeader() bool {
+	if a.ring == nil {
+		return true
+	}
+	leader, err := a.ring.IsLeader()
+	if err != nil {
+		level.Warn(a.logger).Log("msg", "failed to determine ad hoc profiles ring leader", "err", err)
+		return false
+	}
+	return leader
 }
 
 func (a *AdHocProfiles) Upload(ctx context.Context, c *connect.Request[v1.AdHocProfilesUploadRequest]) (*connect.Response[v1.AdHocProfilesGetResponse], error) {
@@ -99,8 +190,6 @@ func (a *AdHocProfiles) Upload(ctx context.Context, c *connect.Request[v1.AdHocP
 	// replace runes outside of [a-zA-Z0-9_-.] with underscores
 	adHocProfile.Name = replaceInvalidRunes(adHocProfile.Name)
 
-	// TODO: Add per-tenant upload limits (number of files, total size, etc.)
-
 	maxNodes, err := validation.ValidateMaxNodes(a.limits, []string{tenantID}, c.Msg.GetMaxNodes())
 	if err != nil {
 		return nil, errors.Wrapf(err, "could not determine max nodes")
@@ -121,8 +210,25 @@ func (a *AdHocProfiles) Upload(ctx context.Context, c *connect.Request[v1.AdHocP
 		return nil, errors.Wrapf(err, "failed to upload profile")
 	}
 
+	size := int64(len(dataToStore))
+	if err = a.reserve(ctx, tenantID, bucket, size); err != nil {
+		return nil, err
+	}
+
 	err = bucket.Upload(ctx, id, bytes.NewReader(dataToStore))
 	if err != nil {
+		a.release(tenantID, size)
+		return nil, errors.Wrapf(err, "failed to upload profile")
+	}
+
+	md := &AdHocProfileMetadata{
+		Name: adHocProfile.Name,
+		Tags: normalizeTags(c.Msg.Tags, nil),
+		Size: int64(len(dataToStore)),
+	}
+	if err = writeMetadata(ctx, bucket, id, md); err != nil {
+		_ = bucket.Delete(ctx, id)
+		a.release(tenantID, size)
 		return nil, errors.Wrapf(err, "failed to upload profile")
 	}
 
@@ -138,6 +244,7 @@ func (a *AdHocProfiles) Upload(ctx context.Context, c *connect.Request[v1.AdHocP
 		FlamebearerProfile: string(jsonProfile),
 		ProfileType:        profile.Metadata.Name,
 		ProfileTypes:       profileTypes,
+		Tags:               md.Tags,
 	}), nil
 }
 
@@ -150,32 +257,28 @@ func (a *AdHocProfiles) Get(ctx context.Context, c *connect.Request[v1.AdHocProf
 	bucket := a.getBucket(tenantID)
 
 	id := c.Msg.GetId()
-	if !validID(id) {
-		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("id '%s' is invalid: can only contain [a-zA-Z0-9_-.]", id))
-	}
-
-	reader, err := bucket.Get(ctx, id)
-	if err != nil {
-		return nil, errors.Wrapf(err, "failed to get profile")
+	if err = a.checkNotExpired(tenantID, id); err != nil {
+		return nil, err
 	}
-
-	adHocProfileBytes, err := io.ReadAll(reader)
+	adHocProfile, err := readProfile(ctx, bucket, id)
 	if err != nil {
 		return nil, err
 	}
 
-	var adHocProfile AdHocProfile
-	err = json.Unmarshal(adHocProfileBytes, &adHocProfile)
+	md, err := readMetadata(ctx, bucket, id)
 	if err != nil {
 		return nil, err
 	}
+	if md == nil {
+		md = &AdHocProfileMetadata{Name: adHocProfile.Name}
+	}
 
 	maxNodes, err := validation.ValidateMaxNodes(a.limits, []string{tenantID}, c.Msg.GetMaxNodes())
 	if err != nil {
 		return nil, errors.Wrapf(err, "could not determine max nodes")
 	}
 
-	profile, profileTypes, err := parse(&adHocProfile, c.Msg.ProfileType, maxNodes)
+	profile, profileTypes, err := parse(adHocProfile, c.Msg.ProfileType, maxNodes)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to parse profile")
 	}
@@ -187,67 +290,552 @@ func (a *AdHocProfiles) Get(ctx context.Context, c *connect.Request[v1.AdHocProf
 
 	return connect.NewResponse(&v1.AdHocProfilesGetResponse{
 		Id:                 c.Msg.Id,
-		Name:               adHocProfile.Name,
+		Name:               md.Name,
 		UploadedAt:         adHocProfile.UploadedAt.UnixMilli(),
 		FlamebearerProfile: string(jsonProfile),
 		ProfileType:        profile.Metadata.Name,
 		ProfileTypes:       profileTypes,
+		Tags:               md.Tags,
 	}), nil
 }
 
 func (a *AdHocProfiles) List(ctx context.Context, c *connect.Request[v1.AdHocProfilesListRequest]) (*connect.Response[v1.AdHocProfilesListResponse], error) {
-	bucket, err := a.getBucketFromContext(ctx)
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if c.Msg.PageToken != "" && !validID(c.Msg.PageToken) {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("page token '%s' is invalid", c.Msg.PageToken))
+	}
+
+	var token *v1.AdHocProfilesProfileMetadata
+	if c.Msg.PageToken != "" {
+		if token = metadataFromID(c.Msg.PageToken); token == nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("page token '%s' is invalid", c.Msg.PageToken))
+		}
+	}
+
+	bucket := a.getBucket(tenantID)
+	profiles, withMetadata, err := a.listProfiles(ctx, bucket, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Filter by the attributes derived from the profile id first,
+	// so that the metadata is only read for the profiles on the page.
+	profiles = slices.DeleteFunc(profiles, func(p *v1.AdHocProfilesProfileMetadata) bool {
+		switch {
+		case c.Msg.Start > 0 && p.UploadedAt < c.Msg.Start:
+			return true
+		case c.Msg.End > 0 && p.UploadedAt >= c.Msg.End:
+			return true
+		case token != nil && compareProfiles(p, token) <= 0:
+			return true
+		}
+		return false
+	})
+
+	name := strings.ToLower(c.Msg.Name)
+	limit := int(c.Msg.Limit)
+	page := make([]*v1.AdHocProfilesProfileMetadata, 0, min(len(profiles), max(limit, 0)+1))
+	for _, p := range profiles {
+		if limit > 0 && len(page) > limit {
+			break
+		}
+		if _, ok := withMetadata[p.Id]; ok {
+			md, err := readMetadata(ctx, bucket, p.Id)
+			if err != nil {
+				return nil, err
+			}
+			if md != nil {
+				p.Name = md.Name
+				p.Tags = md.Tags
+				p.Size = md.Size
+			}
+		}
+		if name != "" && !strings.Contains(strings.ToLower(p.Name), name) {
+			continue
+		}
+		if slices.ContainsFunc(c.Msg.Tags, func(t string) bool { return !slices.Contains(p.Tags, t) }) {
+			continue
+		}
+		page = append(page, p)
+	}
+
+	var nextPageToken string
+	if limit > 0 && len(page) > limit {
+		page = page[:limit]
+		nextPageToken = page[limit-1].Id
+	}
+
+	return connect.NewResponse(&v1.AdHocProfilesListResponse{
+		Profiles:      page,
+		NextPageToken: nextPageToken,
+	}), nil
+}
+
+func (a *AdHocProfiles) Delete(ctx context.Context, c *connect.Request[v1.AdHocProfilesDeleteRequest]) (*connect.Response[v1.AdHocProfilesDeleteResponse], error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	bucket := a.getBucket(tenantID)
+
+	id := c.Msg.GetId()
+	if err = checkExists(ctx, bucket, id); err != nil {
+		return nil, err
+	}
+	size, err := profileSize(ctx, bucket, id)
+	if err != nil {
+		return nil, err
+	}
+	if err = deleteProfile(ctx, bucket, id); err != nil {
+		return nil, errors.Wrapf(err, "failed to delete profile")
+	}
+	a.release(tenantID, size)
+
+	return connect.NewResponse(&v1.AdHocProfilesDeleteResponse{}), nil
+}
+
+func (a *AdHocProfiles) Update(ctx context.Context, c *connect.Request[v1.AdHocProfilesUpdateRequest]) (*connect.Response[v1.AdHocProfilesUpdateResponse], error) {
+	tenantID, err := tenant.TenantID(ctx)
 	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	bucket := a.getBucket(tenantID)
+
+	id := c.Msg.GetId()
+	if err = a.checkNotExpired(tenantID, id); err != nil {
+		return nil, err
+	}
+	if err = checkExists(ctx, bucket, id); err != nil {
 		return nil, err
 	}
 
+	p := metadataFromID(id)
+	if p == nil {
+		p = &v1.AdHocProfilesProfileMetadata{Id: id, Name: id}
+	}
+
+	md, err := readMetadata(ctx, bucket, id)
+	if err != nil {
+		return nil, err
+	}
+	if md == nil {
+		// The profile was uploaded before metadata was stored separately.
+		attrs, err := bucket.Attributes(ctx, id)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get profile attributes")
+		}
+		md = &AdHocProfileMetadata{
+			Name: p.Name,
+			Size: attrs.Size,
+		}
+	}
+
+	if c.Msg.Name != nil {
+		name := replaceInvalidRunes(strings.TrimSpace(c.Msg.GetName()))
+		if name == "" {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("name must not be empty"))
+		}
+		md.Name = name
+	}
+	md.Tags = normalizeTags(append(md.Tags, c.Msg.AddTags...), c.Msg.RemoveTags)
+
+	if err = writeMetadata(ctx, bucket, id, md); err != nil {
+		return nil, errors.Wrapf(err, "failed to update profile")
+	}
+
+	p.Name = md.Name
+	p.Tags = md.Tags
+	p.Size = md.Size
+	p.ExpiresAt = a.expiresAt(tenantID, p.UploadedAt)
+	return connect.NewResponse(&v1.AdHocProfilesUpdateResponse{Profile: p}), nil
+}
+
+func (a *AdHocProfiles) Diff(ctx context.Context, c *connect.Request[v1.AdHocProfilesDiffRequest]) (*connect.Response[v1.AdHocProfilesDiffResponse], error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	bucket := a.getBucket(tenantID)
+
+	maxNodes, err := validation.ValidateMaxNodes(a.limits, []string{tenantID}, c.Msg.GetMaxNodes())
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not determine max nodes")
+	}
+
+	for _, id := range []string{c.Msg.LeftId, c.Msg.RightId} {
+		if err = a.checkNotExpired(tenantID, id); err != nil {
+			return nil, err
+		}
+	}
+	left, leftProfileTypes, err := readTree(ctx, bucket, c.Msg.LeftId, c.Msg.LeftProfileType)
+	if err != nil {
+		return nil, err
+	}
+	right, rightProfileTypes, err := readTree(ctx, bucket, c.Msg.RightId, c.Msg.RightProfileType)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := phlaremodel.NewFlamegraphDiff(left, right, maxNodes)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	return connect.NewResponse(&v1.AdHocProfilesDiffResponse{
+		Flamegraph:        diff,
+		LeftProfileTypes:  leftProfileTypes,
+		RightProfileTypes: rightProfileTypes,
+	}), nil
+}
+
+// listProfiles returns the non-expired profiles of the tenant, ordered
+// by upload time, newest first, and the set of profiles that have metadata
+// stored. Only the attributes derived from the profile id are populated:
+// the metadata is not read.
+func (a *AdHocProfiles) listProfiles(ctx context.Context, bucket objstore.Bucket, tenantID string) ([]*v1.AdHocProfilesProfileMetadata, map[string]struct{}, error) {
 	profiles := make([]*v1.AdHocProfilesProfileMetadata, 0)
-	err = bucket.Iter(ctx, "", func(s string) error {
+	err := bucket.Iter(ctx, "", func(s string) error {
 		// do not list elements with invalid ids
 		if !validID(s) {
 			return nil
 		}
-
-		separatorIndex := strings.IndexRune(s, '-')
-		id, err := ulid.Parse(s[0:separatorIndex])
-		if err != nil {
-			level.Warn(a.logger).Log("msg", "cannot parse ad hoc profile", "key", s, "err", err)
+		p := metadataFromID(s)
+		if p == nil {
+			level.Warn(a.logger).Log("msg", "cannot parse ad hoc profile", "key", s)
 			return nil
 		}
-		name := s[separatorIndex+1:]
-		profiles = append(profiles, &v1.AdHocProfilesProfileMetadata{
-			Id:         s,
-			Name:       name,
-			UploadedAt: int64(id.Time()),
-		})
+		p.ExpiresAt = a.expiresAt(tenantID, p.UploadedAt)
+		if p.ExpiresAt > 0 && p.ExpiresAt <= time.Now().UnixMilli() {
+			return nil
+		}
+		profiles = append(profiles, p)
 		return nil
 	})
-	cmp := func(a, b *v1.AdHocProfilesProfileMetadata) int {
-		if a.UploadedAt < b.UploadedAt {
-			return 1
+	if err != nil {
+		return nil, nil, err
+	}
+
+	withMetadata := make(map[string]struct{})
+	err = bucket.Iter(ctx, metadataPrefix, func(s string) error {
+		withMetadata[strings.TrimPrefix(s, metadataPrefix)] = struct{}{}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	slices.SortFunc(profiles, compareProfiles)
+	return profiles, withMetadata, nil
+}
+
+// totalSize returns the total size of the stored profiles in bytes.
+func (a *AdHocProfiles) totalSize(ctx context.Context, bucket objstore.Bucket) (int64, error) {
+	var size int64
+	err := bucket.Iter(ctx, "", func(s string) error {
+		if !validID(s) {
+			return nil
 		}
-		if a.UploadedAt > b.UploadedAt {
-			return -1
+		n, err := profileSize(ctx, bucket, s)
+		size += n
+		return err
+	})
+	return size, err
+}
+
+// reserve accounts the size of a new profile against the tenant storage
+// limit. The reservation must be released if the profile is not stored.
+func (a *AdHocProfiles) reserve(ctx context.Context, tenantID string, bucket objstore.Bucket, size int64) error {
+	maxSize := int64(a.limits.AdHocProfilesMaxTotalSizeBytes(tenantID))
+	if maxSize <= 0 {
+		return nil
+	}
+	u := a.tenantUsage(tenantID)
+	u.Lock()
+	defer u.Unlock()
+	if time.Since(u.updatedAt) > usageRefreshInterval {
+		used, err := a.totalSize(ctx, bucket)
+		if err != nil {
+			return errors.Wrapf(err, "failed to determine used storage")
 		}
+		u.size = used
+		u.updatedAt = time.Now()
+	}
+	if u.size+size > maxSize {
+		return connect.NewError(connect.CodeResourceExhausted, fmt.Errorf(
+			"ad hoc profiles storage limit exceeded: %d of %d bytes used, the profile requires %d bytes; delete profiles that are no longer needed",
+			u.size, maxSize, size))
+	}
+	u.size += size
+	return nil
+}
+
+func (a *AdHocProfiles) release(tenantID string, size int64) {
+	u := a.tenantUsage(tenantID)
+	u.Lock()
+	u.size = max(0, u.size-size)
+	u.Unlock()
+}
+
+func (a *AdHocProfiles) tenantUsage(tenantID string) *tenantUsage {
+	a.usageMu.Lock()
+	defer a.usageMu.Unlock()
+	if a.usage == nil {
+		a.usage = make(map[string]*tenantUsage)
+	}
+	u, ok := a.usage[tenantID]
+	if !ok {
+		u = new(tenantUsage)
+		a.usage[tenantID] = u
+	}
+	return u
+}
+
+// deleteExpired deletes the profiles of all tenants that are older
+// than the tenant retention period.
+func (a *AdHocProfiles) deleteExpired(ctx context.Context) error {
+	tenants, err := bucket.ListUsers(ctx, a.bucket)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UnixMilli()
+	for _, tenantID := range tenants {
+		if a.limits.AdHocProfilesRetentionPeriod(tenantID) <= 0 {
+			continue
+		}
+		b := a.getBucket(tenantID)
+		var expired []string
+		err = b.Iter(ctx, "", func(s string) error {
+			if !validID(s) {
+				return nil
+			}
+			p := metadataFromID(s)
+			if p == nil {
+				return nil
+			}
+			if expiresAt := a.expiresAt(tenantID, p.UploadedAt); expiresAt > 0 && expiresAt <= now {
+				expired = append(expired, s)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, id := range expired {
+			size, err := profileSize(ctx, b, id)
+			if err != nil {
+				return err
+			}
+			if err = deleteProfile(ctx, b, id); err != nil {
+				return err
+			}
+			a.release(tenantID, size)
+			level.Debug(a.logger).Log("msg", "deleted expired ad hoc profile", "tenant", tenantID, "id", id)
+		}
+	}
+	return nil
+}
+
+// expiresAt returns the time in milliseconds after which a profile
+// uploaded at the given time expires, or zero if it does not expire.
+func (a *AdHocProfiles) expiresAt(tenantID string, uploadedAt int64) int64 {
+	retention := a.limits.AdHocProfilesRetentionPeriod(tenantID)
+	if retention <= 0 {
 		return 0
 	}
-	slices.SortFunc(profiles, cmp)
+	return uploadedAt + retention.Milliseconds()
+}
+
+// checkNotExpired returns a not found error if the profile has expired
+// but has not been deleted yet.
+func (a *AdHocProfiles) checkNotExpired(tenantID string, id string) error {
+	p := metadataFromID(id)
+	if p == nil {
+		return nil
+	}
+	if expiresAt := a.expiresAt(tenantID, p.UploadedAt); expiresAt > 0 && expiresAt <= time.Now().UnixMilli() {
+		return connect.NewError(connect.CodeNotFound, fmt.Errorf("profile '%s' not found", id))
+	}
+	return nil
+}
+
+func (a *AdHocProfiles) getBucket(tenantID string) objstore.Bucket {
+	return objstore.NewPrefixedBucket(a.bucket, tenantID+"/adhoc")
+}
+
+// metadataFromID builds the profile metadata out of the profile id, which
+// is composed of a ULID and the original profile name. It returns nil if
+// the id is not well-formed.
+func metadataFromID(id string) *v1.AdHocProfilesProfileMetadata {
+	separatorIndex := strings.IndexRune(id, '-')
+	if separatorIndex < 0 {
+		return nil
+	}
+	uid, err := ulid.Parse(id[0:separatorIndex])
+	if err != nil {
+		return nil
+	}
+	return &v1.AdHocProfilesProfileMetadata{
+		Id:         id,
+		Name:       id[separatorIndex+1:],
+		UploadedAt: int64(uid.Time()),
+	}
+}
+
+// compareProfiles orders profiles by upload time, newest first.
+func compareProfiles(a, b *v1.AdHocProfilesProfileMetadata) int {
+	if a.UploadedAt < b.UploadedAt {
+		return 1
+	}
+	if a.UploadedAt > b.UploadedAt {
+		return -1
+	}
+	return strings.Compare(b.Id, a.Id)
+}
+
+// normalizeTags returns the sorted set of non-empty tags, excluding the
+// removed ones.
+func normalizeTags(tags []string, removed []string) []string {
+	res := make([]string, 0, len(tags))
+	for _, t := range tags {
+		t = strings.TrimSpace(t)
+		if t != "" && !slices.Contains(removed, t) {
+			res = append(res, t)
+		}
+	}
+	slices.Sort(res)
+	return slices.Compact(res)
+}
+
+func checkExists(ctx context.Context, bucket objstore.Bucket, id string) error {
+	if !validID(id) {
+		return connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("id '%s' is invalid: can only contain [a-zA-Z0-9_-.]", id))
+	}
+	exists, err := bucket.Exists(ctx, id)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get profile")
+	}
+	if !exists {
+		return connect.NewError(connect.CodeNotFound, fmt.Errorf("profile '%s' not found", id))
+	}
+	return nil
+}
+
+func readProfile(ctx context.Context, bucket objstore.Bucket, id string) (*AdHocProfile, error) {
+	if !validID(id) {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("id '%s' is invalid: can only contain [a-zA-Z0-9_-.]", id))
+	}
+
+	reader, err := bucket.Get(ctx, id)
+	if err != nil {
+		if bucket.IsObjNotFoundErr(err) {
+			return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("profile '%s' not found", id))
+		}
+		return nil, errors.Wrapf(err, "failed to get profile")
+	}
+	defer reader.Close()
+
+	adHocProfileBytes, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	var adHocProfile AdHocProfile
+	err = json.Unmarshal(adHocProfileBytes, &adHocProfile)
 	if err != nil {
 		return nil, err
 	}
-	return connect.NewResponse(&v1.AdHocProfilesListResponse{Profiles: profiles}), nil
+	return &adHocProfile, nil
 }
 
-func (a *AdHocProfiles) getBucketFromContext(ctx context.Context) (objstore.Bucket, error) {
-	tenantID, err := tenant.TenantID(ctx)
+// readTree reads a profile and converts the selected profile type to a tree.
+func readTree(ctx context.Context, bucket objstore.Bucket, id string, profileType *string) (*phlaremodel.Tree, []string, error) {
+	adHocProfile, err := readProfile(ctx, bucket, id)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		return nil, nil, err
+	}
+	profile, profileTypes, err := parse(adHocProfile, profileType, -1)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to parse profile")
 	}
-	return a.getBucket(tenantID), nil
+	t, err := flamebearer.ProfileToTree(*profile)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to parse profile")
+	}
+	return toModelTree(t), profileTypes, nil
 }
 
-func (a *AdHocProfiles) getBucket(tenantID string) objstore.Bucket {
-	return objstore.NewPrefixedBucket(a.bucket, tenantID+"/adhoc")
+func toModelTree(t *tree.Tree) *phlaremodel.Tree {
+	res := new(phlaremodel.Tree)
+	t.IterateStacks(func(_ string, self uint64, stack []string) {
+		s := make([]string, len(stack))
+		for i := range stack {
+			s[len(stack)-1-i] = strings.Clone(stack[i])
+		}
+		res.InsertStack(int64(self), s...)
+	})
+	return res
+}
+
+// readMetadata returns the profile metadata, or nil if the profile
+// has no metadata stored.
+func readMetadata(ctx context.Context, bucket objstore.Bucket, id string) (*AdHocProfileMetadata, error) {
+	reader, err := bucket.Get(ctx, metadataPrefix+id)
+	if err != nil {
+		if bucket.IsObjNotFoundErr(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get profile metadata")
+	}
+	defer reader.Close()
+
+	var md AdHocProfileMetadata
+	if err = json.NewDecoder(reader).Decode(&md); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode profile metadata")
+	}
+	return &md, nil
+}
+
+// profileSize returns the size of the stored profile in bytes.
+func profileSize(ctx context.Context, bucket objstore.Bucket, id string) (int64, error) {
+	md, err := readMetadata(ctx, bucket, id)
+	if err != nil {
+		return 0, err
+	}
+	if md != nil && md.Size > 0 {
+		return md.Size, nil
+	}
+	attrs, err := bucket.Attributes(ctx, id)
+	if err != nil {
+		if bucket.IsObjNotFoundErr(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return attrs.Size, nil
+}
+
+func writeMetadata(ctx context.Context, bucket objstore.Bucket, id string, md *AdHocProfileMetadata) error {
+	b, err := json.Marshal(md)
+	if err != nil {
+		return err
+	}
+	return bucket.Upload(ctx, metadataPrefix+id, bytes.NewReader(b))
+}
+
+func deleteProfile(ctx context.Context, bucket objstore.Bucket, id string) error {
+	if err := bucket.Delete(ctx, id); err != nil && !bucket.IsObjNotFoundErr(err) {
+		return err
+	}
+	if err := bucket.Delete(ctx, metadataPrefix+id); err != nil && !bucket.IsObjNotFoundErr(err) {
+		return err
+	}
+	return nil
 }
 
 func parse(p *AdHocProfile, profileType *string, maxNodes int64) (fg *flamebearer.FlamebearerProfile, profileTypes []string, err error) {
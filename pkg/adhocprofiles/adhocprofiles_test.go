@@ -5,15 +5,23 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"io"
 	"os"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"connectrpc.com/connect"
 	"github.com/stretchr/testify/require"
 	thanosobjstore "github.com/thanos-io/objstore"
 
 	v1 "github.com/grafana/pyroscope/api/gen/proto/go/adhocprofiles/v1"
+	pushv1 "github.com/grafana/pyroscope/api/gen/proto/go/push/v1"
+	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+	distributormodel "github.com/grafana/pyroscope/pkg/distributor/model"
+	phlaremodel "github.com/grafana/pyroscope/pkg/model"
 	phlareobjstore "github.com/grafana/pyroscope/pkg/objstore"
 	"github.com/grafana/pyroscope/pkg/tenant"
 	"github.com/grafana/pyroscope/pkg/util"
@@ -97,6 +105,7 @@ func TestAdHocProfiles_List(t *testing.T) {
 	_ = bucket.Upload(context.Background(), "tenant/adhoc/01HMXRV02963FK36GGRE9N6MPH-heap.pprof", bytes.NewReader([]byte{1}))
 	a := &AdHocProfiles{
 		logger: util.Logger,
+		limits: validation.MockLimits{},
 		bucket: bucket,
 	}
 	response, err := a.List(tenant.InjectTenantID(context.Background(), "tenant"), connect.NewRequest(&v1.AdHocProfilesListRequest{}))
@@ -116,6 +125,45 @@ func TestAdHocProfiles_List(t *testing.T) {
 	require.Equal(t, connect.NewResponse(&v1.AdHocProfilesListResponse{Profiles: expected}), response)
 }
 
+// countingBucket counts the objects read.
+type countingBucket struct {
+	thanosobjstore.Bucket
+	gets atomic.Int32
+}
+
+func (b *countingBucket) Get(ctx context.Context, name string) (io.ReadCloser, error) {
+	b.gets.Add(1)
+	return b.Bucket.Get(ctx, name)
+}
+
+func TestAdHocProfiles_List_ReadsMetadataOfPageOnly(t *testing.T) {
+	counting := &countingBucket{Bucket: thanosobjstore.NewInMemBucket()}
+	a := &AdHocProfiles{
+		logger: util.Logger,
+		limits: validation.MockLimits{},
+		bucket: phlareobjstore.NewBucket(counting),
+	}
+	ctx := context.Background()
+	ids := []string{
+		"01HMXRV02963FK36GGRE9N6MPH-a.pprof",
+		"01HMXRV02963FK36GGRE9N6MPJ-b.pprof",
+		"01HMXRV02963FK36GGRE9N6MPK-c.pprof",
+		"01HMXRV02963FK36GGRE9N6MPM-d.pprof",
+	}
+	for _, id := range ids {
+		require.NoError(t, counting.Upload(ctx, "tenant/adhoc/"+id, bytes.NewReader([]byte{1})))
+		require.NoError(t, counting.Upload(ctx, "tenant/adhoc/meta/"+id, strings.NewReader(`{"name":"renamed-`+id+`"}`)))
+	}
+
+	resp, err := a.List(tenant.InjectTenantID(ctx, "tenant"), connect.NewRequest(&v1.AdHocProfilesListRequest{Limit: 1}))
+	require.NoError(t, err)
+	require.Len(t, resp.Msg.Profiles, 1)
+	require.Equal(t, "renamed-"+ids[3], resp.Msg.Profiles[0].Name)
+	require.Equal(t, ids[3], resp.Msg.NextPageToken)
+	// The profile on the page, and the one that tells whether there is a next page.
+	require.Equal(t, int32(2), counting.gets.Load())
+}
+
 func TestAdHocProfiles_Upload(t *testing.T) {
 	bucket := phlareobjstore.NewBucket(thanosobjstore.NewInMemBucket())
 	rawProfile, err := os.ReadFile("testdata/cpu.pprof")
@@ -202,3 +250,313 @@ func TestAdHocProfiles_Upload(t *testing.T) {
 		})
 	}
 }
+
+func TestAdHocProfiles_Lifecycle(t *testing.T) {
+	bucket := phlareobjstore.NewBucket(thanosobjstore.NewInMemBucket())
+	rawProfile, err := os.ReadFile("testdata/cpu.pprof")
+	require.NoError(t, err)
+	encodedProfile := base64.StdEncoding.EncodeToString(rawProfile)
+
+	a := &AdHocProfiles{
+		logger: util.Logger,
+		limits: validation.MockLimits{MaxFlameGraphNodesDefaultValue: 8192},
+		bucket: bucket,
+	}
+	ctx := tenant.InjectTenantID(context.Background(), "tenant")
+
+	upload := func(name string, tags ...string) string {
+		// Profiles are ordered by upload time with millisecond precision.
+		time.Sleep(2 * time.Millisecond)
+		resp, err := a.Upload(ctx, connect.NewRequest(&v1.AdHocProfilesUploadRequest{
+			Name:    name,
+			Profile: encodedProfile,
+			Tags:    tags,
+		}))
+		require.NoError(t, err)
+		return resp.Msg.Id
+	}
+	list := func(req *v1.AdHocProfilesListRequest) *v1.AdHocProfilesListResponse {
+		resp, err := a.List(ctx, connect.NewRequest(req))
+		require.NoError(t, err)
+		return resp.Msg
+	}
+	names := func(profiles []*v1.AdHocProfilesProfileMetadata) []string {
+		res := make([]string, 0, len(profiles))
+		for _, p := range profiles {
+			res = append(res, p.Name)
+		}
+		return res
+	}
+
+	first := upload("first.pprof", "bench", "v1")
+	second := upload("second.pprof", "bench")
+	third := upload("third.pprof")
+
+	t.Run("list all", func(t *testing.T) {
+		resp := list(&v1.AdHocProfilesListRequest{})
+		require.Equal(t, []string{"third.pprof", "second.pprof", "first.pprof"}, names(resp.Profiles))
+		require.Equal(t, []string{"bench", "v1"}, resp.Profiles[2].Tags)
+		require.Greater(t, resp.Profiles[2].Size, int64(0))
+		require.Empty(t, resp.NextPageToken)
+	})
+
+	t.Run("filter by tags and name", func(t *testing.T) {
+		require.Equal(t, []string{"second.pprof", "first.pprof"}, names(list(&v1.AdHocProfilesListRequest{Tags: []string{"bench"}}).Profiles))
+		require.Equal(t, []string{"first.pprof"}, names(list(&v1.AdHocProfilesListRequest{Tags: []string{"bench", "v1"}}).Profiles))
+		require.Equal(t, []string{"second.pprof"}, names(list(&v1.AdHocProfilesListRequest{Name: "SECOND"}).Profiles))
+	})
+
+	t.Run("paginate", func(t *testing.T) {
+		resp := list(&v1.AdHocProfilesListRequest{Limit: 2})
+		require.Equal(t, []string{"third.pprof", "second.pprof"}, names(resp.Profiles))
+		require.Equal(t, second, resp.NextPageToken)
+		resp = list(&v1.AdHocProfilesListRequest{Limit: 2, PageToken: resp.NextPageToken})
+		require.Equal(t, []string{"first.pprof"}, names(resp.Profiles))
+		require.Empty(t, resp.NextPageToken)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		name := "renamed.pprof"
+		resp, err := a.Update(ctx, connect.NewRequest(&v1.AdHocProfilesUpdateRequest{
+			Id:         first,
+			Name:       &name,
+			AddTags:    []string{"baseline"},
+			RemoveTags: []string{"v1"},
+		}))
+		require.NoError(t, err)
+		require.Equal(t, first, resp.Msg.Profile.Id)
+		require.Equal(t, "renamed.pprof", resp.Msg.Profile.Name)
+		require.Equal(t, []string{"baseline", "bench"}, resp.Msg.Profile.Tags)
+
+		get, err := a.Get(ctx, connect.NewRequest(&v1.AdHocProfilesGetRequest{Id: first}))
+		require.NoError(t, err)
+		require.Equal(t, "renamed.pprof", get.Msg.Name)
+		require.Equal(t, []string{"baseline", "bench"}, get.Msg.Tags)
+	})
+
+	t.Run("diff", func(t *testing.T) {
+		resp, err := a.Diff(ctx, connect.NewRequest(&v1.AdHocProfilesDiffRequest{
+			LeftId:  first,
+			RightId: second,
+		}))
+		require.NoError(t, err)
+		require.NotEmpty(t, resp.Msg.Flamegraph.Names)
+		require.Equal(t, resp.Msg.Flamegraph.LeftTicks, resp.Msg.Flamegraph.RightTicks)
+		require.Equal(t, resp.Msg.LeftProfileTypes, resp.Msg.RightProfileTypes)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		_, err := a.Delete(ctx, connect.NewRequest(&v1.AdHocProfilesDeleteRequest{Id: third}))
+		require.NoError(t, err)
+		require.Equal(t, []string{"second.pprof", "renamed.pprof"}, names(list(&v1.AdHocProfilesListRequest{}).Profiles))
+
+		_, err = a.Delete(ctx, connect.NewRequest(&v1.AdHocProfilesDeleteRequest{Id: third}))
+		require.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+	})
+}
+
+func TestAdHocProfiles_Limits(t *testing.T) {
+	bucket := phlareobjstore.NewBucket(thanosobjstore.NewInMemBucket())
+	rawProfile, err := os.ReadFile("testdata/cpu.pprof")
+	require.NoError(t, err)
+	encodedProfile := base64.StdEncoding.EncodeToString(rawProfile)
+	ctx := tenant.InjectTenantID(context.Background(), "tenant")
+
+	t.Run("reject uploads exceeding the storage limit", func(t *testing.T) {
+		a := &AdHocProfiles{
+			logger: util.Logger,
+			limits: validation.MockLimits{
+				MaxFlameGraphNodesDefaultValue:      8192,
+				AdHocProfilesMaxTotalSizeBytesValue: 3 * len(encodedProfile) / 2,
+			},
+			bucket: bucket,
+		}
+		req := &v1.AdHocProfilesUploadRequest{Name: "cpu.pprof", Profile: encodedProfile}
+		_, err := a.Upload(ctx, connect.NewRequest(req))
+		require.NoError(t, err)
+		_, err = a.Upload(ctx, connect.NewRequest(req))
+		require.Equal(t, connect.CodeResourceExhausted, connect.CodeOf(err))
+	})
+
+	t.Run("release storage of deleted profiles", func(t *testing.T) {
+		a := &AdHocProfiles{
+			logger: util.Logger,
+			limits: validation.MockLimits{
+				MaxFlameGraphNodesDefaultValue:      8192,
+				AdHocProfilesMaxTotalSizeBytesValue: 3 * len(encodedProfile),
+			},
+			bucket: phlareobjstore.NewBucket(thanosobjstore.NewInMemBucket()),
+		}
+		req := &v1.AdHocProfilesUploadRequest{Name: "cpu.pprof", Profile: encodedProfile}
+		var wg sync.WaitGroup
+		errs := make([]error, 4)
+		for i := range errs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, errs[i] = a.Upload(ctx, connect.NewRequest(req))
+			}(i)
+		}
+		wg.Wait()
+		var rejected int
+		for _, err := range errs {
+			if err != nil {
+				require.Equal(t, connect.CodeResourceExhausted, connect.CodeOf(err))
+				rejected++
+			}
+		}
+		require.Equal(t, 2, rejected)
+
+		resp, err := a.List(ctx, connect.NewRequest(&v1.AdHocProfilesListRequest{}))
+		require.NoError(t, err)
+		require.Len(t, resp.Msg.Profiles, 2)
+		_, err = a.Delete(ctx, connect.NewRequest(&v1.AdHocProfilesDeleteRequest{Id: resp.Msg.Profiles[0].Id}))
+		require.NoError(t, err)
+		_, err = a.Upload(ctx, connect.NewRequest(req))
+		require.NoError(t, err)
+	})
+
+	t.Run("do not return expired profiles", func(t *testing.T) {
+		bucket := phlareobjstore.NewBucket(thanosobjstore.NewInMemBucket())
+		a := &AdHocProfiles{
+			logger: util.Logger,
+			limits: validation.MockLimits{
+				MaxFlameGraphNodesDefaultValue:    8192,
+				AdHocProfilesRetentionPeriodValue: time.Hour,
+			},
+			bucket: bucket,
+		}
+		resp, err := a.Upload(ctx, connect.NewRequest(&v1.AdHocProfilesUploadRequest{Name: "cpu.pprof", Profile: encodedProfile}))
+		require.NoError(t, err)
+		data, err := bucket.Get(ctx, "tenant/adhoc/"+resp.Msg.Id)
+		require.NoError(t, err)
+		const id = "01HMXV8BF4EH71NBYZNPPVGJ2X-old.pprof"
+		require.NoError(t, bucket.Upload(ctx, "tenant/adhoc/"+id, data))
+
+		_, err = a.Get(ctx, connect.NewRequest(&v1.AdHocProfilesGetRequest{Id: resp.Msg.Id}))
+		require.NoError(t, err)
+		_, err = a.Get(ctx, connect.NewRequest(&v1.AdHocProfilesGetRequest{Id: id}))
+		require.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+		_, err = a.Diff(ctx, connect.NewRequest(&v1.AdHocProfilesDiffRequest{LeftId: resp.Msg.Id, RightId: id}))
+		require.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+
+		pusher := new(mockPushService)
+		a.pusher = pusher
+		_, err = a.Ingest(ctx, connect.NewRequest(&v1.AdHocProfilesIngestRequest{
+			Id:     id,
+			Labels: []*typesv1.LabelPair{{Name: "service_name", Value: "benchmark"}},
+		}))
+		require.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+		require.Empty(t, pusher.reqs)
+	})
+
+	t.Run("delete expired profiles", func(t *testing.T) {
+		a := &AdHocProfiles{
+			logger: util.Logger,
+			limits: validation.MockLimits{AdHocProfilesRetentionPeriodValue: time.Hour},
+			bucket: bucket,
+		}
+		_ = bucket.Upload(ctx, "tenant/adhoc/01HMXV8BF4EH71NBYZNPPVGJ2X-expired.pprof", bytes.NewReader([]byte{1}))
+		_ = bucket.Upload(ctx, "tenant/adhoc/meta/01HMXV8BF4EH71NBYZNPPVGJ2X-expired.pprof", bytes.NewReader([]byte("{}")))
+
+		resp, err := a.List(ctx, connect.NewRequest(&v1.AdHocProfilesListRequest{}))
+		require.NoError(t, err)
+		require.Len(t, resp.Msg.Profiles, 1)
+		require.Equal(t, "cpu.pprof", resp.Msg.Profiles[0].Name)
+		require.NotZero(t, resp.Msg.Profiles[0].ExpiresAt)
+
+		require.NoError(t, a.deleteExpired(ctx))
+		for _, key := range []string{
+			"tenant/adhoc/01HMXV8BF4EH71NBYZNPPVGJ2X-expired.pprof",
+			"tenant/adhoc/meta/01HMXV8BF4EH71NBYZNPPVGJ2X-expired.pprof",
+		} {
+			exists, err := bucket.Exists(ctx, key)
+			require.NoError(t, err)
+			require.False(t, exists)
+		}
+		exists, err := bucket.Exists(ctx, "tenant/adhoc/"+resp.Msg.Profiles[0].Id)
+		require.NoError(t, err)
+		require.True(t, exists)
+	})
+}
+
+type mockPushService struct {
+	reqs []*distributormodel.PushRequest
+}
+
+func (m *mockPushService) PushParsed(_ context.Context, req *distributormodel.PushRequest) (*connect.Response[pushv1.PushResponse], error) {
+	m.reqs = append(m.reqs, req)
+	return connect.NewResponse(&pushv1.PushResponse{}), nil
+}
+
+func TestAdHocProfiles_Ingest(t *testing.T) {
+	bucket := phlareobjstore.NewBucket(thanosobjstore.NewInMemBucket())
+	rawProfile, err := os.ReadFile("testdata/cpu.pprof")
+	require.NoError(t, err)
+	ctx := tenant.InjectTenantID(context.Background(), "tenant")
+
+	pusher := new(mockPushService)
+	a := &AdHocProfiles{
+		logger: util.Logger,
+		limits: validation.MockLimits{MaxFlameGraphNodesDefaultValue: 8192},
+		bucket: bucket,
+		pusher: pusher,
+	}
+
+	upload := func(name string, data []byte) string {
+		resp, err := a.Upload(ctx, connect.NewRequest(&v1.AdHocProfilesUploadRequest{
+			Name:    name,
+			Profile: base64.StdEncoding.EncodeToString(data),
+		}))
+		require.NoError(t, err)
+		return resp.Msg.Id
+	}
+
+	ts := time.UnixMilli(1706103680484)
+	serviceName := []*typesv1.LabelPair{{Name: "service_name", Value: "benchmark"}}
+
+	t.Run("reject profiles without service name", func(t *testing.T) {
+		_, err := a.Ingest(ctx, connect.NewRequest(&v1.AdHocProfilesIngestRequest{
+			Id: upload("cpu.pprof", rawProfile),
+		}))
+		require.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+	})
+
+	t.Run("ingest pprof", func(t *testing.T) {
+		pusher.reqs = nil
+		_, err := a.Ingest(ctx, connect.NewRequest(&v1.AdHocProfilesIngestRequest{
+			Id:        upload("cpu.pprof", rawProfile),
+			Labels:    serviceName,
+			Timestamp: ts.UnixMilli(),
+		}))
+		require.NoError(t, err)
+		require.Len(t, pusher.reqs, 1)
+		require.Len(t, pusher.reqs[0].Series, 1)
+		series := pusher.reqs[0].Series[0]
+		require.Equal(t, "process_cpu", phlaremodel.Labels(series.Labels).Get("__name__"))
+		require.Equal(t, "benchmark", phlaremodel.Labels(series.Labels).Get("service_name"))
+		require.Equal(t, ts.UnixNano(), series.Samples[0].Profile.TimeNanos)
+	})
+
+	t.Run("ingest collapsed", func(t *testing.T) {
+		pusher.reqs = nil
+		_, err := a.Ingest(ctx, connect.NewRequest(&v1.AdHocProfilesIngestRequest{
+			Id:        upload("profile.txt", []byte("foo;bar 10\nfoo;baz 20\n")),
+			Labels:    serviceName,
+			Timestamp: ts.UnixMilli(),
+		}))
+		require.NoError(t, err)
+		require.Len(t, pusher.reqs, 1)
+		require.Len(t, pusher.reqs[0].Series, 1)
+		series := pusher.reqs[0].Series[0]
+		require.Equal(t, "process_cpu", phlaremodel.Labels(series.Labels).Get("__name__"))
+		p := series.Samples[0].Profile
+		require.Equal(t, ts.UnixNano(), p.TimeNanos)
+		require.Equal(t, "cpu", p.StringTable[p.SampleType[0].Type])
+		var total int64
+		for _, s := range p.Sample {
+			total += s.Value[0]
+		}
+		require.Equal(t, 30*(time.Second/100).Nanoseconds(), total)
+	})
+}
@@ -0,0 +1,188 @@
+package adhocprofiles
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/grafana/dskit/tenant"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/model/labels"
+
+	v1 "github.com/grafana/pyroscope/api/gen/proto/go/adhocprofiles/v1"
+	pushv1 "github.com/grafana/pyroscope/api/gen/proto/go/push/v1"
+	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+	distributormodel "github.com/grafana/pyroscope/pkg/distributor/model"
+	phlaremodel "github.com/grafana/pyroscope/pkg/model"
+	ogpprof "github.com/grafana/pyroscope/pkg/og/convert/pprof"
+	"github.com/grafana/pyroscope/pkg/og/storage/metadata"
+	"github.com/grafana/pyroscope/pkg/og/storage/tree"
+	"github.com/grafana/pyroscope/pkg/og/structs/flamebearer"
+	"github.com/grafana/pyroscope/pkg/og/structs/flamebearer/convert"
+	"github.com/grafana/pyroscope/pkg/pprof"
+)
+
+// PushService is the write path ad hoc profiles are ingested through.
+type PushService interface {
+	PushParsed(ctx context.Context, req *distributormodel.PushRequest) (*connect.Response[pushv1.PushResponse], error)
+}
+
+func (a *AdHocProfiles) Ingest(ctx context.Context, c *connect.Request[v1.AdHocProfilesIngestRequest]) (*connect.Response[v1.AdHocProfilesIngestResponse], error) {
+	if a.pusher == nil {
+		return nil, connect.NewError(connect.CodeUnimplemented, fmt.Errorf("ingesting ad hoc profiles is not supported by this instance"))
+	}
+
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	ls := phlaremodel.Labels(c.Msg.Labels).Clone()
+	if ls.Get(phlaremodel.LabelNameServiceName) == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("the %s label is required", phlaremodel.LabelNameServiceName))
+	}
+
+	if err = a.checkNotExpired(tenantID, c.Msg.Id); err != nil {
+		return nil, err
+	}
+	bucket := a.getBucket(tenantID)
+	adHocProfile, err := readProfile(ctx, bucket, c.Msg.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	ts := adHocProfile.UploadedAt
+	if c.Msg.Timestamp > 0 {
+		ts = time.UnixMilli(c.Msg.Timestamp)
+	}
+
+	req, err := toPushRequest(adHocProfile, ls, ts)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	if _, err = a.pusher.PushParsed(ctx, req); err != nil {
+		return nil, err
+	}
+
+	return connect.NewResponse(&v1.AdHocProfilesIngestResponse{}), nil
+}
+
+// toPushRequest converts an ad hoc profile to a push request. pprof
+// profiles are pushed as is, other formats are converted to pprof, one
+// series per profile type.
+func toPushRequest(p *AdHocProfile, ls phlaremodel.Labels, ts time.Time) (*distributormodel.PushRequest, error) {
+	data, err := base64.StdEncoding.DecodeString(p.Data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode profile")
+	}
+
+	f := convert.ProfileFile{
+		Name: p.Name,
+		Data: data,
+	}
+	convertFn, fileType, err := convert.Converter(f)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &distributormodel.PushRequest{
+		RawProfileSize: len(data),
+		RawProfileType: distributormodel.RawProfileTypePPROF,
+	}
+
+	if fileType == convert.ProfileFileTypePprof {
+		profile, err := pprof.RawFromBytes(data)
+		if err != nil {
+			return nil, err
+		}
+		profile.TimeNanos = ts.UnixNano()
+		req.Series = append(req.Series, &distributormodel.ProfileSeries{
+			Labels: seriesLabels(ls, ogpprof.MetricName(profile)),
+			Samples: []*distributormodel.ProfileSample{{
+				Profile:    profile,
+				RawProfile: data,
+				ID:         uuid.NewString(),
+			}},
+		})
+		return req, nil
+	}
+
+	profiles, err := convertFn(f.Data, f.Name, -1)
+	if err != nil {
+		return nil, err
+	}
+	for _, fb := range profiles {
+		metricName, md, err := pprofMetadata(fb.Metadata, ts)
+		if err != nil {
+			return nil, err
+		}
+		t, err := flamebearer.ProfileToTree(*fb)
+		if err != nil {
+			return nil, err
+		}
+		if md.Period > 0 {
+			t.Scale(uint64(md.Period))
+		}
+		b, err := t.Pprof(md).MarshalVT()
+		if err != nil {
+			return nil, err
+		}
+		profile, err := pprof.RawFromBytes(b)
+		if err != nil {
+			return nil, err
+		}
+		req.Series = append(req.Series, &distributormodel.ProfileSeries{
+			Labels: seriesLabels(ls, metricName),
+			Samples: []*distributormodel.ProfileSample{{
+				Profile:    profile,
+				RawProfile: b,
+				ID:         uuid.NewString(),
+			}},
+		})
+	}
+	return req, nil
+}
+
+// pprofMetadata returns the metric name and the pprof sample type of a
+// profile based on its units. Samples are converted to CPU time, using
+// the sample rate, so that they can be compared with CPU profiles.
+func pprofMetadata(fb flamebearer.FlamebearerMetadataV1, ts time.Time) (string, *tree.PprofMetadata, error) {
+	md := &tree.PprofMetadata{StartTime: ts}
+	switch fb.Units {
+	case "", metadata.SamplesUnits:
+		if fb.SampleRate == 0 {
+			md.Type, md.Unit = "samples", "count"
+			return "process_cpu", md, nil
+		}
+		md.Type, md.Unit = "cpu", "nanoseconds"
+		md.PeriodType, md.PeriodUnit = "cpu", "nanoseconds"
+		md.Period = time.Second.Nanoseconds() / int64(fb.SampleRate)
+		return "process_cpu", md, nil
+	case metadata.ObjectsUnits:
+		md.Type, md.Unit = "inuse_objects", "count"
+		return "memory", md, nil
+	case metadata.BytesUnits:
+		md.Type, md.Unit = "inuse_space", "bytes"
+		return "memory", md, nil
+	case metadata.GoroutinesUnits:
+		md.Type, md.Unit = "goroutine", "count"
+		return "goroutine", md, nil
+	default:
+		return "", nil, fmt.Errorf("unsupported profile units %q", fb.Units)
+	}
+}
+
+func seriesLabels(ls phlaremodel.Labels, metricName string) []*typesv1.LabelPair {
+	res := ls.Clone()
+	if res.Get(labels.MetricName) == "" {
+		res = append(res, &typesv1.LabelPair{Name: labels.MetricName, Value: metricName})
+	}
+	if res.Get(phlaremodel.LabelNameDelta) == "" {
+		res = append(res, &typesv1.LabelPair{Name: phlaremodel.LabelNameDelta, Value: "false"})
+	}
+	return res
+}
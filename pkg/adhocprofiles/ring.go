@@ -0,0 +1,33 @@
+package adhocprofiles
+
+import (
+	"flag"
+	"strings"
+
+	"github.com/go-kit/log"
+
+	"github.com/grafana/pyroscope/pkg/util/fieldcategory"
+	"github.com/grafana/pyroscope/pkg/validation/exporter"
+)
+
+// ringName is the name of the ring used to elect the replica that deletes
+// expired profiles, and the key it is stored under in the KVStore.
+const ringName = "ad-hoc-profiles"
+
+type Config struct {
+	Ring exporter.RingConfig `yaml:"ring"`
+}
+
+func (c *Config) RegisterFlags(f *flag.FlagSet, logger log.Logger) {
+	const flagNamePrefix = "adhoc-profiles.ring."
+	c.Ring.RegisterFlagsWithPrefix(flagNamePrefix, "ad hoc profiles instances", f, logger)
+	// The ring shares the KV store of the ingester ring, therefore
+	// the flags are only listed with the advanced ones.
+	advanced := make(map[string]fieldcategory.Category)
+	f.VisitAll(func(fl *flag.Flag) {
+		if strings.HasPrefix(fl.Name, flagNamePrefix) {
+			advanced[fl.Name] = fieldcategory.Advanced
+		}
+	})
+	fieldcategory.AddOverrides(advanced)
+}
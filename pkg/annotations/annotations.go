@@ -0,0 +1,209 @@
+package annotations
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/tenant"
+	"github.com/oklog/ulid"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+
+	annotationsv1 "github.com/grafana/pyroscope/api/gen/proto/go/annotations/v1"
+)
+
+func New(store Store, logger log.Logger) (*Annotations, error) {
+	a := &Annotations{
+		store:  store,
+		logger: logger,
+	}
+
+	a.Service = services.NewIdleService(nil, a.stopping)
+
+	return a, nil
+}
+
+// Annotations records events, such as deploys or incidents, that can be
+// displayed alongside profiles on a timeline.
+type Annotations struct {
+	services.Service
+
+	store  Store
+	logger log.Logger
+}
+
+func (a *Annotations) stopping(_ error) error {
+	return a.store.Close()
+}
+
+func (a *Annotations) Create(ctx context.Context, req *connect.Request[annotationsv1.CreateAnnotationRequest]) (*connect.Response[annotationsv1.CreateAnnotationResponse], error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	annotation := &annotationsv1.Annotation{
+		Start:         req.Msg.Start,
+		End:           req.Msg.End,
+		LabelSelector: strings.TrimSpace(req.Msg.LabelSelector),
+		Text:          strings.TrimSpace(req.Msg.Text),
+		Tags:          normalizeTags(req.Msg.Tags),
+		CreatedAt:     time.Now().UnixMilli(),
+	}
+	if annotation.End == 0 {
+		annotation.End = annotation.Start
+	}
+	if err = validateAnnotation(annotation); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	annotation.Id = ulid.MustNew(ulid.Timestamp(time.UnixMilli(annotation.CreatedAt)), rand.Reader).String()
+
+	annotation, err = a.store.Create(ctx, tenantID, annotation)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	return connect.NewResponse(&annotationsv1.CreateAnnotationResponse{
+		Annotation: annotation,
+	}), nil
+}
+
+func (a *Annotations) List(ctx context.Context, req *connect.Request[annotationsv1.ListAnnotationsRequest]) (*connect.Response[annotationsv1.ListAnnotationsResponse], error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	var matchers []*labels.Matcher
+	if req.Msg.LabelSelector != "" {
+		matchers, err = parser.ParseMetricSelector(req.Msg.LabelSelector)
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, errors.Wrap(err, "failed to parse label selector"))
+		}
+	}
+
+	annotations, err := a.store.List(ctx, tenantID)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	filtered := make([]*annotationsv1.Annotation, 0, len(annotations))
+	for _, annotation := range annotations {
+		if !overlaps(annotation, req.Msg.Start, req.Msg.End) {
+			continue
+		}
+		if !hasTags(annotation, req.Msg.Tags) {
+			continue
+		}
+		if !scopeMatches(annotation, matchers) {
+			continue
+		}
+		filtered = append(filtered, annotation)
+	}
+
+	return connect.NewResponse(&annotationsv1.ListAnnotationsResponse{
+		Annotations: filtered,
+	}), nil
+}
+
+func (a *Annotations) Delete(ctx context.Context, req *connect.Request[annotationsv1.DeleteAnnotationRequest]) (*connect.Response[annotationsv1.DeleteAnnotationResponse], error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	if req.Msg.Id == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("no annotation id provided"))
+	}
+
+	err = a.store.Delete(ctx, tenantID, req.Msg.Id)
+	if err != nil {
+		if errors.Is(err, annotationNotFoundErr) {
+			return nil, connect.NewError(connect.CodeNotFound, err)
+		}
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	return connect.NewResponse(&annotationsv1.DeleteAnnotationResponse{}), nil
+}
+
+func validateAnnotation(annotation *annotationsv1.Annotation) error {
+	if annotation.Start <= 0 {
+		return fmt.Errorf("annotation start time is required")
+	}
+	if annotation.End < annotation.Start {
+		return fmt.Errorf("annotation end time must not be before its start time")
+	}
+	if annotation.Text == "" {
+		return fmt.Errorf("annotation text is required")
+	}
+	if annotation.LabelSelector != "" {
+		if _, err := parser.ParseMetricSelector(annotation.LabelSelector); err != nil {
+			return errors.Wrap(err, "failed to parse label selector")
+		}
+	}
+	return nil
+}
+
+// overlaps reports whether the annotation overlaps the given time range. A
+// zero start or end leaves the range open on that side.
+func overlaps(annotation *annotationsv1.Annotation, start, end int64) bool {
+	if start > 0 && annotation.End < start {
+		return false
+	}
+	if end > 0 && annotation.Start > end {
+		return false
+	}
+	return true
+}
+
+func hasTags(annotation *annotationsv1.Annotation, tags []string) bool {
+	for _, tag := range tags {
+		if !slices.Contains(annotation.Tags, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// scopeMatches reports whether the series selected by the query matchers
+// fall within the scope of the annotation: every matcher of the annotation
+// selector must be satisfied by an equality matcher of the query. An
+// annotation without a selector applies to all series, and a query without
+// matchers returns all annotations.
+func scopeMatches(annotation *annotationsv1.Annotation, query []*labels.Matcher) bool {
+	if annotation.LabelSelector == "" || len(query) == 0 {
+		return true
+	}
+	scope, err := parser.ParseMetricSelector(annotation.LabelSelector)
+	if err != nil {
+		return false
+	}
+	for _, m := range scope {
+		if !slices.ContainsFunc(query, func(q *labels.Matcher) bool {
+			return q.Type == labels.MatchEqual && q.Name == m.Name && m.Matches(q.Value)
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeTags(tags []string) []string {
+	res := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			res = append(res, tag)
+		}
+	}
+	slices.Sort(res)
+	return slices.Compact(res)
+}
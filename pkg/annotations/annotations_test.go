@@ -0,0 +1,141 @@
+package annotations
+
+import (
+	"context"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+
+	annotationsv1 "github.com/grafana/pyroscope/api/gen/proto/go/annotations/v1"
+	"github.com/grafana/pyroscope/pkg/tenant"
+)
+
+func newTestAnnotations(t *testing.T, bucket objstore.Bucket) *Annotations {
+	t.Helper()
+	store, err := NewBucketStore(bucket)
+	require.NoError(t, err)
+	a, err := New(store, log.NewNopLogger())
+	require.NoError(t, err)
+	return a
+}
+
+func createAnnotation(t *testing.T, ctx context.Context, a *Annotations, req *annotationsv1.CreateAnnotationRequest) *annotationsv1.Annotation {
+	t.Helper()
+	resp, err := a.Create(ctx, connect.NewRequest(req))
+	require.NoError(t, err)
+	return resp.Msg.Annotation
+}
+
+func listAnnotations(t *testing.T, ctx context.Context, a *Annotations, req *annotationsv1.ListAnnotationsRequest) []string {
+	t.Helper()
+	resp, err := a.List(ctx, connect.NewRequest(req))
+	require.NoError(t, err)
+	texts := make([]string, 0, len(resp.Msg.Annotations))
+	for _, annotation := range resp.Msg.Annotations {
+		texts = append(texts, annotation.Text)
+	}
+	return texts
+}
+
+func TestAnnotations_Create(t *testing.T) {
+	a := newTestAnnotations(t, objstore.NewInMemBucket())
+	ctx := tenant.InjectTenantID(context.Background(), "1234")
+
+	t.Run("point in time annotation", func(t *testing.T) {
+		annotation := createAnnotation(t, ctx, a, &annotationsv1.CreateAnnotationRequest{
+			Start: 100,
+			Text:  " deploy v1.2.3 ",
+			Tags:  []string{"deploy", "", "deploy", "checkout"},
+		})
+		require.NotEmpty(t, annotation.Id)
+		require.NotZero(t, annotation.CreatedAt)
+		require.Equal(t, int64(100), annotation.End)
+		require.Equal(t, "deploy v1.2.3", annotation.Text)
+		require.Equal(t, []string{"checkout", "deploy"}, annotation.Tags)
+	})
+
+	for _, tc := range []struct {
+		name string
+		req  *annotationsv1.CreateAnnotationRequest
+	}{
+		{name: "missing start", req: &annotationsv1.CreateAnnotationRequest{Text: "deploy"}},
+		{name: "missing text", req: &annotationsv1.CreateAnnotationRequest{Start: 100}},
+		{name: "end before start", req: &annotationsv1.CreateAnnotationRequest{Start: 100, End: 50, Text: "deploy"}},
+		{name: "invalid selector", req: &annotationsv1.CreateAnnotationRequest{Start: 100, Text: "deploy", LabelSelector: "{service_name="}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := a.Create(ctx, connect.NewRequest(tc.req))
+			require.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+		})
+	}
+
+	t.Run("missing tenant id", func(t *testing.T) {
+		_, err := a.Create(context.Background(), connect.NewRequest(&annotationsv1.CreateAnnotationRequest{Start: 100, Text: "deploy"}))
+		require.EqualError(t, err, "invalid_argument: no org id")
+	})
+}
+
+func TestAnnotations_List(t *testing.T) {
+	bucket := objstore.NewInMemBucket()
+	a := newTestAnnotations(t, bucket)
+	ctx := tenant.InjectTenantID(context.Background(), "1234")
+
+	createAnnotation(t, ctx, a, &annotationsv1.CreateAnnotationRequest{Start: 300, Text: "incident", End: 500, Tags: []string{"incident"}})
+	createAnnotation(t, ctx, a, &annotationsv1.CreateAnnotationRequest{Start: 100, Text: "deploy checkout", LabelSelector: `{service_name="checkout"}`, Tags: []string{"deploy"}})
+	createAnnotation(t, ctx, a, &annotationsv1.CreateAnnotationRequest{Start: 200, Text: "deploy cart", LabelSelector: `{service_name=~"cart|cart-.*"}`, Tags: []string{"deploy"}})
+	createAnnotation(t, tenant.InjectTenantID(context.Background(), "other"), a, &annotationsv1.CreateAnnotationRequest{Start: 100, Text: "other tenant"})
+
+	t.Run("all annotations ordered by start", func(t *testing.T) {
+		got := listAnnotations(t, ctx, a, &annotationsv1.ListAnnotationsRequest{})
+		require.Equal(t, []string{"deploy checkout", "deploy cart", "incident"}, got)
+	})
+
+	t.Run("overlapping the time range", func(t *testing.T) {
+		got := listAnnotations(t, ctx, a, &annotationsv1.ListAnnotationsRequest{Start: 150, End: 350})
+		require.Equal(t, []string{"deploy cart", "incident"}, got)
+
+		got = listAnnotations(t, ctx, a, &annotationsv1.ListAnnotationsRequest{Start: 450, End: 1000})
+		require.Equal(t, []string{"incident"}, got)
+	})
+
+	t.Run("matching the query selector", func(t *testing.T) {
+		got := listAnnotations(t, ctx, a, &annotationsv1.ListAnnotationsRequest{LabelSelector: `{service_name="cart-eu", region="eu"}`})
+		require.Equal(t, []string{"deploy cart", "incident"}, got)
+
+		got = listAnnotations(t, ctx, a, &annotationsv1.ListAnnotationsRequest{LabelSelector: `{service_name=~"check.*"}`})
+		require.Equal(t, []string{"incident"}, got)
+
+		_, err := a.List(ctx, connect.NewRequest(&annotationsv1.ListAnnotationsRequest{LabelSelector: "{"}))
+		require.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+	})
+
+	t.Run("having tags", func(t *testing.T) {
+		got := listAnnotations(t, ctx, a, &annotationsv1.ListAnnotationsRequest{Tags: []string{"deploy"}})
+		require.Equal(t, []string{"deploy checkout", "deploy cart"}, got)
+	})
+
+	t.Run("persisted in the bucket", func(t *testing.T) {
+		got := listAnnotations(t, ctx, newTestAnnotations(t, bucket), &annotationsv1.ListAnnotationsRequest{})
+		require.Equal(t, []string{"deploy checkout", "deploy cart", "incident"}, got)
+	})
+}
+
+func TestAnnotations_Delete(t *testing.T) {
+	a := newTestAnnotations(t, objstore.NewInMemBucket())
+	ctx := tenant.InjectTenantID(context.Background(), "1234")
+
+	annotation := createAnnotation(t, ctx, a, &annotationsv1.CreateAnnotationRequest{Start: 100, Text: "deploy"})
+
+	_, err := a.Delete(tenant.InjectTenantID(context.Background(), "other"), connect.NewRequest(&annotationsv1.DeleteAnnotationRequest{Id: annotation.Id}))
+	require.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+
+	_, err = a.Delete(ctx, connect.NewRequest(&annotationsv1.DeleteAnnotationRequest{Id: annotation.Id}))
+	require.NoError(t, err)
+	require.Empty(t, listAnnotations(t, ctx, a, &annotationsv1.ListAnnotationsRequest{}))
+
+	_, err = a.Delete(ctx, connect.NewRequest(&annotationsv1.DeleteAnnotationRequest{Id: annotation.Id}))
+	require.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+}
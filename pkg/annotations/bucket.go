@@ -0,0 +1,143 @@
+package annotations
+
+import (
+	"context"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/thanos-io/objstore"
+
+	annotationsv1 "github.com/grafana/pyroscope/api/gen/proto/go/annotations/v1"
+	phlareobj "github.com/grafana/pyroscope/pkg/objstore"
+)
+
+var (
+	annotationNotFoundErr = errors.New("annotation not found")
+	annotationsFilename   = "annotations.json"
+)
+
+// NewMemoryStore will create an annotations store with an in-memory objstore
+// bucket.
+func NewMemoryStore() (Store, error) {
+	return NewBucketStore(objstore.NewInMemBucket())
+}
+
+// NewBucketStore will create an annotations store with an objstore bucket.
+// Annotations of a tenant are stored in a single object within the tenant
+// directory.
+func NewBucketStore(bucket objstore.Bucket) (Store, error) {
+	store := &bucketStore{
+		store:  make(map[string]map[string]*annotationsv1.Annotation),
+		bucket: bucket,
+	}
+
+	return store, nil
+}
+
+type bucketStore struct {
+	rw sync.Mutex
+
+	// store is annotations indexed by tenant id and annotation id.
+	store map[string]map[string]*annotationsv1.Annotation
+
+	// bucket is an object store bucket.
+	bucket objstore.Bucket
+}
+
+func (s *bucketStore) List(ctx context.Context, tenantID string) ([]*annotationsv1.Annotation, error) {
+	s.rw.Lock()
+	defer s.rw.Unlock()
+
+	err := s.unsafeLoad(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	tenantAnnotations := s.store[tenantID]
+
+	annotations := make([]*annotationsv1.Annotation, 0, len(tenantAnnotations))
+	for _, annotation := range tenantAnnotations {
+		annotations = append(annotations, annotation)
+	}
+
+	slices.SortFunc(annotations, func(a, b *annotationsv1.Annotation) int {
+		if a.Start != b.Start {
+			if a.Start < b.Start {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Id, b.Id)
+	})
+	return annotations, nil
+}
+
+func (s *bucketStore) Create(ctx context.Context, tenantID string, annotation *annotationsv1.Annotation) (*annotationsv1.Annotation, error) {
+	s.rw.Lock()
+	defer s.rw.Unlock()
+
+	err := s.unsafeLoad(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, ok := s.store[tenantID]
+	if !ok {
+		s.store[tenantID] = make(map[string]*annotationsv1.Annotation, 1)
+	}
+	s.store[tenantID][annotation.Id] = annotation
+
+	err = s.unsafeFlush(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	return annotation, nil
+}
+
+func (s *bucketStore) Delete(ctx context.Context, tenantID string, id string) error {
+	s.rw.Lock()
+	defer s.rw.Unlock()
+
+	err := s.unsafeLoad(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := s.store[tenantID][id]; !ok {
+		return errors.Wrapf(annotationNotFoundErr, "failed to delete %s", id)
+	}
+	delete(s.store[tenantID], id)
+
+	return s.unsafeFlush(ctx, tenantID)
+}
+
+func (s *bucketStore) Close() error {
+	return s.bucket.Close()
+}
+
+func tenantAnnotationsPath(tenantID string) string {
+	return path.Join(tenantID, annotationsFilename)
+}
+
+// unsafeFlush will flush the annotations of a tenant to object storage. This
+// is not thread-safe, the store's write mutex should be acquired first.
+func (s *bucketStore) unsafeFlush(ctx context.Context, tenantID string) error {
+	return phlareobj.UploadJSON(ctx, s.bucket, tenantAnnotationsPath(tenantID), s.store[tenantID])
+}
+
+// unsafeLoad will read the annotations of a tenant in object storage into
+// memory, if they exist. This is not thread-safe, the store's write mutex
+// should be acquired first.
+func (s *bucketStore) unsafeLoad(ctx context.Context, tenantID string) error {
+	tenantAnnotations := make(map[string]*annotationsv1.Annotation)
+	found, err := phlareobj.ReadJSON(ctx, s.bucket, tenantAnnotationsPath(tenantID), &tenantAnnotations)
+	if err != nil || !found {
+		return err
+	}
+	s.store[tenantID] = tenantAnnotations
+	return nil
+}
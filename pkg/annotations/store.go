@@ -0,0 +1,21 @@
+package annotations
+
+import (
+	"context"
+
+	annotationsv1 "github.com/grafana/pyroscope/api/gen/proto/go/annotations/v1"
+)
+
+type Store interface {
+	// List all annotations of a tenant, ordered by start time.
+	List(ctx context.Context, tenantID string) ([]*annotationsv1.Annotation, error)
+
+	// Create an annotation for a tenant.
+	Create(ctx context.Context, tenantID string, annotation *annotationsv1.Annotation) (*annotationsv1.Annotation, error)
+
+	// Delete an annotation of a tenant.
+	Delete(ctx context.Context, tenantID string, id string) error
+
+	// Close the store.
+	Close() error
+}
@@ -25,9 +25,11 @@ import (
 	"github.com/grafana/pyroscope/public"
 
 	"github.com/grafana/pyroscope/api/gen/proto/go/adhocprofiles/v1/adhocprofilesv1connect"
+	"github.com/grafana/pyroscope/api/gen/proto/go/annotations/v1/annotationsv1connect"
 	"github.com/grafana/pyroscope/api/gen/proto/go/ingester/v1/ingesterv1connect"
 	"github.com/grafana/pyroscope/api/gen/proto/go/push/v1/pushv1connect"
 	"github.com/grafana/pyroscope/api/gen/proto/go/querier/v1/querierv1connect"
+	"github.com/grafana/pyroscope/api/gen/proto/go/serviceversions/v1/serviceversionsv1connect"
 	"github.com/grafana/pyroscope/api/gen/proto/go/settings/v1/settingsv1connect"
 	statusv1 "github.com/grafana/pyroscope/api/gen/proto/go/status/v1"
 	"github.com/grafana/pyroscope/api/gen/proto/go/storegateway/v1/storegatewayv1connect"
@@ -35,6 +37,7 @@ import (
 	"github.com/grafana/pyroscope/api/gen/proto/go/version/v1/versionv1connect"
 	"github.com/grafana/pyroscope/api/openapiv2"
 	"github.com/grafana/pyroscope/pkg/adhocprofiles"
+	"github.com/grafana/pyroscope/pkg/annotations"
 	connectapi "github.com/grafana/pyroscope/pkg/api/connect"
 	"github.com/grafana/pyroscope/pkg/compactor"
 	"github.com/grafana/pyroscope/pkg/distributor"
@@ -51,23 +54,36 @@ import (
 	"github.com/grafana/pyroscope/pkg/util"
 	"github.com/grafana/pyroscope/pkg/util/gziphandler"
 	"github.com/grafana/pyroscope/pkg/validation/exporter"
+	"github.com/grafana/pyroscope/pkg/validation/tenantoverrides"
 )
 
 type Config struct {
 	// The following configs are injected by the upstream caller.
 	HTTPAuthMiddleware middleware.Interface `yaml:"-"`
 	GrpcAuthMiddleware connect.Option       `yaml:"-"`
-	BaseURL            string               `yaml:"base-url"`
+	// GrpcUserAuthMiddleware authenticates the requests to the user facing
+	// services. If not set, GrpcAuthMiddleware is used.
+	GrpcUserAuthMiddleware connect.Option `yaml:"-"`
+	// HTTPAdminAuthMiddleware authenticates the requests to the
+	// administrative endpoints that modify the state of the cluster.
+	HTTPAdminAuthMiddleware middleware.Interface `yaml:"-"`
+	BaseURL                 string               `yaml:"base-url"`
 }
 
 type API struct {
 	server             *server.Server
 	httpAuthMiddleware middleware.Interface
+	httpAdminAuth      middleware.Interface
 	grpcGatewayMux     *grpcgw.ServeMux
 	grpcAuthMiddleware connect.Option
+	grpcUserAuth       connect.Option
 	grpcLogMiddleware  connect.Option
 	recoveryMiddleware connect.Option
 
+	// annotations is used by the render handlers to attach annotations to
+	// the timeline, if the annotations service is registered.
+	annotations annotationsv1connect.AnnotationServiceClient
+
 	cfg       Config
 	logger    log.Logger
 	indexPage *IndexPageContent
@@ -77,11 +93,13 @@ func New(cfg Config, s *server.Server, grpcGatewayMux *grpcgw.ServeMux, logger l
 	api := &API{
 		cfg:                cfg,
 		httpAuthMiddleware: cfg.HTTPAuthMiddleware,
+		httpAdminAuth:      cfg.HTTPAdminAuthMiddleware,
 		server:             s,
 		logger:             logger,
 		indexPage:          NewIndexPageContent(),
 		grpcGatewayMux:     grpcGatewayMux,
 		grpcAuthMiddleware: cfg.GrpcAuthMiddleware,
+		grpcUserAuth:       cfg.GrpcUserAuthMiddleware,
 		grpcLogMiddleware:  connect.WithInterceptors(util.NewLogInterceptor(logger)),
 		recoveryMiddleware: connect.WithInterceptors(util.RecoveryInterceptor),
 	}
@@ -90,6 +108,9 @@ func New(cfg Config, s *server.Server, grpcGatewayMux *grpcgw.ServeMux, logger l
 	if cfg.HTTPAuthMiddleware == nil {
 		api.httpAuthMiddleware = middleware.AuthenticateUser
 	}
+	if cfg.GrpcUserAuthMiddleware == nil {
+		api.grpcUserAuth = cfg.GrpcAuthMiddleware
+	}
 
 	return api, nil
 }
@@ -202,8 +223,21 @@ func (a *API) RegisterRuntimeConfig(runtimeConfigHandler http.HandlerFunc, userL
 	})
 }
 
+func (a *API) RegisterTenantOverrides(m *tenantoverrides.Manager) {
+	var overridesHandler, tenantOverridesHandler http.Handler = http.HandlerFunc(m.OverridesHandler), http.HandlerFunc(m.TenantOverridesHandler)
+	if a.httpAdminAuth != nil {
+		overridesHandler = a.httpAdminAuth.Wrap(overridesHandler)
+		tenantOverridesHandler = a.httpAdminAuth.Wrap(tenantOverridesHandler)
+	}
+	a.RegisterRoute("/ops/overrides", overridesHandler, false, true, "GET")
+	a.RegisterRoute("/ops/overrides/{tenant}", tenantOverridesHandler, false, true, "GET", "PUT", "DELETE")
+	a.indexPage.AddLinks(runtimeConfigWeight, "Tenant overrides", []IndexPageLink{
+		{Desc: "Tenant overrides set through the API", Path: "/ops/overrides"},
+	})
+}
+
 func (a *API) RegisterTenantSettings(ts *settings.TenantSettings) {
-	settingsv1connect.RegisterSettingsServiceHandler(a.server.HTTP, ts, a.connectOptionsAuthRecovery()...)
+	settingsv1connect.RegisterSettingsServiceHandler(a.server.HTTP, ts, a.connectOptionsUserAuthRecovery()...)
 }
 
 // RegisterOverridesExporter registers the endpoints associated with the overrides exporter.
@@ -215,11 +249,11 @@ func (a *API) RegisterOverridesExporter(oe *exporter.OverridesExporter) {
 }
 
 // RegisterDistributor registers the endpoints associated with the distributor.
-func (a *API) RegisterDistributor(d *distributor.Distributor) {
-	pyroscopeHandler := pyroscope.NewPyroscopeIngestHandler(d, a.logger)
+func (a *API) RegisterDistributor(d *distributor.Distributor, limits pyroscope.Limits) {
+	pyroscopeHandler := pyroscope.NewPyroscopeIngestHandler(d, limits, a.logger)
 	a.RegisterRoute("/ingest", pyroscopeHandler, true, true, "POST")
 	a.RegisterRoute("/pyroscope/ingest", pyroscopeHandler, true, true, "POST")
-	pushv1connect.RegisterPusherServiceHandler(a.server.HTTP, d, a.connectOptionsAuthRecovery()...)
+	pushv1connect.RegisterPusherServiceHandler(a.server.HTTP, d, a.connectOptionsUserAuthRecovery()...)
 	a.RegisterRoute("/distributor/ring", d, false, true, "GET", "POST")
 	a.indexPage.AddLinks(defaultWeight, "Distributor", []IndexPageLink{
 		{Desc: "Ring status", Path: "/distributor/ring"},
@@ -243,15 +277,19 @@ func (a *API) RegisterIngesterRing(r http.Handler) {
 }
 
 func (a *API) RegisterQuerierServiceHandler(svc querierv1connect.QuerierServiceHandler) {
-	querierv1connect.RegisterQuerierServiceHandler(a.server.HTTP, svc, a.connectOptionsAuthLogRecovery()...)
+	querierv1connect.RegisterQuerierServiceHandler(a.server.HTTP, svc, a.connectOptionsUserAuthLogRecovery()...)
 }
 
 func (a *API) RegisterVCSServiceHandler(svc vcsv1connect.VCSServiceHandler) {
-	vcsv1connect.RegisterVCSServiceHandler(a.server.HTTP, svc, a.connectOptionsAuthLogRecovery()...)
+	vcsv1connect.RegisterVCSServiceHandler(a.server.HTTP, svc, a.connectOptionsUserAuthLogRecovery()...)
+}
+
+func (a *API) RegisterServiceVersionsHandler(svc serviceversionsv1connect.ServiceVersionsServiceHandler) {
+	serviceversionsv1connect.RegisterServiceVersionsServiceHandler(a.server.HTTP, svc, a.connectOptionsUserAuthLogRecovery()...)
 }
 
 func (a *API) RegisterPyroscopeHandlers(client querierv1connect.QuerierServiceClient) {
-	handlers := querier.NewHTTPHandlers(client)
+	handlers := querier.NewHTTPHandlers(client, a.annotations, a.logger)
 	a.RegisterRoute("/pyroscope/render", http.HandlerFunc(handlers.Render), true, true, "GET")
 	a.RegisterRoute("/pyroscope/render-diff", http.HandlerFunc(handlers.RenderDiff), true, true, "GET")
 	a.RegisterRoute("/pyroscope/label-values", http.HandlerFunc(handlers.LabelValues), true, true, "GET")
@@ -314,7 +352,12 @@ func (a *API) RegisterAdmin(ad *operations.Admin) {
 }
 
 func (a *API) RegisterAdHocProfiles(ahp *adhocprofiles.AdHocProfiles) {
-	adhocprofilesv1connect.RegisterAdHocProfileServiceHandler(a.server.HTTP, ahp, a.connectOptionsAuthRecovery()...)
+	adhocprofilesv1connect.RegisterAdHocProfileServiceHandler(a.server.HTTP, ahp, a.connectOptionsUserAuthRecovery()...)
+}
+
+func (a *API) RegisterAnnotations(svc *annotations.Annotations) {
+	a.annotations = svc
+	annotationsv1connect.RegisterAnnotationServiceHandler(a.server.HTTP, svc, a.connectOptionsUserAuthRecovery()...)
 }
 
 func (a *API) connectOptionsRecovery() []connect.HandlerOption {
@@ -325,6 +368,10 @@ func (a *API) connectOptionsAuthRecovery() []connect.HandlerOption {
 	return append(connectapi.DefaultHandlerOptions(), []connect.HandlerOption{a.grpcAuthMiddleware, a.recoveryMiddleware}...)
 }
 
-func (a *API) connectOptionsAuthLogRecovery() []connect.HandlerOption {
-	return append(connectapi.DefaultHandlerOptions(), []connect.HandlerOption{a.grpcAuthMiddleware, a.grpcLogMiddleware, a.recoveryMiddleware}...)
+func (a *API) connectOptionsUserAuthRecovery() []connect.HandlerOption {
+	return append(connectapi.DefaultHandlerOptions(), []connect.HandlerOption{a.grpcUserAuth, a.recoveryMiddleware}...)
+}
+
+func (a *API) connectOptionsUserAuthLogRecovery() []connect.HandlerOption {
+	return append(connectapi.DefaultHandlerOptions(), []connect.HandlerOption{a.grpcUserAuth, a.grpcLogMiddleware, a.recoveryMiddleware}...)
 }
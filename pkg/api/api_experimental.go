@@ -29,7 +29,14 @@ func (a *API) RegisterSegmentWriterRing(r http.Handler) {
 
 func (a *API) RegisterMetastore(svc *metastore.Metastore) {
 	metastorev1.RegisterMetastoreServiceServer(a.server.GRPC, svc)
-	metastorev1connect.RegisterOperatorServiceHandler(a.server.HTTP, svc)
+	// Membership changes may render the metastore unavailable,
+	// therefore the operator API requires the admin role.
+	operator := a.server.HTTP.NewRoute().Subrouter()
+	if a.httpAdminAuth != nil {
+		operator.Use(a.httpAdminAuth.Wrap)
+	}
+	metastorev1connect.RegisterOperatorServiceHandler(operator, svc)
+	operator.Handle("/metastore/compaction/release-quarantined-jobs", http.HandlerFunc(svc.ReleaseQuarantinedJobsHandler)).Methods(http.MethodPost)
 	compactorv1.RegisterCompactionPlannerServer(a.server.GRPC, svc)
 }
 
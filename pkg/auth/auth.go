@@ -0,0 +1,249 @@
+// Package auth implements authentication of the API requests and
+// authorization of the access to tenants.
+//
+// Requests are authenticated with static bearer tokens, basic auth
+// credentials or JWTs issued by an OIDC provider. The principal of the
+// request is granted access to a set of tenants and roles: the tenants
+// of the X-Scope-OrgID header and the role required by the API called
+// are checked against them.
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/go-kit/log"
+	dskittenant "github.com/grafana/dskit/tenant"
+	"github.com/grafana/dskit/user"
+
+	"github.com/grafana/pyroscope/pkg/tenant"
+)
+
+var (
+	ErrNoCredentials      = errors.New("no credentials provided")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// Principal is the authenticated identity of a request.
+type Principal struct {
+	Name string
+
+	// tenants the principal has access to. If nil, the principal
+	// does not have access to any tenant.
+	tenants *tenant.AllowedTenants
+	// defaultTenant is used if the request does not specify the tenant,
+	// and the principal has access to a single tenant only.
+	defaultTenant string
+	roles         []Role
+}
+
+// anonymous is the principal of requests when authentication is disabled.
+var anonymous = newPrincipal("anonymous", []string{AnyTenant}, []Role{RoleAdmin})
+
+func newPrincipal(name string, tenants []string, roles []Role) *Principal {
+	p := &Principal{Name: name, roles: roles}
+	switch {
+	case slices.Contains(tenants, AnyTenant):
+		p.tenants = tenant.NewAllowedTenants(nil, nil)
+	case len(tenants) > 0:
+		p.tenants = tenant.NewAllowedTenants(tenants, nil)
+		if len(tenants) == 1 {
+			p.defaultTenant = tenants[0]
+		}
+	}
+	return p
+}
+
+// HasRole reports whether the principal is granted the role.
+// The admin role grants all the roles.
+func (p *Principal) HasRole(role Role) bool {
+	return slices.Contains(p.roles, role) || slices.Contains(p.roles, RoleAdmin)
+}
+
+// CanAccess reports whether the principal has access to the tenant.
+func (p *Principal) CanAccess(tenantID string) bool {
+	return p.tenants != nil && p.tenants.IsAllowed(tenantID)
+}
+
+type principalContextKey struct{}
+
+// InjectPrincipal returns a derived context with the principal.
+func InjectPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, p)
+}
+
+// PrincipalFromContext returns the principal of the request, if any.
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalContextKey{}).(*Principal)
+	return p, ok
+}
+
+// staticCredential is a token or a password. Only hashes of the secrets
+// are kept, so that they can be compared in constant time regardless of
+// their length.
+type staticCredential struct {
+	hash      [sha256.Size]byte
+	principal *Principal
+}
+
+// Authenticator authenticates requests and authorizes access to tenants.
+type Authenticator struct {
+	multitenancy bool
+	enabled      bool
+	allowed      *tenant.AllowedTenants
+	tokens       []staticCredential
+	users        map[string]staticCredential
+	jwt          *jwtValidator
+
+	internalToken     string
+	internalTokenHash [sha256.Size]byte
+}
+
+// New creates a new Authenticator. If no credentials are configured,
+// requests are not authenticated, but access to the tenants is still
+// restricted by the enabled and disabled tenants.
+func New(cfg Config, multitenancy bool, logger log.Logger) (*Authenticator, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	a := &Authenticator{
+		multitenancy: multitenancy,
+		enabled:      cfg.Enabled(),
+		allowed:      tenant.NewAllowedTenants(cfg.EnabledTenants, cfg.DisabledTenants),
+		users:        make(map[string]staticCredential),
+
+		internalToken:     cfg.InternalToken.String(),
+		internalTokenHash: sha256.Sum256([]byte(cfg.InternalToken.String())),
+	}
+	for i, c := range cfg.Credentials {
+		name := c.Name
+		if name == "" {
+			name = c.Username
+		}
+		if name == "" {
+			name = fmt.Sprintf("token-%d", i)
+		}
+		p := newPrincipal(name, c.Tenants, c.Roles)
+		if c.Username != "" {
+			if _, ok := a.users[c.Username]; ok {
+				return nil, fmt.Errorf("duplicate auth credentials for user %q", c.Username)
+			}
+			a.users[c.Username] = staticCredential{hash: sha256.Sum256([]byte(c.Password.String())), principal: p}
+			continue
+		}
+		a.tokens = append(a.tokens, staticCredential{hash: sha256.Sum256([]byte(c.Token.String())), principal: p})
+	}
+	if cfg.OIDC.JWKSFile != "" {
+		v, err := newJWTValidator(cfg.OIDC, logger)
+		if err != nil {
+			return nil, err
+		}
+		a.jwt = v
+	}
+	return a, nil
+}
+
+// Authenticate returns the principal of the request
+// with the given headers.
+func (a *Authenticator) Authenticate(h http.Header) (*Principal, error) {
+	if !a.enabled {
+		return anonymous, nil
+	}
+	scheme, credentials, ok := strings.Cut(h.Get("Authorization"), " ")
+	if !ok {
+		return nil, ErrNoCredentials
+	}
+	switch strings.ToLower(scheme) {
+	case "bearer":
+		return a.authenticateToken(strings.TrimSpace(credentials))
+	case "basic":
+		return a.authenticateUser(strings.TrimSpace(credentials))
+	default:
+		return nil, fmt.Errorf("%w: unsupported authorization scheme %q", ErrInvalidCredentials, scheme)
+	}
+}
+
+func (a *Authenticator) authenticateToken(token string) (*Principal, error) {
+	hash := sha256.Sum256([]byte(token))
+	var principal *Principal
+	for _, c := range a.tokens {
+		// All the tokens are compared, so that the time
+		// does not depend on the position of the token.
+		if subtle.ConstantTimeCompare(hash[:], c.hash[:]) == 1 {
+			principal = c.principal
+		}
+	}
+	if principal != nil {
+		return principal, nil
+	}
+	// Static tokens are opaque, anything else is expected to be a JWT.
+	if a.jwt != nil && strings.Count(token, ".") == 2 {
+		return a.jwt.validate(token)
+	}
+	return nil, ErrInvalidCredentials
+}
+
+func (a *Authenticator) authenticateUser(credentials string) (*Principal, error) {
+	decoded, err := base64.StdEncoding.DecodeString(credentials)
+	if err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	username, password, ok := strings.Cut(string(decoded), ":")
+	if !ok {
+		return nil, ErrInvalidCredentials
+	}
+	c, ok := a.users[username]
+	hash := sha256.Sum256([]byte(password))
+	if subtle.ConstantTimeCompare(hash[:], c.hash[:]) != 1 || !ok {
+		return nil, ErrInvalidCredentials
+	}
+	return c.principal, nil
+}
+
+// authorize authenticates the request and checks whether the principal
+// is granted the role in all the tenants of the request. The returned
+// context carries the principal and the tenant ID.
+//
+// If the request does not specify the tenant, the request is not rejected
+// to let the handlers not bound to a tenant serve it: those bound to a
+// tenant fail to resolve it from the context.
+func (a *Authenticator) authorize(ctx context.Context, h http.Header, role Role) (context.Context, error) {
+	p, err := a.Authenticate(h)
+	if err != nil {
+		return ctx, connect.NewError(connect.CodeUnauthenticated, err)
+	}
+	ctx = InjectPrincipal(ctx, p)
+	if !p.HasRole(role) {
+		return ctx, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("%s does not have the %s role", p.Name, role))
+	}
+
+	orgID := tenant.DefaultTenantID
+	if a.multitenancy {
+		if orgID = h.Get(user.OrgIDHeaderName); orgID == "" {
+			if orgID = p.defaultTenant; orgID == "" {
+				return ctx, nil
+			}
+		}
+	}
+	tenantIDs, err := dskittenant.TenantIDsFromOrgID(orgID)
+	if err != nil {
+		return ctx, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	for _, tenantID := range tenantIDs {
+		if !a.allowed.IsAllowed(tenantID) {
+			return ctx, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("tenant %s is not allowed", tenantID))
+		}
+		if !p.CanAccess(tenantID) {
+			return ctx, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("%s is not allowed to access tenant %s", p.Name, tenantID))
+		}
+	}
+	return tenant.InjectTenantID(ctx, orgID), nil
+}
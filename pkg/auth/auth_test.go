@@ -0,0 +1,325 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/flagext"
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	settingsv1 "github.com/grafana/pyroscope/api/gen/proto/go/settings/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/settings/v1/settingsv1connect"
+	"github.com/grafana/pyroscope/pkg/tenant"
+)
+
+func testConfig() Config {
+	return Config{
+		Credentials: []Credential{
+			{Name: "reader", Token: flagext.SecretWithValue("read-token"), Tenants: []string{"a", "b"}, Roles: []Role{RoleRead}},
+			{Name: "agent", Token: flagext.SecretWithValue("write-token"), Tenants: []string{"a"}, Roles: []Role{RoleWrite}},
+			{Username: "admin", Password: flagext.SecretWithValue("secret"), Tenants: []string{AnyTenant}, Roles: []Role{RoleAdmin}},
+		},
+		InternalToken: flagext.SecretWithValue("internal-token"),
+	}
+}
+
+func Test_Config_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		cred Credential
+		err  string
+	}{
+		{name: "no secret", cred: Credential{Tenants: []string{"a"}, Roles: []Role{RoleRead}}, err: "either token or username must be specified"},
+		{name: "no password", cred: Credential{Username: "u", Tenants: []string{"a"}, Roles: []Role{RoleRead}}, err: "password must be specified"},
+		{name: "no tenants", cred: Credential{Token: flagext.SecretWithValue("t"), Roles: []Role{RoleRead}}, err: "no tenants specified"},
+		{name: "unknown role", cred: Credential{Token: flagext.SecretWithValue("t"), Tenants: []string{"a"}, Roles: []Role{"owner"}}, err: `unknown role "owner"`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := Config{Credentials: []Credential{tc.cred}}
+			assert.ErrorContains(t, cfg.Validate(), tc.err)
+		})
+	}
+	cfg := testConfig()
+	assert.NoError(t, cfg.Validate())
+	cfg.InternalToken = flagext.Secret{}
+	assert.ErrorContains(t, cfg.Validate(), "internal token must be specified")
+}
+
+func Test_Authenticator_authorize(t *testing.T) {
+	a, err := New(testConfig(), true, log.NewNopLogger())
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name          string
+		authorization string
+		orgID         string
+		role          Role
+		code          connect.Code
+		tenantID      string
+	}{
+		{name: "no credentials", orgID: "a", role: RoleRead, code: connect.CodeUnauthenticated},
+		{name: "invalid token", authorization: "Bearer invalid", orgID: "a", role: RoleRead, code: connect.CodeUnauthenticated},
+		{name: "unsupported scheme", authorization: "Digest foo", orgID: "a", role: RoleRead, code: connect.CodeUnauthenticated},
+		{name: "read", authorization: "Bearer read-token", orgID: "a", role: RoleRead, tenantID: "a"},
+		{name: "read other tenant", authorization: "Bearer read-token", orgID: "c", role: RoleRead, code: connect.CodePermissionDenied},
+		// Multiple tenants are not resolved to a single one.
+		{name: "read multiple tenants", authorization: "Bearer read-token", orgID: "a|b", role: RoleRead},
+		{name: "read multiple tenants denied", authorization: "Bearer read-token", orgID: "a|c", role: RoleRead, code: connect.CodePermissionDenied},
+		{name: "write with read token", authorization: "Bearer read-token", orgID: "a", role: RoleWrite, code: connect.CodePermissionDenied},
+		{name: "write", authorization: "Bearer write-token", orgID: "a", role: RoleWrite, tenantID: "a"},
+		{name: "read with write token", authorization: "Bearer write-token", orgID: "a", role: RoleRead, code: connect.CodePermissionDenied},
+		{name: "default tenant", authorization: "Bearer write-token", role: RoleWrite, tenantID: "a"},
+		{name: "no tenant", authorization: "Bearer read-token", role: RoleRead},
+		{name: "invalid tenant", authorization: "Bearer read-token", orgID: "../a", role: RoleRead, code: connect.CodeInvalidArgument},
+		{name: "basic auth", authorization: "Basic YWRtaW46c2VjcmV0", orgID: "c", role: RoleWrite, tenantID: "c"},
+		{name: "basic auth admin", authorization: "Basic YWRtaW46c2VjcmV0", orgID: "c", role: RoleAdmin, tenantID: "c"},
+		{name: "basic auth invalid password", authorization: "Basic YWRtaW46Zm9v", orgID: "c", role: RoleRead, code: connect.CodeUnauthenticated},
+		{name: "basic auth unknown user", authorization: "Basic Zm9vOnNlY3JldA==", orgID: "c", role: RoleRead, code: connect.CodeUnauthenticated},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			h := http.Header{}
+			if tc.authorization != "" {
+				h.Set("Authorization", tc.authorization)
+			}
+			if tc.orgID != "" {
+				h.Set("X-Scope-OrgID", tc.orgID)
+			}
+			ctx, err := a.authorize(context.Background(), h, tc.role)
+			if tc.code != 0 {
+				require.Error(t, err)
+				assert.Equal(t, tc.code, connect.CodeOf(err))
+				return
+			}
+			require.NoError(t, err)
+			_, ok := PrincipalFromContext(ctx)
+			assert.True(t, ok)
+			tenantID, _ := tenant.ExtractTenantIDFromContext(ctx)
+			assert.Equal(t, tc.tenantID, tenantID)
+		})
+	}
+}
+
+func Test_Authenticator_AllowedTenants(t *testing.T) {
+	cfg := Config{DisabledTenants: []string{"disabled"}}
+	a, err := New(cfg, true, log.NewNopLogger())
+	require.NoError(t, err)
+
+	// Authentication is disabled: any request is allowed,
+	// unless the tenant is disabled.
+	ctx, err := a.authorize(context.Background(), http.Header{"X-Scope-Orgid": []string{"a"}}, RoleAdmin)
+	require.NoError(t, err)
+	tenantID, err := tenant.ExtractTenantIDFromContext(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "a", tenantID)
+
+	_, err = a.authorize(context.Background(), http.Header{"X-Scope-Orgid": []string{"disabled"}}, RoleRead)
+	assert.Equal(t, connect.CodePermissionDenied, connect.CodeOf(err))
+}
+
+func Test_Authenticator_MultitenancyDisabled(t *testing.T) {
+	cfg := testConfig()
+	cfg.Credentials[0].Tenants = []string{tenant.DefaultTenantID}
+	a, err := New(cfg, false, log.NewNopLogger())
+	require.NoError(t, err)
+
+	h := http.Header{"Authorization": []string{"Bearer read-token"}, "X-Scope-Orgid": []string{"a"}}
+	ctx, err := a.authorize(context.Background(), h, RoleRead)
+	require.NoError(t, err)
+	tenantID, err := tenant.ExtractTenantIDFromContext(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, tenant.DefaultTenantID, tenantID)
+
+	h.Set("Authorization", "Bearer write-token")
+	_, err = a.authorize(context.Background(), h, RoleWrite)
+	assert.Equal(t, connect.CodePermissionDenied, connect.CodeOf(err))
+}
+
+func Test_HTTPMiddleware(t *testing.T) {
+	a, err := New(testConfig(), true, log.NewNopLogger())
+	require.NoError(t, err)
+	handler := a.HTTPMiddleware().Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		orgID, err := user.ExtractOrgID(r.Context())
+		require.NoError(t, err)
+		_, _ = w.Write([]byte(orgID))
+	}))
+
+	for _, tc := range []struct {
+		name          string
+		method        string
+		authorization string
+		orgID         string
+		status        int
+	}{
+		{name: "render", method: http.MethodGet, authorization: "Bearer read-token", orgID: "b", status: http.StatusOK},
+		{name: "ingest", method: http.MethodPost, authorization: "Bearer write-token", status: http.StatusOK},
+		{name: "render multiple tenants", method: http.MethodGet, authorization: "Bearer read-token", orgID: "a|b", status: http.StatusOK},
+		{name: "ingest with read token", method: http.MethodPost, authorization: "Bearer read-token", orgID: "a", status: http.StatusForbidden},
+		{name: "no credentials", method: http.MethodGet, orgID: "a", status: http.StatusUnauthorized},
+		{name: "no tenant", method: http.MethodGet, authorization: "Bearer read-token", status: http.StatusUnauthorized},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, "/ingest", nil)
+			if tc.authorization != "" {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+			if tc.orgID != "" {
+				req.Header.Set("X-Scope-OrgID", tc.orgID)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			assert.Equal(t, tc.status, rec.Code, rec.Body.String())
+			if tc.status == http.StatusUnauthorized {
+				assert.Equal(t, `Basic realm="pyroscope"`, rec.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func Test_AdminHTTPMiddleware(t *testing.T) {
+	a, err := New(testConfig(), true, log.NewNopLogger())
+	require.NoError(t, err)
+	handler := a.AdminHTTPMiddleware().Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, ok := PrincipalFromContext(r.Context())
+		require.True(t, ok)
+		_, _ = w.Write([]byte(p.Name))
+	}))
+
+	for _, tc := range []struct {
+		name          string
+		authorization string
+		status        int
+	}{
+		{name: "admin", authorization: "Basic YWRtaW46c2VjcmV0", status: http.StatusOK},
+		{name: "write", authorization: "Bearer write-token", status: http.StatusForbidden},
+		{name: "no credentials", status: http.StatusUnauthorized},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/ops/overrides/a", nil)
+			if tc.authorization != "" {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			assert.Equal(t, tc.status, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+type settingsHandler struct {
+	settingsv1connect.UnimplementedSettingsServiceHandler
+}
+
+func (settingsHandler) Get(ctx context.Context, _ *connect.Request[settingsv1.GetSettingsRequest]) (*connect.Response[settingsv1.GetSettingsResponse], error) {
+	tenantID, err := tenant.ExtractTenantIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&settingsv1.GetSettingsResponse{
+		Settings: []*settingsv1.Setting{{Name: "tenant", Value: tenantID}},
+	}), nil
+}
+
+func (settingsHandler) Set(context.Context, *connect.Request[settingsv1.SetSettingsRequest]) (*connect.Response[settingsv1.SetSettingsResponse], error) {
+	return connect.NewResponse(&settingsv1.SetSettingsResponse{}), nil
+}
+
+func Test_Interceptor(t *testing.T) {
+	a, err := New(testConfig(), true, log.NewNopLogger())
+	require.NoError(t, err)
+	mux := http.NewServeMux()
+	mux.Handle(settingsv1connect.NewSettingsServiceHandler(settingsHandler{}, connect.WithInterceptors(a.Interceptor())))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	client := settingsv1connect.NewSettingsServiceClient(server.Client(), server.URL)
+
+	newRequest := func(token, orgID string) *connect.Request[settingsv1.GetSettingsRequest] {
+		req := connect.NewRequest(&settingsv1.GetSettingsRequest{})
+		req.Header().Set("Authorization", "Bearer "+token)
+		if orgID != "" {
+			req.Header().Set("X-Scope-OrgID", orgID)
+		}
+		return req
+	}
+
+	resp, err := client.Get(context.Background(), newRequest("read-token", "b"))
+	require.NoError(t, err)
+	assert.Equal(t, "b", resp.Msg.Settings[0].Value)
+
+	_, err = client.Get(context.Background(), newRequest("invalid", "b"))
+	assert.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+
+	_, err = client.Get(context.Background(), newRequest("read-token", "c"))
+	assert.Equal(t, connect.CodePermissionDenied, connect.CodeOf(err))
+
+	// The handler requires a tenant.
+	_, err = client.Get(context.Background(), newRequest("read-token", ""))
+	assert.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+
+	// Settings can only be modified with the write role.
+	setReq := connect.NewRequest(&settingsv1.SetSettingsRequest{})
+	setReq.Header().Set("Authorization", "Bearer read-token")
+	setReq.Header().Set("X-Scope-OrgID", "a")
+	_, err = client.Set(context.Background(), setReq)
+	assert.Equal(t, connect.CodePermissionDenied, connect.CodeOf(err))
+
+	setReq.Header().Set("Authorization", "Bearer write-token")
+	_, err = client.Set(context.Background(), setReq)
+	assert.NoError(t, err)
+}
+
+func Test_InternalInterceptor(t *testing.T) {
+	a, err := New(testConfig(), true, log.NewNopLogger())
+	require.NoError(t, err)
+	mux := http.NewServeMux()
+	mux.Handle(settingsv1connect.NewSettingsServiceHandler(settingsHandler{}, connect.WithInterceptors(a.InternalInterceptor())))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	newRequest := func() *connect.Request[settingsv1.GetSettingsRequest] {
+		req := connect.NewRequest(&settingsv1.GetSettingsRequest{})
+		req.Header().Set("X-Scope-OrgID", "b")
+		return req
+	}
+
+	// A call with the tenant ID only, without the internal token, is rejected.
+	client := settingsv1connect.NewSettingsServiceClient(server.Client(), server.URL)
+	_, err = client.Get(context.Background(), newRequest())
+	assert.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+
+	// So are user credentials.
+	req := newRequest()
+	req.Header().Set("Authorization", "Bearer read-token")
+	_, err = client.Get(context.Background(), req)
+	assert.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+
+	req = newRequest()
+	req.Header().Set(InternalTokenHeader, "invalid")
+	_, err = client.Get(context.Background(), req)
+	assert.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+
+	// The clients of the internal services send the token,
+	// and forward the tenant ID from the context.
+	client = settingsv1connect.NewSettingsServiceClient(server.Client(), server.URL, connect.WithInterceptors(a.InternalInterceptor()))
+	ctx := tenant.InjectTenantID(context.Background(), "b")
+	resp, err := client.Get(ctx, connect.NewRequest(&settingsv1.GetSettingsRequest{}))
+	require.NoError(t, err)
+	assert.Equal(t, "b", resp.Msg.Settings[0].Value)
+
+	// The token is not required if authentication is disabled.
+	a, err = New(Config{}, true, log.NewNopLogger())
+	require.NoError(t, err)
+	mux = http.NewServeMux()
+	mux.Handle(settingsv1connect.NewSettingsServiceHandler(settingsHandler{}, connect.WithInterceptors(a.InternalInterceptor())))
+	server = httptest.NewServer(mux)
+	defer server.Close()
+	client = settingsv1connect.NewSettingsServiceClient(server.Client(), server.URL)
+	resp, err = client.Get(context.Background(), newRequest())
+	require.NoError(t, err)
+	assert.Equal(t, "b", resp.Msg.Settings[0].Value)
+}
@@ -0,0 +1,144 @@
+package auth
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/grafana/dskit/flagext"
+)
+
+// Role is a set of operations a principal is allowed to perform.
+type Role string
+
+const (
+	// RoleRead allows querying profiles and reading tenant data.
+	RoleRead Role = "read"
+	// RoleWrite allows ingesting profiles and modifying tenant data.
+	RoleWrite Role = "write"
+	// RoleAdmin grants all the roles, including the administrative APIs.
+	RoleAdmin Role = "admin"
+)
+
+// AnyTenant grants access to all the tenants.
+const AnyTenant = "*"
+
+func (r Role) valid() bool {
+	switch r {
+	case RoleRead, RoleWrite, RoleAdmin:
+		return true
+	}
+	return false
+}
+
+type Config struct {
+	EnabledTenants  flagext.StringSliceCSV `yaml:"enabled_tenants"`
+	DisabledTenants flagext.StringSliceCSV `yaml:"disabled_tenants"`
+
+	Credentials []Credential `yaml:"credentials" doc:"description=Static credentials: bearer tokens or basic auth users, mapped to the tenants and roles they are allowed to access. Use '*' to allow access to all tenants."`
+	OIDC        OIDCConfig   `yaml:"oidc"`
+
+	InternalToken flagext.Secret `yaml:"internal_token"`
+}
+
+// Credential is a static bearer token or a basic auth user.
+type Credential struct {
+	Name     string         `yaml:"name" doc:"description=Name of the principal, used in logs."`
+	Token    flagext.Secret `yaml:"token" doc:"description=Bearer token."`
+	Username string         `yaml:"username" doc:"description=Basic auth username."`
+	Password flagext.Secret `yaml:"password" doc:"description=Basic auth password."`
+	Tenants  []string       `yaml:"tenants" doc:"description=Tenants the credential grants access to."`
+	Roles    []Role         `yaml:"roles" doc:"description=Roles granted: read, write or admin."`
+}
+
+type OIDCConfig struct {
+	JWKSFile      string            `yaml:"jwks_file"`
+	Issuer        string            `yaml:"issuer"`
+	Audience      string            `yaml:"audience"`
+	TenantsClaim  string            `yaml:"tenants_claim"`
+	TenantMapping map[string]string `yaml:"tenant_mapping" doc:"description=Maps the values of the tenants claim to tenant IDs. If set, values that are not mapped are ignored."`
+	RolesClaim    string            `yaml:"roles_claim"`
+	RoleMapping   map[string]string `yaml:"role_mapping" doc:"description=Maps the values of the roles claim to roles. If set, values that are not mapped are ignored."`
+	ClockSkew     time.Duration     `yaml:"clock_skew"`
+}
+
+func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
+	f.Var(&cfg.EnabledTenants, "auth.enabled-tenants", "Comma separated list of tenants that are allowed to access the API. If specified, only these tenants are allowed, otherwise all tenants are allowed.")
+	f.Var(&cfg.DisabledTenants, "auth.disabled-tenants", "Comma separated list of tenants that are not allowed to access the API. If specified, and the API would normally allow the tenant, the tenant is rejected.")
+	f.Var(&cfg.InternalToken, "auth.internal-token", "Shared secret the components authenticate the requests they send to each other with, for example, queriers to ingesters. Required if authentication is enabled, and must be the same for all the components.")
+	cfg.OIDC.RegisterFlags(f)
+}
+
+func (cfg *OIDCConfig) RegisterFlags(f *flag.FlagSet) {
+	f.StringVar(&cfg.JWKSFile, "auth.oidc.jwks-file", "", "Path to the JSON Web Key Set file used to verify the signature of the JWT bearer tokens. If empty, JWT authentication is disabled.")
+	f.StringVar(&cfg.Issuer, "auth.oidc.issuer", "", "Expected issuer (iss claim) of the JWT. If empty, the issuer is not verified.")
+	f.StringVar(&cfg.Audience, "auth.oidc.audience", "", "Expected audience (aud claim) of the JWT. If empty, the audience is not verified.")
+	f.StringVar(&cfg.TenantsClaim, "auth.oidc.tenants-claim", "tenants", "Claim holding the tenants the JWT grants access to. Nested claims are separated with dots.")
+	f.StringVar(&cfg.RolesClaim, "auth.oidc.roles-claim", "roles", "Claim holding the roles the JWT grants. Nested claims are separated with dots.")
+	f.DurationVar(&cfg.ClockSkew, "auth.oidc.clock-skew", time.Minute, "Clock skew tolerated when validating the time based claims of the JWT.")
+}
+
+// Enabled reports whether authentication is required.
+func (cfg *Config) Enabled() bool {
+	return len(cfg.Credentials) > 0 || cfg.OIDC.JWKSFile != ""
+}
+
+func (cfg *Config) Validate() error {
+	for i, c := range cfg.Credentials {
+		if err := c.validate(); err != nil {
+			return fmt.Errorf("invalid auth credential %d: %w", i, err)
+		}
+	}
+	if err := cfg.OIDC.validate(); err != nil {
+		return err
+	}
+	if cfg.Enabled() && cfg.InternalToken.String() == "" {
+		return errors.New("auth internal token must be specified if authentication is enabled")
+	}
+	return nil
+}
+
+func (c *Credential) validate() error {
+	switch {
+	case c.Token.String() != "" && c.Username != "":
+		return errors.New("either token or username must be specified, not both")
+	case c.Token.String() == "" && c.Username == "":
+		return errors.New("either token or username must be specified")
+	case c.Username != "" && c.Password.String() == "":
+		return errors.New("password must be specified")
+	case len(c.Tenants) == 0:
+		return errors.New("no tenants specified")
+	}
+	return validateRoles(c.Roles)
+}
+
+func (cfg *OIDCConfig) validate() error {
+	if cfg.JWKSFile == "" {
+		return nil
+	}
+	if cfg.TenantsClaim == "" {
+		return errors.New("oidc tenants claim must be specified")
+	}
+	if cfg.RolesClaim == "" {
+		return errors.New("oidc roles claim must be specified")
+	}
+	for _, r := range cfg.RoleMapping {
+		if !Role(r).valid() {
+			return fmt.Errorf("oidc role mapping: unknown role %q", r)
+		}
+	}
+	return nil
+}
+
+func validateRoles(roles []Role) error {
+	if len(roles) == 0 {
+		return errors.New("no roles specified")
+	}
+	for _, r := range roles {
+		if !r.valid() {
+			return fmt.Errorf("unknown role %q", r)
+		}
+	}
+	return nil
+}
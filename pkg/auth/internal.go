@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+
+	"connectrpc.com/connect"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/grafana/pyroscope/pkg/tenant"
+)
+
+// InternalTokenHeader carries the token the components authenticate
+// the requests they send to each other with.
+const InternalTokenHeader = "X-Pyroscope-Internal-Token"
+
+var ErrInvalidInternalToken = errors.New("invalid internal token")
+
+// authenticateInternal checks the internal token of the request,
+// if authentication is enabled.
+func (a *Authenticator) authenticateInternal(h http.Header) error {
+	if !a.enabled {
+		return nil
+	}
+	hash := sha256.Sum256([]byte(h.Get(InternalTokenHeader)))
+	if subtle.ConstantTimeCompare(hash[:], a.internalTokenHash[:]) != 1 {
+		return connect.NewError(connect.CodeUnauthenticated, ErrInvalidInternalToken)
+	}
+	return nil
+}
+
+// InternalInterceptor returns a connect interceptor for the services
+// only called by other components, such as ingesters and store-gateways.
+// If authentication is enabled, the handlers reject the requests without
+// the internal token, and the clients send it. The tenant ID is handled
+// as by the tenant interceptor: the caller is trusted to have authorized
+// the access to the tenant.
+func (a *Authenticator) InternalInterceptor() connect.Interceptor {
+	return &internalInterceptor{
+		Authenticator: a,
+		tenant:        tenant.NewAuthInterceptor(a.multitenancy),
+	}
+}
+
+type internalInterceptor struct {
+	*Authenticator
+	tenant connect.Interceptor
+}
+
+func (i *internalInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	next = i.tenant.WrapUnary(next)
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			if i.enabled {
+				req.Header().Set(InternalTokenHeader, i.internalToken)
+			}
+			return next(ctx, req)
+		}
+		if err := i.authenticateInternal(req.Header()); err != nil {
+			return nil, err
+		}
+		return next(ctx, req)
+	}
+}
+
+func (i *internalInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	next = i.tenant.WrapStreamingClient(next)
+	return func(ctx context.Context, s connect.Spec) connect.StreamingClientConn {
+		conn := next(ctx, s)
+		if i.enabled {
+			conn.RequestHeader().Set(InternalTokenHeader, i.internalToken)
+		}
+		return conn
+	}
+}
+
+func (i *internalInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	next = i.tenant.WrapStreamingHandler(next)
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		if err := i.authenticateInternal(conn.RequestHeader()); err != nil {
+			return err
+		}
+		return next(ctx, conn)
+	}
+}
+
+// InternalGRPCClientInterceptor returns a gRPC client interceptor sending
+// the internal token, for the components calling the internal services
+// with gRPC clients.
+func (a *Authenticator) InternalGRPCClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if a.enabled {
+			ctx = metadata.AppendToOutgoingContext(ctx, InternalTokenHeader, a.internalToken)
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
@@ -0,0 +1,280 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwksReloadInterval limits how often the JWKS file is reloaded
+// when a token signed with an unknown key is presented.
+const jwksReloadInterval = time.Minute
+
+var validSigningMethods = []string{
+	"RS256", "RS384", "RS512",
+	"PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512",
+	"EdDSA",
+}
+
+// jwtValidator validates the JWTs issued by an OIDC provider, and maps
+// their claims to the tenants and roles of the principal.
+type jwtValidator struct {
+	cfg    OIDCConfig
+	logger log.Logger
+	parser *jwt.Parser
+
+	mu   sync.RWMutex
+	keys map[string]crypto.PublicKey
+	// loaded is the time of the last attempt to load the keys.
+	loaded time.Time
+}
+
+func newJWTValidator(cfg OIDCConfig, logger log.Logger) (*jwtValidator, error) {
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(validSigningMethods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(cfg.ClockSkew),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+	v := &jwtValidator{
+		cfg:    cfg,
+		logger: logger,
+		parser: jwt.NewParser(opts...),
+	}
+	if err := v.load(); err != nil {
+		return nil, err
+	}
+	v.loaded = time.Now()
+	return v, nil
+}
+
+// load reads the keys from the JWKS file.
+func (v *jwtValidator) load() error {
+	b, err := os.ReadFile(v.cfg.JWKSFile)
+	if err != nil {
+		return fmt.Errorf("reading JWKS file: %w", err)
+	}
+	keys, err := parseJWKS(b)
+	if err != nil {
+		return fmt.Errorf("parsing JWKS file %s: %w", v.cfg.JWKSFile, err)
+	}
+	v.mu.Lock()
+	v.keys = keys
+	v.mu.Unlock()
+	return nil
+}
+
+func (v *jwtValidator) key(kid string) (crypto.PublicKey, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	if kid == "" && len(v.keys) == 1 {
+		for _, k := range v.keys {
+			return k, true
+		}
+	}
+	k, ok := v.keys[kid]
+	return k, ok
+}
+
+func (v *jwtValidator) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if k, ok := v.key(kid); ok {
+		return k, nil
+	}
+	// The keys might have been rotated.
+	v.mu.Lock()
+	reload := time.Since(v.loaded) > jwksReloadInterval
+	if reload {
+		v.loaded = time.Now()
+	}
+	v.mu.Unlock()
+	if reload {
+		if err := v.load(); err != nil {
+			level.Warn(v.logger).Log("msg", "failed to reload JWKS file", "err", err)
+		}
+		if k, ok := v.key(kid); ok {
+			return k, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown key %q", kid)
+}
+
+func (v *jwtValidator) validate(token string) (*Principal, error) {
+	claims := jwt.MapClaims{}
+	if _, err := v.parser.ParseWithClaims(token, claims, v.keyFunc); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCredentials, err)
+	}
+	name, _ := claims.GetSubject()
+	tenants := mapClaimValues(claimValues(claims, v.cfg.TenantsClaim), v.cfg.TenantMapping)
+	var roles []Role
+	for _, r := range mapClaimValues(claimValues(claims, v.cfg.RolesClaim), v.cfg.RoleMapping) {
+		if role := Role(r); role.valid() {
+			roles = append(roles, role)
+		}
+	}
+	return newPrincipal(name, tenants, roles), nil
+}
+
+// claimValues returns the values of the claim. The claim is either a
+// string or a list of strings; nested claims are separated with dots.
+func claimValues(claims jwt.MapClaims, name string) []string {
+	var v interface{} = map[string]interface{}(claims)
+	for _, key := range strings.Split(name, ".") {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		v = m[key]
+	}
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, x := range v {
+			if s, ok := x.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+func mapClaimValues(values []string, mapping map[string]string) []string {
+	if len(mapping) == 0 {
+		return values
+	}
+	mapped := make([]string, 0, len(values))
+	for _, v := range values {
+		if m, ok := mapping[v]; ok {
+			mapped = append(mapped, m)
+		}
+	}
+	return mapped
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// parseJWKS parses the public signing keys of the JSON Web Key Set.
+func parseJWKS(b []byte) (map[string]crypto.PublicKey, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(b, &set); err != nil {
+		return nil, err
+	}
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", k.Kid, err)
+		}
+		keys[k.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no signing keys found")
+	}
+	return keys, nil
+}
+
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBase64URL(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBase64URL(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBase64URL(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBase64URL(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		key := &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}
+		if !curve.IsOnCurve(key.X, key.Y) {
+			return nil, errors.New("invalid point")
+		}
+		return key, nil
+
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBase64URL(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid key size")
+		}
+		return ed25519.PublicKey(x), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBase64URL(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
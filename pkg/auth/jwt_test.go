@@ -0,0 +1,238 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/grafana/dskit/flagext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testKey struct {
+	kid     string
+	method  jwt.SigningMethod
+	private crypto.Signer
+}
+
+func newTestKeys(t *testing.T) []testKey {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return []testKey{
+		{kid: "rsa", method: jwt.SigningMethodRS256, private: rsaKey},
+		{kid: "ec", method: jwt.SigningMethodES256, private: ecKey},
+		{kid: "ed", method: jwt.SigningMethodEdDSA, private: edKey},
+	}
+}
+
+func (k testKey) jwk() map[string]string {
+	enc := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+	switch pub := k.private.Public().(type) {
+	case *rsa.PublicKey:
+		return map[string]string{"kty": "RSA", "kid": k.kid, "use": "sig", "n": enc(pub.N.Bytes()), "e": enc(big.NewInt(int64(pub.E)).Bytes())}
+	case *ecdsa.PublicKey:
+		return map[string]string{"kty": "EC", "kid": k.kid, "crv": "P-256", "x": enc(pub.X.FillBytes(make([]byte, 32))), "y": enc(pub.Y.FillBytes(make([]byte, 32)))}
+	case ed25519.PublicKey:
+		return map[string]string{"kty": "OKP", "kid": k.kid, "crv": "Ed25519", "x": enc(pub)}
+	}
+	panic("unsupported key")
+}
+
+func (k testKey) sign(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(k.method, claims)
+	token.Header["kid"] = k.kid
+	s, err := token.SignedString(k.private)
+	require.NoError(t, err)
+	return s
+}
+
+func writeJWKS(t *testing.T, path string, keys ...testKey) {
+	t.Helper()
+	set := struct {
+		Keys []map[string]string `json:"keys"`
+	}{}
+	for _, k := range keys {
+		set.Keys = append(set.Keys, k.jwk())
+	}
+	b, err := json.Marshal(set)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, b, 0o644))
+}
+
+func testOIDCConfig(jwksFile string) Config {
+	return Config{
+		OIDC: OIDCConfig{
+			JWKSFile:     jwksFile,
+			Issuer:       "https://issuer.example.com",
+			Audience:     "pyroscope",
+			TenantsClaim: "tenants",
+			RolesClaim:   "realm_access.roles",
+			RoleMapping:  map[string]string{"profiles-reader": "read", "profiles-admin": "admin"},
+		},
+		InternalToken: flagext.SecretWithValue("internal-token"),
+	}
+}
+
+func Test_JWT(t *testing.T) {
+	keys := newTestKeys(t)
+	jwksFile := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, jwksFile, keys...)
+	a, err := New(testOIDCConfig(jwksFile), true, log.NewNopLogger())
+	require.NoError(t, err)
+
+	claims := func(overrides jwt.MapClaims) jwt.MapClaims {
+		c := jwt.MapClaims{
+			"iss":     "https://issuer.example.com",
+			"aud":     "pyroscope",
+			"sub":     "john",
+			"exp":     time.Now().Add(time.Hour).Unix(),
+			"tenants": []string{"a", "b"},
+			"realm_access": map[string]interface{}{
+				"roles": []string{"profiles-reader", "unknown"},
+			},
+		}
+		for k, v := range overrides {
+			c[k] = v
+		}
+		return c
+	}
+	authenticate := func(token string) (*Principal, error) {
+		return a.Authenticate(http.Header{"Authorization": []string{"Bearer " + token}})
+	}
+
+	for _, k := range keys {
+		t.Run(k.kid, func(t *testing.T) {
+			p, err := authenticate(k.sign(t, claims(nil)))
+			require.NoError(t, err)
+			assert.Equal(t, "john", p.Name)
+			assert.True(t, p.CanAccess("a"))
+			assert.True(t, p.CanAccess("b"))
+			assert.False(t, p.CanAccess("c"))
+			assert.True(t, p.HasRole(RoleRead))
+			assert.False(t, p.HasRole(RoleWrite))
+		})
+	}
+
+	key := keys[0]
+	for _, tc := range []struct {
+		name   string
+		claims jwt.MapClaims
+	}{
+		{name: "expired", claims: claims(jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()})},
+		{name: "no expiration", claims: claims(jwt.MapClaims{"exp": nil})},
+		{name: "wrong issuer", claims: claims(jwt.MapClaims{"iss": "https://other.example.com"})},
+		{name: "wrong audience", claims: claims(jwt.MapClaims{"aud": "other"})},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := authenticate(key.sign(t, tc.claims))
+			assert.ErrorIs(t, err, ErrInvalidCredentials)
+		})
+	}
+
+	t.Run("unknown key", func(t *testing.T) {
+		other := newTestKeys(t)[0]
+		_, err := authenticate(other.sign(t, claims(nil)))
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+	})
+
+	t.Run("unsigned", func(t *testing.T) {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodNone, claims(nil)).SignedString(jwt.UnsafeAllowNoneSignatureType)
+		require.NoError(t, err)
+		_, err = authenticate(token)
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+	})
+
+	t.Run("single tenant", func(t *testing.T) {
+		p, err := authenticate(key.sign(t, claims(jwt.MapClaims{
+			"tenants":      "a",
+			"realm_access": map[string]interface{}{"roles": "profiles-admin"},
+		})))
+		require.NoError(t, err)
+		assert.Equal(t, "a", p.defaultTenant)
+		assert.True(t, p.HasRole(RoleWrite))
+	})
+}
+
+func Test_JWT_TenantMapping(t *testing.T) {
+	key := newTestKeys(t)[1]
+	jwksFile := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, jwksFile, key)
+	cfg := testOIDCConfig(jwksFile)
+	cfg.OIDC.TenantsClaim = "groups"
+	cfg.OIDC.TenantMapping = map[string]string{"team-a": "a"}
+	a, err := New(cfg, true, log.NewNopLogger())
+	require.NoError(t, err)
+
+	p, err := a.Authenticate(http.Header{"Authorization": []string{"Bearer " + key.sign(t, jwt.MapClaims{
+		"iss":          "https://issuer.example.com",
+		"aud":          []string{"pyroscope", "grafana"},
+		"exp":          time.Now().Add(time.Hour).Unix(),
+		"groups":       []string{"team-a", "team-b"},
+		"realm_access": map[string]interface{}{"roles": []string{"profiles-reader"}},
+	})}})
+	require.NoError(t, err)
+	assert.True(t, p.CanAccess("a"))
+	assert.False(t, p.CanAccess("team-a"))
+	assert.False(t, p.CanAccess("team-b"))
+}
+
+func Test_JWT_KeyRotation(t *testing.T) {
+	keys := newTestKeys(t)
+	jwksFile := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, jwksFile, keys[0])
+	a, err := New(testOIDCConfig(jwksFile), true, log.NewNopLogger())
+	require.NoError(t, err)
+
+	token := keys[1].sign(t, jwt.MapClaims{
+		"iss":          "https://issuer.example.com",
+		"aud":          "pyroscope",
+		"exp":          time.Now().Add(time.Hour).Unix(),
+		"tenants":      "a",
+		"realm_access": map[string]interface{}{"roles": "profiles-reader"},
+	})
+	writeJWKS(t, jwksFile, keys[0], keys[1])
+	h := http.Header{"Authorization": []string{"Bearer " + token}}
+
+	// The file is not reloaded more often than once per jwksReloadInterval.
+	_, err = a.Authenticate(h)
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+	a.jwt.loaded = time.Now().Add(-jwksReloadInterval - time.Second)
+	_, err = a.Authenticate(h)
+	assert.NoError(t, err)
+}
+
+func Test_parseJWKS(t *testing.T) {
+	_, err := parseJWKS([]byte(`{"keys":[]}`))
+	assert.EqualError(t, err, "no signing keys found")
+
+	_, err = parseJWKS([]byte(`{"keys":[{"kty":"oct","kid":"k","k":"c2VjcmV0"}]}`))
+	assert.EqualError(t, err, `key "k": unsupported key type "oct"`)
+
+	// Encryption keys are ignored.
+	_, err = parseJWKS([]byte(`{"keys":[{"kty":"RSA","kid":"enc","use":"enc"}]}`))
+	assert.EqualError(t, err, "no signing keys found")
+
+	_, err = parseJWKS([]byte(`{"keys":[{"kty":"EC","kid":"ec","crv":"P-256","x":"AQ","y":"AQ"}]}`))
+	assert.EqualError(t, err, `key "ec": invalid point`)
+}
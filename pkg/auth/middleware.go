@@ -0,0 +1,166 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"connectrpc.com/connect"
+	"github.com/grafana/dskit/middleware"
+	"github.com/grafana/dskit/user"
+
+	"github.com/grafana/pyroscope/api/gen/proto/go/adhocprofiles/v1/adhocprofilesv1connect"
+	"github.com/grafana/pyroscope/api/gen/proto/go/annotations/v1/annotationsv1connect"
+	"github.com/grafana/pyroscope/api/gen/proto/go/push/v1/pushv1connect"
+	"github.com/grafana/pyroscope/api/gen/proto/go/settings/v1/settingsv1connect"
+	"github.com/grafana/pyroscope/pkg/tenant"
+	"github.com/grafana/pyroscope/pkg/util/connectgrpc"
+	httputil "github.com/grafana/pyroscope/pkg/util/http"
+)
+
+// procedureRoles are the roles required by the procedures that modify
+// tenant data. Other procedures require the read role.
+var procedureRoles = map[string]Role{
+	pushv1connect.PusherServicePushProcedure:                  RoleWrite,
+	settingsv1connect.SettingsServiceSetProcedure:             RoleWrite,
+	annotationsv1connect.AnnotationServiceCreateProcedure:     RoleWrite,
+	annotationsv1connect.AnnotationServiceDeleteProcedure:     RoleWrite,
+	adhocprofilesv1connect.AdHocProfileServiceUploadProcedure: RoleWrite,
+	adhocprofilesv1connect.AdHocProfileServiceUpdateProcedure: RoleWrite,
+	adhocprofilesv1connect.AdHocProfileServiceDeleteProcedure: RoleWrite,
+	adhocprofilesv1connect.AdHocProfileServiceIngestProcedure: RoleWrite,
+}
+
+// ProcedureRole returns the role required to call the procedure.
+func ProcedureRole(procedure string) Role {
+	if role, ok := procedureRoles[procedure]; ok {
+		return role
+	}
+	return RoleRead
+}
+
+// HTTPRole returns the role required by the HTTP request:
+// requests that may modify data require the write role.
+func HTTPRole(r *http.Request) Role {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return RoleRead
+	default:
+		return RoleWrite
+	}
+}
+
+// HTTPMiddleware authenticates the requests and injects the principal
+// and the tenant ID into the request context.
+func (a *Authenticator) HTTPMiddleware() middleware.Interface {
+	return a.httpMiddleware(HTTPRole)
+}
+
+// HTTPMiddlewareWithRole is like HTTPMiddleware, but the role required
+// is the given one, instead of the one determined by HTTPRole.
+func (a *Authenticator) HTTPMiddlewareWithRole(role Role) middleware.Interface {
+	return a.httpMiddleware(func(*http.Request) Role { return role })
+}
+
+func (a *Authenticator) httpMiddleware(requiredRole func(*http.Request) Role) middleware.Interface {
+	return middleware.Func(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, err := a.authorize(r.Context(), r.Header, requiredRole(r))
+			if err == nil {
+				if _, err = user.ExtractOrgID(ctx); err != nil {
+					err = connect.NewError(connect.CodeUnauthenticated, err)
+				}
+			}
+			if err != nil {
+				a.writeError(w, err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+}
+
+// AdminHTTPMiddleware authenticates the requests to the administrative
+// endpoints, which require the admin role, and injects the principal
+// into the request context. The tenant is not resolved: the endpoints
+// are expected to check the access to the tenants they operate on.
+func (a *Authenticator) AdminHTTPMiddleware() middleware.Interface {
+	return middleware.Func(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			p, err := a.Authenticate(r.Header)
+			if err != nil {
+				a.writeError(w, connect.NewError(connect.CodeUnauthenticated, err))
+				return
+			}
+			if !p.HasRole(RoleAdmin) {
+				a.writeError(w, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("%s does not have the %s role", p.Name, RoleAdmin)))
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(InjectPrincipal(r.Context(), p)))
+		})
+	})
+}
+
+func (a *Authenticator) writeError(w http.ResponseWriter, err error) {
+	code := connect.CodeOf(err)
+	if code == connect.CodeUnauthenticated && len(a.users) > 0 {
+		w.Header().Set("WWW-Authenticate", `Basic realm="pyroscope"`)
+	}
+	var connectErr *connect.Error
+	if errors.As(err, &connectErr) {
+		err = errors.New(connectErr.Message())
+	}
+	httputil.ErrorWithStatus(w, err, int(connectgrpc.CodeToHTTP(code)))
+}
+
+// Interceptor returns a connect interceptor that authenticates the
+// requests to the handlers and injects the principal and the tenant
+// ID into the request context. For clients, the tenant ID is forwarded
+// from the context, as the tenant interceptor does.
+func (a *Authenticator) Interceptor() connect.Interceptor {
+	return &interceptor{
+		Authenticator: a,
+		client:        tenant.NewAuthInterceptor(a.multitenancy),
+	}
+}
+
+type interceptor struct {
+	*Authenticator
+	client connect.Interceptor
+}
+
+func (i *interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	clientNext := i.client.WrapUnary(next)
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			return clientNext(ctx, req)
+		}
+		ctx, err := i.authorize(ctx, req.Header(), ProcedureRole(req.Spec().Procedure))
+		if err != nil {
+			return nil, err
+		}
+		resp, err := next(ctx, req)
+		if err != nil && errors.Is(err, tenant.ErrNoTenantID) {
+			return resp, connect.NewError(connect.CodeUnauthenticated, err)
+		}
+		return resp, err
+	}
+}
+
+func (i *interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return i.client.WrapStreamingClient(next)
+}
+
+func (i *interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		ctx, err := i.authorize(ctx, conn.RequestHeader(), ProcedureRole(conn.Spec().Procedure))
+		if err != nil {
+			return err
+		}
+		if err = next(ctx, conn); err != nil && errors.Is(err, tenant.ErrNoTenantID) {
+			return connect.NewError(connect.CodeUnauthenticated, err)
+		}
+		return err
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"runtime/debug"
 	"sync"
 	"time"
 
@@ -22,6 +21,8 @@ import (
 	"github.com/grafana/pyroscope/pkg/experiment/metastore/client"
 	"github.com/grafana/pyroscope/pkg/experiment/query_backend/block"
 	"github.com/grafana/pyroscope/pkg/objstore"
+	"github.com/grafana/pyroscope/pkg/util"
+	diskutil "github.com/grafana/pyroscope/pkg/util/disk"
 )
 
 type Worker struct {
@@ -32,6 +33,7 @@ type Worker struct {
 	metastoreClient *metastoreclient.Client
 	storage         objstore.Bucket
 	metrics         *compactionWorkerMetrics
+	volumeChecker   diskutil.VolumeChecker
 
 	jobMutex      sync.RWMutex
 	pendingJobs   map[string]*compactorv1.CompactionJob
@@ -39,13 +41,26 @@ type Worker struct {
 	completedJobs map[string]*compactorv1.CompactionJobStatus
 
 	queue chan *compactorv1.CompactionJob
+	wg    sync.WaitGroup
+	// stop is closed when the worker is stopping:
+	// no new jobs are started after that.
+	stop chan struct{}
+	// Jobs are not bound to the service context, so that
+	// they can be completed after the worker is stopped.
+	jobsCtx    context.Context
+	cancelJobs context.CancelFunc
 }
 
 type Config struct {
 	JobCapacity     int           `yaml:"job_capacity"`
 	JobPollInterval time.Duration `yaml:"job_poll_interval"`
+	JobDiskSpace    uint64        `yaml:"job_disk_space_bytes"`
 	SmallObjectSize int           `yaml:"small_object_size_bytes"`
 	TempDir         string        `yaml:"temp_dir"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+
+	DownsamplingEnabled  bool `yaml:"downsampling_enabled"`
+	DownsamplingMinLevel uint `yaml:"downsampling_min_level"`
 }
 
 func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
@@ -53,12 +68,21 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	tempdir := filepath.Join(os.TempDir(), "pyroscope-compactor")
 	f.IntVar(&cfg.JobCapacity, prefix+"job-capacity", 3, "How many concurrent jobs will a compaction worker run at most.")
 	f.DurationVar(&cfg.JobPollInterval, prefix+"job-poll-interval", 5*time.Second, "How often will a compaction worker poll for jobs.")
+	f.Uint64Var(&cfg.JobDiskSpace, prefix+"job-disk-space-bytes", 1<<30, "Estimated disk space a compaction job needs in the temporary directory. The worker does not take more jobs than the available disk space allows. 0 to disable.")
 	f.IntVar(&cfg.SmallObjectSize, prefix+"small-object-size-bytes", 8<<20, "Size of the object that can be loaded in memory.")
 	f.StringVar(&cfg.TempDir, prefix+"temp-dir", tempdir, "Temporary directory for compaction jobs.")
+	f.DurationVar(&cfg.ShutdownTimeout, prefix+"shutdown-timeout", time.Minute, "How long the worker waits for the in-progress jobs to complete on shutdown. The jobs not completed in time are abandoned and handed off to other workers.")
+	f.BoolVar(&cfg.DownsamplingEnabled, prefix+"downsampling-enabled", false, "Write downsampled (5m and 1h) profile tables to the compacted blocks.")
+	f.UintVar(&cfg.DownsamplingMinLevel, prefix+"downsampling-min-level", 3, "Minimal compaction level of the blocks to be downsampled.")
 }
 
 func (cfg *Config) Validate() error {
-	// TODO(kolesnikovae): implement.
+	if cfg.JobCapacity <= 0 {
+		return errors.New("compaction worker job capacity must be greater than 0")
+	}
+	if cfg.JobPollInterval <= 0 {
+		return errors.New("compaction worker job poll interval must be greater than 0")
+	}
 	return nil
 }
 
@@ -72,43 +96,29 @@ func New(config Config, logger log.Logger, metastoreClient *metastoreclient.Clie
 		activeJobs:      make(map[string]*compactorv1.CompactionJob),
 		completedJobs:   make(map[string]*compactorv1.CompactionJobStatus),
 		metrics:         newMetrics(reg),
-		queue:           make(chan *compactorv1.CompactionJob, 2*config.JobCapacity),
+		volumeChecker:   diskutil.NewVolumeChecker(0, 0),
+		queue:           make(chan *compactorv1.CompactionJob, config.JobCapacity),
+		stop:            make(chan struct{}),
 	}
+	w.jobsCtx, w.cancelJobs = context.WithCancel(context.Background())
 	w.BasicService = services.NewBasicService(w.starting, w.running, w.stopping)
 	return w, nil
 }
 
 func (w *Worker) starting(ctx context.Context) (err error) {
+	if err = os.MkdirAll(w.config.TempDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	for i := 0; i < w.config.JobCapacity; i++ {
+		w.wg.Add(1)
+		go w.runJobs()
+	}
 	return nil
 }
 
 func (w *Worker) running(ctx context.Context) error {
 	ticker := time.NewTicker(w.config.JobPollInterval)
 	defer ticker.Stop()
-	go func() {
-		for {
-			select {
-			case <-ctx.Done():
-				return
-
-			case job := <-w.queue:
-				w.jobMutex.Lock()
-				delete(w.pendingJobs, job.Name)
-				w.activeJobs[job.Name] = job
-				w.jobMutex.Unlock()
-
-				_ = level.Info(w.logger).Log("msg", "starting compaction job", "job", job.Name)
-				status := w.startJob(ctx, job)
-				_ = level.Info(w.logger).Log("msg", "compaction job finished", "job", job.Name)
-
-				w.jobMutex.Lock()
-				delete(w.activeJobs, job.Name)
-				w.completedJobs[job.Name] = status
-				w.jobMutex.Unlock()
-			}
-		}
-	}()
-
 	for {
 		select {
 		case <-ticker.C:
@@ -120,6 +130,44 @@ func (w *Worker) running(ctx context.Context) error {
 	}
 }
 
+func (w *Worker) runJobs() {
+	defer w.wg.Done()
+	for {
+		select {
+		case <-w.stop:
+			return
+
+		case job := <-w.queue:
+			select {
+			case <-w.stop:
+				// The worker is stopping: the pending
+				// job is released in stopping.
+				return
+			default:
+			}
+
+			w.jobMutex.Lock()
+			if _, ok := w.pendingJobs[job.Name]; !ok {
+				// The job has been released.
+				w.jobMutex.Unlock()
+				continue
+			}
+			delete(w.pendingJobs, job.Name)
+			w.activeJobs[job.Name] = job
+			w.jobMutex.Unlock()
+
+			_ = level.Info(w.logger).Log("msg", "starting compaction job", "job", job.Name)
+			status := w.startJob(w.jobsCtx, job)
+			_ = level.Info(w.logger).Log("msg", "compaction job finished", "job", job.Name)
+
+			w.jobMutex.Lock()
+			delete(w.activeJobs, job.Name)
+			w.completedJobs[job.Name] = status
+			w.jobMutex.Unlock()
+		}
+	}
+}
+
 func (w *Worker) poll(ctx context.Context) {
 	w.jobMutex.Lock()
 	level.Debug(w.logger).Log(
@@ -128,28 +176,8 @@ func (w *Worker) poll(ctx context.Context) {
 		"pending_jobs", len(w.pendingJobs),
 		"pending_updates", len(w.completedJobs))
 
-	pendingStatusUpdates := make([]*compactorv1.CompactionJobStatus, 0, len(w.completedJobs))
-	for _, update := range w.completedJobs {
-		level.Debug(w.logger).Log("msg", "completed job update", "job", update.JobName, "status", update.Status)
-		pendingStatusUpdates = append(pendingStatusUpdates, update)
-	}
-	for _, activeJob := range w.activeJobs {
-		level.Debug(w.logger).Log("msg", "in progress job update", "job", activeJob.Name)
-		update := activeJob.Status.CloneVT()
-		update.Status = compactorv1.CompactionStatus_COMPACTION_STATUS_IN_PROGRESS
-		pendingStatusUpdates = append(pendingStatusUpdates, update)
-	}
-	for _, pendingJob := range w.pendingJobs {
-		level.Debug(w.logger).Log("msg", "pending job update", "job", pendingJob.Name)
-		update := pendingJob.Status.CloneVT()
-		update.Status = compactorv1.CompactionStatus_COMPACTION_STATUS_IN_PROGRESS
-		pendingStatusUpdates = append(pendingStatusUpdates, update)
-	}
-
-	jobCapacity := w.config.JobCapacity - len(w.activeJobs) - len(w.pendingJobs)
-	if jobCapacity < 0 {
-		jobCapacity = 0
-	}
+	pendingStatusUpdates := w.statusUpdates()
+	jobCapacity := w.jobCapacity()
 	w.jobMutex.Unlock()
 
 	if len(pendingStatusUpdates) > 0 || jobCapacity > 0 {
@@ -171,9 +199,7 @@ func (w *Worker) poll(ctx context.Context) {
 		}
 
 		w.jobMutex.Lock()
-		for _, update := range pendingStatusUpdates {
-			delete(w.completedJobs, update.JobName)
-		}
+		w.removeReported(pendingStatusUpdates)
 		for _, job := range pendingJobs {
 			w.pendingJobs[job.Name] = job
 		}
@@ -183,20 +209,160 @@ func (w *Worker) poll(ctx context.Context) {
 			select {
 			case w.queue <- job:
 			default:
-				level.Warn(w.logger).Log("msg", "dropping job", "job_name", job.Name)
+				// Should not happen as we never ask for more jobs than
+				// we can run; the job is released to other workers.
+				level.Warn(w.logger).Log("msg", "releasing job: no capacity", "job_name", job.Name)
 				w.jobMutex.Lock()
 				delete(w.pendingJobs, job.Name)
+				w.completedJobs[job.Name] = releasedJobStatus(job)
 				w.jobMutex.Unlock()
 			}
 		}
 	}
 }
 
-func (w *Worker) stopping(err error) error {
-	// TODO aleks: handle shutdown
+// statusUpdates returns the status updates to be reported to
+// the metastore. The caller must hold the jobMutex.
+func (w *Worker) statusUpdates() []*compactorv1.CompactionJobStatus {
+	updates := make([]*compactorv1.CompactionJobStatus, 0, len(w.completedJobs)+len(w.activeJobs)+len(w.pendingJobs))
+	for _, update := range w.completedJobs {
+		level.Debug(w.logger).Log("msg", "completed job update", "job", update.JobName, "status", update.Status)
+		updates = append(updates, update)
+	}
+	for _, activeJob := range w.activeJobs {
+		level.Debug(w.logger).Log("msg", "in progress job update", "job", activeJob.Name)
+		update := activeJob.Status.CloneVT()
+		update.Status = compactorv1.CompactionStatus_COMPACTION_STATUS_IN_PROGRESS
+		updates = append(updates, update)
+	}
+	for _, pendingJob := range w.pendingJobs {
+		level.Debug(w.logger).Log("msg", "pending job update", "job", pendingJob.Name)
+		update := pendingJob.Status.CloneVT()
+		update.Status = compactorv1.CompactionStatus_COMPACTION_STATUS_IN_PROGRESS
+		updates = append(updates, update)
+	}
+	return updates
+}
+
+// removeReported removes the reported updates of completed jobs.
+// The caller must hold the jobMutex.
+func (w *Worker) removeReported(updates []*compactorv1.CompactionJobStatus) {
+	for _, update := range updates {
+		if update.Status != compactorv1.CompactionStatus_COMPACTION_STATUS_IN_PROGRESS {
+			delete(w.completedJobs, update.JobName)
+		}
+	}
+}
+
+// jobCapacity returns the number of new jobs the worker can take, given
+// the jobs it already has and the available disk space in the temp dir.
+// No jobs are taken once the worker is stopping. The disk space used by
+// the jobs in progress is not available anymore, therefore only the
+// pending jobs, which have not started yet, are subtracted from the jobs
+// the available disk space allows. The caller must hold the jobMutex.
+func (w *Worker) jobCapacity() int {
+	select {
+	case <-w.stop:
+		return 0
+	default:
+	}
+	n := w.config.JobCapacity - len(w.activeJobs) - len(w.pendingJobs)
+	if w.config.JobDiskSpace > 0 {
+		stats, err := w.volumeChecker.HasHighDiskUtilization(w.config.TempDir)
+		if err != nil {
+			level.Warn(w.logger).Log("msg", "failed to check available disk space", "err", err)
+		} else if d := int(stats.BytesAvailable/w.config.JobDiskSpace) - len(w.pendingJobs); d < n {
+			level.Debug(w.logger).Log("msg", "job capacity limited by available disk space", "available_bytes", stats.BytesAvailable, "job_capacity", d)
+			n = d
+		}
+	}
+	return max(n, 0)
+}
+
+func releasedJobStatus(job *compactorv1.CompactionJob) *compactorv1.CompactionJobStatus {
+	status := job.Status.CloneVT()
+	status.Status = compactorv1.CompactionStatus_COMPACTION_STATUS_UNSPECIFIED
+	return status
+}
+
+// stopping stops taking new jobs, waits for the jobs in progress to
+// complete within the shutdown timeout, and reports the status of the
+// jobs to the metastore. The jobs that have not been started are released
+// immediately, and so are the jobs not completed in time, so that other
+// workers can take them over without waiting for the lease to expire.
+// The worker keeps polling while the jobs in progress complete: this
+// renews their leases and reports the completed ones.
+func (w *Worker) stopping(error) error {
+	close(w.stop)
+	w.releasePendingJobs()
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	ticker := time.NewTicker(w.config.JobPollInterval)
+	defer ticker.Stop()
+	timeout := time.NewTimer(w.config.ShutdownTimeout)
+	defer timeout.Stop()
+	w.pollStopping()
+drain:
+	for {
+		select {
+		case <-done:
+			break drain
+		case <-ticker.C:
+			w.pollStopping()
+		case <-timeout.C:
+			w.jobMutex.RLock()
+			level.Warn(w.logger).Log("msg", "shutdown timeout exceeded, abandoning compaction jobs", "active_jobs", len(w.activeJobs))
+			w.jobMutex.RUnlock()
+			w.cancelJobs()
+			<-done
+			break drain
+		}
+	}
+	w.cancelJobs()
+
+	w.releasePendingJobs()
+	w.jobMutex.Lock()
+	updates := w.statusUpdates()
+	w.jobMutex.Unlock()
+	if len(updates) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), w.config.JobPollInterval)
+	defer cancel()
+	_, err := w.metastoreClient.PollCompactionJobs(ctx, &compactorv1.PollCompactionJobsRequest{
+		JobStatusUpdates: updates,
+	})
+	if err != nil {
+		level.Error(w.logger).Log("msg", "failed to report compaction job status on shutdown", "err", err)
+		return nil
+	}
+	level.Info(w.logger).Log("msg", "reported compaction job status on shutdown", "jobs", len(updates))
 	return nil
 }
 
+func (w *Worker) releasePendingJobs() {
+	w.jobMutex.Lock()
+	defer w.jobMutex.Unlock()
+	for name, job := range w.pendingJobs {
+		w.completedJobs[name] = releasedJobStatus(job)
+		delete(w.pendingJobs, name)
+	}
+}
+
+// pollStopping reports the job status updates while the
+// worker is stopping; no new jobs are requested.
+func (w *Worker) pollStopping() {
+	ctx, cancel := context.WithTimeout(context.Background(), w.config.JobPollInterval)
+	defer cancel()
+	w.poll(ctx)
+}
+
 func (w *Worker) startJob(ctx context.Context, job *compactorv1.CompactionJob) *compactorv1.CompactionJobStatus {
 	jobStartTime := time.Now()
 	labels := []string{job.TenantId, fmt.Sprint(job.Shard), fmt.Sprint(job.CompactionLevel)}
@@ -226,17 +392,21 @@ func (w *Worker) startJob(ctx context.Context, job *compactorv1.CompactionJob) *
 
 	tempdir := filepath.Join(w.config.TempDir, job.Name)
 	sourcedir := filepath.Join(tempdir, "source")
-	// TODO(kolesnikovae): Return the actual error once we
-	//   can handle compaction failures in metastore.
-	compacted, err := pretendEverythingIsOK(func() ([]*metastorev1.BlockMeta, error) {
-		return block.Compact(ctx, job.Blocks, w.storage,
-			block.WithCompactionTempDir(tempdir),
-			block.WithCompactionObjectOptions(
-				block.WithObjectMaxSizeLoadInMemory(w.config.SmallObjectSize),
-				block.WithObjectDownload(sourcedir),
-			),
-		)
-	})
+	options := []block.CompactionOption{
+		block.WithCompactionTempDir(tempdir),
+		block.WithCompactionObjectOptions(
+			block.WithObjectMaxSizeLoadInMemory(w.config.SmallObjectSize),
+			block.WithObjectDownload(sourcedir),
+		),
+	}
+	if w.config.DownsamplingEnabled {
+		options = append(options, block.WithCompactionDownsampling(uint32(w.config.DownsamplingMinLevel)))
+	}
+	var compacted []*metastorev1.BlockMeta
+	err := util.RecoverPanic(func() (err error) {
+		compacted, err = block.Compact(ctx, job.Blocks, w.storage, options...)
+		return err
+	})()
 
 	logger := log.With(w.logger,
 		"job_name", job.Name,
@@ -269,7 +439,8 @@ func (w *Worker) startJob(ctx context.Context, job *compactorv1.CompactionJob) *
 		job.Status.CompletedJob = &compactorv1.CompletedJob{Blocks: compacted}
 		statusName = "success"
 
-	case errors.Is(err, context.Canceled):
+	case errors.Is(err, context.Canceled) || ctx.Err() != nil:
+		// The job is abandoned: the worker is shutting down.
 		_ = level.Warn(logger).Log("msg", "job cancelled", "job", job.Name)
 		job.Status.Status = compactorv1.CompactionStatus_COMPACTION_STATUS_UNSPECIFIED
 		statusName = "cancelled"
@@ -277,28 +448,9 @@ func (w *Worker) startJob(ctx context.Context, job *compactorv1.CompactionJob) *
 	default:
 		_ = level.Error(logger).Log("msg", "failed to compact blocks", "err", err, "job", job.Name)
 		job.Status.Status = compactorv1.CompactionStatus_COMPACTION_STATUS_FAILURE
+		job.Status.FailureReason = err.Error()
 		statusName = "failure"
 	}
 
 	return job.Status
 }
-
-func pretendEverythingIsOK(fn func() ([]*metastorev1.BlockMeta, error)) (m []*metastorev1.BlockMeta, err error) {
-	defer func() {
-		if r := recover(); r != nil {
-			fmt.Println("ignoring compaction panic:", r)
-			fmt.Println(string(debug.Stack()))
-			m = nil
-		}
-		if err != nil {
-			if errors.Is(err, context.Canceled) {
-				// We can handle this.
-				return
-			}
-			fmt.Println("ignoring compaction error:", err)
-			m = nil
-		}
-		err = nil
-	}()
-	return fn()
-}
@@ -235,28 +235,8 @@ func selectShards(s []shard, n uint32, k uint64) []shard {
 	x := make([]shard, 0, n)
 	// Note that the choice is deterministic.
 	for i := uint32(0); i < n; i++ {
-		j := jump(k&^steps[i], m)
+		j := placement.Jump(k&^steps[i], m)
 		x = append(x, s[j])
 	}
 	return x
 }
-
-// The inputs are a key and the number of buckets.
-// It outputs a bucket number in the range [0, buckets).
-//
-// Refer to https://arxiv.org/pdf/1406.2294:
-// The function satisfies the two properties:
-//  1. About the same number of keys map to each bucket.
-//  2. The mapping from key to bucket is perturbed as little as possible when
-//     the number of buckets is changed. Thus, the only data that needs to move
-//     when the number of buckets changes is the data for the relatively small
-//     number of keys whose bucket assignment changed.
-func jump(key uint64, buckets int) int {
-	var b, j = -1, 0
-	for j < buckets {
-		b = j
-		key = key*2862933555777941757 + 1
-		j = int(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
-	}
-	return b
-}
@@ -0,0 +1,199 @@
+package placement
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// The ingestion rate of a dataset is measured over the window,
+	// and smoothed with the exponentially weighted moving average.
+	rateWindow    = 15 * time.Second
+	rateSmoothing = 0.3
+
+	// The number of shards is only decreased, if the observed rate
+	// stays below the threshold of the target shard rate. This prevents
+	// the placement from flapping when the rate is close to the boundary.
+	scaleDownThreshold = 0.75
+
+	// Datasets not observed for this period are removed.
+	datasetStatsTTL = 10 * time.Minute
+)
+
+// Limits provides the per-tenant placement limits.
+type Limits interface {
+	SegmentWriterTenantShards(tenantID string) int
+	SegmentWriterDatasetShards(tenantID, dataset string) (shards int, fixed bool)
+	SegmentWriterDatasetShardsMax(tenantID string) int
+	SegmentWriterDatasetShardRateBytes(tenantID string) int
+}
+
+// Distributors provides the number of distributor instances. Each of the
+// distributors only observes its own part of the ingestion rate.
+type Distributors interface {
+	HealthyInstancesCount() int
+}
+
+// Observer is implemented by strategies that take
+// the observed ingestion rate into account.
+type Observer interface {
+	// Observe records size bytes ingested for the key.
+	Observe(k Key, size int)
+}
+
+// AdaptivePlacement is the placement strategy that determines the number
+// of shards for tenants and datasets based on the tenant limits. Unless
+// the number of dataset shards is fixed, the strategy adjusts it to the
+// observed ingestion rate of the dataset, so that each of the shards
+// receives about the target rate, within the limits configured.
+//
+// Each distributor observes the rate of the requests it handles: assuming
+// the requests are balanced across the distributors, the rate of the
+// dataset is estimated as the observed rate multiplied by the number of
+// distributors, so that all of them arrive at about the same shard count.
+//
+// The shard for a key is picked with the jump consistent hashing: when
+// the number of shards changes, only the minimal fraction of the series
+// is relocated.
+type AdaptivePlacement struct {
+	limits       Limits
+	distributors Distributors
+
+	mu          sync.RWMutex
+	datasets    map[uint64]*datasetStats
+	lastCleanup time.Time
+}
+
+type datasetStats struct {
+	mu          sync.Mutex
+	windowStart time.Time
+	lastSeen    time.Time
+	bytes       uint64
+	rate        float64
+	measured    bool
+	shards      uint32
+}
+
+// NewAdaptivePlacement creates the adaptive placement strategy.
+// If distributors is nil, the observed rate is considered to be
+// the rate of the dataset.
+func NewAdaptivePlacement(limits Limits, distributors Distributors) *AdaptivePlacement {
+	return &AdaptivePlacement{
+		limits:       limits,
+		distributors: distributors,
+		datasets:     make(map[uint64]*datasetStats),
+		lastCleanup:  time.Now(),
+	}
+}
+
+func (p *AdaptivePlacement) NumTenantShards(k Key, n uint32) uint32 {
+	size := p.limits.SegmentWriterTenantShards(k.TenantID)
+	if size <= 0 {
+		return 0
+	}
+	return min(uint32(size), n)
+}
+
+func (p *AdaptivePlacement) NumDatasetShards(k Key, n uint32) uint32 {
+	size, fixed := p.limits.SegmentWriterDatasetShards(k.TenantID, k.DatasetName)
+	shards := uint32(max(size, 0))
+	if !fixed {
+		if m := p.limits.SegmentWriterDatasetShardsMax(k.TenantID); m > size {
+			// The dataset is placed on at least one shard: 0 would mean
+			// all the shards before the rate is measured.
+			shards = max(shards, min(p.observedShards(k), uint32(m)), 1)
+		}
+	}
+	return min(shards, n)
+}
+
+func (p *AdaptivePlacement) PickShard(k Key, n uint32) uint32 {
+	return uint32(Jump(k.Fingerprint, int(n)))
+}
+
+func (p *AdaptivePlacement) Observe(k Key, size int) { p.observe(k, size, time.Now()) }
+
+func (p *AdaptivePlacement) observe(k Key, size int, now time.Time) {
+	s := p.dataset(k, now)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bytes += uint64(size)
+	s.lastSeen = now
+	elapsed := now.Sub(s.windowStart)
+	if elapsed < rateWindow {
+		return
+	}
+	rate := float64(s.bytes) / elapsed.Seconds()
+	if s.measured {
+		rate = rateSmoothing*rate + (1-rateSmoothing)*s.rate
+	}
+	s.rate = rate
+	s.measured = true
+	s.bytes = 0
+	s.windowStart = now
+	if target := p.limits.SegmentWriterDatasetShardRateBytes(k.TenantID); target > 0 {
+		s.updateShards(rate*float64(p.numDistributors()), float64(target))
+	}
+}
+
+func (p *AdaptivePlacement) numDistributors() int {
+	if p.distributors == nil {
+		return 1
+	}
+	return max(p.distributors.HealthyInstancesCount(), 1)
+}
+
+func (s *datasetStats) updateShards(rate, target float64) {
+	if up := uint32(math.Ceil(rate / target)); up > s.shards {
+		s.shards = up
+		return
+	}
+	if down := uint32(math.Ceil(rate / (target * scaleDownThreshold))); down < s.shards {
+		s.shards = down
+	}
+}
+
+func (p *AdaptivePlacement) observedShards(k Key) uint32 {
+	p.mu.RLock()
+	s, ok := p.datasets[k.Dataset]
+	p.mu.RUnlock()
+	if !ok {
+		return 0
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.shards
+}
+
+func (p *AdaptivePlacement) dataset(k Key, now time.Time) *datasetStats {
+	p.mu.RLock()
+	s, ok := p.datasets[k.Dataset]
+	p.mu.RUnlock()
+	if ok {
+		return s
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if s, ok = p.datasets[k.Dataset]; ok {
+		return s
+	}
+	if now.Sub(p.lastCleanup) > datasetStatsTTL {
+		p.removeStale(now)
+	}
+	s = &datasetStats{windowStart: now, lastSeen: now}
+	p.datasets[k.Dataset] = s
+	return s
+}
+
+func (p *AdaptivePlacement) removeStale(now time.Time) {
+	for key, s := range p.datasets {
+		s.mu.Lock()
+		stale := now.Sub(s.lastSeen) > datasetStatsTTL
+		s.mu.Unlock()
+		if stale {
+			delete(p.datasets, key)
+		}
+	}
+	p.lastCleanup = now
+}
@@ -0,0 +1,144 @@
+package placement
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockLimits struct {
+	tenantShards    int
+	datasetShards   int
+	datasetMax      int
+	shardRate       int
+	datasetOverride map[string]int
+}
+
+func (m mockLimits) SegmentWriterTenantShards(string) int { return m.tenantShards }
+
+func (m mockLimits) SegmentWriterDatasetShards(_, dataset string) (int, bool) {
+	if n, ok := m.datasetOverride[dataset]; ok {
+		return n, true
+	}
+	return m.datasetShards, false
+}
+
+func (m mockLimits) SegmentWriterDatasetShardsMax(string) int { return m.datasetMax }
+
+func (m mockLimits) SegmentWriterDatasetShardRateBytes(string) int { return m.shardRate }
+
+func Test_AdaptivePlacement_Limits(t *testing.T) {
+	p := NewAdaptivePlacement(mockLimits{
+		tenantShards:    8,
+		datasetShards:   2,
+		datasetOverride: map[string]int{"large": 6},
+	}, nil)
+	k := Key{TenantID: "tenant", DatasetName: "small"}
+	assert.Equal(t, uint32(8), p.NumTenantShards(k, 64))
+	assert.Equal(t, uint32(4), p.NumTenantShards(k, 4))
+	assert.Equal(t, uint32(2), p.NumDatasetShards(k, 8))
+	k.DatasetName = "large"
+	assert.Equal(t, uint32(6), p.NumDatasetShards(k, 8))
+	assert.Equal(t, uint32(3), p.NumDatasetShards(k, 3))
+
+	p = NewAdaptivePlacement(mockLimits{}, nil)
+	assert.Equal(t, uint32(0), p.NumTenantShards(k, 64))
+	assert.Equal(t, uint32(0), p.NumDatasetShards(k, 64))
+}
+
+func Test_AdaptivePlacement_Rate(t *testing.T) {
+	p := NewAdaptivePlacement(mockLimits{
+		datasetShards: 2,
+		datasetMax:    8,
+		shardRate:     100,
+	}, nil)
+	k := Key{TenantID: "tenant", DatasetName: "service", Dataset: 1}
+	now := time.Now()
+	observe := func(rate int, d time.Duration) {
+		for end := now.Add(d); now.Before(end); now = now.Add(time.Second) {
+			p.observe(k, rate, now)
+		}
+	}
+
+	assert.Equal(t, uint32(2), p.NumDatasetShards(k, 64))
+	observe(150, time.Minute)
+	assert.Equal(t, uint32(2), p.NumDatasetShards(k, 64))
+
+	observe(550, 5*time.Minute)
+	assert.Equal(t, uint32(6), p.NumDatasetShards(k, 64))
+	assert.Equal(t, uint32(4), p.NumDatasetShards(k, 4))
+
+	// The rate is close to the boundary: the number
+	// of shards should not be decreased.
+	observe(480, 5*time.Minute)
+	assert.Equal(t, uint32(6), p.NumDatasetShards(k, 64))
+
+	observe(5000, 5*time.Minute)
+	assert.Equal(t, uint32(8), p.NumDatasetShards(k, 64))
+
+	observe(10, 5*time.Minute)
+	assert.Equal(t, uint32(2), p.NumDatasetShards(k, 64))
+}
+
+type mockDistributors int
+
+func (m mockDistributors) HealthyInstancesCount() int { return int(m) }
+
+func Test_AdaptivePlacement_MultipleDistributors(t *testing.T) {
+	limits := mockLimits{
+		datasetShards: 2,
+		datasetMax:    8,
+		shardRate:     100,
+	}
+	// Each of the distributors observes half of the dataset rate.
+	distributors := []*AdaptivePlacement{
+		NewAdaptivePlacement(limits, mockDistributors(2)),
+		NewAdaptivePlacement(limits, mockDistributors(2)),
+	}
+	k := Key{TenantID: "tenant", DatasetName: "service", Dataset: 1}
+	now := time.Now()
+	for end := now.Add(5 * time.Minute); now.Before(end); now = now.Add(time.Second) {
+		for _, p := range distributors {
+			p.observe(k, 275, now)
+		}
+	}
+	for _, p := range distributors {
+		assert.Equal(t, uint32(6), p.NumDatasetShards(k, 64))
+	}
+}
+
+func Test_AdaptivePlacement_MinDatasetShards(t *testing.T) {
+	p := NewAdaptivePlacement(mockLimits{
+		datasetMax: 8,
+		shardRate:  100,
+	}, nil)
+	k := Key{TenantID: "tenant", DatasetName: "service", Dataset: 1}
+	// 0 would mean all the shards.
+	assert.Equal(t, uint32(1), p.NumDatasetShards(k, 64))
+}
+
+func Test_AdaptivePlacement_RemoveStale(t *testing.T) {
+	p := NewAdaptivePlacement(mockLimits{}, nil)
+	now := time.Now()
+	p.observe(Key{Dataset: 1}, 1, now)
+	now = now.Add(2 * datasetStatsTTL)
+	p.observe(Key{Dataset: 2}, 1, now)
+	assert.Len(t, p.datasets, 1)
+	assert.Contains(t, p.datasets, uint64(2))
+}
+
+func Test_Jump_Stable(t *testing.T) {
+	const keys = 10000
+	var moved int
+	for i := uint64(0); i < keys; i++ {
+		k := i * 0x9E3779B97F4A7C15
+		a, b := Jump(k, 4), Jump(k, 5)
+		if a != b {
+			assert.Equal(t, 4, b)
+			moved++
+		}
+	}
+	// About 1/5 of the keys are expected to move to the new bucket.
+	assert.InDelta(t, keys/5, moved, keys/50)
+}
@@ -26,6 +26,8 @@ type Strategy interface {
 	PickShard(k Key, n uint32) (shard uint32)
 }
 
+// DefaultPlacement places datasets on two shards, and does
+// not limit the number of shards available to a tenant.
 var DefaultPlacement = defaultPlacement{}
 
 type defaultPlacement struct{}
@@ -34,7 +36,7 @@ func (defaultPlacement) NumTenantShards(Key, uint32) uint32 { return 0 }
 
 func (defaultPlacement) NumDatasetShards(Key, uint32) uint32 { return 2 }
 
-func (defaultPlacement) PickShard(k Key, n uint32) uint32 { return uint32(k.Fingerprint % uint64(n)) }
+func (defaultPlacement) PickShard(k Key, n uint32) uint32 { return uint32(Jump(k.Fingerprint, int(n))) }
 
 // Placement represents the placement for the given distribution key.
 type Placement struct {
@@ -65,3 +67,24 @@ func (p *Placement) isVisited(x *ring.InstanceDesc) bool {
 	p.visited[x.Addr] = x
 	return false
 }
+
+// Jump implements the jump consistent hashing.
+// The inputs are a key and the number of buckets.
+// It outputs a bucket number in the range [0, buckets).
+//
+// Refer to https://arxiv.org/pdf/1406.2294:
+// The function satisfies the two properties:
+//  1. About the same number of keys map to each bucket.
+//  2. The mapping from key to bucket is perturbed as little as possible when
+//     the number of buckets is changed. Thus, the only data that needs to move
+//     when the number of buckets changes is the data for the relatively small
+//     number of keys whose bucket assignment changed.
+func Jump(key uint64, buckets int) int {
+	var b, j = -1, 0
+	for j < buckets {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return b
+}
@@ -146,6 +146,7 @@ type Client struct {
 
 	ring        ring.ReadRing
 	pool        *connpool.RingConnPool
+	placement   placement.Strategy
 	distributor *distributor.Distributor
 
 	service     services.Service
@@ -158,6 +159,7 @@ func NewSegmentWriterClient(
 	logger log.Logger,
 	registry prometheus.Registerer,
 	ring ring.ReadRing,
+	placementStrategy placement.Strategy,
 	dialOpts ...grpc.DialOption,
 ) (*Client, error) {
 	pool, err := newConnPool(ring, logger, grpcClientConfig, dialOpts...)
@@ -169,7 +171,8 @@ func NewSegmentWriterClient(
 		metrics:     newMetrics(registry),
 		ring:        ring,
 		pool:        pool,
-		distributor: distributor.NewDistributor(placement.DefaultPlacement),
+		placement:   placementStrategy,
+		distributor: distributor.NewDistributor(placementStrategy),
 	}
 	c.subservices, err = services.NewManager(c.pool)
 	if err != nil {
@@ -205,6 +208,9 @@ func (c *Client) Push(
 	req *segmentwriterv1.PushRequest,
 ) (*segmentwriterv1.PushResponse, error) {
 	k := distributor.NewTenantServiceDatasetKey(req.TenantId, req.Labels)
+	if o, ok := c.placement.(placement.Observer); ok {
+		o.Observe(k, len(req.Profile))
+	}
 	p, dErr := c.distributor.Distribute(k, c.ring)
 	if dErr != nil {
 		_ = level.Error(c.logger).Log(
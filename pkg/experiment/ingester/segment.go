@@ -10,6 +10,7 @@ import (
 	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-kit/log"
@@ -22,6 +23,7 @@ import (
 	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
 	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
 	"github.com/grafana/pyroscope/pkg/experiment/ingester/memdb"
+	"github.com/grafana/pyroscope/pkg/experiment/query_backend/block"
 	"github.com/grafana/pyroscope/pkg/model"
 	pprofsplit "github.com/grafana/pyroscope/pkg/model/pprof_split"
 	pprofmodel "github.com/grafana/pyroscope/pkg/pprof"
@@ -37,16 +39,29 @@ const pathBlock = "block.bin"
 
 var ErrMetastoreDLQFailed = fmt.Errorf("failed to store block metadata in DLQ")
 
+// ErrShardMemoryLimit is returned when the shard holds more data
+// pending flush than allowed, e.g., when the object storage is slow.
+var ErrShardMemoryLimit = fmt.Errorf("segment writer shard memory limit reached")
+
 type shardKey uint32
 
 type segmentWriterConfig struct {
-	segmentDuration time.Duration
+	segmentDuration    time.Duration
+	segmentMaxSize     int64
+	maxInFlightFlushes int
+	shardMaxMemory     int64
 }
 
 type segmentsWriter struct {
 	segmentDuration time.Duration
+	segmentMaxSize  int64
+	shardMaxMemory  int64
 	limits          Limits
 
+	// flushes limits the number of segments being flushed
+	// concurrently. If nil, the number is not limited.
+	flushes chan struct{}
+
 	l               log.Logger
 	bucket          objstore.Bucket
 	metastoreClient metastorev1.MetastoreServiceClient
@@ -68,6 +83,12 @@ type shard struct {
 	wg          sync.WaitGroup
 	l           log.Logger
 	concatBuf   []byte
+	sshard      string
+
+	// memory is the size of the data ingested into
+	// the shard, which has not been flushed yet.
+	memory   atomic.Int64
+	flushNow chan struct{}
 }
 
 func (sh *shard) ingest(fn func(head segmentIngest)) segmentWaitFlushed {
@@ -80,32 +101,70 @@ func (sh *shard) ingest(fn func(head segmentIngest)) segmentWaitFlushed {
 	return s
 }
 
+// The segment is flushed when the segment duration elapses, or when the
+// segment reaches the max size, whichever happens first. In the latter
+// case the segment duration is counted from the flush.
 func (sh *shard) loop(ctx context.Context) {
 	ticker := time.NewTicker(sh.sw.segmentDuration)
 	defer ticker.Stop()
 	for {
 		select {
 		case <-ticker.C:
-			sh.flushSegment(context.Background())
+			sh.flushSegment(context.Background(), flushReasonTime)
+		case <-sh.flushNow:
+			ticker.Reset(sh.sw.segmentDuration)
+			sh.flushSegment(context.Background(), flushReasonSize)
 		case <-ctx.Done():
-			sh.flushSegment(context.Background())
+			sh.flushSegment(context.Background(), flushReasonShutdown)
 			return
 		}
 	}
 }
 
-func (sh *shard) flushSegment(ctx context.Context) {
+const (
+	flushReasonTime     = "time"
+	flushReasonSize     = "size"
+	flushReasonShutdown = "shutdown"
+)
+
+// requestFlush notifies the shard loop that the current
+// segment should be flushed without waiting for the ticker.
+func (sh *shard) requestFlush() {
+	select {
+	case sh.flushNow <- struct{}{}:
+	default:
+	}
+}
+
+// checkMemory returns ErrShardMemoryLimit if the shard holds
+// more data pending flush than allowed.
+func (sh *shard) checkMemory() error {
+	if sh.sw.shardMaxMemory > 0 && sh.memory.Load() >= sh.sw.shardMaxMemory {
+		return ErrShardMemoryLimit
+	}
+	return nil
+}
+
+func (sh *shard) flushSegment(ctx context.Context, reason string) {
 	sh.currentLock.Lock()
 	s := sh.current
 	sh.current = sh.sw.newSegment(sh, s.shard, sh.l)
 	sh.currentLock.Unlock()
+	sh.sw.metrics.segmentFlushes.WithLabelValues(reason).Inc()
 
+	sh.wg.Add(1)
 	go func() { // not blocking next ticks in case metastore/s3 latency is high
+		defer sh.wg.Done()
 		t1 := time.Now()
 		s.inFlightProfiles.Wait()
 		s.debuginfo.waitInflight = time.Since(t1)
 
+		sh.sw.acquireFlush()
 		err := s.flush(ctx)
+		sh.sw.releaseFlush()
+		size := s.size.Load()
+		sh.memory.Add(-size)
+		sh.sw.metrics.shardMemoryBytes.WithLabelValues(sh.sshard).Sub(float64(size))
 		if err != nil {
 			_ = level.Error(sh.sw.l).Log("msg", "failed to flush segment", "err", err)
 		}
@@ -130,6 +189,8 @@ func newSegmentWriter(l log.Logger, metrics *segmentMetrics, hm *memdb.HeadMetri
 		metrics:         metrics,
 		headMetrics:     hm,
 		segmentDuration: cfg.segmentDuration,
+		segmentMaxSize:  cfg.segmentMaxSize,
+		shardMaxMemory:  cfg.shardMaxMemory,
 		l:               l,
 		bucket:          bucket,
 		shards:          make(map[shardKey]*shard),
@@ -137,29 +198,53 @@ func newSegmentWriter(l log.Logger, metrics *segmentMetrics, hm *memdb.HeadMetri
 		cancel:          cancelFunc,
 		cancelCtx:       ctx,
 	}
+	if cfg.maxInFlightFlushes > 0 {
+		sw.flushes = make(chan struct{}, cfg.maxInFlightFlushes)
+	}
 
 	return sw
 }
 
 func (sw *segmentsWriter) ingest(shard shardKey, fn func(head segmentIngest)) (await segmentWaitFlushed) {
+	return sw.shard(shard).ingest(fn)
+}
+
+// checkLimits returns an error if the shard is not able to
+// accept more data until the pending segments are flushed.
+func (sw *segmentsWriter) checkLimits(shard shardKey) error {
+	return sw.shard(shard).checkMemory()
+}
+
+func (sw *segmentsWriter) shard(k shardKey) *shard {
 	sw.shardsLock.RLock()
-	s, ok := sw.shards[shard]
+	s, ok := sw.shards[k]
 	sw.shardsLock.RUnlock()
 	if ok {
-		return s.ingest(fn)
+		return s
 	}
 
 	sw.shardsLock.Lock()
-	s, ok = sw.shards[shard]
-	if ok {
-		sw.shardsLock.Unlock()
-		return s.ingest(fn)
+	defer sw.shardsLock.Unlock()
+	if s, ok = sw.shards[k]; ok {
+		return s
 	}
+	s = sw.newShard(k)
+	sw.shards[k] = s
+	return s
+}
 
-	s = sw.newShard(shard)
-	sw.shards[shard] = s
-	sw.shardsLock.Unlock()
-	return s.ingest(fn)
+func (sw *segmentsWriter) acquireFlush() {
+	if sw.flushes != nil {
+		sw.flushes <- struct{}{}
+	}
+	sw.metrics.flushesInFlight.Inc()
+}
+
+func (sw *segmentsWriter) releaseFlush() {
+	sw.metrics.flushesInFlight.Dec()
+	if sw.flushes != nil {
+		<-sw.flushes
+	}
 }
 
 func (sw *segmentsWriter) Stop() error {
@@ -181,6 +266,8 @@ func (sw *segmentsWriter) newShard(sk shardKey) *shard {
 		sw:        sw,
 		l:         sl,
 		concatBuf: make([]byte, 4*0x1000),
+		sshard:    fmt.Sprintf("%d", sk),
+		flushNow:  make(chan struct{}, 1),
 	}
 	sh.current = sw.newSegment(sh, sk, sl)
 	sh.wg.Add(1)
@@ -283,6 +370,9 @@ func (s *segment) flushBlock(heads []flushedServiceHead) ([]byte, *metastorev1.B
 	}
 
 	meta.Size = uint64(w.offset)
+	if err := block.WriteMetadata(blockFile, meta, nil); err != nil {
+		return nil, nil, fmt.Errorf("failed to write block metadata: %w", err)
+	}
 	s.debuginfo.flushBlockDuration = time.Since(t1)
 	return blockFile.Bytes(), meta, nil
 }
@@ -421,6 +511,8 @@ type segment struct {
 	}
 	sh      *shard
 	counter int64
+	// size is the size of the data ingested into the segment.
+	size atomic.Int64
 }
 
 type segmentIngest interface {
@@ -460,6 +552,11 @@ func (s *segment) ingest(ctx context.Context, tenantID string, p *profilev1.Prof
 	pprofsplit.VisitSampleSeries(p, labels, rules, appender)
 	size -= appender.discardedBytes
 	s.sw.metrics.segmentIngestBytes.WithLabelValues(s.sshard, tenantID).Observe(float64(size))
+	s.sh.memory.Add(int64(size))
+	s.sw.metrics.shardMemoryBytes.WithLabelValues(s.sshard).Add(float64(size))
+	if n := s.size.Add(int64(size)); s.sw.segmentMaxSize > 0 && n >= s.sw.segmentMaxSize {
+		s.sh.requestFlush()
+	}
 	usage.CountDiscardedBytes(string(validation.DroppedByRelabelRules), int64(appender.discardedBytes))
 	// CountReceivedBytes is tracked in distributors.
 }
@@ -18,6 +18,10 @@ type segmentMetrics struct {
 	flushHeadsDuration       *prometheus.HistogramVec
 	flushServiceHeadDuration *prometheus.HistogramVec
 	flushServiceHeadError    *prometheus.CounterVec
+	segmentFlushes           *prometheus.CounterVec
+	flushesInFlight          prometheus.Gauge
+	shardMemoryBytes         *prometheus.GaugeVec
+	rejectedPushes           *prometheus.CounterVec
 }
 
 var (
@@ -102,6 +106,27 @@ func newSegmentMetrics(reg prometheus.Registerer) *segmentMetrics {
 				Name:      "segment_head_size_bytes",
 				Buckets:   prometheus.ExponentialBucketsRange(10*1024, 100*1024*1024, 30),
 			}, []string{"shard", "tenant"}),
+
+		segmentFlushes: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "pyroscope",
+				Name:      "segment_flushes",
+			}, []string{"reason"}),
+		flushesInFlight: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: "pyroscope",
+				Name:      "segment_flushes_in_flight",
+			}),
+		shardMemoryBytes: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pyroscope",
+				Name:      "segment_shard_memory_bytes",
+			}, []string{"shard"}),
+		rejectedPushes: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "pyroscope",
+				Name:      "segment_rejected_pushes",
+			}, []string{"tenant"}),
 	}
 
 	if reg != nil {
@@ -118,6 +143,10 @@ func newSegmentMetrics(reg prometheus.Registerer) *segmentMetrics {
 		reg.MustRegister(m.flushServiceHeadError)
 		reg.MustRegister(m.flushSegmentDuration)
 		reg.MustRegister(m.headSizeBytes)
+		reg.MustRegister(m.segmentFlushes)
+		reg.MustRegister(m.flushesInFlight)
+		reg.MustRegister(m.shardMemoryBytes)
+		reg.MustRegister(m.rejectedPushes)
 	}
 	return m
 }
@@ -136,6 +136,51 @@ func TestIngestWait(t *testing.T) {
 	require.True(t, since > 1*time.Second)
 }
 
+func TestSegmentMaxSizeFlush(t *testing.T) {
+	sw := newTestSegmentWriter(t, segmentWriterConfig{
+		segmentDuration: time.Hour,
+		segmentMaxSize:  1,
+	})
+	defer sw.Stop()
+	sw.client.On("AddBlock", mock.Anything, mock.Anything, mock.Anything).
+		Return(new(metastorev1.AddBlockResponse), nil)
+
+	awaiter := sw.ingest(0, func(head segmentIngest) {
+		p := cpuProfile(42, 480, "svc1", "foo", "bar")
+		head.ingest(context.Background(), "t1", p.Profile, p.UUID, p.Labels)
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, awaiter.waitFlushed(ctx))
+}
+
+func TestShardMemoryLimit(t *testing.T) {
+	sw := newTestSegmentWriter(t, segmentWriterConfig{
+		segmentDuration:    100 * time.Millisecond,
+		maxInFlightFlushes: 1,
+		shardMaxMemory:     1,
+	})
+	defer sw.Stop()
+	release := make(chan struct{})
+	sw.client.On("AddBlock", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(mock.Arguments) { <-release }).
+		Return(new(metastorev1.AddBlockResponse), nil)
+
+	require.NoError(t, sw.checkLimits(0))
+	awaiter := sw.ingest(0, func(head segmentIngest) {
+		p := cpuProfile(42, 480, "svc1", "foo", "bar")
+		head.ingest(context.Background(), "t1", p.Profile, p.UUID, p.Labels)
+	})
+	require.ErrorIs(t, sw.checkLimits(0), ErrShardMemoryLimit)
+	require.NoError(t, sw.checkLimits(1))
+
+	close(release)
+	require.NoError(t, awaiter.waitFlushed(context.Background()))
+	require.Eventually(t, func() bool {
+		return sw.checkLimits(0) == nil
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
 func TestBusyIngestLoop(t *testing.T) {
 
 	sw := newTestSegmentWriter(t, segmentWriterConfig{
@@ -40,6 +40,10 @@ type Config struct {
 	LifecyclerConfig ring.LifecyclerConfig `yaml:"lifecycler,omitempty"`
 	SegmentDuration  time.Duration         `yaml:"segmentDuration,omitempty"`
 	Async            bool                  `yaml:"async,omitempty"` //todo make it pertenant
+
+	SegmentMaxSizeBytes int `yaml:"segment_max_size_bytes,omitempty"`
+	MaxInFlightFlushes  int `yaml:"max_in_flight_flushes,omitempty"`
+	ShardMaxMemoryBytes int `yaml:"shard_max_memory_bytes,omitempty"`
 }
 
 // RegisterFlags registers the flags.
@@ -49,10 +53,15 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	cfg.LifecyclerConfig.RegisterFlagsWithPrefix(prefix+".", f, util.Logger)
 	f.DurationVar(&cfg.SegmentDuration, prefix+".segment.duration", 500*time.Millisecond, "Timeout when flushing segments to bucket.")
 	f.BoolVar(&cfg.Async, prefix+".async", false, "Enable async mode for segment writer.")
+	f.IntVar(&cfg.SegmentMaxSizeBytes, prefix+".segment.max-size-bytes", 64<<20, "The segment is flushed before the segment duration elapses, if the size of the data ingested into it reaches the limit. 0 to disable.")
+	f.IntVar(&cfg.MaxInFlightFlushes, prefix+".max-in-flight-flushes", 32, "Maximum number of segments flushed concurrently. Segments exceeding the limit wait for the in-flight flushes to complete. 0 to disable.")
+	f.IntVar(&cfg.ShardMaxMemoryBytes, prefix+".shard.max-memory-bytes", 256<<20, "Maximum size of the data held by a shard, which has not been flushed yet. Push requests are rejected with a retryable error when the limit is reached. 0 to disable.")
 }
 
 func (cfg *Config) Validate() error {
-	// TODO(kolesnikovae): implement.
+	if cfg.SegmentMaxSizeBytes < 0 || cfg.MaxInFlightFlushes < 0 || cfg.ShardMaxMemoryBytes < 0 {
+		return errors.New("segment writer limits must not be negative")
+	}
 	if err := cfg.LifecyclerConfig.Validate(); err != nil {
 		return err
 	}
@@ -131,7 +140,12 @@ func New(
 	}
 	segmentMetrics := newSegmentMetrics(i.reg)
 	headMetrics := memdb.NewHeadMetricsWithPrefix(reg, "pyroscope_segment_writer")
-	config := segmentWriterConfig{segmentDuration: cfg.SegmentDuration}
+	config := segmentWriterConfig{
+		segmentDuration:    cfg.SegmentDuration,
+		segmentMaxSize:     int64(cfg.SegmentMaxSizeBytes),
+		maxInFlightFlushes: cfg.MaxInFlightFlushes,
+		shardMaxMemory:     int64(cfg.ShardMaxMemoryBytes),
+	}
 	i.segmentWriter = newSegmentWriter(i.logger, segmentMetrics, headMetrics, config, lim, storageBucket, metastoreClient)
 	i.subservicesWatcher = services.NewFailureWatcher()
 	i.subservicesWatcher.WatchManager(i.subservices)
@@ -172,6 +186,12 @@ func (i *SegmentWriterService) Push(ctx context.Context, req *segmentwriterv1.Pu
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
+	// The error is retryable: the distributor sends the
+	// request to another instance.
+	if err = i.segmentWriter.checkLimits(shardKey(req.Shard)); err != nil {
+		i.segmentWriter.metrics.rejectedPushes.WithLabelValues(req.TenantId).Inc()
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
 	wait := i.segmentWriter.ingest(shardKey(req.Shard), func(segment segmentIngest) {
 		segment.ingest(ctx, req.TenantId, p.Profile, id, req.Labels)
 	})
@@ -9,6 +9,7 @@ import (
 	"github.com/hashicorp/raft"
 	"io"
 	"sync"
+	"time"
 
 	"github.com/grafana/dskit/grpcclient"
 	"github.com/grafana/dskit/services"
@@ -42,6 +43,9 @@ type client struct {
 	compactorv1.CompactionPlannerClient
 	conn io.Closer
 	srv  discovery.Server
+	// The last time the instance failed to serve a request with
+	// codes.Unavailable. Guarded by the Client mutex.
+	failedAt time.Time
 }
 
 // todo
@@ -11,6 +11,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"sync"
 	"testing"
 )
@@ -115,3 +117,45 @@ func testRediscoverWrongLeader(t *testing.T, f func(c *Client)) {
 	f(c)
 	verify()
 }
+
+func TestFollowerReads(t *testing.T) {
+	d := mockdiscovery.NewMockDiscovery(t)
+	d.On("Subscribe", mock.Anything).Return()
+	d.On("ServerError", mock.Anything).Maybe().Return()
+	l := testutil.NewLogger(t)
+	config := &grpcclient.Config{}
+	flagext.DefaultValues(config)
+	c := New(l, *config, d)
+	ports, err := getFreePorts(nServers)
+	require.NoError(t, err)
+	servers := createMockServers(t, l, ports)
+	defer servers.Close()
+	c.updateServers(createServers(ports))
+	c.leader = testServerId(0)
+
+	var m sync.Mutex
+	calls := make(map[int]int)
+	for _, srv := range servers.servers {
+		srv := srv
+		srv.metastore.On("QueryMetadata", mock.Anything, mock.Anything).Maybe().Return(func(context.Context, *metastorev1.QueryMetadataRequest) (*metastorev1.QueryMetadataResponse, error) {
+			m.Lock()
+			calls[srv.index]++
+			m.Unlock()
+			if srv.index != 0 {
+				return nil, status.Error(codes.Unavailable, "not caught up")
+			}
+			return &metastorev1.QueryMetadataResponse{}, nil
+		})
+	}
+
+	for i := 0; i < 20; i++ {
+		res, err := c.QueryMetadata(context.Background(), &metastorev1.QueryMetadataRequest{})
+		require.NoError(t, err)
+		require.NotNil(t, res)
+	}
+	// Failed followers are not used for reads for a while,
+	// and the requests fall back to the leader.
+	assert.LessOrEqual(t, calls[1], 1)
+	assert.LessOrEqual(t, calls[2], 1)
+	assert.GreaterOrEqual(t, calls[0], 20)
+}
@@ -14,6 +14,10 @@ import (
 	"time"
 )
 
+// unhealthyPeriod is the period an instance is not used for reads after
+// it failed to serve a request.
+const unhealthyPeriod = 10 * time.Second
+
 func invoke[R any](ctx context.Context, cl *Client,
 	f func(ctx context.Context, instance instance) (*R, error),
 ) (*R, error) {
@@ -41,35 +45,76 @@ func invoke[R any](ctx context.Context, cl *Client,
 		if err == nil {
 			return res, nil
 		}
-		cl.logger.Log(
-			"msg", "metastore client error",
-			"err", err,
-			"server_id", it.srv.Raft.ID,
-			"server_address", it.srv.Raft.Address,
-			"server_resolved_laddress", it.srv.ResolvedAddress,
-		)
-		s, ok := status.FromError(err)
-		if ok && s.Code() == codes.Unavailable {
-			ds := s.Details()
-			detailsLeader := ""
-			if len(ds) > 0 {
-				for _, d := range ds {
-					if rd, ok := d.(*typesv1.RaftDetails); ok {
-						detailsLeader = rd.Leader
-						break
-					}
+		cl.handleError(it, err)
+		time.Sleep(backoff)
+	}
+	return nil, fmt.Errorf("metastore client retries failed")
+}
+
+// invokeRead calls a read-only method on a random healthy instance: any
+// node, including followers, serves reads consistent with the leader. If
+// the call fails, the request is retried with the leader.
+func invokeRead[R any](ctx context.Context, cl *Client,
+	f func(ctx context.Context, instance instance) (*R, error),
+) (*R, error) {
+	if it := cl.selectReadInstance(); it != nil {
+		res, err := f(ctx, it)
+		if err == nil {
+			return res, nil
+		}
+		if status.Code(err) == codes.InvalidArgument {
+			return nil, err
+		}
+		cl.handleError(it, err)
+	}
+	return invoke(ctx, cl, f)
+}
+
+func (c *Client) handleError(it *client, err error) {
+	c.logger.Log(
+		"msg", "metastore client error",
+		"err", err,
+		"server_id", it.srv.Raft.ID,
+		"server_address", it.srv.Raft.Address,
+		"server_resolved_laddress", it.srv.ResolvedAddress,
+	)
+	s, ok := status.FromError(err)
+	if ok && s.Code() == codes.Unavailable {
+		ds := s.Details()
+		detailsLeader := ""
+		if len(ds) > 0 {
+			for _, d := range ds {
+				if rd, ok := d.(*typesv1.RaftDetails); ok {
+					detailsLeader = rd.Leader
+					break
 				}
 			}
-			cl.mu.Lock()
-			if cl.leader == it.srv.Raft.ID {
-				cl.leader = raft.ServerID(detailsLeader)
-			}
-			cl.mu.Unlock()
-			cl.discovery.ServerError(it.srv)
 		}
-		time.Sleep(backoff)
+		c.mu.Lock()
+		if c.leader == it.srv.Raft.ID {
+			c.leader = raft.ServerID(detailsLeader)
+		}
+		it.failedAt = time.Now()
+		c.mu.Unlock()
+		c.discovery.ServerError(it.srv)
 	}
-	return nil, fmt.Errorf("metastore client retries failed")
+}
+
+// selectReadInstance returns a random instance, excluding
+// the ones that failed recently.
+func (c *Client) selectReadInstance() *client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	healthy := make([]*client, 0, len(c.servers))
+	for _, v := range c.servers {
+		if time.Since(v.failedAt) > unhealthyPeriod {
+			healthy = append(healthy, v)
+		}
+	}
+	if len(healthy) == 0 {
+		return nil
+	}
+	return healthy[rand.Intn(len(healthy))]
 }
 
 func (c *Client) selectInstance() *client {
@@ -98,7 +143,7 @@ func (c *Client) AddBlock(ctx context.Context, in *metastorev1.AddBlockRequest,
 }
 
 func (c *Client) QueryMetadata(ctx context.Context, in *metastorev1.QueryMetadataRequest, opts ...grpc.CallOption) (*metastorev1.QueryMetadataResponse, error) {
-	return invoke(ctx, c, func(ctx context.Context, instance instance) (*metastorev1.QueryMetadataResponse, error) {
+	return invokeRead(ctx, c, func(ctx context.Context, instance instance) (*metastorev1.QueryMetadataResponse, error) {
 		return instance.QueryMetadata(ctx, in, opts...)
 	})
 }
@@ -122,7 +167,7 @@ func (c *Client) GetCompactionJobs(ctx context.Context, in *compactorv1.GetCompa
 }
 
 func (c *Client) GetProfileStats(ctx context.Context, in *metastorev1.GetProfileStatsRequest, opts ...grpc.CallOption) (*typesv1.GetProfileStatsResponse, error) {
-	return invoke(ctx, c, func(ctx context.Context, instance instance) (*typesv1.GetProfileStatsResponse, error) {
+	return invokeRead(ctx, c, func(ctx context.Context, instance instance) (*typesv1.GetProfileStatsResponse, error) {
 		return instance.GetProfileStats(ctx, in, opts...)
 	})
 }
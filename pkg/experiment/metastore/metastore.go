@@ -21,6 +21,7 @@ import (
 	raftwal "github.com/hashicorp/raft-wal"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/thanos-io/objstore"
 
 	compactorv1 "github.com/grafana/pyroscope/api/gen/proto/go/compactor/v1"
 	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
@@ -42,11 +43,13 @@ const (
 )
 
 type Config struct {
-	Address          string            `yaml:"address"`
-	GRPCClientConfig grpcclient.Config `yaml:"grpc_client_config" doc:"description=Configures the gRPC client used to communicate with the metastore."`
-	DataDir          string            `yaml:"data_dir"`
-	Raft             RaftConfig        `yaml:"raft"`
-	Compaction       CompactionConfig  `yaml:"compaction_config"`
+	Address          string               `yaml:"address"`
+	GRPCClientConfig grpcclient.Config    `yaml:"grpc_client_config" doc:"description=Configures the gRPC client used to communicate with the metastore."`
+	DataDir          string               `yaml:"data_dir"`
+	Raft             RaftConfig           `yaml:"raft"`
+	Compaction       CompactionConfig     `yaml:"compaction_config"`
+	BlockCleaner     BlockCleanerConfig   `yaml:"block_cleaner"`
+	SnapshotBackup   SnapshotBackupConfig `yaml:"snapshot_backup"`
 }
 
 type RaftConfig struct {
@@ -55,6 +58,11 @@ type RaftConfig struct {
 	BootstrapPeers       []string `yaml:"bootstrap_peers"`
 	BootstrapExpectPeers int      `yaml:"bootstrap_expect_peers"`
 
+	// SkipBootstrap prevents the node from bootstrapping the cluster,
+	// if no state is found: the node is to join an existing cluster,
+	// and is added with the operator API.
+	SkipBootstrap bool `yaml:"skip_bootstrap"`
+
 	ServerID         string `yaml:"server_id"`
 	BindAddress      string `yaml:"bind_address"`
 	AdvertiseAddress string `yaml:"advertise_address"`
@@ -69,6 +77,8 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	f.StringVar(&cfg.DataDir, prefix+"data-dir", "./data-metastore/data", "")
 	cfg.Raft.RegisterFlagsWithPrefix(prefix+"raft.", f)
 	cfg.Compaction.RegisterFlagsWithPrefix(prefix+"compaction.", f)
+	cfg.BlockCleaner.RegisterFlagsWithPrefix(prefix+"block-cleaner.", f)
+	cfg.SnapshotBackup.RegisterFlagsWithPrefix(prefix+"snapshot-backup.", f)
 }
 
 func (cfg *Config) Validate() error {
@@ -78,6 +88,12 @@ func (cfg *Config) Validate() error {
 	if err := cfg.GRPCClientConfig.Validate(); err != nil {
 		return err
 	}
+	if err := cfg.BlockCleaner.Validate(); err != nil {
+		return err
+	}
+	if err := cfg.SnapshotBackup.Validate(); err != nil {
+		return err
+	}
 	return cfg.Raft.Validate()
 }
 
@@ -85,6 +101,7 @@ func (cfg *RaftConfig) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
 	f.StringVar(&cfg.Dir, prefix+"dir", "./data-metastore/raft", "")
 	f.Var((*flagext.StringSlice)(&cfg.BootstrapPeers), prefix+"bootstrap-peers", "")
 	f.IntVar(&cfg.BootstrapExpectPeers, prefix+"bootstrap-expect-peers", 1, "Expected number of peers including the local node.")
+	f.BoolVar(&cfg.SkipBootstrap, prefix+"skip-bootstrap", false, "Do not bootstrap the cluster if no state is found. The node is to be added to an existing cluster with the operator API.")
 	f.StringVar(&cfg.BindAddress, prefix+"bind-address", "localhost:9099", "")
 	f.StringVar(&cfg.ServerID, prefix+"server-id", "localhost:9099", "")
 	f.StringVar(&cfg.AdvertiseAddress, prefix+"advertise-address", "localhost:9099", "")
@@ -113,12 +130,20 @@ type Metastore struct {
 	// Persistent state.
 	db *boltdb
 
+	// Deletes the objects of the blocks removed from
+	// the metastore. Nil, if the bucket is not configured.
+	blockCleaner *blockCleaner
+	// Uploads raft snapshots to the object storage, and restores
+	// the state from there. Nil, if the bucket is not configured.
+	snapshotBackup *snapshotBackup
+
 	// Raft module.
 	wal          *raftwal.WAL
 	snapshots    *raft.FileSnapshotStore
 	transport    *raft.NetworkTransport
 	raft         *raft.Raft
 	leaderhealth *raftleader.HealthObserver //todo remove
+	peers        *raftPeerHealth
 
 	logStore      raft.LogStore
 	stableStore   raft.StableStore
@@ -137,7 +162,14 @@ type Metastore struct {
 
 type Limits interface{}
 
-func New(config Config, limits Limits, logger log.Logger, reg prometheus.Registerer, client *metastoreclient.Client) (*Metastore, error) {
+func New(
+	config Config,
+	limits Limits,
+	logger log.Logger,
+	reg prometheus.Registerer,
+	client *metastoreclient.Client,
+	bucket objstore.Bucket,
+) (*Metastore, error) {
 	metrics := newMetastoreMetrics(reg)
 	m := &Metastore{
 		config:  config,
@@ -151,6 +183,10 @@ func New(config Config, limits Limits, logger log.Logger, reg prometheus.Registe
 	}
 	m.leaderhealth = raftleader.NewRaftLeaderHealthObserver(logger, raftleader.NewMetrics(reg))
 	m.state = newMetastoreState(logger, m.db, m.reg, &config.Compaction)
+	if bucket != nil {
+		m.blockCleaner = newBlockCleaner(&m.config.BlockCleaner, logger, bucket, m.state, reg, m.cleanTombstones)
+		m.snapshotBackup = newSnapshotBackup(&m.config.SnapshotBackup, logger, bucket, reg)
+	}
 	m.service = services.NewBasicService(m.starting, m.running, m.stopping)
 	return m, nil
 }
@@ -172,6 +208,14 @@ func (m *Metastore) starting(context.Context) error {
 	}
 	m.wg.Add(1)
 	go m.cleanupLoop()
+	if m.blockCleaner != nil {
+		m.wg.Add(1)
+		go m.blockCleanerLoop()
+	}
+	if m.snapshotBackup != nil && m.config.SnapshotBackup.Interval > 0 {
+		m.wg.Add(1)
+		go m.snapshotBackupLoop()
+	}
 	return nil
 }
 
@@ -209,6 +253,21 @@ func (m *Metastore) initRaft() (err error) {
 		return err
 	}
 
+	// The node that bootstraps the cluster may restore the state from
+	// the object storage, before raft is started.
+	var bootstrap *raft.Configuration
+	var restored bool
+	if !hasState && !m.config.Raft.SkipBootstrap {
+		if bootstrap, err = m.bootstrapConfiguration(); err != nil {
+			return fmt.Errorf("failed to bootstrap cluster: %w", err)
+		}
+		if bootstrap != nil && m.snapshotBackup != nil {
+			if restored, err = m.restoreFromBucket(*bootstrap); err != nil {
+				return fmt.Errorf("failed to restore state from object storage: %w", err)
+			}
+		}
+	}
+
 	config := raft.DefaultConfig()
 	// TODO: Wrap gokit
 	//	config.Logger
@@ -224,13 +283,23 @@ func (m *Metastore) initRaft() (err error) {
 		return fmt.Errorf("starting raft node: %w", err)
 	}
 
-	if !hasState {
+	m.peers = newRaftPeerHealth()
+	m.peers.register(m.raft)
+
+	switch {
+	case hasState:
+		_ = level.Info(m.logger).Log("msg", "restoring existing state, not bootstraping")
+	case m.config.Raft.SkipBootstrap:
+		_ = level.Info(m.logger).Log("msg", "no existing state found, waiting to join the cluster")
+	case restored:
+		_ = level.Info(m.logger).Log("msg", "state restored from object storage, not bootstrapping")
+	case bootstrap == nil:
+		_ = level.Info(m.logger).Log("msg", "no existing state found, not the bootstrap node")
+	default:
 		_ = level.Warn(m.logger).Log("msg", "no existing state found, trying to bootstrap cluster")
-		if err = m.bootstrap(); err != nil {
+		if err = m.bootstrap(*bootstrap); err != nil {
 			return fmt.Errorf("failed to bootstrap cluster: %w", err)
 		}
-	} else {
-		_ = level.Info(m.logger).Log("msg", "restoring existing state, not bootstraping")
 	}
 
 	m.leaderhealth.Register(m.raft, metastoreRaftLeaderHealthServiceName)
@@ -288,6 +357,9 @@ func (m *Metastore) shutdownRaft() {
 			}
 		}
 		m.leaderhealth.Deregister(m.raft, metastoreRaftLeaderHealthServiceName)
+		if m.peers != nil {
+			m.peers.deregister()
+		}
 		if err := m.raft.Shutdown().Error(); err != nil {
 			_ = level.Error(m.logger).Log("msg", "failed to shutdown raft", "err", err)
 		}
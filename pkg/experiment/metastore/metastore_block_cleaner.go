@@ -0,0 +1,420 @@
+package metastore
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/concurrency"
+	"github.com/hashicorp/raft"
+	"github.com/oklog/ulid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/thanos-io/objstore"
+	"go.etcd.io/bbolt"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/compactionpb"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/raftlogpb"
+	"github.com/grafana/pyroscope/pkg/experiment/query_backend/block"
+	"github.com/grafana/pyroscope/pkg/util"
+)
+
+// Blocks removed from the metastore as a result of compaction are not
+// deleted from the object storage immediately: a tombstone is added to
+// the deletion queue, which is replicated with raft as part of the state.
+// Once the deletion delay has passed, the leader deletes the objects and
+// removes the tombstones with the CleanBlocksCommand.
+//
+// In addition, the leader periodically reconciles the object storage with
+// the metastore: objects that are neither referenced by the metastore nor
+// by a tombstone are considered orphaned. Such objects may be left behind,
+// for example, if a segment writer or a compaction worker fails after the
+// object is uploaded, but before the metastore is updated.
+
+const blockCleanerDeleteConcurrency = 16
+
+type BlockCleanerConfig struct {
+	DeletionDelay          time.Duration `yaml:"deletion_delay"`
+	CleanupInterval        time.Duration `yaml:"cleanup_interval"`
+	CleanupBatchSize       int           `yaml:"cleanup_batch_size"`
+	ReconciliationInterval time.Duration `yaml:"reconciliation_interval"`
+	OrphanGracePeriod      time.Duration `yaml:"orphan_grace_period"`
+	DeleteOrphans          bool          `yaml:"delete_orphans"`
+	DryRun                 bool          `yaml:"dry_run"`
+}
+
+func (cfg *BlockCleanerConfig) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	f.DurationVar(&cfg.DeletionDelay, prefix+"deletion-delay", 15*time.Minute, "Delay before the objects of the blocks removed by compaction are deleted from the object storage. Queries in flight may still access the blocks during this period.")
+	f.DurationVar(&cfg.CleanupInterval, prefix+"cleanup-interval", time.Minute, "How often the leader deletes the objects of the removed blocks.")
+	f.IntVar(&cfg.CleanupBatchSize, prefix+"cleanup-batch-size", 1000, "Maximum number of objects deleted in a cleanup cycle.")
+	f.DurationVar(&cfg.ReconciliationInterval, prefix+"reconciliation-interval", time.Hour, "How often the leader looks for objects not referenced by the metastore. 0 to disable.")
+	f.DurationVar(&cfg.OrphanGracePeriod, prefix+"orphan-grace-period", time.Hour, "Objects not referenced by the metastore are only considered orphaned if they were created before this period.")
+	f.BoolVar(&cfg.DeleteOrphans, prefix+"delete-orphans", false, "Delete the orphaned objects found during reconciliation. If disabled, the objects are only reported.")
+	f.BoolVar(&cfg.DryRun, prefix+"dry-run", false, "Log the objects that would be deleted, without deleting them.")
+}
+
+func (cfg *BlockCleanerConfig) Validate() error {
+	if cfg.CleanupInterval <= 0 {
+		return fmt.Errorf("block cleaner cleanup interval must be greater than 0")
+	}
+	if cfg.CleanupBatchSize <= 0 {
+		return fmt.Errorf("block cleaner cleanup batch size must be greater than 0")
+	}
+	return nil
+}
+
+type blockCleanerMetrics struct {
+	tombstones      prometheus.Gauge
+	deletedObjects  *prometheus.CounterVec
+	deleteFailures  prometheus.Counter
+	orphanedObjects prometheus.Gauge
+}
+
+func newBlockCleanerMetrics(reg prometheus.Registerer) *blockCleanerMetrics {
+	m := &blockCleanerMetrics{
+		tombstones: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "pyroscope",
+			Name:      "metastore_block_cleaner_tombstones",
+			Help:      "The number of blocks pending deletion from the object storage",
+		}),
+		deletedObjects: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "pyroscope",
+			Name:      "metastore_block_cleaner_deleted_objects_total",
+			Help:      "The number of objects deleted from the object storage",
+		}, []string{"reason"}),
+		deleteFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "pyroscope",
+			Name:      "metastore_block_cleaner_delete_failures_total",
+			Help:      "The number of objects that could not be deleted from the object storage",
+		}),
+		orphanedObjects: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "pyroscope",
+			Name:      "metastore_block_cleaner_orphaned_objects",
+			Help:      "The number of orphaned objects found during the last reconciliation",
+		}),
+	}
+	if reg != nil {
+		util.RegisterOrGet(reg, m.tombstones)
+		util.RegisterOrGet(reg, m.deletedObjects)
+		util.RegisterOrGet(reg, m.deleteFailures)
+		util.RegisterOrGet(reg, m.orphanedObjects)
+	}
+	return m
+}
+
+const (
+	deletionReasonCompacted = "compacted"
+	deletionReasonOrphaned  = "orphaned"
+)
+
+type blockCleaner struct {
+	config  *BlockCleanerConfig
+	logger  log.Logger
+	bucket  objstore.Bucket
+	state   *metastoreState
+	metrics *blockCleanerMetrics
+	// cleanTombstones removes the tombstones from the replicated state.
+	cleanTombstones func(ctx context.Context, paths []string) error
+}
+
+func newBlockCleaner(
+	config *BlockCleanerConfig,
+	logger log.Logger,
+	bucket objstore.Bucket,
+	state *metastoreState,
+	reg prometheus.Registerer,
+	cleanTombstones func(context.Context, []string) error,
+) *blockCleaner {
+	return &blockCleaner{
+		config:          config,
+		logger:          logger,
+		bucket:          bucket,
+		state:           state,
+		metrics:         newBlockCleanerMetrics(reg),
+		cleanTombstones: cleanTombstones,
+	}
+}
+
+// cleanup deletes the objects of the blocks which tombstones are older
+// than the deletion delay, and removes the tombstones.
+func (c *blockCleaner) cleanup(ctx context.Context, now time.Time) error {
+	before := now.Add(-c.config.DeletionDelay).UnixNano()
+	tombstones := c.state.expiredTombstones(before, c.config.CleanupBatchSize)
+	if len(tombstones) == 0 {
+		return nil
+	}
+	if c.config.DryRun {
+		for _, t := range tombstones {
+			level.Info(c.logger).Log("msg", "dry run: would delete block", "path", t.Path, "compaction_job", t.CompactionJob)
+		}
+		return nil
+	}
+	paths := make([]string, len(tombstones))
+	for i, t := range tombstones {
+		paths[i] = t.Path
+	}
+	deleted := c.deleteObjects(ctx, paths, deletionReasonCompacted)
+	if len(deleted) == 0 {
+		return nil
+	}
+	if err := c.cleanTombstones(ctx, deleted); err != nil {
+		// The objects will be deleted again in the next cycle,
+		// which is a no-op for objects that do not exist.
+		return fmt.Errorf("failed to remove tombstones: %w", err)
+	}
+	level.Info(c.logger).Log("msg", "deleted compacted blocks", "count", len(deleted), "pending", len(tombstones)-len(deleted))
+	return nil
+}
+
+// reconcile finds the objects that are not referenced by the metastore.
+func (c *blockCleaner) reconcile(ctx context.Context, now time.Time) error {
+	before := now.Add(-c.config.OrphanGracePeriod)
+	var orphans []string
+	for _, dir := range []string{block.DirPathSegment, block.DirPathBlock} {
+		err := c.bucket.Iter(ctx, dir, func(path string) error {
+			shard, id, ok := parseObjectPath(path)
+			if !ok {
+				return nil
+			}
+			created, err := ulid.Parse(id)
+			if err != nil || ulid.Time(created.Time()).After(before) {
+				// The object might be uploaded, but not yet added to the metastore.
+				return nil
+			}
+			if c.state.hasBlock(shard, id) || c.state.hasTombstone(path) {
+				return nil
+			}
+			orphans = append(orphans, path)
+			return nil
+		}, objstore.WithRecursiveIter)
+		if err != nil {
+			return fmt.Errorf("failed to list %s: %w", dir, err)
+		}
+	}
+	c.metrics.orphanedObjects.Set(float64(len(orphans)))
+	if len(orphans) == 0 {
+		return nil
+	}
+	level.Warn(c.logger).Log("msg", "found orphaned objects", "count", len(orphans))
+	if c.config.DryRun || !c.config.DeleteOrphans {
+		for _, path := range orphans {
+			level.Info(c.logger).Log("msg", "orphaned object", "path", path)
+		}
+		return nil
+	}
+	deleted := c.deleteObjects(ctx, orphans, deletionReasonOrphaned)
+	level.Info(c.logger).Log("msg", "deleted orphaned objects", "count", len(deleted))
+	return nil
+}
+
+// deleteObjects deletes the objects and returns the paths of the objects
+// that have been deleted or do not exist.
+func (c *blockCleaner) deleteObjects(ctx context.Context, paths []string, reason string) []string {
+	var mu sync.Mutex
+	deleted := make([]string, 0, len(paths))
+	_ = concurrency.ForEachJob(ctx, len(paths), blockCleanerDeleteConcurrency, func(ctx context.Context, i int) error {
+		path := paths[i]
+		if err := c.bucket.Delete(ctx, path); err != nil && !c.bucket.IsObjNotFoundErr(err) {
+			level.Warn(c.logger).Log("msg", "failed to delete object", "path", path, "err", err)
+			c.metrics.deleteFailures.Inc()
+			return nil
+		}
+		c.metrics.deletedObjects.WithLabelValues(reason).Inc()
+		mu.Lock()
+		deleted = append(deleted, path)
+		mu.Unlock()
+		return nil
+	})
+	return deleted
+}
+
+// parseObjectPath parses the path of a block object created with
+// block.ObjectPath: {segments|blocks}/{shard}/{tenant}/{block_id}/block.bin
+func parseObjectPath(path string) (shard uint32, id string, ok bool) {
+	parts := strings.Split(path, "/")
+	if len(parts) != 5 || parts[4] != block.FileNameDataObject {
+		return 0, "", false
+	}
+	s, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return 0, "", false
+	}
+	return uint32(s), parts[3], true
+}
+
+func (m *Metastore) blockCleanerLoop() {
+	cleanup := time.NewTicker(m.config.BlockCleaner.CleanupInterval)
+	var reconcile <-chan time.Time
+	if interval := m.config.BlockCleaner.ReconciliationInterval; interval > 0 {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		reconcile = t.C
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() {
+		cancel()
+		cleanup.Stop()
+		m.wg.Done()
+	}()
+	go func() {
+		select {
+		case <-m.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	for {
+		select {
+		case <-m.done:
+			return
+		case <-cleanup.C:
+			m.blockCleaner.metrics.tombstones.Set(float64(m.state.tombstonesCount()))
+			if m.raft.State() != raft.Leader {
+				continue
+			}
+			if err := m.blockCleaner.cleanup(ctx, time.Now()); err != nil {
+				_ = level.Error(m.logger).Log("msg", "block cleanup failed", "err", err)
+			}
+		case <-reconcile:
+			if m.raft.State() != raft.Leader {
+				continue
+			}
+			// Make sure the state includes all the committed entries.
+			if err := m.raft.Barrier(m.config.Raft.ApplyTimeout).Error(); err != nil {
+				_ = level.Error(m.logger).Log("msg", "failed to reconcile blocks", "err", err)
+				continue
+			}
+			if err := m.blockCleaner.reconcile(ctx, time.Now()); err != nil {
+				_ = level.Error(m.logger).Log("msg", "failed to reconcile blocks", "err", err)
+			}
+		}
+	}
+}
+
+func (m *Metastore) cleanTombstones(_ context.Context, paths []string) error {
+	req := &raftlogpb.CleanBlocksCommand{Paths: paths}
+	_, _, err := applyCommand[*raftlogpb.CleanBlocksCommand, *anypb.Any](m.raft, req, m.config.Raft.ApplyTimeout)
+	return err
+}
+
+func (m *metastoreState) applyCleanBlocks(_ *raft.Log, request *raftlogpb.CleanBlocksCommand) (*anypb.Any, error) {
+	m.tombstonesMutex.Lock()
+	defer m.tombstonesMutex.Unlock()
+	err := m.db.boltdb.Update(func(tx *bbolt.Tx) error {
+		b, err := getBlockTombstoneBucket(tx)
+		if err != nil {
+			return err
+		}
+		for _, path := range request.Paths {
+			if err = b.Delete([]byte(path)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		panic(fatalCommandError{fmt.Errorf("error removing block tombstones from db, %w", err)})
+	}
+	for _, path := range request.Paths {
+		delete(m.tombstones, path)
+	}
+	return &anypb.Any{}, nil
+}
+
+func newBlockTombstone(b *metastorev1.BlockMeta, job string, deletedAt int64) *compactionpb.BlockTombstone {
+	return &compactionpb.BlockTombstone{
+		Path:            block.ObjectPath(b),
+		BlockId:         b.Id,
+		Shard:           b.Shard,
+		TenantId:        b.TenantId,
+		CompactionLevel: b.CompactionLevel,
+		CompactionJob:   job,
+		DeletedAt:       deletedAt,
+	}
+}
+
+func (m *metastoreState) addTombstone(t *compactionpb.BlockTombstone) {
+	m.tombstonesMutex.Lock()
+	m.tombstones[t.Path] = t
+	m.tombstonesMutex.Unlock()
+}
+
+func (m *metastoreState) hasTombstone(path string) bool {
+	m.tombstonesMutex.Lock()
+	defer m.tombstonesMutex.Unlock()
+	_, ok := m.tombstones[path]
+	return ok
+}
+
+func (m *metastoreState) tombstonesCount() int {
+	m.tombstonesMutex.Lock()
+	defer m.tombstonesMutex.Unlock()
+	return len(m.tombstones)
+}
+
+// expiredTombstones returns up to max oldest tombstones
+// created before the given time (unix nanoseconds).
+func (m *metastoreState) expiredTombstones(before int64, max int) []*compactionpb.BlockTombstone {
+	m.tombstonesMutex.Lock()
+	expired := make([]*compactionpb.BlockTombstone, 0, len(m.tombstones))
+	for _, t := range m.tombstones {
+		if t.DeletedAt < before {
+			expired = append(expired, t)
+		}
+	}
+	m.tombstonesMutex.Unlock()
+	slices.SortFunc(expired, func(a, b *compactionpb.BlockTombstone) int {
+		if c := cmp.Compare(a.DeletedAt, b.DeletedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Path, b.Path)
+	})
+	if len(expired) > max {
+		expired = expired[:max]
+	}
+	return expired
+}
+
+// hasBlock reports whether the block is present in the metastore.
+func (m *metastoreState) hasBlock(shard uint32, blockID string) bool {
+	m.shardsMutex.Lock()
+	s, ok := m.shards[shard]
+	m.shardsMutex.Unlock()
+	if !ok {
+		return false
+	}
+	s.segmentsMutex.Lock()
+	defer s.segmentsMutex.Unlock()
+	_, ok = s.segments[blockID]
+	return ok
+}
+
+func (m *metastoreState) restoreTombstones(tx *bbolt.Tx) error {
+	b, err := getBlockTombstoneBucket(tx)
+	if err != nil {
+		if errors.Is(err, bbolt.ErrBucketNotFound) {
+			return nil
+		}
+		return err
+	}
+	m.tombstonesMutex.Lock()
+	defer m.tombstonesMutex.Unlock()
+	return b.ForEach(func(k, v []byte) error {
+		var t compactionpb.BlockTombstone
+		if err := t.UnmarshalVT(v); err != nil {
+			return fmt.Errorf("failed to unmarshal block tombstone %q: %w", string(k), err)
+		}
+		m.tombstones[t.Path] = &t
+		return nil
+	})
+}
@@ -0,0 +1,167 @@
+package metastore
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/raft"
+	"github.com/oklog/ulid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+
+	compactorv1 "github.com/grafana/pyroscope/api/gen/proto/go/compactor/v1"
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/raftlogpb"
+	"github.com/grafana/pyroscope/pkg/experiment/query_backend/block"
+	"github.com/grafana/pyroscope/pkg/util"
+)
+
+func newTestBlockCleaner(m *metastoreState, bucket objstore.Bucket, config BlockCleanerConfig) *blockCleaner {
+	return newBlockCleaner(&config, util.Logger, bucket, m, prometheus.NewRegistry(), func(_ context.Context, paths []string) error {
+		_, err := m.applyCleanBlocks(nil, &raftlogpb.CleanBlocksCommand{Paths: paths})
+		return err
+	})
+}
+
+func uploadBlocks(t *testing.T, bucket objstore.Bucket, blocks ...*metastorev1.BlockMeta) {
+	for _, b := range blocks {
+		require.NoError(t, bucket.Upload(context.Background(), block.ObjectPath(b), bytes.NewReader([]byte(b.Id))))
+	}
+}
+
+func Test_BlockCleaner_CompactedBlocks(t *testing.T) {
+	m := initState(t)
+	bucket := objstore.NewInMemBucket()
+	addLevel0Blocks(m, 20)
+	for _, shard := range m.shards {
+		for _, b := range shard.segments {
+			uploadBlocks(t, bucket, b)
+		}
+	}
+
+	resp, err := m.pollCompactionJobs(&compactorv1.PollCompactionJobsRequest{JobCapacity: 1}, 20, 20)
+	require.NoError(t, err)
+	require.Len(t, resp.CompactionJobs, 1)
+	compacted := createBlock(20, 0, "tenant", 1)
+	uploadBlocks(t, bucket, compacted)
+	deletedAt := time.Unix(0, 21)
+	_, err = m.pollCompactionJobs(&compactorv1.PollCompactionJobsRequest{
+		JobStatusUpdates: []*compactorv1.CompactionJobStatus{{
+			JobName:      resp.CompactionJobs[0].Name,
+			Status:       compactorv1.CompactionStatus_COMPACTION_STATUS_SUCCESS,
+			CompletedJob: &compactorv1.CompletedJob{Blocks: []*metastorev1.BlockMeta{compacted}},
+			RaftLogIndex: 20,
+		}},
+	}, 21, deletedAt.UnixNano())
+	require.NoError(t, err)
+
+	// The source blocks are marked for deletion.
+	require.Equal(t, 20, m.tombstonesCount())
+	assert.True(t, m.hasTombstone(block.ObjectPath(createBlock(0, 0, "", 0))))
+
+	// Tombstones are persisted.
+	restored := newMetastoreState(util.Logger, m.db, prometheus.NewRegistry(), m.compactionConfig)
+	require.NoError(t, restored.restore(m.db))
+	require.Equal(t, 20, restored.tombstonesCount())
+
+	config := BlockCleanerConfig{DeletionDelay: time.Minute, CleanupBatchSize: 15}
+	c := newTestBlockCleaner(m, bucket, config)
+
+	// The deletion delay has not passed yet.
+	require.NoError(t, c.cleanup(context.Background(), deletedAt.Add(time.Second)))
+	assert.Len(t, bucket.Objects(), 21)
+
+	// In dry-run mode, nothing is deleted.
+	c.config.DryRun = true
+	require.NoError(t, c.cleanup(context.Background(), deletedAt.Add(time.Hour)))
+	assert.Len(t, bucket.Objects(), 21)
+	assert.Equal(t, 20, m.tombstonesCount())
+
+	c.config.DryRun = false
+	require.NoError(t, c.cleanup(context.Background(), deletedAt.Add(time.Hour)))
+	assert.Len(t, bucket.Objects(), 6)
+	assert.Equal(t, 5, m.tombstonesCount())
+	require.NoError(t, c.cleanup(context.Background(), deletedAt.Add(time.Hour)))
+	assert.Equal(t, map[string][]byte{block.ObjectPath(compacted): []byte(compacted.Id)}, bucket.Objects())
+	assert.Equal(t, 0, m.tombstonesCount())
+
+	// The tombstones are removed from the database.
+	restored = newMetastoreState(util.Logger, m.db, prometheus.NewRegistry(), m.compactionConfig)
+	require.NoError(t, restored.restore(m.db))
+	require.Equal(t, 0, restored.tombstonesCount())
+}
+
+func Test_BlockCleaner_Reconcile(t *testing.T) {
+	m := initState(t)
+	bucket := objstore.NewInMemBucket()
+	now := time.Now()
+	newBlock := func(created time.Time, shard uint32, tenant string, level uint32) *metastorev1.BlockMeta {
+		return &metastorev1.BlockMeta{
+			Id:              ulid.MustNew(ulid.Timestamp(created), nil).String(),
+			Shard:           shard,
+			TenantId:        tenant,
+			CompactionLevel: level,
+		}
+	}
+
+	referenced := newBlock(now.Add(-2*time.Hour), 1, "", 0)
+	_, err := m.applyAddBlock(raftLog(1), &metastorev1.AddBlockRequest{Block: referenced})
+	require.NoError(t, err)
+	tombstone := newBlock(now.Add(-3*time.Hour), 1, "tenant", 1)
+	m.addTombstone(newBlockTombstone(tombstone, "job", now.UnixNano()))
+	orphanedSegment := newBlock(now.Add(-4*time.Hour), 2, "", 0)
+	orphanedBlock := newBlock(now.Add(-5*time.Hour), 1, "tenant", 2)
+	recent := newBlock(now.Add(-time.Minute), 3, "", 0)
+	uploadBlocks(t, bucket, referenced, tombstone, orphanedSegment, orphanedBlock, recent)
+	require.NoError(t, bucket.Upload(context.Background(), "blocks/1/tenant/unknown.bin", bytes.NewReader(nil)))
+
+	config := BlockCleanerConfig{OrphanGracePeriod: time.Hour, CleanupBatchSize: 10}
+	c := newTestBlockCleaner(m, bucket, config)
+
+	// Orphaned objects are only reported by default.
+	require.NoError(t, c.reconcile(context.Background(), now))
+	assert.Len(t, bucket.Objects(), 6)
+
+	c.config.DeleteOrphans = true
+	c.config.DryRun = true
+	require.NoError(t, c.reconcile(context.Background(), now))
+	assert.Len(t, bucket.Objects(), 6)
+
+	c.config.DryRun = false
+	require.NoError(t, c.reconcile(context.Background(), now))
+	objects := bucket.Objects()
+	assert.Len(t, objects, 4)
+	assert.NotContains(t, objects, block.ObjectPath(orphanedSegment))
+	assert.NotContains(t, objects, block.ObjectPath(orphanedBlock))
+}
+
+func Test_parseObjectPath(t *testing.T) {
+	b := &metastorev1.BlockMeta{Id: "01J2VJQPYDC160REPAD2VN88XN", Shard: 12, TenantId: "tenant", CompactionLevel: 1}
+	shard, id, ok := parseObjectPath(block.ObjectPath(b))
+	require.True(t, ok)
+	assert.Equal(t, uint32(12), shard)
+	assert.Equal(t, b.Id, id)
+
+	b.CompactionLevel = 0
+	shard, id, ok = parseObjectPath(block.ObjectPath(b))
+	require.True(t, ok)
+	assert.Equal(t, uint32(12), shard)
+	assert.Equal(t, b.Id, id)
+
+	for _, path := range []string{
+		"blocks/12/tenant/01J2VJQPYDC160REPAD2VN88XN/meta.json",
+		"blocks/x/tenant/01J2VJQPYDC160REPAD2VN88XN/block.bin",
+		"blocks/12/01J2VJQPYDC160REPAD2VN88XN/block.bin",
+	} {
+		_, _, ok = parseObjectPath(path)
+		assert.False(t, ok, path)
+	}
+}
+
+func raftLog(index uint64) *raft.Log {
+	return &raft.Log{Index: index, AppendedAt: time.Unix(0, int64(index))}
+}
@@ -76,6 +76,10 @@ func (db *boltdb) open(readOnly bool) (err error) {
 				return err
 			}
 			_, err = tx.CreateBucketIfNotExists(compactionJobBucketNameBytes)
+			if err != nil {
+				return err
+			}
+			_, err = tx.CreateBucketIfNotExists(blockTombstoneBucketNameBytes)
 			return err
 		})
 		if err != nil {
@@ -218,9 +222,11 @@ func getOrCreateSubBucket(parent *bbolt.Bucket, name []byte) (*bbolt.Bucket, err
 
 const blockMetadataBucketName = "block_metadata"
 const compactionJobBucketName = "compaction_job"
+const blockTombstoneBucketName = "block_tombstone"
 
 var blockMetadataBucketNameBytes = []byte(blockMetadataBucketName)
 var compactionJobBucketNameBytes = []byte(compactionJobBucketName)
+var blockTombstoneBucketNameBytes = []byte(blockTombstoneBucketName)
 
 func getBlockMetadataBucket(tx *bbolt.Tx) (*bbolt.Bucket, error) {
 	mdb := tx.Bucket(blockMetadataBucketNameBytes)
@@ -286,3 +292,13 @@ func getCompactionJobBucket(tx *bbolt.Tx) (*bbolt.Bucket, error) {
 	}
 	return cdb, nil
 }
+
+// Bucket          |Key
+// block_tombstone |[object_path]
+func getBlockTombstoneBucket(tx *bbolt.Tx) (*bbolt.Bucket, error) {
+	tdb := tx.Bucket(blockTombstoneBucketNameBytes)
+	if tdb == nil {
+		return nil, bbolt.ErrBucketNotFound
+	}
+	return tdb, nil
+}
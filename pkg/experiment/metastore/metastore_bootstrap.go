@@ -16,10 +16,12 @@ import (
 	"github.com/hashicorp/raft"
 )
 
-func (m *Metastore) bootstrap() error {
+// bootstrapConfiguration returns the initial cluster configuration,
+// if the local node is the one to bootstrap the cluster, or nil.
+func (m *Metastore) bootstrapConfiguration() (*raft.Configuration, error) {
 	peers, err := m.bootstrapPeersWithRetries()
 	if err != nil {
-		return fmt.Errorf("failed to resolve peers: %w", err)
+		return nil, fmt.Errorf("failed to resolve peers: %w", err)
 	}
 	logger := log.With(m.logger,
 		"server_id", m.config.Raft.ServerID,
@@ -28,10 +30,14 @@ func (m *Metastore) bootstrap() error {
 	lastPeer := peers[len(peers)-1]
 	if raft.ServerAddress(m.config.Raft.AdvertiseAddress) != lastPeer.Address {
 		_ = level.Info(logger).Log("msg", "not the bootstrap node, skipping")
-		return nil
+		return nil, nil
 	}
-	_ = level.Info(logger).Log("msg", "bootstrapping raft")
-	bootstrap := m.raft.BootstrapCluster(raft.Configuration{Servers: peers})
+	return &raft.Configuration{Servers: peers}, nil
+}
+
+func (m *Metastore) bootstrap(configuration raft.Configuration) error {
+	_ = level.Info(m.logger).Log("msg", "bootstrapping raft", "peers", fmt.Sprint(configuration.Servers))
+	bootstrap := m.raft.BootstrapCluster(configuration)
 	if bootstrapErr := bootstrap.Error(); bootstrapErr != nil {
 		if !errors.Is(bootstrapErr, raft.ErrCantBootstrap) {
 			return fmt.Errorf("failed to bootstrap raft: %w", bootstrapErr)
@@ -1,9 +1,11 @@
 package metastore
 
 import (
+	"cmp"
 	"context"
 	"flag"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -11,6 +13,7 @@ import (
 	"github.com/go-kit/log/level"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.etcd.io/bbolt"
+	"google.golang.org/protobuf/proto"
 
 	compactorv1 "github.com/grafana/pyroscope/api/gen/proto/go/compactor/v1"
 	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
@@ -19,13 +22,36 @@ import (
 )
 
 type CompactionConfig struct {
-	JobLeaseDuration time.Duration `yaml:"job_lease_duration"`
-	JobMaxFailures   int           `yaml:"job_max_failures"`
+	JobLeaseDuration   time.Duration `yaml:"job_lease_duration"`
+	JobMaxFailures     int           `yaml:"job_max_failures"`
+	JobRetryBackoffMin time.Duration `yaml:"job_retry_backoff_min"`
+	JobRetryBackoffMax time.Duration `yaml:"job_retry_backoff_max"`
 }
 
 func (cfg *CompactionConfig) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
 	f.DurationVar(&cfg.JobLeaseDuration, prefix+"job-lease-duration", 15*time.Second, "")
-	f.IntVar(&cfg.JobMaxFailures, prefix+"job-max-failures", 3, "")
+	f.IntVar(&cfg.JobMaxFailures, prefix+"job-max-failures", 3, "The number of failures after which the compaction job is cancelled and its blocks are quarantined: they are no longer compacted, but remain available for queries. The jobs can be released with the /metastore/compaction/release-quarantined-jobs endpoint.")
+	f.DurationVar(&cfg.JobRetryBackoffMin, prefix+"job-retry-backoff-min", 15*time.Second, "The delay before a failed compaction job is retried. The delay doubles with every failure.")
+	f.DurationVar(&cfg.JobRetryBackoffMax, prefix+"job-retry-backoff-max", 5*time.Minute, "The maximum delay before a failed compaction job is retried.")
+}
+
+// retryBackoff returns the delay before the job that has failed
+// the given number of times can be retried.
+func (cfg *CompactionConfig) retryBackoff(failures uint32) time.Duration {
+	if cfg.JobRetryBackoffMin <= 0 || failures == 0 {
+		return 0
+	}
+	d := cfg.JobRetryBackoffMin
+	for i := uint32(1); i < failures; i++ {
+		d *= 2
+		if cfg.JobRetryBackoffMax > 0 && d >= cfg.JobRetryBackoffMax {
+			break
+		}
+	}
+	if cfg.JobRetryBackoffMax > 0 && d > cfg.JobRetryBackoffMax {
+		d = cfg.JobRetryBackoffMax
+	}
+	return d
 }
 
 var (
@@ -64,6 +90,9 @@ type compactionMetrics struct {
 	completedJobs *prometheus.CounterVec
 	retriedJobs   *prometheus.CounterVec
 	discardedJobs *prometheus.CounterVec
+	releasedJobs  *prometheus.CounterVec
+
+	quarantinedBlocks *prometheus.CounterVec
 }
 
 func newCompactionMetrics(reg prometheus.Registerer) *compactionMetrics {
@@ -103,6 +132,16 @@ func newCompactionMetrics(reg prometheus.Registerer) *compactionMetrics {
 			Name:      "metastore_compaction_discarded_jobs_count",
 			Help:      "The number of discarded compaction jobs",
 		}, []string{"shard", "tenant", "level"}),
+		releasedJobs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "pyroscope",
+			Name:      "metastore_compaction_released_jobs_count",
+			Help:      "The number of compaction jobs released by workers before completion",
+		}, []string{"shard", "tenant", "level"}),
+		quarantinedBlocks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "pyroscope",
+			Name:      "metastore_compaction_quarantined_blocks_count",
+			Help:      "The number of blocks excluded from compaction after the compaction job reached the maximum number of failures",
+		}, []string{"shard", "tenant", "level"}),
 	}
 	if reg != nil {
 		util.Register(reg,
@@ -113,13 +152,147 @@ func newCompactionMetrics(reg prometheus.Registerer) *compactionMetrics {
 			m.completedJobs,
 			m.retriedJobs,
 			m.discardedJobs,
+			m.releasedJobs,
+			m.quarantinedBlocks,
 		)
 	}
 	return m
 }
 
+// GetCompactionJobs lists the compaction jobs matching the request filters:
+// jobs in the queue, and the recently completed ones.
 func (m *Metastore) GetCompactionJobs(_ context.Context, req *compactorv1.GetCompactionRequest) (*compactorv1.GetCompactionResponse, error) {
-	return nil, nil
+	return &compactorv1.GetCompactionResponse{CompactionJobs: m.state.listCompactionJobs(req)}, nil
+}
+
+func (m *metastoreState) listCompactionJobs(req *compactorv1.GetCompactionRequest) []*compactorv1.CompactionJob {
+	m.compactionJobQueue.mu.Lock()
+	queued := make([]*compactionpb.CompactionJob, 0, len(m.compactionJobQueue.jobs))
+	for _, job := range m.compactionJobQueue.jobs {
+		if matchCompactionJob(req, job.TenantId, job.Shard, job.CompactionLevel, compactorv1.CompactionStatus(job.Status)) {
+			queued = append(queued, proto.Clone(job.CompactionJob).(*compactionpb.CompactionJob))
+		}
+	}
+	m.compactionJobQueue.mu.Unlock()
+
+	jobs := make([]*compactorv1.CompactionJob, 0, len(queued))
+	for _, job := range queued {
+		blocks := make([]*metastorev1.BlockMeta, 0, len(job.Blocks))
+		for _, b := range job.Blocks {
+			if md := m.findBlock(job.Shard, b); md != nil {
+				blocks = append(blocks, md.CloneVT())
+			}
+		}
+		j := newCompactionJobInfo(job, blocks)
+		j.RetryNotBefore = job.RetryNotBefore
+		if job.Status == compactionpb.CompactionStatus_COMPACTION_STATUS_IN_PROGRESS {
+			j.LeaseExpiresAt = job.LeaseExpiresAt
+		}
+		jobs = append(jobs, j)
+	}
+
+	m.compactionMutex.Lock()
+	for _, job := range m.completedJobs {
+		if matchCompactionJob(req, job.TenantId, job.Shard, job.CompactionLevel, job.Status.Status) {
+			jobs = append(jobs, job.CloneVT())
+		}
+	}
+	m.compactionMutex.Unlock()
+
+	slices.SortFunc(jobs, func(a, b *compactorv1.CompactionJob) int {
+		if c := cmp.Compare(a.TenantId, b.TenantId); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.Shard, b.Shard); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.CompactionLevel, b.CompactionLevel); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return jobs
+}
+
+func matchCompactionJob(
+	req *compactorv1.GetCompactionRequest,
+	tenant string,
+	shard uint32,
+	compactionLevel uint32,
+	status compactorv1.CompactionStatus,
+) bool {
+	return (len(req.TenantIds) == 0 || slices.Contains(req.TenantIds, tenant)) &&
+		(len(req.Shards) == 0 || slices.Contains(req.Shards, shard)) &&
+		(len(req.CompactionLevels) == 0 || slices.Contains(req.CompactionLevels, compactionLevel)) &&
+		(len(req.Statuses) == 0 || slices.Contains(req.Statuses, status))
+}
+
+func newCompactionJobInfo(job *compactionpb.CompactionJob, blocks []*metastorev1.BlockMeta) *compactorv1.CompactionJob {
+	return &compactorv1.CompactionJob{
+		Name:   job.Name,
+		Blocks: blocks,
+		Status: &compactorv1.CompactionJobStatus{
+			JobName:      job.Name,
+			Status:       compactorv1.CompactionStatus(job.Status),
+			RaftLogIndex: job.RaftLogIndex,
+			Shard:        job.Shard,
+			TenantId:     job.TenantId,
+		},
+		RaftLogIndex:      job.RaftLogIndex,
+		Shard:             job.Shard,
+		TenantId:          job.TenantId,
+		CompactionLevel:   job.CompactionLevel,
+		Failures:          job.Failures,
+		LastFailureReason: job.LastFailureReason,
+	}
+}
+
+// maxCompletedJobs is the number of completed compaction
+// jobs kept in the history.
+const maxCompletedJobs = 100
+
+func (m *metastoreState) addCompletedJob(job *compactionpb.CompactionJob, sources, compacted []*metastorev1.BlockMeta) {
+	j := newCompactionJobInfo(job, sources)
+	j.Status.Status = compactorv1.CompactionStatus_COMPACTION_STATUS_SUCCESS
+	j.Status.CompletedJob = &compactorv1.CompletedJob{Blocks: compacted}
+	m.compactionMutex.Lock()
+	defer m.compactionMutex.Unlock()
+	if len(m.completedJobs) >= maxCompletedJobs {
+		m.completedJobs = slices.Delete(m.completedJobs, 0, len(m.completedJobs)-maxCompletedJobs+1)
+	}
+	m.completedJobs = append(m.completedJobs, j)
+}
+
+// quarantineBlocks excludes the blocks of the job from compaction.
+// The job is expected to have reached the max number of failures:
+// we assume that at least one of the blocks can't be compacted. The
+// blocks remain in quarantine until the job is released by an operator
+// (see ReleaseQuarantinedJobsHandler).
+func (m *metastoreState) quarantineBlocks(job *compactionpb.CompactionJob) {
+	m.compactionMutex.Lock()
+	defer m.compactionMutex.Unlock()
+	for _, b := range job.Blocks {
+		m.quarantinedBlocks[b] = job.Name
+	}
+	m.compactionMetrics.quarantinedBlocks.WithLabelValues(
+		fmt.Sprint(job.Shard), job.TenantId, fmt.Sprint(job.CompactionLevel)).Add(float64(len(job.Blocks)))
+}
+
+func (m *metastoreState) isQuarantined(blockID string) (job string, ok bool) {
+	m.compactionMutex.Lock()
+	defer m.compactionMutex.Unlock()
+	job, ok = m.quarantinedBlocks[blockID]
+	return job, ok
+}
+
+// truncateFailureReason limits the size of the failure
+// reason reported by a worker, which is stored in the job.
+func truncateFailureReason(reason string) string {
+	const maxLength = 1 << 10
+	if len(reason) > maxLength {
+		return reason[:maxLength]
+	}
+	return reason
 }
 
 // compactBlock is the entry point for adding blocks to the compaction flow.
@@ -114,3 +114,12 @@ func verifyCompactionState(t *testing.T, m *metastoreState) {
 		}
 	}
 }
+
+func Test_CompactionConfig_retryBackoff(t *testing.T) {
+	cfg := CompactionConfig{JobRetryBackoffMin: time.Second, JobRetryBackoffMax: 5 * time.Second}
+	for failures, expected := range []time.Duration{0, time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second} {
+		require.Equal(t, expected, cfg.retryBackoff(uint32(failures)), failures)
+	}
+	cfg.JobRetryBackoffMin = 0
+	require.Equal(t, time.Duration(0), cfg.retryBackoff(3))
+}
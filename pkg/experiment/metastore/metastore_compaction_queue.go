@@ -66,8 +66,20 @@ func (c *jobQueueEntry) less(x *jobQueueEntry) bool {
 func (q *jobQueue) dequeue(now int64, raftLogIndex uint64) *compactionpb.CompactionJob {
 	q.mu.Lock()
 	defer q.mu.Unlock()
+	// Jobs waiting to be retried are put back once we're done.
+	var backoff []*jobQueueEntry
+	defer func() {
+		for _, job := range backoff {
+			heap.Push(&q.pq, job)
+		}
+	}()
 	for q.pq.Len() > 0 {
 		job := q.pq[0]
+		if job.Status == compactionpb.CompactionStatus_COMPACTION_STATUS_UNSPECIFIED &&
+			now < job.RetryNotBefore {
+			backoff = append(backoff, heap.Pop(&q.pq).(*jobQueueEntry))
+			continue
+		}
 		if job.Status == compactionpb.CompactionStatus_COMPACTION_STATUS_IN_PROGRESS &&
 			now <= job.LeaseExpiresAt {
 			// If the top job is in progress and not expired, stop checking further
@@ -105,6 +117,23 @@ func (q *jobQueue) update(name string, now int64, raftLogIndex uint64) bool {
 	return false
 }
 
+// release returns the job to the queue, so that it can be assigned to
+// another worker immediately, without waiting for the lease to expire.
+func (q *jobQueue) release(name string, raftLogIndex uint64) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if job, exists := q.jobs[name]; exists {
+		if job.RaftLogIndex > raftLogIndex {
+			return false
+		}
+		job.LeaseExpiresAt = 0
+		job.Status = compactionpb.CompactionStatus_COMPACTION_STATUS_UNSPECIFIED
+		heap.Fix(&q.pq, job.index)
+		return true
+	}
+	return false
+}
+
 func (q *jobQueue) cancel(name string) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
@@ -114,6 +143,24 @@ func (q *jobQueue) cancel(name string) {
 	}
 }
 
+// requeue returns the cancelled job to the queue, as a new job: the
+// failures are reset. It returns nil, if the job is not cancelled.
+func (q *jobQueue) requeue(name string) *compactionpb.CompactionJob {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	job, exists := q.jobs[name]
+	if !exists || job.Status != compactionpb.CompactionStatus_COMPACTION_STATUS_CANCELLED {
+		return nil
+	}
+	job.Status = compactionpb.CompactionStatus_COMPACTION_STATUS_UNSPECIFIED
+	job.Failures = 0
+	job.RaftLogIndex = 0
+	job.LeaseExpiresAt = 0
+	job.RetryNotBefore = 0
+	heap.Fix(&q.pq, job.index)
+	return job.CompactionJob
+}
+
 func (q *jobQueue) getNewDeadline(now int64) int64 {
 	return now + q.lease
 }
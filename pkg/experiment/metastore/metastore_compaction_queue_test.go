@@ -69,3 +69,29 @@ func assertJob(t *testing.T, j *compactionpb.CompactionJob, name string, commitI
 	assert.Equal(t, name, j.Name)
 	assert.Equal(t, commitIndex, j.RaftLogIndex)
 }
+
+func Test_compactionJobQueue_RetryBackoff(t *testing.T) {
+	q := newJobQueue(1000)
+	assert.True(t, q.enqueue(&compactionpb.CompactionJob{
+		Name:           "job1",
+		RetryNotBefore: 100,
+	}))
+	assert.True(t, q.enqueue(&compactionpb.CompactionJob{
+		Name:            "job2",
+		CompactionLevel: 1,
+	}))
+	assert.True(t, q.enqueue(&compactionpb.CompactionJob{
+		Name:           "job3",
+		Status:         compactionpb.CompactionStatus_COMPACTION_STATUS_IN_PROGRESS,
+		LeaseExpiresAt: 50,
+	}))
+
+	// The job waiting to be retried is skipped,
+	// but does not block the other jobs.
+	assertJob(t, q.dequeue(0, 1), "job2", 1)
+	require.Nil(t, q.dequeue(0, 2))
+	assertJob(t, q.dequeue(51, 3), "job3", 3)
+	require.Nil(t, q.dequeue(99, 4))
+	assertJob(t, q.dequeue(100, 5), "job1", 5)
+	assert.Len(t, q.pq, 3)
+}
@@ -24,9 +24,11 @@ import (
 // The map is used to determine the type of the given command,
 // when the request is converted to a Raft log entry.
 var commandTypeMap = map[reflect.Type]raftlogpb.CommandType{
-	reflect.TypeOf(new(metastorev1.AddBlockRequest)):           raftlogpb.CommandType_COMMAND_TYPE_ADD_BLOCK,
-	reflect.TypeOf(new(raftlogpb.TruncateCommand)):             raftlogpb.CommandType_COMMAND_TYPE_TRUNCATE,
-	reflect.TypeOf(new(compactorv1.PollCompactionJobsRequest)): raftlogpb.CommandType_COMMAND_TYPE_POLL_COMPACTION_JOBS_STATUS,
+	reflect.TypeOf(new(metastorev1.AddBlockRequest)):             raftlogpb.CommandType_COMMAND_TYPE_ADD_BLOCK,
+	reflect.TypeOf(new(raftlogpb.TruncateCommand)):               raftlogpb.CommandType_COMMAND_TYPE_TRUNCATE,
+	reflect.TypeOf(new(compactorv1.PollCompactionJobsRequest)):   raftlogpb.CommandType_COMMAND_TYPE_POLL_COMPACTION_JOBS_STATUS,
+	reflect.TypeOf(new(raftlogpb.CleanBlocksCommand)):            raftlogpb.CommandType_COMMAND_TYPE_CLEAN_BLOCKS,
+	reflect.TypeOf(new(raftlogpb.ReleaseQuarantinedJobsCommand)): raftlogpb.CommandType_COMMAND_TYPE_RELEASE_QUARANTINED_JOBS,
 }
 
 // The map is used to determine the handler for the given command,
@@ -41,6 +43,12 @@ var commandHandlers = map[raftlogpb.CommandType]commandHandler{
 	raftlogpb.CommandType_COMMAND_TYPE_POLL_COMPACTION_JOBS_STATUS: func(fsm *FSM, cmd *raft.Log, raw []byte) fsmResponse {
 		return handleCommand(raw, cmd, fsm.state.applyPollCompactionJobs)
 	},
+	raftlogpb.CommandType_COMMAND_TYPE_CLEAN_BLOCKS: func(fsm *FSM, cmd *raft.Log, raw []byte) fsmResponse {
+		return handleCommand(raw, cmd, fsm.state.applyCleanBlocks)
+	},
+	raftlogpb.CommandType_COMMAND_TYPE_RELEASE_QUARANTINED_JOBS: func(fsm *FSM, cmd *raft.Log, raw []byte) fsmResponse {
+		return handleCommand(raw, cmd, fsm.state.applyReleaseQuarantinedJobs)
+	},
 }
 
 // TODO: Add registration functions.
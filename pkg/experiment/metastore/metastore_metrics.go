@@ -13,6 +13,7 @@ type metastoreMetrics struct {
 	fsmRestoreSnapshotDuration     prometheus.Histogram
 	fsmApplyCommandHandlerDuration prometheus.Histogram
 	raftAddBlockDuration           prometheus.Histogram
+	followerReadIndexWaitDuration  prometheus.Histogram
 }
 
 func newMetastoreMetrics(reg prometheus.Registerer) *metastoreMetrics {
@@ -44,6 +45,12 @@ func newMetastoreMetrics(reg prometheus.Registerer) *metastoreMetrics {
 			Name:      "metastore_raft_add_block_duration_seconds",
 			Buckets:   dataTimingBuckets,
 		}),
+		followerReadIndexWaitDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: "pyroscope",
+			Name:      "metastore_follower_read_index_wait_duration_seconds",
+			Help:      "Time a follower waits for its state to catch up with the leader read index before serving a read",
+			Buckets:   dataTimingBuckets,
+		}),
 	}
 	if reg != nil {
 		util.RegisterOrGet(reg, m.boltDBPersistSnapshotDuration)
@@ -51,6 +58,7 @@ func newMetastoreMetrics(reg prometheus.Registerer) *metastoreMetrics {
 		util.RegisterOrGet(reg, m.fsmRestoreSnapshotDuration)
 		util.RegisterOrGet(reg, m.fsmApplyCommandHandlerDuration)
 		util.RegisterOrGet(reg, m.raftAddBlockDuration)
+		util.RegisterOrGet(reg, m.followerReadIndexWaitDuration)
 	}
 	return m
 }
@@ -2,7 +2,12 @@ package metastore
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"connectrpc.com/connect"
 	"github.com/hashicorp/raft"
@@ -80,6 +85,258 @@ func (m *Metastore) Info(_ context.Context, _ *connect.Request[metastorev1.InfoR
 	return connect.NewResponse(res), nil
 }
 
+func (m *Metastore) AddVoter(_ context.Context, req *connect.Request[metastorev1.AddVoterRequest]) (*connect.Response[metastorev1.AddVoterResponse], error) {
+	server := raft.Server{
+		Suffrage: raft.Voter,
+		ID:       raft.ServerID(req.Msg.Id),
+		Address:  raft.ServerAddress(req.Msg.Address),
+	}
+	if server.ID == "" || server.Address == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("server id and address are required"))
+	}
+	cfg, err := m.leaderConfiguration()
+	if err != nil {
+		return nil, err
+	}
+	if !req.Msg.Force {
+		// A new voter increases the quorum size. Unless it is already
+		// in the cluster as a healthy non-voter, it's not counted.
+		if err = m.checkQuorum(cfg.Configuration().Servers, withServer(cfg.Configuration().Servers, server)); err != nil {
+			return nil, err
+		}
+	}
+	f := m.raft.AddVoter(server.ID, server.Address, cfg.Index(), m.config.Raft.ApplyTimeout)
+	if err = f.Error(); err != nil {
+		return nil, operatorError(err)
+	}
+	return connect.NewResponse(&metastorev1.AddVoterResponse{Index: f.Index()}), nil
+}
+
+func (m *Metastore) AddNonvoter(_ context.Context, req *connect.Request[metastorev1.AddNonvoterRequest]) (*connect.Response[metastorev1.AddNonvoterResponse], error) {
+	server := raft.Server{
+		Suffrage: raft.Nonvoter,
+		ID:       raft.ServerID(req.Msg.Id),
+		Address:  raft.ServerAddress(req.Msg.Address),
+	}
+	if server.ID == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("server id is required"))
+	}
+	cfg, err := m.leaderConfiguration()
+	if err != nil {
+		return nil, err
+	}
+	servers := cfg.Configuration().Servers
+	i := slices.IndexFunc(servers, func(s raft.Server) bool { return s.ID == server.ID })
+	if i < 0 || servers[i].Suffrage != raft.Voter {
+		// A non-voter does not affect the quorum.
+		if server.Address == "" {
+			return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("server address is required"))
+		}
+		f := m.raft.AddNonvoter(server.ID, server.Address, cfg.Index(), m.config.Raft.ApplyTimeout)
+		if err = f.Error(); err != nil {
+			return nil, operatorError(err)
+		}
+		return connect.NewResponse(&metastorev1.AddNonvoterResponse{Index: f.Index()}), nil
+	}
+	if !req.Msg.Force {
+		if err = m.checkLocalServer(server.ID); err != nil {
+			return nil, err
+		}
+		server.Address = servers[i].Address
+		if err = m.checkQuorum(servers, withServer(servers, server)); err != nil {
+			return nil, err
+		}
+	}
+	f := m.raft.DemoteVoter(server.ID, cfg.Index(), m.config.Raft.ApplyTimeout)
+	if err = f.Error(); err != nil {
+		return nil, operatorError(err)
+	}
+	return connect.NewResponse(&metastorev1.AddNonvoterResponse{Index: f.Index()}), nil
+}
+
+func (m *Metastore) RemoveServer(_ context.Context, req *connect.Request[metastorev1.RemoveServerRequest]) (*connect.Response[metastorev1.RemoveServerResponse], error) {
+	id := raft.ServerID(req.Msg.Id)
+	if id == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("server id is required"))
+	}
+	cfg, err := m.leaderConfiguration()
+	if err != nil {
+		return nil, err
+	}
+	servers := cfg.Configuration().Servers
+	if !slices.ContainsFunc(servers, func(s raft.Server) bool { return s.ID == id }) {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("server %q not found", id))
+	}
+	if !req.Msg.Force {
+		if err = m.checkLocalServer(id); err != nil {
+			return nil, err
+		}
+		if err = m.checkQuorum(servers, withoutServer(servers, id)); err != nil {
+			return nil, err
+		}
+	}
+	f := m.raft.RemoveServer(id, cfg.Index(), m.config.Raft.ApplyTimeout)
+	if err = f.Error(); err != nil {
+		return nil, operatorError(err)
+	}
+	return connect.NewResponse(&metastorev1.RemoveServerResponse{Index: f.Index()}), nil
+}
+
+func (m *Metastore) TransferLeadership(_ context.Context, req *connect.Request[metastorev1.TransferLeadershipRequest]) (*connect.Response[metastorev1.TransferLeadershipResponse], error) {
+	cfg, err := m.leaderConfiguration()
+	if err != nil {
+		return nil, err
+	}
+	var f raft.Future
+	if req.Msg.Id == "" {
+		f = m.raft.LeadershipTransfer()
+	} else {
+		id := raft.ServerID(req.Msg.Id)
+		servers := cfg.Configuration().Servers
+		i := slices.IndexFunc(servers, func(s raft.Server) bool { return s.ID == id })
+		switch {
+		case i < 0:
+			return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("server %q not found", id))
+		case servers[i].Suffrage != raft.Voter:
+			return nil, connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("server %q is not a voter", id))
+		case id == raft.ServerID(m.config.Raft.ServerID):
+			return nil, connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("server %q is already the leader", id))
+		}
+		f = m.raft.LeadershipTransferToServer(id, servers[i].Address)
+	}
+	if err = f.Error(); err != nil {
+		return nil, operatorError(err)
+	}
+	return connect.NewResponse(&metastorev1.TransferLeadershipResponse{LeaderId: string(m.awaitLeader())}), nil
+}
+
+// awaitLeader waits for the new leader to contact the local node.
+// An empty string is returned, if the leader is not known in time.
+func (m *Metastore) awaitLeader() raft.ServerID {
+	tcheck := time.NewTicker(tcheckFreq)
+	defer tcheck.Stop()
+	timeout := time.NewTimer(m.config.Raft.ApplyTimeout)
+	defer timeout.Stop()
+	for {
+		if _, id := m.raft.LeaderWithID(); id != "" {
+			return id
+		}
+		select {
+		case <-tcheck.C:
+		case <-timeout.C:
+			return ""
+		}
+	}
+}
+
+func (m *Metastore) Snapshot(_ context.Context, _ *connect.Request[metastorev1.SnapshotRequest]) (*connect.Response[metastorev1.SnapshotResponse], error) {
+	f := m.raft.Snapshot()
+	if err := f.Error(); err != nil {
+		return nil, operatorError(err)
+	}
+	meta, snapshot, err := f.Open()
+	if err != nil {
+		return nil, operatorError(err)
+	}
+	_ = snapshot.Close()
+	return connect.NewResponse(&metastorev1.SnapshotResponse{
+		Snapshot: &metastorev1.Snapshot{
+			LastIndex: meta.Index,
+			LastTerm:  meta.Term,
+		},
+	}), nil
+}
+
+// leaderConfiguration returns the current raft configuration,
+// if the local node is the leader.
+func (m *Metastore) leaderConfiguration() (raft.ConfigurationFuture, error) {
+	if m.raft.State() != raft.Leader {
+		_, leaderID := m.raft.LeaderWithID()
+		return nil, connect.NewError(connect.CodeFailedPrecondition,
+			fmt.Errorf("%w: membership changes must be performed on the leader (%q)", raft.ErrNotLeader, leaderID))
+	}
+	f := m.raft.GetConfiguration()
+	if err := f.Error(); err != nil {
+		return nil, operatorError(err)
+	}
+	return f, nil
+}
+
+// checkLocalServer rejects changes to the local node: as the leader,
+// it should transfer the leadership first.
+func (m *Metastore) checkLocalServer(id raft.ServerID) error {
+	if id == raft.ServerID(m.config.Raft.ServerID) {
+		return connect.NewError(connect.CodeFailedPrecondition,
+			fmt.Errorf("server %q is the leader: transfer the leadership first", id))
+	}
+	return nil
+}
+
+// checkQuorum verifies that the healthy voters of the next configuration
+// form a quorum. The servers that are not present in the current
+// configuration are not considered healthy.
+func (m *Metastore) checkQuorum(current, next []raft.Server) error {
+	var voters, healthy int
+	for _, s := range next {
+		if s.Suffrage != raft.Voter {
+			continue
+		}
+		voters++
+		if m.healthyPeer(current, s.ID) {
+			healthy++
+		}
+	}
+	if voters == 0 {
+		return connect.NewError(connect.CodeFailedPrecondition, errors.New("the cluster must have at least one voter"))
+	}
+	if quorum := voters/2 + 1; healthy < quorum {
+		return connect.NewError(connect.CodeFailedPrecondition,
+			fmt.Errorf("quorum would be lost: %d of %d voters are healthy, %d required", healthy, voters, quorum))
+	}
+	return nil
+}
+
+func (m *Metastore) healthyPeer(current []raft.Server, id raft.ServerID) bool {
+	if id == raft.ServerID(m.config.Raft.ServerID) {
+		return true
+	}
+	if !slices.ContainsFunc(current, func(s raft.Server) bool { return s.ID == id }) {
+		return false
+	}
+	return m.peers.healthy(id)
+}
+
+func withServer(servers []raft.Server, server raft.Server) []raft.Server {
+	next := slices.Clone(servers)
+	for i := range next {
+		if next[i].ID == server.ID {
+			next[i] = server
+			return next
+		}
+	}
+	return append(next, server)
+}
+
+func withoutServer(servers []raft.Server, id raft.ServerID) []raft.Server {
+	return slices.DeleteFunc(slices.Clone(servers), func(s raft.Server) bool { return s.ID == id })
+}
+
+func operatorError(err error) error {
+	switch {
+	case shouldRetryCommand(err):
+		return connect.NewError(connect.CodeUnavailable, err)
+	case errors.Is(err, raft.ErrEnqueueTimeout):
+		return connect.NewError(connect.CodeDeadlineExceeded, err)
+	case errors.Is(err, raft.ErrNothingNewToSnapshot):
+		return connect.NewError(connect.CodeFailedPrecondition, err)
+	case strings.Contains(err.Error(), "configuration changed since"):
+		// The configuration has been changed concurrently.
+		return connect.NewError(connect.CodeAborted, err)
+	default:
+		return connect.NewError(connect.CodeInternal, err)
+	}
+}
+
 // getUint64 tries to get a uint64 value from a map. If the key does not exist
 // or the value is not a valid uint64, it returns 0.
 func getUint64(m map[string]string, key string) uint64 {
@@ -0,0 +1,240 @@
+package metastore
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/services"
+	"github.com/hashicorp/raft"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+)
+
+type testNode struct {
+	id   string
+	addr string
+	m    *Metastore
+}
+
+func newTestNode(t *testing.T, id string, peers []string, skipBootstrap bool) *testNode {
+	t.Helper()
+	addr := freeAddress(t)
+	return newTestNodeWithAddress(t, id, addr, peers, skipBootstrap)
+}
+
+func newTestNodeWithAddress(t *testing.T, id, addr string, peers []string, skipBootstrap bool) *testNode {
+	t.Helper()
+	dir := t.TempDir()
+	config := Config{
+		DataDir: dir,
+		Raft: RaftConfig{
+			Dir:                  dir,
+			BootstrapPeers:       peers,
+			BootstrapExpectPeers: len(peers),
+			SkipBootstrap:        skipBootstrap,
+			ServerID:             id,
+			BindAddress:          addr,
+			AdvertiseAddress:     addr,
+			ApplyTimeout:         5 * time.Second,
+		},
+	}
+	m, err := New(config, nil, log.NewNopLogger(), prometheus.NewRegistry(), nil, nil)
+	require.NoError(t, err)
+	return &testNode{id: id, addr: addr, m: m}
+}
+
+func freeAddress(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := l.Addr().String()
+	require.NoError(t, l.Close())
+	return addr
+}
+
+func (n *testNode) start(t *testing.T) {
+	t.Helper()
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), n.m.Service()))
+}
+
+func (n *testNode) stop(t *testing.T) {
+	t.Helper()
+	require.NoError(t, services.StopAndAwaitTerminated(context.Background(), n.m.Service()))
+}
+
+func (n *testNode) info(t *testing.T) *metastorev1.InfoResponse {
+	t.Helper()
+	res, err := n.m.Info(context.Background(), connect.NewRequest(&metastorev1.InfoRequest{}))
+	require.NoError(t, err)
+	return res.Msg
+}
+
+// newTestCluster starts a cluster of n voters, and waits for the leader.
+func newTestCluster(t *testing.T, n int) []*testNode {
+	t.Helper()
+	addrs := make([]string, n)
+	peers := make([]string, n)
+	for i := range addrs {
+		addrs[i] = freeAddress(t)
+		peers[i] = fmt.Sprintf("%s/node-%d", addrs[i], i)
+	}
+	nodes := make([]*testNode, n)
+	for i := range nodes {
+		nodes[i] = newTestNodeWithAddress(t, fmt.Sprintf("node-%d", i), addrs[i], peers, false)
+		nodes[i].start(t)
+	}
+	t.Cleanup(func() {
+		for _, node := range nodes {
+			if node.m.Service().State() == services.Running {
+				node.stop(t)
+			}
+		}
+	})
+	leader(t, nodes)
+	return nodes
+}
+
+// leader waits for the running nodes to agree on the leader.
+func leader(t *testing.T, nodes []*testNode) *testNode {
+	t.Helper()
+	var l *testNode
+	require.Eventually(t, func() bool {
+		l = nil
+		var leaderID raft.ServerID
+		for _, node := range nodes {
+			if node.m.Service().State() != services.Running {
+				continue
+			}
+			_, id := node.m.raft.LeaderWithID()
+			if id == "" || (leaderID != "" && id != leaderID) {
+				return false
+			}
+			leaderID = id
+			if node.m.raft.State() == raft.Leader {
+				l = node
+			}
+		}
+		return l != nil && string(leaderID) == l.id
+	}, 30*time.Second, 50*time.Millisecond)
+	return l
+}
+
+func followers(nodes []*testNode, leader *testNode) []*testNode {
+	var f []*testNode
+	for _, node := range nodes {
+		if node != leader {
+			f = append(f, node)
+		}
+	}
+	return f
+}
+
+func requireCode(t *testing.T, code connect.Code, err error) {
+	t.Helper()
+	require.Error(t, err)
+	assert.Equal(t, code, connect.CodeOf(err), err.Error())
+}
+
+func Test_Operator_Membership(t *testing.T) {
+	ctx := context.Background()
+	nodes := newTestCluster(t, 3)
+	l := leader(t, nodes)
+	f := followers(nodes, l)
+
+	info := l.info(t)
+	assert.Equal(t, metastorev1.State_Leader, info.State)
+	assert.True(t, info.IsStateVerified)
+	assert.Len(t, info.Peers, 2)
+
+	// Membership changes are only allowed on the leader.
+	_, err := f[0].m.RemoveServer(ctx, connect.NewRequest(&metastorev1.RemoveServerRequest{Id: f[1].id}))
+	requireCode(t, connect.CodeFailedPrecondition, err)
+	// The leader can't be removed without transferring the leadership.
+	_, err = l.m.RemoveServer(ctx, connect.NewRequest(&metastorev1.RemoveServerRequest{Id: l.id}))
+	requireCode(t, connect.CodeFailedPrecondition, err)
+	_, err = l.m.RemoveServer(ctx, connect.NewRequest(&metastorev1.RemoveServerRequest{Id: "unknown"}))
+	requireCode(t, connect.CodeNotFound, err)
+	_, err = l.m.AddVoter(ctx, connect.NewRequest(&metastorev1.AddVoterRequest{Id: "node-x"}))
+	requireCode(t, connect.CodeInvalidArgument, err)
+
+	// One of the followers fails: the remaining voters
+	// are the only quorum the cluster may have.
+	f[0].stop(t)
+	require.Eventually(t, func() bool {
+		return !l.m.peers.healthy(raft.ServerID(f[0].id))
+	}, 10*time.Second, 50*time.Millisecond)
+
+	_, err = l.m.RemoveServer(ctx, connect.NewRequest(&metastorev1.RemoveServerRequest{Id: f[1].id}))
+	requireCode(t, connect.CodeFailedPrecondition, err)
+	_, err = l.m.AddNonvoter(ctx, connect.NewRequest(&metastorev1.AddNonvoterRequest{Id: f[1].id}))
+	requireCode(t, connect.CodeFailedPrecondition, err)
+	// The unhealthy node can be removed safely.
+	res, err := l.m.RemoveServer(ctx, connect.NewRequest(&metastorev1.RemoveServerRequest{Id: f[0].id}))
+	require.NoError(t, err)
+	assert.NotZero(t, res.Msg.Index)
+	assert.Len(t, l.info(t).Peers, 1)
+
+	// A new node joins the cluster: first as a non-voter, then it is
+	// promoted, once it has caught up with the leader.
+	n := newTestNode(t, "node-3", nil, true)
+	n.start(t)
+	defer n.stop(t)
+	nodes = []*testNode{l, f[1], n}
+
+	_, err = l.m.AddNonvoter(ctx, connect.NewRequest(&metastorev1.AddNonvoterRequest{Id: n.id, Address: n.addr}))
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return n.m.raft.AppliedIndex() >= l.m.raft.CommitIndex()
+	}, 10*time.Second, 50*time.Millisecond)
+	assert.Equal(t, metastorev1.Suffrage_NonVoter, n.info(t).Suffrage)
+
+	_, err = l.m.AddVoter(ctx, connect.NewRequest(&metastorev1.AddVoterRequest{Id: n.id, Address: n.addr}))
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return n.info(t).Suffrage == metastorev1.Suffrage_Voter
+	}, 10*time.Second, 50*time.Millisecond)
+
+	// The leadership is handed over to the new node.
+	tl, err := l.m.TransferLeadership(ctx, connect.NewRequest(&metastorev1.TransferLeadershipRequest{Id: n.id}))
+	require.NoError(t, err)
+	assert.Equal(t, n.id, tl.Msg.LeaderId)
+	assert.Equal(t, n.id, leader(t, nodes).id)
+
+	// The former leader is demoted and removed.
+	_, err = n.m.AddNonvoter(ctx, connect.NewRequest(&metastorev1.AddNonvoterRequest{Id: l.id}))
+	require.NoError(t, err)
+	_, err = n.m.RemoveServer(ctx, connect.NewRequest(&metastorev1.RemoveServerRequest{Id: l.id}))
+	require.NoError(t, err)
+	info = n.info(t)
+	require.Len(t, info.Peers, 1)
+	assert.Equal(t, f[1].id, info.Peers[0].Id)
+	assert.Equal(t, metastorev1.Suffrage_Voter, info.Peers[0].Suffrage)
+}
+
+func Test_Operator_Snapshot(t *testing.T) {
+	ctx := context.Background()
+	nodes := newTestCluster(t, 3)
+	l := leader(t, nodes)
+	_, err := l.m.Snapshot(ctx, connect.NewRequest(&metastorev1.SnapshotRequest{}))
+	requireCode(t, connect.CodeFailedPrecondition, err)
+
+	_, err = l.m.AddBlock(ctx, &metastorev1.AddBlockRequest{Block: createBlock(1, 1, "tenant", 0)})
+	require.NoError(t, err)
+	for _, node := range nodes {
+		require.Eventually(t, func() bool {
+			return node.m.raft.AppliedIndex() >= l.m.raft.CommitIndex()
+		}, 10*time.Second, 50*time.Millisecond)
+		res, err := node.m.Snapshot(ctx, connect.NewRequest(&metastorev1.SnapshotRequest{}))
+		require.NoError(t, err)
+		assert.NotZero(t, res.Msg.Snapshot.LastIndex)
+		assert.NotZero(t, res.Msg.Snapshot.LastTerm)
+	}
+}
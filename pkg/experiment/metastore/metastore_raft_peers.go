@@ -0,0 +1,95 @@
+package metastore
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hashicorp/raft"
+)
+
+// raftPeerHealth tracks the peers the local node fails to heartbeat.
+// Heartbeats are only sent by the leader, therefore the state is only
+// meaningful on the leader, and is reset on leadership changes.
+type raftPeerHealth struct {
+	mu       sync.Mutex
+	failures map[raft.ServerID]time.Time // Last contact.
+
+	raft     *raft.Raft
+	observer *raft.Observer
+	c        chan raft.Observation
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+func newRaftPeerHealth() *raftPeerHealth {
+	return &raftPeerHealth{
+		failures: make(map[raft.ServerID]time.Time),
+		c:        make(chan raft.Observation, 64),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+func (h *raftPeerHealth) register(r *raft.Raft) {
+	h.raft = r
+	// The observer must not block raft: the observations are
+	// infrequent, and the channel is large enough to not drop them.
+	h.observer = raft.NewObserver(h.c, false, func(o *raft.Observation) bool {
+		switch o.Data.(type) {
+		case raft.FailedHeartbeatObservation,
+			raft.ResumedHeartbeatObservation,
+			raft.LeaderObservation,
+			raft.PeerObservation:
+			return true
+		}
+		return false
+	})
+	go h.run()
+	r.RegisterObserver(h.observer)
+}
+
+func (h *raftPeerHealth) deregister() {
+	if h.raft == nil {
+		return
+	}
+	close(h.stop)
+	<-h.done
+}
+
+func (h *raftPeerHealth) run() {
+	defer close(h.done)
+	for {
+		select {
+		case o := <-h.c:
+			h.observe(o)
+		case <-h.stop:
+			h.raft.DeregisterObserver(h.observer)
+			return
+		}
+	}
+}
+
+func (h *raftPeerHealth) observe(o raft.Observation) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	switch x := o.Data.(type) {
+	case raft.FailedHeartbeatObservation:
+		h.failures[x.PeerID] = x.LastContact
+	case raft.ResumedHeartbeatObservation:
+		delete(h.failures, x.PeerID)
+	case raft.LeaderObservation:
+		clear(h.failures)
+	case raft.PeerObservation:
+		if x.Removed {
+			delete(h.failures, x.Peer.ID)
+		}
+	}
+}
+
+// healthy reports whether the leader can heartbeat the peer.
+func (h *raftPeerHealth) healthy(id raft.ServerID) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, failed := h.failures[id]
+	return !failed
+}
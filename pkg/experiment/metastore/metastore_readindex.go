@@ -6,12 +6,19 @@ import (
 	"time"
 
 	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
 	"github.com/google/uuid"
+	"github.com/hashicorp/raft"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
 )
 
-var tcheckFreq = 10 * time.Millisecond
+var (
+	tcheckFreq       = 10 * time.Millisecond
+	readIndexTimeout = 5 * time.Second
+)
 
 func (m *Metastore) ReadIndex(ctx context.Context, req *metastorev1.ReadIndexRequest) (*metastorev1.ReadIndexResponse, error) {
 	//todo
@@ -35,32 +42,58 @@ func (m *Metastore) ReadIndex(ctx context.Context, req *metastorev1.ReadIndexReq
 		)
 	}
 
-	raftLogger().Log("msg", "verify_leader")
+	level.Debug(raftLogger()).Log("msg", "verify_leader")
 	if err := m.raft.VerifyLeader().Error(); err != nil {
+		return new(metastorev1.ReadIndexResponse), wrapRetryableErrorWithRaftDetails(err, m.raft)
+	}
+	if err := m.waitApplied(ctx, readIndex); err != nil {
+		level.Debug(raftLogger()).Log("msg", "failed to wait for the read index to be applied", "err", err)
 		return new(metastorev1.ReadIndexResponse), err
 	}
+	level.Debug(raftLogger()).Log("msg", "caught up")
+	return &metastorev1.ReadIndexResponse{ReadIndex: readIndex}, nil
+}
 
+// waitReadIndex blocks until the local state includes all the entries
+// committed by the time of the call: the read index is obtained from
+// the leader, and the node waits until the index is applied locally.
+// Once the call returns, reads served by the node, including followers,
+// are consistent with the leader.
+func (m *Metastore) waitReadIndex(ctx context.Context) error {
+	if m.raft.State() == raft.Leader {
+		_, err := m.ReadIndex(ctx, new(metastorev1.ReadIndexRequest))
+		return err
+	}
+	t1 := time.Now()
+	defer func() {
+		m.metrics.followerReadIndexWaitDuration.Observe(time.Since(t1).Seconds())
+	}()
+	res, err := m.client.ReadIndex(ctx, new(metastorev1.ReadIndexRequest))
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "failed to get read index: %v", err)
+	}
+	return m.waitApplied(ctx, res.ReadIndex)
+}
+
+// waitApplied blocks until the local applied index reaches the given one.
+func (m *Metastore) waitApplied(ctx context.Context, index uint64) error {
+	if m.raft.AppliedIndex() >= index {
+		return nil
+	}
 	tcheck := time.NewTicker(tcheckFreq)
 	defer tcheck.Stop()
-	timeout := time.NewTimer(5 * time.Second)
+	timeout := time.NewTimer(readIndexTimeout)
 	defer timeout.Stop()
-
 	for {
 		select {
 		case <-tcheck.C:
-			appliedIndex := m.raft.AppliedIndex()
-			raftLogger().Log("msg", "tick")
-			if appliedIndex >= readIndex {
-				raftLogger().Log("msg", "caught up")
-				return &metastorev1.ReadIndexResponse{ReadIndex: readIndex}, nil
+			if m.raft.AppliedIndex() >= index {
+				return nil
 			}
-			continue
 		case <-timeout.C:
-			raftLogger().Log("err", "timeout")
-			return new(metastorev1.ReadIndexResponse), fmt.Errorf("timeout")
+			return status.Errorf(codes.Unavailable, "timeout waiting for index %d to be applied, applied index: %d", index, m.raft.AppliedIndex())
 		case <-ctx.Done():
-			raftLogger().Log("err", "context canceled")
-			return new(metastorev1.ReadIndexResponse), fmt.Errorf("canceled %w", ctx.Err())
+			return fmt.Errorf("canceled %w", ctx.Err())
 		}
 	}
 }
@@ -0,0 +1,75 @@
+package metastore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/flagext"
+	"github.com/grafana/dskit/grpcclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	metastoreclient "github.com/grafana/pyroscope/pkg/experiment/metastore/client"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/raftlogpb"
+	"github.com/grafana/pyroscope/pkg/test/mocks/mockdiscovery"
+)
+
+func Test_WaitApplied(t *testing.T) {
+	nodes := newTestCluster(t, 3)
+	l := leader(t, nodes)
+	f := followers(nodes, l)[0]
+	ctx := context.Background()
+
+	// The follower is behind: the index is not committed yet.
+	index := l.m.raft.LastIndex() + 1
+	done := make(chan error, 1)
+	go func() { done <- f.m.waitApplied(ctx, index) }()
+	select {
+	case err := <-done:
+		t.Fatalf("waitApplied returned before the index was applied: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	_, _, err := applyCommand[*raftlogpb.CleanBlocksCommand, *anypb.Any](l.m.raft, &raftlogpb.CleanBlocksCommand{}, time.Second)
+	require.NoError(t, err)
+	select {
+	case err = <-done:
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, f.m.raft.AppliedIndex(), index)
+	case <-time.After(5 * time.Second):
+		t.Fatal("waitApplied did not return after the index was applied")
+	}
+
+	// The follower does not catch up in time.
+	defer func(timeout time.Duration) { readIndexTimeout = timeout }(readIndexTimeout)
+	readIndexTimeout = 100 * time.Millisecond
+	err = f.m.waitApplied(ctx, f.m.raft.LastIndex()+100)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func Test_WaitReadIndex_NoLeader(t *testing.T) {
+	nodes := newTestCluster(t, 3)
+	l := leader(t, nodes)
+	f := followers(nodes, l)
+	require.NoError(t, l.m.waitReadIndex(context.Background()))
+
+	// The follower can't get the read index: the other
+	// nodes are stopped, and there is no leader.
+	d := mockdiscovery.NewMockDiscovery(t)
+	d.On("Subscribe", mock.Anything).Return()
+	d.On("ServerError", mock.Anything).Maybe().Return()
+	var config grpcclient.Config
+	flagext.DefaultValues(&config)
+	f[0].m.client = metastoreclient.New(log.NewNopLogger(), config, d)
+	l.stop(t)
+	f[1].stop(t)
+
+	err := f[0].m.waitReadIndex(context.Background())
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
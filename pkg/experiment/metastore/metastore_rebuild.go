@@ -0,0 +1,160 @@
+package metastore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/concurrency"
+	"github.com/hashicorp/raft"
+	"github.com/oklog/ulid"
+	"github.com/thanos-io/objstore"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/pkg/experiment/query_backend/block"
+)
+
+const (
+	boltDBRebuildName       = "metastore_rebuild.boltdb"
+	rebuildReadConcurrency  = 16
+	rebuildProgressInterval = 10000
+)
+
+// rebuildFromBucket reconstructs the metastore state from the metadata
+// of the block objects, and installs it as the initial raft snapshot.
+// It reports whether any blocks have been found.
+func (m *Metastore) rebuildFromBucket(ctx context.Context, configuration raft.Configuration) (bool, error) {
+	_ = level.Warn(m.logger).Log("msg", "rebuilding state from block objects")
+	p := filepath.Join(m.config.DataDir, boltDBRebuildName)
+	index, err := rebuildState(ctx, m.logger, m.snapshotBackup.bucket, m.config, m.metrics, p)
+	defer func() {
+		_ = os.Remove(p)
+	}()
+	if err != nil || index == 0 {
+		return false, err
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	if err = m.installSnapshot(index, configuration, f); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// rebuildState creates a new database at the given path, and adds all the
+// blocks found in the object storage, in the order of their creation. The
+// function returns the raft index of the last added block, as if they were
+// added with raft commands: the index is used for the initial raft snapshot.
+//
+// Objects without metadata are skipped. Blocks that have been compacted
+// into other blocks, but not yet deleted, are skipped as well.
+func rebuildState(
+	ctx context.Context,
+	logger log.Logger,
+	bucket objstore.Bucket,
+	config Config,
+	metrics *metastoreMetrics,
+	path string,
+) (index uint64, err error) {
+	blocks, err := readBlockMetadata(ctx, logger, bucket)
+	if err != nil || len(blocks) == 0 {
+		return 0, err
+	}
+	if err = os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return 0, err
+	}
+	db := newDB(config, logger, metrics)
+	db.path = path
+	if err = db.open(false); err != nil {
+		return 0, err
+	}
+	defer db.shutdown()
+	state := newMetastoreState(logger, db, nil, &config.Compaction)
+	for _, b := range blocks {
+		index++
+		cmd := &raft.Log{Index: index, AppendedAt: time.UnixMilli(int64(ulid.MustParse(b.Id).Time()))}
+		if _, err = state.applyAddBlock(cmd, &metastorev1.AddBlockRequest{Block: b}); err != nil {
+			return 0, fmt.Errorf("failed to add block %s: %w", b.Id, err)
+		}
+		if index%rebuildProgressInterval == 0 {
+			_ = level.Info(logger).Log("msg", "rebuilding state", "blocks", index, "total", len(blocks))
+		}
+	}
+	_ = level.Info(logger).Log("msg", "state rebuilt from block objects", "blocks", len(blocks))
+	return index, db.boltdb.Sync()
+}
+
+// readBlockMetadata reads the metadata of all the blocks in the object
+// storage, except for the compaction sources. The blocks are ordered by ID.
+func readBlockMetadata(ctx context.Context, logger log.Logger, bucket objstore.Bucket) ([]*metastorev1.BlockMeta, error) {
+	var paths []string
+	for _, dir := range []string{block.DirPathSegment, block.DirPathBlock} {
+		err := bucket.Iter(ctx, dir, func(path string) error {
+			if _, id, ok := parseObjectPath(path); ok {
+				if _, err := ulid.Parse(id); err == nil {
+					paths = append(paths, path)
+				}
+			}
+			return nil
+		}, objstore.WithRecursiveIter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", dir, err)
+		}
+	}
+	var (
+		mu       sync.Mutex
+		blocks   = make([]*metastorev1.BlockMeta, 0, len(paths))
+		sources  = make(map[string]struct{})
+		skipped  int
+		readPath = func(ctx context.Context, i int) error {
+			md, src, err := block.ReadMetadata(ctx, bucket, paths[i])
+			if err != nil {
+				if errors.Is(err, block.ErrNoMetadata) || bucket.IsObjNotFoundErr(err) {
+					mu.Lock()
+					skipped++
+					mu.Unlock()
+					_ = level.Debug(logger).Log("msg", "skipping object", "path", paths[i], "err", err)
+					return nil
+				}
+				return fmt.Errorf("failed to read metadata of %s: %w", paths[i], err)
+			}
+			mu.Lock()
+			blocks = append(blocks, md)
+			for _, s := range src {
+				sources[s] = struct{}{}
+			}
+			mu.Unlock()
+			return nil
+		}
+	)
+	if err := concurrency.ForEachJob(ctx, len(paths), rebuildReadConcurrency, readPath); err != nil {
+		return nil, err
+	}
+	if skipped > 0 {
+		_ = level.Warn(logger).Log("msg", "objects without metadata skipped", "count", skipped)
+	}
+	compacted := len(blocks)
+	blocks = slices.DeleteFunc(blocks, func(b *metastorev1.BlockMeta) bool {
+		_, ok := sources[b.Id]
+		return ok
+	})
+	compacted -= len(blocks)
+	slices.SortFunc(blocks, func(a, b *metastorev1.BlockMeta) int {
+		return strings.Compare(a.Id, b.Id)
+	})
+	_ = level.Info(logger).Log("msg", "read block metadata", "objects", len(paths), "blocks", len(blocks), "compacted", compacted)
+	return blocks, nil
+}
@@ -0,0 +1,78 @@
+package metastore
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/oklog/ulid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/pkg/experiment/query_backend/block"
+	"github.com/grafana/pyroscope/pkg/util"
+)
+
+func uploadBlockWithMetadata(t *testing.T, bucket objstore.Bucket, b *metastorev1.BlockMeta, sources ...string) {
+	var buf bytes.Buffer
+	buf.WriteString(b.Id)
+	require.NoError(t, block.WriteMetadata(&buf, b, sources))
+	require.NoError(t, bucket.Upload(context.Background(), block.ObjectPath(b), &buf))
+}
+
+func Test_RebuildState(t *testing.T) {
+	ctx := context.Background()
+	bucket := objstore.NewInMemBucket()
+
+	newBlock := func(ts uint64, tenant string, level uint32) *metastorev1.BlockMeta {
+		return &metastorev1.BlockMeta{
+			Id:              ulid.MustNew(ts, nil).String(),
+			Shard:           1,
+			TenantId:        tenant,
+			CompactionLevel: level,
+		}
+	}
+
+	segments := make([]*metastorev1.BlockMeta, 4)
+	for i := range segments {
+		segments[i] = newBlock(uint64(i+1), "", 0)
+		uploadBlockWithMetadata(t, bucket, segments[i])
+	}
+	// The first two segments have been compacted, but not yet deleted.
+	compacted := newBlock(10, "tenant", 1)
+	uploadBlockWithMetadata(t, bucket, compacted, segments[0].Id, segments[1].Id)
+	// Objects without metadata are ignored.
+	legacy := newBlock(11, "", 0)
+	require.NoError(t, bucket.Upload(ctx, block.ObjectPath(legacy), bytes.NewReader([]byte(legacy.Id))))
+
+	config := Config{DataDir: t.TempDir()}
+	path := filepath.Join(config.DataDir, boltDBRebuildName)
+	index, err := rebuildState(ctx, util.Logger, bucket, config, newMetastoreMetrics(nil), path)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), index)
+
+	db := newDB(config, util.Logger, newMetastoreMetrics(nil))
+	db.path = path
+	require.NoError(t, db.open(true))
+	defer db.shutdown()
+	state := newMetastoreState(util.Logger, db, nil, &config.Compaction)
+	require.NoError(t, state.restore(db))
+
+	assert.False(t, state.hasBlock(1, segments[0].Id))
+	assert.False(t, state.hasBlock(1, segments[1].Id))
+	assert.True(t, state.hasBlock(1, segments[2].Id))
+	assert.True(t, state.hasBlock(1, segments[3].Id))
+	assert.True(t, state.hasBlock(1, compacted.Id))
+	assert.False(t, state.hasBlock(1, legacy.Id))
+}
+
+func Test_RebuildState_NoBlocks(t *testing.T) {
+	config := Config{DataDir: t.TempDir()}
+	path := filepath.Join(config.DataDir, boltDBRebuildName)
+	index, err := rebuildState(context.Background(), util.Logger, objstore.NewInMemBucket(), config, newMetastoreMetrics(nil), path)
+	require.NoError(t, err)
+	assert.Zero(t, index)
+}
@@ -0,0 +1,332 @@
+package metastore
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/hashicorp/raft"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/pyroscope/pkg/util"
+)
+
+// The metastore state only lives in the raft snapshots and logs, which
+// are stored on the local volumes of the metastore nodes. The leader
+// periodically uploads the latest raft snapshot to the object storage,
+// so that the state can be restored if all the volumes are lost.
+//
+// A node that bootstraps the cluster (no local state is found) may restore
+// the state from the latest snapshot in the object storage. If there is
+// no snapshot, the state can be rebuilt from the metadata of the block
+// objects (see metastore_rebuild.go). In both cases, the state is installed
+// into the local snapshot store as the initial raft snapshot, which
+// includes the bootstrap cluster configuration.
+
+const (
+	snapshotBackupDir    = "metastore/snapshots/"
+	snapshotBackupSuffix = ".snapshot"
+
+	// Term of the initial raft snapshot created from the object storage.
+	// The cluster is bootstrapped anew, and the terms of the original
+	// cluster are meaningless.
+	snapshotRestoreTerm = 1
+)
+
+type SnapshotBackupConfig struct {
+	Interval           time.Duration `yaml:"interval"`
+	Retention          int           `yaml:"retention"`
+	RestoreOnBootstrap bool          `yaml:"restore_on_bootstrap"`
+	RebuildOnBootstrap bool          `yaml:"rebuild_on_bootstrap"`
+}
+
+func (cfg *SnapshotBackupConfig) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	f.DurationVar(&cfg.Interval, prefix+"interval", time.Hour, "How often the leader uploads the latest raft snapshot to the object storage. 0 to disable.")
+	f.IntVar(&cfg.Retention, prefix+"retention", 24, "Number of the most recent snapshots kept in the object storage.")
+	f.BoolVar(&cfg.RestoreOnBootstrap, prefix+"restore-on-bootstrap", false, "Restore the state from the latest snapshot in the object storage when bootstrapping the cluster, if no local state is found.")
+	f.BoolVar(&cfg.RebuildOnBootstrap, prefix+"rebuild-on-bootstrap", false, "Rebuild the state from the metadata of the block objects when bootstrapping the cluster, if no local state and no snapshot in the object storage is found.")
+}
+
+func (cfg *SnapshotBackupConfig) Validate() error {
+	if cfg.Interval < 0 {
+		return fmt.Errorf("snapshot backup interval must not be negative")
+	}
+	if cfg.Interval > 0 && cfg.Retention <= 0 {
+		return fmt.Errorf("snapshot backup retention must be greater than 0")
+	}
+	return nil
+}
+
+type snapshotBackupMetrics struct {
+	uploads          prometheus.Counter
+	uploadFailures   prometheus.Counter
+	lastUploadIndex  prometheus.Gauge
+	lastUploadTime   prometheus.Gauge
+	uploadDuration   prometheus.Histogram
+	deletedSnapshots prometheus.Counter
+}
+
+func newSnapshotBackupMetrics(reg prometheus.Registerer) *snapshotBackupMetrics {
+	m := &snapshotBackupMetrics{
+		uploads: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "pyroscope",
+			Name:      "metastore_snapshot_backup_uploads_total",
+			Help:      "The number of raft snapshots uploaded to the object storage",
+		}),
+		uploadFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "pyroscope",
+			Name:      "metastore_snapshot_backup_upload_failures_total",
+			Help:      "The number of failed raft snapshot uploads",
+		}),
+		lastUploadIndex: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "pyroscope",
+			Name:      "metastore_snapshot_backup_last_upload_index",
+			Help:      "The raft index of the last snapshot uploaded to the object storage",
+		}),
+		lastUploadTime: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "pyroscope",
+			Name:      "metastore_snapshot_backup_last_upload_timestamp_seconds",
+			Help:      "The time of the last successful snapshot upload",
+		}),
+		uploadDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: "pyroscope",
+			Name:      "metastore_snapshot_backup_upload_duration_seconds",
+			Buckets:   prometheus.ExponentialBucketsRange(0.1, 600, 20),
+		}),
+		deletedSnapshots: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "pyroscope",
+			Name:      "metastore_snapshot_backup_deleted_snapshots_total",
+			Help:      "The number of snapshots deleted from the object storage due to retention",
+		}),
+	}
+	if reg != nil {
+		util.RegisterOrGet(reg, m.uploads)
+		util.RegisterOrGet(reg, m.uploadFailures)
+		util.RegisterOrGet(reg, m.lastUploadIndex)
+		util.RegisterOrGet(reg, m.lastUploadTime)
+		util.RegisterOrGet(reg, m.uploadDuration)
+		util.RegisterOrGet(reg, m.deletedSnapshots)
+	}
+	return m
+}
+
+type snapshotBackup struct {
+	config  *SnapshotBackupConfig
+	logger  log.Logger
+	bucket  objstore.Bucket
+	metrics *snapshotBackupMetrics
+	// Index of the last uploaded snapshot.
+	lastIndex uint64
+}
+
+func newSnapshotBackup(
+	config *SnapshotBackupConfig,
+	logger log.Logger,
+	bucket objstore.Bucket,
+	reg prometheus.Registerer,
+) *snapshotBackup {
+	return &snapshotBackup{
+		config:  config,
+		logger:  logger,
+		bucket:  bucket,
+		metrics: newSnapshotBackupMetrics(reg),
+	}
+}
+
+// snapshotBackupPath returns the path of the snapshot object. The raft
+// index is zero-padded, so that the lexicographical order of the paths
+// matches the order of the snapshots.
+func snapshotBackupPath(index uint64) string {
+	return fmt.Sprintf("%s%020d%s", snapshotBackupDir, index, snapshotBackupSuffix)
+}
+
+func parseSnapshotBackupPath(p string) (index uint64, ok bool) {
+	name := path.Base(p)
+	if !strings.HasSuffix(name, snapshotBackupSuffix) {
+		return 0, false
+	}
+	index, err := strconv.ParseUint(strings.TrimSuffix(name, snapshotBackupSuffix), 10, 64)
+	return index, err == nil
+}
+
+// upload uploads the latest snapshot from the local snapshot store,
+// unless it has been uploaded already.
+func (b *snapshotBackup) upload(ctx context.Context, snapshots raft.SnapshotStore) error {
+	list, err := snapshots.List()
+	if err != nil {
+		return fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	if len(list) == 0 || list[0].Index <= b.lastIndex {
+		return nil
+	}
+	t1 := time.Now()
+	meta, r, err := snapshots.Open(list[0].ID)
+	if err != nil {
+		return fmt.Errorf("failed to open snapshot %s: %w", list[0].ID, err)
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+	p := snapshotBackupPath(meta.Index)
+	if err = b.bucket.Upload(ctx, p, r); err != nil {
+		b.metrics.uploadFailures.Inc()
+		return fmt.Errorf("failed to upload snapshot %s: %w", meta.ID, err)
+	}
+	b.lastIndex = meta.Index
+	b.metrics.uploads.Inc()
+	b.metrics.uploadDuration.Observe(time.Since(t1).Seconds())
+	b.metrics.lastUploadIndex.Set(float64(meta.Index))
+	b.metrics.lastUploadTime.SetToCurrentTime()
+	level.Info(b.logger).Log("msg", "uploaded snapshot", "path", p, "index", meta.Index, "size", meta.Size, "duration", time.Since(t1))
+	return b.enforceRetention(ctx)
+}
+
+// enforceRetention deletes all the snapshots but the most recent ones.
+func (b *snapshotBackup) enforceRetention(ctx context.Context) error {
+	paths, err := b.list(ctx)
+	if err != nil {
+		return err
+	}
+	if len(paths) <= b.config.Retention {
+		return nil
+	}
+	for _, p := range paths[:len(paths)-b.config.Retention] {
+		if err = b.bucket.Delete(ctx, p); err != nil && !b.bucket.IsObjNotFoundErr(err) {
+			return fmt.Errorf("failed to delete snapshot %s: %w", p, err)
+		}
+		b.metrics.deletedSnapshots.Inc()
+		level.Debug(b.logger).Log("msg", "deleted snapshot", "path", p)
+	}
+	return nil
+}
+
+// list returns the paths of the snapshots in the object
+// storage, ordered by the raft index, from oldest to newest.
+func (b *snapshotBackup) list(ctx context.Context) ([]string, error) {
+	var paths []string
+	err := b.bucket.Iter(ctx, snapshotBackupDir, func(p string) error {
+		if _, ok := parseSnapshotBackupPath(p); ok {
+			paths = append(paths, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	slices.Sort(paths)
+	return paths, nil
+}
+
+// latest returns the path and the raft index of the most recent snapshot
+// in the object storage. If there are no snapshots, the path is empty.
+func (b *snapshotBackup) latest(ctx context.Context) (p string, index uint64, err error) {
+	paths, err := b.list(ctx)
+	if err != nil || len(paths) == 0 {
+		return "", 0, err
+	}
+	p = paths[len(paths)-1]
+	index, _ = parseSnapshotBackupPath(p)
+	return p, index, nil
+}
+
+func (m *Metastore) snapshotBackupLoop() {
+	t := time.NewTicker(m.config.SnapshotBackup.Interval)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() {
+		cancel()
+		t.Stop()
+		m.wg.Done()
+	}()
+	go func() {
+		select {
+		case <-m.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	for {
+		select {
+		case <-m.done:
+			return
+		case <-t.C:
+			if m.raft.State() != raft.Leader {
+				continue
+			}
+			if err := m.backupSnapshot(ctx); err != nil {
+				_ = level.Error(m.logger).Log("msg", "failed to back up snapshot", "err", err)
+			}
+		}
+	}
+}
+
+func (m *Metastore) backupSnapshot(ctx context.Context) error {
+	// Make sure the snapshot includes the most recent state: if nothing
+	// has changed since the last snapshot, the latest one is uploaded.
+	err := m.raft.Snapshot().Error()
+	if err != nil && !errors.Is(err, raft.ErrNothingNewToSnapshot) {
+		return fmt.Errorf("failed to create snapshot: %w", err)
+	}
+	return m.snapshotBackup.upload(ctx, m.snapshotStore)
+}
+
+// restoreFromBucket installs the initial raft snapshot with the state
+// restored from the object storage. It reports whether the state has
+// been restored: if there is nothing to restore, the cluster should be
+// bootstrapped as usual.
+func (m *Metastore) restoreFromBucket(configuration raft.Configuration) (bool, error) {
+	ctx := context.Background()
+	cfg := m.config.SnapshotBackup
+	if cfg.RestoreOnBootstrap {
+		p, index, err := m.snapshotBackup.latest(ctx)
+		if err != nil {
+			return false, err
+		}
+		if p != "" {
+			_ = level.Info(m.logger).Log("msg", "restoring state from snapshot", "path", p, "index", index)
+			r, err := m.snapshotBackup.bucket.Get(ctx, p)
+			if err != nil {
+				return false, fmt.Errorf("failed to download snapshot %s: %w", p, err)
+			}
+			defer func() {
+				_ = r.Close()
+			}()
+			if err = m.installSnapshot(index, configuration, r); err != nil {
+				return false, err
+			}
+			m.snapshotBackup.lastIndex = index
+			return true, nil
+		}
+		_ = level.Warn(m.logger).Log("msg", "no snapshot found in the object storage")
+	}
+	if cfg.RebuildOnBootstrap {
+		return m.rebuildFromBucket(ctx, configuration)
+	}
+	return false, nil
+}
+
+// installSnapshot creates a raft snapshot in the local snapshot store.
+// The snapshot is restored by raft when the node starts.
+func (m *Metastore) installSnapshot(index uint64, configuration raft.Configuration, r io.Reader) (err error) {
+	sink, err := m.snapshotStore.Create(raft.SnapshotVersionMax, index, snapshotRestoreTerm, configuration, index, m.transport)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot: %w", err)
+	}
+	if _, err = io.Copy(sink, r); err != nil {
+		_ = sink.Cancel()
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err = sink.Close(); err != nil {
+		return fmt.Errorf("failed to close snapshot: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package metastore
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/raft"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/pyroscope/pkg/util"
+)
+
+func createTestSnapshot(t *testing.T, store raft.SnapshotStore, index uint64, data []byte) {
+	sink, err := store.Create(raft.SnapshotVersionMax, index, 1, raft.Configuration{}, index, nil)
+	require.NoError(t, err)
+	_, err = sink.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, sink.Close())
+}
+
+func Test_SnapshotBackup_Upload(t *testing.T) {
+	ctx := context.Background()
+	bucket := objstore.NewInMemBucket()
+	store := raft.NewInmemSnapshotStore()
+	config := SnapshotBackupConfig{Retention: 2}
+	b := newSnapshotBackup(&config, util.Logger, bucket, prometheus.NewRegistry())
+
+	// Nothing to upload.
+	require.NoError(t, b.upload(ctx, store))
+	assert.Empty(t, bucket.Objects())
+
+	createTestSnapshot(t, store, 10, []byte("snapshot-10"))
+	require.NoError(t, b.upload(ctx, store))
+	assert.Equal(t, []byte("snapshot-10"), bucket.Objects()[snapshotBackupPath(10)])
+
+	// The snapshot has been uploaded already.
+	require.NoError(t, bucket.Delete(ctx, snapshotBackupPath(10)))
+	require.NoError(t, b.upload(ctx, store))
+	assert.Empty(t, bucket.Objects())
+
+	for _, index := range []uint64{20, 100, 1000} {
+		createTestSnapshot(t, store, index, []byte("snapshot"))
+		require.NoError(t, b.upload(ctx, store))
+	}
+	// Only the most recent snapshots are retained.
+	paths, err := b.list(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{snapshotBackupPath(100), snapshotBackupPath(1000)}, paths)
+
+	p, index, err := b.latest(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, snapshotBackupPath(1000), p)
+	assert.Equal(t, uint64(1000), index)
+}
+
+func Test_SnapshotBackup_Restore(t *testing.T) {
+	ctx := context.Background()
+	bucket := objstore.NewInMemBucket()
+
+	// Create a snapshot of the state, and upload it.
+	m := initState(t)
+	addLevel0Blocks(m, 5)
+	s, err := m.db.createSnapshot()
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	_, err = s.tx.WriteTo(&buf)
+	s.Release()
+	require.NoError(t, err)
+	require.NoError(t, bucket.Upload(ctx, snapshotBackupPath(42), &buf))
+
+	config := Config{
+		DataDir:        t.TempDir(),
+		SnapshotBackup: SnapshotBackupConfig{RestoreOnBootstrap: true},
+	}
+	snapshots, err := raft.NewFileSnapshotStore(t.TempDir(), 1, io.Discard)
+	require.NoError(t, err)
+	transport, err := raft.NewTCPTransport("127.0.0.1:0", nil, 1, time.Second, io.Discard)
+	require.NoError(t, err)
+	defer transport.Close()
+	restored := &Metastore{
+		config:         config,
+		logger:         util.Logger,
+		snapshotStore:  snapshots,
+		transport:      transport,
+		snapshotBackup: newSnapshotBackup(&config.SnapshotBackup, util.Logger, bucket, prometheus.NewRegistry()),
+	}
+	configuration := raft.Configuration{Servers: []raft.Server{{
+		Suffrage: raft.Voter,
+		ID:       "node-a",
+		Address:  transport.LocalAddr(),
+	}}}
+	ok, err := restored.restoreFromBucket(configuration)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// The state is installed as the initial raft snapshot.
+	list, err := snapshots.List()
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, uint64(42), list[0].Index)
+	assert.Equal(t, configuration, list[0].Configuration)
+
+	_, r, err := snapshots.Open(list[0].ID)
+	require.NoError(t, err)
+	db := newDB(config, util.Logger, newMetastoreMetrics(nil))
+	require.NoError(t, db.open(false))
+	defer db.shutdown()
+	state := newMetastoreState(util.Logger, db, nil, &config.Compaction)
+	require.NoError(t, newFSM(util.Logger, db, state).Restore(r))
+	for i := 0; i < 5; i++ {
+		assert.True(t, state.hasBlock(0, createBlock(i, 0, "", 0).Id))
+	}
+}
+
+func Test_SnapshotBackup_NothingToRestore(t *testing.T) {
+	config := Config{
+		DataDir:        t.TempDir(),
+		SnapshotBackup: SnapshotBackupConfig{RestoreOnBootstrap: true, RebuildOnBootstrap: true},
+	}
+	m := &Metastore{
+		config:         config,
+		logger:         util.Logger,
+		metrics:        newMetastoreMetrics(nil),
+		snapshotBackup: newSnapshotBackup(&config.SnapshotBackup, util.Logger, objstore.NewInMemBucket(), nil),
+	}
+	ok, err := m.restoreFromBucket(raft.Configuration{})
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
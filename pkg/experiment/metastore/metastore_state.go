@@ -11,6 +11,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"go.etcd.io/bbolt"
 
+	compactorv1 "github.com/grafana/pyroscope/api/gen/proto/go/compactor/v1"
 	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
 	"github.com/grafana/pyroscope/pkg/experiment/metastore/compactionpb"
 )
@@ -35,6 +36,17 @@ type metastoreState struct {
 	compactionMutex          sync.Mutex
 	compactionJobBlockQueues map[tenantShard]*compactionJobBlockQueue
 	compactionJobQueue       *jobQueue
+	// Blocks of the compaction jobs cancelled after reaching the max
+	// number of failures, by block ID. Quarantined blocks are excluded
+	// from compaction but remain available for queries.
+	quarantinedBlocks map[string]string
+	// Recently completed compaction jobs. The history is only kept for
+	// admin purposes and is not persisted.
+	completedJobs []*compactorv1.CompactionJob
+
+	// Blocks pending deletion from the object storage, by object path.
+	tombstonesMutex sync.Mutex
+	tombstones      map[string]*compactionpb.BlockTombstone
 
 	db *boltdb
 }
@@ -56,6 +68,8 @@ func newMetastoreState(logger log.Logger, db *boltdb, reg prometheus.Registerer,
 		db:                       db,
 		compactionJobBlockQueues: make(map[tenantShard]*compactionJobBlockQueue),
 		compactionJobQueue:       newJobQueue(compaction.JobLeaseDuration.Nanoseconds()),
+		quarantinedBlocks:        make(map[string]string),
+		tombstones:               make(map[string]*compactionpb.BlockTombstone),
 		compactionMetrics:        newCompactionMetrics(reg),
 		compactionConfig:         compaction,
 	}
@@ -64,12 +78,17 @@ func newMetastoreState(logger log.Logger, db *boltdb, reg prometheus.Registerer,
 func (m *metastoreState) reset(db *boltdb) {
 	m.shardsMutex.Lock()
 	m.compactionMutex.Lock()
+	m.tombstonesMutex.Lock()
 	clear(m.shards)
 	clear(m.compactionJobBlockQueues)
+	clear(m.quarantinedBlocks)
+	m.completedJobs = nil
+	clear(m.tombstones)
 	m.compactionJobQueue = newJobQueue(m.compactionConfig.JobLeaseDuration.Nanoseconds())
 	m.db = db
 	m.shardsMutex.Unlock()
 	m.compactionMutex.Unlock()
+	m.tombstonesMutex.Unlock()
 }
 
 func (m *metastoreState) getOrCreateShard(shardID uint32) *metastoreShard {
@@ -89,6 +108,9 @@ func (m *metastoreState) restore(db *boltdb) error {
 		if err := m.restoreBlockMetadata(tx); err != nil {
 			return fmt.Errorf("failed to restore metadata entries: %w", err)
 		}
+		if err := m.restoreTombstones(tx); err != nil {
+			return fmt.Errorf("failed to restore block tombstones: %w", err)
+		}
 		return m.restoreCompactionPlan(tx)
 	})
 }
@@ -221,6 +243,9 @@ func (m *metastoreState) loadCompactionPlan(b *bbolt.Bucket, blockQueue *compact
 				return fmt.Errorf("failed to unmarshal job %q: %w", string(k), err)
 			}
 			m.compactionJobQueue.enqueue(&job)
+			if job.Status == compactionpb.CompactionStatus_COMPACTION_STATUS_CANCELLED {
+				m.quarantineBlocks(&job)
+			}
 			level.Debug(m.logger).Log(
 				"msg", "restored job into queue",
 				"job", job.Name,
@@ -15,7 +15,9 @@ func (m *Metastore) GetProfileStats(
 	ctx context.Context,
 	r *metastorev1.GetProfileStatsRequest,
 ) (*typesv1.GetProfileStatsResponse, error) {
-	// TODO(kolesnikovae): ReadIndex
+	if err := m.waitReadIndex(ctx); err != nil {
+		return nil, err
+	}
 	return m.state.getProfileStats(r.TenantId, ctx)
 }
 
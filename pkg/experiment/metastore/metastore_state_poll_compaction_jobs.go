@@ -46,6 +46,7 @@ type pollStateUpdate struct {
 	updatedBlockQueues map[tenantShard][]uint32
 	deletedJobs        map[tenantShard][]string
 	updatedJobs        []string
+	newTombstones      []*compactionpb.BlockTombstone
 }
 
 func (m *metastoreState) pollCompactionJobs(request *compactorv1.PollCompactionJobsRequest, raftIndex uint64, raftAppendedAtNanos int64) (resp *compactorv1.PollCompactionJobsResponse, err error) {
@@ -100,8 +101,16 @@ func (m *metastoreState) pollCompactionJobs(request *compactorv1.PollCompactionJ
 				blockTenantShard := tenantShard{tenant: b.TenantId, shard: b.Shard}
 				stateUpdate.updatedBlockQueues[blockTenantShard] = append(stateUpdate.updatedBlockQueues[blockTenantShard], b.CompactionLevel)
 			}
-			// finally we'll delete the metadata for source blocks (this doesn't delete blocks from object store)
+			// finally we'll delete the metadata for source blocks; the objects
+			// are deleted from the object store by the block cleaner later on
+			sourceBlocks := make([]*metastorev1.BlockMeta, 0, len(job.Blocks))
 			for _, b := range job.Blocks {
+				if md := m.shards[job.Shard].segments[b]; md != nil {
+					sourceBlocks = append(sourceBlocks, md)
+					tombstone := newBlockTombstone(md, job.Name, raftAppendedAtNanos)
+					m.addTombstone(tombstone)
+					stateUpdate.newTombstones = append(stateUpdate.newTombstones, tombstone)
+				}
 				level.Debug(m.logger).Log(
 					"msg", "deleting source block",
 					"block", b,
@@ -115,6 +124,7 @@ func (m *metastoreState) pollCompactionJobs(request *compactorv1.PollCompactionJ
 					fmt.Sprint(job.Shard), job.TenantId, fmt.Sprint(job.CompactionLevel)).Inc()
 			}
 			m.shardsMutex.Unlock()
+			m.addCompletedJob(job, sourceBlocks, jobUpdate.CompletedJob.Blocks)
 		case compactorv1.CompactionStatus_COMPACTION_STATUS_IN_PROGRESS:
 			level.Debug(m.logger).Log(
 				"msg", "compaction job still in progress",
@@ -125,8 +135,24 @@ func (m *metastoreState) pollCompactionJobs(request *compactorv1.PollCompactionJ
 			)
 			m.compactionJobQueue.update(jobUpdate.JobName, raftAppendedAtNanos, jobUpdate.RaftLogIndex)
 			stateUpdate.updatedJobs = append(stateUpdate.updatedJobs, job.Name)
+		case compactorv1.CompactionStatus_COMPACTION_STATUS_UNSPECIFIED:
+			// The worker has abandoned the job (e.g., on shutdown):
+			// it can be assigned to another worker right away.
+			level.Info(m.logger).Log(
+				"msg", "compaction job released by worker",
+				"job", job.Name,
+				"tenant", job.TenantId,
+				"shard", job.Shard,
+				"level", job.CompactionLevel,
+			)
+			if m.compactionJobQueue.release(job.Name, jobUpdate.RaftLogIndex) {
+				stateUpdate.updatedJobs = append(stateUpdate.updatedJobs, job.Name)
+				m.compactionMetrics.releasedJobs.WithLabelValues(
+					fmt.Sprint(job.Shard), job.TenantId, fmt.Sprint(job.CompactionLevel)).Inc()
+			}
 		case compactorv1.CompactionStatus_COMPACTION_STATUS_FAILURE:
 			job.Failures += 1
+			job.LastFailureReason = truncateFailureReason(jobUpdate.FailureReason)
 			level.Warn(m.logger).Log(
 				"msg", "compaction job failed",
 				"job", job.Name,
@@ -134,6 +160,7 @@ func (m *metastoreState) pollCompactionJobs(request *compactorv1.PollCompactionJ
 				"shard", job.Shard,
 				"level", job.CompactionLevel,
 				"failures", job.Failures,
+				"reason", job.LastFailureReason,
 			)
 			if int(job.Failures) >= m.compactionConfig.JobMaxFailures {
 				level.Warn(m.logger).Log(
@@ -145,10 +172,12 @@ func (m *metastoreState) pollCompactionJobs(request *compactorv1.PollCompactionJ
 					"failures", job.Failures,
 				)
 				m.compactionJobQueue.cancel(job.Name)
+				m.quarantineBlocks(job)
 				stateUpdate.updatedJobs = append(stateUpdate.updatedJobs, job.Name)
 				m.compactionMetrics.discardedJobs.WithLabelValues(
 					fmt.Sprint(job.Shard), job.TenantId, fmt.Sprint(job.CompactionLevel)).Inc()
 			} else {
+				backoff := m.compactionConfig.retryBackoff(job.Failures)
 				level.Warn(m.logger).Log(
 					"msg", "adding failed compaction job back to the queue",
 					"job", job.Name,
@@ -156,11 +185,13 @@ func (m *metastoreState) pollCompactionJobs(request *compactorv1.PollCompactionJ
 					"shard", job.Shard,
 					"level", job.CompactionLevel,
 					"failures", job.Failures,
+					"backoff", backoff,
 				)
 				m.compactionJobQueue.evict(job.Name, math.MaxInt64)
 				job.Status = compactionpb.CompactionStatus_COMPACTION_STATUS_UNSPECIFIED
 				job.RaftLogIndex = 0
 				job.LeaseExpiresAt = 0
+				job.RetryNotBefore = raftAppendedAtNanos + backoff.Nanoseconds()
 				m.compactionJobQueue.enqueue(job)
 				stateUpdate.updatedJobs = append(stateUpdate.updatedJobs, job.Name)
 				m.compactionMetrics.retriedJobs.WithLabelValues(
@@ -360,6 +391,18 @@ func (m *metastoreState) writeToDb(sTable *pollStateUpdate) error {
 				}
 			}
 		}
+		if len(sTable.newTombstones) > 0 {
+			tdb, err := getBlockTombstoneBucket(tx)
+			if err != nil {
+				return err
+			}
+			for _, t := range sTable.newTombstones {
+				tValue, _ := t.MarshalVT()
+				if err = tdb.Put([]byte(t.Path), tValue); err != nil {
+					return err
+				}
+			}
+		}
 		for _, jobName := range sTable.updatedJobs {
 			job := m.findJob(jobName)
 			if job == nil {
@@ -6,11 +6,14 @@ import (
 	"time"
 
 	"github.com/hashicorp/raft"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/require"
 
 	compactorv1 "github.com/grafana/pyroscope/api/gen/proto/go/compactor/v1"
 	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
 	"github.com/grafana/pyroscope/pkg/experiment/metastore/compactionpb"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/raftlogpb"
+	"github.com/grafana/pyroscope/pkg/util"
 )
 
 func Test_JobAssignments(t *testing.T) {
@@ -338,3 +341,163 @@ func addLevel1Blocks(m *metastoreState, tenant string, count int) {
 		_, _ = m.applyAddBlock(raftLog, &metastorev1.AddBlockRequest{Block: b})
 	}
 }
+
+func Test_FailedCompaction_RetryAndQuarantine(t *testing.T) {
+	m := initState(t)
+	m.compactionConfig.JobMaxFailures = 2
+	m.compactionConfig.JobRetryBackoffMin = time.Second
+	addLevel0Blocks(m, 20)
+
+	resp, err := m.pollCompactionJobs(&compactorv1.PollCompactionJobsRequest{JobCapacity: 1}, 20, 20)
+	require.NoError(t, err)
+	job := resp.CompactionJobs[0]
+
+	statusUpdates := []*compactorv1.CompactionJobStatus{{
+		JobName:       job.Name,
+		Status:        compactorv1.CompactionStatus_COMPACTION_STATUS_FAILURE,
+		FailureReason: "corrupted block",
+		RaftLogIndex:  20,
+	}}
+	resp, err = m.pollCompactionJobs(&compactorv1.PollCompactionJobsRequest{JobStatusUpdates: statusUpdates, JobCapacity: 1}, 21, 21)
+	require.NoError(t, err)
+	require.Empty(t, resp.CompactionJobs, "the job should not be retried before the backoff expires")
+	queued := m.compactionJobQueue.jobs[job.Name]
+	require.Equal(t, uint32(1), queued.Failures)
+	require.Equal(t, "corrupted block", queued.LastFailureReason)
+	require.Equal(t, int64(21+time.Second), queued.RetryNotBefore)
+	verifyCompactionState(t, m)
+
+	resp, err = m.pollCompactionJobs(&compactorv1.PollCompactionJobsRequest{JobCapacity: 1}, 22, int64(21+time.Second))
+	require.NoError(t, err)
+	require.Len(t, resp.CompactionJobs, 1)
+	require.Equal(t, job.Name, resp.CompactionJobs[0].Name)
+
+	statusUpdates[0].RaftLogIndex = 22
+	_, err = m.pollCompactionJobs(&compactorv1.PollCompactionJobsRequest{JobStatusUpdates: statusUpdates}, 23, int64(22+time.Second))
+	require.NoError(t, err)
+	require.Equal(t, compactionpb.CompactionStatus_COMPACTION_STATUS_CANCELLED, queued.Status)
+	verifyCompactionState(t, m)
+
+	// The blocks are quarantined, but remain available for queries.
+	for _, b := range job.Blocks {
+		_, ok := m.isQuarantined(b.Id)
+		require.True(t, ok)
+		require.NotNil(t, m.findBlock(0, b.Id))
+	}
+	resp, err = m.pollCompactionJobs(&compactorv1.PollCompactionJobsRequest{JobCapacity: 1}, 24, int64(24+time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, resp.CompactionJobs, "the cancelled job should not be assigned")
+
+	// Quarantine survives restarts.
+	restored := newMetastoreState(util.Logger, m.db, prometheus.NewRegistry(), m.compactionConfig)
+	require.NoError(t, restored.restore(m.db))
+	_, ok := restored.isQuarantined(job.Blocks[0].Id)
+	require.True(t, ok)
+
+	// The jobs that are not cancelled are not released.
+	released, err := m.applyReleaseQuarantinedJobs(&raft.Log{Index: 25}, &raftlogpb.ReleaseQuarantinedJobsCommand{Jobs: []string{"unknown"}})
+	require.NoError(t, err)
+	require.Empty(t, released.Jobs)
+
+	// Once released, the job is retried from scratch, and the blocks
+	// are no longer quarantined.
+	released, err = m.applyReleaseQuarantinedJobs(&raft.Log{Index: 26}, &raftlogpb.ReleaseQuarantinedJobsCommand{})
+	require.NoError(t, err)
+	require.Equal(t, []string{job.Name}, released.Jobs)
+	require.Equal(t, uint32(0), queued.Failures)
+	for _, b := range job.Blocks {
+		_, ok = m.isQuarantined(b.Id)
+		require.False(t, ok)
+	}
+	verifyCompactionState(t, m)
+
+	resp, err = m.pollCompactionJobs(&compactorv1.PollCompactionJobsRequest{JobCapacity: 1}, 27, int64(27+time.Hour))
+	require.NoError(t, err)
+	require.Len(t, resp.CompactionJobs, 1)
+	require.Equal(t, job.Name, resp.CompactionJobs[0].Name)
+
+	restored = newMetastoreState(util.Logger, m.db, prometheus.NewRegistry(), m.compactionConfig)
+	require.NoError(t, restored.restore(m.db))
+	_, ok = restored.isQuarantined(job.Blocks[0].Id)
+	require.False(t, ok)
+}
+
+func Test_GetCompactionJobs(t *testing.T) {
+	m := initState(t)
+	addLevel0Blocks(m, 40)
+	addLevel0BlocksForShard(m, 20, 1)
+
+	resp, err := m.pollCompactionJobs(&compactorv1.PollCompactionJobsRequest{JobCapacity: 2}, 40, 40)
+	require.NoError(t, err)
+	require.Len(t, resp.CompactionJobs, 2)
+	_, err = m.pollCompactionJobs(&compactorv1.PollCompactionJobsRequest{
+		JobStatusUpdates: []*compactorv1.CompactionJobStatus{{
+			JobName:      resp.CompactionJobs[0].Name,
+			Status:       compactorv1.CompactionStatus_COMPACTION_STATUS_SUCCESS,
+			CompletedJob: &compactorv1.CompletedJob{Blocks: []*metastorev1.BlockMeta{createBlock(100, 0, "tenant", 1)}},
+			RaftLogIndex: 40,
+		}},
+	}, 41, 41)
+	require.NoError(t, err)
+
+	jobs := m.listCompactionJobs(&compactorv1.GetCompactionRequest{})
+	require.Len(t, jobs, 3)
+	statuses := make(map[compactorv1.CompactionStatus]int)
+	for _, j := range jobs {
+		statuses[j.Status.Status]++
+		require.NotEmpty(t, j.Blocks)
+	}
+	require.Equal(t, map[compactorv1.CompactionStatus]int{
+		compactorv1.CompactionStatus_COMPACTION_STATUS_UNSPECIFIED: 1,
+		compactorv1.CompactionStatus_COMPACTION_STATUS_IN_PROGRESS: 1,
+		compactorv1.CompactionStatus_COMPACTION_STATUS_SUCCESS:     1,
+	}, statuses)
+
+	jobs = m.listCompactionJobs(&compactorv1.GetCompactionRequest{
+		Statuses: []compactorv1.CompactionStatus{compactorv1.CompactionStatus_COMPACTION_STATUS_SUCCESS},
+	})
+	require.Len(t, jobs, 1)
+	require.Equal(t, resp.CompactionJobs[0].Name, jobs[0].Name)
+	require.Len(t, jobs[0].Blocks, 20)
+	require.Equal(t, "b-100", jobs[0].Status.CompletedJob.Blocks[0].Id)
+
+	jobs = m.listCompactionJobs(&compactorv1.GetCompactionRequest{Shards: []uint32{1}, CompactionLevels: []uint32{0}})
+	require.Len(t, jobs, 1)
+	require.Equal(t, uint32(1), jobs[0].Shard)
+
+	require.Empty(t, m.listCompactionJobs(&compactorv1.GetCompactionRequest{TenantIds: []string{"tenant"}}))
+}
+
+func Test_StatusUpdates_Released(t *testing.T) {
+	m := initState(t)
+	addLevel0Blocks(m, 20)
+
+	resp, err := m.pollCompactionJobs(&compactorv1.PollCompactionJobsRequest{JobCapacity: 1}, 20, 20)
+	require.NoError(t, err)
+	require.Len(t, resp.CompactionJobs, 1)
+	job := resp.CompactionJobs[0]
+
+	// The worker releases the job on shutdown: the job is available
+	// right away, without waiting for the lease to expire.
+	statusUpdates := []*compactorv1.CompactionJobStatus{{
+		JobName:      job.Name,
+		Status:       compactorv1.CompactionStatus_COMPACTION_STATUS_UNSPECIFIED,
+		RaftLogIndex: 20,
+	}}
+	_, err = m.pollCompactionJobs(&compactorv1.PollCompactionJobsRequest{JobStatusUpdates: statusUpdates}, 21, 21)
+	require.NoError(t, err)
+	require.Equal(t, compactionpb.CompactionStatus_COMPACTION_STATUS_UNSPECIFIED, m.compactionJobQueue.jobs[job.Name].Status)
+	require.Zero(t, m.compactionJobQueue.jobs[job.Name].Failures)
+	verifyCompactionState(t, m)
+
+	resp, err = m.pollCompactionJobs(&compactorv1.PollCompactionJobsRequest{JobCapacity: 1}, 22, 22)
+	require.NoError(t, err)
+	require.Len(t, resp.CompactionJobs, 1)
+	require.Equal(t, job.Name, resp.CompactionJobs[0].Name)
+	require.Equal(t, uint64(22), resp.CompactionJobs[0].RaftLogIndex)
+
+	// A stale owner can't release the job.
+	_, err = m.pollCompactionJobs(&compactorv1.PollCompactionJobsRequest{JobStatusUpdates: statusUpdates}, 23, 23)
+	require.NoError(t, err)
+	require.Equal(t, compactionpb.CompactionStatus_COMPACTION_STATUS_IN_PROGRESS, m.compactionJobQueue.jobs[job.Name].Status)
+}
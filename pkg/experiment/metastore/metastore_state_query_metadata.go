@@ -21,7 +21,9 @@ func (m *Metastore) QueryMetadata(
 	ctx context.Context,
 	request *metastorev1.QueryMetadataRequest,
 ) (*metastorev1.QueryMetadataResponse, error) {
-	// TODO(kolesnikovae): ReadIndex
+	if err := m.waitReadIndex(ctx); err != nil {
+		return nil, err
+	}
 	return m.state.listBlocksForQuery(ctx, request)
 }
 
@@ -0,0 +1,74 @@
+package metastore
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-kit/log/level"
+	"github.com/hashicorp/raft"
+
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/compactionpb"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/raftlogpb"
+	"github.com/grafana/pyroscope/pkg/util"
+	httputil "github.com/grafana/pyroscope/pkg/util/http"
+)
+
+// ReleaseQuarantinedJobsHandler returns the compaction jobs cancelled after
+// reaching the max number of failures to the queue, for example, once the
+// cause of the failures has been fixed. The jobs are specified with the
+// repeated "job" query parameter; if none is specified, all the cancelled
+// jobs are released. The request must be sent to the leader. The response
+// lists the jobs released.
+func (m *Metastore) ReleaseQuarantinedJobsHandler(w http.ResponseWriter, r *http.Request) {
+	req := &raftlogpb.ReleaseQuarantinedJobsCommand{Jobs: r.URL.Query()["job"]}
+	_, resp, err := applyCommand[*raftlogpb.ReleaseQuarantinedJobsCommand, *raftlogpb.ReleaseQuarantinedJobsCommand](m.raft, req, m.config.Raft.ApplyTimeout)
+	if err != nil {
+		_ = level.Error(m.logger).Log("msg", "failed to release quarantined compaction jobs", "err", err)
+		httputil.Error(w, err)
+		return
+	}
+	released := make([]string, 0)
+	if resp != nil {
+		released = resp.Jobs
+	}
+	util.WriteJSONResponse(w, struct {
+		Released []string `json:"released"`
+	}{Released: released})
+}
+
+// applyReleaseQuarantinedJobs responds with the jobs released.
+func (m *metastoreState) applyReleaseQuarantinedJobs(_ *raft.Log, request *raftlogpb.ReleaseQuarantinedJobsCommand) (*raftlogpb.ReleaseQuarantinedJobsCommand, error) {
+	names := request.Jobs
+	if len(names) == 0 {
+		_, _, _, _, _, names = m.compactionJobQueue.stats()
+	}
+	released := make([]string, 0, len(names))
+	for _, name := range names {
+		job := m.compactionJobQueue.requeue(name)
+		if job == nil {
+			continue
+		}
+		m.releaseBlocks(job)
+		released = append(released, job.Name)
+		level.Info(m.logger).Log(
+			"msg", "released quarantined compaction job",
+			"job", job.Name,
+			"tenant", job.TenantId,
+			"shard", job.Shard,
+			"level", job.CompactionLevel,
+		)
+	}
+	if err := m.writeToDb(&pollStateUpdate{updatedJobs: released}); err != nil {
+		panic(fatalCommandError{fmt.Errorf("error persisting metadata state to db, %w", err)})
+	}
+	return &raftlogpb.ReleaseQuarantinedJobsCommand{Jobs: released}, nil
+}
+
+// releaseBlocks removes the blocks of the job from quarantine.
+func (m *metastoreState) releaseBlocks(job *compactionpb.CompactionJob) {
+	m.compactionMutex.Lock()
+	defer m.compactionMutex.Unlock()
+	for _, b := range job.Blocks {
+		delete(m.quarantinedBlocks, b)
+	}
+}
@@ -26,6 +26,8 @@ const (
 	CommandType_COMMAND_TYPE_UNKNOWN                     CommandType = 0
 	CommandType_COMMAND_TYPE_ADD_BLOCK                   CommandType = 1
 	CommandType_COMMAND_TYPE_POLL_COMPACTION_JOBS_STATUS CommandType = 2
+	CommandType_COMMAND_TYPE_CLEAN_BLOCKS                CommandType = 3
+	CommandType_COMMAND_TYPE_RELEASE_QUARANTINED_JOBS    CommandType = 4
 	// This is a temporary solution.
 	CommandType_COMMAND_TYPE_TRUNCATE CommandType = 4196
 )
@@ -36,12 +38,16 @@ var (
 		0:    "COMMAND_TYPE_UNKNOWN",
 		1:    "COMMAND_TYPE_ADD_BLOCK",
 		2:    "COMMAND_TYPE_POLL_COMPACTION_JOBS_STATUS",
+		3:    "COMMAND_TYPE_CLEAN_BLOCKS",
+		4:    "COMMAND_TYPE_RELEASE_QUARANTINED_JOBS",
 		4196: "COMMAND_TYPE_TRUNCATE",
 	}
 	CommandType_value = map[string]int32{
 		"COMMAND_TYPE_UNKNOWN":                     0,
 		"COMMAND_TYPE_ADD_BLOCK":                   1,
 		"COMMAND_TYPE_POLL_COMPACTION_JOBS_STATUS": 2,
+		"COMMAND_TYPE_CLEAN_BLOCKS":                3,
+		"COMMAND_TYPE_RELEASE_QUARANTINED_JOBS":    4,
 		"COMMAND_TYPE_TRUNCATE":                    4196,
 	}
 )
@@ -175,6 +181,108 @@ func (x *TruncateCommand) GetTimestamp() uint64 {
 	return 0
 }
 
+// CleanBlocksCommand removes the tombstones of the blocks
+// that have been deleted from the object storage.
+type CleanBlocksCommand struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Object paths of the deleted blocks.
+	Paths []string `protobuf:"bytes,1,rep,name=paths,proto3" json:"paths,omitempty"`
+}
+
+func (x *CleanBlocksCommand) Reset() {
+	*x = CleanBlocksCommand{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_experiment_metastore_raftlogpb_raflog_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CleanBlocksCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CleanBlocksCommand) ProtoMessage() {}
+
+func (x *CleanBlocksCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_experiment_metastore_raftlogpb_raflog_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CleanBlocksCommand.ProtoReflect.Descriptor instead.
+func (*CleanBlocksCommand) Descriptor() ([]byte, []int) {
+	return file_experiment_metastore_raftlogpb_raflog_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CleanBlocksCommand) GetPaths() []string {
+	if x != nil {
+		return x.Paths
+	}
+	return nil
+}
+
+// ReleaseQuarantinedJobsCommand returns the compaction jobs cancelled
+// after reaching the max number of failures to the queue, and releases
+// their blocks from quarantine.
+type ReleaseQuarantinedJobsCommand struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Names of the jobs to release. If empty, all the cancelled jobs
+	// are released.
+	Jobs []string `protobuf:"bytes,1,rep,name=jobs,proto3" json:"jobs,omitempty"`
+}
+
+func (x *ReleaseQuarantinedJobsCommand) Reset() {
+	*x = ReleaseQuarantinedJobsCommand{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_experiment_metastore_raftlogpb_raflog_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReleaseQuarantinedJobsCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseQuarantinedJobsCommand) ProtoMessage() {}
+
+func (x *ReleaseQuarantinedJobsCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_experiment_metastore_raftlogpb_raflog_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseQuarantinedJobsCommand.ProtoReflect.Descriptor instead.
+func (*ReleaseQuarantinedJobsCommand) Descriptor() ([]byte, []int) {
+	return file_experiment_metastore_raftlogpb_raflog_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ReleaseQuarantinedJobsCommand) GetJobs() []string {
+	if x != nil {
+		return x.Jobs
+	}
+	return nil
+}
+
 var File_experiment_metastore_raftlogpb_raflog_proto protoreflect.FileDescriptor
 
 var file_experiment_metastore_raftlogpb_raflog_proto_rawDesc = []byte{
@@ -189,26 +297,37 @@ var file_experiment_metastore_raftlogpb_raflog_proto_rawDesc = []byte{
 	0x01, 0x28, 0x0c, 0x52, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x22, 0x2f, 0x0a, 0x0f,
 	0x54, 0x72, 0x75, 0x6e, 0x63, 0x61, 0x74, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12,
 	0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x04, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2a, 0x8d, 0x01,
-	0x0a, 0x0b, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x54, 0x79, 0x70, 0x65, 0x12, 0x18, 0x0a,
-	0x14, 0x43, 0x4f, 0x4d, 0x4d, 0x41, 0x4e, 0x44, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e,
-	0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x1a, 0x0a, 0x16, 0x43, 0x4f, 0x4d, 0x4d, 0x41,
-	0x4e, 0x44, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x41, 0x44, 0x44, 0x5f, 0x42, 0x4c, 0x4f, 0x43,
-	0x4b, 0x10, 0x01, 0x12, 0x2c, 0x0a, 0x28, 0x43, 0x4f, 0x4d, 0x4d, 0x41, 0x4e, 0x44, 0x5f, 0x54,
-	0x59, 0x50, 0x45, 0x5f, 0x50, 0x4f, 0x4c, 0x4c, 0x5f, 0x43, 0x4f, 0x4d, 0x50, 0x41, 0x43, 0x54,
-	0x49, 0x4f, 0x4e, 0x5f, 0x4a, 0x4f, 0x42, 0x53, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x10,
-	0x02, 0x12, 0x1a, 0x0a, 0x15, 0x43, 0x4f, 0x4d, 0x4d, 0x41, 0x4e, 0x44, 0x5f, 0x54, 0x59, 0x50,
-	0x45, 0x5f, 0x54, 0x52, 0x55, 0x4e, 0x43, 0x41, 0x54, 0x45, 0x10, 0xe4, 0x20, 0x42, 0x98, 0x01,
-	0x0a, 0x0c, 0x63, 0x6f, 0x6d, 0x2e, 0x72, 0x61, 0x66, 0x74, 0x5f, 0x6c, 0x6f, 0x67, 0x42, 0x0b,
-	0x52, 0x61, 0x66, 0x6c, 0x6f, 0x67, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x3f, 0x67,
-	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x72, 0x61, 0x66, 0x61, 0x6e,
-	0x61, 0x2f, 0x70, 0x79, 0x72, 0x6f, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x2f, 0x70, 0x6b, 0x67, 0x2f,
-	0x65, 0x78, 0x70, 0x65, 0x72, 0x69, 0x6d, 0x65, 0x6e, 0x74, 0x2f, 0x6d, 0x65, 0x74, 0x61, 0x73,
-	0x74, 0x6f, 0x72, 0x65, 0x2f, 0x72, 0x61, 0x66, 0x74, 0x6c, 0x6f, 0x67, 0x70, 0x62, 0xa2, 0x02,
-	0x03, 0x52, 0x58, 0x58, 0xaa, 0x02, 0x07, 0x52, 0x61, 0x66, 0x74, 0x4c, 0x6f, 0x67, 0xca, 0x02,
-	0x07, 0x52, 0x61, 0x66, 0x74, 0x4c, 0x6f, 0x67, 0xe2, 0x02, 0x13, 0x52, 0x61, 0x66, 0x74, 0x4c,
-	0x6f, 0x67, 0x5c, 0x47, 0x50, 0x42, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0xea, 0x02,
-	0x07, 0x52, 0x61, 0x66, 0x74, 0x4c, 0x6f, 0x67, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x28, 0x04, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x22, 0x2a, 0x0a,
+	0x12, 0x43, 0x6c, 0x65, 0x61, 0x6e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x43, 0x6f, 0x6d, 0x6d,
+	0x61, 0x6e, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x61, 0x74, 0x68, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x05, 0x70, 0x61, 0x74, 0x68, 0x73, 0x22, 0x33, 0x0a, 0x1d, 0x52, 0x65, 0x6c,
+	0x65, 0x61, 0x73, 0x65, 0x51, 0x75, 0x61, 0x72, 0x61, 0x6e, 0x74, 0x69, 0x6e, 0x65, 0x64, 0x4a,
+	0x6f, 0x62, 0x73, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6a, 0x6f,
+	0x62, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x6a, 0x6f, 0x62, 0x73, 0x2a, 0xd7,
+	0x01, 0x0a, 0x0b, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x54, 0x79, 0x70, 0x65, 0x12, 0x18,
+	0x0a, 0x14, 0x43, 0x4f, 0x4d, 0x4d, 0x41, 0x4e, 0x44, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55,
+	0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x1a, 0x0a, 0x16, 0x43, 0x4f, 0x4d, 0x4d,
+	0x41, 0x4e, 0x44, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x41, 0x44, 0x44, 0x5f, 0x42, 0x4c, 0x4f,
+	0x43, 0x4b, 0x10, 0x01, 0x12, 0x2c, 0x0a, 0x28, 0x43, 0x4f, 0x4d, 0x4d, 0x41, 0x4e, 0x44, 0x5f,
+	0x54, 0x59, 0x50, 0x45, 0x5f, 0x50, 0x4f, 0x4c, 0x4c, 0x5f, 0x43, 0x4f, 0x4d, 0x50, 0x41, 0x43,
+	0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x4a, 0x4f, 0x42, 0x53, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53,
+	0x10, 0x02, 0x12, 0x1d, 0x0a, 0x19, 0x43, 0x4f, 0x4d, 0x4d, 0x41, 0x4e, 0x44, 0x5f, 0x54, 0x59,
+	0x50, 0x45, 0x5f, 0x43, 0x4c, 0x45, 0x41, 0x4e, 0x5f, 0x42, 0x4c, 0x4f, 0x43, 0x4b, 0x53, 0x10,
+	0x03, 0x12, 0x29, 0x0a, 0x25, 0x43, 0x4f, 0x4d, 0x4d, 0x41, 0x4e, 0x44, 0x5f, 0x54, 0x59, 0x50,
+	0x45, 0x5f, 0x52, 0x45, 0x4c, 0x45, 0x41, 0x53, 0x45, 0x5f, 0x51, 0x55, 0x41, 0x52, 0x41, 0x4e,
+	0x54, 0x49, 0x4e, 0x45, 0x44, 0x5f, 0x4a, 0x4f, 0x42, 0x53, 0x10, 0x04, 0x12, 0x1a, 0x0a, 0x15,
+	0x43, 0x4f, 0x4d, 0x4d, 0x41, 0x4e, 0x44, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x54, 0x52, 0x55,
+	0x4e, 0x43, 0x41, 0x54, 0x45, 0x10, 0xe4, 0x20, 0x42, 0x98, 0x01, 0x0a, 0x0c, 0x63, 0x6f, 0x6d,
+	0x2e, 0x72, 0x61, 0x66, 0x74, 0x5f, 0x6c, 0x6f, 0x67, 0x42, 0x0b, 0x52, 0x61, 0x66, 0x6c, 0x6f,
+	0x67, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x3f, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
+	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x72, 0x61, 0x66, 0x61, 0x6e, 0x61, 0x2f, 0x70, 0x79, 0x72,
+	0x6f, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x65, 0x78, 0x70, 0x65, 0x72,
+	0x69, 0x6d, 0x65, 0x6e, 0x74, 0x2f, 0x6d, 0x65, 0x74, 0x61, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2f,
+	0x72, 0x61, 0x66, 0x74, 0x6c, 0x6f, 0x67, 0x70, 0x62, 0xa2, 0x02, 0x03, 0x52, 0x58, 0x58, 0xaa,
+	0x02, 0x07, 0x52, 0x61, 0x66, 0x74, 0x4c, 0x6f, 0x67, 0xca, 0x02, 0x07, 0x52, 0x61, 0x66, 0x74,
+	0x4c, 0x6f, 0x67, 0xe2, 0x02, 0x13, 0x52, 0x61, 0x66, 0x74, 0x4c, 0x6f, 0x67, 0x5c, 0x47, 0x50,
+	0x42, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0xea, 0x02, 0x07, 0x52, 0x61, 0x66, 0x74,
+	0x4c, 0x6f, 0x67, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -224,11 +343,13 @@ func file_experiment_metastore_raftlogpb_raflog_proto_rawDescGZIP() []byte {
 }
 
 var file_experiment_metastore_raftlogpb_raflog_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_experiment_metastore_raftlogpb_raflog_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_experiment_metastore_raftlogpb_raflog_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_experiment_metastore_raftlogpb_raflog_proto_goTypes = []any{
-	(CommandType)(0),        // 0: raft_log.CommandType
-	(*RaftLogEntry)(nil),    // 1: raft_log.RaftLogEntry
-	(*TruncateCommand)(nil), // 2: raft_log.TruncateCommand
+	(CommandType)(0),                      // 0: raft_log.CommandType
+	(*RaftLogEntry)(nil),                  // 1: raft_log.RaftLogEntry
+	(*TruncateCommand)(nil),               // 2: raft_log.TruncateCommand
+	(*CleanBlocksCommand)(nil),            // 3: raft_log.CleanBlocksCommand
+	(*ReleaseQuarantinedJobsCommand)(nil), // 4: raft_log.ReleaseQuarantinedJobsCommand
 }
 var file_experiment_metastore_raftlogpb_raflog_proto_depIdxs = []int32{
 	0, // 0: raft_log.RaftLogEntry.type:type_name -> raft_log.CommandType
@@ -269,6 +390,30 @@ func file_experiment_metastore_raftlogpb_raflog_proto_init() {
 				return nil
 			}
 		}
+		file_experiment_metastore_raftlogpb_raflog_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*CleanBlocksCommand); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_experiment_metastore_raftlogpb_raflog_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*ReleaseQuarantinedJobsCommand); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -276,7 +421,7 @@ func file_experiment_metastore_raftlogpb_raflog_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_experiment_metastore_raftlogpb_raflog_proto_rawDesc,
 			NumEnums:      1,
-			NumMessages:   2,
+			NumMessages:   4,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -111,19 +111,29 @@ func (q *QueryBackend) merge(
 ) (*queryv1.InvokeResponse, error) {
 	request.QueryPlan = nil
 	m := newAggregator(request)
+	d := newDiagnostics(request, queryplan.NodeMerge)
 	g, ctx := errgroup.WithContext(ctx)
 	for children.Next() {
 		req := request.CloneVT()
 		req.QueryPlan = children.At().Plan().Proto()
 		g.Go(util.RecoverPanic(func() error {
 			// TODO: Speculative retry.
-			return m.aggregateResponse(q.backendClient.Invoke(ctx, req))
+			resp, err := q.backendClient.Invoke(ctx, req)
+			if err == nil {
+				d.addChild(resp)
+			}
+			return m.aggregateResponse(resp, err)
 		}))
 	}
 	if err := g.Wait(); err != nil {
 		return nil, err
 	}
-	return m.response()
+	resp, err := m.response()
+	if err != nil {
+		return nil, err
+	}
+	d.finish(resp)
+	return resp, nil
 }
 
 func (q *QueryBackend) read(
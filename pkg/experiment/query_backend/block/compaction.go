@@ -12,6 +12,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/go-kit/log"
 	"github.com/grafana/dskit/multierror"
 	"github.com/oklog/ulid"
 	"github.com/parquet-go/parquet-go"
@@ -23,6 +24,7 @@ import (
 	phlaremodel "github.com/grafana/pyroscope/pkg/model"
 	"github.com/grafana/pyroscope/pkg/objstore"
 	"github.com/grafana/pyroscope/pkg/phlaredb/block"
+	"github.com/grafana/pyroscope/pkg/phlaredb/downsample"
 	schemav1 "github.com/grafana/pyroscope/pkg/phlaredb/schemas/v1"
 	"github.com/grafana/pyroscope/pkg/phlaredb/symdb"
 	"github.com/grafana/pyroscope/pkg/phlaredb/tsdb/index"
@@ -54,11 +56,28 @@ func WithCompactionDestination(storage objstore.Bucket) CompactionOption {
 	}
 }
 
+// WithCompactionDownsampling enables downsampling of profiles in
+// the compacted blocks of the given compaction level and above.
+// Such blocks are written in format version 2.
+func WithCompactionDownsampling(minLevel uint32) CompactionOption {
+	return func(p *compactionConfig) {
+		p.downsampling = true
+		p.downsamplingMinLevel = minLevel
+	}
+}
+
 type compactionConfig struct {
 	objectOptions []ObjectOption
 	tempdir       string
 	source        objstore.BucketReader
 	destination   objstore.Bucket
+
+	downsampling         bool
+	downsamplingMinLevel uint32
+}
+
+func (c *compactionConfig) downsample(level uint32) bool {
+	return c.downsampling && level >= c.downsamplingMinLevel
 }
 
 func Compact(
@@ -91,6 +110,9 @@ func Compact(
 
 	compacted := make([]*metastorev1.BlockMeta, 0, len(plan))
 	for _, p := range plan {
+		if c.downsample(p.meta.CompactionLevel) {
+			p.enableDownsampling()
+		}
 		md, compactionErr := p.Compact(ctx, c.destination, c.tempdir)
 		if compactionErr != nil {
 			return nil, compactionErr
@@ -125,6 +147,9 @@ func PlanCompaction(objects Objects) ([]*CompactionPlan, error) {
 				tm = newBlockCompaction(s.TenantId, r.meta.Shard, c)
 				m[s.TenantId] = tm
 			}
+			if !slices.Contains(tm.sources, obj.meta.Id) {
+				tm.sources = append(tm.sources, obj.meta.Id)
+			}
 			sm := tm.addDataset(s)
 			// Bind objects to datasets.
 			sm.append(NewDataset(s, obj))
@@ -146,10 +171,13 @@ func PlanCompaction(objects Objects) ([]*CompactionPlan, error) {
 }
 
 type CompactionPlan struct {
-	tenantID   string
-	datasetMap map[string]*datasetCompaction
-	datasets   []*datasetCompaction
-	meta       *metastorev1.BlockMeta
+	tenantID    string
+	datasetMap  map[string]*datasetCompaction
+	datasets    []*datasetCompaction
+	meta        *metastorev1.BlockMeta
+	downsampled bool
+	// Identifiers of the blocks the datasets are compacted from.
+	sources []string
 }
 
 func newBlockCompaction(tenantID string, shard uint32, compactionLevel uint32) *CompactionPlan {
@@ -171,6 +199,13 @@ func newBlockCompaction(tenantID string, shard uint32, compactionLevel uint32) *
 	}
 }
 
+// enableDownsampling makes the compaction produce downsampled profile
+// tables for all the datasets of the block.
+func (b *CompactionPlan) enableDownsampling() {
+	b.downsampled = true
+	b.meta.FormatVersion = 2
+}
+
 func (b *CompactionPlan) Estimate() {
 	// TODO(kolesnikovae): Implement.
 }
@@ -184,15 +219,19 @@ func (b *CompactionPlan) Compact(ctx context.Context, dst objstore.Bucket, tmpdi
 	for _, s := range b.datasets {
 		s.estimate()
 		// TODO(kolesnikovae): Wait until the required resources are available?
+		s.downsample = b.downsampled
 		if err = s.compact(ctx, w); err != nil {
 			return nil, fmt.Errorf("compacting block: %w", err)
 		}
 		b.meta.Datasets = append(b.meta.Datasets, s.meta)
 	}
+	b.meta.Size = w.Offset()
+	if err = w.WriteMetadata(b.meta, b.sources); err != nil {
+		return nil, fmt.Errorf("writing block metadata: %w", err)
+	}
 	if err = w.Flush(ctx); err != nil {
 		return nil, fmt.Errorf("flushing block writer: %w", err)
 	}
-	b.meta.Size = w.Offset()
 	return b.meta, nil
 }
 
@@ -245,6 +284,8 @@ type datasetCompaction struct {
 	indexRewriter   *indexRewriter
 	symbolsRewriter *symbolsRewriter
 	profilesWriter  *profilesWriter
+	downsampler     *downsample.Downsampler
+	downsample      bool
 
 	estimates compactionEstimates
 	samples   uint64
@@ -367,6 +408,12 @@ func (m *datasetCompaction) open(ctx context.Context, path string) (err error) {
 		return err
 	}
 
+	if m.downsample {
+		if m.downsampler, err = downsample.NewDownsampler(m.path, log.NewNopLogger()); err != nil {
+			return err
+		}
+	}
+
 	m.indexRewriter = newIndexRewriter(m.path)
 	m.symbolsRewriter = newSymbolsRewriter(m.path)
 
@@ -427,7 +474,15 @@ func (m *datasetCompaction) writeRow(r ProfileEntry) (err error) {
 	if err = m.symbolsRewriter.rewriteRow(r); err != nil {
 		return err
 	}
-	return m.profilesWriter.writeRow(r)
+	if err = m.profilesWriter.writeRow(r); err != nil {
+		return err
+	}
+	if m.downsampler != nil {
+		// The row has already been rewritten: it refers
+		// to the series and stack traces of the new block.
+		return m.downsampler.AddRow(r.Row, r.Fingerprint)
+	}
+	return nil
 }
 
 func (m *datasetCompaction) close() (err error) {
@@ -436,12 +491,16 @@ func (m *datasetCompaction) close() (err error) {
 		merr.Add(m.symbolsRewriter.Flush())
 		merr.Add(m.indexRewriter.Flush())
 		merr.Add(m.profilesWriter.Close())
+		if m.downsampler != nil {
+			merr.Add(m.downsampler.Close())
+		}
 		m.samples = m.symbolsRewriter.samples
 		m.series = m.indexRewriter.NumSeries()
 		m.profiles = m.profilesWriter.profiles
 		m.symbolsRewriter = nil
 		m.indexRewriter = nil
 		m.profilesWriter = nil
+		m.downsampler = nil
 		// Note that m.datasets are closed by merge
 		// iterator as they reach the end of the profile
 		// table. We do it here again just in case.
@@ -454,11 +513,17 @@ func (m *datasetCompaction) close() (err error) {
 
 func (m *datasetCompaction) writeTo(w *Writer) (err error) {
 	off := w.Offset()
-	m.meta.TableOfContents, err = w.ReadFromFiles(
+	files := []string{
 		FileNameProfilesParquet,
 		block.IndexFilename,
 		symdb.DefaultFileName,
-	)
+	}
+	if m.downsample {
+		for _, t := range downsample.Tables() {
+			files = append(files, t.FileName)
+		}
+	}
+	m.meta.TableOfContents, err = w.ReadFromFiles(files...)
 	if err != nil {
 		return err
 	}
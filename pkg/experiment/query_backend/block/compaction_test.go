@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/encoding/protojson"
@@ -34,5 +35,85 @@ func Test_CompactBlocks(t *testing.T) {
 
 	require.NoError(t, err)
 	require.Len(t, compactedBlocks, 1)
+
+	// The metadata is stored in the object along with the sources.
+	md, sources, err := ReadMetadata(ctx, dst, ObjectPath(compactedBlocks[0]))
+	require.NoError(t, err)
+	require.Equal(t, compactedBlocks[0].String(), md.String())
+	require.Len(t, sources, len(blockMetas.Blocks))
 	// TODO: Assertions.
 }
+
+func Test_CompactBlocks_Downsampling(t *testing.T) {
+	ctx := context.Background()
+	bucket, _ := testutil.NewFilesystemBucket(t, ctx, "testdata")
+
+	var blockMetas compactorv1.CompletedJob
+	blockMetasData, err := os.ReadFile("testdata/block-metas.json")
+	require.NoError(t, err)
+	err = protojson.Unmarshal(blockMetasData, &blockMetas)
+	require.NoError(t, err)
+
+	dst, tempdir := testutil.NewFilesystemBucket(t, ctx, t.TempDir())
+	compactedBlocks, err := Compact(ctx, blockMetas.Blocks, bucket,
+		WithCompactionDestination(dst),
+		WithCompactionTempDir(tempdir),
+		WithCompactionDownsampling(1),
+	)
+	require.NoError(t, err)
+	require.Len(t, compactedBlocks, 1)
+
+	meta := compactedBlocks[0]
+	require.Equal(t, uint64(2), meta.FormatVersion)
+	require.NotEmpty(t, meta.Datasets)
+
+	obj := NewObject(dst, meta)
+	for _, ds := range meta.Datasets {
+		require.Len(t, ds.TableOfContents, 5)
+		dataset := NewDataset(ds, obj)
+		require.NoError(t, dataset.Open(ctx, SectionProfiles, SectionDownsampledProfiles))
+		downsampled := dataset.DownsampledProfiles()
+		require.Len(t, downsampled, 2)
+		require.Equal(t, 5*time.Minute, downsampled[0].Resolution)
+		require.Equal(t, time.Hour, downsampled[1].Resolution)
+		// Each downsampled profile aggregates at least one profile.
+		profiles := dataset.Profiles().NumRows()
+		require.Greater(t, profiles, int64(0))
+		require.Greater(t, downsampled[0].NumRows(), int64(0))
+		require.LessOrEqual(t, downsampled[0].NumRows(), profiles)
+		require.LessOrEqual(t, downsampled[1].NumRows(), downsampled[0].NumRows())
+		require.NoError(t, dataset.Close())
+	}
+}
+
+func Test_CompactBlocks_DownsamplingMinLevel(t *testing.T) {
+	ctx := context.Background()
+	bucket, _ := testutil.NewFilesystemBucket(t, ctx, "testdata")
+
+	var blockMetas compactorv1.CompletedJob
+	blockMetasData, err := os.ReadFile("testdata/block-metas.json")
+	require.NoError(t, err)
+	err = protojson.Unmarshal(blockMetasData, &blockMetas)
+	require.NoError(t, err)
+
+	dst, tempdir := testutil.NewFilesystemBucket(t, ctx, t.TempDir())
+	compactedBlocks, err := Compact(ctx, blockMetas.Blocks, bucket,
+		WithCompactionDestination(dst),
+		WithCompactionTempDir(tempdir),
+		WithCompactionDownsampling(2),
+	)
+	require.NoError(t, err)
+	require.Len(t, compactedBlocks, 1)
+
+	meta := compactedBlocks[0]
+	require.Equal(t, uint64(1), meta.FormatVersion)
+	obj := NewObject(dst, meta)
+	for _, ds := range meta.Datasets {
+		require.Len(t, ds.TableOfContents, 3)
+		dataset := NewDataset(ds, obj)
+		// The section is ignored for blocks without downsampled data.
+		require.NoError(t, dataset.Open(ctx, SectionProfiles, SectionDownsampledProfiles))
+		require.Empty(t, dataset.DownsampledProfiles())
+		require.NoError(t, dataset.Close())
+	}
+}
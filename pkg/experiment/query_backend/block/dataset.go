@@ -30,6 +30,8 @@ type Dataset struct {
 	symbols  *symdb.Reader
 	profiles *ParquetFile
 
+	downsampled []DownsampledProfiles
+
 	memSize int
 }
 
@@ -125,6 +127,10 @@ func (s *Dataset) closeErr(err error) error {
 	if s.profiles != nil {
 		merr.Add(s.profiles.Close())
 	}
+	for _, t := range s.downsampled {
+		merr.Add(t.Close())
+	}
+	s.downsampled = nil
 	if s.obj != nil {
 		merr.Add(s.obj.CloseWithError(err))
 	}
@@ -135,6 +141,11 @@ func (s *Dataset) Meta() *metastorev1.Dataset { return s.meta }
 
 func (s *Dataset) Profiles() *ParquetFile { return s.profiles }
 
+// DownsampledProfiles returns the downsampled profile tables, ordered
+// by resolution in ascending order. If the dataset has no downsampled
+// profiles, or the section has not been opened, the result is empty.
+func (s *Dataset) DownsampledProfiles() []DownsampledProfiles { return s.downsampled }
+
 func (s *Dataset) ProfileRowReader() parquet.RowReader { return s.profiles.RowReader() }
 
 func (s *Dataset) Symbols() symdb.SymbolsReader { return s.symbols }
@@ -147,6 +158,8 @@ func (s *Dataset) offset() uint64 { return s.meta.TableOfContents[0] }
 func (s *Dataset) sectionIndex(sc Section) int {
 	var n []int
 	switch s.obj.meta.FormatVersion {
+	case 2:
+		n = sectionIndices[2]
 	default:
 		n = sectionIndices[1]
 	}
@@ -159,6 +172,8 @@ func (s *Dataset) sectionIndex(sc Section) int {
 func (s *Dataset) sectionName(sc Section) string {
 	var n []string
 	switch s.obj.meta.FormatVersion {
+	case 2:
+		n = sectionNames[2]
 	default:
 		n = sectionNames[1]
 	}
@@ -169,11 +184,36 @@ func (s *Dataset) sectionName(sc Section) string {
 }
 
 func (s *Dataset) sectionOffset(sc Section) int64 {
-	return int64(s.meta.TableOfContents[s.sectionIndex(sc)])
+	return s.entryOffset(s.sectionIndex(sc))
 }
 
 func (s *Dataset) sectionSize(sc Section) int64 {
-	idx := s.sectionIndex(sc)
+	return s.entrySize(s.sectionIndex(sc))
+}
+
+// SectionSize returns the size of the dataset section in bytes,
+// or zero, if the section is not present in the dataset.
+func (s *Dataset) SectionSize(sc Section) int64 {
+	if sc == SectionDownsampledProfiles {
+		if s.obj.meta.FormatVersion < 2 || len(s.meta.TableOfContents) <= sectionIndices[2][sc] {
+			return 0
+		}
+		// The section spans all the downsampled tables.
+		return int64(s.offset() + s.meta.Size - s.meta.TableOfContents[sectionIndices[2][sc]])
+	}
+	if idx := s.sectionIndex(sc); idx < len(s.meta.TableOfContents) {
+		return s.entrySize(idx)
+	}
+	return 0
+}
+
+// entryOffset returns the offset of the table of contents entry.
+func (s *Dataset) entryOffset(idx int) int64 {
+	return int64(s.meta.TableOfContents[idx])
+}
+
+// entrySize returns the size of the table of contents entry.
+func (s *Dataset) entrySize(idx int) int64 {
 	off := s.meta.TableOfContents[idx]
 	var next uint64
 	if idx == len(s.meta.TableOfContents)-1 {
@@ -0,0 +1,132 @@
+package block
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	thanosobjstore "github.com/thanos-io/objstore"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/pkg/objstore"
+	"github.com/grafana/pyroscope/pkg/util/bufferpool"
+)
+
+// The block metadata is stored at the end of the object, after the data
+// described by the metadata, so that the metastore index can be rebuilt
+// from the object storage alone. The metadata does not change the layout
+// of the data: BlockMeta.Size does not include the footer.
+//
+//	[data][metadata][4:metadata_size][4:magic]
+//
+// The metadata includes the BlockMeta and the identifiers of the blocks
+// the object has been compacted from:
+//
+//	[uvarint:meta_size][meta][uvarint:sources]([uvarint:id_size][id])...
+const (
+	metadataFooterSize   = 8
+	metadataMagic        = 0x50594d44 // PYMD
+	metadataReadTailSize = 32 << 10
+)
+
+var ErrNoMetadata = errors.New("block object has no metadata")
+
+// WriteMetadata writes the block metadata footer to w.
+func WriteMetadata(w io.Writer, md *metastorev1.BlockMeta, sources []string) error {
+	raw, err := md.MarshalVT()
+	if err != nil {
+		return err
+	}
+	buf := make([]byte, 0, len(raw)+metadataFooterSize+binary.MaxVarintLen64*(2+len(sources)))
+	buf = binary.AppendUvarint(buf, uint64(len(raw)))
+	buf = append(buf, raw...)
+	buf = binary.AppendUvarint(buf, uint64(len(sources)))
+	for _, s := range sources {
+		buf = binary.AppendUvarint(buf, uint64(len(s)))
+		buf = append(buf, s...)
+	}
+	size := len(buf)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(size))
+	buf = binary.BigEndian.AppendUint32(buf, metadataMagic)
+	_, err = w.Write(buf)
+	return err
+}
+
+// ReadMetadata reads the metadata footer of the object at the given path.
+// ErrNoMetadata is returned, if the object does not have the footer.
+func ReadMetadata(ctx context.Context, storage thanosobjstore.BucketReader, path string) (*metastorev1.BlockMeta, []string, error) {
+	attrs, err := storage.Attributes(ctx, path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if attrs.Size < metadataFooterSize {
+		return nil, nil, ErrNoMetadata
+	}
+	tail := min(attrs.Size, metadataReadTailSize)
+	buf := bufferpool.GetBuffer(int(tail))
+	defer bufferpool.Put(buf)
+	if err = objstore.ReadRange(ctx, buf, path, storage, attrs.Size-tail, tail); err != nil {
+		return nil, nil, err
+	}
+	footer := buf.B[len(buf.B)-metadataFooterSize:]
+	if binary.BigEndian.Uint32(footer[4:]) != metadataMagic {
+		return nil, nil, ErrNoMetadata
+	}
+	size := int64(binary.BigEndian.Uint32(footer[:4]))
+	if size+metadataFooterSize > attrs.Size {
+		return nil, nil, fmt.Errorf("invalid metadata size %d, object size %d", size, attrs.Size)
+	}
+	if size+metadataFooterSize > tail {
+		buf.B = buf.B[:0]
+		if err = objstore.ReadRange(ctx, buf, path, storage, attrs.Size-size-metadataFooterSize, size+metadataFooterSize); err != nil {
+			return nil, nil, err
+		}
+	}
+	raw := buf.B[len(buf.B)-metadataFooterSize-int(size) : len(buf.B)-metadataFooterSize]
+	return decodeMetadata(raw)
+}
+
+func decodeMetadata(raw []byte) (*metastorev1.BlockMeta, []string, error) {
+	r := bytes.NewReader(raw)
+	readBytes := func() ([]byte, error) {
+		n, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, err
+		}
+		if n > uint64(r.Len()) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		b := make([]byte, n)
+		_, err = io.ReadFull(r, b)
+		return b, err
+	}
+	b, err := readBytes()
+	if err != nil {
+		return nil, nil, fmt.Errorf("malformed metadata: %w", err)
+	}
+	var md metastorev1.BlockMeta
+	if err = md.UnmarshalVT(b); err != nil {
+		return nil, nil, fmt.Errorf("malformed metadata: %w", err)
+	}
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("malformed metadata: %w", err)
+	}
+	if n > uint64(r.Len()) {
+		return nil, nil, fmt.Errorf("malformed metadata: %w", io.ErrUnexpectedEOF)
+	}
+	var sources []string
+	if n > 0 {
+		sources = make([]string, n)
+	}
+	for i := range sources {
+		if b, err = readBytes(); err != nil {
+			return nil, nil, fmt.Errorf("malformed metadata: %w", err)
+		}
+		sources[i] = string(b)
+	}
+	return &md, sources, nil
+}
@@ -0,0 +1,76 @@
+package block
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+)
+
+func Test_Metadata(t *testing.T) {
+	ctx := context.Background()
+	bucket := objstore.NewInMemBucket()
+	data := []byte("block data")
+	md := &metastorev1.BlockMeta{
+		Id:              "01J2VJQPYDC160REPAD2VN88XN",
+		TenantId:        "tenant",
+		Shard:           1,
+		CompactionLevel: 1,
+		Size:            uint64(len(data)),
+		Datasets: []*metastorev1.Dataset{{
+			TenantId:        "tenant",
+			Name:            "service",
+			TableOfContents: []uint64{0, 4, 8},
+			Size:            uint64(len(data)),
+		}},
+	}
+	sources := []string{"01J2VJQRGBK8YFWVV8K1MPRRWM", "01J2VJQRTMSCY4VDYBP5N4N5JK"}
+
+	upload := func(t *testing.T, path string, md *metastorev1.BlockMeta, sources []string) {
+		var buf bytes.Buffer
+		buf.Write(data)
+		require.NoError(t, WriteMetadata(&buf, md, sources))
+		require.NoError(t, bucket.Upload(ctx, path, &buf))
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		upload(t, "a", md, sources)
+		m, s, err := ReadMetadata(ctx, bucket, "a")
+		require.NoError(t, err)
+		assert.Equal(t, md.String(), m.String())
+		assert.Equal(t, sources, s)
+	})
+
+	t.Run("no sources", func(t *testing.T) {
+		upload(t, "b", md, nil)
+		m, s, err := ReadMetadata(ctx, bucket, "b")
+		require.NoError(t, err)
+		assert.Equal(t, md.String(), m.String())
+		assert.Empty(t, s)
+	})
+
+	t.Run("metadata larger than the read tail", func(t *testing.T) {
+		large := md.CloneVT()
+		large.Datasets[0].ProfileTypes = []string{strings.Repeat("x", 2*metadataReadTailSize)}
+		upload(t, "c", large, sources)
+		m, s, err := ReadMetadata(ctx, bucket, "c")
+		require.NoError(t, err)
+		assert.Equal(t, large.String(), m.String())
+		assert.Equal(t, sources, s)
+	})
+
+	t.Run("no metadata", func(t *testing.T) {
+		require.NoError(t, bucket.Upload(ctx, "d", bytes.NewReader(data)))
+		_, _, err := ReadMetadata(ctx, bucket, "d")
+		assert.ErrorIs(t, err, ErrNoMetadata)
+		require.NoError(t, bucket.Upload(ctx, "e", bytes.NewReader(nil)))
+		_, _, err = ReadMetadata(ctx, bucket, "e")
+		assert.ErrorIs(t, err, ErrNoMetadata)
+	})
+}
@@ -33,6 +33,10 @@ const (
 	SectionProfiles
 	SectionTSDB
 	SectionSymbols
+	// SectionDownsampledProfiles includes all the downsampled profile
+	// tables of the dataset. The section is only present in objects of
+	// format version 2 and is ignored otherwise.
+	SectionDownsampledProfiles
 )
 
 var allSections = []Section{
@@ -43,8 +47,16 @@ var allSections = []Section{
 
 var (
 	// Version-specific.
-	sectionNames   = [...][]string{1: {"invalid", "profiles", "tsdb", "symbols"}}
-	sectionIndices = [...][]int{1: {-1, 0, 1, 2}}
+	sectionNames = [...][]string{
+		1: {"invalid", "profiles", "tsdb", "symbols"},
+		2: {"invalid", "profiles", "tsdb", "symbols", "downsampled profiles"},
+	}
+	sectionIndices = [...][]int{
+		1: {-1, 0, 1, 2},
+		// Downsampled tables follow the symbols,
+		// in the order defined by downsample.Tables.
+		2: {-1, 0, 1, 2, 3},
+	}
 )
 
 func (sc Section) open(ctx context.Context, s *Dataset) (err error) {
@@ -55,6 +67,8 @@ func (sc Section) open(ctx context.Context, s *Dataset) (err error) {
 		return openSymbols(ctx, s)
 	case SectionProfiles:
 		return openProfileTable(ctx, s)
+	case SectionDownsampledProfiles:
+		return openDownsampledProfileTables(ctx, s)
 	default:
 		panic(fmt.Sprintf("bug: unknown section: %d", sc))
 	}
@@ -1,6 +1,7 @@
 package block
 
 import (
+	"cmp"
 	"context"
 	"encoding/binary"
 	"fmt"
@@ -8,6 +9,8 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"slices"
+	"time"
 
 	"github.com/parquet-go/parquet-go"
 	"github.com/pkg/errors"
@@ -18,6 +21,7 @@ import (
 	"github.com/grafana/pyroscope/pkg/objstore"
 	phlareparquet "github.com/grafana/pyroscope/pkg/parquet"
 	"github.com/grafana/pyroscope/pkg/phlaredb"
+	"github.com/grafana/pyroscope/pkg/phlaredb/downsample"
 	"github.com/grafana/pyroscope/pkg/phlaredb/query"
 	schemav1 "github.com/grafana/pyroscope/pkg/phlaredb/schemas/v1"
 	"github.com/grafana/pyroscope/pkg/phlaredb/tsdb/index"
@@ -29,26 +33,65 @@ import (
 func openProfileTable(_ context.Context, s *Dataset) (err error) {
 	offset := s.sectionOffset(SectionProfiles)
 	size := s.sectionSize(SectionProfiles)
+	if s.profiles, err = openDatasetParquetFile(s, offset, size); err != nil {
+		return fmt.Errorf("opening profile parquet table: %w", err)
+	}
+	return nil
+}
+
+// DownsampledProfiles is a profile table, where profiles of a series
+// are aggregated over the time intervals of the given resolution.
+// Each row is timestamped with the beginning of the interval.
+type DownsampledProfiles struct {
+	*ParquetFile
+	Resolution  time.Duration
+	Aggregation string
+}
+
+func openDownsampledProfileTables(_ context.Context, s *Dataset) (err error) {
+	if s.obj.meta.FormatVersion < 2 {
+		// Objects of older formats don't have downsampled data.
+		return nil
+	}
+	tables := downsample.Tables()
+	idx := s.sectionIndex(SectionDownsampledProfiles)
+	if len(s.meta.TableOfContents) < idx+len(tables) {
+		return fmt.Errorf("invalid table of contents: expected %d downsampled tables", len(tables))
+	}
+	s.downsampled = make([]DownsampledProfiles, 0, len(tables))
+	for i, t := range tables {
+		f, openErr := openDatasetParquetFile(s, s.entryOffset(idx+i), s.entrySize(idx+i))
+		if openErr != nil {
+			return fmt.Errorf("opening downsampled profile parquet table %s: %w", t.FileName, openErr)
+		}
+		s.downsampled = append(s.downsampled, DownsampledProfiles{
+			ParquetFile: f,
+			Resolution:  t.Resolution,
+			Aggregation: t.Aggregation,
+		})
+	}
+	slices.SortFunc(s.downsampled, func(a, b DownsampledProfiles) int {
+		return cmp.Compare(a.Resolution, b.Resolution)
+	})
+	return nil
+}
+
+func openDatasetParquetFile(s *Dataset, offset, size int64) (*ParquetFile, error) {
 	if buf := s.inMemoryBuffer(); buf != nil {
 		offset -= int64(s.offset())
-		s.profiles, err = openParquetFile(
+		return openParquetFile(
 			s.inMemoryBucket(buf), s.obj.path, offset, size,
 			0, // Do not prefetch the footer.
 			parquet.SkipBloomFilters(true),
 			parquet.FileReadMode(parquet.ReadModeSync),
 			parquet.ReadBufferSize(4<<10))
-	} else {
-		s.profiles, err = openParquetFile(
-			s.obj.storage, s.obj.path, offset, size,
-			estimateFooterSize(size),
-			parquet.SkipBloomFilters(true),
-			parquet.FileReadMode(parquet.ReadModeAsync),
-			parquet.ReadBufferSize(estimateReadBufferSize(size)))
 	}
-	if err != nil {
-		return fmt.Errorf("opening profile parquet table: %w", err)
-	}
-	return nil
+	return openParquetFile(
+		objstore.NewBucketReaderWithObjectKind(s.obj.storage, objstore.ObjectKindProfiles), s.obj.path, offset, size,
+		estimateFooterSize(size),
+		parquet.SkipBloomFilters(true),
+		parquet.FileReadMode(parquet.ReadModeAsync),
+		parquet.ReadBufferSize(estimateReadBufferSize(size)))
 }
 
 type ParquetFile struct {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/grafana/pyroscope/pkg/objstore"
 	"github.com/grafana/pyroscope/pkg/phlaredb/symdb"
 )
 
@@ -14,7 +15,7 @@ func openSymbols(ctx context.Context, s *Dataset) (err error) {
 		offset -= int64(s.offset())
 		s.symbols, err = symdb.OpenObject(ctx, s.inMemoryBucket(buf), s.obj.path, offset, size)
 	} else {
-		s.symbols, err = symdb.OpenObject(ctx, s.obj.storage, s.obj.path, offset, size,
+		s.symbols, err = symdb.OpenObject(ctx, objstore.NewBucketReaderWithObjectKind(s.obj.storage, objstore.ObjectKindSymbols), s.obj.path, offset, size,
 			symdb.WithPrefetchSize(symbolsPrefetchSize))
 	}
 	if err != nil {
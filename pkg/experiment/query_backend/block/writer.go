@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"strconv"
 
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
 	"github.com/grafana/pyroscope/pkg/objstore"
 	"github.com/grafana/pyroscope/pkg/util/bufferpool"
 )
@@ -63,10 +64,8 @@ func (b *Writer) ReadFromFiles(files ...string) (toc []uint64, err error) {
 
 // ReadFromFile located in the directory Dir.
 func (b *Writer) ReadFromFile(file string) (err error) {
-	if b.w == nil {
-		if b.w, err = os.Create(b.local); err != nil {
-			return err
-		}
+	if err = b.open(); err != nil {
+		return err
 	}
 	f, err := os.Open(filepath.Join(b.cur, file))
 	if err != nil {
@@ -81,6 +80,22 @@ func (b *Writer) ReadFromFile(file string) (err error) {
 	return err
 }
 
+// WriteMetadata appends the metadata footer to the object.
+// The footer is not included into the offset.
+func (b *Writer) WriteMetadata(md *metastorev1.BlockMeta, sources []string) error {
+	if err := b.open(); err != nil {
+		return err
+	}
+	return WriteMetadata(b.w, md, sources)
+}
+
+func (b *Writer) open() (err error) {
+	if b.w == nil {
+		b.w, err = os.Create(b.local)
+	}
+	return err
+}
+
 func (b *Writer) Offset() uint64 { return b.off }
 
 func (b *Writer) Flush(ctx context.Context) error {
@@ -15,6 +15,7 @@ import (
 
 	queryv1 "github.com/grafana/pyroscope/api/gen/proto/go/query/v1"
 	"github.com/grafana/pyroscope/pkg/experiment/query_backend/block"
+	queryplan "github.com/grafana/pyroscope/pkg/experiment/query_backend/query_plan"
 	"github.com/grafana/pyroscope/pkg/objstore"
 	"github.com/grafana/pyroscope/pkg/util"
 )
@@ -58,7 +59,7 @@ type BlockReader struct {
 func NewBlockReader(logger log.Logger, storage objstore.Bucket) *BlockReader {
 	return &BlockReader{
 		log:     logger,
-		storage: storage,
+		storage: objstore.NewReadStatsBucket(storage),
 	}
 }
 
@@ -72,10 +73,18 @@ func (b *BlockReader) Invoke(
 	if err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, "request validation failed: %v", err)
 	}
+	d := newDiagnostics(req, queryplan.NodeRead)
+	var stats *objstore.ReadStats
+	if d != nil {
+		stats = new(objstore.ReadStats)
+		ctx = objstore.ContextWithReadStats(ctx, stats)
+	}
 	g, ctx := errgroup.WithContext(ctx)
 	m := newAggregator(req)
+	var datasets int
 	for _, md := range req.QueryPlan.Blocks {
 		obj := block.NewObject(b.storage, md)
+		datasets += len(md.Datasets)
 		for _, meta := range md.Datasets {
 			c := newQueryContext(ctx, b.log, meta, vr, obj)
 			for _, query := range req.Query {
@@ -93,7 +102,15 @@ func (b *BlockReader) Invoke(
 	if err = g.Wait(); err != nil {
 		return nil, err
 	}
-	return m.response()
+	resp, err := m.response()
+	if err != nil {
+		return nil, err
+	}
+	if d != nil {
+		d.addReadStats(len(req.QueryPlan.Blocks), datasets, stats)
+		d.finish(resp)
+	}
+	return resp, nil
 }
 
 type request struct {
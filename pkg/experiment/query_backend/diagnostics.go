@@ -0,0 +1,86 @@
+package query_backend
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	queryv1 "github.com/grafana/pyroscope/api/gen/proto/go/query/v1"
+	queryplan "github.com/grafana/pyroscope/pkg/experiment/query_backend/query_plan"
+	"github.com/grafana/pyroscope/pkg/objstore"
+)
+
+var executor = sync.OnceValue(func() string {
+	hostname, _ := os.Hostname()
+	return hostname
+})
+
+// diagnostics collects execution statistics of a query plan node.
+// A nil diagnostics is valid and collects nothing: this is the case
+// when the request does not ask for the diagnostics.
+type diagnostics struct {
+	mu    sync.Mutex
+	start time.Time
+	node  *queryv1.ExecutionNode
+}
+
+func newDiagnostics(req *queryv1.InvokeRequest, typ queryplan.NodeType) *diagnostics {
+	if !req.GetOptions().GetCollectDiagnostics() {
+		return nil
+	}
+	return &diagnostics{
+		start: time.Now(),
+		node: &queryv1.ExecutionNode{
+			Type:     typ.String(),
+			Executor: executor(),
+			Stats:    new(queryv1.ExecutionStats),
+		},
+	}
+}
+
+// addChild adds the execution statistics of the child node
+// to the merge node.
+func (d *diagnostics) addChild(resp *queryv1.InvokeResponse) {
+	if d == nil {
+		return
+	}
+	c := resp.GetDiagnostics().GetExecution()
+	if c == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.node.Children = append(d.node.Children, c)
+	s := c.GetStats()
+	d.node.Stats.BlocksRead += s.GetBlocksRead()
+	d.node.Stats.DatasetsRead += s.GetDatasetsRead()
+	d.node.Stats.BytesRead += s.GetBytesRead()
+	d.node.Stats.CacheHits += s.GetCacheHits()
+	d.node.Stats.CacheMisses += s.GetCacheMisses()
+}
+
+// addReadStats adds the object storage read statistics
+// to the read node.
+func (d *diagnostics) addReadStats(blocks, datasets int, stats *objstore.ReadStats) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.node.Stats.BlocksRead += uint64(blocks)
+	d.node.Stats.DatasetsRead += uint64(datasets)
+	d.node.Stats.BytesRead += stats.BytesRead.Load()
+	d.node.Stats.CacheHits += stats.CacheHits.Load()
+	d.node.Stats.CacheMisses += stats.CacheMisses.Load()
+}
+
+// finish sets the node diagnostics in the response.
+func (d *diagnostics) finish(resp *queryv1.InvokeResponse) {
+	if d == nil || resp == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.node.DurationNs = time.Since(d.start).Nanoseconds()
+	resp.Diagnostics = &queryv1.Diagnostics{Execution: d.node}
+}
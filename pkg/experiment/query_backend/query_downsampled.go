@@ -0,0 +1,127 @@
+package query_backend
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/grafana/pyroscope/pkg/experiment/query_backend/block"
+)
+
+// profileTableRange is a part of the query time range to be
+// served by the profile table of the given resolution.
+type profileTableRange struct {
+	table      *block.ParquetFile
+	resolution time.Duration // Zero, if the table is not downsampled.
+	startTime  int64         // Unix nano, inclusive.
+	endTime    int64         // Unix nano, inclusive.
+}
+
+// profileTableRanges splits the query time range into sub-ranges, each
+// served by the coarsest downsampled profile table compatible with the
+// query. The parts of the time range not aligned with any compatible
+// resolution are served by the original profile table.
+//
+// Note that SectionDownsampledProfiles must be opened for the dataset,
+// otherwise the original profile table is used for the whole range.
+func profileTableRanges(q *queryContext, compatible func(time.Duration) bool) []profileTableRange {
+	return splitProfileTables(q.ds.Profiles(), q.ds.DownsampledProfiles(), q.req.startTime, q.req.endTime, compatible)
+}
+
+func splitProfileTables(
+	profiles *block.ParquetFile,
+	downsampled []block.DownsampledProfiles,
+	startTime, endTime int64,
+	compatible func(time.Duration) bool,
+) []profileTableRange {
+	tables := make(map[time.Duration]*block.ParquetFile)
+	var resolutions []time.Duration
+	for _, t := range downsampled {
+		// Only additive aggregation is supported: a downsampled
+		// profile is the sum of the profiles it replaces.
+		if t.Aggregation == "sum" && compatible(t.Resolution) {
+			tables[t.Resolution] = t.ParquetFile
+			resolutions = append(resolutions, t.Resolution)
+		}
+	}
+	if len(resolutions) == 0 {
+		return []profileTableRange{{
+			table:     profiles,
+			startTime: startTime,
+			endTime:   endTime,
+		}}
+	}
+	var ranges []profileTableRange
+	splitTimeRange(startTime, endTime, resolutions, func(start, end int64, resolution time.Duration) {
+		r := profileTableRange{
+			table:     profiles,
+			startTime: start,
+			endTime:   end,
+		}
+		if t, ok := tables[resolution]; ok {
+			r.table = t
+			r.resolution = resolution
+		}
+		ranges = append(ranges, r)
+	})
+	return ranges
+}
+
+// splitTimeRange splits the time range into the minimal number of
+// sub-ranges aligned with the resolutions. Unlike the time ranges of
+// util.SplitTimeRangeByResolution, which have a millisecond step, the
+// time ranges are in nanoseconds: the profile timestamps are matched
+// with nanosecond precision. Both ends of the ranges are inclusive;
+// the resolution of the parts not aligned with any is zero.
+func splitTimeRange(start, end int64, resolutions []time.Duration, fn func(start, end int64, resolution time.Duration)) {
+	if start > end {
+		return
+	}
+	resolutions = slices.Clone(resolutions)
+	slices.SortFunc(resolutions, func(a, b time.Duration) int { return cmp.Compare(b, a) })
+	finest := resolutions[len(resolutions)-1].Nanoseconds()
+	// Makes the calculation of the alignment simpler.
+	end++
+	var (
+		c = start // Current range start position.
+		r time.Duration
+	)
+	for c < end {
+		var res time.Duration
+		d := int64(-1)
+		// Find the coarsest resolution aligned with the current position.
+		for _, x := range resolutions {
+			if n := x.Nanoseconds(); c%n == 0 && c+n <= end {
+				res, d = x, n
+				break
+			}
+		}
+		if d < 0 {
+			// No suitable resolution found: advance to
+			// the next boundary of the finest resolution.
+			d = finest - c%finest
+		}
+		d = min(d, end-c)
+		// If the resolution has changed, emit a new range.
+		if r != res && c > start {
+			fn(start, c-1, r)
+			start = c
+		}
+		c += d
+		r = res
+	}
+	fn(start, c-1, r)
+}
+
+// anyResolution is used when the time distribution
+// of profiles within the query time range is irrelevant.
+func anyResolution(time.Duration) bool { return true }
+
+// stepAlignedResolution reports whether the downsampled profiles can be
+// used to build time series with the given step, starting at startTime
+// (Unix nano): each downsampled interval must fall entirely into a step.
+func stepAlignedResolution(startTime int64, step time.Duration) func(time.Duration) bool {
+	return func(r time.Duration) bool {
+		return step > 0 && step%r == 0 && startTime%r.Nanoseconds() == 0
+	}
+}
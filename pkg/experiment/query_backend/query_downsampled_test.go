@@ -0,0 +1,84 @@
+package query_backend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/pyroscope/pkg/experiment/query_backend/block"
+)
+
+func Test_splitProfileTables(t *testing.T) {
+	var (
+		profiles = new(block.ParquetFile)
+		res15s   = new(block.ParquetFile)
+		res1h    = new(block.ParquetFile)
+		base     = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).UnixNano()
+	)
+	downsampled := []block.DownsampledProfiles{
+		{ParquetFile: res15s, Resolution: 15 * time.Second, Aggregation: "sum"},
+		{ParquetFile: new(block.ParquetFile), Resolution: 5 * time.Minute, Aggregation: "avg"},
+		{ParquetFile: res1h, Resolution: time.Hour, Aggregation: "sum"},
+	}
+	at := func(d time.Duration) int64 { return base + d.Nanoseconds() }
+
+	startTime, endTime := at(10*time.Second), at(2*time.Hour+20*time.Second)
+	ranges := splitProfileTables(profiles, downsampled, startTime, endTime, anyResolution)
+	expected := []profileTableRange{
+		{table: profiles, startTime: at(10 * time.Second), endTime: at(15*time.Second) - 1},
+		{table: res15s, resolution: 15 * time.Second, startTime: at(15 * time.Second), endTime: at(time.Hour) - 1},
+		{table: res1h, resolution: time.Hour, startTime: at(time.Hour), endTime: at(2*time.Hour) - 1},
+		{table: res15s, resolution: 15 * time.Second, startTime: at(2 * time.Hour), endTime: at(2*time.Hour+15*time.Second) - 1},
+		{table: profiles, startTime: at(2*time.Hour + 15*time.Second), endTime: endTime},
+	}
+	require.Equal(t, expected, ranges)
+	// The ranges cover the whole time range, without gaps: profiles
+	// timestamped just before a boundary are not skipped.
+	for i := 1; i < len(ranges); i++ {
+		assert.Equal(t, ranges[i-1].endTime+1, ranges[i].startTime)
+	}
+
+	// A downsampled table is used only if the interval
+	// is entirely within the query time range.
+	ranges = splitProfileTables(profiles, downsampled, at(0), at(15*time.Second)-2, anyResolution)
+	assert.Equal(t, []profileTableRange{
+		{table: profiles, startTime: at(0), endTime: at(15*time.Second) - 2},
+	}, ranges)
+
+	ranges = splitProfileTables(profiles, downsampled, startTime, endTime, func(time.Duration) bool { return false })
+	assert.Equal(t, []profileTableRange{
+		{table: profiles, startTime: startTime, endTime: endTime},
+	}, ranges)
+}
+
+func Test_stepAlignedResolution(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).UnixNano()
+	compatible := stepAlignedResolution(start, time.Minute)
+	assert.True(t, compatible(15*time.Second))
+	assert.True(t, compatible(time.Minute))
+	assert.False(t, compatible(time.Hour))
+	assert.False(t, compatible(45*time.Second))
+
+	compatible = stepAlignedResolution(start+(10*time.Second).Nanoseconds(), time.Minute)
+	assert.False(t, compatible(15*time.Second))
+
+	compatible = stepAlignedResolution(start, 0)
+	assert.False(t, compatible(15*time.Second))
+}
+
+func Test_downsampledTimestampOffset(t *testing.T) {
+	assert.Equal(t, int64(0), downsampledTimestampOffset(0))
+
+	const resolution = 15 * time.Second
+	offset := downsampledTimestampOffset(resolution)
+	assert.Equal(t, int64(14999), offset)
+	// The point of a downsampled profile falls into the last millisecond
+	// of the interval it covers, the same step as the profiles it replaces.
+	step := time.Minute.Milliseconds()
+	start := time.Date(2024, 1, 1, 0, 0, 45, 0, time.UTC).UnixMilli()
+	last := start + resolution.Milliseconds() - 1
+	assert.Equal(t, last, start+offset)
+	assert.Equal(t, start/step, (start+offset)/step)
+}
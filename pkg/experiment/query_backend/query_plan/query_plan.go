@@ -87,6 +87,98 @@ func Build(
 	// fetched by workers.
 	s := int(math.Max(float64(maxReads), float64(maxMerges)))
 	ranges := uniformSplit(make([][2]uint32, s), len(blocks), maxReads)
+	return build(blocks, ranges, maxMerges)
+}
+
+// Options defines the limits of the cost-based query plan.
+type Options struct {
+	// MaxReadBytes is the maximum total size of the datasets
+	// processed by a single read node.
+	MaxReadBytes uint64
+	// MaxReadDatasets is the maximum number of datasets
+	// processed by a single read node.
+	MaxReadDatasets int
+	// MaxReadBlocks is the maximum number of blocks
+	// processed by a single read node.
+	MaxReadBlocks int
+	// MaxMerges is the maximum number of children of
+	// a merge node.
+	MaxMerges int64
+}
+
+var DefaultOptions = Options{
+	MaxReadBytes:    64 << 20,
+	MaxReadDatasets: 64,
+	MaxReadBlocks:   32,
+	MaxMerges:       20,
+}
+
+// BuildWithOptions creates a query plan from the list of block metadata,
+// taking into account the size of the blocks. Unlike Build, it sizes read
+// nodes by the total size and the number of the datasets they process.
+//
+// Blocks are ordered by the compaction level and shard, so that blocks
+// of the same shard are read by the same node, which reduces the number
+// of unique series the node has to handle. A read node never spans blocks
+// of different compaction levels: the blocks of lower levels are usually
+// much smaller, and more numerous.
+//
+// The input slice is not modified.
+func BuildWithOptions(blocks []*metastorev1.BlockMeta, opts Options) *QueryPlan {
+	if len(blocks) == 0 {
+		return new(QueryPlan)
+	}
+	blocks = slices.Clone(blocks)
+	slices.SortFunc(blocks, func(a, b *metastorev1.BlockMeta) int {
+		if a.CompactionLevel != b.CompactionLevel {
+			return int(a.CompactionLevel) - int(b.CompactionLevel)
+		}
+		if a.Shard != b.Shard {
+			return int(a.Shard) - int(b.Shard)
+		}
+		return strings.Compare(a.Id, b.Id)
+	})
+	var (
+		ranges   [][2]uint32
+		off      int
+		size     uint64
+		datasets int
+	)
+	for i, b := range blocks {
+		bs := blockSize(b)
+		if n := i - off; n > 0 {
+			if blocks[off].CompactionLevel != b.CompactionLevel ||
+				(opts.MaxReadBlocks > 0 && n >= opts.MaxReadBlocks) ||
+				(opts.MaxReadDatasets > 0 && datasets+len(b.Datasets) > opts.MaxReadDatasets) ||
+				(opts.MaxReadBytes > 0 && size+bs > opts.MaxReadBytes) {
+				ranges = append(ranges, [2]uint32{uint32(off), uint32(n)})
+				off, size, datasets = i, 0, 0
+			}
+		}
+		size += bs
+		datasets += len(b.Datasets)
+	}
+	ranges = append(ranges, [2]uint32{uint32(off), uint32(len(blocks) - off)})
+	return build(blocks, ranges, max(opts.MaxMerges, 2))
+}
+
+// blockSize returns the total size of the block datasets.
+func blockSize(b *metastorev1.BlockMeta) uint64 {
+	var size uint64
+	for _, ds := range b.Datasets {
+		size += ds.Size
+	}
+	return size
+}
+
+// build creates a query plan with read nodes referring
+// to the block ranges specified, and merge nodes combining
+// up to maxMerges nodes each.
+func build(
+	blocks []*metastorev1.BlockMeta,
+	ranges [][2]uint32,
+	maxMerges int64,
+) *QueryPlan {
 	nodes := make([]node, len(ranges))
 	for i, b := range ranges {
 		nodes[i] = node{
@@ -240,6 +332,28 @@ func (p *QueryPlan) Proto() *queryv1.QueryPlan {
 	}
 }
 
+// Stats returns the statistics of the query plan.
+func (p *QueryPlan) Stats() *queryv1.QueryPlanStats {
+	s := queryv1.QueryPlanStats{Blocks: uint64(len(p.blocks))}
+	for _, b := range p.blocks {
+		s.Datasets += uint64(len(b.Datasets))
+		s.DatasetBytes += blockSize(b)
+	}
+	for _, n := range p.nodes {
+		switch n.typ {
+		case NodeRead:
+			s.ReadNodes++
+		case NodeMerge:
+			s.MergeNodes++
+		}
+	}
+	if len(p.nodes) == 0 && len(p.blocks) > 0 {
+		// The root is an implicit read node.
+		s.ReadNodes = 1
+	}
+	return &s
+}
+
 func (p *QueryPlan) String() string {
 	var b strings.Builder
 	printPlan(&b, "", p, false)
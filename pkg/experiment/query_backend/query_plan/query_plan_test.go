@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"os"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -126,3 +127,81 @@ func Test_Plan_skip_top_merge(t *testing.T) {
 
 	require.Equal(t, expected, buf.String())
 }
+
+func Test_BuildWithOptions(t *testing.T) {
+	block := func(id string, level, shard uint32, sizes ...uint64) *metastorev1.BlockMeta {
+		b := &metastorev1.BlockMeta{Id: id, CompactionLevel: level, Shard: shard}
+		for _, s := range sizes {
+			b.Datasets = append(b.Datasets, &metastorev1.Dataset{Size: s})
+		}
+		return b
+	}
+	blocks := []*metastorev1.BlockMeta{
+		block("a", 0, 2, 10),
+		block("b", 1, 1, 40, 40),
+		block("c", 0, 1, 10),
+		block("d", 1, 1, 50),
+		block("e", 0, 2, 10, 10),
+		block("f", 1, 2, 30),
+		block("g", 0, 1, 10),
+		block("h", 2, 1, 500),
+	}
+	input := slices.Clone(blocks)
+
+	p := BuildWithOptions(blocks, Options{
+		MaxReadBytes:    100,
+		MaxReadDatasets: 3,
+		MaxReadBlocks:   4,
+		MaxMerges:       2,
+	})
+	assert.Equal(t, input, blocks)
+
+	var reads [][]string
+	var visit func(*QueryPlan)
+	visit = func(x *QueryPlan) {
+		switch r := x.Root(); r.Type {
+		case NodeMerge:
+			c := r.Children()
+			for c.Next() {
+				visit(c.At().Plan())
+			}
+		case NodeRead:
+			var ids []string
+			for _, b := range iter.MustSlice(r.Blocks()) {
+				ids = append(ids, b.Id)
+			}
+			reads = append(reads, ids)
+		}
+	}
+	visit(Open(p.Proto()))
+
+	expected := [][]string{
+		// Level 0: limited by the number of datasets;
+		// blocks of the same shard are adjacent.
+		{"c", "g", "a"},
+		{"e"},
+		// Level 1: limited by size.
+		{"b"},
+		{"d", "f"},
+		// Level 2: a block exceeding the limit
+		// is read by a dedicated node.
+		{"h"},
+	}
+	assert.Equal(t, expected, reads)
+
+	stats := p.Stats()
+	assert.Equal(t, uint64(8), stats.Blocks)
+	assert.Equal(t, uint64(10), stats.Datasets)
+	assert.Equal(t, uint64(710), stats.DatasetBytes)
+	assert.Equal(t, uint64(5), stats.ReadNodes)
+	assert.Equal(t, uint64(6), stats.MergeNodes)
+}
+
+func Test_BuildWithOptions_single_read(t *testing.T) {
+	blocks := []*metastorev1.BlockMeta{{Id: "1"}, {Id: "2"}}
+	p := BuildWithOptions(blocks, DefaultOptions)
+	r := p.Root()
+	assert.Equal(t, NodeRead, r.Type)
+	assert.Len(t, iter.MustSlice(r.Blocks()), 2)
+	assert.Equal(t, uint64(1), p.Stats().ReadNodes)
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/labels"
 
+	"github.com/grafana/pyroscope/pkg/experiment/query_backend/block"
 	"github.com/grafana/pyroscope/pkg/iter"
 	phlaremodel "github.com/grafana/pyroscope/pkg/model"
 	"github.com/grafana/pyroscope/pkg/phlaredb"
@@ -18,12 +19,23 @@ func profileEntryIterator(q *queryContext, groupBy ...string) (iter.Iterator[Pro
 	if err != nil {
 		return nil, err
 	}
+	return profileTableEntryIterator(q, q.ds.Profiles(), series, q.req.startTime, q.req.endTime), nil
+}
+
+// profileTableEntryIterator iterates over the profiles of the given series
+// in the profile table within the time range (Unix nano, inclusive).
+func profileTableEntryIterator(
+	q *queryContext,
+	table *block.ParquetFile,
+	series map[uint32]seriesLabels,
+	startTime, endTime int64,
+) iter.Iterator[ProfileEntry] {
 	results := parquetquery.NewBinaryJoinIterator(0,
-		q.ds.Profiles().Column(q.ctx, "SeriesIndex", parquetquery.NewMapPredicate(series)),
-		q.ds.Profiles().Column(q.ctx, "TimeNanos", parquetquery.NewIntBetweenPredicate(q.req.startTime, q.req.endTime)),
+		table.Column(q.ctx, "SeriesIndex", parquetquery.NewMapPredicate(series)),
+		table.Column(q.ctx, "TimeNanos", parquetquery.NewIntBetweenPredicate(startTime, endTime)),
 	)
 	results = parquetquery.NewBinaryJoinIterator(0, results,
-		q.ds.Profiles().Column(q.ctx, "StacktracePartition", nil),
+		table.Column(q.ctx, "StacktracePartition", nil),
 	)
 
 	buf := make([][]parquet.Value, 3)
@@ -45,7 +57,7 @@ func profileEntryIterator(q *queryContext, groupBy ...string) (iter.Iterator[Pro
 		},
 		func([]ProfileEntry) {},
 	)
-	return entries, nil
+	return entries
 }
 
 type ProfileEntry struct {
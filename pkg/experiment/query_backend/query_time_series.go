@@ -24,47 +24,75 @@ func init() {
 		[]block.Section{
 			block.SectionTSDB,
 			block.SectionProfiles,
+			block.SectionDownsampledProfiles,
 		}...,
 	)
 }
 
 func queryTimeSeries(q *queryContext, query *queryv1.Query) (r *queryv1.Report, err error) {
-	entries, err := profileEntryIterator(q, query.TimeSeries.GroupBy...)
+	series, err := getSeriesLabels(q.ds.Index(), q.req.matchers, query.TimeSeries.GroupBy...)
 	if err != nil {
 		return nil, err
 	}
+
+	builder := phlaremodel.NewTimeSeriesBuilder(query.TimeSeries.GroupBy...)
+	step := time.Duration(query.TimeSeries.GetStep() * float64(time.Second))
+	for _, tr := range profileTableRanges(q, stepAlignedResolution(q.req.startTime, step)) {
+		if err = buildTimeSeries(q, builder, series, tr); err != nil {
+			return nil, err
+		}
+	}
+
+	resp := &queryv1.Report{
+		TimeSeries: &queryv1.TimeSeriesReport{
+			Query:      query.TimeSeries.CloneVT(),
+			TimeSeries: builder.Build(),
+		},
+	}
+
+	return resp, nil
+}
+
+func buildTimeSeries(
+	q *queryContext,
+	builder *phlaremodel.TimeSeriesBuilder,
+	series map[uint32]seriesLabels,
+	tr profileTableRange,
+) (err error) {
+	entries := profileTableEntryIterator(q, tr.table, series, tr.startTime, tr.endTime)
 	defer runutil.CloseWithErrCapture(&err, entries, "failed to close profile entry iterator")
 
-	column, err := schemav1.ResolveColumnByPath(q.ds.Profiles().Schema(), strings.Split("TotalValue", "."))
+	column, err := schemav1.ResolveColumnByPath(tr.table.Schema(), strings.Split("TotalValue", "."))
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	rows := parquetquery.NewRepeatedRowIterator(q.ctx, entries, q.ds.Profiles().RowGroups(), column.ColumnIndex)
+	rows := parquetquery.NewRepeatedRowIterator(q.ctx, entries, tr.table.RowGroups(), column.ColumnIndex)
 	defer runutil.CloseWithErrCapture(&err, rows, "failed to close column iterator")
 
-	builder := phlaremodel.NewTimeSeriesBuilder(query.TimeSeries.GroupBy...)
+	offset := downsampledTimestampOffset(tr.resolution)
 	for rows.Next() {
 		row := rows.At()
 		builder.Add(
 			row.Row.Fingerprint,
 			row.Row.Labels,
-			int64(row.Row.Timestamp),
+			int64(row.Row.Timestamp)+offset,
 			float64(row.Values[0][0].Int64()),
 		)
 	}
-	if err = rows.Err(); err != nil {
-		return nil, err
-	}
+	return rows.Err()
+}
 
-	resp := &queryv1.Report{
-		TimeSeries: &queryv1.TimeSeriesReport{
-			Query:      query.TimeSeries.CloneVT(),
-			TimeSeries: builder.Build(),
-		},
+// downsampledTimestampOffset returns the offset, in milliseconds, of the
+// time series points built from the profiles of the given resolution.
+// A downsampled profile is timestamped with the beginning of the interval
+// it covers. The point is moved to the end of the interval, so that it
+// falls into the same step as the profiles it aggregates.
+func downsampledTimestampOffset(resolution time.Duration) int64 {
+	if resolution <= 0 {
+		return 0
 	}
-
-	return resp, nil
+	return resolution.Milliseconds() - 1
 }
 
 type timeSeriesAggregator struct {
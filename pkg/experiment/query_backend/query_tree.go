@@ -23,37 +23,27 @@ func init() {
 			block.SectionTSDB,
 			block.SectionProfiles,
 			block.SectionSymbols,
+			block.SectionDownsampledProfiles,
 		}...,
 	)
 }
 
 func queryTree(q *queryContext, query *queryv1.Query) (*queryv1.Report, error) {
-	entries, err := profileEntryIterator(q)
+	series, err := getSeriesLabels(q.ds.Index(), q.req.matchers)
 	if err != nil {
 		return nil, err
 	}
-	defer runutil.CloseWithErrCapture(&err, entries, "failed to close profile entry iterator")
-
-	var columns v1.SampleColumns
-	if err = columns.Resolve(q.ds.Profiles().Schema()); err != nil {
-		return nil, err
-	}
-
-	profiles := parquetquery.NewRepeatedRowIterator(q.ctx, entries, q.ds.Profiles().RowGroups(),
-		columns.StacktraceID.ColumnIndex,
-		columns.Value.ColumnIndex)
-	defer runutil.CloseWithErrCapture(&err, profiles, "failed to close profile stream")
 
 	resolver := symdb.NewResolver(q.ctx, q.ds.Symbols(),
 		symdb.WithResolverMaxNodes(query.Tree.GetMaxNodes()))
 	defer resolver.Release()
 
-	for profiles.Next() {
-		p := profiles.At()
-		resolver.AddSamplesFromParquetRow(p.Row.Partition, p.Values[0], p.Values[1])
-	}
-	if err = profiles.Err(); err != nil {
-		return nil, err
+	// The time distribution of samples does not matter for the tree,
+	// therefore any downsampled table can be used.
+	for _, tr := range profileTableRanges(q, anyResolution) {
+		if err = addTreeSamples(q, resolver, series, tr); err != nil {
+			return nil, err
+		}
 	}
 
 	tree, err := resolver.Tree()
@@ -70,6 +60,32 @@ func queryTree(q *queryContext, query *queryv1.Query) (*queryv1.Report, error) {
 	return resp, nil
 }
 
+func addTreeSamples(
+	q *queryContext,
+	resolver *symdb.Resolver,
+	series map[uint32]seriesLabels,
+	tr profileTableRange,
+) (err error) {
+	entries := profileTableEntryIterator(q, tr.table, series, tr.startTime, tr.endTime)
+	defer runutil.CloseWithErrCapture(&err, entries, "failed to close profile entry iterator")
+
+	var columns v1.SampleColumns
+	if err = columns.Resolve(tr.table.Schema()); err != nil {
+		return err
+	}
+
+	profiles := parquetquery.NewRepeatedRowIterator(q.ctx, entries, tr.table.RowGroups(),
+		columns.StacktraceID.ColumnIndex,
+		columns.Value.ColumnIndex)
+	defer runutil.CloseWithErrCapture(&err, profiles, "failed to close profile stream")
+
+	for profiles.Next() {
+		p := profiles.At()
+		resolver.AddSamplesFromParquetRow(p.Row.Partition, p.Values[0], p.Values[1])
+	}
+	return profiles.Err()
+}
+
 type treeAggregator struct {
 	init  sync.Once
 	query *queryv1.TreeQuery
@@ -32,6 +32,7 @@ import (
 	"github.com/grafana/pyroscope/pkg/frontend/frontendpb"
 	"github.com/grafana/pyroscope/pkg/querier/stats"
 	"github.com/grafana/pyroscope/pkg/scheduler/schedulerdiscovery"
+	"github.com/grafana/pyroscope/pkg/scheduler/schedulerpb"
 	"github.com/grafana/pyroscope/pkg/util/connectgrpc"
 	"github.com/grafana/pyroscope/pkg/util/httpgrpc"
 	"github.com/grafana/pyroscope/pkg/util/httpgrpcutil"
@@ -52,6 +53,8 @@ type Config struct {
 	Addr string `yaml:"address" category:"advanced"`
 	Port int    `yaml:"-"`
 
+	TenantFederationEnabled bool `yaml:"tenant_federation_enabled" category:"experimental"`
+
 	// This configuration is injected internally.
 	QuerySchedulerDiscovery schedulerdiscovery.Config `yaml:"-"`
 	MaxLoopDuration         time.Duration             `yaml:"-"`
@@ -64,6 +67,8 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet, logger log.Logger) {
 	f.Var((*flagext.StringSlice)(&cfg.InfNames), "query-frontend.instance-interface-names", "List of network interface names to look up when finding the instance IP address. This address is sent to query-scheduler and querier, which uses it to send the query response back to query-frontend.")
 	f.StringVar(&cfg.Addr, "query-frontend.instance-addr", "", "IP address to advertise to the querier (via scheduler) (default is auto-detected from network interfaces).")
 
+	f.BoolVar(&cfg.TenantFederationEnabled, "query-frontend.tenant-federation.enabled", false, "If enabled, queries can span multiple tenants, whose IDs are separated by '|' in the tenant header. Results are merged and labelled with the __tenant_id__ label, which can be used in selectors and group by clauses.")
+
 	cfg.GRPCClientConfig.RegisterFlagsWithPrefix("query-frontend.grpc-client-config", f)
 }
 
@@ -102,6 +107,9 @@ type Limits interface {
 	MaxQueryLength(tenantID string) time.Duration
 	MaxQueryLookback(tenantID string) time.Duration
 	QueryAnalysisEnabled(string) bool
+	QueryAnalysisSeriesEnabled(string) bool
+	QueryCostEstimationEnabled(string) bool
+	validation.QueryImpactLimits
 	validation.FlameGraphLimits
 }
 
@@ -110,6 +118,8 @@ type frontendRequest struct {
 	request      *httpgrpc.HTTPRequest
 	userID       string
 	statsEnabled bool
+	priority     schedulerpb.QueryPriority
+	cost         uint64
 
 	cancel context.CancelFunc
 
@@ -230,6 +240,8 @@ func (rt *realFrontendRoundTripper) RoundTripGRPC(ctx context.Context, req *http
 		request:      req,
 		userID:       userID,
 		statsEnabled: stats.IsEnabled(ctx),
+		priority:     queryPriority(req),
+		cost:         queryCostFromContext(ctx),
 
 		cancel: cancel,
 
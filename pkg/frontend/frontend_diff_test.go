@@ -39,6 +39,26 @@ func (m *mockLimits) QueryAnalysisEnabled(_ string) bool {
 	return true
 }
 
+func (m *mockLimits) QueryAnalysisSeriesEnabled(_ string) bool {
+	return false
+}
+
+func (m *mockLimits) QueryCostEstimationEnabled(_ string) bool {
+	return false
+}
+
+func (m *mockLimits) MaxQueryBytesScanned(_ string) int {
+	return 0
+}
+
+func (m *mockLimits) MaxQuerySeries(_ string) int {
+	return 0
+}
+
+func (m *mockLimits) MaxQueryProfiles(_ string) int {
+	return 0
+}
+
 func (m *mockLimits) MaxFlameGraphNodesDefault(_ string) int {
 	return 10_000
 }
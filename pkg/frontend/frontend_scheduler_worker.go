@@ -367,6 +367,8 @@ func (w *frontendSchedulerWorker) schedulerLoop(loop schedulerpb.SchedulerForFro
 				HttpRequest:     req.request,
 				FrontendAddress: w.frontendAddr,
 				StatsEnabled:    req.statsEnabled,
+				Priority:        req.priority,
+				Cost:            req.cost,
 			})
 			w.enqueuedRequests.Inc()
 
@@ -51,6 +51,10 @@ func (f *Frontend) SelectMergeProfile(
 	}
 
 	interval := validationutil.MaxDurationOrZeroPerTenant(tenantIDs, f.limits.QuerySplitDuration)
+	cost, err := f.estimateQueryCost(ctx, tenantIDs, c.Msg.LabelSelector, validated.Interval)
+	if err != nil {
+		return nil, err
+	}
 	intervals := NewTimeIntervalIterator(time.UnixMilli(int64(validated.Start)), time.UnixMilli(int64(validated.End)), interval)
 
 	// NOTE: Max nodes limit is not set by default:
@@ -71,7 +75,7 @@ func (f *Frontend) SelectMergeProfile(
 			})
 			resp, err := connectgrpc.RoundTripUnary[
 				querierv1.SelectMergeProfileRequest,
-				profilev1.Profile](ctx, f, req)
+				profilev1.Profile](cost.forInterval(ctx, r), f, req)
 			if err != nil {
 				return err
 			}
@@ -54,6 +54,10 @@ func (f *Frontend) SelectMergeSpanProfile(
 
 	m := phlaremodel.NewFlameGraphMerger()
 	interval := validationutil.MaxDurationOrZeroPerTenant(tenantIDs, f.limits.QuerySplitDuration)
+	cost, err := f.estimateQueryCost(ctx, tenantIDs, c.Msg.LabelSelector, validated.Interval)
+	if err != nil {
+		return nil, err
+	}
 	intervals := NewTimeIntervalIterator(time.UnixMilli(int64(validated.Start)), time.UnixMilli(int64(validated.End)), interval)
 
 	for intervals.Next() {
@@ -70,7 +74,7 @@ func (f *Frontend) SelectMergeSpanProfile(
 			})
 			resp, err := connectgrpc.RoundTripUnary[
 				querierv1.SelectMergeSpanProfileRequest,
-				querierv1.SelectMergeSpanProfileResponse](ctx, f, req)
+				querierv1.SelectMergeSpanProfileResponse](cost.forInterval(ctx, r), f, req)
 			if err != nil {
 				return err
 			}
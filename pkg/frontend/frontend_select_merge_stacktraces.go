@@ -72,6 +72,10 @@ func (f *Frontend) selectMergeStacktracesTree(
 
 	m := phlaremodel.NewFlameGraphMerger()
 	interval := validationutil.MaxDurationOrZeroPerTenant(tenantIDs, f.limits.QuerySplitDuration)
+	cost, err := f.estimateQueryCost(ctx, tenantIDs, c.Msg.LabelSelector, validated.Interval)
+	if err != nil {
+		return nil, err
+	}
 	intervals := NewTimeIntervalIterator(time.UnixMilli(int64(validated.Start)), time.UnixMilli(int64(validated.End)), interval)
 
 	for intervals.Next() {
@@ -87,7 +91,7 @@ func (f *Frontend) selectMergeStacktracesTree(
 			})
 			resp, err := connectgrpc.RoundTripUnary[
 				querierv1.SelectMergeStacktracesRequest,
-				querierv1.SelectMergeStacktracesResponse](ctx, f, req)
+				querierv1.SelectMergeStacktracesResponse](cost.forInterval(ctx, r), f, req)
 			if err != nil {
 				return err
 			}
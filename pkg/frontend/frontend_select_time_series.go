@@ -53,6 +53,10 @@ func (f *Frontend) SelectSeries(
 
 	m := phlaremodel.NewTimeSeriesMerger(true)
 	interval := validationutil.MaxDurationOrZeroPerTenant(tenantIDs, f.limits.QuerySplitDuration)
+	cost, err := f.estimateQueryCost(ctx, tenantIDs, c.Msg.LabelSelector, validated.Interval)
+	if err != nil {
+		return nil, err
+	}
 	intervals := NewTimeIntervalIterator(time.UnixMilli(c.Msg.Start), time.UnixMilli(c.Msg.End), interval,
 		WithAlignment(time.Second*time.Duration(c.Msg.Step)))
 
@@ -71,7 +75,7 @@ func (f *Frontend) SelectSeries(
 			})
 			resp, err := connectgrpc.RoundTripUnary[
 				querierv1.SelectSeriesRequest,
-				querierv1.SelectSeriesResponse](ctx, f, req)
+				querierv1.SelectSeriesResponse](cost.forInterval(ctx, r), f, req)
 			if err != nil {
 				return err
 			}
@@ -0,0 +1,611 @@
+package frontend
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/grafana/dskit/tenant"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+	"golang.org/x/sync/errgroup"
+
+	profilev1 "github.com/grafana/pyroscope/api/gen/proto/go/google/v1"
+	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/querier/v1/querierv1connect"
+	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+	phlaremodel "github.com/grafana/pyroscope/pkg/model"
+	"github.com/grafana/pyroscope/pkg/pprof"
+	pyroscopetenant "github.com/grafana/pyroscope/pkg/tenant"
+	"github.com/grafana/pyroscope/pkg/validation"
+)
+
+// TenantFederation serves queries spanning multiple tenants, e.g. with the
+// org ID "a|b", by fanning them out per tenant to the underlying handler and
+// merging the results. Series are labelled with the tenant they belong to
+// (__tenant_id__), which can be used in selectors and group by clauses.
+// Queries for a single tenant are passed through as is.
+type TenantFederation struct {
+	limits  Limits
+	handler querierv1connect.QuerierServiceHandler
+}
+
+func NewTenantFederation(limits Limits, handler querierv1connect.QuerierServiceHandler) *TenantFederation {
+	return &TenantFederation{
+		limits:  limits,
+		handler: handler,
+	}
+}
+
+func (f *TenantFederation) ProfileTypes(
+	ctx context.Context,
+	c *connect.Request[querierv1.ProfileTypesRequest],
+) (*connect.Response[querierv1.ProfileTypesResponse], error) {
+	tenantIDs, ok := federatedTenants(ctx)
+	if !ok {
+		return f.handler.ProfileTypes(ctx, c)
+	}
+	responses, err := forEachTenant(ctx, tenantIDs, func(ctx context.Context, _ string) (*querierv1.ProfileTypesResponse, error) {
+		resp, err := f.handler.ProfileTypes(ctx, connect.NewRequest(c.Msg.CloneVT()))
+		if err != nil {
+			return nil, err
+		}
+		return resp.Msg, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*typesv1.ProfileType)
+	for _, resp := range responses {
+		for _, t := range resp.ProfileTypes {
+			byID[t.ID] = t
+		}
+	}
+	res := &querierv1.ProfileTypesResponse{ProfileTypes: make([]*typesv1.ProfileType, 0, len(byID))}
+	for _, t := range byID {
+		res.ProfileTypes = append(res.ProfileTypes, t)
+	}
+	sort.Slice(res.ProfileTypes, func(i, j int) bool {
+		return res.ProfileTypes[i].ID < res.ProfileTypes[j].ID
+	})
+	return connect.NewResponse(res), nil
+}
+
+func (f *TenantFederation) LabelValues(
+	ctx context.Context,
+	c *connect.Request[typesv1.LabelValuesRequest],
+) (*connect.Response[typesv1.LabelValuesResponse], error) {
+	tenantIDs, ok := federatedTenants(ctx)
+	if !ok {
+		return f.handler.LabelValues(ctx, c)
+	}
+	selectors, err := selectorsPerTenant(c.Msg.Matchers, tenantIDs)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if c.Msg.Name == phlaremodel.LabelNameTenantID {
+		return connect.NewResponse(&typesv1.LabelValuesResponse{Names: tenantsOf(selectors, tenantIDs)}), nil
+	}
+	responses, err := forEachTenant(ctx, tenantsOf(selectors, tenantIDs), func(ctx context.Context, tenantID string) (*typesv1.LabelValuesResponse, error) {
+		req := c.Msg.CloneVT()
+		req.Matchers = selectors[tenantID]
+		resp, err := f.handler.LabelValues(ctx, connect.NewRequest(req))
+		if err != nil {
+			return nil, err
+		}
+		return resp.Msg, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, resp := range responses {
+		names = append(names, resp.Names...)
+	}
+	return connect.NewResponse(&typesv1.LabelValuesResponse{Names: sortedUnique(names)}), nil
+}
+
+func (f *TenantFederation) LabelNames(
+	ctx context.Context,
+	c *connect.Request[typesv1.LabelNamesRequest],
+) (*connect.Response[typesv1.LabelNamesResponse], error) {
+	tenantIDs, ok := federatedTenants(ctx)
+	if !ok {
+		return f.handler.LabelNames(ctx, c)
+	}
+	selectors, err := selectorsPerTenant(c.Msg.Matchers, tenantIDs)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	responses, err := forEachTenant(ctx, tenantsOf(selectors, tenantIDs), func(ctx context.Context, tenantID string) (*typesv1.LabelNamesResponse, error) {
+		req := c.Msg.CloneVT()
+		req.Matchers = selectors[tenantID]
+		resp, err := f.handler.LabelNames(ctx, connect.NewRequest(req))
+		if err != nil {
+			return nil, err
+		}
+		return resp.Msg, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	names := []string{phlaremodel.LabelNameTenantID}
+	for _, resp := range responses {
+		names = append(names, resp.Names...)
+	}
+	return connect.NewResponse(&typesv1.LabelNamesResponse{Names: sortedUnique(names)}), nil
+}
+
+func (f *TenantFederation) Series(
+	ctx context.Context,
+	c *connect.Request[querierv1.SeriesRequest],
+) (*connect.Response[querierv1.SeriesResponse], error) {
+	tenantIDs, ok := federatedTenants(ctx)
+	if !ok {
+		return f.handler.Series(ctx, c)
+	}
+	selectors, err := selectorsPerTenant(c.Msg.Matchers, tenantIDs)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	withTenantID := len(c.Msg.LabelNames) == 0 || slices.Contains(c.Msg.LabelNames, phlaremodel.LabelNameTenantID)
+	labelNames := withoutTenantID(c.Msg.LabelNames)
+	responses, err := forEachTenant(ctx, tenantsOf(selectors, tenantIDs), func(ctx context.Context, tenantID string) ([]*typesv1.Labels, error) {
+		req := c.Msg.CloneVT()
+		req.Matchers = selectors[tenantID]
+		req.LabelNames = labelNames
+		if len(labelNames) == 0 && len(c.Msg.LabelNames) > 0 {
+			// Only the tenant label is requested.
+			return []*typesv1.Labels{{Labels: []*typesv1.LabelPair{{Name: phlaremodel.LabelNameTenantID, Value: tenantID}}}}, nil
+		}
+		resp, err := f.handler.Series(ctx, connect.NewRequest(req))
+		if err != nil {
+			return nil, err
+		}
+		if withTenantID {
+			for _, ls := range resp.Msg.LabelsSet {
+				ls.Labels = phlaremodel.Labels(ls.Labels).InsertSorted(phlaremodel.LabelNameTenantID, tenantID)
+			}
+		}
+		return resp.Msg.LabelsSet, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[uint64]struct{})
+	res := &querierv1.SeriesResponse{}
+	for _, labelsSet := range responses {
+		for _, ls := range labelsSet {
+			h := phlaremodel.Labels(ls.Labels).Hash()
+			if _, ok := seen[h]; ok {
+				continue
+			}
+			seen[h] = struct{}{}
+			res.LabelsSet = append(res.LabelsSet, ls)
+		}
+	}
+	return connect.NewResponse(res), nil
+}
+
+func (f *TenantFederation) SelectMergeStacktraces(
+	ctx context.Context,
+	c *connect.Request[querierv1.SelectMergeStacktracesRequest],
+) (*connect.Response[querierv1.SelectMergeStacktracesResponse], error) {
+	tenantIDs, ok := federatedTenants(ctx)
+	if !ok {
+		return f.handler.SelectMergeStacktraces(ctx, c)
+	}
+	maxNodes, err := validation.ValidateMaxNodes(f.limits, tenantIDs, c.Msg.GetMaxNodes())
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	t, err := f.selectMergeStacktracesTree(ctx, tenantIDs, c.Msg, maxNodes)
+	if err != nil {
+		return nil, err
+	}
+	var resp querierv1.SelectMergeStacktracesResponse
+	switch c.Msg.Format {
+	default:
+		resp.Flamegraph = phlaremodel.NewFlameGraph(t, maxNodes)
+	case querierv1.ProfileFormat_PROFILE_FORMAT_TREE:
+		resp.Tree = t.Bytes(maxNodes)
+	}
+	return connect.NewResponse(&resp), nil
+}
+
+func (f *TenantFederation) selectMergeStacktracesTree(
+	ctx context.Context,
+	tenantIDs []string,
+	msg *querierv1.SelectMergeStacktracesRequest,
+	maxNodes int64,
+) (*phlaremodel.Tree, error) {
+	selector, tenantIDs, err := selectorForTenants(msg.LabelSelector, tenantIDs)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	responses, err := forEachTenant(ctx, tenantIDs, func(ctx context.Context, _ string) (*querierv1.SelectMergeStacktracesResponse, error) {
+		req := msg.CloneVT()
+		req.LabelSelector = selector
+		req.MaxNodes = &maxNodes
+		req.Format = querierv1.ProfileFormat_PROFILE_FORMAT_TREE
+		resp, err := f.handler.SelectMergeStacktraces(ctx, connect.NewRequest(req))
+		if err != nil {
+			return nil, err
+		}
+		return resp.Msg, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	m := phlaremodel.NewFlameGraphMerger()
+	for _, resp := range responses {
+		if err = m.MergeTreeBytes(resp.Tree); err != nil {
+			return nil, err
+		}
+	}
+	return m.Tree(), nil
+}
+
+func (f *TenantFederation) SelectMergeSpanProfile(
+	ctx context.Context,
+	c *connect.Request[querierv1.SelectMergeSpanProfileRequest],
+) (*connect.Response[querierv1.SelectMergeSpanProfileResponse], error) {
+	tenantIDs, ok := federatedTenants(ctx)
+	if !ok {
+		return f.handler.SelectMergeSpanProfile(ctx, c)
+	}
+	maxNodes, err := validation.ValidateMaxNodes(f.limits, tenantIDs, c.Msg.GetMaxNodes())
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	selector, tenantIDs, err := selectorForTenants(c.Msg.LabelSelector, tenantIDs)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	responses, err := forEachTenant(ctx, tenantIDs, func(ctx context.Context, _ string) (*querierv1.SelectMergeSpanProfileResponse, error) {
+		req := c.Msg.CloneVT()
+		req.LabelSelector = selector
+		req.MaxNodes = &maxNodes
+		req.Format = querierv1.ProfileFormat_PROFILE_FORMAT_TREE
+		resp, err := f.handler.SelectMergeSpanProfile(ctx, connect.NewRequest(req))
+		if err != nil {
+			return nil, err
+		}
+		return resp.Msg, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	m := phlaremodel.NewFlameGraphMerger()
+	for _, resp := range responses {
+		if err = m.MergeTreeBytes(resp.Tree); err != nil {
+			return nil, err
+		}
+	}
+	var resp querierv1.SelectMergeSpanProfileResponse
+	switch c.Msg.Format {
+	default:
+		resp.Flamegraph = m.FlameGraph(maxNodes)
+	case querierv1.ProfileFormat_PROFILE_FORMAT_TREE:
+		resp.Tree = m.Tree().Bytes(maxNodes)
+	}
+	return connect.NewResponse(&resp), nil
+}
+
+func (f *TenantFederation) SelectMergeProfile(
+	ctx context.Context,
+	c *connect.Request[querierv1.SelectMergeProfileRequest],
+) (*connect.Response[profilev1.Profile], error) {
+	tenantIDs, ok := federatedTenants(ctx)
+	if !ok {
+		return f.handler.SelectMergeProfile(ctx, c)
+	}
+	selector, tenantIDs, err := selectorForTenants(c.Msg.LabelSelector, tenantIDs)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	responses, err := forEachTenant(ctx, tenantIDs, func(ctx context.Context, _ string) (*profilev1.Profile, error) {
+		req := c.Msg.CloneVT()
+		req.LabelSelector = selector
+		resp, err := f.handler.SelectMergeProfile(ctx, connect.NewRequest(req))
+		if err != nil {
+			return nil, err
+		}
+		return resp.Msg, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	var m pprof.ProfileMerge
+	for _, resp := range responses {
+		if err = m.Merge(resp); err != nil {
+			return nil, err
+		}
+	}
+	return connect.NewResponse(m.Profile()), nil
+}
+
+func (f *TenantFederation) SelectSeries(
+	ctx context.Context,
+	c *connect.Request[querierv1.SelectSeriesRequest],
+) (*connect.Response[querierv1.SelectSeriesResponse], error) {
+	tenantIDs, ok := federatedTenants(ctx)
+	if !ok {
+		return f.handler.SelectSeries(ctx, c)
+	}
+	selector, tenantIDs, err := selectorForTenants(c.Msg.LabelSelector, tenantIDs)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	byTenantID := slices.Contains(c.Msg.GroupBy, phlaremodel.LabelNameTenantID)
+	responses, err := forEachTenant(ctx, tenantIDs, func(ctx context.Context, tenantID string) ([]*typesv1.Series, error) {
+		req := c.Msg.CloneVT()
+		req.LabelSelector = selector
+		req.GroupBy = withoutTenantID(c.Msg.GroupBy)
+		resp, err := f.handler.SelectSeries(ctx, connect.NewRequest(req))
+		if err != nil {
+			return nil, err
+		}
+		if byTenantID {
+			for _, s := range resp.Msg.Series {
+				s.Labels = phlaremodel.Labels(s.Labels).InsertSorted(phlaremodel.LabelNameTenantID, tenantID)
+			}
+		}
+		return resp.Msg.Series, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	m := phlaremodel.NewTimeSeriesMerger(true)
+	for _, series := range responses {
+		m.MergeTimeSeries(series)
+	}
+	return connect.NewResponse(&querierv1.SelectSeriesResponse{Series: m.TimeSeries()}), nil
+}
+
+func (f *TenantFederation) Diff(
+	ctx context.Context,
+	c *connect.Request[querierv1.DiffRequest],
+) (*connect.Response[querierv1.DiffResponse], error) {
+	tenantIDs, ok := federatedTenants(ctx)
+	if !ok {
+		return f.handler.Diff(ctx, c)
+	}
+	maxNodes, err := validation.ValidateMaxNodes(f.limits, tenantIDs, max(c.Msg.Left.GetMaxNodes(), c.Msg.Right.GetMaxNodes()))
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	var left, right *phlaremodel.Tree
+	g, gCtx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		var leftErr error
+		left, leftErr = f.selectMergeStacktracesTree(gCtx, tenantIDs, c.Msg.Left, maxNodes)
+		return leftErr
+	})
+	g.Go(func() error {
+		var rightErr error
+		right, rightErr = f.selectMergeStacktracesTree(gCtx, tenantIDs, c.Msg.Right, maxNodes)
+		return rightErr
+	})
+	if err = g.Wait(); err != nil {
+		return nil, err
+	}
+
+	diff, err := phlaremodel.NewFlamegraphDiff(left, right, maxNodes)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	return connect.NewResponse(&querierv1.DiffResponse{Flamegraph: diff}), nil
+}
+
+func (f *TenantFederation) GetProfileStats(
+	ctx context.Context,
+	c *connect.Request[typesv1.GetProfileStatsRequest],
+) (*connect.Response[typesv1.GetProfileStatsResponse], error) {
+	tenantIDs, ok := federatedTenants(ctx)
+	if !ok {
+		return f.handler.GetProfileStats(ctx, c)
+	}
+	responses, err := forEachTenant(ctx, tenantIDs, func(ctx context.Context, _ string) (*typesv1.GetProfileStatsResponse, error) {
+		resp, err := f.handler.GetProfileStats(ctx, connect.NewRequest(c.Msg.CloneVT()))
+		if err != nil {
+			return nil, err
+		}
+		return resp.Msg, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	res := &typesv1.GetProfileStatsResponse{}
+	for _, resp := range responses {
+		if !resp.DataIngested {
+			continue
+		}
+		if !res.DataIngested || resp.OldestProfileTime < res.OldestProfileTime {
+			res.OldestProfileTime = resp.OldestProfileTime
+		}
+		if !res.DataIngested || resp.NewestProfileTime > res.NewestProfileTime {
+			res.NewestProfileTime = resp.NewestProfileTime
+		}
+		res.DataIngested = true
+	}
+	return connect.NewResponse(res), nil
+}
+
+func (f *TenantFederation) AnalyzeQuery(
+	ctx context.Context,
+	c *connect.Request[querierv1.AnalyzeQueryRequest],
+) (*connect.Response[querierv1.AnalyzeQueryResponse], error) {
+	tenantIDs, ok := federatedTenants(ctx)
+	if !ok {
+		return f.handler.AnalyzeQuery(ctx, c)
+	}
+	query, tenantIDs, err := selectorForTenants(c.Msg.Query, tenantIDs)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	responses, err := forEachTenant(ctx, tenantIDs, func(ctx context.Context, _ string) (*querierv1.AnalyzeQueryResponse, error) {
+		req := c.Msg.CloneVT()
+		req.Query = query
+		resp, err := f.handler.AnalyzeQuery(ctx, connect.NewRequest(req))
+		if err != nil {
+			return nil, err
+		}
+		return resp.Msg, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	res := &querierv1.AnalyzeQueryResponse{QueryImpact: &querierv1.QueryImpact{}}
+	scopes := make(map[string]*querierv1.QueryScope)
+	for _, resp := range responses {
+		for _, s := range resp.QueryScopes {
+			scope, ok := scopes[s.ComponentType]
+			if !ok {
+				scope = &querierv1.QueryScope{ComponentType: s.ComponentType}
+				scopes[s.ComponentType] = scope
+				res.QueryScopes = append(res.QueryScopes, scope)
+			}
+			scope.ComponentCount = max(scope.ComponentCount, s.ComponentCount)
+			scope.BlockCount += s.BlockCount
+			scope.SeriesCount += s.SeriesCount
+			scope.ProfileCount += s.ProfileCount
+			scope.SampleCount += s.SampleCount
+			scope.IndexBytes += s.IndexBytes
+			scope.ProfileBytes += s.ProfileBytes
+			scope.SymbolBytes += s.SymbolBytes
+		}
+		if impact := resp.QueryImpact; impact != nil {
+			res.QueryImpact.TotalBytesInTimeRange += impact.TotalBytesInTimeRange
+			res.QueryImpact.TotalQueriedSeries += impact.TotalQueriedSeries
+			res.QueryImpact.DeduplicationNeeded = res.QueryImpact.DeduplicationNeeded || impact.DeduplicationNeeded
+		}
+	}
+	return connect.NewResponse(res), nil
+}
+
+// federatedTenants returns the tenants the request is issued for, if there
+// is more than one. Requests for a single tenant, or without a valid tenant,
+// are left to the underlying handler.
+func federatedTenants(ctx context.Context) ([]string, bool) {
+	tenantIDs, err := tenant.TenantIDs(ctx)
+	if err != nil || len(tenantIDs) < 2 {
+		return nil, false
+	}
+	return tenantIDs, true
+}
+
+// forEachTenant calls fn for every tenant concurrently, with a context
+// scoped to the tenant. Results are returned in the order of the tenants.
+func forEachTenant[T any](ctx context.Context, tenantIDs []string, fn func(context.Context, string) (T, error)) ([]T, error) {
+	results := make([]T, len(tenantIDs))
+	g, ctx := errgroup.WithContext(ctx)
+	for i, tenantID := range tenantIDs {
+		g.Go(func() error {
+			res, err := fn(pyroscopetenant.InjectTenantID(ctx, tenantID), tenantID)
+			if err != nil {
+				return err
+			}
+			results[i] = res
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// selectorForTenants removes the tenant matchers from the label selector,
+// and returns the tenants matching them. If the selector only has tenant
+// matchers, the match-all selector is returned.
+func selectorForTenants(selector string, tenantIDs []string) (string, []string, error) {
+	if selector == "" {
+		return selector, tenantIDs, nil
+	}
+	matchers, err := parser.ParseMetricSelector(selector)
+	if err != nil {
+		return "", nil, err
+	}
+	var (
+		rest    = make([]*labels.Matcher, 0, len(matchers))
+		matched = tenantIDs
+	)
+	for _, m := range matchers {
+		if m.Name != phlaremodel.LabelNameTenantID {
+			rest = append(rest, m)
+			continue
+		}
+		matched = slices.DeleteFunc(slices.Clone(matched), func(tenantID string) bool {
+			return !m.Matches(tenantID)
+		})
+	}
+	switch len(rest) {
+	case len(matchers):
+		return selector, tenantIDs, nil
+	case 0:
+		return "{}", matched, nil
+	}
+	return matchersString(rest), matched, nil
+}
+
+// selectorsPerTenant returns the label selectors to query each tenant with,
+// with the tenant matchers removed. Tenants not matched by any selector are
+// not present in the result.
+func selectorsPerTenant(selectors []string, tenantIDs []string) (map[string][]string, error) {
+	res := make(map[string][]string, len(tenantIDs))
+	if len(selectors) == 0 {
+		for _, tenantID := range tenantIDs {
+			res[tenantID] = nil
+		}
+		return res, nil
+	}
+	for _, s := range selectors {
+		selector, matched, err := selectorForTenants(s, tenantIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, tenantID := range matched {
+			res[tenantID] = append(res[tenantID], selector)
+		}
+	}
+	return res, nil
+}
+
+// tenantsOf returns the tenants present in the selectors, in the original
+// order.
+func tenantsOf(selectors map[string][]string, tenantIDs []string) []string {
+	res := make([]string, 0, len(selectors))
+	for _, tenantID := range tenantIDs {
+		if _, ok := selectors[tenantID]; ok {
+			res = append(res, tenantID)
+		}
+	}
+	return res
+}
+
+func matchersString(matchers []*labels.Matcher) string {
+	s := make([]string, len(matchers))
+	for i, m := range matchers {
+		s[i] = m.String()
+	}
+	return fmt.Sprintf("{%s}", strings.Join(s, ","))
+}
+
+func withoutTenantID(names []string) []string {
+	if !slices.Contains(names, phlaremodel.LabelNameTenantID) {
+		return names
+	}
+	return slices.DeleteFunc(slices.Clone(names), func(name string) bool {
+		return name == phlaremodel.LabelNameTenantID
+	})
+}
+
+func sortedUnique(s []string) []string {
+	slices.Sort(s)
+	return slices.Compact(s)
+}
@@ -0,0 +1,182 @@
+package frontend
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/grafana/dskit/tenant"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/stretchr/testify/require"
+
+	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/querier/v1/querierv1connect"
+	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+	"github.com/grafana/pyroscope/pkg/model"
+)
+
+// federationTestHandler serves a single series and a single stack trace per
+// tenant, with a value specific to the tenant.
+type federationTestHandler struct {
+	querierv1connect.UnimplementedQuerierServiceHandler
+
+	values map[string]int64
+
+	mu        sync.Mutex
+	selectors map[string]string
+}
+
+func (h *federationTestHandler) record(ctx context.Context, selector string) (string, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return "", err
+	}
+	h.mu.Lock()
+	h.selectors[tenantID] = selector
+	h.mu.Unlock()
+	return tenantID, nil
+}
+
+func (h *federationTestHandler) SelectSeries(ctx context.Context, c *connect.Request[querierv1.SelectSeriesRequest]) (*connect.Response[querierv1.SelectSeriesResponse], error) {
+	tenantID, err := h.record(ctx, c.Msg.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&querierv1.SelectSeriesResponse{Series: []*typesv1.Series{{
+		Labels: []*typesv1.LabelPair{{Name: "service_name", Value: "svc"}},
+		Points: []*typesv1.Point{{Timestamp: 1000, Value: float64(h.values[tenantID])}},
+	}}}), nil
+}
+
+func (h *federationTestHandler) SelectMergeStacktraces(ctx context.Context, c *connect.Request[querierv1.SelectMergeStacktracesRequest]) (*connect.Response[querierv1.SelectMergeStacktracesResponse], error) {
+	tenantID, err := h.record(ctx, c.Msg.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+	t := new(model.Tree)
+	t.InsertStack(h.values[tenantID], "main", "foo")
+	t.InsertStack(1, "main", tenantID)
+	return connect.NewResponse(&querierv1.SelectMergeStacktracesResponse{Tree: t.Bytes(-1)}), nil
+}
+
+func (h *federationTestHandler) LabelNames(ctx context.Context, c *connect.Request[typesv1.LabelNamesRequest]) (*connect.Response[typesv1.LabelNamesResponse], error) {
+	tenantID, err := h.record(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&typesv1.LabelNamesResponse{Names: []string{"service_name", tenantID + "_label"}}), nil
+}
+
+func newFederationTest() (*TenantFederation, *federationTestHandler) {
+	h := &federationTestHandler{
+		values:    map[string]int64{"a": 1, "b": 2, "c": 4},
+		selectors: make(map[string]string),
+	}
+	return NewTenantFederation(&mockLimits{}, h), h
+}
+
+func Test_TenantFederation_SelectSeries(t *testing.T) {
+	f, h := newFederationTest()
+	ctx := user.InjectOrgID(context.Background(), "a|b|c")
+
+	t.Run("series of all tenants are merged", func(t *testing.T) {
+		resp, err := f.SelectSeries(ctx, connect.NewRequest(&querierv1.SelectSeriesRequest{
+			LabelSelector: `{service_name="svc"}`,
+		}))
+		require.NoError(t, err)
+		require.Len(t, resp.Msg.Series, 1)
+		require.Equal(t, float64(7), resp.Msg.Series[0].Points[0].Value)
+	})
+
+	t.Run("series are grouped by tenant", func(t *testing.T) {
+		resp, err := f.SelectSeries(ctx, connect.NewRequest(&querierv1.SelectSeriesRequest{
+			LabelSelector: `{service_name="svc", __tenant_id__=~"a|c"}`,
+			GroupBy:       []string{model.LabelNameTenantID},
+		}))
+		require.NoError(t, err)
+		require.Equal(t, `{service_name="svc"}`, h.selectors["a"])
+		require.Len(t, resp.Msg.Series, 2)
+		for _, s := range resp.Msg.Series {
+			tenantID := model.Labels(s.Labels).Get(model.LabelNameTenantID)
+			require.Equal(t, float64(h.values[tenantID]), s.Points[0].Value)
+			require.NotEqual(t, "b", tenantID)
+		}
+	})
+}
+
+func Test_TenantFederation_SelectMergeStacktraces(t *testing.T) {
+	f, h := newFederationTest()
+	ctx := user.InjectOrgID(context.Background(), "a|b|c")
+
+	resp, err := f.SelectMergeStacktraces(ctx, connect.NewRequest(&querierv1.SelectMergeStacktracesRequest{
+		LabelSelector: `{__tenant_id__!="c"}`,
+		Format:        querierv1.ProfileFormat_PROFILE_FORMAT_TREE,
+	}))
+	require.NoError(t, err)
+	tree, err := model.UnmarshalTree(resp.Msg.Tree)
+	require.NoError(t, err)
+	expected := new(model.Tree)
+	expected.InsertStack(3, "main", "foo")
+	expected.InsertStack(1, "main", "a")
+	expected.InsertStack(1, "main", "b")
+	require.Equal(t, expected.String(), tree.String())
+	// The tenants are queried with the match-all selector.
+	require.Equal(t, map[string]string{"a": "{}", "b": "{}"}, h.selectors)
+}
+
+func Test_selectorForTenants(t *testing.T) {
+	tenantIDs := []string{"a", "b", "c"}
+	for _, tc := range []struct {
+		selector, expected string
+		tenantIDs          []string
+	}{
+		{selector: "", expected: "", tenantIDs: tenantIDs},
+		{selector: "{}", expected: "{}", tenantIDs: tenantIDs},
+		{selector: `{service_name="svc"}`, expected: `{service_name="svc"}`, tenantIDs: tenantIDs},
+		{selector: `{__tenant_id__="b"}`, expected: "{}", tenantIDs: []string{"b"}},
+		{selector: `{__tenant_id__=~"a|b", __tenant_id__!="a"}`, expected: "{}", tenantIDs: []string{"b"}},
+		{selector: `{service_name="svc", __tenant_id__!="c"}`, expected: `{service_name="svc"}`, tenantIDs: []string{"a", "b"}},
+	} {
+		t.Run(tc.selector, func(t *testing.T) {
+			selector, matched, err := selectorForTenants(tc.selector, tenantIDs)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, selector)
+			require.Equal(t, tc.tenantIDs, matched)
+			if selector != "" {
+				_, err = parser.ParseMetricSelector(selector)
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_TenantFederation_Labels(t *testing.T) {
+	f, _ := newFederationTest()
+	ctx := user.InjectOrgID(context.Background(), "a|b")
+
+	names, err := f.LabelNames(ctx, connect.NewRequest(&typesv1.LabelNamesRequest{}))
+	require.NoError(t, err)
+	require.Equal(t, []string{model.LabelNameTenantID, "a_label", "b_label", "service_name"}, names.Msg.Names)
+
+	values, err := f.LabelValues(ctx, connect.NewRequest(&typesv1.LabelValuesRequest{
+		Name:     model.LabelNameTenantID,
+		Matchers: []string{`{__tenant_id__="b"}`},
+	}))
+	require.NoError(t, err)
+	require.Equal(t, []string{"b"}, values.Msg.Names)
+}
+
+func Test_TenantFederation_SingleTenant(t *testing.T) {
+	f, h := newFederationTest()
+	ctx := user.InjectOrgID(context.Background(), "b")
+
+	resp, err := f.SelectSeries(ctx, connect.NewRequest(&querierv1.SelectSeriesRequest{
+		LabelSelector: `{service_name="svc"}`,
+		GroupBy:       []string{model.LabelNameTenantID},
+	}))
+	require.NoError(t, err)
+	require.Len(t, h.selectors, 1)
+	require.Equal(t, float64(2), resp.Msg.Series[0].Points[0].Value)
+}
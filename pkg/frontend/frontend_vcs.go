@@ -47,3 +47,24 @@ func (f *Frontend) GetCommit(
 func (f *Frontend) GetCommits(ctx context.Context, req *connect.Request[vcsv1.GetCommitsRequest]) (*connect.Response[vcsv1.GetCommitsResponse], error) {
 	return connectgrpc.RoundTripUnary[vcsv1.GetCommitsRequest, vcsv1.GetCommitsResponse](ctx, f, req)
 }
+
+func (f *Frontend) OAuthApp(
+	ctx context.Context,
+	req *connect.Request[vcsv1.OAuthAppRequest],
+) (*connect.Response[vcsv1.OAuthAppResponse], error) {
+	return connectgrpc.RoundTripUnary[vcsv1.OAuthAppRequest, vcsv1.OAuthAppResponse](ctx, f, req)
+}
+
+func (f *Frontend) OAuthLogin(
+	ctx context.Context,
+	req *connect.Request[vcsv1.OAuthLoginRequest],
+) (*connect.Response[vcsv1.OAuthLoginResponse], error) {
+	return connectgrpc.RoundTripUnary[vcsv1.OAuthLoginRequest, vcsv1.OAuthLoginResponse](ctx, f, req)
+}
+
+func (f *Frontend) OAuthRefresh(
+	ctx context.Context,
+	req *connect.Request[vcsv1.OAuthRefreshRequest],
+) (*connect.Response[vcsv1.OAuthRefreshResponse], error) {
+	return connectgrpc.RoundTripUnary[vcsv1.OAuthRefreshRequest, vcsv1.OAuthRefreshResponse](ctx, f, req)
+}
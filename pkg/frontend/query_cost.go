@@ -0,0 +1,144 @@
+package frontend
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/common/model"
+
+	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
+	"github.com/grafana/pyroscope/pkg/scheduler/schedulerpb"
+	"github.com/grafana/pyroscope/pkg/util/httpgrpc"
+	"github.com/grafana/pyroscope/pkg/validation"
+)
+
+// QueryPriorityHeader is the request header clients use to specify the
+// priority class of a query: "interactive" (default) or "batch".
+const QueryPriorityHeader = "X-Pyroscope-Query-Priority"
+
+const queryPriorityBatch = "batch"
+
+func queryPriority(req *httpgrpc.HTTPRequest) schedulerpb.QueryPriority {
+	for _, h := range req.GetHeaders() {
+		if http.CanonicalHeaderKey(h.Key) != QueryPriorityHeader {
+			continue
+		}
+		for _, v := range h.Values {
+			if strings.EqualFold(strings.TrimSpace(v), queryPriorityBatch) {
+				return schedulerpb.QueryPriority_BATCH
+			}
+		}
+	}
+	return schedulerpb.QueryPriority_INTERACTIVE
+}
+
+type queryCostContextKey struct{}
+
+// withQueryCost attaches the estimated cost of the request, in bytes, to
+// the context. The cost is passed to the query-scheduler along with the
+// request.
+func withQueryCost(ctx context.Context, cost uint64) context.Context {
+	if cost == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, queryCostContextKey{}, cost)
+}
+
+func queryCostFromContext(ctx context.Context) uint64 {
+	cost, _ := ctx.Value(queryCostContextKey{}).(uint64)
+	return cost
+}
+
+// queryCostEstimate is the estimated cost of a query time range.
+// The cost of sub-queries is assumed to be proportional to the
+// fraction of the time range they cover.
+type queryCostEstimate struct {
+	start, end model.Time
+	bytes      uint64
+}
+
+// forInterval returns the context carrying the estimated cost of
+// the sub-query covering the given time interval.
+func (e *queryCostEstimate) forInterval(ctx context.Context, r TimeInterval) context.Context {
+	if e == nil || e.bytes == 0 || e.end <= e.start {
+		return ctx
+	}
+	d := min(model.TimeFromUnixNano(r.End.UnixNano()), e.end) - max(model.TimeFromUnixNano(r.Start.UnixNano()), e.start)
+	if d <= 0 {
+		return ctx
+	}
+	return withQueryCost(ctx, uint64(float64(e.bytes)*float64(d)/float64(e.end-e.start)))
+}
+
+// estimateQueryCost estimates the cost of the query with the query analysis,
+// if cost estimation or any of the query impact limits is enabled for the
+// tenants. An error is returned, if the query is known to exceed the limits.
+// Nil is returned, if the cost can't be estimated: the query-scheduler then
+// assumes the default cost.
+func (f *Frontend) estimateQueryCost(ctx context.Context, tenantIDs []string, selector string, interval model.Interval) (*queryCostEstimate, error) {
+	costEstimationEnabled := true
+	for _, tenantID := range tenantIDs {
+		if !f.limits.QueryCostEstimationEnabled(tenantID) {
+			costEstimationEnabled = false
+			break
+		}
+	}
+	limitsEnabled := validation.QueryImpactLimitsEnabled(f.limits, tenantIDs)
+	if !costEstimationEnabled && !limitsEnabled {
+		return nil, nil
+	}
+	resp, err := f.AnalyzeQuery(ctx, connect.NewRequest(&querierv1.AnalyzeQueryRequest{
+		Start: int64(interval.Start),
+		End:   int64(interval.End),
+		Query: selector,
+	}))
+	if err != nil {
+		// The limits are enforced by ingesters and store-gateways anyway.
+		level.Warn(f.log).Log("msg", "failed to estimate query cost", "err", err)
+		return nil, nil
+	}
+	impact := resp.Msg.GetQueryImpact()
+	bytes := impact.GetTotalBytesInTimeRange()
+	if bytes == 0 {
+		return nil, nil
+	}
+	var totalSeries, totalProfiles uint64
+	for _, s := range resp.Msg.GetQueryScopes() {
+		totalSeries += s.GetSeriesCount()
+		totalProfiles += s.GetProfileCount()
+	}
+	// If the number of series matching the selector is known,
+	// only the respective fraction of the data is to be read.
+	// Otherwise (e.g., the series portion of query analysis is
+	// disabled), the estimate is only an upper bound, and the
+	// limits are not checked: ingesters and store-gateways
+	// enforce them when the query is executed.
+	if queried := impact.GetTotalQueriedSeries(); queried > 0 && totalSeries > 0 {
+		if queried < totalSeries {
+			ratio := float64(queried) / float64(totalSeries)
+			bytes = max(1, uint64(float64(bytes)*ratio))
+			totalProfiles = uint64(float64(totalProfiles) * ratio)
+		}
+		if limitsEnabled {
+			err = validation.ValidateQueryImpact(f.limits, tenantIDs, validation.QueryImpact{
+				Bytes:    bytes,
+				Series:   queried,
+				Profiles: totalProfiles,
+			})
+			if err != nil {
+				return nil, connect.NewError(connect.CodeInvalidArgument, err)
+			}
+		}
+	}
+	if !costEstimationEnabled {
+		return nil, nil
+	}
+	return &queryCostEstimate{
+		start: interval.Start,
+		end:   interval.End,
+		bytes: bytes,
+	}, nil
+}
@@ -0,0 +1,54 @@
+package frontend
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/grafana/pyroscope/pkg/scheduler/schedulerpb"
+	"github.com/grafana/pyroscope/pkg/util/httpgrpc"
+)
+
+func Test_queryPriority(t *testing.T) {
+	for _, tc := range []struct {
+		headers  []*httpgrpc.Header
+		expected schedulerpb.QueryPriority
+	}{
+		{expected: schedulerpb.QueryPriority_INTERACTIVE},
+		{
+			headers:  []*httpgrpc.Header{{Key: QueryPriorityHeader, Values: []string{"interactive"}}},
+			expected: schedulerpb.QueryPriority_INTERACTIVE,
+		},
+		{
+			headers:  []*httpgrpc.Header{{Key: QueryPriorityHeader, Values: []string{"Batch"}}},
+			expected: schedulerpb.QueryPriority_BATCH,
+		},
+		{
+			headers:  []*httpgrpc.Header{{Key: "x-pyroscope-query-priority", Values: []string{"batch"}}},
+			expected: schedulerpb.QueryPriority_BATCH,
+		},
+	} {
+		assert.Equal(t, tc.expected, queryPriority(&httpgrpc.HTTPRequest{Headers: tc.headers}))
+	}
+}
+
+func Test_queryCostEstimate_forInterval(t *testing.T) {
+	ctx := context.Background()
+	start := time.Unix(0, 0)
+	e := &queryCostEstimate{start: 0, end: 4 * 3600 * 1000, bytes: 400}
+
+	intervals := NewTimeIntervalIterator(start, start.Add(4*time.Hour), time.Hour)
+	var total uint64
+	for intervals.Next() {
+		cost := queryCostFromContext(e.forInterval(ctx, intervals.At()))
+		assert.InDelta(t, 100, cost, 1)
+		total += cost
+	}
+	assert.InDelta(t, 400, total, 4)
+
+	// Unknown cost is not propagated.
+	var unknown *queryCostEstimate
+	assert.Zero(t, queryCostFromContext(unknown.forInterval(ctx, TimeInterval{Start: start, End: start.Add(time.Hour)})))
+}
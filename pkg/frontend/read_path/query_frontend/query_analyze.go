@@ -0,0 +1,193 @@
+package query_frontend
+
+import (
+	"context"
+	"fmt"
+
+	"connectrpc.com/connect"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/tenant"
+	"github.com/opentracing/opentracing-go"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
+	queryv1 "github.com/grafana/pyroscope/api/gen/proto/go/query/v1"
+	"github.com/grafana/pyroscope/pkg/experiment/query_backend/block"
+	queryplan "github.com/grafana/pyroscope/pkg/experiment/query_backend/query_plan"
+	"github.com/grafana/pyroscope/pkg/iter"
+	phlaremodel "github.com/grafana/pyroscope/pkg/model"
+	"github.com/grafana/pyroscope/pkg/validation"
+)
+
+// AnalyzeQuery reports the scope of the query plan: the blocks and the
+// data to be read, grouped by the compaction level. If the series analysis
+// is enabled, the method also executes the series query and reports its
+// execution statistics.
+func (q *QueryFrontend) AnalyzeQuery(
+	ctx context.Context,
+	c *connect.Request[querierv1.AnalyzeQueryRequest],
+) (*connect.Response[querierv1.AnalyzeQueryResponse], error) {
+	sp, ctx := opentracing.StartSpanFromContext(ctx, "QueryFrontend.AnalyzeQuery")
+	defer sp.Finish()
+	sp.SetTag("start", model.Time(c.Msg.Start).Time().String()).
+		SetTag("end", model.Time(c.Msg.End).Time().String()).
+		SetTag("query", c.Msg.Query)
+
+	tenantIDs, err := tenant.TenantIDs(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	for _, tenantID := range tenantIDs {
+		if !q.limits.QueryAnalysisEnabled(tenantID) {
+			return connect.NewResponse(&querierv1.AnalyzeQueryResponse{}), nil
+		}
+	}
+	empty, err := validation.SanitizeTimeRange(q.limits, tenantIDs, &c.Msg.Start, &c.Msg.End)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if empty {
+		return connect.NewResponse(&querierv1.AnalyzeQueryResponse{}), nil
+	}
+
+	labelSelector, err := buildLabelSelectorFromQuery(c.Msg.Query)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	p, err := q.queryPlan(ctx, tenantIDs, c.Msg.Start, c.Msg.End, labelSelector)
+	if err != nil {
+		return nil, err
+	}
+	resp := &querierv1.AnalyzeQueryResponse{QueryImpact: new(querierv1.QueryImpact)}
+	if p == nil {
+		return connect.NewResponse(resp), nil
+	}
+	resp.QueryScopes = planQueryScopes(p)
+	for _, s := range resp.QueryScopes {
+		resp.QueryImpact.TotalBytesInTimeRange += s.IndexBytes + s.ProfileBytes + s.SymbolBytes
+	}
+
+	for _, tenantID := range tenantIDs {
+		if !q.limits.QueryAnalysisSeriesEnabled(tenantID) {
+			return connect.NewResponse(resp), nil
+		}
+	}
+	r, err := q.Query(ctx, &queryv1.QueryRequest{
+		StartTime:          c.Msg.Start,
+		EndTime:            c.Msg.End,
+		LabelSelector:      labelSelector,
+		CollectDiagnostics: true,
+		Query: []*queryv1.Query{{
+			QueryType:    queryv1.QueryType_QUERY_SERIES_LABELS,
+			SeriesLabels: &queryv1.SeriesLabelsQuery{},
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var series uint64
+	if report := findReport(queryv1.ReportType_REPORT_SERIES_LABELS, r.Reports); report != nil {
+		series = uint64(len(report.SeriesLabels.SeriesLabels))
+	}
+	resp.QueryImpact.TotalQueriedSeries = series
+	resp.QueryScopes = append(resp.QueryScopes, executionQueryScope(r.Diagnostics.GetExecution(), series))
+	level.Debug(q.logger).Log("msg", "series query executed", "diagnostics", r.Diagnostics.String())
+	return connect.NewResponse(resp), nil
+}
+
+// buildLabelSelectorFromQuery converts the query to the label selector,
+// replacing the metric name with the profile type.
+func buildLabelSelectorFromQuery(query string) (string, error) {
+	var matchers []*labels.Matcher
+	if query != "" {
+		var err error
+		if matchers, err = parser.ParseMetricSelector(query); err != nil {
+			return "", fmt.Errorf("parsing query %q: %w", query, err)
+		}
+	}
+	for _, m := range matchers {
+		if m.Name == labels.MetricName {
+			m.Name = phlaremodel.LabelNameProfileType
+		}
+	}
+	return matchersToLabelSelector(matchers), nil
+}
+
+// planQueryScopes returns the query scopes of the read nodes,
+// grouped by the compaction level of the blocks: a read node
+// never spans blocks of different compaction levels.
+func planQueryScopes(p *queryplan.QueryPlan) []*querierv1.QueryScope {
+	var scopes []*querierv1.QueryScope
+	levels := make(map[uint32]*querierv1.QueryScope)
+	visitReadNodes(p, func(blocks []*metastorev1.BlockMeta) {
+		compactionLevel := blocks[0].CompactionLevel
+		s, ok := levels[compactionLevel]
+		if !ok {
+			s = &querierv1.QueryScope{ComponentType: queryScopeName(compactionLevel)}
+			levels[compactionLevel] = s
+			scopes = append(scopes, s)
+		}
+		s.ComponentCount++
+		s.BlockCount += uint64(len(blocks))
+		for _, md := range blocks {
+			obj := block.NewObject(nil, md)
+			for _, ds := range md.Datasets {
+				d := block.NewDataset(ds, obj)
+				index := uint64(d.SectionSize(block.SectionTSDB))
+				symbols := uint64(d.SectionSize(block.SectionSymbols))
+				s.IndexBytes += index
+				s.SymbolBytes += symbols
+				// Includes the downsampled profiles.
+				s.ProfileBytes += ds.Size - index - symbols
+			}
+		}
+	})
+	return scopes
+}
+
+func visitReadNodes(p *queryplan.QueryPlan, fn func([]*metastorev1.BlockMeta)) {
+	switch r := p.Root(); r.Type {
+	case queryplan.NodeMerge:
+		c := r.Children()
+		for c.Next() {
+			visitReadNodes(c.At().Plan(), fn)
+		}
+	case queryplan.NodeRead:
+		if blocks := iter.MustSlice(r.Blocks()); len(blocks) > 0 {
+			fn(blocks)
+		}
+	}
+}
+
+func queryScopeName(compactionLevel uint32) string {
+	if compactionLevel == 0 {
+		return "Segments"
+	}
+	return fmt.Sprintf("Compacted blocks (level %d)", compactionLevel)
+}
+
+// executionQueryScope returns the query scope of the executed series
+// query. The query only reads the TSDB index of the datasets.
+func executionQueryScope(n *queryv1.ExecutionNode, series uint64) *querierv1.QueryScope {
+	executors := make(map[string]struct{})
+	var visit func(*queryv1.ExecutionNode)
+	visit = func(n *queryv1.ExecutionNode) {
+		if n.GetType() == queryplan.NodeRead.String() {
+			executors[n.Executor] = struct{}{}
+		}
+		for _, c := range n.GetChildren() {
+			visit(c)
+		}
+	}
+	visit(n)
+	return &querierv1.QueryScope{
+		ComponentType:  "Query backend (series query)",
+		ComponentCount: uint64(len(executors)),
+		BlockCount:     n.GetStats().GetBlocksRead(),
+		SeriesCount:    series,
+		IndexBytes:     n.GetStats().GetBytesRead(),
+	}
+}
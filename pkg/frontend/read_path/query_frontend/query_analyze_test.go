@@ -0,0 +1,78 @@
+package query_frontend
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
+	"github.com/grafana/pyroscope/pkg/tenant"
+	"github.com/grafana/pyroscope/pkg/test/mocks/mockfrontend"
+	"github.com/grafana/pyroscope/pkg/test/mocks/mockmetastorev1"
+)
+
+func TestQueryFrontend_AnalyzeQuery(t *testing.T) {
+	metaClient := mockmetastorev1.NewMockMetastoreServiceClient(t)
+	limits := mockfrontend.NewMockLimits(t)
+	f := NewQueryFrontend(log.NewNopLogger(), limits, metaClient, nil)
+
+	limits.On("QueryAnalysisEnabled", "tenant").Return(true)
+	limits.On("QueryAnalysisSeriesEnabled", "tenant").Return(false)
+	limits.On("MaxQueryLookback", mock.Anything).Return(24 * time.Hour)
+	limits.On("MaxQueryLength", mock.Anything).Return(2 * time.Hour)
+
+	// Profiles: 100 bytes, index: 30 bytes, symbols: 20 bytes.
+	dataset := func() *metastorev1.Dataset {
+		return &metastorev1.Dataset{TableOfContents: []uint64{0, 100, 130}, Size: 150}
+	}
+	const selector = `{service_name="foo",__profile_type__="process_cpu:cpu:nanoseconds:cpu:nanoseconds"}`
+	metaClient.On("QueryMetadata", mock.Anything, mock.MatchedBy(func(r *metastorev1.QueryMetadataRequest) bool {
+		return r.Query == selector
+	})).Return(&metastorev1.QueryMetadataResponse{
+		Blocks: []*metastorev1.BlockMeta{
+			{Id: "a", FormatVersion: 1, CompactionLevel: 1, Shard: 1, Datasets: []*metastorev1.Dataset{dataset()}},
+			{Id: "b", FormatVersion: 1, Shard: 1, Datasets: []*metastorev1.Dataset{dataset()}},
+			{Id: "c", FormatVersion: 1, Shard: 2, Datasets: []*metastorev1.Dataset{dataset(), dataset()}},
+		},
+	}, nil)
+
+	ctx := tenant.InjectTenantID(context.Background(), "tenant")
+	resp, err := f.AnalyzeQuery(ctx, connect.NewRequest(&querierv1.AnalyzeQueryRequest{
+		Start: time.Now().Add(-time.Hour).UnixMilli(),
+		End:   time.Now().UnixMilli(),
+		Query: `process_cpu:cpu:nanoseconds:cpu:nanoseconds{service_name="foo"}`,
+	}))
+	require.NoError(t, err)
+
+	expected := &querierv1.AnalyzeQueryResponse{
+		QueryScopes: []*querierv1.QueryScope{
+			{
+				ComponentType:  "Segments",
+				ComponentCount: 1,
+				BlockCount:     2,
+				IndexBytes:     90,
+				ProfileBytes:   300,
+				SymbolBytes:    60,
+			},
+			{
+				ComponentType:  "Compacted blocks (level 1)",
+				ComponentCount: 1,
+				BlockCount:     1,
+				IndexBytes:     30,
+				ProfileBytes:   100,
+				SymbolBytes:    20,
+			},
+		},
+		QueryImpact: &querierv1.QueryImpact{
+			TotalBytesInTimeRange: 600,
+		},
+	}
+	assert.Equal(t, expected, resp.Msg)
+}
@@ -2,7 +2,8 @@ package query_frontend
 
 import (
 	"context"
-	"math/rand"
+	"slices"
+	"time"
 
 	"connectrpc.com/connect"
 	"github.com/go-kit/log"
@@ -15,6 +16,7 @@ import (
 	querybackendclient "github.com/grafana/pyroscope/pkg/experiment/query_backend/client"
 	queryplan "github.com/grafana/pyroscope/pkg/experiment/query_backend/query_plan"
 	"github.com/grafana/pyroscope/pkg/frontend"
+	"github.com/grafana/pyroscope/pkg/validation"
 )
 
 var _ querierv1connect.QuerierServiceClient = (*QueryFrontend)(nil)
@@ -40,8 +42,6 @@ func NewQueryFrontend(
 	}
 }
 
-var xrand = rand.New(rand.NewSource(4349676827832284783))
-
 func (q *QueryFrontend) Query(
 	ctx context.Context,
 	req *queryv1.QueryRequest,
@@ -54,40 +54,90 @@ func (q *QueryFrontend) Query(
 	if err != nil {
 		return nil, connect.NewError(connect.CodeInvalidArgument, err)
 	}
-	md, err := q.metastore.QueryMetadata(ctx, &metastorev1.QueryMetadataRequest{
-		TenantId:  tenants,
-		StartTime: req.StartTime,
-		EndTime:   req.EndTime,
-		Query:     req.LabelSelector,
-	})
+	planningStart := time.Now()
+	p, err := q.queryPlan(ctx, tenants, req.StartTime, req.EndTime, req.LabelSelector)
 	if err != nil {
 		return nil, err
 	}
-	if len(md.Blocks) == 0 {
+	if p == nil {
 		return new(queryv1.QueryResponse), nil
 	}
-
-	// TODO(kolesnikovae): Implement query planning.
-	// Randomize the order of blocks to avoid hotspots.
-	xrand.Shuffle(len(md.Blocks), func(i, j int) {
-		md.Blocks[i], md.Blocks[j] = md.Blocks[j], md.Blocks[i]
-	})
-	p := queryplan.Build(md.Blocks, 4, 20)
+	if err = checkQueryImpact(q.limits, tenants, p, req.Query); err != nil {
+		return nil, err
+	}
+	var diagnostics *queryv1.Diagnostics
+	if req.CollectDiagnostics {
+		diagnostics = &queryv1.Diagnostics{QueryPlan: p.Stats()}
+		diagnostics.QueryPlan.PlanningDurationNs = time.Since(planningStart).Nanoseconds()
+	}
 
 	resp, err := q.querybackend.Invoke(ctx, &queryv1.InvokeRequest{
 		Tenant:        tenants,
 		StartTime:     req.StartTime,
 		EndTime:       req.EndTime,
 		LabelSelector: req.LabelSelector,
-		Options:       &queryv1.InvokeOptions{},
-		QueryPlan:     p.Proto(),
-		Query:         req.Query,
+		Options: &queryv1.InvokeOptions{
+			CollectDiagnostics: req.CollectDiagnostics,
+		},
+		QueryPlan: p.Proto(),
+		Query:     req.Query,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if diagnostics != nil {
+		diagnostics.Execution = resp.Diagnostics.GetExecution()
+	}
+	return &queryv1.QueryResponse{
+		Reports:     resp.Reports,
+		Diagnostics: diagnostics,
+	}, nil
+}
+
+// queryPlan builds the query plan for the blocks matching the query.
+// If no blocks match the query, the returned plan is nil.
+func (q *QueryFrontend) queryPlan(
+	ctx context.Context,
+	tenants []string,
+	startTime, endTime int64,
+	labelSelector string,
+) (*queryplan.QueryPlan, error) {
+	md, err := q.metastore.QueryMetadata(ctx, &metastorev1.QueryMetadataRequest{
+		TenantId:  tenants,
+		StartTime: startTime,
+		EndTime:   endTime,
+		Query:     labelSelector,
 	})
 	if err != nil {
 		return nil, err
 	}
-	// TODO(kolesnikovae): Diagnostics.
-	return &queryv1.QueryResponse{Reports: resp.Reports}, nil
+	if len(md.Blocks) == 0 {
+		return nil, nil
+	}
+	return queryplan.BuildWithOptions(md.Blocks, queryplan.DefaultOptions), nil
+}
+
+// checkQueryImpact rejects queries reading profiles if the datasets of the
+// query plan exceed the limit of bytes scanned. Unlike ingesters and
+// store-gateways, the frontend checks the limit against the whole query.
+func checkQueryImpact(limits frontend.Limits, tenants []string, p *queryplan.QueryPlan, queries []*queryv1.Query) error {
+	readsProfiles := slices.ContainsFunc(queries, func(x *queryv1.Query) bool {
+		switch x.QueryType {
+		case queryv1.QueryType_QUERY_TIME_SERIES,
+			queryv1.QueryType_QUERY_TREE,
+			queryv1.QueryType_QUERY_PPROF:
+			return true
+		}
+		return false
+	})
+	if !readsProfiles {
+		return nil
+	}
+	impact := validation.QueryImpact{Bytes: p.Stats().DatasetBytes}
+	if err := validation.ValidateQueryImpact(limits, tenants, impact); err != nil {
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	return nil
 }
 
 // querySingle is a helper method that expects a single report
@@ -0,0 +1,38 @@
+package query_frontend
+
+import (
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	queryv1 "github.com/grafana/pyroscope/api/gen/proto/go/query/v1"
+	queryplan "github.com/grafana/pyroscope/pkg/experiment/query_backend/query_plan"
+	"github.com/grafana/pyroscope/pkg/test/mocks/mockfrontend"
+)
+
+func Test_checkQueryImpact(t *testing.T) {
+	limits := mockfrontend.NewMockLimits(t)
+	limits.On("MaxQueryBytesScanned", "tenant").Return(100)
+	limits.On("MaxQuerySeries", "tenant").Return(0)
+	limits.On("MaxQueryProfiles", "tenant").Return(0)
+
+	p := queryplan.BuildWithOptions([]*metastorev1.BlockMeta{
+		{Id: "a", Datasets: []*metastorev1.Dataset{{Size: 80}}},
+		{Id: "b", Datasets: []*metastorev1.Dataset{{Size: 40}}},
+	}, queryplan.DefaultOptions)
+	tenants := []string{"tenant"}
+
+	labelNames := []*queryv1.Query{{QueryType: queryv1.QueryType_QUERY_LABEL_NAMES}}
+	require.NoError(t, checkQueryImpact(limits, tenants, p, labelNames))
+
+	tree := []*queryv1.Query{
+		{QueryType: queryv1.QueryType_QUERY_LABEL_NAMES},
+		{QueryType: queryv1.QueryType_QUERY_TREE},
+	}
+	err := checkQueryImpact(limits, tenants, p, tree)
+	require.Error(t, err)
+	assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+}
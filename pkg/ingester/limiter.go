@@ -27,6 +27,7 @@ type Limits interface {
 	MaxGlobalSeriesPerTenant(tenantID string) int
 	IngestionTenantShardSize(tenantID string) int
 	DistributorUsageGroups(tenantID string) *validation.UsageGroupConfig
+	validation.QueryImpactLimits
 }
 
 type Limiter interface {
@@ -32,6 +32,18 @@ func (f *fakeLimits) IngestionTenantShardSize(userID string) int {
 	return f.ingestionTenantShardSize
 }
 
+func (f *fakeLimits) MaxQueryBytesScanned(userID string) int {
+	return 0
+}
+
+func (f *fakeLimits) MaxQuerySeries(userID string) int {
+	return 0
+}
+
+func (f *fakeLimits) MaxQueryProfiles(userID string) int {
+	return 0
+}
+
 func (f *fakeLimits) DistributorUsageGroups(userID string) *validation.UsageGroupConfig {
 	return &validation.UsageGroupConfig{}
 }
@@ -31,10 +31,11 @@ type PushService interface {
 	PushParsed(ctx context.Context, req *model.PushRequest) (*connect.Response[pushv1.PushResponse], error)
 }
 
-func NewPyroscopeIngestHandler(svc PushService, logger log.Logger) http.Handler {
+func NewPyroscopeIngestHandler(svc PushService, limits Limits, logger log.Logger) http.Handler {
 	return NewIngestHandler(
 		logger,
 		&pyroscopeIngesterAdapter{svc: svc, log: logger},
+		limits,
 	)
 }
 
@@ -33,18 +33,28 @@ import (
 type ingestHandler struct {
 	log      log.Logger
 	ingester ingestion.Ingester
+	limits   Limits
 }
 
-func NewIngestHandler(l log.Logger, p ingestion.Ingester) http.Handler {
+// Limits are the tenant limits applied by the ingest handler.
+type Limits interface {
+	MaxProfileSizeBytes(tenantID string) int
+}
+
+// NewIngestHandler creates a handler of the ingest requests. If limits
+// is not nil, the size of the decompressed text profiles is limited to
+// the maximum profile size of the tenant.
+func NewIngestHandler(l log.Logger, p ingestion.Ingester, limits Limits) http.Handler {
 	return ingestHandler{
 		log:      level.Error(l),
 		ingester: p,
+		limits:   limits,
 	}
 }
 
 func (h ingestHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	tenantID, _ := tenant.ExtractTenantIDFromContext(r.Context())
-	input, err := h.ingestInputFromRequest(r)
+	input, err := h.ingestInputFromRequest(r, tenantID)
 	if err != nil {
 		_ = h.log.Log("msg", "bad request", "err", err, "orgID", tenantID)
 		httputil.ErrorWithStatus(w, err, http.StatusBadRequest)
@@ -63,7 +73,7 @@ func (h ingestHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func (h ingestHandler) ingestInputFromRequest(r *http.Request) (*ingestion.IngestInput, error) {
+func (h ingestHandler) ingestInputFromRequest(r *http.Request, tenantID string) (*ingestion.IngestInput, error) {
 	var (
 		q     = r.URL.Query()
 		input ingestion.IngestInput
@@ -139,6 +149,8 @@ func (h ingestHandler) ingestInputFromRequest(r *http.Request) (*ingestion.Inges
 		input.Format = ingestion.FormatTree
 	case format == "lines":
 		input.Format = ingestion.FormatLines
+	case format == "perf_script":
+		input.Format = ingestion.FormatPerfScript
 
 	case format == "jfr":
 		input.Format = ingestion.FormatJFR
@@ -167,10 +179,14 @@ func (h ingestHandler) ingestInputFromRequest(r *http.Request) (*ingestion.Inges
 	}
 
 	if input.Profile == nil {
-		input.Profile = &profile.RawProfile{
+		rawProfile := &profile.RawProfile{
 			Format:  input.Format,
 			RawData: b,
 		}
+		if h.limits != nil {
+			rawProfile.MaxDecompressedSize = h.limits.MaxProfileSizeBytes(tenantID)
+		}
+		input.Profile = rawProfile
 	}
 
 	return &input, nil
@@ -2,13 +2,17 @@ package pyroscope
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
+	"fmt"
 	"mime/multipart"
 	"net/http/httptest"
 	"os"
 	"slices"
 	"sort"
+	"strconv"
+	"strings"
 	"testing"
 
 	"connectrpc.com/connect"
@@ -151,7 +155,7 @@ func TestCorruptedJFR422(t *testing.T) {
 	jfr[0] = 0 // corrupt jfr
 
 	svc := &MockPushService{Keep: true, T: t}
-	h := NewPyroscopeIngestHandler(svc, l)
+	h := NewPyroscopeIngestHandler(svc, nil, l)
 
 	res := httptest.NewRecorder()
 	body, ct := createJFRRequestBody(t, jfr, nil)
@@ -194,7 +198,7 @@ func BenchmarkIngestJFR(b *testing.B) {
 		"cortex-dev-01__kafka-0__cpu_lock_alloc__3.jfr.gz",
 	}
 	l := log.NewSyncLogger(log.NewLogfmtLogger(os.Stderr))
-	h := NewPyroscopeIngestHandler(&MockPushService{}, l)
+	h := NewPyroscopeIngestHandler(&MockPushService{}, nil, l)
 
 	for _, jfr := range jfrs {
 		b.Run(jfr, func(b *testing.B) {
@@ -338,7 +342,7 @@ func TestIngestPPROFFixtures(t *testing.T) {
 			bs, ct := createPProfRequest(t, profile, prevProfile, sampleTypeConfig)
 
 			svc := &MockPushService{Keep: true, T: t}
-			h := NewPyroscopeIngestHandler(svc, log.NewSyncLogger(log.NewLogfmtLogger(os.Stderr)))
+			h := NewPyroscopeIngestHandler(svc, nil, log.NewSyncLogger(log.NewLogfmtLogger(os.Stderr)))
 
 			res := httptest.NewRecorder()
 			spyName := "foo239"
@@ -371,6 +375,125 @@ func TestIngestPPROFFixtures(t *testing.T) {
 	}
 }
 
+func TestIngestTextFormats(t *testing.T) {
+	const perfScript = "" +
+		"foo 1 [000] 1.000000: 1 cycles:\n" +
+		"\t1 bar (/bin/foo)\n" +
+		"\t2 main (/bin/foo)\n" +
+		"\n" +
+		"foo 1 [000] 1.000001: 1 cycles:\n" +
+		"\t1 baz (/bin/foo)\n" +
+		"\t2 main (/bin/foo)\n" +
+		"\n"
+
+	testdata := []struct {
+		name     string
+		format   string
+		body     string
+		gzip     bool
+		expected []string
+	}{
+		{
+			name:     "groups",
+			format:   "groups",
+			body:     "main;bar 2\nmain;baz 1\n",
+			expected: []string{"main;bar 2", "main;baz 1"},
+		},
+		{
+			name:     "groups gzip",
+			format:   "groups",
+			body:     "main;bar 2\nmain;baz 1\n",
+			gzip:     true,
+			expected: []string{"main;bar 2", "main;baz 1"},
+		},
+		{
+			name:     "lines gzip",
+			format:   "lines",
+			body:     "main;bar\nmain;bar\nmain;baz\n",
+			gzip:     true,
+			expected: []string{"main;bar 2", "main;baz 1"},
+		},
+		{
+			name:     "perf_script",
+			format:   "perf_script",
+			body:     perfScript,
+			expected: []string{"foo;main;bar 1", "foo;main;baz 1"},
+		},
+		{
+			name:     "perf_script gzip",
+			format:   "perf_script",
+			body:     perfScript,
+			gzip:     true,
+			expected: []string{"foo;main;bar 1", "foo;main;baz 1"},
+		},
+	}
+
+	for _, td := range testdata {
+		t.Run(td.name, func(t *testing.T) {
+			svc := &MockPushService{Keep: true, T: t}
+			h := NewPyroscopeIngestHandler(svc, nil, log.NewNopLogger())
+
+			body := []byte(td.body)
+			if td.gzip {
+				var buf bytes.Buffer
+				w := gzip.NewWriter(&buf)
+				_, err := w.Write(body)
+				require.NoError(t, err)
+				require.NoError(t, w.Close())
+				body = buf.Bytes()
+			}
+
+			res := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/ingest?name=app.cpu&sampleRate=100&format="+td.format, bytes.NewReader(body))
+			h.ServeHTTP(res, req)
+			require.Equal(t, 200, res.Code)
+
+			require.Len(t, svc.reqPprof, 1)
+			ls := phlaremodel.Labels(svc.reqPprof[0].Labels)
+			assert.Equal(t, "process_cpu", ls.Get(labels.MetricName))
+			assert.Equal(t, "app", ls.Get("service_name"))
+
+			p := svc.reqPprof[0].Profile
+			actual := bench.StackCollapseProto(p, 0, 1.0)
+			slices.Sort(actual)
+			assert.Equal(t, td.expected, scaleCollapsed(t, actual, p.Period))
+		})
+	}
+}
+
+type maxProfileSizeLimits int
+
+func (l maxProfileSizeLimits) MaxProfileSizeBytes(string) int { return int(l) }
+
+func TestIngestTextFormatsDecompressedSizeLimit(t *testing.T) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write(bytes.Repeat([]byte("main;bar 2\n"), 1000))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	svc := &MockPushService{Keep: true, T: t}
+	h := NewPyroscopeIngestHandler(svc, maxProfileSizeLimits(1024), log.NewNopLogger())
+	res := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/ingest?name=app.cpu&format=groups", bytes.NewReader(buf.Bytes()))
+	h.ServeHTTP(res, req)
+	assert.Equal(t, 422, res.Code)
+	assert.Contains(t, res.Body.String(), "exceeds the limit")
+	assert.Empty(t, svc.reqPprof)
+}
+
+// scaleCollapsed converts collapsed stack values back into sample counts.
+func scaleCollapsed(t *testing.T, collapsed []string, period int64) []string {
+	res := make([]string, 0, len(collapsed))
+	for _, c := range collapsed {
+		i := strings.LastIndexByte(c, ' ')
+		v, err := strconv.ParseInt(c[i+1:], 10, 64)
+		require.NoError(t, err)
+		res = append(res, fmt.Sprintf("%s %d", c[:i], v/period))
+	}
+	return res
+}
+
 func comparePPROF(t *testing.T, actual *profilev1.Profile, profile2 []byte) {
 	expected, err := pprof.RawFromBytes(profile2)
 	require.NoError(t, err)
@@ -7,6 +7,7 @@ import (
 
 	ingestv1 "github.com/grafana/pyroscope/api/gen/proto/go/ingester/v1"
 	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+	"github.com/grafana/pyroscope/pkg/phlaredb"
 )
 
 // LabelValues returns the possible label values for a given label name.
@@ -46,25 +47,25 @@ func (i *Ingester) BlockMetadata(ctx context.Context, req *connect.Request[inges
 
 func (i *Ingester) MergeProfilesStacktraces(ctx context.Context, stream *connect.BidiStream[ingestv1.MergeProfilesStacktracesRequest, ingestv1.MergeProfilesStacktracesResponse]) error {
 	return i.forInstance(ctx, func(instance *instance) error {
-		return instance.MergeProfilesStacktraces(ctx, stream)
+		return instance.MergeProfilesStacktraces(i.withQueryLimiter(ctx, instance.tenantID), stream)
 	})
 }
 
 func (i *Ingester) MergeProfilesLabels(ctx context.Context, stream *connect.BidiStream[ingestv1.MergeProfilesLabelsRequest, ingestv1.MergeProfilesLabelsResponse]) error {
 	return i.forInstance(ctx, func(instance *instance) error {
-		return instance.MergeProfilesLabels(ctx, stream)
+		return instance.MergeProfilesLabels(i.withQueryLimiter(ctx, instance.tenantID), stream)
 	})
 }
 
 func (i *Ingester) MergeProfilesPprof(ctx context.Context, stream *connect.BidiStream[ingestv1.MergeProfilesPprofRequest, ingestv1.MergeProfilesPprofResponse]) error {
 	return i.forInstance(ctx, func(instance *instance) error {
-		return instance.MergeProfilesPprof(ctx, stream)
+		return instance.MergeProfilesPprof(i.withQueryLimiter(ctx, instance.tenantID), stream)
 	})
 }
 
 func (i *Ingester) MergeSpanProfile(ctx context.Context, stream *connect.BidiStream[ingestv1.MergeSpanProfileRequest, ingestv1.MergeSpanProfileResponse]) error {
 	return i.forInstance(ctx, func(instance *instance) error {
-		return instance.MergeSpanProfile(ctx, stream)
+		return instance.MergeSpanProfile(i.withQueryLimiter(ctx, instance.tenantID), stream)
 	})
 }
 
@@ -79,3 +80,9 @@ func (i *Ingester) GetBlockStats(ctx context.Context, req *connect.Request[inges
 		return instance.GetBlockStats(ctx, req)
 	})
 }
+
+// withQueryLimiter attaches the limiter of the resources
+// the query is allowed to consume to the context.
+func (i *Ingester) withQueryLimiter(ctx context.Context, tenantID string) context.Context {
+	return phlaredb.ContextWithQueryLimiter(ctx, phlaredb.NewQueryLimiter(i.limits, tenantID))
+}
@@ -28,6 +28,7 @@ const (
 	LabelNamePeriodType         = "__period_type__"
 	LabelNamePeriodUnit         = "__period_unit__"
 	LabelNameSessionID          = "__session_id__"
+	LabelNameTenantID           = "__tenant_id__"
 	LabelNameType               = "__type__"
 	LabelNameUnit               = "__unit__"
 
@@ -35,6 +36,7 @@ const (
 	LabelNameServiceName       = "service_name"
 	LabelNameServiceRepository = "service_repository"
 	LabelNameServiceRootPath   = "service_root_path"
+	LabelNameServiceVersion    = "service_version"
 
 	LabelNameOrder     = "__order__"
 	LabelOrderEnforced = "enforced"
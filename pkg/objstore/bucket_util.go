@@ -6,7 +6,9 @@
 package objstore
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"strings"
 
 	"github.com/go-kit/log"
@@ -36,3 +38,30 @@ func DeletePrefix(ctx context.Context, bkt objstore.Bucket, prefix string, logge
 
 	return result, err
 }
+
+// ReadJSON decodes the JSON object with the given name into v. It returns
+// false if the object does not exist, leaving v unchanged.
+func ReadJSON(ctx context.Context, bkt objstore.BucketReader, name string, v any) (bool, error) {
+	reader, err := bkt.Get(ctx, name)
+	if err != nil {
+		if bkt.IsObjNotFoundErr(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if err = json.NewDecoder(reader).Decode(v); err != nil {
+		_ = reader.Close()
+		return false, err
+	}
+	return true, reader.Close()
+}
+
+// UploadJSON encodes v as JSON and uploads it as the object with the
+// given name.
+func UploadJSON(ctx context.Context, bkt objstore.Bucket, name string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return bkt.Upload(ctx, name, bytes.NewReader(data))
+}
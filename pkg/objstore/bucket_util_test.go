@@ -31,3 +31,24 @@ func TestDeletePrefix(t *testing.T) {
 	assert.Equal(t, 4, del)
 	assert.Equal(t, 2, len(mem.Objects()))
 }
+
+func TestReadUploadJSON(t *testing.T) {
+	ctx := context.Background()
+	mem := objstore.NewInMemBucket()
+
+	var v map[string]int
+	found, err := ReadJSON(ctx, mem, "obj.json", &v)
+	require.NoError(t, err)
+	assert.False(t, found)
+	assert.Nil(t, v)
+
+	require.NoError(t, UploadJSON(ctx, mem, "obj.json", map[string]int{"a": 1}))
+	found, err = ReadJSON(ctx, mem, "obj.json", &v)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, map[string]int{"a": 1}, v)
+
+	require.NoError(t, mem.Upload(ctx, "invalid.json", strings.NewReader("{")))
+	_, err = ReadJSON(ctx, mem, "invalid.json", &v)
+	require.Error(t, err)
+}
@@ -0,0 +1,498 @@
+package objstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/cache"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/thanos-io/objstore"
+)
+
+// ObjectKind identifies the kind of data stored in an object (or in a
+// section of an object), which determines how it is cached.
+type ObjectKind int
+
+const (
+	ObjectKindUnknown ObjectKind = iota
+	ObjectKindTSDBIndex
+	ObjectKindSymbols
+	ObjectKindProfiles
+)
+
+func (k ObjectKind) String() string {
+	switch k {
+	case ObjectKindTSDBIndex:
+		return "tsdb-index"
+	case ObjectKindSymbols:
+		return "symbols"
+	case ObjectKindProfiles:
+		return "profiles"
+	default:
+		return "unknown"
+	}
+}
+
+type objectKindKey struct{}
+
+// ContextWithObjectKind returns a context carrying the kind of the object
+// being read. It is used when the kind can not be derived from the object
+// name, e.g., when a single object stores multiple sections.
+func ContextWithObjectKind(ctx context.Context, kind ObjectKind) context.Context {
+	return context.WithValue(ctx, objectKindKey{}, kind)
+}
+
+// ObjectKindFromContext returns the object kind set with ContextWithObjectKind.
+func ObjectKindFromContext(ctx context.Context) (ObjectKind, bool) {
+	kind, ok := ctx.Value(objectKindKey{}).(ObjectKind)
+	return kind, ok
+}
+
+type objectKindBucketReader struct {
+	BucketReader
+	kind ObjectKind
+}
+
+// NewBucketReaderWithObjectKind returns a bucket reader that marks all the
+// reads with the given object kind. See ContextWithObjectKind.
+func NewBucketReaderWithObjectKind(r BucketReader, kind ObjectKind) BucketReader {
+	return &objectKindBucketReader{BucketReader: r, kind: kind}
+}
+
+func (r *objectKindBucketReader) Get(ctx context.Context, name string) (io.ReadCloser, error) {
+	return r.BucketReader.Get(ContextWithObjectKind(ctx, r.kind), name)
+}
+
+func (r *objectKindBucketReader) GetRange(ctx context.Context, name string, off, length int64) (io.ReadCloser, error) {
+	return r.BucketReader.GetRange(ContextWithObjectKind(ctx, r.kind), name, off, length)
+}
+
+func (r *objectKindBucketReader) Exists(ctx context.Context, name string) (bool, error) {
+	return r.BucketReader.Exists(ContextWithObjectKind(ctx, r.kind), name)
+}
+
+func (r *objectKindBucketReader) Attributes(ctx context.Context, name string) (objstore.ObjectAttributes, error) {
+	return r.BucketReader.Attributes(ContextWithObjectKind(ctx, r.kind), name)
+}
+
+func (r *objectKindBucketReader) ReaderAt(ctx context.Context, name string) (ReaderAtCloser, error) {
+	return r.BucketReader.ReaderAt(ContextWithObjectKind(ctx, r.kind), name)
+}
+
+// ObjectKindOf returns the object kind based on the block file name.
+func ObjectKindOf(name string) ObjectKind {
+	switch {
+	case strings.HasSuffix(name, "index.tsdb"):
+		return ObjectKindTSDBIndex
+	case strings.HasSuffix(name, ".symdb"):
+		return ObjectKindSymbols
+	case strings.HasSuffix(name, ".parquet"):
+		// Older block formats store symbols in parquet tables.
+		if strings.Contains(name, "/symbols/") {
+			return ObjectKindSymbols
+		}
+		switch name[strings.LastIndexByte(name, '/')+1:] {
+		case "locations.parquet", "functions.parquet", "mappings.parquet", "strings.parquet":
+			return ObjectKindSymbols
+		}
+		return ObjectKindProfiles
+	default:
+		return ObjectKindUnknown
+	}
+}
+
+const (
+	CacheBackendNone      = ""
+	CacheBackendInMemory  = "inmemory"
+	CacheBackendMemcached = "memcached"
+)
+
+var supportedCacheBackends = []string{CacheBackendNone, CacheBackendInMemory, CacheBackendMemcached}
+
+type CachingBucketConfig struct {
+	Backend   string                      `yaml:"backend"`
+	InMemory  InMemoryCacheConfig         `yaml:"inmemory"`
+	Memcached cache.MemcachedClientConfig `yaml:"memcached"`
+
+	TSDBIndex ObjectCacheConfig `yaml:"tsdb_index"`
+	Symbols   ObjectCacheConfig `yaml:"symbols"`
+	Profiles  ObjectCacheConfig `yaml:"profiles"`
+}
+
+func (cfg *CachingBucketConfig) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	f.StringVar(&cfg.Backend, prefix+"backend", CacheBackendNone, fmt.Sprintf("Backend of the cache used for object storage reads. Supported values: %s. Empty value disables the cache.", strings.Join(supportedCacheBackends[1:], ", ")))
+	cfg.InMemory.RegisterFlagsWithPrefix(prefix+"inmemory.", f)
+	cfg.Memcached.RegisterFlagsWithPrefix(prefix+"memcached.", f)
+	cfg.TSDBIndex.RegisterFlagsWithPrefix(prefix+"tsdb-index.", "TSDB index", 16<<10, f)
+	cfg.Symbols.RegisterFlagsWithPrefix(prefix+"symbols.", "symbols", 16<<10, f)
+	cfg.Profiles.RegisterFlagsWithPrefix(prefix+"profiles.", "profiles parquet", 64<<10, f)
+}
+
+func (cfg *CachingBucketConfig) Validate() error {
+	switch cfg.Backend {
+	case CacheBackendNone:
+		return nil
+	case CacheBackendInMemory:
+		if err := cfg.InMemory.Validate(); err != nil {
+			return errors.Wrap(err, "in-memory cache")
+		}
+	case CacheBackendMemcached:
+		if err := cfg.Memcached.Validate(); err != nil {
+			return errors.Wrap(err, "memcached cache")
+		}
+	default:
+		return fmt.Errorf("unsupported cache backend: %q", cfg.Backend)
+	}
+	for kind, c := range map[ObjectKind]ObjectCacheConfig{
+		ObjectKindTSDBIndex: cfg.TSDBIndex,
+		ObjectKindSymbols:   cfg.Symbols,
+		ObjectKindProfiles:  cfg.Profiles,
+	} {
+		if err := c.Validate(); err != nil {
+			return errors.Wrap(err, kind.String())
+		}
+	}
+	return nil
+}
+
+func (cfg *CachingBucketConfig) forKind(kind ObjectKind) ObjectCacheConfig {
+	switch kind {
+	case ObjectKindTSDBIndex:
+		return cfg.TSDBIndex
+	case ObjectKindSymbols:
+		return cfg.Symbols
+	case ObjectKindProfiles:
+		return cfg.Profiles
+	default:
+		return ObjectCacheConfig{}
+	}
+}
+
+type InMemoryCacheConfig struct {
+	MaxSizeBytes int `yaml:"max_size_bytes"`
+}
+
+func (cfg *InMemoryCacheConfig) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	f.IntVar(&cfg.MaxSizeBytes, prefix+"max-size-bytes", 1<<30, "Maximum size of the in-memory cache, in bytes.")
+}
+
+func (cfg *InMemoryCacheConfig) Validate() error {
+	if cfg.MaxSizeBytes <= 0 {
+		return errors.New("max size must be greater than 0")
+	}
+	return nil
+}
+
+// ObjectCacheConfig configures caching of a particular object kind.
+type ObjectCacheConfig struct {
+	Enabled      bool          `yaml:"enabled" category:"advanced"`
+	SubrangeSize int64         `yaml:"subrange_size" category:"advanced"`
+	SubrangeTTL  time.Duration `yaml:"subrange_ttl" category:"advanced"`
+	MetadataTTL  time.Duration `yaml:"metadata_ttl" category:"advanced"`
+}
+
+func (cfg *ObjectCacheConfig) RegisterFlagsWithPrefix(prefix, name string, subrangeSize int64, f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, prefix+"enabled", true, fmt.Sprintf("Cache %s objects, if the cache backend is configured.", name))
+	f.Int64Var(&cfg.SubrangeSize, prefix+"subrange-size", subrangeSize, fmt.Sprintf("Size of the %s object subranges the range reads are aligned to and cached by.", name))
+	f.DurationVar(&cfg.SubrangeTTL, prefix+"subrange-ttl", 24*time.Hour, fmt.Sprintf("TTL of the cached %s object subranges.", name))
+	f.DurationVar(&cfg.MetadataTTL, prefix+"metadata-ttl", 10*time.Minute, fmt.Sprintf("TTL of the cached %s object attributes and existence.", name))
+}
+
+func (cfg *ObjectCacheConfig) Validate() error {
+	if cfg.Enabled && cfg.SubrangeSize <= 0 {
+		return errors.New("subrange size must be greater than 0")
+	}
+	return nil
+}
+
+// NewCache creates the cache client for the configured backend.
+// If no backend is configured, nil is returned.
+func NewCache(cfg CachingBucketConfig, logger log.Logger, reg prometheus.Registerer) (cache.Cache, error) {
+	const name = "bucket-cache"
+	reg = prometheus.WrapRegistererWithPrefix("pyroscope_bucket_cache_", reg)
+	switch cfg.Backend {
+	case CacheBackendNone:
+		return nil, nil
+	case CacheBackendInMemory:
+		return NewInMemoryCache(name, cfg.InMemory.MaxSizeBytes, reg), nil
+	case CacheBackendMemcached:
+		client, err := cache.NewMemcachedClientWithConfig(logger, name, cfg.Memcached, reg)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create memcached client")
+		}
+		return cache.NewMemcachedCache(name, logger, client, reg), nil
+	default:
+		return nil, fmt.Errorf("unsupported cache backend: %q", cfg.Backend)
+	}
+}
+
+// CachingBucket caches range reads, attributes and existence checks of
+// immutable block objects. The object kind is determined by the context
+// (see ContextWithObjectKind) or, if not specified, by the object name.
+// Objects of an unknown kind are never cached.
+type CachingBucket struct {
+	Bucket
+	cfg   CachingBucketConfig
+	cache cache.Cache
+
+	requests *prometheus.CounterVec
+	hits     *prometheus.CounterVec
+}
+
+// NewCachingBucket wraps the bucket with a caching layer. If no cache
+// backend is configured, the bucket is returned as is.
+func NewCachingBucket(bkt Bucket, cfg CachingBucketConfig, logger log.Logger, reg prometheus.Registerer) (Bucket, error) {
+	c, err := NewCache(cfg, logger, reg)
+	if err != nil || c == nil {
+		return bkt, err
+	}
+	return newCachingBucket(bkt, cfg, c, reg), nil
+}
+
+func newCachingBucket(bkt Bucket, cfg CachingBucketConfig, c cache.Cache, reg prometheus.Registerer) *CachingBucket {
+	return &CachingBucket{
+		Bucket: bkt,
+		cfg:    cfg,
+		cache:  c,
+		requests: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "pyroscope_bucket_cache_requests_total",
+			Help: "Total number of items requested from the bucket cache.",
+		}, []string{"kind", "operation"}),
+		hits: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "pyroscope_bucket_cache_hits_total",
+			Help: "Total number of items requested from the bucket cache that were a hit.",
+		}, []string{"kind", "operation"}),
+	}
+}
+
+const (
+	opGetRange   = "get_range"
+	opAttributes = "attributes"
+	opExists     = "exists"
+)
+
+func (b *CachingBucket) objectConfig(ctx context.Context, name string) (ObjectKind, ObjectCacheConfig, bool) {
+	kind, ok := ObjectKindFromContext(ctx)
+	if !ok {
+		kind = ObjectKindOf(name)
+	}
+	cfg := b.cfg.forKind(kind)
+	return kind, cfg, cfg.Enabled
+}
+
+func (b *CachingBucket) GetRange(ctx context.Context, name string, off, length int64) (io.ReadCloser, error) {
+	kind, cfg, ok := b.objectConfig(ctx, name)
+	if !ok || off < 0 || length <= 0 {
+		return b.Bucket.GetRange(ctx, name, off, length)
+	}
+	// The object size is needed to not read past the end of the
+	// object, and to tell a short last subrange from a partial one.
+	attrs, err := b.Attributes(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if off >= attrs.Size {
+		return b.Bucket.GetRange(ctx, name, off, length)
+	}
+	end := min(off+length, attrs.Size)
+
+	// The requested range is aligned to subranges,
+	// which are fetched from the cache individually.
+	size := cfg.SubrangeSize
+	first := off / size * size
+	last := (end - 1) / size * size
+	n := int((last-first)/size) + 1
+	bounds := func(i int) (int64, int64) {
+		start := first + int64(i)*size
+		return start, min(start+size, attrs.Size)
+	}
+	keys := make([]string, n)
+	for i := range keys {
+		start, stop := bounds(i)
+		keys[i] = cacheKey(opGetRange, name, strconv.FormatInt(start, 10), strconv.FormatInt(stop, 10))
+	}
+	hits := b.cache.Fetch(ctx, keys)
+	subranges := make([][]byte, n)
+	var hit int
+	for i, k := range keys {
+		// Subranges of an unexpected size are considered missing.
+		if start, stop := bounds(i); int64(len(hits[k])) == stop-start {
+			subranges[i] = hits[k]
+			hit++
+		}
+	}
+	b.requests.WithLabelValues(kind.String(), opGetRange).Add(float64(n))
+	b.hits.WithLabelValues(kind.String(), opGetRange).Add(float64(hit))
+	if stats := ReadStatsFromContext(ctx); stats != nil {
+		stats.CacheHits.Add(uint64(hit))
+		stats.CacheMisses.Add(uint64(n - hit))
+	}
+
+	// Missing subranges are fetched with as few requests as possible:
+	// adjacent missing subranges are read in a single request.
+	store := make(map[string][]byte)
+	for i := 0; i < n; {
+		if subranges[i] != nil {
+			i++
+			continue
+		}
+		j := i
+		for j < n && subranges[j] == nil {
+			j++
+		}
+		start, _ := bounds(i)
+		_, stop := bounds(j - 1)
+		data, err := b.getRange(ctx, name, start, stop-start)
+		if err != nil {
+			return nil, err
+		}
+		if int64(len(data)) != stop-start {
+			return nil, fmt.Errorf("%s: read %d bytes at offset %d, expected %d: %w", name, len(data), start, stop-start, io.ErrUnexpectedEOF)
+		}
+		for k := i; k < j; k++ {
+			lo, hi := bounds(k)
+			s := data[lo-start : hi-start : hi-start]
+			subranges[k] = s
+			store[keys[k]] = s
+		}
+		i = j
+	}
+	if len(store) > 0 {
+		b.cache.StoreAsync(store, cfg.SubrangeTTL)
+	}
+
+	buf := make([]byte, 0, end-first)
+	for _, s := range subranges {
+		buf = append(buf, s...)
+	}
+	return io.NopCloser(bytes.NewReader(buf[off-first : end-first])), nil
+}
+
+func (b *CachingBucket) getRange(ctx context.Context, name string, off, length int64) ([]byte, error) {
+	rc, err := b.Bucket.GetRange(ctx, name, off, length)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rc.Close()
+	}()
+	return io.ReadAll(io.LimitReader(rc, length))
+}
+
+func (b *CachingBucket) Attributes(ctx context.Context, name string) (objstore.ObjectAttributes, error) {
+	kind, cfg, ok := b.objectConfig(ctx, name)
+	if !ok {
+		return b.Bucket.Attributes(ctx, name)
+	}
+	key := cacheKey(opAttributes, name)
+	b.requests.WithLabelValues(kind.String(), opAttributes).Inc()
+	var attrs objstore.ObjectAttributes
+	if v, found := b.cache.Fetch(ctx, []string{key})[key]; found {
+		if err := json.Unmarshal(v, &attrs); err == nil {
+			b.hits.WithLabelValues(kind.String(), opAttributes).Inc()
+			return attrs, nil
+		}
+	}
+	attrs, err := b.Bucket.Attributes(ctx, name)
+	if err != nil {
+		return attrs, err
+	}
+	if v, err := json.Marshal(attrs); err == nil {
+		b.cache.StoreAsync(map[string][]byte{key: v}, cfg.MetadataTTL)
+	}
+	return attrs, nil
+}
+
+// Exists caches only the objects found: an object missing may be uploaded
+// by another instance, which only invalidates its own cache.
+func (b *CachingBucket) Exists(ctx context.Context, name string) (bool, error) {
+	kind, cfg, ok := b.objectConfig(ctx, name)
+	if !ok {
+		return b.Bucket.Exists(ctx, name)
+	}
+	key := cacheKey(opExists, name)
+	b.requests.WithLabelValues(kind.String(), opExists).Inc()
+	if v, found := b.cache.Fetch(ctx, []string{key})[key]; found && len(v) == 1 && v[0] == 1 {
+		b.hits.WithLabelValues(kind.String(), opExists).Inc()
+		return true, nil
+	}
+	exists, err := b.Bucket.Exists(ctx, name)
+	if err != nil || !exists {
+		return false, err
+	}
+	b.cache.StoreAsync(map[string][]byte{key: {1}}, cfg.MetadataTTL)
+	return true, nil
+}
+
+// ReaderAt returns a reader that reads the object through the cache.
+func (b *CachingBucket) ReaderAt(ctx context.Context, name string) (ReaderAtCloser, error) {
+	if _, _, ok := b.objectConfig(ctx, name); !ok {
+		return b.Bucket.ReaderAt(ctx, name)
+	}
+	return &ReaderAt{GetRangeReader: b, name: name, ctx: ctx}, nil
+}
+
+func (b *CachingBucket) Upload(ctx context.Context, name string, r io.Reader) error {
+	b.invalidate(ctx, name)
+	return b.Bucket.Upload(ctx, name, r)
+}
+
+func (b *CachingBucket) Delete(ctx context.Context, name string) error {
+	b.invalidate(ctx, name)
+	return b.Bucket.Delete(ctx, name)
+}
+
+// invalidate removes cached object metadata. Cached subranges
+// are not removed: the objects are expected to be immutable.
+func (b *CachingBucket) invalidate(ctx context.Context, name string) {
+	if _, _, ok := b.objectConfig(ctx, name); ok {
+		_ = b.cache.Delete(ctx, cacheKey(opAttributes, name))
+		_ = b.cache.Delete(ctx, cacheKey(opExists, name))
+	}
+}
+
+// ReaderWithExpectedErrs implements objstore.InstrumentedBucket.
+func (b *CachingBucket) ReaderWithExpectedErrs(fn IsOpFailureExpectedFunc) BucketReader {
+	return b.WithExpectedErrs(fn)
+}
+
+// WithExpectedErrs implements objstore.InstrumentedBucket.
+func (b *CachingBucket) WithExpectedErrs(fn IsOpFailureExpectedFunc) Bucket {
+	if ib, ok := b.Bucket.(InstrumentedBucket); ok {
+		c := *b
+		c.Bucket = ib.WithExpectedErrs(fn)
+		return &c
+	}
+	return b
+}
+
+// Memcached limits the key length to 250 bytes.
+const maxCacheKeyLength = 250
+
+func cacheKey(op, name string, parts ...string) string {
+	var k strings.Builder
+	k.WriteString(op)
+	k.WriteByte(':')
+	k.WriteString(name)
+	for _, p := range parts {
+		k.WriteByte(':')
+		k.WriteString(p)
+	}
+	if k.Len() <= maxCacheKeyLength && !strings.ContainsAny(name, " \t\r\n") {
+		return k.String()
+	}
+	h := sha256.Sum256([]byte(k.String()))
+	return op + ":" + hex.EncodeToString(h[:])
+}
@@ -0,0 +1,272 @@
+package objstore
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+)
+
+type countingBucket struct {
+	objstore.Bucket
+	getRange   int
+	attributes int
+	exists     int
+	// maxRangeEnd is the end of the furthest range requested.
+	maxRangeEnd int64
+}
+
+func (b *countingBucket) GetRange(ctx context.Context, name string, off, length int64) (io.ReadCloser, error) {
+	b.getRange++
+	b.maxRangeEnd = max(b.maxRangeEnd, off+length)
+	return b.Bucket.GetRange(ctx, name, off, length)
+}
+
+func (b *countingBucket) Attributes(ctx context.Context, name string) (objstore.ObjectAttributes, error) {
+	b.attributes++
+	return b.Bucket.Attributes(ctx, name)
+}
+
+func (b *countingBucket) Exists(ctx context.Context, name string) (bool, error) {
+	b.exists++
+	return b.Bucket.Exists(ctx, name)
+}
+
+func newTestCachingBucket(t *testing.T) (*CachingBucket, *countingBucket) {
+	t.Helper()
+	cfg := CachingBucketConfig{
+		Backend:   CacheBackendInMemory,
+		InMemory:  InMemoryCacheConfig{MaxSizeBytes: 1 << 20},
+		TSDBIndex: ObjectCacheConfig{Enabled: true, SubrangeSize: 10, SubrangeTTL: time.Hour, MetadataTTL: time.Hour},
+		Symbols:   ObjectCacheConfig{Enabled: true, SubrangeSize: 10, SubrangeTTL: time.Hour, MetadataTTL: time.Hour},
+		Profiles:  ObjectCacheConfig{Enabled: false},
+	}
+	require.NoError(t, cfg.Validate())
+	reg := prometheus.NewRegistry()
+	counting := &countingBucket{Bucket: objstore.NewInMemBucket()}
+	c, err := NewCache(cfg, nil, reg)
+	require.NoError(t, err)
+	return newCachingBucket(NewBucket(counting), cfg, c, reg), counting
+}
+
+func readRange(ctx context.Context, t *testing.T, b Bucket, name string, off, length int64) []byte {
+	t.Helper()
+	rc, err := b.GetRange(ctx, name, off, length)
+	require.NoError(t, err)
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	return data
+}
+
+func TestCachingBucket_GetRange(t *testing.T) {
+	ctx := context.Background()
+	b, counting := newTestCachingBucket(t)
+	data := make([]byte, 95)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	const name = "tenant/block/index.tsdb"
+	require.NoError(t, b.Upload(ctx, name, bytes.NewReader(data)))
+
+	for _, tc := range []struct {
+		off, length int64
+		requests    int
+	}{
+		{off: 0, length: 5, requests: 1},
+		{off: 0, length: 10, requests: 0},
+		{off: 3, length: 20, requests: 1},
+		{off: 15, length: 40, requests: 1},
+		{off: 0, length: 50, requests: 0},
+		{off: 85, length: 10, requests: 1},
+		{off: 90, length: 100, requests: 0},
+		{off: 60, length: 20, requests: 1},
+		{off: 0, length: 95, requests: 0},
+	} {
+		before := counting.getRange
+		actual := readRange(ctx, t, b, name, tc.off, tc.length)
+		expected := data[tc.off:min(tc.off+tc.length, int64(len(data)))]
+		assert.Equal(t, expected, actual, "off=%d length=%d", tc.off, tc.length)
+		assert.Equal(t, tc.requests, counting.getRange-before, "off=%d length=%d", tc.off, tc.length)
+	}
+
+	// Reads through ReaderAt are cached as well.
+	r, err := b.ReaderAt(ctx, name)
+	require.NoError(t, err)
+	before := counting.getRange
+	p := make([]byte, 30)
+	n, err := r.ReadAt(p, 40)
+	require.NoError(t, err)
+	assert.Equal(t, 30, n)
+	assert.Equal(t, data[40:70], p)
+	assert.Equal(t, before, counting.getRange)
+}
+
+func TestCachingBucket_GetRange_ObjectEnd(t *testing.T) {
+	ctx := context.Background()
+	for _, objectSize := range []int64{95, 100} {
+		b, counting := newTestCachingBucket(t)
+		data := make([]byte, objectSize)
+		for i := range data {
+			data[i] = byte(i)
+		}
+		const name = "tenant/block/index.tsdb"
+		require.NoError(t, b.Upload(ctx, name, bytes.NewReader(data)))
+
+		// The range ends at the object end.
+		off := objectSize - 15
+		assert.Equal(t, data[off:], readRange(ctx, t, b, name, off, 15))
+		assert.Equal(t, 1, counting.getRange)
+		assert.Equal(t, objectSize, counting.maxRangeEnd)
+
+		// The last subrange is cached in full, even if it is short.
+		assert.Equal(t, data[off:], readRange(ctx, t, b, name, off, 100))
+		assert.Equal(t, data[objectSize-1:], readRange(ctx, t, b, name, objectSize-1, 1))
+		assert.Equal(t, 1, counting.getRange)
+
+		// A range running past the object end is clamped.
+		assert.Equal(t, data[50:], readRange(ctx, t, b, name, 50, 1000))
+		assert.Equal(t, 2, counting.getRange)
+		assert.Equal(t, objectSize, counting.maxRangeEnd)
+	}
+}
+
+func TestCachingBucket_ObjectKind(t *testing.T) {
+	ctx := context.Background()
+	b, counting := newTestCachingBucket(t)
+	data := bytes.Repeat([]byte{1}, 20)
+	require.NoError(t, b.Upload(ctx, "block/profiles.parquet", bytes.NewReader(data)))
+	require.NoError(t, b.Upload(ctx, "block/block.bin", bytes.NewReader(data)))
+
+	// Profiles caching is disabled.
+	for i := 0; i < 2; i++ {
+		readRange(ctx, t, b, "block/profiles.parquet", 0, 10)
+	}
+	assert.Equal(t, 2, counting.getRange)
+
+	// Unknown objects are not cached.
+	for i := 0; i < 2; i++ {
+		readRange(ctx, t, b, "block/block.bin", 0, 10)
+	}
+	assert.Equal(t, 4, counting.getRange)
+
+	// Unless the kind is specified explicitly.
+	symbolsCtx := ContextWithObjectKind(ctx, ObjectKindSymbols)
+	for i := 0; i < 2; i++ {
+		readRange(symbolsCtx, t, b, "block/block.bin", 0, 10)
+	}
+	assert.Equal(t, 5, counting.getRange)
+
+	r := NewBucketReaderWithObjectKind(b, ObjectKindTSDBIndex)
+	for i := 0; i < 2; i++ {
+		rc, err := r.GetRange(ctx, "block/block.bin", 10, 10)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+	}
+	assert.Equal(t, 6, counting.getRange)
+}
+
+func TestCachingBucket_Metadata(t *testing.T) {
+	ctx := context.Background()
+	b, counting := newTestCachingBucket(t)
+	const name = "tenant/block/symbols.symdb"
+
+	for i := 0; i < 2; i++ {
+		exists, err := b.Exists(ctx, name)
+		require.NoError(t, err)
+		assert.False(t, exists)
+	}
+	// The objects missing are not cached.
+	assert.Equal(t, 2, counting.exists)
+
+	require.NoError(t, b.Upload(ctx, name, bytes.NewReader([]byte("symbols"))))
+	for i := 0; i < 2; i++ {
+		exists, err := b.Exists(ctx, name)
+		require.NoError(t, err)
+		assert.True(t, exists)
+		attrs, err := b.Attributes(ctx, name)
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), attrs.Size)
+	}
+	assert.Equal(t, 3, counting.exists)
+	assert.Equal(t, 1, counting.attributes)
+
+	require.NoError(t, b.Delete(ctx, name))
+	exists, err := b.Exists(ctx, name)
+	require.NoError(t, err)
+	assert.False(t, exists)
+	_, err = b.Attributes(ctx, name)
+	assert.True(t, b.IsObjNotFoundErr(err))
+}
+
+func TestObjectKindOf(t *testing.T) {
+	for name, kind := range map[string]ObjectKind{
+		"tenant/block/index.tsdb":                ObjectKindTSDBIndex,
+		"tenant/block/symbols.symdb":             ObjectKindSymbols,
+		"tenant/block/symbols/index.symdb":       ObjectKindSymbols,
+		"tenant/block/symbols/locations.parquet": ObjectKindSymbols,
+		"tenant/block/strings.parquet":           ObjectKindSymbols,
+		"tenant/block/profiles.parquet":          ObjectKindProfiles,
+		"tenant/block/meta.json":                 ObjectKindUnknown,
+		"segments/1/anon/block/block.bin":        ObjectKindUnknown,
+	} {
+		assert.Equal(t, kind, ObjectKindOf(name), name)
+	}
+}
+
+func TestInMemoryCache(t *testing.T) {
+	ctx := context.Background()
+	c := NewInMemoryCache("test", 10, nil)
+
+	c.StoreAsync(map[string][]byte{"a": []byte("aaaa"), "b": []byte("bbbb")}, time.Hour)
+	assert.Len(t, c.Fetch(ctx, []string{"a", "b"}), 2)
+
+	// "a" was accessed more recently than "b".
+	c.Fetch(ctx, []string{"a"})
+	c.StoreAsync(map[string][]byte{"c": []byte("cccc")}, time.Hour)
+	assert.Equal(t, map[string][]byte{"a": []byte("aaaa"), "c": []byte("cccc")}, c.Fetch(ctx, []string{"a", "b", "c"}))
+
+	// Items larger than the cache are not stored.
+	c.StoreAsync(map[string][]byte{"d": bytes.Repeat([]byte("d"), 11)}, time.Hour)
+	assert.Empty(t, c.Fetch(ctx, []string{"d"}))
+
+	// Expired items are not returned.
+	c.StoreAsync(map[string][]byte{"e": []byte("e")}, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	assert.Empty(t, c.Fetch(ctx, []string{"e"}))
+
+	require.NoError(t, c.Delete(ctx, "a"))
+	assert.Equal(t, map[string][]byte{"c": []byte("cccc")}, c.Fetch(ctx, []string{"a", "c"}))
+}
+
+func TestCachingBucket_ReadStats(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newTestCachingBucket(t)
+	b := NewReadStatsBucket(c)
+	data := bytes.Repeat([]byte{1}, 50)
+	const name = "tenant/block/index.tsdb"
+	require.NoError(t, b.Upload(ctx, name, bytes.NewReader(data)))
+
+	// Reads without stats in the context are not accounted.
+	readRange(ctx, t, b, name, 0, 20)
+
+	var stats ReadStats
+	ctx = ContextWithReadStats(ctx, &stats)
+	readRange(ctx, t, b, name, 10, 20)
+	assert.Equal(t, uint64(20), stats.BytesRead.Load())
+	assert.Equal(t, uint64(1), stats.CacheHits.Load())
+	assert.Equal(t, uint64(1), stats.CacheMisses.Load())
+
+	r, err := b.ReaderAt(ctx, name)
+	require.NoError(t, err)
+	_, err = r.ReadAt(make([]byte, 5), 0)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(25), stats.BytesRead.Load())
+	assert.Equal(t, uint64(2), stats.CacheHits.Load())
+}
@@ -0,0 +1,129 @@
+package objstore
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/grafana/dskit/cache"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// InMemoryCache is a cache.Cache bounded by the total size of the
+// stored values. The least recently used items are evicted first.
+type InMemoryCache struct {
+	name    string
+	maxSize int
+
+	mu    sync.Mutex
+	size  int
+	lru   *list.List
+	items map[string]*list.Element
+
+	evictions prometheus.Counter
+}
+
+type inMemoryCacheItem struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+var _ cache.Cache = (*InMemoryCache)(nil)
+
+func NewInMemoryCache(name string, maxSizeBytes int, reg prometheus.Registerer) *InMemoryCache {
+	c := &InMemoryCache{
+		name:    name,
+		maxSize: maxSizeBytes,
+		lru:     list.New(),
+		items:   make(map[string]*list.Element),
+	}
+	labels := prometheus.Labels{"name": name}
+	c.evictions = promauto.With(reg).NewCounter(prometheus.CounterOpts{
+		Name:        "inmemory_evictions_total",
+		Help:        "Total number of items evicted from the in-memory cache.",
+		ConstLabels: labels,
+	})
+	promauto.With(reg).NewGaugeFunc(prometheus.GaugeOpts{
+		Name:        "inmemory_items",
+		Help:        "Number of items currently in the in-memory cache.",
+		ConstLabels: labels,
+	}, func() float64 {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return float64(len(c.items))
+	})
+	promauto.With(reg).NewGaugeFunc(prometheus.GaugeOpts{
+		Name:        "inmemory_size_bytes",
+		Help:        "Total size of the items currently in the in-memory cache.",
+		ConstLabels: labels,
+	}, func() float64 {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return float64(c.size)
+	})
+	return c
+}
+
+func (c *InMemoryCache) StoreAsync(data map[string][]byte, ttl time.Duration) {
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, v := range data {
+		if len(v) > c.maxSize {
+			continue
+		}
+		if e, ok := c.items[k]; ok {
+			c.remove(e)
+		}
+		c.items[k] = c.lru.PushFront(&inMemoryCacheItem{key: k, value: v, expiresAt: expiresAt})
+		c.size += len(v)
+	}
+	for c.size > c.maxSize {
+		c.remove(c.lru.Back())
+		c.evictions.Inc()
+	}
+}
+
+func (c *InMemoryCache) Fetch(_ context.Context, keys []string, _ ...cache.Option) map[string][]byte {
+	now := time.Now()
+	found := make(map[string][]byte, len(keys))
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, k := range keys {
+		e, ok := c.items[k]
+		if !ok {
+			continue
+		}
+		item := e.Value.(*inMemoryCacheItem)
+		if !item.expiresAt.IsZero() && now.After(item.expiresAt) {
+			c.remove(e)
+			continue
+		}
+		c.lru.MoveToFront(e)
+		found[k] = item.value
+	}
+	return found
+}
+
+func (c *InMemoryCache) Delete(_ context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.items[key]; ok {
+		c.remove(e)
+	}
+	return nil
+}
+
+func (c *InMemoryCache) Name() string { return c.name }
+
+func (c *InMemoryCache) remove(e *list.Element) {
+	item := c.lru.Remove(e).(*inMemoryCacheItem)
+	delete(c.items, item.key)
+	c.size -= len(item.value)
+}
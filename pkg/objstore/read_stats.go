@@ -0,0 +1,95 @@
+package objstore
+
+import (
+	"context"
+	"io"
+
+	"go.uber.org/atomic"
+)
+
+// ReadStats collects statistics of the object storage reads
+// performed within a context. See ContextWithReadStats.
+type ReadStats struct {
+	BytesRead   atomic.Uint64
+	CacheHits   atomic.Uint64
+	CacheMisses atomic.Uint64
+}
+
+type readStatsKey struct{}
+
+// ContextWithReadStats returns a context carrying the read statistics.
+// Reads are only accounted, if the bucket is wrapped with
+// NewReadStatsBucket; cache hits are accounted by CachingBucket.
+func ContextWithReadStats(ctx context.Context, stats *ReadStats) context.Context {
+	return context.WithValue(ctx, readStatsKey{}, stats)
+}
+
+// ReadStatsFromContext returns the read statistics set with
+// ContextWithReadStats, or nil, if the context has none.
+func ReadStatsFromContext(ctx context.Context) *ReadStats {
+	stats, _ := ctx.Value(readStatsKey{}).(*ReadStats)
+	return stats
+}
+
+type readStatsBucket struct {
+	Bucket
+}
+
+// NewReadStatsBucket returns a bucket that accounts the bytes read
+// in the ReadStats of the request context, if present.
+func NewReadStatsBucket(b Bucket) Bucket {
+	return &readStatsBucket{Bucket: b}
+}
+
+func (b *readStatsBucket) Get(ctx context.Context, name string) (io.ReadCloser, error) {
+	rc, err := b.Bucket.Get(ctx, name)
+	return withReadStats(ctx, rc), err
+}
+
+func (b *readStatsBucket) GetRange(ctx context.Context, name string, off, length int64) (io.ReadCloser, error) {
+	rc, err := b.Bucket.GetRange(ctx, name, off, length)
+	return withReadStats(ctx, rc), err
+}
+
+func (b *readStatsBucket) ReaderAt(ctx context.Context, name string) (ReaderAtCloser, error) {
+	r, err := b.Bucket.ReaderAt(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if stats := ReadStatsFromContext(ctx); stats != nil {
+		return &readStatsReaderAt{ReaderAtCloser: r, stats: stats}, nil
+	}
+	return r, nil
+}
+
+func withReadStats(ctx context.Context, rc io.ReadCloser) io.ReadCloser {
+	if rc == nil {
+		return nil
+	}
+	if stats := ReadStatsFromContext(ctx); stats != nil {
+		return &readStatsReader{ReadCloser: rc, stats: stats}
+	}
+	return rc
+}
+
+type readStatsReader struct {
+	io.ReadCloser
+	stats *ReadStats
+}
+
+func (r *readStatsReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.stats.BytesRead.Add(uint64(n))
+	return n, err
+}
+
+type readStatsReaderAt struct {
+	ReaderAtCloser
+	stats *ReadStats
+}
+
+func (r *readStatsReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	n, err := r.ReaderAtCloser.ReadAt(p, off)
+	r.stats.BytesRead.Add(uint64(n))
+	return n, err
+}
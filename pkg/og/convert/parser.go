@@ -3,9 +3,12 @@ package convert
 import (
 	"bufio"
 	"bytes"
+	"compress/gzip"
+	"fmt"
 	"io"
 	"strconv"
 
+	"github.com/grafana/pyroscope/pkg/og/convert/perf"
 	"github.com/grafana/pyroscope/pkg/og/storage/tree"
 )
 
@@ -24,6 +27,32 @@ func ParseTreeNoDict(r io.Reader, cb func(name []byte, val int)) error {
 
 var gzipMagicBytes = []byte{0x1f, 0x8b}
 
+// MaybeDecompress returns the decompressed data if b is gzip-compressed,
+// otherwise b is returned as is. The decompressed data may not exceed
+// maxSize bytes; 0 means no limit.
+func MaybeDecompress(b []byte, maxSize int) ([]byte, error) {
+	if !bytes.HasPrefix(b, gzipMagicBytes) {
+		return b, nil
+	}
+	r, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	var src io.Reader = r
+	if maxSize > 0 {
+		src = io.LimitReader(r, int64(maxSize)+1)
+	}
+	buf := bytes.NewBuffer(make([]byte, 0, len(b)*4))
+	if _, err = io.Copy(buf, src); err != nil {
+		return nil, err
+	}
+	if maxSize > 0 && buf.Len() > maxSize {
+		return nil, fmt.Errorf("decompressed size exceeds the limit of %d bytes", maxSize)
+	}
+	return buf.Bytes(), nil
+}
+
 // format:
 // stack-trace-foo 1
 // stack-trace-bar 2
@@ -86,3 +115,27 @@ func ParseIndividualLines(r io.Reader, cb func(name []byte, val int)) error {
 
 	return nil
 }
+
+// format: output of `perf script`
+// comm pid/tid timestamp: event:
+//
+//	ffffffff81000000 foo (/lib/bar.so)
+//	ffffffff81000001 baz (/lib/bar.so)
+func ParsePerfScript(r io.Reader, cb func(name []byte, val int)) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	events, err := perf.NewScriptParser(b).ParseEvents()
+	if err != nil {
+		return err
+	}
+	groups := make(map[string]int)
+	for _, e := range events {
+		groups[string(bytes.Join(e, []byte{';'}))]++
+	}
+	for k, v := range groups {
+		cb([]byte(k), v)
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package convert
 
 import (
 	"bytes"
+	"compress/gzip"
 	"fmt"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -30,4 +31,52 @@ var _ = Describe("convert", func() {
 			Expect(result).To(ConsistOf("foo;bar 1", "foo;baz 1"))
 		})
 	})
+	Describe("ParsePerfScript", func() {
+		It("parses data correctly", func() {
+			r := bytes.NewReader([]byte("" +
+				"foo 1 [000] 1.000000: 1 cycles:\n" +
+				"\t1 bar (/bin/foo)\n" +
+				"\t2 main (/bin/foo)\n" +
+				"\n" +
+				"foo 1 [000] 1.000001: 1 cycles:\n" +
+				"\t1 bar (/bin/foo)\n" +
+				"\t2 main (/bin/foo)\n" +
+				"\n"))
+			result := []string{}
+			ParsePerfScript(r, func(name []byte, val int) {
+				result = append(result, fmt.Sprintf("%s %d", name, val))
+			})
+			Expect(result).To(ConsistOf("foo;main;bar 2"))
+		})
+	})
+
+	Describe("MaybeDecompress", func() {
+		It("decompresses gzip data", func() {
+			var buf bytes.Buffer
+			w := gzip.NewWriter(&buf)
+			w.Write([]byte("foo;bar 10\n"))
+			w.Close()
+			b, err := MaybeDecompress(buf.Bytes(), 0)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(b)).To(Equal("foo;bar 10\n"))
+			b, err = MaybeDecompress(buf.Bytes(), 11)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(b)).To(Equal("foo;bar 10\n"))
+		})
+
+		It("fails if the decompressed data exceeds the limit", func() {
+			var buf bytes.Buffer
+			w := gzip.NewWriter(&buf)
+			w.Write(bytes.Repeat([]byte("foo;bar 10\n"), 1000))
+			w.Close()
+			_, err := MaybeDecompress(buf.Bytes(), 100)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("returns uncompressed data as is", func() {
+			b, err := MaybeDecompress([]byte("foo;bar 10\n"), 0)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(b)).To(Equal("foo;bar 10\n"))
+		})
+	})
 })
@@ -163,6 +163,12 @@ func (p *RawProfile) loadPprofFromForm() error {
 	return nil
 }
 
+// MetricName returns the metric name (the __name__ label) of the profile,
+// derived from its sample types.
+func MetricName(profile *pprof.Profile) string {
+	return new(RawProfile).metricName(profile)
+}
+
 func (p *RawProfile) metricName(profile *pprof.Profile) string {
 	stConfigs := p.getSampleTypes()
 	var st string
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 
 	"github.com/grafana/pyroscope/pkg/og/convert"
 	"github.com/grafana/pyroscope/pkg/og/ingestion"
@@ -15,6 +16,9 @@ import (
 type RawProfile struct {
 	Format  ingestion.Format
 	RawData []byte
+	// MaxDecompressedSize limits the size of the text profiles
+	// once decompressed; 0 means no limit.
+	MaxDecompressedSize int
 }
 
 func (p *RawProfile) Bytes() ([]byte, error) { return p.RawData, nil }
@@ -42,9 +46,11 @@ func (p *RawProfile) Parse(ctx context.Context, putter storage.Putter, exporter
 	case ingestion.FormatTree:
 		err = convert.ParseTreeNoDict(r, cb)
 	case ingestion.FormatLines:
-		err = convert.ParseIndividualLines(r, cb)
+		err = p.parseText(cb, convert.ParseIndividualLines)
 	case ingestion.FormatGroups:
-		err = convert.ParseGroups(r, cb)
+		err = p.parseText(cb, convert.ParseGroups)
+	case ingestion.FormatPerfScript:
+		err = p.parseText(cb, convert.ParsePerfScript)
 	default:
 		return fmt.Errorf("unknown format %q", p.Format)
 	}
@@ -60,6 +66,15 @@ func (p *RawProfile) Parse(ctx context.Context, putter storage.Putter, exporter
 	return nil
 }
 
+// parseText parses a text profile, which may be gzip-compressed.
+func (p *RawProfile) parseText(cb func([]byte, int), parse func(io.Reader, func([]byte, int)) error) error {
+	b, err := convert.MaybeDecompress(p.RawData, p.MaxDecompressedSize)
+	if err != nil {
+		return fmt.Errorf("failed to decompress profile: %w", err)
+	}
+	return parse(bytes.NewReader(b), cb)
+}
+
 func createParseCallback(pi *storage.PutInput, e storage.MetricsExporter) func([]byte, int) {
 	o, ok := e.Evaluate(pi)
 	if !ok {
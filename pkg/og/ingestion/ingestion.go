@@ -32,6 +32,7 @@ const (
   FormatLines      Format = "lines"
   FormatGroups     Format = "groups"
   FormatSpeedscope Format = "speedscope"
+  FormatPerfScript Format = "perf_script"
 )
 
 type RawProfile interface {
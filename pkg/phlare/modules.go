@@ -33,8 +33,10 @@ import (
 	"google.golang.org/protobuf/encoding/protojson"
 	"gopkg.in/yaml.v3"
 
+	"github.com/grafana/pyroscope/api/gen/proto/go/querier/v1/querierv1connect"
 	statusv1 "github.com/grafana/pyroscope/api/gen/proto/go/status/v1"
 	"github.com/grafana/pyroscope/pkg/adhocprofiles"
+	"github.com/grafana/pyroscope/pkg/annotations"
 	apiversion "github.com/grafana/pyroscope/pkg/api/version"
 	"github.com/grafana/pyroscope/pkg/compactor"
 	"github.com/grafana/pyroscope/pkg/distributor"
@@ -43,11 +45,13 @@ import (
 	readpath "github.com/grafana/pyroscope/pkg/frontend/read_path"
 	queryfrontend "github.com/grafana/pyroscope/pkg/frontend/read_path/query_frontend"
 	"github.com/grafana/pyroscope/pkg/ingester"
+	phlareobj "github.com/grafana/pyroscope/pkg/objstore"
 	objstoreclient "github.com/grafana/pyroscope/pkg/objstore/client"
 	"github.com/grafana/pyroscope/pkg/objstore/providers/filesystem"
 	"github.com/grafana/pyroscope/pkg/operations"
 	phlarecontext "github.com/grafana/pyroscope/pkg/phlare/context"
 	"github.com/grafana/pyroscope/pkg/querier"
+	"github.com/grafana/pyroscope/pkg/querier/serviceversions"
 	"github.com/grafana/pyroscope/pkg/querier/vcs"
 	"github.com/grafana/pyroscope/pkg/querier/worker"
 	"github.com/grafana/pyroscope/pkg/scheduler"
@@ -58,6 +62,7 @@ import (
 	"github.com/grafana/pyroscope/pkg/util/build"
 	"github.com/grafana/pyroscope/pkg/validation"
 	"github.com/grafana/pyroscope/pkg/validation/exporter"
+	"github.com/grafana/pyroscope/pkg/validation/tenantoverrides"
 )
 
 // The various modules that make up Pyroscope.
@@ -84,6 +89,7 @@ const (
 	Admin             string = "admin"
 	TenantSettings    string = "tenant-settings"
 	AdHocProfiles     string = "ad-hoc-profiles"
+	Annotations       string = "annotations"
 	EmbeddedGrafana   string = "embedded-grafana"
 
 	// Experimental modules
@@ -122,9 +128,14 @@ func (f *Phlare) initQueryFrontend() (services.Service, error) {
 	f.frontend = frontendSvc
 	f.API.RegisterFrontendForQuerierHandler(frontendSvc)
 	if !f.Cfg.v2Experiment {
-		f.API.RegisterQuerierServiceHandler(frontendSvc)
-		f.API.RegisterPyroscopeHandlers(frontendSvc)
+		var querierSvc querierv1connect.QuerierServiceHandler = frontendSvc
+		if f.Cfg.Frontend.TenantFederationEnabled {
+			querierSvc = frontend.NewTenantFederation(f.Overrides, frontendSvc)
+		}
+		f.API.RegisterQuerierServiceHandler(querierSvc)
+		f.API.RegisterPyroscopeHandlers(querierSvc)
 		f.API.RegisterVCSServiceHandler(frontendSvc)
+		f.API.RegisterServiceVersionsHandler(serviceversions.New(log.With(f.logger, "component", "service-versions"), querierSvc, frontendSvc))
 	} else {
 		f.initReadPathRouter()
 	}
@@ -136,6 +147,7 @@ func (f *Phlare) initReadPathRouter() {
 	vcsService := vcs.New(
 		log.With(f.logger, "component", "vcs-service"),
 		f.reg,
+		f.Overrides,
 	)
 
 	newFrontend := queryfrontend.NewQueryFrontend(
@@ -155,6 +167,7 @@ func (f *Phlare) initReadPathRouter() {
 	f.API.RegisterQuerierServiceHandler(router)
 	f.API.RegisterPyroscopeHandlers(router)
 	f.API.RegisterVCSServiceHandler(vcsService)
+	f.API.RegisterServiceVersionsHandler(serviceversions.New(log.With(f.logger, "component", "service-versions"), router, vcsService))
 }
 
 func (f *Phlare) initRuntimeConfig() (services.Service, error) {
@@ -208,22 +221,80 @@ func (f *Phlare) initTenantSettings() (services.Service, error) {
 	return settings, nil
 }
 
+func (f *Phlare) initAnnotations() (services.Service, error) {
+	var store annotations.Store
+	var err error
+
+	switch {
+	case f.storageBucket != nil:
+		store, err = annotations.NewBucketStore(f.storageBucket)
+	default:
+		store, err = annotations.NewMemoryStore()
+		level.Warn(f.logger).Log("msg", "using in-memory annotations store, changes will be lost after shutdown")
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to init annotations store")
+	}
+
+	a, err := annotations.New(store, log.With(f.logger, "component", Annotations))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to init annotations service")
+	}
+
+	f.API.RegisterAnnotations(a)
+	return a, nil
+}
+
 func (f *Phlare) initAdHocProfiles() (services.Service, error) {
 	if f.storageBucket == nil {
 		level.Warn(f.logger).Log("msg", "no storage bucket configured, ad hoc profiles will not be loaded")
 		return nil, nil
 	}
 
-	a := adhocprofiles.NewAdHocProfiles(f.storageBucket, f.logger, f.Overrides)
+	// The distributor is optional: f.distributor must not be passed
+	// as is, as a nil *Distributor is a non-nil PushService.
+	var pusher adhocprofiles.PushService
+	if f.distributor != nil {
+		pusher = f.distributor
+	}
+	f.Cfg.AdHocProfiles.Ring.Ring.ListenPort = f.Cfg.Server.HTTPListenPort
+	a, err := adhocprofiles.NewAdHocProfiles(f.Cfg.AdHocProfiles, f.storageBucket, f.logger, f.Overrides, pusher, f.reg)
+	if err != nil {
+		return nil, err
+	}
 	f.API.RegisterAdHocProfiles(a)
 	return a, nil
 }
 
 func (f *Phlare) initOverrides() (serv services.Service, err error) {
+	if f.Cfg.TenantOverrides.Enabled {
+		if f.storageBucket == nil {
+			return nil, errors.New("storage bucket configuration is required for tenant overrides")
+		}
+		f.Cfg.TenantOverrides.Ring.Ring.ListenPort = f.Cfg.Server.HTTPListenPort
+		if f.Cfg.Server.HTTPTLSConfig.TLSCertPath != "" {
+			f.Cfg.TenantOverrides.LeaderScheme = "https"
+		}
+		m, err := tenantoverrides.NewManager(
+			f.Cfg.TenantOverrides,
+			f.Cfg.LimitsConfig,
+			f.TenantLimits,
+			f.storageBucket,
+			log.With(f.logger, "component", "tenant-overrides"),
+			f.reg,
+		)
+		if err != nil {
+			return nil, err
+		}
+		f.TenantLimits = m
+		f.API.RegisterTenantOverrides(m)
+		serv = m
+	}
 	f.Overrides, err = validation.NewOverrides(f.Cfg.LimitsConfig, f.TenantLimits)
-	// overrides don't have operational state, nor do they need to do anything more in starting/stopping phase,
-	// so there is no need to return any service.
-	return nil, err
+	// Unless the tenant overrides are enabled, overrides don't have operational state,
+	// nor do they need to do anything more in starting/stopping phase, so there is no
+	// need to return any service.
+	return serv, err
 }
 
 func (f *Phlare) initOverridesExporter() (services.Service, error) {
@@ -293,12 +364,16 @@ func (f *Phlare) setupWorkerTimeout() {
 }
 
 func (f *Phlare) initQuerier() (services.Service, error) {
+	bucket, err := f.readPathBucket()
+	if err != nil {
+		return nil, err
+	}
 	newQuerierParams := &querier.NewQuerierParams{
 		Cfg:             f.Cfg.Querier,
 		StoreGatewayCfg: f.Cfg.StoreGateway,
 		Overrides:       f.Overrides,
 		CfgProvider:     f.Overrides,
-		StorageBucket:   f.storageBucket,
+		StorageBucket:   bucket,
 		IngestersRing:   f.ingesterRing,
 		Reg:             f.reg,
 		Logger:          log.With(f.logger, "component", "querier"),
@@ -313,6 +388,7 @@ func (f *Phlare) initQuerier() (services.Service, error) {
 		f.API.RegisterPyroscopeHandlers(querierSvc)
 		f.API.RegisterQuerierServiceHandler(querierSvc)
 		f.API.RegisterVCSServiceHandler(querierSvc)
+		f.API.RegisterServiceVersionsHandler(serviceversions.New(log.With(f.logger, "component", "service-versions"), querierSvc, querierSvc))
 	}
 	qWorker, err := worker.NewQuerierWorker(f.Cfg.Worker, querier.NewGRPCHandler(querierSvc), log.With(f.logger, "component", "querier-worker"), f.reg)
 	if err != nil {
@@ -367,7 +443,8 @@ func (f *Phlare) initDistributor() (services.Service, error) {
 	if err != nil {
 		return nil, err
 	}
-	f.API.RegisterDistributor(d)
+	f.distributor = d
+	f.API.RegisterDistributor(d, f.Overrides)
 	return d, nil
 }
 
@@ -394,6 +471,8 @@ func (f *Phlare) initMemberlistKV() (services.Service, error) {
 	f.Cfg.SegmentWriter.LifecyclerConfig.RingConfig.KVStore.MemberlistKV = f.MemberlistKV.GetMemberlistKV
 	f.Cfg.QueryScheduler.ServiceDiscovery.SchedulerRing.KVStore.MemberlistKV = f.MemberlistKV.GetMemberlistKV
 	f.Cfg.OverridesExporter.Ring.Ring.KVStore.MemberlistKV = f.MemberlistKV.GetMemberlistKV
+	f.Cfg.AdHocProfiles.Ring.Ring.KVStore.MemberlistKV = f.MemberlistKV.GetMemberlistKV
+	f.Cfg.TenantOverrides.Ring.Ring.KVStore.MemberlistKV = f.MemberlistKV.GetMemberlistKV
 	f.Cfg.StoreGateway.ShardingRing.Ring.KVStore.MemberlistKV = f.MemberlistKV.GetMemberlistKV
 	f.Cfg.Compactor.ShardingRing.Common.KVStore.MemberlistKV = f.MemberlistKV.GetMemberlistKV
 	f.Cfg.Frontend.QuerySchedulerDiscovery = f.Cfg.QueryScheduler.ServiceDiscovery
@@ -437,6 +516,28 @@ func (f *Phlare) initStorage() (_ services.Service, err error) {
 	return nil, nil
 }
 
+// readPathBucket returns the storage bucket wrapped with the cache used by
+// the read path components. The caching bucket is created once and shared by
+// all the modules running in the process.
+func (f *Phlare) readPathBucket() (phlareobj.Bucket, error) {
+	if f.storageBucket == nil {
+		return nil, nil
+	}
+	if f.cachingBucket == nil {
+		b, err := phlareobj.NewCachingBucket(
+			f.storageBucket,
+			f.Cfg.StoreGateway.BucketStoreConfig.Cache,
+			log.With(f.logger, "component", "bucket-cache"),
+			f.reg,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to initialise bucket cache")
+		}
+		f.cachingBucket = b
+	}
+	return f.cachingBucket, nil
+}
+
 // TODO: This should be passed to all other services and could also be used to signal shutdown
 func (f *Phlare) context() context.Context {
 	phlarectx := phlarecontext.WithLogger(context.Background(), f.logger)
@@ -463,7 +564,11 @@ func (f *Phlare) initStoreGateway() (serv services.Service, err error) {
 		return nil, nil
 	}
 
-	svc, err := storegateway.NewStoreGateway(f.Cfg.StoreGateway, f.storageBucket, f.Overrides, f.logger, f.reg)
+	bucket, err := f.readPathBucket()
+	if err != nil {
+		return nil, err
+	}
+	svc, err := storegateway.NewStoreGateway(f.Cfg.StoreGateway, bucket, f.Overrides, f.logger, f.reg)
 	if err != nil {
 		return nil, err
 	}
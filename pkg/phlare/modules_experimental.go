@@ -13,6 +13,7 @@ import (
 	kuberesolver2 "github.com/grafana/pyroscope/pkg/experiment/metastore/discovery/kuberesolver"
 
 	compactionworker "github.com/grafana/pyroscope/pkg/experiment/compactor"
+	"github.com/grafana/pyroscope/pkg/experiment/distributor/placement"
 	segmentwriter "github.com/grafana/pyroscope/pkg/experiment/ingester"
 	segmentwriterclient "github.com/grafana/pyroscope/pkg/experiment/ingester/client"
 	"github.com/grafana/pyroscope/pkg/experiment/metastore"
@@ -70,6 +71,7 @@ func (f *Phlare) initSegmentWriterClient() (_ services.Service, err error) {
 		f.Cfg.SegmentWriter.GRPCClientConfig,
 		logger, f.reg,
 		f.segmentWriterRing,
+		placement.NewAdaptivePlacement(f.Overrides, healthyDistributors{f}),
 	)
 	if err != nil {
 		return nil, err
@@ -78,6 +80,18 @@ func (f *Phlare) initSegmentWriterClient() (_ services.Service, err error) {
 	return client.Service(), nil
 }
 
+// healthyDistributors reports the number of healthy distributors to the
+// segment writer client placement. The client is created before the
+// distributor it is used by, therefore the distributor is resolved lazily.
+type healthyDistributors struct{ f *Phlare }
+
+func (d healthyDistributors) HealthyInstancesCount() int {
+	if d.f.distributor == nil {
+		return 0
+	}
+	return d.f.distributor.HealthyInstancesCount()
+}
+
 func (f *Phlare) initCompactionWorker() (svc services.Service, err error) {
 	if err = f.Cfg.CompactionWorker.Validate(); err != nil {
 		return nil, err
@@ -107,6 +121,7 @@ func (f *Phlare) initMetastore() (services.Service, error) {
 		logger,
 		f.reg,
 		f.metastoreClient,
+		f.storageBucket,
 	)
 	if err != nil {
 		return nil, err
@@ -147,12 +162,16 @@ func (f *Phlare) initQueryBackend() (services.Service, error) {
 		return nil, err
 	}
 	logger := log.With(f.logger, "component", "query-backend")
+	bucket, err := f.readPathBucket()
+	if err != nil {
+		return nil, err
+	}
 	b, err := querybackend.New(
 		f.Cfg.QueryBackend,
 		logger,
 		f.reg,
 		f.queryBackendClient,
-		querybackend.NewBlockReader(f.logger, f.storageBucket),
+		querybackend.NewBlockReader(f.logger, bucket),
 	)
 	if err != nil {
 		return nil, err
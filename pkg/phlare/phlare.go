@@ -37,9 +37,12 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/version"
 	"github.com/samber/lo"
+	"google.golang.org/grpc"
 
+	"github.com/grafana/pyroscope/pkg/adhocprofiles"
 	"github.com/grafana/pyroscope/pkg/api"
 	apiversion "github.com/grafana/pyroscope/pkg/api/version"
+	"github.com/grafana/pyroscope/pkg/auth"
 	"github.com/grafana/pyroscope/pkg/cfg"
 	"github.com/grafana/pyroscope/pkg/compactor"
 	"github.com/grafana/pyroscope/pkg/distributor"
@@ -63,7 +66,6 @@ import (
 	"github.com/grafana/pyroscope/pkg/scheduler"
 	"github.com/grafana/pyroscope/pkg/scheduler/schedulerdiscovery"
 	"github.com/grafana/pyroscope/pkg/storegateway"
-	"github.com/grafana/pyroscope/pkg/tenant"
 	"github.com/grafana/pyroscope/pkg/tracing"
 	"github.com/grafana/pyroscope/pkg/usagestats"
 	"github.com/grafana/pyroscope/pkg/util"
@@ -71,6 +73,7 @@ import (
 	"github.com/grafana/pyroscope/pkg/util/health"
 	"github.com/grafana/pyroscope/pkg/validation"
 	"github.com/grafana/pyroscope/pkg/validation/exporter"
+	"github.com/grafana/pyroscope/pkg/validation/tenantoverrides"
 )
 
 type Config struct {
@@ -89,13 +92,16 @@ type Config struct {
 	PhlareDB          phlaredb.Config        `yaml:"pyroscopedb,omitempty"`
 	Tracing           tracing.Config         `yaml:"tracing"`
 	OverridesExporter exporter.Config        `yaml:"overrides_exporter" doc:"hidden"`
+	AdHocProfiles     adhocprofiles.Config   `yaml:"adhoc_profiles" doc:"hidden"`
 	RuntimeConfig     runtimeconfig.Config   `yaml:"runtime_config"`
+	TenantOverrides   tenantoverrides.Config `yaml:"tenant_overrides"`
 	Compactor         compactor.Config       `yaml:"compactor"`
 
 	Storage       StorageConfig       `yaml:"storage"`
 	SelfProfiling SelfProfilingConfig `yaml:"self_profiling,omitempty"`
 
 	MultitenancyEnabled bool              `yaml:"multitenancy_enabled,omitempty"`
+	Auth                auth.Config       `yaml:"auth"`
 	Analytics           usagestats.Config `yaml:"analytics"`
 	ShowBanner          bool              `yaml:"show_banner,omitempty"`
 
@@ -167,7 +173,9 @@ func (c *Config) RegisterFlagsWithContext(ctx context.Context, f *flag.FlagSet)
 	c.Storage.RegisterFlagsWithContext(ctx, f)
 	c.SelfProfiling.RegisterFlags(f)
 	c.RuntimeConfig.RegisterFlags(f)
+	c.TenantOverrides.RegisterFlags(f, log.NewLogfmtLogger(os.Stderr))
 	c.Analytics.RegisterFlags(f)
+	c.Auth.RegisterFlags(f)
 	c.LimitsConfig.RegisterFlags(f)
 	c.Compactor.RegisterFlags(f, log.NewLogfmtLogger(os.Stderr))
 	c.API.RegisterFlags(f)
@@ -187,6 +195,7 @@ func (c *Config) registerServerFlagsWithChangedDefaultValues(fs *flag.FlagSet) {
 	c.QueryScheduler.RegisterFlags(throwaway, log.NewLogfmtLogger(os.Stderr))
 	c.Worker.RegisterFlags(throwaway)
 	c.OverridesExporter.RegisterFlags(throwaway, log.NewLogfmtLogger(os.Stderr))
+	c.AdHocProfiles.RegisterFlags(throwaway, log.NewLogfmtLogger(os.Stderr))
 
 	overrides := map[string]string{
 		"server.http-listen-port":                "4040",
@@ -232,6 +241,12 @@ func (c *Config) Validate() error {
 	if err := c.Compactor.Validate(c.PhlareDB.MaxBlockDuration); err != nil {
 		return err
 	}
+	if err := c.Auth.Validate(); err != nil {
+		return err
+	}
+	if err := c.TenantOverrides.Validate(); err != nil {
+		return err
+	}
 	return c.Ingester.Validate()
 }
 
@@ -240,6 +255,8 @@ func (c *Config) ApplyDynamicConfig() cfg.Source {
 	c.SegmentWriter.LifecyclerConfig.RingConfig.KVStore.Store = "memberlist"
 	c.Distributor.DistributorRing.KVStore.Store = c.Ingester.LifecyclerConfig.RingConfig.KVStore.Store
 	c.OverridesExporter.Ring.Ring.KVStore.Store = c.Ingester.LifecyclerConfig.RingConfig.KVStore.Store
+	c.AdHocProfiles.Ring.Ring.KVStore.Store = c.Ingester.LifecyclerConfig.RingConfig.KVStore.Store
+	c.TenantOverrides.Ring.Ring.KVStore.Store = c.Ingester.LifecyclerConfig.RingConfig.KVStore.Store
 	c.Frontend.QuerySchedulerDiscovery.SchedulerRing.KVStore.Store = c.Ingester.LifecyclerConfig.RingConfig.KVStore.Store
 	c.Worker.QuerySchedulerDiscovery.SchedulerRing.KVStore.Store = c.Ingester.LifecyclerConfig.RingConfig.KVStore.Store
 	c.QueryScheduler.ServiceDiscovery.SchedulerRing.KVStore.Store = c.Ingester.LifecyclerConfig.RingConfig.KVStore.Store
@@ -283,12 +300,14 @@ type Phlare struct {
 	TenantLimits validation.TenantLimits
 
 	storageBucket phlareobj.Bucket
+	cachingBucket phlareobj.Bucket
 
 	grpcGatewayMux *grpcgw.ServeMux
 
-	auth     connect.Option
-	ingester *ingester.Ingester
-	frontend *frontend.Frontend
+	auth        connect.Option
+	ingester    *ingester.Ingester
+	frontend    *frontend.Frontend
+	distributor *distributor.Distributor
 
 	// Experimental modules.
 	segmentWriter       *segmentwriter.SegmentWriterService
@@ -336,9 +355,19 @@ func New(cfg Config) (*Phlare, error) {
 		phlare.tracer = trace
 	}
 
-	phlare.auth = connect.WithInterceptors(tenant.NewAuthInterceptor(cfg.MultitenancyEnabled))
-	phlare.Cfg.API.HTTPAuthMiddleware = util.AuthenticateUser(cfg.MultitenancyEnabled)
+	authenticator, err := auth.New(cfg.Auth, cfg.MultitenancyEnabled, logger)
+	if err != nil {
+		return nil, err
+	}
+	// The internal services require the internal token: the components
+	// calling them authenticate with it, and forward the tenant ID.
+	phlare.auth = connect.WithInterceptors(authenticator.InternalInterceptor())
+	phlare.Cfg.Worker.FrontendClientInterceptors = []grpc.UnaryClientInterceptor{authenticator.InternalGRPCClientInterceptor()}
+	phlare.Cfg.QueryScheduler.FrontendClientInterceptors = []grpc.UnaryClientInterceptor{authenticator.InternalGRPCClientInterceptor()}
+	phlare.Cfg.API.HTTPAuthMiddleware = authenticator.HTTPMiddleware()
+	phlare.Cfg.API.HTTPAdminAuthMiddleware = authenticator.AdminHTTPMiddleware()
 	phlare.Cfg.API.GrpcAuthMiddleware = phlare.auth
+	phlare.Cfg.API.GrpcUserAuthMiddleware = connect.WithInterceptors(authenticator.Interceptor())
 
 	return phlare, nil
 }
@@ -368,11 +397,12 @@ func (f *Phlare) setupModuleManager() error {
 	mm.RegisterModule(All, nil)
 	mm.RegisterModule(TenantSettings, f.initTenantSettings)
 	mm.RegisterModule(AdHocProfiles, f.initAdHocProfiles)
+	mm.RegisterModule(Annotations, f.initAnnotations)
 	mm.RegisterModule(EmbeddedGrafana, f.initEmbeddedGrafana)
 
 	// Add dependencies
 	deps := map[string][]string{
-		All: {Ingester, Distributor, QueryFrontend, QueryScheduler, Querier, StoreGateway, Compactor, Admin, TenantSettings, AdHocProfiles},
+		All: {Ingester, Distributor, QueryFrontend, QueryScheduler, Querier, StoreGateway, Compactor, Admin, TenantSettings, AdHocProfiles, Annotations},
 
 		Server:            {GRPCGateway},
 		API:               {Server},
@@ -392,19 +422,24 @@ func (f *Phlare) setupModuleManager() error {
 		Admin:             {API, Storage},
 		Version:           {API, MemberlistKV},
 		TenantSettings:    {API, Storage},
-		AdHocProfiles:     {API, Overrides, Storage},
+		AdHocProfiles:     {API, Overrides, Storage, MemberlistKV},
+		Annotations:       {API, Storage},
 		EmbeddedGrafana:   {API},
 	}
 
+	if f.Cfg.TenantOverrides.Enabled {
+		deps[Overrides] = append(deps[Overrides], API, Storage, MemberlistKV)
+	}
+
 	// Experimental modules.
 	if f.Cfg.v2Experiment {
 		experimentalModules := map[string][]string{
 			SegmentWriter:       {Overrides, API, MemberlistKV, Storage, UsageReport, MetastoreClient},
-			Metastore:           {Overrides, API, MetastoreClient},
+			Metastore:           {Overrides, API, MetastoreClient, Storage},
 			CompactionWorker:    {Overrides, API, Storage, Overrides, MetastoreClient},
 			QueryBackend:        {Overrides, API, Storage, Overrides, QueryBackendClient},
 			SegmentWriterRing:   {Overrides, API, MemberlistKV},
-			SegmentWriterClient: {SegmentWriterRing},
+			SegmentWriterClient: {Overrides, SegmentWriterRing},
 			HealthService:       {API},
 		}
 		for k, v := range experimentalModules {
@@ -426,13 +461,25 @@ func (f *Phlare) setupModuleManager() error {
 		mm.RegisterModule(HealthService, f.initHealthService, modules.UserInvisibleModule)
 	}
 
+	f.deps = deps
+	// Ad hoc profiles are ingested through the distributor, if it runs
+	// in the same process.
+	if f.isModuleActive(Distributor) {
+		deps[AdHocProfiles] = append(deps[AdHocProfiles], Distributor)
+	}
+	// The render handlers attach annotations to the timeline, if the
+	// annotations service runs in the same process.
+	if f.isModuleActive(Annotations) {
+		deps[Querier] = append(deps[Querier], Annotations)
+		deps[QueryFrontend] = append(deps[QueryFrontend], Annotations)
+	}
+
 	for mod, targets := range deps {
 		if err := mm.AddDependency(mod, targets...); err != nil {
 			return err
 		}
 	}
 
-	f.deps = deps
 	f.ModuleManager = mm
 
 	return nil
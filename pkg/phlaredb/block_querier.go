@@ -551,6 +551,16 @@ func (b *singleBlockQuerier) GetMetaStats() block.MetaStats {
 	return b.meta.GetStats()
 }
 
+// bytesPerProfile returns the average size of a profile in the block,
+// including its share of the index and symbols. It is used to estimate
+// the number of bytes a query scans.
+func (b *singleBlockQuerier) bytesPerProfile() uint64 {
+	if b.meta.Stats.NumProfiles == 0 {
+		return 0
+	}
+	return b.meta.GetStats().TotalSizeBytes / b.meta.Stats.NumProfiles
+}
+
 type Profile interface {
 	RowNumber() int64
 	StacktracePartition() uint64
@@ -894,6 +904,12 @@ func MergeProfilesStacktraces(ctx context.Context, stream *connect.BidiStream[in
 		return err
 	}
 
+	// The merge iterators stop once the query limits are exceeded,
+	// which may not be reported as an error.
+	if err := QueryLimiterFromContext(ctx).Err(); err != nil {
+		return err
+	}
+
 	// sends the final result to the client.
 	treeBytes := t.Bytes(r.GetMaxNodes())
 	sp.LogFields(
@@ -1037,6 +1053,12 @@ func MergeSpanProfile(ctx context.Context, stream *connect.BidiStream[ingestv1.M
 		return err
 	}
 
+	// The merge iterators stop once the query limits are exceeded,
+	// which may not be reported as an error.
+	if err := QueryLimiterFromContext(ctx).Err(); err != nil {
+		return err
+	}
+
 	// sends the final result to the client.
 	treeBytes := t.Bytes(r.GetMaxNodes())
 	sp.LogFields(
@@ -1166,6 +1188,12 @@ func MergeProfilesLabels(ctx context.Context, stream *connect.BidiStream[ingestv
 		return err
 	}
 
+	// The merge iterators stop once the query limits are exceeded,
+	// which may not be reported as an error.
+	if err := QueryLimiterFromContext(ctx).Err(); err != nil {
+		return err
+	}
+
 	// sends the final result to the client.
 	err = stream.Send(&ingestv1.MergeProfilesLabelsResponse{
 		Series: phlaremodel.MergeSeries(request.Aggregation, result...),
@@ -1283,6 +1311,12 @@ func MergeProfilesPprof(ctx context.Context, stream *connect.BidiStream[ingestv1
 		return err
 	}
 
+	// The merge iterators stop once the query limits are exceeded,
+	// which may not be reported as an error.
+	if err := QueryLimiterFromContext(ctx).Err(); err != nil {
+		return err
+	}
+
 	sp.LogFields(otlog.String("msg", "building pprof bytes"))
 	mergedProfile := result.Profile()
 	pprof.SetProfileMetadata(mergedProfile, request.Type, model.Time(r.Request.End).UnixNano(), 0)
@@ -1544,6 +1578,9 @@ func (b *singleBlockQuerier) SelectMatchingProfiles(ctx context.Context, params
 		if err != nil {
 			return nil, err
 		}
+		if err = QueryLimiterFromContext(ctx).AddSeries(model.Fingerprint(fp)); err != nil {
+			return nil, err
+		}
 		if _, exists := lblsPerRef[int64(chks[0].SeriesIndex)]; exists {
 			continue
 		}
@@ -1579,9 +1616,16 @@ func (b *singleBlockQuerier) SelectMatchingProfiles(ctx context.Context, params
 	iters := make([]iter.Iterator[Profile], 0, len(lblsPerRef))
 	defer pIt.Close()
 
+	var (
+		limiter         = QueryLimiterFromContext(ctx)
+		bytesPerProfile = b.bytesPerProfile()
+	)
 	currSeriesIndex := int64(-1)
 	var currentSeriesSlice []Profile
 	for pIt.Next() {
+		if err = limiter.AddProfiles(1, bytesPerProfile); err != nil {
+			return nil, err
+		}
 		res := pIt.At()
 		buf = res.Columns(buf, "SeriesIndex", "TimeNanos", "StacktracePartition")
 		seriesIndex := buf[0][0].Int64()
@@ -1649,6 +1693,9 @@ func (b *singleBlockQuerier) SelectMergeByLabels(
 		if err != nil {
 			return nil, err
 		}
+		if err = QueryLimiterFromContext(ctx).AddSeries(model.Fingerprint(fp)); err != nil {
+			return nil, err
+		}
 
 		_, ok := lblsPerRef[int64(chks[0].SeriesIndex)]
 		if !ok {
@@ -1673,7 +1720,7 @@ func (b *singleBlockQuerier) SelectMergeByLabels(
 		if b.meta.Version == 1 {
 			columnName = "Samples.list.element.Value"
 		}
-		rows := profileBatchIteratorBySeriesIndex(it, lblsPerRef)
+		rows := profileBatchIteratorBySeriesIndex(limitProfiles(ctx, it, b.bytesPerProfile()), lblsPerRef)
 		defer rows.Close()
 		return mergeByLabels[Profile](ctx, profiles.file, columnName, rows, by...)
 	}
@@ -1687,7 +1734,7 @@ func (b *singleBlockQuerier) SelectMergeByLabels(
 	defer r.Release()
 
 	it = query.NewBinaryJoinIterator(0, it, profiles.columnIter(ctx, "StacktracePartition", nil, "StacktracePartition"))
-	rows := profileBatchIteratorBySeriesIndex(it, lblsPerRef)
+	rows := profileBatchIteratorBySeriesIndex(limitProfiles(ctx, it, b.bytesPerProfile()), lblsPerRef)
 	defer rows.Close()
 
 	return mergeByLabelsWithStackTraceSelector[Profile](ctx, profiles.file, rows, r, by...)
@@ -1726,10 +1773,13 @@ func (b *singleBlockQuerier) SelectMergeByStacktraces(ctx context.Context, param
 
 	// get all relevant labels/fingerprints
 	for postings.Next() {
-		_, err := b.index.Series(postings.At(), nil, &chks)
+		fp, err := b.index.Series(postings.At(), nil, &chks)
 		if err != nil {
 			return nil, err
 		}
+		if err = QueryLimiterFromContext(ctx).AddSeries(model.Fingerprint(fp)); err != nil {
+			return nil, err
+		}
 		lblsPerRef[int64(chks[0].SeriesIndex)] = struct{}{}
 	}
 	r := symdb.NewResolver(ctx, b.symbols, symdb.WithResolverMaxNodes(maxNodes))
@@ -1751,7 +1801,7 @@ func (b *singleBlockQuerier) SelectMergeByStacktraces(ctx context.Context, param
 					profiles.columnIter(ctx, "StacktracePartition", nil, "StacktracePartition"),
 				)
 			}
-			rows := profileRowBatchIterator(it)
+			rows := profileRowBatchIterator(limitProfiles(ctx, it, b.bytesPerProfile()))
 			defer rows.Close()
 			return mergeByStacktraces(ctx, profiles.file, rows, r)
 		})
@@ -1799,10 +1849,13 @@ func (b *singleBlockQuerier) SelectMergeBySpans(ctx context.Context, params *ing
 
 	// get all relevant labels/fingerprints
 	for postings.Next() {
-		_, err := b.index.Series(postings.At(), nil, &chks)
+		fp, err := b.index.Series(postings.At(), nil, &chks)
 		if err != nil {
 			return nil, err
 		}
+		if err = QueryLimiterFromContext(ctx).AddSeries(model.Fingerprint(fp)); err != nil {
+			return nil, err
+		}
 		lblsPerRef[int64(chks[0].SeriesIndex)] = struct{}{}
 	}
 	r := symdb.NewResolver(ctx, b.symbols)
@@ -1822,7 +1875,7 @@ func (b *singleBlockQuerier) SelectMergeBySpans(ctx context.Context, params *ing
 		)
 	}
 
-	rows := profileRowBatchIterator(it)
+	rows := profileRowBatchIterator(limitProfiles(ctx, it, b.bytesPerProfile()))
 	defer rows.Close()
 	if err = mergeBySpans[rowProfile](ctx, profiles.file, rows, r, spans); err != nil {
 		return nil, err
@@ -1863,10 +1916,13 @@ func (b *singleBlockQuerier) SelectMergePprof(ctx context.Context, params *inges
 
 	// get all relevant labels/fingerprints
 	for postings.Next() {
-		_, err := b.index.Series(postings.At(), nil, &chks)
+		fp, err := b.index.Series(postings.At(), nil, &chks)
 		if err != nil {
 			return nil, err
 		}
+		if err = QueryLimiterFromContext(ctx).AddSeries(model.Fingerprint(fp)); err != nil {
+			return nil, err
+		}
 		lblsPerRef[int64(chks[0].SeriesIndex)] = struct{}{}
 	}
 	r := symdb.NewResolver(ctx, b.symbols,
@@ -1890,7 +1946,7 @@ func (b *singleBlockQuerier) SelectMergePprof(ctx context.Context, params *inges
 					profiles.columnIter(ctx, "StacktracePartition", nil, "StacktracePartition"),
 				)
 			}
-			rows := profileRowBatchIterator(it)
+			rows := profileRowBatchIterator(limitProfiles(ctx, it, b.bytesPerProfile()))
 			defer rows.Close()
 			return mergeByStacktraces[rowProfile](ctx, profiles.file, rows, r)
 		})
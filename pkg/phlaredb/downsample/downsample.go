@@ -5,6 +5,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/dolthub/swiss"
 	"github.com/go-kit/log"
@@ -93,6 +94,31 @@ func initConfigs() []downsampleConfig {
 	return configs
 }
 
+// Table describes a downsampled profile table written by the Downsampler.
+type Table struct {
+	Resolution  time.Duration
+	Aggregation string
+	FileName    string
+}
+
+// Tables returns the downsampled profile tables in the order
+// they are written by the Downsampler.
+func Tables() []Table {
+	tables := make([]Table, len(configs))
+	for i, c := range configs {
+		tables[i] = Table{
+			Resolution:  time.Duration(c.interval.durationSeconds) * time.Second,
+			Aggregation: c.aggregation.name,
+			FileName:    tableFileName(c.interval, c.aggregation.name),
+		}
+	}
+	return tables
+}
+
+func tableFileName(i interval, aggregation string) string {
+	return fmt.Sprintf("profiles_%s_%s", i.shortName, aggregation) + block.ParquetSuffix
+}
+
 type profilesWriter struct {
 	*parquet.GenericWriter[*schemav1.Profile]
 	file *os.File
@@ -111,7 +137,7 @@ func (p *profilesWriter) WriteRow(r parquet.Row) error {
 }
 
 func newProfilesWriter(path string, i interval, aggregation string) (*profilesWriter, error) {
-	profilePath := filepath.Join(path, fmt.Sprintf("profiles_%s_%s", i.shortName, aggregation)+block.ParquetSuffix)
+	profilePath := filepath.Join(path, tableFileName(i, aggregation))
 	profileFile, err := os.OpenFile(profilePath, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o644)
 	if err != nil {
 		return nil, err
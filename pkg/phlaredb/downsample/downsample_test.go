@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/go-kit/log"
 	"github.com/parquet-go/parquet-go"
@@ -250,3 +251,18 @@ func readDownsampledRows(t *testing.T, path string, expectedRowCount int) []parq
 	require.Equal(t, expectedRowCount, rowCount)
 	return downsampledRows
 }
+
+func TestDownsampler_Tables(t *testing.T) {
+	d, err := NewDownsampler(t.TempDir(), log.NewNopLogger())
+	require.NoError(t, err)
+	require.NoError(t, d.Close())
+
+	tables := Tables()
+	require.Len(t, tables, 2)
+	assert.Equal(t, Table{Resolution: 5 * time.Minute, Aggregation: "sum", FileName: "profiles_5m_sum.parquet"}, tables[0])
+	assert.Equal(t, Table{Resolution: time.Hour, Aggregation: "sum", FileName: "profiles_1h_sum.parquet"}, tables[1])
+	for _, table := range tables {
+		_, err = os.Stat(filepath.Join(d.path, table.FileName))
+		assert.NoError(t, err)
+	}
+}
@@ -154,6 +154,16 @@ func (h *Head) Size() uint64 {
 	return h.profiles.Size() + h.symdb.MemorySize()
 }
 
+// bytesPerProfile returns the estimated size of a profile in the head,
+// the same way it is estimated for blocks.
+func (h *Head) bytesPerProfile() uint64 {
+	n := h.profiles.index.totalProfiles.Load()
+	if n <= 0 {
+		return 0
+	}
+	return h.Size() / uint64(n)
+}
+
 func (h *Head) loop() {
 	symdbMetricsUpdateTicker := time.NewTicker(5 * time.Second)
 	var memStats symdb.MemoryStats
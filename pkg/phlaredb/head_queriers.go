@@ -68,8 +68,13 @@ func (q *headOnDiskQuerier) SelectMatchingProfiles(ctx context.Context, params *
 	var (
 		profiles []Profile
 		buf      = make([][]parquet.Value, 2)
+		limiter  = QueryLimiterFromContext(ctx)
+		size     = q.head.bytesPerProfile()
 	)
 	for pIt.Next() {
+		if err = limiter.AddProfiles(1, size); err != nil {
+			return nil, err
+		}
 		res := pIt.At()
 
 		v, ok := res.Entries[0].RowValue.(fingerprintWithRowNum)
@@ -134,7 +139,7 @@ func (q *headOnDiskQuerier) SelectMergeByStacktraces(ctx context.Context, params
 		),
 		q.rowGroup().columnIter(ctx, "StacktracePartition", nil, "StacktracePartition"))
 
-	rows := profileRowBatchIterator(it)
+	rows := profileRowBatchIterator(limitProfiles(ctx, it, q.head.bytesPerProfile()))
 	defer rows.Close()
 
 	r := symdb.NewResolver(ctx, q.head.symdb, symdb.WithResolverMaxNodes(maxNodes))
@@ -179,7 +184,7 @@ func (q *headOnDiskQuerier) SelectMergeBySpans(ctx context.Context, params *inge
 		),
 		q.rowGroup().columnIter(ctx, "StacktracePartition", nil, "StacktracePartition"))
 
-	rows := profileRowBatchIterator(it)
+	rows := profileRowBatchIterator(limitProfiles(ctx, it, q.head.bytesPerProfile()))
 	defer rows.Close()
 
 	r := symdb.NewResolver(ctx, q.head.symdb)
@@ -214,7 +219,7 @@ func (q *headOnDiskQuerier) SelectMergePprof(ctx context.Context, params *ingest
 		),
 		q.rowGroup().columnIter(ctx, "StacktracePartition", nil, "StacktracePartition"))
 
-	rows := profileRowBatchIterator(it)
+	rows := profileRowBatchIterator(limitProfiles(ctx, it, q.head.bytesPerProfile()))
 	defer rows.Close()
 
 	r := symdb.NewResolver(ctx, q.head.symdb,
@@ -303,7 +308,7 @@ func (q *headOnDiskQuerier) SelectMergeByLabels(ctx context.Context, params *ing
 	)
 
 	if len(sts.GetCallSite()) == 0 {
-		rows := profileBatchIteratorByFingerprints(it, labelsPerFP)
+		rows := profileBatchIteratorByFingerprints(limitProfiles(ctx, it, q.head.bytesPerProfile()), labelsPerFP)
 		defer rows.Close()
 		return mergeByLabels[Profile](ctx, q.rowGroup(), "TotalValue", rows, by...)
 	}
@@ -313,7 +318,7 @@ func (q *headOnDiskQuerier) SelectMergeByLabels(ctx context.Context, params *ing
 	defer r.Release()
 
 	it = query.NewBinaryJoinIterator(0, it, q.rowGroup().columnIter(ctx, "StacktracePartition", nil, "StacktracePartition"))
-	rows := profileBatchIteratorByFingerprints(it, labelsPerFP)
+	rows := profileBatchIteratorByFingerprints(limitProfiles(ctx, it, q.head.bytesPerProfile()), labelsPerFP)
 	defer rows.Close()
 
 	return mergeByLabelsWithStackTraceSelector[Profile](ctx, q.rowGroup(), rows, r, by...)
@@ -245,6 +245,10 @@ outer:
 			}
 		}
 
+		if err = QueryLimiterFromContext(ctx).AddSeries(fp); err != nil {
+			return nil, err
+		}
+
 		// keep this one
 		ids[idx] = fp
 		idx++
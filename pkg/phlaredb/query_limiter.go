@@ -0,0 +1,150 @@
+package phlaredb
+
+import (
+	"context"
+	"sync"
+
+	"connectrpc.com/connect"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/pyroscope/pkg/iter"
+	"github.com/grafana/pyroscope/pkg/phlaredb/query"
+	"github.com/grafana/pyroscope/pkg/validation"
+)
+
+const queryLimiterContextKey contextKey = iota + 16
+
+// QueryLimiter keeps track of the resources consumed by a single query
+// and fails the query once any of the query impact limits is exceeded.
+// A nil limiter imposes no limits.
+type QueryLimiter struct {
+	maxBytes    uint64
+	maxSeries   uint64
+	maxProfiles uint64
+
+	mu       sync.Mutex
+	series   map[model.Fingerprint]struct{}
+	profiles uint64
+	bytes    uint64
+	err      error
+}
+
+// NewQueryLimiter creates a limiter for a query of the tenant.
+// Nil is returned, if none of the limits is set.
+func NewQueryLimiter(limits validation.QueryImpactLimits, tenantID string) *QueryLimiter {
+	l := &QueryLimiter{
+		maxBytes:    uint64(max(0, limits.MaxQueryBytesScanned(tenantID))),
+		maxSeries:   uint64(max(0, limits.MaxQuerySeries(tenantID))),
+		maxProfiles: uint64(max(0, limits.MaxQueryProfiles(tenantID))),
+	}
+	if l.maxBytes == 0 && l.maxSeries == 0 && l.maxProfiles == 0 {
+		return nil
+	}
+	l.series = make(map[model.Fingerprint]struct{})
+	return l
+}
+
+func ContextWithQueryLimiter(ctx context.Context, l *QueryLimiter) context.Context {
+	if l == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, queryLimiterContextKey, l)
+}
+
+func QueryLimiterFromContext(ctx context.Context) *QueryLimiter {
+	l, _ := ctx.Value(queryLimiterContextKey).(*QueryLimiter)
+	return l
+}
+
+// AddSeries accounts for a series selected by the query. The same
+// series may be selected from multiple blocks: it is only counted once.
+func (l *QueryLimiter) AddSeries(fp model.Fingerprint) error {
+	if l == nil || l.maxSeries == 0 {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.err != nil {
+		return l.err
+	}
+	l.series[fp] = struct{}{}
+	if n := uint64(len(l.series)); n > l.maxSeries {
+		l.err = connect.NewError(connect.CodeInvalidArgument,
+			validation.NewErrorf(validation.QueryLimit, validation.QueryTooManySeriesErrorMsg, n, l.maxSeries))
+	}
+	return l.err
+}
+
+// AddProfiles accounts for n profiles read by the query,
+// each of which is estimated to take bytesPerProfile bytes.
+func (l *QueryLimiter) AddProfiles(n, bytesPerProfile uint64) error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.err != nil {
+		return l.err
+	}
+	l.profiles += n
+	l.bytes += n * bytesPerProfile
+	switch {
+	case l.maxProfiles > 0 && l.profiles > l.maxProfiles:
+		l.err = connect.NewError(connect.CodeInvalidArgument,
+			validation.NewErrorf(validation.QueryLimit, validation.QueryTooManyProfilesErrorMsg, l.profiles, l.maxProfiles))
+	case l.maxBytes > 0 && l.bytes > l.maxBytes:
+		l.err = connect.NewError(connect.CodeInvalidArgument,
+			validation.NewErrorf(validation.QueryLimit, validation.QueryTooManyBytesErrorMsg, l.bytes, l.maxBytes))
+	}
+	return l.err
+}
+
+// Err returns the error, if any of the limits has been exceeded.
+func (l *QueryLimiter) Err() error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.err
+}
+
+// limitProfiles returns an iterator over the profile rows that stops once
+// the query limits are exceeded. Note that the merge functions may not
+// propagate the iterator error: the limiter error has to be checked
+// once the query is completed.
+func limitProfiles(ctx context.Context, it iter.Iterator[*query.IteratorResult], bytesPerProfile uint64) iter.Iterator[*query.IteratorResult] {
+	l := QueryLimiterFromContext(ctx)
+	if l == nil {
+		return it
+	}
+	return &profilesLimitIterator{
+		Iterator:        it,
+		limiter:         l,
+		bytesPerProfile: bytesPerProfile,
+	}
+}
+
+type profilesLimitIterator struct {
+	iter.Iterator[*query.IteratorResult]
+	limiter         *QueryLimiter
+	bytesPerProfile uint64
+	err             error
+}
+
+func (it *profilesLimitIterator) Next() bool {
+	if it.err != nil || !it.Iterator.Next() {
+		return false
+	}
+	if it.err = it.limiter.AddProfiles(1, it.bytesPerProfile); it.err != nil {
+		return false
+	}
+	return true
+}
+
+func (it *profilesLimitIterator) Err() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.Iterator.Err()
+}
@@ -0,0 +1,132 @@
+package phlaredb
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ingestv1 "github.com/grafana/pyroscope/api/gen/proto/go/ingester/v1"
+	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+	phlaremodel "github.com/grafana/pyroscope/pkg/model"
+	"github.com/grafana/pyroscope/pkg/validation"
+)
+
+func Test_QueryLimiter(t *testing.T) {
+	var l *QueryLimiter
+	require.NoError(t, l.AddSeries(1))
+	require.NoError(t, l.AddProfiles(1, 1))
+	require.NoError(t, l.Err())
+	assert.Nil(t, NewQueryLimiter(validation.MockLimits{}, "tenant"))
+
+	l = NewQueryLimiter(validation.MockLimits{MaxQuerySeriesValue: 2}, "tenant")
+	require.NoError(t, l.AddSeries(1))
+	require.NoError(t, l.AddSeries(2))
+	// The same series selected from another block.
+	require.NoError(t, l.AddSeries(1))
+	err := l.AddSeries(3)
+	require.Error(t, err)
+	assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+	assert.Contains(t, err.Error(), "max_query_series")
+	assert.Equal(t, err, l.Err())
+
+	l = NewQueryLimiter(validation.MockLimits{MaxQueryProfilesValue: 10}, "tenant")
+	require.NoError(t, l.AddProfiles(10, 100))
+	err = l.AddProfiles(1, 100)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "max_query_profiles")
+
+	l = NewQueryLimiter(validation.MockLimits{MaxQueryBytesScannedValue: 1000}, "tenant")
+	require.NoError(t, l.AddProfiles(10, 100))
+	err = l.AddProfiles(1, 100)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "max_query_bytes_scanned")
+}
+
+func Test_QueryLimiter_Head(t *testing.T) {
+	ctx := testContext(t)
+	const n = 15
+	head, err := NewHead(ctx, Config{
+		DataPath: t.TempDir(),
+		Parquet: &ParquetConfig{
+			MaxBufferRowCount: n - 1,
+		},
+	}, NoLimit)
+	require.NoError(t, err)
+
+	c := make(chan struct{})
+	var closeOnce sync.Once
+	head.profiles.onFlush = func() {
+		closeOnce.Do(func() {
+			close(c)
+		})
+	}
+
+	now := time.Now()
+	for i := 0; i < n; i++ {
+		x := newProfileFoo()
+		x.TimeNanos = now.Add(time.Second * time.Duration(i)).UnixNano()
+		require.NoError(t, head.Ingest(ctx, x, uuid.UUID{}, []*typesv1.LabelPair{
+			{Name: "job", Value: "foo"},
+			{Name: "x", Value: strconv.Itoa(i)},
+		}...))
+	}
+
+	<-c
+	q := head.Queriers()
+	require.Equal(t, 2, len(q)) // on-disk and in-memory parts.
+
+	typ, err := phlaremodel.ParseProfileTypeSelector(":type:unit:type:unit")
+	require.NoError(t, err)
+	req := &ingestv1.SelectProfilesRequest{
+		LabelSelector: "{}",
+		Type:          typ,
+		End:           now.Add(time.Hour).UnixMilli(),
+	}
+
+	selectProfiles := func(ctx context.Context) error {
+		for _, b := range q {
+			if _, err := b.SelectMatchingProfiles(ctx, req); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	require.NoError(t, selectProfiles(ContextWithQueryLimiter(ctx,
+		NewQueryLimiter(validation.MockLimits{MaxQuerySeriesValue: n, MaxQueryProfilesValue: n}, "tenant"))))
+
+	err = selectProfiles(ContextWithQueryLimiter(ctx,
+		NewQueryLimiter(validation.MockLimits{MaxQuerySeriesValue: n - 1}, "tenant")))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "max_query_series")
+
+	err = selectProfiles(ContextWithQueryLimiter(ctx,
+		NewQueryLimiter(validation.MockLimits{MaxQueryProfilesValue: 5}, "tenant")))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "max_query_profiles")
+
+	// The bytes scanned are estimated from the size of the head.
+	require.NotZero(t, head.bytesPerProfile())
+	err = selectProfiles(ContextWithQueryLimiter(ctx,
+		NewQueryLimiter(validation.MockLimits{MaxQueryBytesScannedValue: int(head.bytesPerProfile())}, "tenant")))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "max_query_bytes_scanned")
+
+	// The limit is enforced when the profiles are merged, too.
+	limitedCtx := ContextWithQueryLimiter(ctx,
+		NewQueryLimiter(validation.MockLimits{MaxQueryBytesScannedValue: int(head.bytesPerProfile())}, "tenant"))
+	for _, b := range q {
+		if _, err = b.SelectMergeByStacktraces(limitedCtx, req, 0); err != nil {
+			break
+		}
+	}
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "max_query_bytes_scanned")
+}
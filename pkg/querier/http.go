@@ -10,6 +10,8 @@ import (
 	"strings"
 
 	"connectrpc.com/connect"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
 	"github.com/gogo/status"
 	"github.com/google/pprof/profile"
 	"github.com/prometheus/common/model"
@@ -17,7 +19,10 @@ import (
 	"github.com/prometheus/prometheus/promql/parser"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/encoding/protojson"
 
+	annotationsv1 "github.com/grafana/pyroscope/api/gen/proto/go/annotations/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/annotations/v1/annotationsv1connect"
 	profilev1 "github.com/grafana/pyroscope/api/gen/proto/go/google/v1"
 	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
 	"github.com/grafana/pyroscope/api/gen/proto/go/querier/v1/querierv1connect"
@@ -28,15 +33,29 @@ import (
 	"github.com/grafana/pyroscope/pkg/og/structs/flamebearer"
 	"github.com/grafana/pyroscope/pkg/og/util/attime"
 	"github.com/grafana/pyroscope/pkg/querier/timeline"
+	"github.com/grafana/pyroscope/pkg/util"
 	httputil "github.com/grafana/pyroscope/pkg/util/http"
 )
 
-func NewHTTPHandlers(client querierv1connect.QuerierServiceClient) *QueryHandlers {
-	return &QueryHandlers{client}
+// NewHTTPHandlers creates the pyroscope HTTP handlers. The annotations
+// client is optional: if it is nil, render requests asking for annotations
+// return none.
+func NewHTTPHandlers(client querierv1connect.QuerierServiceClient, annotations annotationsv1connect.AnnotationServiceClient, logger log.Logger) *QueryHandlers {
+	return &QueryHandlers{client: client, annotations: annotations, logger: logger}
 }
 
 type QueryHandlers struct {
-	client querierv1connect.QuerierServiceClient
+	client      querierv1connect.QuerierServiceClient
+	annotations annotationsv1connect.AnnotationServiceClient
+	logger      log.Logger
+}
+
+// renderResponse is the flamebearer profile returned by the render handler,
+// with the annotations overlapping the requested time range. Annotations are
+// encoded with protojson, as in the annotations API responses.
+type renderResponse struct {
+	*flamebearer.FlamebearerProfile
+	Annotations []json.RawMessage `json:"annotations,omitempty"`
 }
 
 // LabelValues only returns the label values for the given label name.
@@ -204,11 +223,31 @@ func (q *QueryHandlers) Render(w http.ResponseWriter, req *http.Request) {
 		return err
 	})
 
+	// Annotations are optional: the profile is rendered without
+	// them, if they can't be retrieved.
+	var (
+		resAnnotations *connect.Response[annotationsv1.ListAnnotationsResponse]
+		annotationsErr error
+	)
+	if q.annotations != nil && req.URL.Query().Get("annotations") == "true" {
+		g.Go(func() error {
+			resAnnotations, annotationsErr = q.annotations.List(gCtx, connect.NewRequest(&annotationsv1.ListAnnotationsRequest{
+				Start:         selectParams.Start,
+				End:           selectParams.End,
+				LabelSelector: selectParams.LabelSelector,
+			}))
+			return nil
+		})
+	}
+
 	err = g.Wait()
 	if err != nil {
 		httputil.Error(w, err)
 		return
 	}
+	if annotationsErr != nil {
+		level.Warn(util.LoggerWithContext(req.Context(), q.logger)).Log("msg", "failed to list annotations", "err", annotationsErr)
+	}
 
 	seriesVal := &typesv1.Series{}
 	if len(resSeries.Msg.Series) == 1 {
@@ -233,8 +272,21 @@ func (q *QueryHandlers) Render(w http.ResponseWriter, req *http.Request) {
 		}
 	}
 
+	res := renderResponse{FlamebearerProfile: fb}
+	if resAnnotations != nil {
+		res.Annotations = make([]json.RawMessage, 0, len(resAnnotations.Msg.Annotations))
+		for _, a := range resAnnotations.Msg.Annotations {
+			b, err := protojson.Marshal(a)
+			if err != nil {
+				httputil.Error(w, connect.NewError(connect.CodeInternal, err))
+				return
+			}
+			res.Annotations = append(res.Annotations, b)
+		}
+	}
+
 	w.Header().Add("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(fb); err != nil {
+	if err := json.NewEncoder(w).Encode(res); err != nil {
 		httputil.Error(w, err)
 		return
 	}
@@ -1,16 +1,27 @@
 package querier
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
 	"time"
 
+	"connectrpc.com/connect"
+	"github.com/grafana/dskit/user"
 	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	annotationsv1 "github.com/grafana/pyroscope/api/gen/proto/go/annotations/v1"
+	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
 	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+	"github.com/grafana/pyroscope/pkg/annotations"
+	"github.com/grafana/pyroscope/pkg/test/mocks/mockquerierv1connect"
+	"github.com/grafana/pyroscope/pkg/util"
 )
 
 func Test_ParseQuery(t *testing.T) {
@@ -40,3 +51,59 @@ func Test_ParseQuery(t *testing.T) {
 
 	require.Equal(t, `{foo="bar",bar=~"buzz"}`, queryRequest.LabelSelector)
 }
+
+func Test_RenderAnnotations(t *testing.T) {
+	client := mockquerierv1connect.NewMockQuerierServiceClient(t)
+	client.EXPECT().SelectMergeStacktraces(mock.Anything, mock.Anything).
+		Return(connect.NewResponse(&querierv1.SelectMergeStacktracesResponse{
+			Flamegraph: &querierv1.FlameGraph{Names: []string{"total"}, Levels: []*querierv1.Level{{Values: []int64{0, 1, 1, 0}}}},
+		}), nil)
+	client.EXPECT().SelectSeries(mock.Anything, mock.Anything).
+		Return(connect.NewResponse(&querierv1.SelectSeriesResponse{}), nil)
+
+	store, err := annotations.NewMemoryStore()
+	require.NoError(t, err)
+	svc, err := annotations.New(store, util.Logger)
+	require.NoError(t, err)
+	ctx := user.InjectOrgID(context.Background(), "tenant")
+	now := time.Now().UnixMilli()
+	_, err = svc.Create(ctx, connect.NewRequest(&annotationsv1.CreateAnnotationRequest{
+		Start:         now - time.Hour.Milliseconds(),
+		LabelSelector: `{service_name="svc"}`,
+		Text:          "deploy",
+	}))
+	require.NoError(t, err)
+
+	q := url.Values{
+		"query":       []string{`process_cpu:cpu:nanoseconds:cpu:nanoseconds{service_name="svc"}`},
+		"from":        []string{"now-6h"},
+		"until":       []string{"now"},
+		"annotations": []string{"true"},
+	}
+	req := httptest.NewRequest("GET", "/pyroscope/render?"+q.Encode(), nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	handlers := NewHTTPHandlers(client, svc, util.Logger)
+	handlers.Render(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var res struct {
+		Flamebearer json.RawMessage  `json:"flamebearer"`
+		Annotations []map[string]any `json:"annotations"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	require.Len(t, res.Annotations, 1)
+	require.Equal(t, "deploy", res.Annotations[0]["text"])
+	require.Equal(t, `{service_name="svc"}`, res.Annotations[0]["labelSelector"])
+	require.Equal(t, fmt.Sprint(now-time.Hour.Milliseconds()), res.Annotations[0]["start"])
+
+	// The profile is rendered without annotations, if they can't be listed:
+	// the annotations service requires the tenant ID.
+	req = httptest.NewRequest("GET", "/pyroscope/render?"+q.Encode(), nil)
+	rec = httptest.NewRecorder()
+	handlers.Render(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	res.Flamebearer, res.Annotations = nil, nil
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	require.NotEmpty(t, res.Flamebearer)
+	require.Empty(t, res.Annotations)
+}
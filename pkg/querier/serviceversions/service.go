@@ -0,0 +1,247 @@
+package serviceversions
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+
+	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/querier/v1/querierv1connect"
+	serviceversionsv1 "github.com/grafana/pyroscope/api/gen/proto/go/serviceversions/v1"
+	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+	vcsv1 "github.com/grafana/pyroscope/api/gen/proto/go/vcs/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/vcs/v1/vcsv1connect"
+	phlaremodel "github.com/grafana/pyroscope/pkg/model"
+	"github.com/grafana/pyroscope/pkg/querier/timeline"
+	"github.com/grafana/pyroscope/pkg/querier/vcs"
+)
+
+// Service lists the versions of a service and diffs their profiles. It is
+// built on top of the querier and the VCS services.
+type Service struct {
+	logger  log.Logger
+	querier querierv1connect.QuerierServiceClient
+	vcs     vcsv1connect.VCSServiceClient
+}
+
+func New(logger log.Logger, querier querierv1connect.QuerierServiceClient, vcs vcsv1connect.VCSServiceClient) *Service {
+	return &Service{
+		logger:  logger,
+		querier: querier,
+		vcs:     vcs,
+	}
+}
+
+// version is a service version along with the label identifying it.
+type version struct {
+	*serviceversionsv1.ServiceVersion
+	labelName string
+}
+
+func (q *Service) List(ctx context.Context, req *connect.Request[serviceversionsv1.ListServiceVersionsRequest]) (*connect.Response[serviceversionsv1.ListServiceVersionsResponse], error) {
+	if err := validateRequest(req.Msg.ServiceName, req.Msg.ProfileTypeID, req.Msg.Start, req.Msg.End); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	versions, err := q.versions(ctx, req.Msg.ServiceName, req.Msg.ProfileTypeID, req.Msg.Start, req.Msg.End)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &serviceversionsv1.ListServiceVersionsResponse{
+		Versions: make([]*serviceversionsv1.ServiceVersion, 0, len(versions)),
+	}
+	for _, v := range versions {
+		res.Versions = append(res.Versions, v.ServiceVersion)
+	}
+	return connect.NewResponse(res), nil
+}
+
+func (q *Service) Diff(ctx context.Context, req *connect.Request[serviceversionsv1.DiffServiceVersionsRequest]) (*connect.Response[serviceversionsv1.DiffServiceVersionsResponse], error) {
+	if err := validateRequest(req.Msg.ServiceName, req.Msg.ProfileTypeID, req.Msg.Start, req.Msg.End); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if req.Msg.LeftVersion == "" || req.Msg.RightVersion == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("both a left and a right version are required"))
+	}
+
+	versions, err := q.versions(ctx, req.Msg.ServiceName, req.Msg.ProfileTypeID, req.Msg.Start, req.Msg.End)
+	if err != nil {
+		return nil, err
+	}
+	left, err := findVersion(versions, req.Msg.LeftVersion)
+	if err != nil {
+		return nil, err
+	}
+	right, err := findVersion(versions, req.Msg.RightVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := q.querier.Diff(ctx, connect.NewRequest(&querierv1.DiffRequest{
+		Left:  selectVersion(req.Msg, left),
+		Right: selectVersion(req.Msg, right),
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	return connect.NewResponse(&serviceversionsv1.DiffServiceVersionsResponse{
+		Flamegraph:  diff.Msg.Flamegraph,
+		Left:        left.ServiceVersion,
+		Right:       right.ServiceVersion,
+		LeftCommit:  q.commit(ctx, req.Header(), left),
+		RightCommit: q.commit(ctx, req.Header(), right),
+	}), nil
+}
+
+// versions returns the versions of the service seen within the time range,
+// ordered by the time they were first seen. The time span of a version is
+// approximated with the resolution of the timeline of the time range.
+func (q *Service) versions(ctx context.Context, serviceName, profileTypeID string, start, end int64) ([]*version, error) {
+	step := timeline.CalcPointInterval(start, end)
+	resp, err := q.querier.SelectSeries(ctx, connect.NewRequest(&querierv1.SelectSeriesRequest{
+		ProfileTypeID: profileTypeID,
+		LabelSelector: serviceSelector(serviceName),
+		Start:         start,
+		End:           end,
+		Step:          step,
+		GroupBy: []string{
+			phlaremodel.LabelNameServiceVersion,
+			phlaremodel.LabelNameServiceGitRef,
+			phlaremodel.LabelNameServiceRepository,
+		},
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	stepMs := time.Duration(step * float64(time.Second)).Milliseconds()
+	byVersion := make(map[string]*version)
+	for _, s := range resp.Msg.Series {
+		if len(s.Points) == 0 {
+			continue
+		}
+		v := versionFromSeries(s)
+		if v == nil {
+			continue
+		}
+		firstSeen := s.Points[0].Timestamp
+		lastSeen := min(s.Points[len(s.Points)-1].Timestamp+stepMs, end)
+		existing, ok := byVersion[v.Version]
+		if !ok {
+			v.FirstSeen, v.LastSeen = firstSeen, lastSeen
+			byVersion[v.Version] = v
+			continue
+		}
+		existing.FirstSeen = min(existing.FirstSeen, firstSeen)
+		existing.LastSeen = max(existing.LastSeen, lastSeen)
+		if existing.Repository == "" {
+			existing.Repository = v.Repository
+		}
+		if existing.GitRef == "" {
+			existing.GitRef = v.GitRef
+		}
+	}
+
+	versions := make([]*version, 0, len(byVersion))
+	for _, v := range byVersion {
+		versions = append(versions, v)
+	}
+	slices.SortFunc(versions, func(a, b *version) int {
+		if a.FirstSeen != b.FirstSeen {
+			if a.FirstSeen < b.FirstSeen {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Version, b.Version)
+	})
+	return versions, nil
+}
+
+// commit returns the commit of the version, if it can be retrieved. Only
+// the VCS session cookies of the caller are passed to the VCS service, the
+// other credentials are not. Failing to retrieve the commit, e.g. because
+// the user is not logged in to the VCS provider, does not fail the request.
+func (q *Service) commit(ctx context.Context, header http.Header, v *version) *vcsv1.GetCommitResponse {
+	if q.vcs == nil || v.Repository == "" || v.GitRef == "" {
+		return nil
+	}
+	req := connect.NewRequest(&vcsv1.GetCommitRequest{
+		RepositoryURL: v.Repository,
+		Ref:           v.GitRef,
+	})
+	for _, c := range vcs.SessionCookies(header) {
+		req.Header().Add("Cookie", c.String())
+	}
+	resp, err := q.vcs.GetCommit(ctx, req)
+	if err != nil {
+		level.Debug(q.logger).Log("msg", "failed to get commit", "repository", v.Repository, "ref", v.GitRef, "err", err)
+		return nil
+	}
+	return resp.Msg
+}
+
+func versionFromSeries(s *typesv1.Series) *version {
+	ls := phlaremodel.Labels(s.Labels)
+	v := &version{
+		ServiceVersion: &serviceversionsv1.ServiceVersion{
+			Version:    ls.Get(phlaremodel.LabelNameServiceVersion),
+			Repository: ls.Get(phlaremodel.LabelNameServiceRepository),
+			GitRef:     ls.Get(phlaremodel.LabelNameServiceGitRef),
+		},
+		labelName: phlaremodel.LabelNameServiceVersion,
+	}
+	if v.Version == "" {
+		v.Version = v.GitRef
+		v.labelName = phlaremodel.LabelNameServiceGitRef
+	}
+	if v.Version == "" {
+		return nil
+	}
+	return v
+}
+
+func findVersion(versions []*version, name string) (*version, error) {
+	for _, v := range versions {
+		if v.Version == name {
+			return v, nil
+		}
+	}
+	return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("version %q not found", name))
+}
+
+func selectVersion(req *serviceversionsv1.DiffServiceVersionsRequest, v *version) *querierv1.SelectMergeStacktracesRequest {
+	return &querierv1.SelectMergeStacktracesRequest{
+		ProfileTypeID: req.ProfileTypeID,
+		LabelSelector: fmt.Sprintf(`{%s=%q,%s=%q}`, phlaremodel.LabelNameServiceName, req.ServiceName, v.labelName, v.Version),
+		Start:         v.FirstSeen,
+		End:           v.LastSeen,
+		MaxNodes:      req.MaxNodes,
+	}
+}
+
+func serviceSelector(serviceName string) string {
+	return fmt.Sprintf(`{%s=%q}`, phlaremodel.LabelNameServiceName, serviceName)
+}
+
+func validateRequest(serviceName, profileTypeID string, start, end int64) error {
+	if serviceName == "" {
+		return fmt.Errorf("service name is required")
+	}
+	if profileTypeID == "" {
+		return fmt.Errorf("profile type is required")
+	}
+	if start <= 0 || end <= start {
+		return fmt.Errorf("a valid time range is required")
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package serviceversions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/require"
+
+	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/querier/v1/querierv1connect"
+	serviceversionsv1 "github.com/grafana/pyroscope/api/gen/proto/go/serviceversions/v1"
+	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+	vcsv1 "github.com/grafana/pyroscope/api/gen/proto/go/vcs/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/vcs/v1/vcsv1connect"
+)
+
+const (
+	minute = int64(60 * 1000)
+	hour   = 60 * minute
+)
+
+type fakeQuerier struct {
+	querierv1connect.UnimplementedQuerierServiceHandler
+
+	series      []*typesv1.Series
+	diffRequest *querierv1.DiffRequest
+}
+
+func (f *fakeQuerier) SelectSeries(_ context.Context, _ *connect.Request[querierv1.SelectSeriesRequest]) (*connect.Response[querierv1.SelectSeriesResponse], error) {
+	return connect.NewResponse(&querierv1.SelectSeriesResponse{Series: f.series}), nil
+}
+
+func (f *fakeQuerier) Diff(_ context.Context, req *connect.Request[querierv1.DiffRequest]) (*connect.Response[querierv1.DiffResponse], error) {
+	f.diffRequest = req.Msg
+	return connect.NewResponse(&querierv1.DiffResponse{Flamegraph: &querierv1.FlameGraphDiff{Total: 1}}), nil
+}
+
+type fakeVCS struct {
+	vcsv1connect.UnimplementedVCSServiceHandler
+}
+
+func (f *fakeVCS) GetCommit(_ context.Context, req *connect.Request[vcsv1.GetCommitRequest]) (*connect.Response[vcsv1.GetCommitResponse], error) {
+	if req.Header().Get("Authorization") != "" || strings.Contains(req.Header().Get("Cookie"), "grafana_session") {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("user credentials must not be passed"))
+	}
+	// Like the VCS service, the commit is retrieved with the session
+	// token of the provider.
+	if req.Header().Get("Cookie") != "pyroscope_git_session=token" || req.Msg.Ref == "def456" {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+	return connect.NewResponse(&vcsv1.GetCommitResponse{Sha: req.Msg.Ref, Message: "commit " + req.Msg.Ref}), nil
+}
+
+func series(points []int64, ls ...string) *typesv1.Series {
+	s := &typesv1.Series{}
+	for i := 0; i < len(ls); i += 2 {
+		s.Labels = append(s.Labels, &typesv1.LabelPair{Name: ls[i], Value: ls[i+1]})
+	}
+	for _, ts := range points {
+		s.Points = append(s.Points, &typesv1.Point{Timestamp: ts, Value: 1})
+	}
+	return s
+}
+
+func newTestService() (*Service, *fakeQuerier) {
+	start := int64(1_700_000_000_000)
+	q := &fakeQuerier{
+		series: []*typesv1.Series{
+			series([]int64{start + 2*hour, start + 3*hour},
+				"service_git_ref", "def456", "service_repository", "https://github.com/grafana/pyroscope", "service_version", "v2"),
+			series([]int64{start, start + hour},
+				"service_git_ref", "abc123", "service_repository", "https://github.com/grafana/pyroscope", "service_version", "v1"),
+			series([]int64{start + hour},
+				"service_git_ref", "abc124", "service_version", "v1"),
+			series([]int64{start + 4*hour},
+				"service_git_ref", "0123abc"),
+			series([]int64{start}),
+		},
+	}
+	return New(log.NewNopLogger(), q, &fakeVCS{}), q
+}
+
+func TestService_List(t *testing.T) {
+	svc, _ := newTestService()
+	start := int64(1_700_000_000_000)
+
+	resp, err := svc.List(context.Background(), connect.NewRequest(&serviceversionsv1.ListServiceVersionsRequest{
+		ServiceName:   "pyroscope",
+		ProfileTypeID: "process_cpu:cpu:nanoseconds:cpu:nanoseconds",
+		Start:         start,
+		End:           start + 6*hour,
+	}))
+	require.NoError(t, err)
+
+	// The timeline of a 6 hours range has a 15 seconds resolution.
+	step := 15 * int64(1000)
+	require.Equal(t, []*serviceversionsv1.ServiceVersion{
+		{Version: "v1", Repository: "https://github.com/grafana/pyroscope", GitRef: "abc123", FirstSeen: start, LastSeen: start + hour + step},
+		{Version: "v2", Repository: "https://github.com/grafana/pyroscope", GitRef: "def456", FirstSeen: start + 2*hour, LastSeen: start + 3*hour + step},
+		{Version: "0123abc", GitRef: "0123abc", FirstSeen: start + 4*hour, LastSeen: start + 4*hour + step},
+	}, resp.Msg.Versions)
+
+	_, err = svc.List(context.Background(), connect.NewRequest(&serviceversionsv1.ListServiceVersionsRequest{
+		ProfileTypeID: "process_cpu:cpu:nanoseconds:cpu:nanoseconds",
+		Start:         start,
+		End:           start + 6*hour,
+	}))
+	require.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+}
+
+func TestService_Diff(t *testing.T) {
+	svc, q := newTestService()
+	start := int64(1_700_000_000_000)
+	step := 15 * int64(1000)
+
+	newRequest := func(left, right string) *connect.Request[serviceversionsv1.DiffServiceVersionsRequest] {
+		req := connect.NewRequest(&serviceversionsv1.DiffServiceVersionsRequest{
+			ServiceName:   "pyroscope",
+			ProfileTypeID: "process_cpu:cpu:nanoseconds:cpu:nanoseconds",
+			Start:         start,
+			End:           start + 6*hour,
+			LeftVersion:   left,
+			RightVersion:  right,
+		})
+		req.Header().Set("Cookie", "grafana_session=secret; pyroscope_git_session=token")
+		req.Header().Set("Authorization", "Bearer token")
+		return req
+	}
+
+	resp, err := svc.Diff(context.Background(), newRequest("v1", "0123abc"))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), resp.Msg.Flamegraph.Total)
+	require.Equal(t, "v1", resp.Msg.Left.Version)
+	require.Equal(t, "0123abc", resp.Msg.Right.Version)
+
+	require.Equal(t, `{service_name="pyroscope",service_version="v1"}`, q.diffRequest.Left.LabelSelector)
+	require.Equal(t, start, q.diffRequest.Left.Start)
+	require.Equal(t, start+hour+step, q.diffRequest.Left.End)
+	require.Equal(t, `{service_name="pyroscope",service_git_ref="0123abc"}`, q.diffRequest.Right.LabelSelector)
+	require.Equal(t, start+4*hour, q.diffRequest.Right.Start)
+
+	// Only versions with a repository have commit information.
+	require.Equal(t, "abc123", resp.Msg.LeftCommit.GetSha())
+	require.Nil(t, resp.Msg.RightCommit)
+
+	// Commit information is optional.
+	resp, err = svc.Diff(context.Background(), newRequest("v2", "v1"))
+	require.NoError(t, err)
+	require.Nil(t, resp.Msg.LeftCommit)
+	require.Equal(t, "abc123", resp.Msg.RightCommit.GetSha())
+
+	// Without the session cookie, the commit can't be retrieved.
+	req := newRequest("v1", "v2")
+	req.Header().Set("Cookie", "grafana_session=secret")
+	resp, err = svc.Diff(context.Background(), req)
+	require.NoError(t, err)
+	require.Nil(t, resp.Msg.LeftCommit)
+
+	_, err = svc.Diff(context.Background(), newRequest("v1", "v3"))
+	require.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+}
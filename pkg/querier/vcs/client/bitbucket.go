@@ -0,0 +1,115 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	"golang.org/x/oauth2"
+
+	vcsv1 "github.com/grafana/pyroscope/api/gen/proto/go/vcs/v1"
+)
+
+// BitbucketClient returns a client of the Bitbucket Cloud API. The web URL
+// is used to build links to the repository files.
+func BitbucketClient(ctx context.Context, token *oauth2.Token, client *http.Client, webURL, apiURL string) (*bitbucketClient, error) {
+	return &bitbucketClient{
+		api:    newAPIClient(client, token, apiURL),
+		webURL: strings.TrimSuffix(webURL, "/"),
+	}, nil
+}
+
+type bitbucketClient struct {
+	api    apiClient
+	webURL string
+}
+
+type bitbucketCommit struct {
+	Hash    string    `json:"hash"`
+	Message string    `json:"message"`
+	Date    time.Time `json:"date"`
+	Author  struct {
+		Raw  string `json:"raw"`
+		User struct {
+			Nickname    string `json:"nickname"`
+			DisplayName string `json:"display_name"`
+			Links       struct {
+				Avatar bitbucketLink `json:"avatar"`
+			} `json:"links"`
+		} `json:"user"`
+	} `json:"author"`
+	Links struct {
+		HTML bitbucketLink `json:"html"`
+	} `json:"links"`
+}
+
+type bitbucketLink struct {
+	Href string `json:"href"`
+}
+
+type bitbucketRepository struct {
+	MainBranch struct {
+		Name string `json:"name"`
+	} `json:"mainbranch"`
+}
+
+func (bb *bitbucketClient) GetCommit(ctx context.Context, owner, repo, ref string) (*vcsv1.CommitInfo, error) {
+	var commit bitbucketCommit
+	path := fmt.Sprintf("/repositories/%s/%s/commit/%s", url.PathEscape(owner), url.PathEscape(repo), url.PathEscape(ref))
+	if err := bb.api.getJSON(ctx, path, &commit); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("commit %s not found", ref))
+		}
+		return nil, err
+	}
+	login := commit.Author.User.Nickname
+	if login == "" {
+		login = commit.Author.User.DisplayName
+	}
+	if login == "" {
+		login = commit.Author.Raw
+	}
+	return &vcsv1.CommitInfo{
+		Sha:     commit.Hash,
+		Message: commit.Message,
+		Author: &vcsv1.CommitAuthor{
+			Login:     login,
+			AvatarURL: commit.Author.User.Links.Avatar.Href,
+		},
+		Date: commit.Date.Format(time.RFC3339),
+		URL:  commit.Links.HTML.Href,
+	}, nil
+}
+
+func (bb *bitbucketClient) GetFile(ctx context.Context, req FileRequest) (File, error) {
+	ref := req.Ref
+	if ref == "" || ref == "HEAD" {
+		// The source endpoint does not resolve HEAD:
+		// we need the name of the main branch instead.
+		var repo bitbucketRepository
+		path := fmt.Sprintf("/repositories/%s/%s", url.PathEscape(req.Owner), url.PathEscape(req.Repo))
+		if err := bb.api.getJSON(ctx, path, &repo); err != nil {
+			return File{}, err
+		}
+		ref = repo.MainBranch.Name
+	}
+	path := fmt.Sprintf("/repositories/%s/%s/src/%s/%s",
+		url.PathEscape(req.Owner),
+		url.PathEscape(req.Repo),
+		url.PathEscape(ref),
+		escapePath(req.Path),
+	)
+	content, err := bb.api.get(ctx, path)
+	if err != nil {
+		return File{}, err
+	}
+	return File{
+		Content: string(content),
+		URL:     fmt.Sprintf("%s/%s/%s/src/%s/%s", bb.webURL, req.Owner, req.Repo, ref, escapePath(req.Path)),
+	}, nil
+}
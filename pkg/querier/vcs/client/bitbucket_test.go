@@ -0,0 +1,63 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+func Test_bitbucketClient(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/2.0/repositories/grafana/pyroscope", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"mainbranch":{"name":"trunk"}}`))
+	})
+	mux.HandleFunc("/2.0/repositories/grafana/pyroscope/src/trunk/pkg/main.go", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte("package main"))
+	})
+	mux.HandleFunc("/2.0/repositories/grafana/pyroscope/commit/abcdef", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{
+			"hash": "abcdef",
+			"message": "initial commit",
+			"date": "2024-01-02T03:04:05+00:00",
+			"author": {"raw": "John <john@example.com>", "user": {"nickname": "john", "links": {"avatar": {"href": "https://example.com/john.png"}}}},
+			"links": {"html": {"href": "https://bitbucket.org/grafana/pyroscope/commits/abcdef"}}
+		}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c, err := BitbucketClient(context.Background(), &oauth2.Token{AccessToken: "token"}, server.Client(), "https://bitbucket.org", server.URL+"/2.0")
+	require.NoError(t, err)
+
+	file, err := c.GetFile(context.Background(), FileRequest{
+		Owner: "grafana",
+		Repo:  "pyroscope",
+		Path:  "pkg/main.go",
+		Ref:   "HEAD",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "package main", file.Content)
+	assert.Equal(t, "https://bitbucket.org/grafana/pyroscope/src/trunk/pkg/main.go", file.URL)
+
+	_, err = c.GetFile(context.Background(), FileRequest{Owner: "grafana", Repo: "pyroscope", Path: "missing.go", Ref: "trunk"})
+	require.ErrorIs(t, err, ErrNotFound)
+
+	commit, err := c.GetCommit(context.Background(), "grafana", "pyroscope", "abcdef")
+	require.NoError(t, err)
+	assert.Equal(t, "abcdef", commit.Sha)
+	assert.Equal(t, "john", commit.Author.Login)
+	assert.Equal(t, "https://example.com/john.png", commit.Author.AvatarURL)
+	assert.Equal(t, "2024-01-02T03:04:05Z", commit.Date)
+	assert.Equal(t, "https://bitbucket.org/grafana/pyroscope/commits/abcdef", commit.URL)
+
+	_, err = c.GetCommit(context.Background(), "grafana", "pyroscope", "unknown")
+	require.Error(t, err)
+	assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+}
@@ -0,0 +1,109 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	"golang.org/x/oauth2"
+
+	vcsv1 "github.com/grafana/pyroscope/api/gen/proto/go/vcs/v1"
+)
+
+// GiteaClient returns a client of the Gitea instance at the given base URL.
+func GiteaClient(ctx context.Context, token *oauth2.Token, client *http.Client, baseURL string) (*giteaClient, error) {
+	return &giteaClient{
+		api: newAPIClient(client, token, strings.TrimSuffix(baseURL, "/")+"/api/v1"),
+	}, nil
+}
+
+type giteaClient struct {
+	api apiClient
+}
+
+type giteaCommit struct {
+	SHA     string `json:"sha"`
+	HTMLURL string `json:"html_url"`
+	Commit  struct {
+		Message string `json:"message"`
+		Author  struct {
+			Name string    `json:"name"`
+			Date time.Time `json:"date"`
+		} `json:"author"`
+	} `json:"commit"`
+	Author *struct {
+		Login     string `json:"login"`
+		AvatarURL string `json:"avatar_url"`
+	} `json:"author"`
+}
+
+type giteaContents struct {
+	Type     string `json:"type"`
+	Content  string `json:"content"`
+	Encoding string `json:"encoding"`
+	HTMLURL  string `json:"html_url"`
+}
+
+func (gt *giteaClient) GetCommit(ctx context.Context, owner, repo, ref string) (*vcsv1.CommitInfo, error) {
+	var commits []giteaCommit
+	path := fmt.Sprintf("/repos/%s/%s/commits?sha=%s&limit=1&stat=false",
+		url.PathEscape(owner), url.PathEscape(repo), url.QueryEscape(ref))
+	err := gt.api.getJSON(ctx, path, &commits)
+	if err == nil && len(commits) == 0 {
+		err = ErrNotFound
+	}
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("commit %s not found", ref))
+		}
+		return nil, err
+	}
+	commit := commits[0]
+	author := &vcsv1.CommitAuthor{Login: commit.Commit.Author.Name}
+	if commit.Author != nil {
+		author.Login = commit.Author.Login
+		author.AvatarURL = commit.Author.AvatarURL
+	}
+	return &vcsv1.CommitInfo{
+		Sha:     commit.SHA,
+		Message: commit.Commit.Message,
+		Author:  author,
+		Date:    commit.Commit.Author.Date.Format(time.RFC3339),
+		URL:     commit.HTMLURL,
+	}, nil
+}
+
+func (gt *giteaClient) GetFile(ctx context.Context, req FileRequest) (File, error) {
+	path := fmt.Sprintf("/repos/%s/%s/contents/%s",
+		url.PathEscape(req.Owner), url.PathEscape(req.Repo), escapePath(req.Path))
+	// The default branch is used, if ref is omitted.
+	if req.Ref != "" && req.Ref != "HEAD" {
+		path += "?ref=" + url.QueryEscape(req.Ref)
+	}
+	var file giteaContents
+	if err := gt.api.getJSON(ctx, path, &file); err != nil {
+		return File{}, err
+	}
+	// We only support files retrieval.
+	if file.Type != "file" {
+		return File{}, connect.NewError(connect.CodeInvalidArgument, errors.New("path is not a file"))
+	}
+	content := file.Content
+	if file.Encoding == "base64" {
+		decoded, err := base64.StdEncoding.DecodeString(file.Content)
+		if err != nil {
+			return File{}, fmt.Errorf("failed to decode file content: %w", err)
+		}
+		content = string(decoded)
+	}
+	return File{
+		Content: content,
+		URL:     file.HTMLURL,
+	}, nil
+}
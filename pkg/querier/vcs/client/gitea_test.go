@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+func Test_giteaClient(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/repos/grafana/pyroscope/contents/pkg/main.go", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		assert.False(t, r.URL.Query().Has("ref"))
+		_, _ = w.Write([]byte(`{
+			"type": "file",
+			"encoding": "base64",
+			"content": "` + base64.StdEncoding.EncodeToString([]byte("package main")) + `",
+			"html_url": "https://gitea.example.com/grafana/pyroscope/src/branch/main/pkg/main.go"
+		}`))
+	})
+	mux.HandleFunc("/api/v1/repos/grafana/pyroscope/commits", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("sha") != "main" {
+			_, _ = w.Write([]byte(`[]`))
+			return
+		}
+		_, _ = w.Write([]byte(`[{
+			"sha": "abcdef",
+			"html_url": "https://gitea.example.com/grafana/pyroscope/commit/abcdef",
+			"commit": {"message": "initial commit", "author": {"name": "John", "date": "2024-01-02T03:04:05Z"}},
+			"author": {"login": "john", "avatar_url": "https://example.com/john.png"}
+		}]`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c, err := GiteaClient(context.Background(), &oauth2.Token{AccessToken: "token"}, server.Client(), server.URL)
+	require.NoError(t, err)
+
+	file, err := c.GetFile(context.Background(), FileRequest{
+		Owner: "grafana",
+		Repo:  "pyroscope",
+		Path:  "pkg/main.go",
+		Ref:   "HEAD",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "package main", file.Content)
+	assert.Equal(t, "https://gitea.example.com/grafana/pyroscope/src/branch/main/pkg/main.go", file.URL)
+
+	_, err = c.GetFile(context.Background(), FileRequest{Owner: "grafana", Repo: "pyroscope", Path: "missing.go"})
+	require.ErrorIs(t, err, ErrNotFound)
+
+	commit, err := c.GetCommit(context.Background(), "grafana", "pyroscope", "main")
+	require.NoError(t, err)
+	assert.Equal(t, "abcdef", commit.Sha)
+	assert.Equal(t, "initial commit", commit.Message)
+	assert.Equal(t, "john", commit.Author.Login)
+	assert.Equal(t, "2024-01-02T03:04:05Z", commit.Date)
+
+	_, err = c.GetCommit(context.Background(), "grafana", "pyroscope", "heads/main")
+	require.Error(t, err)
+	assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+}
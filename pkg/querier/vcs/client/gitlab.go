@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	"golang.org/x/oauth2"
+
+	vcsv1 "github.com/grafana/pyroscope/api/gen/proto/go/vcs/v1"
+)
+
+// GitLabClient returns a client of the GitLab instance at the given base URL,
+// e.g. https://gitlab.com or the URL of a self-hosted instance.
+func GitLabClient(ctx context.Context, token *oauth2.Token, client *http.Client, baseURL string) (*gitlabClient, error) {
+	return &gitlabClient{
+		api:     newAPIClient(client, token, strings.TrimSuffix(baseURL, "/")+"/api/v4"),
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}, nil
+}
+
+type gitlabClient struct {
+	api     apiClient
+	baseURL string
+}
+
+type gitlabCommit struct {
+	ID          string    `json:"id"`
+	Message     string    `json:"message"`
+	AuthorName  string    `json:"author_name"`
+	AuthoredAt  time.Time `json:"authored_date"`
+	CommittedAt time.Time `json:"committed_date"`
+	WebURL      string    `json:"web_url"`
+}
+
+type gitlabFile struct {
+	Content  string `json:"content"`
+	Encoding string `json:"encoding"`
+}
+
+// projectPath returns the URL-encoded path of the project, which is
+// accepted by the GitLab API in place of the project ID. GitLab projects
+// may be nested in subgroups, therefore owner may contain slashes.
+func (gl *gitlabClient) projectPath(owner, repo string) string {
+	return url.PathEscape(owner + "/" + repo)
+}
+
+func (gl *gitlabClient) GetCommit(ctx context.Context, owner, repo, ref string) (*vcsv1.CommitInfo, error) {
+	var commit gitlabCommit
+	path := fmt.Sprintf("/projects/%s/repository/commits/%s", gl.projectPath(owner, repo), url.PathEscape(ref))
+	if err := gl.api.getJSON(ctx, path, &commit); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("commit %s not found", ref))
+		}
+		return nil, err
+	}
+	return &vcsv1.CommitInfo{
+		Sha:     commit.ID,
+		Message: commit.Message,
+		Author: &vcsv1.CommitAuthor{
+			Login: commit.AuthorName,
+		},
+		Date: commit.AuthoredAt.Format(time.RFC3339),
+		URL:  commit.WebURL,
+	}, nil
+}
+
+func (gl *gitlabClient) GetFile(ctx context.Context, req FileRequest) (File, error) {
+	var file gitlabFile
+	path := fmt.Sprintf("/projects/%s/repository/files/%s?ref=%s",
+		gl.projectPath(req.Owner, req.Repo),
+		url.PathEscape(strings.TrimPrefix(req.Path, "/")),
+		url.QueryEscape(req.Ref),
+	)
+	if err := gl.api.getJSON(ctx, path, &file); err != nil {
+		return File{}, err
+	}
+	content := file.Content
+	if file.Encoding == "base64" {
+		decoded, err := base64.StdEncoding.DecodeString(file.Content)
+		if err != nil {
+			return File{}, fmt.Errorf("failed to decode file content: %w", err)
+		}
+		content = string(decoded)
+	}
+	return File{
+		Content: content,
+		URL:     fmt.Sprintf("%s/%s/%s/-/blob/%s/%s", gl.baseURL, req.Owner, req.Repo, req.Ref, escapePath(req.Path)),
+	}, nil
+}
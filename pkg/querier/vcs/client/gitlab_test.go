@@ -0,0 +1,70 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+func Test_gitlabClient(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/gitlab/api/v4/projects/{project}/repository/files/{path}", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		if r.PathValue("project") != "group/subgroup/repo" || r.PathValue("path") != "pkg/main.go" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		assert.Equal(t, "main", r.URL.Query().Get("ref"))
+		_, _ = w.Write([]byte(`{"encoding":"base64","content":"` + base64.StdEncoding.EncodeToString([]byte("package main")) + `"}`))
+	})
+	mux.HandleFunc("/gitlab/api/v4/projects/{project}/repository/commits/{ref}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("ref") != "main" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{
+			"id": "abcdef",
+			"message": "initial commit",
+			"author_name": "john",
+			"authored_date": "2024-01-02T03:04:05Z",
+			"web_url": "https://example.com/gitlab/group/subgroup/repo/-/commit/abcdef"
+		}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c, err := GitLabClient(context.Background(), &oauth2.Token{AccessToken: "token"}, server.Client(), server.URL+"/gitlab")
+	require.NoError(t, err)
+
+	file, err := c.GetFile(context.Background(), FileRequest{
+		Owner: "group/subgroup",
+		Repo:  "repo",
+		Path:  "pkg/main.go",
+		Ref:   "main",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "package main", file.Content)
+	assert.Equal(t, server.URL+"/gitlab/group/subgroup/repo/-/blob/main/pkg/main.go", file.URL)
+
+	_, err = c.GetFile(context.Background(), FileRequest{Owner: "group/subgroup", Repo: "repo", Path: "missing.go", Ref: "main"})
+	require.ErrorIs(t, err, ErrNotFound)
+
+	commit, err := c.GetCommit(context.Background(), "group/subgroup", "repo", "main")
+	require.NoError(t, err)
+	assert.Equal(t, "abcdef", commit.Sha)
+	assert.Equal(t, "initial commit", commit.Message)
+	assert.Equal(t, "john", commit.Author.Login)
+	assert.Equal(t, "2024-01-02T03:04:05Z", commit.Date)
+	assert.Equal(t, "https://example.com/gitlab/group/subgroup/repo/-/commit/abcdef", commit.URL)
+
+	_, err = c.GetCommit(context.Background(), "group/subgroup", "repo", "heads/main")
+	require.Error(t, err)
+	assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+}
@@ -0,0 +1,86 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"connectrpc.com/connect"
+	"golang.org/x/oauth2"
+
+	"github.com/grafana/pyroscope/pkg/util/connectgrpc"
+)
+
+// maxResponseSize limits the size of VCS API responses read into memory.
+const maxResponseSize = 16 << 20
+
+// apiClient is a minimal HTTP client of a VCS provider REST API.
+type apiClient struct {
+	client  *http.Client
+	token   *oauth2.Token
+	baseURL string
+}
+
+func newAPIClient(client *http.Client, token *oauth2.Token, baseURL string) apiClient {
+	return apiClient{
+		client:  client,
+		token:   token,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// get sends a GET request to the given API path and returns the response
+// body. Not found responses are reported with ErrNotFound; other non-2xx
+// responses are converted to connect errors with the matching code.
+func (c apiClient) get(ctx context.Context, path string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.token != nil && c.token.AccessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token.AccessToken)
+	}
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	switch {
+	case res.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("%w: %s %s", ErrNotFound, req.Method, req.URL.Path)
+	case res.StatusCode < 200 || res.StatusCode > 299:
+		code := connectgrpc.HTTPToCode(int32(res.StatusCode))
+		return nil, connect.NewError(code, fmt.Errorf("%s %s: %s", req.Method, req.URL.Path, res.Status))
+	}
+	return body, nil
+}
+
+// getJSON sends a GET request to the given API path
+// and decodes the JSON response into v.
+func (c apiClient) getJSON(ctx context.Context, path string, v any) error {
+	body, err := c.get(ctx, path)
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// escapePath escapes each segment of the slash-separated path.
+func escapePath(path string) string {
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
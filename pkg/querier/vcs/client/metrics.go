@@ -14,7 +14,10 @@ import (
 )
 
 var (
-	githubRouteMatchers = map[string]*regexp.Regexp{
+	// apiRouteMatchers maps VCS API routes to the patterns matching request
+	// paths. Routes of self-hosted instances may be served under a path
+	// prefix, therefore only GitHub routes are anchored at the start.
+	apiRouteMatchers = map[string]*regexp.Regexp{
 		// Get repository contents.
 		// https://docs.github.com/en/rest/repos/contents?apiVersion=2022-11-28#get-repository-content
 		"/repos/{owner}/{repo}/contents/{path}": regexp.MustCompile(`^\/repos\/\S+\/\S+\/contents\/\S+$`),
@@ -25,7 +28,44 @@ var (
 
 		// Refresh auth token.
 		// https://docs.github.com/en/apps/creating-github-apps/authenticating-with-a-github-app/refreshing-user-access-tokens#refreshing-a-user-access-token-with-a-refresh-token
-		"/login/oauth/access_token": regexp.MustCompile(`^\/login\/oauth\/access_token$`),
+		// Shared with Gitea.
+		"/login/oauth/access_token": regexp.MustCompile(`\/login\/oauth\/access_token$`),
+
+		// Get file from repository.
+		// https://docs.gitlab.com/ee/api/repository_files.html#get-file-from-repository
+		"/api/v4/projects/{id}/repository/files/{path}": regexp.MustCompile(`\/api\/v4\/projects\/\S+\/repository\/files\/\S+$`),
+
+		// Get a single commit.
+		// https://docs.gitlab.com/ee/api/commits.html#get-a-single-commit
+		"/api/v4/projects/{id}/repository/commits/{ref}": regexp.MustCompile(`\/api\/v4\/projects\/\S+\/repository\/commits\/\S+$`),
+
+		// Refresh auth token.
+		// https://docs.gitlab.com/ee/api/oauth2.html
+		"/oauth/token": regexp.MustCompile(`\/oauth\/token$`),
+
+		// Get a repository.
+		// https://developer.atlassian.com/cloud/bitbucket/rest/api-group-repositories/#api-repositories-workspace-repo-slug-get
+		"/repositories/{owner}/{repo}": regexp.MustCompile(`\/repositories\/[^\/\s]+\/[^\/\s]+$`),
+
+		// Get file from repository.
+		// https://developer.atlassian.com/cloud/bitbucket/rest/api-group-source/#api-repositories-workspace-repo-slug-src-commit-path-get
+		"/repositories/{owner}/{repo}/src/{ref}/{path}": regexp.MustCompile(`\/repositories\/[^\/\s]+\/[^\/\s]+\/src\/[^\/\s]+\/\S+$`),
+
+		// Get a commit.
+		// https://developer.atlassian.com/cloud/bitbucket/rest/api-group-commits/#api-repositories-workspace-repo-slug-commit-commit-get
+		"/repositories/{owner}/{repo}/commit/{ref}": regexp.MustCompile(`\/repositories\/[^\/\s]+\/[^\/\s]+\/commit\/\S+$`),
+
+		// Refresh auth token.
+		// https://developer.atlassian.com/cloud/bitbucket/oauth-2/
+		"/site/oauth2/access_token": regexp.MustCompile(`\/site\/oauth2\/access_token$`),
+
+		// Get a file or a directory.
+		// https://docs.gitea.com/api/#tag/repository/operation/repoGetContents
+		"/api/v1/repos/{owner}/{repo}/contents/{path}": regexp.MustCompile(`\/api\/v1\/repos\/[^\/\s]+\/[^\/\s]+\/contents\/\S+$`),
+
+		// Get a list of all commits from a repository.
+		// https://docs.gitea.com/api/#tag/repository/operation/repoGetAllCommits
+		"/api/v1/repos/{owner}/{repo}/commits": regexp.MustCompile(`\/api\/v1\/repos\/[^\/\s]+\/[^\/\s]+\/commits$`),
 	}
 )
 
@@ -34,7 +74,7 @@ func InstrumentedHTTPClient(logger log.Logger, reg prometheus.Registerer) *http.
 		prometheus.HistogramOpts{
 			Namespace: "pyroscope",
 			Name:      "vcs_github_request_duration",
-			Help:      "Duration of GitHub API requests in seconds",
+			Help:      "Duration of VCS provider API requests in seconds",
 			Buckets:   prometheus.ExponentialBucketsRange(0.1, 10, 8),
 		},
 		[]string{"method", "route", "status_code"},
@@ -44,16 +84,16 @@ func InstrumentedHTTPClient(logger log.Logger, reg prometheus.Registerer) *http.
 		Timeout:   10 * time.Second,
 		Transport: http.DefaultTransport,
 	}
-	client := util.InstrumentedHTTPClient(defaultClient, withGitHubMetricsTransport(logger, apiDuration))
+	client := util.InstrumentedHTTPClient(defaultClient, withMetricsTransport(logger, apiDuration))
 	return client
 }
 
-// withGitHubMetricsTransport wraps a transport with a client to track GitHub
-// API usage.
-func withGitHubMetricsTransport(logger log.Logger, hv *prometheus.HistogramVec) util.RoundTripperInstrumentFunc {
+// withMetricsTransport wraps a transport with a client to track VCS
+// provider API usage.
+func withMetricsTransport(logger log.Logger, hv *prometheus.HistogramVec) util.RoundTripperInstrumentFunc {
 	return func(next http.RoundTripper) http.RoundTripper {
 		return util.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
-			route := matchGitHubAPIRoute(req.URL.Path)
+			route := matchAPIRoute(req.URL.Path)
 			statusCode := ""
 			start := time.Now()
 
@@ -63,7 +103,7 @@ func withGitHubMetricsTransport(logger log.Logger, hv *prometheus.HistogramVec)
 			}
 
 			if route == "unknown_route" {
-				level.Warn(logger).Log("path", req.URL.Path, "msg", "unknown GitHub API route")
+				level.Warn(logger).Log("path", req.URL.Path, "msg", "unknown VCS API route")
 			}
 			hv.WithLabelValues(req.Method, route, statusCode).Observe(time.Since(start).Seconds())
 
@@ -72,8 +112,8 @@ func withGitHubMetricsTransport(logger log.Logger, hv *prometheus.HistogramVec)
 	}
 }
 
-func matchGitHubAPIRoute(path string) string {
-	for route, regex := range githubRouteMatchers {
+func matchAPIRoute(path string) string {
+	for route, regex := range apiRouteMatchers {
 		if regex.MatchString(path) {
 			return route
 		}
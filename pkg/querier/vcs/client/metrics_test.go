@@ -6,7 +6,7 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func Test_matchGitHubAPIRoute(t *testing.T) {
+func Test_matchAPIRoute(t *testing.T) {
 	tests := []struct {
 		Name string
 		Path string
@@ -57,6 +57,56 @@ func Test_matchGitHubAPIRoute(t *testing.T) {
 			Path: "/login/oauth/access_token",
 			Want: "/login/oauth/access_token",
 		},
+		{
+			Name: "GitLab GetFile",
+			Path: "/api/v4/projects/group/subgroup/repo/repository/files/pkg/main.go",
+			Want: "/api/v4/projects/{id}/repository/files/{path}",
+		},
+		{
+			Name: "GitLab GetFile with path prefix",
+			Path: "/gitlab/api/v4/projects/group/repo/repository/files/main.go",
+			Want: "/api/v4/projects/{id}/repository/files/{path}",
+		},
+		{
+			Name: "GitLab GetCommit",
+			Path: "/api/v4/projects/group/repo/repository/commits/abcdef1234567890",
+			Want: "/api/v4/projects/{id}/repository/commits/{ref}",
+		},
+		{
+			Name: "GitLab Refresh",
+			Path: "/oauth/token",
+			Want: "/oauth/token",
+		},
+		{
+			Name: "Bitbucket GetRepository",
+			Path: "/2.0/repositories/grafana/pyroscope",
+			Want: "/repositories/{owner}/{repo}",
+		},
+		{
+			Name: "Bitbucket GetFile",
+			Path: "/2.0/repositories/grafana/pyroscope/src/main/pkg/querier/querier.go",
+			Want: "/repositories/{owner}/{repo}/src/{ref}/{path}",
+		},
+		{
+			Name: "Bitbucket GetCommit",
+			Path: "/2.0/repositories/grafana/pyroscope/commit/abcdef1234567890",
+			Want: "/repositories/{owner}/{repo}/commit/{ref}",
+		},
+		{
+			Name: "Bitbucket Refresh",
+			Path: "/site/oauth2/access_token",
+			Want: "/site/oauth2/access_token",
+		},
+		{
+			Name: "Gitea GetFile",
+			Path: "/api/v1/repos/grafana/pyroscope/contents/pkg/querier/querier.go",
+			Want: "/api/v1/repos/{owner}/{repo}/contents/{path}",
+		},
+		{
+			Name: "Gitea GetCommit",
+			Path: "/api/v1/repos/grafana/pyroscope/commits",
+			Want: "/api/v1/repos/{owner}/{repo}/commits",
+		},
 		{
 			Name: "empty path",
 			Path: "",
@@ -71,7 +121,7 @@ func Test_matchGitHubAPIRoute(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.Name, func(t *testing.T) {
-			got := matchGitHubAPIRoute(tt.Path)
+			got := matchAPIRoute(tt.Path)
 			require.Equal(t, tt.Want, got)
 		})
 	}
@@ -0,0 +1,303 @@
+package vcs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"connectrpc.com/connect"
+	gitparse "github.com/chainguard-dev/git-urls"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/tenant"
+	giturl "github.com/kubescape/go-git-url"
+	"github.com/kubescape/go-git-url/apis"
+	gitlabparserv1 "github.com/kubescape/go-git-url/gitlabparser/v1"
+
+	vcsv1 "github.com/grafana/pyroscope/api/gen/proto/go/vcs/v1"
+	"github.com/grafana/pyroscope/pkg/querier/vcs/client"
+)
+
+const (
+	localFetchTimeout = 5 * time.Minute
+	// maxLocalFileSize limits the size of files read from local repositories.
+	maxLocalFileSize = 16 << 20
+)
+
+// Limits are the per-tenant settings of the VCS service.
+type Limits interface {
+	VCSLocalRepositoriesDir(tenantID string) string
+	VCSLocalRepositoriesFetchInterval(tenantID string) time.Duration
+}
+
+// localRepositories keeps track of the fetches of local git repositories.
+type localRepositories struct {
+	logger log.Logger
+
+	mu       sync.Mutex
+	fetched  map[string]time.Time
+	fetching map[string]struct{}
+}
+
+func newLocalRepositories(logger log.Logger) *localRepositories {
+	return &localRepositories{
+		logger:   logger,
+		fetched:  make(map[string]time.Time),
+		fetching: make(map[string]struct{}),
+	}
+}
+
+// maybeFetch fetches the repository from its remotes in the background, if
+// it has not been fetched within the interval. The request is served from
+// the repository as is: the update is only visible to subsequent requests.
+func (r *localRepositories) maybeFetch(dir string, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.fetching[dir]; ok || time.Since(r.fetched[dir]) < interval {
+		return
+	}
+	r.fetching[dir] = struct{}{}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), localFetchTimeout)
+		defer cancel()
+		if _, err := runGit(ctx, dir, "fetch", "--all", "--prune", "--quiet"); err != nil {
+			level.Warn(r.logger).Log("msg", "failed to fetch local git repository", "dir", dir, "err", err)
+		}
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.fetched[dir] = time.Now()
+		delete(r.fetching, dir)
+	}()
+}
+
+// localClient serves files and commits from the local git repositories of
+// a tenant. Repositories are looked up by the host, owner and name of the
+// repository, therefore dependencies mirrored in the same directory can
+// be resolved as well.
+type localClient struct {
+	repos         *localRepositories
+	root          string
+	host          string
+	provider      string
+	fetchInterval time.Duration
+}
+
+// localRepositoryClient returns a client of the local repositories of the
+// tenant, if configured. Local repositories do not require a token.
+// Requests of multiple tenants (federated queries) are served by the
+// hosted providers.
+func (q *Service) localRepositoryClient(ctx context.Context, repositoryURL string) (vcsClient, giturl.IGitURL, bool, error) {
+	if q.limits == nil {
+		return nil, nil, false, nil
+	}
+	tenantIDs, err := tenant.TenantIDs(ctx)
+	if err != nil || len(tenantIDs) != 1 {
+		return nil, nil, false, nil
+	}
+	tenantID := tenantIDs[0]
+	root := q.limits.VCSLocalRepositoriesDir(tenantID)
+	if root == "" {
+		return nil, nil, false, nil
+	}
+	gitURL, err := parseLocalRepositoryURL(q.providers, repositoryURL)
+	if err != nil {
+		return nil, nil, true, err
+	}
+	return &localClient{
+		repos:         q.localRepos,
+		root:          root,
+		host:          gitURL.GetHostName(),
+		provider:      gitURL.GetProvider(),
+		fetchInterval: q.limits.VCSLocalRepositoriesFetchInterval(tenantID),
+	}, gitURL, true, nil
+}
+
+// parseLocalRepositoryURL parses the repository URL. Unlike hosted
+// repositories, local ones are not limited to the configured providers.
+func parseLocalRepositoryURL(providers []*provider, rawURL string) (giturl.IGitURL, error) {
+	if _, gitURL, err := resolveRepository(providers, rawURL); err == nil {
+		return gitURL, nil
+	}
+	u, err := gitparse.Parse(rawURL)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if u.Host == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("repository URL %q has no host", rawURL))
+	}
+	// The GitLab parser is the most permissive one:
+	// the owner may consist of multiple path segments.
+	gitURL, err := gitlabparserv1.NewGitLabParserWithURL(u.String())
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	return &repositoryURL{
+		IGitURL:  gitURL,
+		provider: &provider{baseURL: &url.URL{Scheme: "https", Host: u.Host}},
+	}, nil
+}
+
+// dir returns the directory of the repository. Both bare repositories and
+// working trees are supported, with or without the ".git" suffix.
+// The host, owner, and name come from the request, therefore each of
+// their path components must be local to the root directory.
+func (c *localClient) dir(owner, repo string) (string, error) {
+	rel := path.Join(owner, repo)
+	for _, name := range append([]string{c.host, repo}, strings.Split(owner, "/")...) {
+		if !filepath.IsLocal(name) {
+			return "", connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("invalid repository %s/%s", c.host, rel))
+		}
+	}
+	for _, candidate := range []string{
+		filepath.Join(c.root, c.host, rel),
+		filepath.Join(c.root, c.host, rel+".git"),
+		filepath.Join(c.root, rel),
+		filepath.Join(c.root, rel+".git"),
+	} {
+		if info, err := os.Stat(candidate); err == nil && info.IsDir() {
+			c.repos.maybeFetch(candidate, c.fetchInterval)
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("%w: repository %s/%s is not available locally", client.ErrNotFound, owner, repo)
+}
+
+func (c *localClient) GetFile(ctx context.Context, req client.FileRequest) (client.File, error) {
+	dir, err := c.dir(req.Owner, req.Repo)
+	if err != nil {
+		return client.File{}, err
+	}
+	ref := req.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+	sha, err := resolveCommit(ctx, dir, ref)
+	if err != nil {
+		return client.File{}, err
+	}
+	filePath := strings.TrimLeft(path.Clean("/"+req.Path), "/")
+	object := sha + ":" + filePath
+	typ, err := runGit(ctx, dir, "cat-file", "-t", object)
+	if err != nil {
+		return client.File{}, fmt.Errorf("%w: %s", client.ErrNotFound, filePath)
+	}
+	// We only support files retrieval.
+	if strings.TrimSpace(string(typ)) != "blob" {
+		return client.File{}, connect.NewError(connect.CodeInvalidArgument, errors.New("path is not a file"))
+	}
+	size, err := runGit(ctx, dir, "cat-file", "-s", object)
+	if err != nil {
+		return client.File{}, err
+	}
+	if n, err := strconv.ParseInt(strings.TrimSpace(string(size)), 10, 64); err == nil && n > maxLocalFileSize {
+		return client.File{}, connect.NewError(connect.CodeResourceExhausted, fmt.Errorf("file %s is too large: %d bytes", filePath, n))
+	}
+	content, err := runGit(ctx, dir, "cat-file", "blob", object)
+	if err != nil {
+		return client.File{}, err
+	}
+	return client.File{
+		Content: string(content),
+		URL:     c.fileURL(req.Owner, req.Repo, ref, filePath),
+	}, nil
+}
+
+func (c *localClient) GetCommit(ctx context.Context, owner, repo, ref string) (*vcsv1.CommitInfo, error) {
+	dir, err := c.dir(owner, repo)
+	if errors.Is(err, client.ErrNotFound) {
+		return nil, connect.NewError(connect.CodeNotFound, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	sha, err := resolveCommit(ctx, dir, ref)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("commit %s not found", ref))
+	}
+	// Fields are separated with NUL, the message is the last one.
+	out, err := runGit(ctx, dir, "show", "--no-patch", "--format=%H%x00%an%x00%aI%x00%B", sha)
+	if err != nil {
+		return nil, err
+	}
+	fields := strings.SplitN(string(out), "\x00", 4)
+	if len(fields) != 4 {
+		return nil, fmt.Errorf("unexpected commit format: %q", out)
+	}
+	date, err := time.Parse(time.RFC3339, fields[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid commit date: %w", err)
+	}
+	return &vcsv1.CommitInfo{
+		Sha:     fields[0],
+		Message: strings.TrimRight(fields[3], "\n"),
+		Author: &vcsv1.CommitAuthor{
+			Login: fields[1],
+		},
+		Date: date.Format(time.RFC3339),
+		URL:  c.commitURL(owner, repo, fields[0]),
+	}, nil
+}
+
+// fileURL returns the link to the file at the web UI of the repository host.
+func (c *localClient) fileURL(owner, repo, ref, filePath string) string {
+	var sep string
+	switch c.provider {
+	case apis.ProviderGitLab.String():
+		sep = "-/blob"
+	case apis.ProviderBitBucket.String(), providerGitea:
+		sep = "src"
+	default:
+		sep = "blob"
+	}
+	return fmt.Sprintf("https://%s/%s/%s/%s/%s/%s", c.host, owner, repo, sep, ref, filePath)
+}
+
+// commitURL returns the link to the commit at the web UI of the repository host.
+func (c *localClient) commitURL(owner, repo, sha string) string {
+	var sep string
+	switch c.provider {
+	case apis.ProviderGitLab.String():
+		sep = "-/commit"
+	case apis.ProviderBitBucket.String():
+		sep = "commits"
+	default:
+		sep = "commit"
+	}
+	return fmt.Sprintf("https://%s/%s/%s/%s/%s", c.host, owner, repo, sep, sha)
+}
+
+// resolveCommit returns the hash of the commit the ref points to.
+func resolveCommit(ctx context.Context, dir, ref string) (string, error) {
+	out, err := runGit(ctx, dir, "rev-parse", "--verify", "--quiet", "--end-of-options", ref+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("%w: ref %s", client.ErrNotFound, ref)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// runGit runs the git command in the repository directory.
+func runGit(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+	// Never prompt for credentials when fetching.
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("git %s: %w: %s", args[0], err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,174 @@
+package vcs
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	vcsv1 "github.com/grafana/pyroscope/api/gen/proto/go/vcs/v1"
+	"github.com/grafana/pyroscope/pkg/querier/vcs/client"
+	"github.com/grafana/pyroscope/pkg/tenant"
+	"github.com/grafana/pyroscope/pkg/validation"
+)
+
+func gitCmd(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=John",
+		"GIT_AUTHOR_EMAIL=john@example.com",
+		"GIT_AUTHOR_DATE=2024-01-02T03:04:05Z",
+		"GIT_COMMITTER_NAME=John",
+		"GIT_COMMITTER_EMAIL=john@example.com",
+		"GIT_COMMITTER_DATE=2024-01-02T03:04:05Z",
+	)
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	return string(out)
+}
+
+// newLocalRepository creates a repository with a single commit, and
+// mirrors it to <root>/github.com/grafana/example.git.
+func newLocalRepository(t *testing.T) (root, sha string) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+	src := t.TempDir()
+	gitCmd(t, src, "init", "--quiet", "--initial-branch=main")
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "pkg"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "pkg", "main.go"), []byte("package main\n"), 0o644))
+	gitCmd(t, src, "add", ".")
+	gitCmd(t, src, "commit", "--quiet", "-m", "Initial commit")
+	gitCmd(t, src, "tag", "v1.0.0")
+	sha = gitCmd(t, src, "rev-parse", "HEAD")
+	sha = sha[:len(sha)-1]
+
+	root = t.TempDir()
+	gitCmd(t, root, "clone", "--quiet", "--mirror", src, filepath.Join(root, "github.com", "grafana", "example.git"))
+	return root, sha
+}
+
+func Test_LocalRepositories(t *testing.T) {
+	root, sha := newLocalRepository(t)
+	svc := New(log.NewNopLogger(), prometheus.NewRegistry(), validation.MockLimits{VCSLocalRepositoriesDirValue: root})
+	ctx := tenant.InjectTenantID(context.Background(), "tenant")
+
+	// No session cookie is required.
+	file, err := svc.GetFile(ctx, connect.NewRequest(&vcsv1.GetFileRequest{
+		RepositoryURL: "https://github.com/grafana/example",
+		LocalPath:     "pkg/main.go",
+		Ref:           "main",
+	}))
+	require.NoError(t, err)
+	content, err := base64.StdEncoding.DecodeString(file.Msg.Content)
+	require.NoError(t, err)
+	assert.Equal(t, "package main\n", string(content))
+	assert.Equal(t, "https://github.com/grafana/example/blob/main/pkg/main.go", file.Msg.URL)
+
+	_, err = svc.GetFile(ctx, connect.NewRequest(&vcsv1.GetFileRequest{
+		RepositoryURL: "https://github.com/grafana/example",
+		LocalPath:     "missing.go",
+	}))
+	assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+
+	_, err = svc.GetFile(ctx, connect.NewRequest(&vcsv1.GetFileRequest{
+		RepositoryURL: "https://github.com/grafana/unknown",
+		LocalPath:     "pkg/main.go",
+	}))
+	assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+
+	commit, err := svc.GetCommit(ctx, connect.NewRequest(&vcsv1.GetCommitRequest{
+		RepositoryURL: "https://github.com/grafana/example",
+		Ref:           "v1.0.0",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, sha, commit.Msg.Sha)
+	assert.Equal(t, "Initial commit", commit.Msg.Message)
+	assert.Equal(t, "John", commit.Msg.Author.Login)
+	assert.Equal(t, "2024-01-02T03:04:05Z", commit.Msg.Date)
+	assert.Equal(t, "https://github.com/grafana/example/commit/"+sha, commit.Msg.URL)
+
+	commits, err := svc.GetCommits(ctx, connect.NewRequest(&vcsv1.GetCommitsRequest{
+		RepositoryUrl: "https://github.com/grafana/example",
+		Refs:          []string{"main", sha[:8], "unknown"},
+	}))
+	require.NoError(t, err)
+	require.Len(t, commits.Msg.Commits, 2)
+
+	// Federated queries are served from the hosted providers.
+	ctx = tenant.InjectTenantID(context.Background(), "tenant|other")
+	_, err = svc.GetFile(ctx, connect.NewRequest(&vcsv1.GetFileRequest{
+		RepositoryURL: "https://github.com/grafana/example",
+		LocalPath:     "pkg/main.go",
+	}))
+	assert.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+
+	// Repositories of other tenants are served from the hosted providers.
+	ctx = tenant.InjectTenantID(context.Background(), "other")
+	svc.limits = validation.MockLimits{}
+	_, err = svc.GetFile(ctx, connect.NewRequest(&vcsv1.GetFileRequest{
+		RepositoryURL: "https://github.com/grafana/example",
+		LocalPath:     "pkg/main.go",
+	}))
+	assert.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+}
+
+func Test_parseLocalRepositoryURL(t *testing.T) {
+	gitURL, err := parseLocalRepositoryURL([]*provider{githubProvider()}, "git@git.internal:platform/services/api.git")
+	require.NoError(t, err)
+	assert.Equal(t, "git.internal", gitURL.GetHostName())
+	assert.Equal(t, "platform/services", gitURL.GetOwnerName())
+	assert.Equal(t, "api", gitURL.GetRepoName())
+
+	_, err = parseLocalRepositoryURL(nil, "not a repository")
+	require.Error(t, err)
+}
+
+func Test_localClient_dir(t *testing.T) {
+	c := &localClient{repos: newLocalRepositories(log.NewNopLogger()), root: t.TempDir(), host: "github.com"}
+	for _, tc := range []struct{ host, owner, repo string }{
+		{host: "github.com", owner: "..", repo: ".."},
+		{host: "github.com", owner: "grafana/../..", repo: "example"},
+		{host: "github.com", owner: "grafana", repo: "../example"},
+		{host: "..", owner: "grafana", repo: "example"},
+		{host: "", owner: "grafana", repo: "example"},
+	} {
+		c.host = tc.host
+		_, err := c.dir(tc.owner, tc.repo)
+		assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err), tc)
+	}
+	c.host = "github.com"
+	_, err := c.dir("grafana", "example")
+	assert.ErrorIs(t, err, client.ErrNotFound)
+}
+
+func Test_localRepositories_maybeFetch(t *testing.T) {
+	root, _ := newLocalRepository(t)
+	dir := filepath.Join(root, "github.com", "grafana", "example.git")
+	src := gitCmd(t, dir, "remote", "get-url", "origin")
+	src = src[:len(src)-1]
+	require.NoError(t, os.WriteFile(filepath.Join(src, "new.go"), []byte("package main\n"), 0o644))
+	gitCmd(t, src, "add", ".")
+	gitCmd(t, src, "commit", "--quiet", "-m", "Second commit")
+
+	repos := newLocalRepositories(log.NewNopLogger())
+	repos.maybeFetch(dir, 0)
+	_, err := resolveCommit(context.Background(), dir, "main~1")
+	require.Error(t, err, "fetch is disabled")
+
+	repos.maybeFetch(dir, time.Hour)
+	require.Eventually(t, func() bool {
+		_, err = resolveCommit(context.Background(), dir, "main~1")
+		return err == nil
+	}, 10*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,328 @@
+package vcs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"connectrpc.com/connect"
+	gitparse "github.com/chainguard-dev/git-urls"
+	giturl "github.com/kubescape/go-git-url"
+	"github.com/kubescape/go-git-url/apis"
+	bitbucketparserv1 "github.com/kubescape/go-git-url/bitbucketparser/v1"
+	gitlabparserv1 "github.com/kubescape/go-git-url/gitlabparser/v1"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/endpoints"
+
+	"github.com/grafana/pyroscope/pkg/querier/vcs/client"
+	"github.com/grafana/pyroscope/pkg/querier/vcs/source"
+)
+
+const providerGitea = "gitea"
+
+// vcsClient provides access to the files and commits of a repository.
+type vcsClient interface {
+	source.VCSClient
+	commitGetter
+}
+
+// provider is a VCS provider the repositories can be hosted at.
+type provider struct {
+	name string
+	// baseURL is the web URL of the provider, e.g. https://gitlab.com.
+	// Self-hosted instances may be served under a path prefix.
+	baseURL *url.URL
+
+	clientID     string
+	clientSecret string
+	endpoint     oauth2.Endpoint
+	cookieName   string
+
+	// parse parses a repository URL with the path prefix of
+	// the base URL trimmed.
+	parse     func(string) (giturl.IGitURL, error)
+	newClient func(context.Context, *oauth2.Token, *http.Client) (vcsClient, error)
+	// refresh exchanges the refresh token for a new token.
+	// If not set, the standard OAuth2 refresh flow is used.
+	refresh func(context.Context, *oauth2.Token, *http.Client) (*oauth2.Token, error)
+}
+
+// providersFromEnv returns the VCS providers configured with environment
+// variables. GitHub is always present; GitLab, Bitbucket and Gitea are only
+// enabled if their OAuth application is configured. The base URLs of GitLab
+// and Gitea can point to self-hosted instances.
+func providersFromEnv() ([]*provider, error) {
+	providers := []*provider{githubProvider()}
+	if os.Getenv("GITLAB_CLIENT_ID") != "" {
+		p, err := gitlabProvider(
+			envOrDefault("GITLAB_URL", "https://gitlab.com"),
+			os.Getenv("GITLAB_CLIENT_ID"),
+			os.Getenv("GITLAB_CLIENT_SECRET"),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("invalid GitLab configuration: %w", err)
+		}
+		providers = append(providers, p)
+	}
+	if os.Getenv("BITBUCKET_CLIENT_ID") != "" {
+		p, err := bitbucketProvider(
+			envOrDefault("BITBUCKET_URL", "https://bitbucket.org"),
+			envOrDefault("BITBUCKET_API_URL", "https://api.bitbucket.org/2.0"),
+			os.Getenv("BITBUCKET_CLIENT_ID"),
+			os.Getenv("BITBUCKET_CLIENT_SECRET"),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Bitbucket configuration: %w", err)
+		}
+		providers = append(providers, p)
+	}
+	if os.Getenv("GITEA_CLIENT_ID") != "" {
+		if os.Getenv("GITEA_URL") == "" {
+			return nil, errors.New("missing GITEA_URL environment variable")
+		}
+		p, err := giteaProvider(
+			os.Getenv("GITEA_URL"),
+			os.Getenv("GITEA_CLIENT_ID"),
+			os.Getenv("GITEA_CLIENT_SECRET"),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Gitea configuration: %w", err)
+		}
+		providers = append(providers, p)
+	}
+	return providers, nil
+}
+
+func envOrDefault(key, defaultValue string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return defaultValue
+}
+
+func parseBaseURL(baseURL string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("base URL %q must be absolute", baseURL)
+	}
+	return u, nil
+}
+
+func githubProvider() *provider {
+	return &provider{
+		name:         apis.ProviderGitHub.String(),
+		baseURL:      &url.URL{Scheme: "https", Host: "github.com"},
+		clientID:     githubAppClientID,
+		clientSecret: githubAppClientSecret,
+		endpoint:     endpoints.GitHub,
+		cookieName:   sessionCookieName,
+		parse:        giturl.NewGitURL,
+		newClient: func(ctx context.Context, token *oauth2.Token, httpClient *http.Client) (vcsClient, error) {
+			return client.GithubClient(ctx, token, httpClient)
+		},
+		refresh: func(ctx context.Context, token *oauth2.Token, httpClient *http.Client) (*oauth2.Token, error) {
+			req, err := buildGithubRefreshRequest(ctx, token)
+			if err != nil {
+				return nil, err
+			}
+			githubToken, err := refreshGithubToken(req, httpClient)
+			if err != nil {
+				return nil, err
+			}
+			return githubToken.toOAuthToken(), nil
+		},
+	}
+}
+
+func gitlabProvider(baseURL, clientID, clientSecret string) (*provider, error) {
+	u, err := parseBaseURL(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	webURL := u.String()
+	return &provider{
+		name:         apis.ProviderGitLab.String(),
+		baseURL:      u,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		endpoint: oauth2.Endpoint{
+			AuthURL:  webURL + "/oauth/authorize",
+			TokenURL: webURL + "/oauth/token",
+		},
+		cookieName: sessionCookieName + "_" + apis.ProviderGitLab.String(),
+		parse: func(s string) (giturl.IGitURL, error) {
+			return gitlabparserv1.NewGitLabParserWithURL(s)
+		},
+		newClient: func(ctx context.Context, token *oauth2.Token, httpClient *http.Client) (vcsClient, error) {
+			return client.GitLabClient(ctx, token, httpClient, webURL)
+		},
+	}, nil
+}
+
+func bitbucketProvider(baseURL, apiURL, clientID, clientSecret string) (*provider, error) {
+	u, err := parseBaseURL(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	webURL := u.String()
+	return &provider{
+		name:         apis.ProviderBitBucket.String(),
+		baseURL:      u,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		endpoint: oauth2.Endpoint{
+			AuthURL:  webURL + "/site/oauth2/authorize",
+			TokenURL: webURL + "/site/oauth2/access_token",
+		},
+		cookieName: sessionCookieName + "_" + apis.ProviderBitBucket.String(),
+		parse: func(s string) (giturl.IGitURL, error) {
+			return bitbucketparserv1.NewBitBucketParserWithURL(s)
+		},
+		newClient: func(ctx context.Context, token *oauth2.Token, httpClient *http.Client) (vcsClient, error) {
+			return client.BitbucketClient(ctx, token, httpClient, webURL, apiURL)
+		},
+	}, nil
+}
+
+func giteaProvider(baseURL, clientID, clientSecret string) (*provider, error) {
+	u, err := parseBaseURL(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	webURL := u.String()
+	return &provider{
+		name:         providerGitea,
+		baseURL:      u,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		endpoint: oauth2.Endpoint{
+			AuthURL:  webURL + "/login/oauth/authorize",
+			TokenURL: webURL + "/login/oauth/access_token",
+		},
+		cookieName: sessionCookieName + "_" + providerGitea,
+		// Like Bitbucket ones, Gitea repository URLs start with
+		// <owner>/<repo>, followed by /src/ and the ref.
+		parse: func(s string) (giturl.IGitURL, error) {
+			return bitbucketparserv1.NewBitBucketParserWithURL(s)
+		},
+		newClient: func(ctx context.Context, token *oauth2.Token, httpClient *http.Client) (vcsClient, error) {
+			return client.GiteaClient(ctx, token, httpClient, webURL)
+		},
+	}, nil
+}
+
+// checkConfigured returns an error, if the OAuth application
+// of the provider is not configured.
+func (p *provider) checkConfigured() error {
+	if p.name == apis.ProviderGitHub.String() {
+		return isGitHubIntegrationConfigured()
+	}
+	var errs []error
+	if p.clientID == "" {
+		errs = append(errs, fmt.Errorf("missing %s client ID", p.name))
+	}
+	if p.clientSecret == "" {
+		errs = append(errs, fmt.Errorf("missing %s client secret", p.name))
+	}
+	if len(githubSessionSecret) == 0 {
+		errs = append(errs, fmt.Errorf("missing %s environment variable", envVarGithubSessionSecret))
+	}
+	return errors.Join(errs...)
+}
+
+func (p *provider) oauthConfig(redirectURL string) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     p.clientID,
+		ClientSecret: p.clientSecret,
+		Endpoint:     p.endpoint,
+		RedirectURL:  redirectURL,
+	}
+}
+
+func (p *provider) refreshToken(ctx context.Context, token *oauth2.Token, httpClient *http.Client) (*oauth2.Token, error) {
+	if p.refresh != nil {
+		return p.refresh(ctx, token, httpClient)
+	}
+	if token.RefreshToken == "" {
+		return nil, errors.New("token has no refresh token")
+	}
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, httpClient)
+	// Force the refresh by omitting the access token.
+	return p.oauthConfig("").TokenSource(ctx, &oauth2.Token{RefreshToken: token.RefreshToken}).Token()
+}
+
+// match reports whether the repository URL belongs to the provider
+// instance, and returns the URL with the base URL path prefix trimmed.
+func (p *provider) match(u *url.URL) (string, bool) {
+	if !strings.EqualFold(u.Host, p.baseURL.Host) {
+		return "", false
+	}
+	if p.baseURL.Path == "" {
+		return u.String(), true
+	}
+	path, ok := strings.CutPrefix(u.Path, p.baseURL.Path+"/")
+	if !ok {
+		return "", false
+	}
+	trimmed := *u
+	trimmed.Path = "/" + path
+	trimmed.RawPath = ""
+	return trimmed.String(), true
+}
+
+// resolveRepository finds the provider hosting the repository,
+// and parses the repository URL.
+func resolveRepository(providers []*provider, rawURL string) (*provider, giturl.IGitURL, error) {
+	u, err := gitparse.Parse(rawURL)
+	if err != nil {
+		return nil, nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	for _, p := range providers {
+		trimmed, ok := p.match(u)
+		if !ok {
+			continue
+		}
+		gitURL, err := p.parse(trimmed)
+		if err != nil {
+			return nil, nil, connect.NewError(connect.CodeInvalidArgument, err)
+		}
+		return p, &repositoryURL{IGitURL: gitURL, provider: p}, nil
+	}
+	// Fall back to the well-known hosts, e.g. raw.githubusercontent.com.
+	gitURL, err := giturl.NewGitURL(rawURL)
+	if err != nil {
+		return nil, nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	for _, p := range providers {
+		if p.name == gitURL.GetProvider() && p.baseURL.Host == gitURL.GetHostName() {
+			return p, gitURL, nil
+		}
+	}
+	return nil, nil, connect.NewError(connect.CodeInvalidArgument,
+		fmt.Errorf("%s repositories are not supported", gitURL.GetProvider()))
+}
+
+// repositoryURL overrides the host of the parsed repository URL,
+// as giturl parsers are not aware of self-hosted instances.
+type repositoryURL struct {
+	giturl.IGitURL
+	provider *provider
+}
+
+func (u *repositoryURL) GetProvider() string { return u.provider.name }
+func (u *repositoryURL) GetHostName() string { return u.provider.baseURL.Host }
+
+func (u *repositoryURL) GetURL() *url.URL {
+	return u.provider.baseURL.JoinPath(u.GetOwnerName(), u.GetRepoName())
+}
+
+func (u *repositoryURL) GetHttpCloneURL() string {
+	return u.GetURL().String() + ".git"
+}
@@ -0,0 +1,107 @@
+package vcs
+
+import (
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_resolveRepository(t *testing.T) {
+	gitlab, err := gitlabProvider("https://example.com/gitlab/", "id", "secret")
+	require.NoError(t, err)
+	gitea, err := giteaProvider("https://gitea.example.com", "id", "secret")
+	require.NoError(t, err)
+	bitbucket, err := bitbucketProvider("https://bitbucket.org", "https://api.bitbucket.org/2.0", "id", "secret")
+	require.NoError(t, err)
+	providers := []*provider{githubProvider(), gitlab, gitea, bitbucket}
+
+	tests := []struct {
+		url      string
+		provider string
+		host     string
+		owner    string
+		repo     string
+		err      bool
+	}{
+		{
+			url:      "https://github.com/grafana/pyroscope",
+			provider: "github",
+			host:     "github.com",
+			owner:    "grafana",
+			repo:     "pyroscope",
+		},
+		{
+			url:      "git@github.com:grafana/pyroscope.git",
+			provider: "github",
+			host:     "github.com",
+			owner:    "grafana",
+			repo:     "pyroscope",
+		},
+		{
+			url:      "https://example.com/gitlab/group/subgroup/repo",
+			provider: "gitlab",
+			host:     "example.com",
+			owner:    "group/subgroup",
+			repo:     "repo",
+		},
+		{
+			url:      "https://example.com/gitlab/group/repo/-/blob/main/README.md",
+			provider: "gitlab",
+			host:     "example.com",
+			owner:    "group",
+			repo:     "repo",
+		},
+		{
+			url:      "https://gitea.example.com/grafana/pyroscope.git",
+			provider: "gitea",
+			host:     "gitea.example.com",
+			owner:    "grafana",
+			repo:     "pyroscope",
+		},
+		{
+			url:      "https://bitbucket.org/grafana/pyroscope",
+			provider: "bitbucket",
+			host:     "bitbucket.org",
+			owner:    "grafana",
+			repo:     "pyroscope",
+		},
+		{
+			// GitLab.com is not configured.
+			url: "https://gitlab.com/grafana/pyroscope",
+			err: true,
+		},
+		{
+			// The path prefix of the self-hosted GitLab does not match.
+			url: "https://example.com/grafana/pyroscope",
+			err: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			p, gitURL, err := resolveRepository(providers, tt.url)
+			if tt.err {
+				require.Error(t, err)
+				assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.provider, p.name)
+			assert.Equal(t, tt.provider, gitURL.GetProvider())
+			assert.Equal(t, tt.host, gitURL.GetHostName())
+			assert.Equal(t, tt.owner, gitURL.GetOwnerName())
+			assert.Equal(t, tt.repo, gitURL.GetRepoName())
+		})
+	}
+}
+
+func Test_providerCookieNames(t *testing.T) {
+	gitlab, err := gitlabProvider("https://gitlab.com", "id", "secret")
+	require.NoError(t, err)
+	// The GitHub cookie name must not change to keep existing sessions.
+	assert.Equal(t, sessionCookieName, githubProvider().cookieName)
+	assert.Equal(t, sessionCookieName+"_gitlab", gitlab.cookieName)
+	assert.Equal(t, "https://gitlab.com/oauth/authorize", gitlab.endpoint.AuthURL)
+}
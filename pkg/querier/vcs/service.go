@@ -9,8 +9,8 @@ import (
 
 	"connectrpc.com/connect"
 	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
 	giturl "github.com/kubescape/go-git-url"
-	"github.com/kubescape/go-git-url/apis"
 	"github.com/prometheus/client_golang/prometheus"
 	"golang.org/x/oauth2"
 
@@ -25,14 +25,26 @@ var _ vcsv1connect.VCSServiceHandler = (*Service)(nil)
 type Service struct {
 	logger     log.Logger
 	httpClient *http.Client
+	providers  []*provider
+	limits     Limits
+	localRepos *localRepositories
 }
 
-func New(logger log.Logger, reg prometheus.Registerer) *Service {
+func New(logger log.Logger, reg prometheus.Registerer, limits Limits) *Service {
 	httpClient := client.InstrumentedHTTPClient(logger, reg)
 
+	providers, err := providersFromEnv()
+	if err != nil {
+		level.Error(logger).Log("err", err, "msg", "failed to configure VCS providers, only GitHub is enabled")
+		providers = []*provider{githubProvider()}
+	}
+
 	return &Service{
 		logger:     logger,
 		httpClient: httpClient,
+		providers:  providers,
+		limits:     limits,
+		localRepos: newLocalRepositories(logger),
 	}
 }
 
@@ -119,35 +131,14 @@ func (q *Service) GithubRefresh(ctx context.Context, req *connect.Request[vcsv1.
 }
 
 func (q *Service) GetFile(ctx context.Context, req *connect.Request[vcsv1.GetFileRequest]) (*connect.Response[vcsv1.GetFileResponse], error) {
-	token, err := tokenFromRequest(ctx, req)
-	if err != nil {
-		q.logger.Log("err", err, "msg", "failed to extract token from request")
-		return nil, connect.NewError(connect.CodeUnauthenticated, fmt.Errorf("invalid token"))
-	}
-
-	err = rejectExpiredToken(token)
-	if err != nil {
-		return nil, err
-	}
-
 	// initialize and parse the git repo URL
-	gitURL, err := giturl.NewGitURL(req.Msg.RepositoryURL)
-	if err != nil {
-		return nil, connect.NewError(connect.CodeInvalidArgument, err)
-	}
-
-	if gitURL.GetProvider() != apis.ProviderGitHub.String() {
-		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("only GitHub repositories are supported"))
-	}
-
-	// todo: we can support multiple provider: bitbucket, gitlab, etc.
-	ghClient, err := client.GithubClient(ctx, token, q.httpClient)
+	vcsClient, gitURL, err := q.repositoryClient(ctx, req, req.Msg.RepositoryURL)
 	if err != nil {
 		return nil, err
 	}
 
 	file, err := source.NewFileFinder(
-		ghClient,
+		vcsClient,
 		gitURL,
 		req.Msg.LocalPath,
 		req.Msg.RootPath,
@@ -165,27 +156,7 @@ func (q *Service) GetFile(ctx context.Context, req *connect.Request[vcsv1.GetFil
 }
 
 func (q *Service) GetCommit(ctx context.Context, req *connect.Request[vcsv1.GetCommitRequest]) (*connect.Response[vcsv1.GetCommitResponse], error) {
-	token, err := tokenFromRequest(ctx, req)
-	if err != nil {
-		q.logger.Log("err", err, "msg", "failed to extract token from request")
-		return nil, connect.NewError(connect.CodeUnauthenticated, fmt.Errorf("invalid token"))
-	}
-
-	err = rejectExpiredToken(token)
-	if err != nil {
-		return nil, err
-	}
-
-	gitURL, err := giturl.NewGitURL(req.Msg.RepositoryURL)
-	if err != nil {
-		return nil, connect.NewError(connect.CodeInvalidArgument, err)
-	}
-
-	if gitURL.GetProvider() != apis.ProviderGitHub.String() {
-		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("only GitHub repositories are supported"))
-	}
-
-	ghClient, err := client.GithubClient(ctx, token, q.httpClient)
+	vcsClient, gitURL, err := q.repositoryClient(ctx, req, req.Msg.RepositoryURL)
 	if err != nil {
 		return nil, err
 	}
@@ -194,7 +165,7 @@ func (q *Service) GetCommit(ctx context.Context, req *connect.Request[vcsv1.GetC
 	repo := gitURL.GetRepoName()
 	ref := req.Msg.GetRef()
 
-	commit, err := tryGetCommit(ctx, ghClient, owner, repo, ref)
+	commit, err := tryGetCommit(ctx, vcsClient, owner, repo, ref)
 	if err != nil {
 		return nil, err
 	}
@@ -209,49 +180,150 @@ func (q *Service) GetCommit(ctx context.Context, req *connect.Request[vcsv1.GetC
 }
 
 func (q *Service) GetCommits(ctx context.Context, req *connect.Request[vcsv1.GetCommitsRequest]) (*connect.Response[vcsv1.GetCommitsResponse], error) {
-	token, err := tokenFromRequest(ctx, req)
+	vcsClient, gitURL, err := q.repositoryClient(ctx, req, req.Msg.RepositoryUrl)
 	if err != nil {
-		q.logger.Log("err", err, "msg", "failed to extract token from request")
-		return nil, connect.NewError(connect.CodeUnauthenticated, fmt.Errorf("invalid token"))
+		return nil, err
 	}
 
-	err = rejectExpiredToken(token)
+	owner := gitURL.GetOwnerName()
+	repo := gitURL.GetRepoName()
+	refs := req.Msg.Refs
+
+	commits, failedFetches, err := getCommits(ctx, vcsClient, owner, repo, refs)
 	if err != nil {
+		q.logger.Log("err", err, "msg", "failed to get any commits", "owner", owner, "repo", repo)
 		return nil, err
 	}
 
-	gitURL, err := giturl.NewGitURL(req.Msg.RepositoryUrl)
+	if len(failedFetches) > 0 {
+		q.logger.Log("warn", "partial success fetching commits", "owner", owner, "repo", repo, "successCount", len(commits), "failureCount", len(failedFetches))
+		for _, fetchErr := range failedFetches {
+			q.logger.Log("err", fetchErr, "msg", "failed to fetch commit")
+		}
+	}
+
+	return connect.NewResponse(&vcsv1.GetCommitsResponse{Commits: commits}), nil
+}
+
+func (q *Service) OAuthApp(ctx context.Context, req *connect.Request[vcsv1.OAuthAppRequest]) (*connect.Response[vcsv1.OAuthAppResponse], error) {
+	p, _, err := resolveRepository(q.providers, req.Msg.RepositoryURL)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		return nil, err
 	}
 
-	if gitURL.GetProvider() != apis.ProviderGitHub.String() {
-		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("only GitHub repositories are supported"))
+	if err = p.checkConfigured(); err != nil {
+		q.logger.Log("err", err, "msg", "VCS integration is not configured", "provider", p.name)
+		return nil, connect.NewError(connect.CodeUnimplemented, fmt.Errorf("%s integration is not configured", p.name))
 	}
 
-	ghClient, err := client.GithubClient(ctx, token, q.httpClient)
+	return connect.NewResponse(&vcsv1.OAuthAppResponse{
+		Provider:     p.name,
+		ClientID:     p.clientID,
+		AuthorizeURL: p.endpoint.AuthURL,
+		CookieName:   p.cookieName,
+	}), nil
+}
+
+func (q *Service) OAuthLogin(ctx context.Context, req *connect.Request[vcsv1.OAuthLoginRequest]) (*connect.Response[vcsv1.OAuthLoginResponse], error) {
+	p, _, err := resolveRepository(q.providers, req.Msg.RepositoryURL)
 	if err != nil {
 		return nil, err
 	}
 
-	owner := gitURL.GetOwnerName()
-	repo := gitURL.GetRepoName()
-	refs := req.Msg.Refs
+	if err = p.checkConfigured(); err != nil {
+		q.logger.Log("err", err, "msg", "VCS integration is not configured", "provider", p.name)
+		return nil, connect.NewError(connect.CodeUnimplemented, fmt.Errorf("%s integration is not configured", p.name))
+	}
 
-	commits, failedFetches, err := getCommits(ctx, ghClient, owner, repo, refs)
+	encryptionKey, err := deriveEncryptionKeyForContext(ctx)
+	if err != nil {
+		q.logger.Log("err", err, "msg", "failed to derive encryption key")
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to authorize with %s", p.name))
+	}
+
+	exchangeCtx := context.WithValue(ctx, oauth2.HTTPClient, q.httpClient)
+	token, err := p.oauthConfig(req.Msg.RedirectURL).Exchange(exchangeCtx, req.Msg.AuthorizationCode)
+	if err != nil {
+		q.logger.Log("err", err, "msg", "failed to exchange authorization code", "provider", p.name)
+		return nil, connect.NewError(connect.CodeUnauthenticated, fmt.Errorf("failed to authorize with %s", p.name))
+	}
+
+	cookie, err := encodeToken(token, encryptionKey)
+	if err != nil {
+		q.logger.Log("err", err, "msg", "failed to encode OAuth token", "provider", p.name)
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to authorize with %s", p.name))
+	}
+	cookie.Name = p.cookieName
+
+	return connect.NewResponse(&vcsv1.OAuthLoginResponse{
+		Cookie: cookie.String(),
+	}), nil
+}
+
+func (q *Service) OAuthRefresh(ctx context.Context, req *connect.Request[vcsv1.OAuthRefreshRequest]) (*connect.Response[vcsv1.OAuthRefreshResponse], error) {
+	p, _, err := resolveRepository(q.providers, req.Msg.RepositoryURL)
 	if err != nil {
-		q.logger.Log("err", err, "msg", "failed to get any commits", "owner", owner, "repo", repo)
 		return nil, err
 	}
 
-	if len(failedFetches) > 0 {
-		q.logger.Log("warn", "partial success fetching commits", "owner", owner, "repo", repo, "successCount", len(commits), "failureCount", len(failedFetches))
-		for _, fetchErr := range failedFetches {
-			q.logger.Log("err", fetchErr, "msg", "failed to fetch commit")
-		}
+	token, err := tokenFromRequestCookie(ctx, req, p.cookieName)
+	if err != nil {
+		q.logger.Log("err", err, "msg", "failed to extract token from request")
+		return nil, connect.NewError(connect.CodeUnauthenticated, fmt.Errorf("invalid token"))
 	}
 
-	return connect.NewResponse(&vcsv1.GetCommitsResponse{Commits: commits}), nil
+	newToken, err := p.refreshToken(ctx, token, q.httpClient)
+	if err != nil {
+		q.logger.Log("err", err, "msg", "failed to refresh token", "provider", p.name)
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to refresh token"))
+	}
+
+	derivedKey, err := deriveEncryptionKeyForContext(ctx)
+	if err != nil {
+		q.logger.Log("err", err, "msg", "failed to derive encryption key")
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to process token"))
+	}
+
+	cookie, err := encodeToken(newToken, derivedKey)
+	if err != nil {
+		q.logger.Log("err", err, "msg", "failed to encode OAuth token", "provider", p.name)
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to refresh token"))
+	}
+	cookie.Name = p.cookieName
+
+	return connect.NewResponse(&vcsv1.OAuthRefreshResponse{
+		Cookie: cookie.String(),
+	}), nil
+}
+
+// repositoryClient resolves the provider hosting the repository and
+// returns a client authorized with the session token of the provider.
+// If the tenant has local repositories configured, they are used instead.
+func (q *Service) repositoryClient(ctx context.Context, req connect.AnyRequest, repositoryURL string) (vcsClient, giturl.IGitURL, error) {
+	if c, gitURL, ok, err := q.localRepositoryClient(ctx, repositoryURL); ok || err != nil {
+		return c, gitURL, err
+	}
+
+	p, gitURL, err := resolveRepository(q.providers, repositoryURL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	token, err := tokenFromRequestCookie(ctx, req, p.cookieName)
+	if err != nil {
+		q.logger.Log("err", err, "msg", "failed to extract token from request")
+		return nil, nil, connect.NewError(connect.CodeUnauthenticated, fmt.Errorf("invalid token"))
+	}
+
+	if err = rejectExpiredToken(token); err != nil {
+		return nil, nil, err
+	}
+
+	c, err := p.newClient(ctx, token, q.httpClient)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c, gitURL, nil
 }
 
 func rejectExpiredToken(token *oauth2.Token) error {
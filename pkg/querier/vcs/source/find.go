@@ -3,19 +3,26 @@ package source
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"path/filepath"
+	"strings"
 
 	"connectrpc.com/connect"
 	"github.com/go-kit/log"
 	giturl "github.com/kubescape/go-git-url"
+	"github.com/kubescape/go-git-url/apis"
 
 	vcsv1 "github.com/grafana/pyroscope/api/gen/proto/go/vcs/v1"
 	"github.com/grafana/pyroscope/pkg/querier/vcs/client"
 )
 
+// maxMetadataSize limits the size of package metadata documents.
+const maxMetadataSize = 32 << 20
+
 type VCSClient interface {
 	GetFile(ctx context.Context, req client.FileRequest) (client.File, error)
 }
@@ -48,10 +55,22 @@ func NewFileFinder(client VCSClient, repo giturl.IGitURL, path, rootPath, ref st
 
 // Find returns the file content and URL.
 func (ff FileFinder) Find(ctx context.Context) (*vcsv1.GetFileResponse, error) {
+	if strings.HasPrefix(ff.path, nodeBuiltinPrefix) {
+		return ff.findNodeFile(ctx)
+	}
 	switch filepath.Ext(ff.path) {
 	case ExtGo:
 		return ff.findGoFile(ctx)
-	// todo: add more languages support
+	case ExtJava, ExtKotlin:
+		return ff.findJavaFile(ctx)
+	case ExtPython:
+		return ff.findPythonFile(ctx)
+	case ExtRust:
+		return ff.findRustFile(ctx)
+	case ExtJavaScript, ExtJavaScriptModule, ExtJavaScriptCommon, ExtTypeScript:
+		return ff.findNodeFile(ctx)
+	case ExtCSharp, ExtFSharp:
+		return ff.findDotNetFile(ctx)
 	default:
 		// by default we return the file content at the given path without any processing.
 		content, err := ff.fetchRepoFile(ctx, ff.path, ff.ref)
@@ -76,6 +95,118 @@ func (arg FileFinder) fetchRepoFile(ctx context.Context, path, ref string) (*vcs
 	return newFileResponse(content.Content, content.URL)
 }
 
+// fetchFirstRepoFile fetches the first of the given paths found in the
+// configured repository. If none of the files exist, ErrNotFound is returned.
+func (ff FileFinder) fetchFirstRepoFile(ctx context.Context, ref string, paths ...string) (*vcsv1.GetFileResponse, error) {
+	err := client.ErrNotFound
+	for _, p := range paths {
+		var file *vcsv1.GetFileResponse
+		file, err = ff.fetchRepoFile(ctx, p, ref)
+		if err == nil {
+			return file, nil
+		}
+		if !errors.Is(err, client.ErrNotFound) {
+			return nil, err
+		}
+	}
+	return nil, err
+}
+
+// tryFindFile tries to find the file in the repo, under the rootPath.
+// It tries to find the file in the rootPath inside the repo by removing path segment after path segment.
+// maxAttempts is the maximum number of attempts to try to find the file in case the file path is very long.
+func (ff FileFinder) tryFindFile(ctx context.Context, path string, maxAttempts int) (*vcsv1.GetFileResponse, error) {
+	if maxAttempts <= 0 {
+		return nil, errors.New("invalid max attempts")
+	}
+	path = strings.TrimLeft(path, "/")
+	attempts := 0
+	for {
+		content, err := ff.client.GetFile(ctx, client.FileRequest{
+			Owner: ff.repo.GetOwnerName(),
+			Repo:  ff.repo.GetRepoName(),
+			Path:  strings.Join([]string{ff.rootPath, path}, "/"),
+			Ref:   ff.ref,
+		})
+		attempts++
+		if err != nil && errors.Is(err, client.ErrNotFound) && attempts < maxAttempts {
+			i := strings.Index(path, "/")
+			if i < 0 {
+				return nil, err
+			}
+			// remove the first path segment
+			path = path[i+1:]
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return newFileResponse(content.Content, content.URL)
+	}
+}
+
+// fetchGitHubFile fetches a file of a public GitHub repository, e.g. the
+// source of a dependency or a standard library. The VCS client is only used
+// if the configured repository is hosted at GitHub as well.
+func (ff FileFinder) fetchGitHubFile(ctx context.Context, owner, repo, path, ref string) (*vcsv1.GetFileResponse, error) {
+	if ff.repo.GetProvider() == apis.ProviderGitHub.String() {
+		content, err := ff.client.GetFile(ctx, client.FileRequest{
+			Owner: owner,
+			Repo:  repo,
+			Path:  path,
+			Ref:   ref,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return newFileResponse(content.Content, content.URL)
+	}
+	return ff.fetchURL(ctx, fmt.Sprintf("https://raw.githubusercontent.com/%s/%s/%s/%s", owner, repo, ref, strings.TrimLeft(path, "/")), false)
+}
+
+// fetchFirstGitHubFile fetches the first file found in a public GitHub
+// repository, trying all the given refs for each of the paths.
+func (ff FileFinder) fetchFirstGitHubFile(ctx context.Context, owner, repo string, refs, paths []string) (*vcsv1.GetFileResponse, error) {
+	err := client.ErrNotFound
+	for _, ref := range refs {
+		for _, p := range paths {
+			var file *vcsv1.GetFileResponse
+			file, err = ff.fetchGitHubFile(ctx, owner, repo, p, ref)
+			if err == nil {
+				return file, nil
+			}
+			if !errors.Is(err, client.ErrNotFound) {
+				return nil, err
+			}
+		}
+	}
+	return nil, err
+}
+
+// fetchJSON fetches a JSON document, e.g. package metadata
+// from a registry, and decodes it into v.
+func (ff FileFinder) fetchJSON(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+	// Some registries, e.g. crates.io, require the user agent to be set.
+	req.Header.Set("User-Agent", "pyroscope")
+	req.Header.Set("Accept", "application/json")
+	resp, err := ff.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %s", client.ErrNotFound, url)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch %s: %s", url, resp.Status)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, maxMetadataSize)).Decode(v)
+}
+
 // fetchURL fetches the file content from the given URL.
 func (ff FileFinder) fetchURL(ctx context.Context, url string, decodeBase64 bool) (*vcsv1.GetFileResponse, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
@@ -87,6 +218,9 @@ func (ff FileFinder) fetchURL(ctx context.Context, url string, decodeBase64 bool
 		return nil, err
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", client.ErrNotFound, url)
+	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("failed to fetch %s: %s", url, resp.Status))
 	}
@@ -0,0 +1,30 @@
+package source
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	vcsv1 "github.com/grafana/pyroscope/api/gen/proto/go/vcs/v1"
+)
+
+const (
+	ExtCSharp = ".cs"
+	ExtFSharp = ".fs"
+)
+
+// windowsDriveRe matches the drive letter of a Windows path.
+var windowsDriveRe = regexp.MustCompile(`^[a-zA-Z]:/`)
+
+// findDotNetFile finds a .NET source file in a vcs repository. The paths
+// recorded in the PDB files are the absolute paths on the build machine,
+// unless deterministic builds are enabled: then the repository root is
+// mapped to "/_/".
+func (ff FileFinder) findDotNetFile(ctx context.Context) (*vcsv1.GetFileResponse, error) {
+	filePath := strings.ReplaceAll(ff.path, "\\", "/")
+	if relativePath, ok := strings.CutPrefix(filePath, "/_/"); ok {
+		return ff.fetchRepoFile(ctx, relativePath, ff.ref)
+	}
+	filePath = windowsDriveRe.ReplaceAllString(filePath, "/")
+	return ff.tryFindFile(ctx, filePath, 30)
+}
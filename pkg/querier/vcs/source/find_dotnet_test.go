@@ -0,0 +1,33 @@
+package source
+
+import (
+	"io/fs"
+	"testing"
+)
+
+func Test_findDotNetFile(t *testing.T) {
+	repos := map[string]fs.FS{"grafana/example": fixtureRepo("dotnet")}
+	tests := map[string]fileFinderTest{
+		"deterministic build": {
+			path:            "/_/src/MyApp/Controllers/HomeController.cs",
+			repos:           repos,
+			expectedContent: readFixture(t, "dotnet/src/MyApp/Controllers/HomeController.cs"),
+			expectedURL:     "https://github.com/grafana/example/blob/HEAD/src/MyApp/Controllers/HomeController.cs",
+		},
+		"windows build path": {
+			path:            `C:\agent\work\1\s\src\MyApp\Controllers\HomeController.cs`,
+			repos:           repos,
+			expectedContent: readFixture(t, "dotnet/src/MyApp/Controllers/HomeController.cs"),
+			expectedURL:     "https://github.com/grafana/example/blob/HEAD/src/MyApp/Controllers/HomeController.cs",
+		},
+		"linux build path": {
+			path:            "/home/runner/work/example/example/src/MyApp/Controllers/HomeController.cs",
+			repos:           repos,
+			expectedContent: readFixture(t, "dotnet/src/MyApp/Controllers/HomeController.cs"),
+			expectedURL:     "https://github.com/grafana/example/blob/HEAD/src/MyApp/Controllers/HomeController.cs",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, tt.run)
+	}
+}
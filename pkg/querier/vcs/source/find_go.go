@@ -2,7 +2,6 @@ package source
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"path"
 	"strings"
@@ -109,33 +108,6 @@ func (ff FileFinder) fetchGoogleSourceDependencyFile(ctx context.Context, mod go
 // - "path/to/module1/log/log.go"
 // - "path/to/module1/log.go"
 func (ff FileFinder) tryFindGoFile(ctx context.Context, maxAttempts int) (*vcsv1.GetFileResponse, error) {
-	if maxAttempts <= 0 {
-		return nil, errors.New("invalid max attempts")
-	}
-	// Try to find the file in the repo.
 	path := strings.TrimPrefix(ff.path, strings.Join([]string{ff.repo.GetHostName(), ff.repo.GetOwnerName(), ff.repo.GetRepoName()}, "/"))
-	path = strings.TrimLeft(path, "/")
-	attempts := 0
-	for {
-		content, err := ff.client.GetFile(ctx, client.FileRequest{
-			Owner: ff.repo.GetOwnerName(),
-			Repo:  ff.repo.GetRepoName(),
-			Path:  strings.Join([]string{ff.rootPath, path}, "/"),
-			Ref:   ff.ref,
-		})
-		attempts++
-		if err != nil && errors.Is(err, client.ErrNotFound) && attempts < maxAttempts {
-			i := strings.Index(path, "/")
-			if i < 0 {
-				return nil, err
-			}
-			// remove the first path segment
-			path = path[i+1:]
-			continue
-		}
-		if err != nil {
-			return nil, err
-		}
-		return newFileResponse(content.Content, content.URL)
-	}
+	return ff.tryFindFile(ctx, path, maxAttempts)
 }
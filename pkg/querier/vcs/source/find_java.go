@@ -0,0 +1,138 @@
+package source
+
+import (
+	"context"
+	"encoding/xml"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/go-kit/log/level"
+
+	vcsv1 "github.com/grafana/pyroscope/api/gen/proto/go/vcs/v1"
+	"github.com/grafana/pyroscope/pkg/querier/vcs/client"
+)
+
+const (
+	ExtJava   = ".java"
+	ExtKotlin = ".kt"
+)
+
+// javaSourceRoots are the source directories of a Maven or Gradle module.
+var javaSourceRoots = []string{
+	"src/main/java",
+	"src/main/kotlin",
+	"src/test/java",
+	"src/test/kotlin",
+	"src",
+}
+
+// gradleIncludeRe matches the projects included in a Gradle settings
+// file, e.g. include("core", ":services:api") or include 'core'.
+var gradleIncludeRe = regexp.MustCompile(`(?m)^\s*include\s*\(?([^)\n]+)\)?`)
+
+// findJavaFile finds a Java or Kotlin file in a vcs repository. Profilers
+// usually report the path of the source file relative to the package root,
+// e.g. "com/example/Foo.java", which is looked up in the source roots of all
+// the Maven or Gradle modules of the repository.
+func (ff FileFinder) findJavaFile(ctx context.Context) (*vcsv1.GetFileResponse, error) {
+	filePath := javaPackagePath(ff.path)
+	if strings.Contains(filePath, "src/") {
+		// The path already includes the source root.
+		return ff.tryFindFile(ctx, filePath, 30)
+	}
+
+	modules := []string{""}
+	modules = append(modules, ff.fetchJavaModules(ctx)...)
+	paths := make([]string, 0, len(modules)*len(javaSourceRoots)+1)
+	for _, m := range modules {
+		for _, root := range javaSourceRoots {
+			paths = append(paths, path.Join(ff.rootPath, m, root, filePath))
+		}
+	}
+	paths = append(paths, path.Join(ff.rootPath, filePath))
+	return ff.fetchFirstRepoFile(ctx, ff.ref, paths...)
+}
+
+// javaPackagePath converts the file path reported by the profiler to the
+// path relative to the package root: the fully qualified class name is
+// converted to the path, and the nested class name is removed.
+func javaPackagePath(filePath string) string {
+	filePath = strings.ReplaceAll(filePath, "\\", "/")
+	ext := path.Ext(filePath)
+	name := strings.TrimSuffix(filePath, ext)
+	if !strings.Contains(name, "/") {
+		// com.example.Foo.java
+		name = strings.ReplaceAll(name, ".", "/")
+	}
+	if i := strings.Index(path.Base(name), "$"); i >= 0 {
+		// com/example/Foo$Bar.java
+		name = name[:len(name)-len(path.Base(name))+i]
+	}
+	return strings.TrimLeft(name, "/") + ext
+}
+
+// fetchJavaModules returns the module directories of a Maven or Gradle
+// multi-module project at the root path.
+func (ff FileFinder) fetchJavaModules(ctx context.Context) []string {
+	var modules []string
+	for _, name := range []string{"pom.xml", "settings.gradle", "settings.gradle.kts"} {
+		content, err := ff.client.GetFile(ctx, client.FileRequest{
+			Owner: ff.repo.GetOwnerName(),
+			Repo:  ff.repo.GetRepoName(),
+			Path:  path.Join(ff.rootPath, name),
+			Ref:   ff.ref,
+		})
+		if err != nil {
+			continue
+		}
+		if name == "pom.xml" {
+			m, err := parseMavenModules(content.Content)
+			if err != nil {
+				level.Warn(ff.logger).Log("msg", "failed to parse pom.xml", "err", err)
+				continue
+			}
+			modules = append(modules, m...)
+		} else {
+			modules = append(modules, parseGradleModules(content.Content)...)
+		}
+	}
+	return modules
+}
+
+// parseMavenModules returns the modules of a Maven project.
+func parseMavenModules(pom string) ([]string, error) {
+	var project struct {
+		Modules  []string `xml:"modules>module"`
+		Profiles []struct {
+			Modules []string `xml:"modules>module"`
+		} `xml:"profiles>profile"`
+	}
+	if err := xml.Unmarshal([]byte(pom), &project); err != nil {
+		return nil, err
+	}
+	modules := project.Modules
+	for _, p := range project.Profiles {
+		modules = append(modules, p.Modules...)
+	}
+	for i, m := range modules {
+		modules[i] = path.Clean(strings.TrimSpace(m))
+	}
+	return modules, nil
+}
+
+// parseGradleModules returns the projects included in a Gradle build.
+// Project paths, e.g. ":services:api", are converted to directories.
+func parseGradleModules(settings string) []string {
+	var modules []string
+	for _, m := range gradleIncludeRe.FindAllStringSubmatch(settings, -1) {
+		for _, p := range strings.Split(m[1], ",") {
+			p = strings.Trim(strings.TrimSpace(p), `"'`)
+			p = strings.ReplaceAll(strings.TrimPrefix(p, ":"), ":", "/")
+			if p != "" {
+				modules = append(modules, p)
+			}
+		}
+	}
+	return modules
+}
@@ -0,0 +1,80 @@
+package source
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/pyroscope/pkg/querier/vcs/client"
+)
+
+func Test_findJavaFile(t *testing.T) {
+	maven := map[string]fs.FS{"grafana/example": fixtureRepo("java-maven")}
+	tests := map[string]fileFinderTest{
+		"maven module": {
+			path:            "com/example/lib/Lib.java",
+			repos:           maven,
+			expectedContent: readFixture(t, "java-maven/lib/src/main/java/com/example/lib/Lib.java"),
+			expectedURL:     "https://github.com/grafana/example/blob/HEAD/lib/src/main/java/com/example/lib/Lib.java",
+		},
+		"fully qualified class name": {
+			path:            "com.example.app.App.java",
+			ref:             "main",
+			repos:           maven,
+			expectedContent: readFixture(t, "java-maven/app/src/main/java/com/example/app/App.java"),
+			expectedURL:     "https://github.com/grafana/example/blob/main/app/src/main/java/com/example/app/App.java",
+		},
+		"nested class": {
+			path:            "com/example/app/App$Inner.java",
+			repos:           maven,
+			expectedContent: readFixture(t, "java-maven/app/src/main/java/com/example/app/App.java"),
+			expectedURL:     "https://github.com/grafana/example/blob/HEAD/app/src/main/java/com/example/app/App.java",
+		},
+		"path with source root": {
+			path:            "/build/workspace/lib/src/main/java/com/example/lib/Lib.java",
+			repos:           maven,
+			expectedContent: readFixture(t, "java-maven/lib/src/main/java/com/example/lib/Lib.java"),
+			expectedURL:     "https://github.com/grafana/example/blob/HEAD/lib/src/main/java/com/example/lib/Lib.java",
+		},
+		"gradle subproject": {
+			path:            "com/example/api/Handler.kt",
+			repos:           map[string]fs.FS{"grafana/example": fixtureRepo("java-gradle")},
+			expectedContent: readFixture(t, "java-gradle/services/api/src/main/kotlin/com/example/api/Handler.kt"),
+			expectedURL:     "https://github.com/grafana/example/blob/HEAD/services/api/src/main/kotlin/com/example/api/Handler.kt",
+		},
+		"not found": {
+			path:          "com/example/Missing.java",
+			repos:         maven,
+			expectedError: client.ErrNotFound,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, tt.run)
+	}
+}
+
+func Test_javaPackagePath(t *testing.T) {
+	for input, expected := range map[string]string{
+		"com/example/Foo.java":     "com/example/Foo.java",
+		"com.example.Foo.java":     "com/example/Foo.java",
+		"com/example/Foo$Bar.java": "com/example/Foo.java",
+		"com\\example\\Foo.kt":     "com/example/Foo.kt",
+		"Foo.java":                 "Foo.java",
+	} {
+		assert.Equal(t, expected, javaPackagePath(input), input)
+	}
+}
+
+func Test_parseJavaModules(t *testing.T) {
+	modules, err := parseMavenModules(readFixture(t, "java-maven/pom.xml"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app", "lib"}, modules)
+
+	assert.Equal(t, []string{"core", "services/api", "web"}, parseGradleModules(`
+rootProject.name = "example"
+include("core", ":services:api")
+include 'web'
+`))
+}
@@ -0,0 +1,170 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	giturl "github.com/kubescape/go-git-url"
+	"github.com/kubescape/go-git-url/apis"
+
+	vcsv1 "github.com/grafana/pyroscope/api/gen/proto/go/vcs/v1"
+	"github.com/grafana/pyroscope/pkg/querier/vcs/client"
+)
+
+const (
+	ExtJavaScript       = ".js"
+	ExtJavaScriptModule = ".mjs"
+	ExtJavaScriptCommon = ".cjs"
+	ExtTypeScript       = ".ts"
+
+	npmRegistryURL    = "https://registry.npmjs.org"
+	nodeModulesDir    = "node_modules/"
+	nodeBuiltinPrefix = "node:"
+)
+
+// findNodeFile finds a JavaScript or TypeScript file in a vcs repository.
+func (ff FileFinder) findNodeFile(ctx context.Context) (*vcsv1.GetFileResponse, error) {
+	filePath := nodeSourcePath(ff.path)
+
+	// Node.js built-in modules, e.g. node:internal/process/task_queues.
+	if builtin, ok := strings.CutPrefix(filePath, nodeBuiltinPrefix); ok {
+		if path.Ext(builtin) == "" {
+			builtin += ExtJavaScript
+		}
+		return ff.fetchGitHubFile(ctx, "nodejs", "node", path.Join("lib", builtin), "HEAD")
+	}
+
+	if i := strings.LastIndex(filePath, nodeModulesDir); i >= 0 {
+		name, relativePath, ok := splitNodeModulePath(filePath[i+len(nodeModulesDir):])
+		if ok {
+			return ff.fetchNodeModuleFile(ctx, name, relativePath)
+		}
+	}
+
+	return ff.tryFindFile(ctx, filePath, 30)
+}
+
+// nodeSourcePath removes the bundler and URL prefixes from the path.
+func nodeSourcePath(filePath string) string {
+	filePath = strings.ReplaceAll(filePath, "\\", "/")
+	if s, ok := strings.CutPrefix(filePath, "file://"); ok {
+		return s
+	}
+	if s, ok := strings.CutPrefix(filePath, "webpack://"); ok {
+		// webpack://<namespace>/./src/index.ts
+		if i := strings.Index(s, "/"); i >= 0 {
+			s = s[i+1:]
+		}
+		return strings.TrimPrefix(s, "./")
+	}
+	return filePath
+}
+
+// splitNodeModulePath splits the path relative to the node_modules directory
+// into the package name, which may be scoped, and the path in the package.
+func splitNodeModulePath(p string) (name, relativePath string, ok bool) {
+	segments := strings.SplitN(p, "/", 3)
+	if strings.HasPrefix(p, "@") {
+		if len(segments) < 3 {
+			return "", "", false
+		}
+		return segments[0] + "/" + segments[1], segments[2], true
+	}
+	if len(segments) < 2 {
+		return "", "", false
+	}
+	return segments[0], strings.Join(segments[1:], "/"), true
+}
+
+// fetchNodeModuleFile fetches a file of the package from the upstream
+// repository declared in the npm registry metadata. The version of the
+// package is taken from the package-lock.json of the repository, if present.
+func (ff FileFinder) fetchNodeModuleFile(ctx context.Context, name, filePath string) (*vcsv1.GetFileResponse, error) {
+	var metadata struct {
+		Repository json.RawMessage `json:"repository"`
+	}
+	// Scoped package names are escaped as a single path segment.
+	if err := ff.fetchJSON(ctx, npmRegistryURL+"/"+url.PathEscape(name), &metadata); err != nil {
+		return nil, err
+	}
+	repoURL, directory := parseNPMRepository(metadata.Repository)
+	if repoURL == "" {
+		return nil, fmt.Errorf("package %s has no repository", name)
+	}
+	repo, err := giturl.NewGitURL(repoURL)
+	if err != nil {
+		return nil, err
+	}
+	if repo.GetProvider() != apis.ProviderGitHub.String() {
+		return nil, fmt.Errorf("unsupported repository of package %s: %s", name, repoURL)
+	}
+
+	refs := []string{"HEAD"}
+	if version := ff.lockedNodeModuleVersion(ctx, name); version != "" {
+		refs = []string{
+			"v" + version,
+			version,
+			name + "@" + version,
+			"HEAD",
+		}
+	}
+	return ff.fetchFirstGitHubFile(ctx, repo.GetOwnerName(), repo.GetRepoName(), refs, []string{path.Join(directory, filePath)})
+}
+
+// parseNPMRepository parses the repository field of the package metadata,
+// which is either an object with the URL and the package directory in
+// a monorepo, or a shorthand string like "github:user/repo" or "user/repo".
+func parseNPMRepository(raw json.RawMessage) (repoURL, directory string) {
+	var repository struct {
+		URL       string `json:"url"`
+		Directory string `json:"directory"`
+	}
+	if err := json.Unmarshal(raw, &repository); err != nil {
+		if err = json.Unmarshal(raw, &repository.URL); err != nil {
+			return "", ""
+		}
+	}
+	repoURL = strings.TrimPrefix(repository.URL, "git+")
+	switch {
+	case repoURL == "":
+	case strings.HasPrefix(repoURL, "github:"):
+		repoURL = "https://github.com/" + strings.TrimPrefix(repoURL, "github:")
+	case !strings.Contains(repoURL, ":"):
+		repoURL = "https://github.com/" + repoURL
+	}
+	return repoURL, repository.Directory
+}
+
+// lockedNodeModuleVersion returns the version of the package
+// locked in the package-lock.json of the repository.
+func (ff FileFinder) lockedNodeModuleVersion(ctx context.Context, name string) string {
+	content, err := ff.client.GetFile(ctx, client.FileRequest{
+		Owner: ff.repo.GetOwnerName(),
+		Repo:  ff.repo.GetRepoName(),
+		Path:  path.Join(ff.rootPath, "package-lock.json"),
+		Ref:   ff.ref,
+	})
+	if err != nil {
+		return ""
+	}
+	var lock struct {
+		Packages map[string]struct {
+			Version string `json:"version"`
+		} `json:"packages"`
+		// lockfileVersion 1.
+		Dependencies map[string]struct {
+			Version string `json:"version"`
+		} `json:"dependencies"`
+	}
+	if err = json.Unmarshal([]byte(content.Content), &lock); err != nil {
+		return ""
+	}
+	if p, ok := lock.Packages[nodeModulesDir+name]; ok {
+		return p.Version
+	}
+	return lock.Dependencies[name].Version
+}
@@ -0,0 +1,75 @@
+package source
+
+import (
+	"encoding/json"
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_findNodeFile(t *testing.T) {
+	repos := map[string]fs.FS{"grafana/example": fixtureRepo("node")}
+	tests := map[string]fileFinderTest{
+		"application source": {
+			path:            "/app/src/index.ts",
+			repos:           repos,
+			expectedContent: readFixture(t, "node/src/index.ts"),
+			expectedURL:     "https://github.com/grafana/example/blob/HEAD/src/index.ts",
+		},
+		"webpack source": {
+			path:            "webpack://example/./src/index.ts",
+			repos:           repos,
+			expectedContent: readFixture(t, "node/src/index.ts"),
+			expectedURL:     "https://github.com/grafana/example/blob/HEAD/src/index.ts",
+		},
+		"locked package": {
+			path: "/app/node_modules/express/lib/router/index.js",
+			http: fixtureTransport{
+				"https://registry.npmjs.org/express": `{"repository":{"type":"git","url":"git+https://github.com/expressjs/express.git"}}`,
+			},
+			repos: map[string]fs.FS{
+				"grafana/example":          fixtureRepo("node"),
+				"expressjs/express@4.19.2": fstestMap("lib/router/index.js", "// router"),
+			},
+			expectedContent: "// router",
+			expectedURL:     "https://github.com/expressjs/express/blob/4.19.2/lib/router/index.js",
+		},
+		"scoped package in monorepo": {
+			path: "/app/node_modules/@fastify/busboy/lib/main.js",
+			http: fixtureTransport{
+				"https://registry.npmjs.org/@fastify%2Fbusboy": `{"repository":{"url":"https://github.com/fastify/busboy.git","directory":"packages/busboy"}}`,
+			},
+			repos: map[string]fs.FS{
+				"grafana/example":       fixtureRepo("node"),
+				"fastify/busboy@v2.1.1": fstestMap("packages/busboy/lib/main.js", "// busboy"),
+			},
+			expectedContent: "// busboy",
+			expectedURL:     "https://github.com/fastify/busboy/blob/v2.1.1/packages/busboy/lib/main.js",
+		},
+		"built-in module": {
+			path: "node:internal/process/task_queues",
+			repos: map[string]fs.FS{
+				"nodejs/node": fstestMap("lib/internal/process/task_queues.js", "// task queues"),
+			},
+			expectedContent: "// task queues",
+			expectedURL:     "https://github.com/nodejs/node/blob/HEAD/lib/internal/process/task_queues.js",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, tt.run)
+	}
+}
+
+func Test_parseNPMRepository(t *testing.T) {
+	for input, expected := range map[string][2]string{
+		`{"type":"git","url":"git+https://github.com/expressjs/express.git"}`:            {"https://github.com/expressjs/express.git", ""},
+		`{"url":"https://github.com/babel/babel.git","directory":"packages/babel-core"}`: {"https://github.com/babel/babel.git", "packages/babel-core"},
+		`"github:user/repo"`: {"https://github.com/user/repo", ""},
+		`"user/repo"`:        {"https://github.com/user/repo", ""},
+		`null`:               {"", ""},
+	} {
+		repoURL, directory := parseNPMRepository(json.RawMessage(input))
+		assert.Equal(t, expected, [2]string{repoURL, directory}, input)
+	}
+}
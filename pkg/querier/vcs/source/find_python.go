@@ -0,0 +1,50 @@
+package source
+
+import (
+	"context"
+	"path"
+	"regexp"
+	"strings"
+
+	vcsv1 "github.com/grafana/pyroscope/api/gen/proto/go/vcs/v1"
+)
+
+const (
+	ExtPython = ".py"
+)
+
+// pythonStdlibRe matches the path of a standard library module,
+// e.g. /usr/local/lib/python3.12/json/decoder.py.
+var pythonStdlibRe = regexp.MustCompile(`(?:^|/)lib/python(3\.\d+)/(.+)$`)
+
+// findPythonFile finds a python file in a vcs repository.
+func (ff FileFinder) findPythonFile(ctx context.Context) (*vcsv1.GetFileResponse, error) {
+	filePath := strings.ReplaceAll(ff.path, "\\", "/")
+
+	// Packages installed in a virtualenv or system-wide: the path relative
+	// to site-packages is the import path of the module, which is looked up
+	// at the repository root and in the src layout.
+	if relativePath, ok := pythonSitePackagesRelativePath(filePath); ok {
+		return ff.fetchFirstRepoFile(ctx, ff.ref,
+			path.Join(ff.rootPath, relativePath),
+			path.Join(ff.rootPath, "src", relativePath),
+		)
+	}
+
+	if m := pythonStdlibRe.FindStringSubmatch(filePath); m != nil {
+		return ff.fetchGitHubFile(ctx, "python", "cpython", path.Join("Lib", m[2]), m[1])
+	}
+
+	return ff.tryFindFile(ctx, filePath, 30)
+}
+
+// pythonSitePackagesRelativePath returns the path relative
+// to the site-packages or dist-packages directory.
+func pythonSitePackagesRelativePath(filePath string) (string, bool) {
+	for _, dir := range []string{"site-packages/", "dist-packages/"} {
+		if i := strings.LastIndex(filePath, dir); i >= 0 {
+			return filePath[i+len(dir):], true
+		}
+	}
+	return "", false
+}
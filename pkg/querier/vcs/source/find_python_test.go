@@ -0,0 +1,50 @@
+package source
+
+import (
+	"io/fs"
+	"testing"
+)
+
+func Test_findPythonFile(t *testing.T) {
+	repos := map[string]fs.FS{"grafana/example": fixtureRepo("python")}
+	tests := map[string]fileFinderTest{
+		"virtualenv site-packages": {
+			path:            "/app/.venv/lib/python3.12/site-packages/mypkg/handlers.py",
+			repos:           repos,
+			expectedContent: readFixture(t, "python/src/mypkg/handlers.py"),
+			expectedURL:     "https://github.com/grafana/example/blob/HEAD/src/mypkg/handlers.py",
+		},
+		"dist-packages": {
+			path:            "/usr/lib/python3/dist-packages/mypkg/handlers.py",
+			repos:           repos,
+			expectedContent: readFixture(t, "python/src/mypkg/handlers.py"),
+			expectedURL:     "https://github.com/grafana/example/blob/HEAD/src/mypkg/handlers.py",
+		},
+		"application path": {
+			path:            "/srv/app/app.py",
+			repos:           repos,
+			expectedContent: readFixture(t, "python/app.py"),
+			expectedURL:     "https://github.com/grafana/example/blob/HEAD/app.py",
+		},
+		"standard library": {
+			path: "/usr/local/lib/python3.12/json/decoder.py",
+			repos: map[string]fs.FS{
+				"python/cpython@3.12": fstestMap("Lib/json/decoder.py", "# json decoder"),
+			},
+			expectedContent: "# json decoder",
+			expectedURL:     "https://github.com/python/cpython/blob/3.12/Lib/json/decoder.py",
+		},
+		"standard library from another provider": {
+			path: "/usr/local/lib/python3.12/json/decoder.py",
+			repo: "https://gitlab.com/grafana/example",
+			http: fixtureTransport{
+				"https://raw.githubusercontent.com/python/cpython/3.12/Lib/json/decoder.py": "# json decoder",
+			},
+			expectedContent: "# json decoder",
+			expectedURL:     "https://raw.githubusercontent.com/python/cpython/3.12/Lib/json/decoder.py",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, tt.run)
+	}
+}
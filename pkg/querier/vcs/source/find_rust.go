@@ -0,0 +1,89 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	giturl "github.com/kubescape/go-git-url"
+	"github.com/kubescape/go-git-url/apis"
+
+	vcsv1 "github.com/grafana/pyroscope/api/gen/proto/go/vcs/v1"
+)
+
+const (
+	ExtRust = ".rs"
+
+	cratesIOURL = "https://crates.io/api/v1/crates"
+)
+
+var (
+	// rustStdlibRe matches the path of the standard library sources, which
+	// are remapped to /rustc/<commit hash>, e.g.
+	// /rustc/90b35a6239c3d8bdabc530a6a0816f7ff89a0aaf/library/core/src/ops/function.rs
+	rustStdlibRe = regexp.MustCompile(`^/rustc/([0-9a-f]{40})/(.+)$`)
+
+	// rustRegistryRe matches the path of a crate downloaded from a registry, e.g.
+	// /home/user/.cargo/registry/src/index.crates.io-6f17d22bba15001f/tokio-1.28.0/src/runtime/mod.rs
+	rustRegistryRe = regexp.MustCompile(`/registry/src/[^/]+/([^/]+)/(.+)$`)
+
+	// rustCrateRe splits a crate directory name into the crate name and
+	// the version. Note that crate names may include dashes and digits.
+	rustCrateRe = regexp.MustCompile(`^(.+?)-(\d+\.\d+\.\d+\S*)$`)
+)
+
+// findRustFile finds a rust file in a vcs repository.
+func (ff FileFinder) findRustFile(ctx context.Context) (*vcsv1.GetFileResponse, error) {
+	filePath := strings.ReplaceAll(ff.path, "\\", "/")
+
+	if m := rustStdlibRe.FindStringSubmatch(filePath); m != nil {
+		return ff.fetchGitHubFile(ctx, "rust-lang", "rust", m[2], m[1])
+	}
+
+	if m := rustRegistryRe.FindStringSubmatch(filePath); m != nil {
+		if c := rustCrateRe.FindStringSubmatch(m[1]); c != nil {
+			return ff.fetchCrateFile(ctx, c[1], c[2], m[2])
+		}
+	}
+
+	return ff.tryFindFile(ctx, filePath, 30)
+}
+
+// fetchCrateFile fetches a file of the crate from the upstream repository
+// declared in the crates.io metadata. The version tag of the crate is
+// looked up using the most common naming conventions; the crate may be
+// located at the repository root or in a workspace directory named after
+// the crate.
+func (ff FileFinder) fetchCrateFile(ctx context.Context, name, version, filePath string) (*vcsv1.GetFileResponse, error) {
+	var metadata struct {
+		Crate struct {
+			Repository string `json:"repository"`
+		} `json:"crate"`
+	}
+	if err := ff.fetchJSON(ctx, cratesIOURL+"/"+url.PathEscape(name), &metadata); err != nil {
+		return nil, err
+	}
+	if metadata.Crate.Repository == "" {
+		return nil, fmt.Errorf("crate %s has no repository", name)
+	}
+	repo, err := giturl.NewGitURL(metadata.Crate.Repository)
+	if err != nil {
+		return nil, err
+	}
+	if repo.GetProvider() != apis.ProviderGitHub.String() {
+		return nil, fmt.Errorf("unsupported repository of crate %s: %s", name, metadata.Crate.Repository)
+	}
+	refs := []string{
+		"v" + version,
+		version,
+		name + "-v" + version,
+		name + "-" + version,
+	}
+	paths := []string{
+		filePath,
+		name + "/" + filePath,
+	}
+	return ff.fetchFirstGitHubFile(ctx, repo.GetOwnerName(), repo.GetRepoName(), refs, paths)
+}
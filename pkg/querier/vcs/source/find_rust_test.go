@@ -0,0 +1,65 @@
+package source
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_findRustFile(t *testing.T) {
+	tests := map[string]fileFinderTest{
+		"crate source": {
+			path:            "/home/user/project/src/main.rs",
+			repos:           map[string]fs.FS{"grafana/example": fixtureRepo("rust")},
+			expectedContent: readFixture(t, "rust/src/main.rs"),
+			expectedURL:     "https://github.com/grafana/example/blob/HEAD/src/main.rs",
+		},
+		"standard library": {
+			path: "/rustc/90b35a6239c3d8bdabc530a6a0816f7ff89a0aaf/library/core/src/ops/function.rs",
+			repos: map[string]fs.FS{
+				"rust-lang/rust@90b35a6239c3d8bdabc530a6a0816f7ff89a0aaf": fstestMap("library/core/src/ops/function.rs", "// function"),
+			},
+			expectedContent: "// function",
+			expectedURL:     "https://github.com/rust-lang/rust/blob/90b35a6239c3d8bdabc530a6a0816f7ff89a0aaf/library/core/src/ops/function.rs",
+		},
+		"registry crate in workspace": {
+			path: "/root/.cargo/registry/src/index.crates.io-6f17d22bba15001f/tokio-1.28.0/src/runtime/mod.rs",
+			http: fixtureTransport{
+				"https://crates.io/api/v1/crates/tokio": `{"crate":{"repository":"https://github.com/tokio-rs/tokio"}}`,
+			},
+			repos: map[string]fs.FS{
+				"tokio-rs/tokio@tokio-1.28.0": fstestMap("tokio/src/runtime/mod.rs", "// runtime"),
+			},
+			expectedContent: "// runtime",
+			expectedURL:     "https://github.com/tokio-rs/tokio/blob/tokio-1.28.0/tokio/src/runtime/mod.rs",
+		},
+		"registry crate": {
+			path: "/root/.cargo/registry/src/github.com-1ecc6299db9ec823/sha-1-0.10.1/src/lib.rs",
+			http: fixtureTransport{
+				"https://crates.io/api/v1/crates/sha-1": `{"crate":{"repository":"https://github.com/RustCrypto/hashes"}}`,
+			},
+			repos: map[string]fs.FS{
+				"RustCrypto/hashes@v0.10.1": fstestMap("src/lib.rs", "// sha-1"),
+			},
+			expectedContent: "// sha-1",
+			expectedURL:     "https://github.com/RustCrypto/hashes/blob/v0.10.1/src/lib.rs",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, tt.run)
+	}
+}
+
+func Test_rustCrateRe(t *testing.T) {
+	for input, expected := range map[string][]string{
+		"tokio-1.28.0":              {"tokio", "1.28.0"},
+		"sha-1-0.10.1":              {"sha-1", "0.10.1"},
+		"h2-0.3.20":                 {"h2", "0.3.20"},
+		"windows_x86_64_gnu-0.48.5": {"windows_x86_64_gnu", "0.48.5"},
+		"ring-0.17.0-alpha.1":       {"ring", "0.17.0-alpha.1"},
+	} {
+		m := rustCrateRe.FindStringSubmatch(input)
+		assert.Equal(t, expected, m[1:], input)
+	}
+}
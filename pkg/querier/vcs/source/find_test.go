@@ -0,0 +1,133 @@
+package source
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-kit/log"
+	giturl "github.com/kubescape/go-git-url"
+	"github.com/stretchr/testify/require"
+
+	vcsv1 "github.com/grafana/pyroscope/api/gen/proto/go/vcs/v1"
+	"github.com/grafana/pyroscope/pkg/querier/vcs/client"
+)
+
+// fixtureClient serves the files of fixture repositories. Repositories are
+// keyed by "owner/repo", or by "owner/repo@ref" if only the given ref
+// has to be served.
+type fixtureClient struct {
+	repos    map[string]fs.FS
+	requests []string
+}
+
+func (c *fixtureClient) GetFile(_ context.Context, req client.FileRequest) (client.File, error) {
+	repo := req.Owner + "/" + req.Repo
+	c.requests = append(c.requests, repo+"@"+req.Ref+":"+req.Path)
+	fsys, ok := c.repos[repo+"@"+req.Ref]
+	if !ok {
+		if fsys, ok = c.repos[repo]; !ok {
+			return client.File{}, client.ErrNotFound
+		}
+	}
+	content, err := fs.ReadFile(fsys, strings.TrimLeft(req.Path, "/"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return client.File{}, client.ErrNotFound
+	}
+	if err != nil {
+		return client.File{}, err
+	}
+	return client.File{
+		Content: string(content),
+		URL:     "https://github.com/" + repo + "/blob/" + req.Ref + "/" + strings.TrimLeft(req.Path, "/"),
+	}, nil
+}
+
+// fixtureTransport serves the given documents by URL.
+type fixtureTransport map[string]string
+
+func (t fixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, ok := t[req.URL.String()]
+	status := http.StatusOK
+	if !ok {
+		status = http.StatusNotFound
+	}
+	return &http.Response{
+		StatusCode: status,
+		Status:     http.StatusText(status),
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+type fileFinderTest struct {
+	path     string
+	rootPath string
+	ref      string
+	repo     string
+	repos    map[string]fs.FS
+	http     fixtureTransport
+
+	expectedContent string
+	expectedURL     string
+	expectedError   error
+}
+
+func (tt fileFinderTest) run(t *testing.T) {
+	t.Helper()
+	repoURL := tt.repo
+	if repoURL == "" {
+		repoURL = "https://github.com/grafana/example"
+	}
+	repo, err := giturl.NewGitURL(repoURL)
+	require.NoError(t, err)
+	ff := NewFileFinder(
+		&fixtureClient{repos: tt.repos},
+		repo,
+		tt.path,
+		tt.rootPath,
+		tt.ref,
+		&http.Client{Transport: tt.http},
+		log.NewNopLogger(),
+	)
+	file, err := ff.Find(context.Background())
+	if tt.expectedError != nil {
+		require.ErrorIs(t, err, tt.expectedError)
+		return
+	}
+	require.NoError(t, err)
+	requireFileResponse(t, file, tt.expectedContent, tt.expectedURL)
+}
+
+func requireFileResponse(t *testing.T, file *vcsv1.GetFileResponse, content, url string) {
+	t.Helper()
+	decoded, err := base64.StdEncoding.DecodeString(file.Content)
+	require.NoError(t, err)
+	require.Equal(t, content, string(decoded))
+	require.Equal(t, url, file.URL)
+}
+
+// fixtureRepo returns the fixture repository in testdata.
+func fixtureRepo(name string) fs.FS {
+	return os.DirFS(path.Join("testdata", name))
+}
+
+func readFixture(t *testing.T, name string) string {
+	t.Helper()
+	b, err := os.ReadFile(path.Join("testdata", name))
+	require.NoError(t, err)
+	return string(b)
+}
+
+// fstestMap returns a repository with a single file.
+func fstestMap(name, content string) fs.FS {
+	return fstest.MapFS{name: &fstest.MapFile{Data: []byte(content)}}
+}
@@ -0,0 +1,6 @@
+namespace MyApp.Controllers;
+
+public class HomeController
+{
+    public string Index() => "ok";
+}
@@ -0,0 +1,5 @@
+package com.example.api
+
+class Handler {
+    fun handle(): String = "ok"
+}
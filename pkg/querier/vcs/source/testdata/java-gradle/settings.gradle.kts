@@ -0,0 +1,3 @@
+rootProject.name = "example"
+
+include(":services:api")
@@ -0,0 +1,7 @@
+package com.example.lib;
+
+public class Lib {
+    public String greeting() {
+        return "hello";
+    }
+}
@@ -0,0 +1 @@
+export const handler = (): string => "ok";
@@ -0,0 +1,3 @@
+from mypkg.handlers import handle
+
+print(handle("request"))
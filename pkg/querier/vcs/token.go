@@ -11,6 +11,7 @@ import (
 	"net/url"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"connectrpc.com/connect"
@@ -83,11 +84,30 @@ func getDurationValueFrom(values url.Values, key string, scalar time.Duration) (
 	return time.Duration(n) * scalar, nil
 }
 
+// SessionCookies returns the cookies holding the session tokens of the VCS
+// providers. The cookies are encrypted with a key specific to the tenant,
+// and can only be used by the VCS service.
+func SessionCookies(header http.Header) []*http.Cookie {
+	var cookies []*http.Cookie
+	for _, c := range (&http.Request{Header: header}).Cookies() {
+		if c.Name == sessionCookieName || strings.HasPrefix(c.Name, sessionCookieName+"_") {
+			cookies = append(cookies, c)
+		}
+	}
+	return cookies
+}
+
 // tokenFromRequest decodes an OAuth token from a request.
 func tokenFromRequest(ctx context.Context, req connect.AnyRequest) (*oauth2.Token, error) {
-	cookie, err := (&http.Request{Header: req.Header()}).Cookie(sessionCookieName)
+	return tokenFromRequestCookie(ctx, req, sessionCookieName)
+}
+
+// tokenFromRequestCookie decodes an OAuth token from the named cookie
+// of a request.
+func tokenFromRequestCookie(ctx context.Context, req connect.AnyRequest, name string) (*oauth2.Token, error) {
+	cookie, err := (&http.Request{Header: req.Header()}).Cookie(name)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read cookie %s: %w", sessionCookieName, err)
+		return nil, fmt.Errorf("failed to read cookie %s: %w", name, err)
 	}
 
 	derivedKey, err := deriveEncryptionKeyForContext(ctx)
@@ -172,6 +172,18 @@ func Test_tokenFromRequest(t *testing.T) {
 	})
 }
 
+func Test_SessionCookies(t *testing.T) {
+	header := http.Header{}
+	header.Add("Cookie", "grafana_session=secret; pyroscope_git_session=github")
+	header.Add("Cookie", "pyroscope_git_session_gitlab=gitlab; pyroscope_git_sessions=other")
+
+	var got []string
+	for _, c := range SessionCookies(header) {
+		got = append(got, c.String())
+	}
+	require.Equal(t, []string{"pyroscope_git_session=github", "pyroscope_git_session_gitlab=gitlab"}, got)
+}
+
 func Test_encodeToken(t *testing.T) {
 	githubSessionSecret = []byte("16_byte_key_XXXX")
 	ctx := newTestContext()
@@ -51,6 +51,7 @@ func newSchedulerProcessor(cfg Config, handler RequestHandler, log log.Logger, r
 		maxMessageSize:  cfg.GRPCClientConfig.MaxSendMsgSize,
 		querierID:       cfg.QuerierID,
 		grpcConfig:      cfg.GRPCClientConfig,
+		interceptors:    cfg.FrontendClientInterceptors,
 		maxLoopDuration: cfg.MaxLoopDuration,
 
 		schedulerClientFactory: func(conn *grpc.ClientConn) schedulerpb.SchedulerForQuerierClient {
@@ -84,6 +85,7 @@ type schedulerProcessor struct {
 	log             log.Logger
 	handler         RequestHandler
 	grpcConfig      grpcclient.Config
+	interceptors    []grpc.UnaryClientInterceptor
 	maxMessageSize  int
 	querierID       string
 	maxLoopDuration time.Duration
@@ -294,11 +296,11 @@ func (f *frontendClientFactory) FromInstance(inst ring.InstanceDesc) (client.Poo
 
 func (sp *schedulerProcessor) frontendClientFactory() client.PoolFactory {
 	return newFrontendClientFactory(func() ([]grpc.DialOption, error) {
-		return sp.grpcConfig.DialOption([]grpc.UnaryClientInterceptor{
+		return sp.grpcConfig.DialOption(append([]grpc.UnaryClientInterceptor{
 			otgrpc.OpenTracingClientInterceptor(opentracing.GlobalTracer()),
 			middleware.ClientUserHeaderInterceptor,
 			middleware.UnaryClientInstrumentInterceptor(sp.frontendClientRequestDuration),
-		}, nil)
+		}, sp.interceptors...), nil)
 	})
 }
 
@@ -35,8 +35,9 @@ type Config struct {
 	MaxConcurrent int `yaml:"max_concurrent" category:"advanced"`
 
 	// This configuration is injected internally.
-	QuerySchedulerDiscovery schedulerdiscovery.Config `yaml:"-"`
-	MaxLoopDuration         time.Duration             `yaml:"-"`
+	QuerySchedulerDiscovery    schedulerdiscovery.Config     `yaml:"-"`
+	MaxLoopDuration            time.Duration                 `yaml:"-"`
+	FrontendClientInterceptors []grpc.UnaryClientInterceptor `yaml:"-"`
 }
 
 func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
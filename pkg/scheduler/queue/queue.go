@@ -48,9 +48,55 @@ func FirstUser() UserIndex {
 // Request stored into the queue.
 type Request interface{}
 
+// Priority is the priority class of a request.
+type Priority int
+
+const (
+	// PriorityInteractive is the priority of requests issued by users
+	// interactively, e.g. from the UI.
+	PriorityInteractive Priority = iota
+	// PriorityBatch is the priority of requests issued programmatically
+	// via the API or by batch jobs.
+	PriorityBatch
+
+	numPriorities = 2
+)
+
+// CostAwareRequest is implemented by requests that carry an estimate of their
+// cost and a priority class. Requests that do not implement the interface are
+// handled as interactive requests of cost 1.
+type CostAwareRequest interface {
+	// Cost returns the estimated cost of the request. The unit is not defined
+	// by the queue, but it must be consistent across all the requests.
+	Cost() int64
+	Priority() Priority
+}
+
+func requestCost(req Request) int64 {
+	if r, ok := req.(CostAwareRequest); ok {
+		return max(r.Cost(), 1)
+	}
+	return 1
+}
+
+func priorityOf(req Request) Priority {
+	if r, ok := req.(CostAwareRequest); ok {
+		if p := r.Priority(); p >= 0 && p < numPriorities {
+			return p
+		}
+	}
+	return PriorityInteractive
+}
+
 // RequestQueue holds incoming requests in per-user queues. It also assigns each user specified number of queriers,
 // and when querier asks for next request to handle (using GetNextRequestForQuerier), it returns requests
 // in a fair fashion.
+//
+// Fairness is based on the cost of requests: every user is charged for the requests dispatched to queriers,
+// and the user that has been charged the least is served first. Batch requests are charged more than the
+// interactive ones, and within a user queue, interactive requests always go first. In addition, the total cost
+// of requests of a user handled by queriers at once can be limited: the cost of a request is returned to the
+// user budget with ReleaseRequest, once the request is handled.
 type RequestQueue struct {
 	services.Service
 
@@ -65,9 +111,11 @@ type RequestQueue struct {
 	discardedRequests *prometheus.CounterVec // Per user.
 }
 
-func NewRequestQueue(maxOutstandingPerTenant int, forgetDelay time.Duration, queueLength *prometheus.GaugeVec, discardedRequests *prometheus.CounterVec) *RequestQueue {
+// NewRequestQueue creates a new request queue. BatchCostFactor is the multiplier applied to the cost of
+// batch requests when the user is charged for them; values less than or equal to zero mean no multiplier.
+func NewRequestQueue(maxOutstandingPerTenant int, forgetDelay time.Duration, batchCostFactor float64, queueLength *prometheus.GaugeVec, discardedRequests *prometheus.CounterVec) *RequestQueue {
 	q := &RequestQueue{
-		queues:                  newUserQueues(maxOutstandingPerTenant, forgetDelay),
+		queues:                  newUserQueues(maxOutstandingPerTenant, forgetDelay, batchCostFactor),
 		connectedQuerierWorkers: atomic.NewInt32(0),
 		queueLength:             queueLength,
 		discardedRequests:       discardedRequests,
@@ -80,11 +128,12 @@ func NewRequestQueue(maxOutstandingPerTenant int, forgetDelay time.Duration, que
 }
 
 // EnqueueRequest puts the request into the queue. MaxQueries is user-specific value that specifies how many queriers can
-// this user use (zero or negative = all queriers). It is passed to each EnqueueRequest, because it can change
-// between calls.
+// this user use (zero or negative = all queriers). MaxCostInFlight is user-specific limit of the total cost of requests
+// handled by queriers at once (zero or negative = no limit). Both are passed to each EnqueueRequest, because they can
+// change between calls.
 //
 // If request is successfully enqueued, successFn is called with the lock held, before any querier can receive the request.
-func (q *RequestQueue) EnqueueRequest(userID string, req Request, maxQueriers int, successFn func()) error {
+func (q *RequestQueue) EnqueueRequest(userID string, req Request, maxQueriers int, maxCostInFlight int64, successFn func()) error {
 	q.mtx.Lock()
 	defer q.mtx.Unlock()
 
@@ -98,24 +147,28 @@ func (q *RequestQueue) EnqueueRequest(userID string, req Request, maxQueriers in
 		return errors.New("no queue found")
 	}
 
-	select {
-	case queue <- req:
-		q.queueLength.WithLabelValues(userID).Inc()
-		q.cond.Broadcast()
-		// Call this function while holding a lock. This guarantees that no querier can fetch the request before function returns.
-		if successFn != nil {
-			successFn()
+	queue.maxCostInFlight = maxCostInFlight
+	if !queue.push(req) {
+		if queue.length == 0 {
+			q.queues.deleteQueue(userID)
 		}
-		return nil
-	default:
 		q.discardedRequests.WithLabelValues(userID).Inc()
 		return ErrTooManyRequests
 	}
+
+	q.queueLength.WithLabelValues(userID).Inc()
+	q.cond.Broadcast()
+	// Call this function while holding a lock. This guarantees that no querier can fetch the request before function returns.
+	if successFn != nil {
+		successFn()
+	}
+	return nil
 }
 
 // GetNextRequestForQuerier find next user queue and takes the next request off of it. Will block if there are no requests.
 // By passing user index from previous call of this method, querier guarantees that it iterates over all users fairly.
 // If querier finds that request from the user is already expired, it can get a request for the same user by using UserIndex.ReuseLastUser.
+// Once the request is handled (or discarded), the querier must call ReleaseRequest.
 func (q *RequestQueue) GetNextRequestForQuerier(ctx context.Context, last UserIndex, querierID string) (Request, UserIndex, error) {
 	q.mtx.Lock()
 	defer q.mtx.Unlock()
@@ -137,27 +190,17 @@ FindQueue:
 		return nil, last, err
 	}
 
-	for {
-		queue, userID, idx := q.queues.getNextQueueForQuerier(last.last, querierID)
-		last.last = idx
-		if queue == nil {
-			break
-		}
-
+	queue, userID, idx := q.queues.getNextQueueForQuerier(last.last, querierID)
+	last.last = idx
+	if queue != nil {
 		// Pick next request from the queue.
-		for {
-			request := <-queue
-			if len(queue) == 0 {
-				q.queues.deleteQueue(userID)
-			}
+		request := q.queues.dequeue(userID, queue)
+		q.queueLength.WithLabelValues(userID).Dec()
 
-			q.queueLength.WithLabelValues(userID).Dec()
-
-			// Tell close() we've processed a request.
-			q.cond.Broadcast()
+		// Tell close() we've processed a request.
+		q.cond.Broadcast()
 
-			return request, last, nil
-		}
+		return request, last, nil
 	}
 
 	// There are no unexpired requests, so we can get back
@@ -166,6 +209,16 @@ FindQueue:
 	goto FindQueue
 }
 
+// ReleaseRequest returns the cost of the request obtained with GetNextRequestForQuerier to the user budget.
+func (q *RequestQueue) ReleaseRequest(userID string, req Request) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	q.queues.release(userID, req)
+	// Queriers may be waiting for the user budget.
+	q.cond.Broadcast()
+}
+
 func (q *RequestQueue) forgetDisconnectedQueriers(_ context.Context) error {
 	q.mtx.Lock()
 	defer q.mtx.Unlock()
@@ -175,6 +228,7 @@ func (q *RequestQueue) forgetDisconnectedQueriers(_ context.Context) error {
 		// may have caused a resharding.
 		q.cond.Broadcast()
 	}
+	q.queues.pruneIdleVtime()
 
 	return nil
 }
@@ -28,7 +28,7 @@ func BenchmarkGetNextRequest(b *testing.B) {
 	queues := make([]*RequestQueue, 0, b.N)
 
 	for n := 0; n < b.N; n++ {
-		queue := NewRequestQueue(maxOutstandingPerTenant, 0,
+		queue := NewRequestQueue(maxOutstandingPerTenant, 0, 0,
 			promauto.With(nil).NewGaugeVec(prometheus.GaugeOpts{}, []string{"tenant"}),
 			promauto.With(nil).NewCounterVec(prometheus.CounterOpts{}, []string{"tenant"}),
 		)
@@ -42,7 +42,7 @@ func BenchmarkGetNextRequest(b *testing.B) {
 			for j := 0; j < numTenants; j++ {
 				tenantID := strconv.Itoa(j)
 
-				err := queue.EnqueueRequest(tenantID, "request", 0, nil)
+				err := queue.EnqueueRequest(tenantID, "request", 0, 0, nil)
 				if err != nil {
 					b.Fatal(err)
 				}
@@ -85,7 +85,7 @@ func BenchmarkQueueRequest(b *testing.B) {
 	requests := make([]string, 0, numTenants)
 
 	for n := 0; n < b.N; n++ {
-		q := NewRequestQueue(maxOutstandingPerTenant, 0,
+		q := NewRequestQueue(maxOutstandingPerTenant, 0, 0,
 			promauto.With(nil).NewGaugeVec(prometheus.GaugeOpts{}, []string{"tenant"}),
 			promauto.With(nil).NewCounterVec(prometheus.CounterOpts{}, []string{"user"}),
 		)
@@ -106,7 +106,7 @@ func BenchmarkQueueRequest(b *testing.B) {
 	for n := 0; n < b.N; n++ {
 		for i := 0; i < maxOutstandingPerTenant; i++ {
 			for j := 0; j < numTenants; j++ {
-				err := queues[n].EnqueueRequest(users[j], requests[j], 0, nil)
+				err := queues[n].EnqueueRequest(users[j], requests[j], 0, 0, nil)
 				if err != nil {
 					b.Fatal(err)
 				}
@@ -118,7 +118,7 @@ func BenchmarkQueueRequest(b *testing.B) {
 func TestRequestQueue_GetNextRequestForQuerier_ShouldGetRequestAfterReshardingBecauseQuerierHasBeenForgotten(t *testing.T) {
 	const forgetDelay = 3 * time.Second
 
-	queue := NewRequestQueue(1, forgetDelay,
+	queue := NewRequestQueue(1, forgetDelay, 0,
 		promauto.With(nil).NewGaugeVec(prometheus.GaugeOpts{}, []string{"user"}),
 		promauto.With(nil).NewCounterVec(prometheus.CounterOpts{}, []string{"user"}))
 
@@ -147,7 +147,7 @@ func TestRequestQueue_GetNextRequestForQuerier_ShouldGetRequestAfterReshardingBe
 
 	// Enqueue a request from an user which would be assigned to querier-1.
 	// NOTE: "user-1" hash falls in the querier-1 shard.
-	require.NoError(t, queue.EnqueueRequest("user-1", "request", 1, nil))
+	require.NoError(t, queue.EnqueueRequest("user-1", "request", 1, 0, nil))
 
 	startTime := time.Now()
 	querier2wg.Wait()
@@ -157,6 +157,145 @@ func TestRequestQueue_GetNextRequestForQuerier_ShouldGetRequestAfterReshardingBe
 	assert.GreaterOrEqual(t, waitTime.Milliseconds(), forgetDelay.Milliseconds())
 }
 
+type costAwareRequest struct {
+	id       string
+	cost     int64
+	priority Priority
+}
+
+func (r costAwareRequest) Cost() int64        { return r.cost }
+func (r costAwareRequest) Priority() Priority { return r.priority }
+
+func newTestRequestQueue(t *testing.T, batchCostFactor float64) *RequestQueue {
+	t.Helper()
+	queue := NewRequestQueue(100, 0, batchCostFactor,
+		promauto.With(nil).NewGaugeVec(prometheus.GaugeOpts{}, []string{"user"}),
+		promauto.With(nil).NewCounterVec(prometheus.CounterOpts{}, []string{"user"}))
+	ctx := context.Background()
+	require.NoError(t, services.StartAndAwaitRunning(ctx, queue))
+	t.Cleanup(func() {
+		require.NoError(t, services.StopAndAwaitTerminated(ctx, queue))
+	})
+	queue.RegisterQuerierConnection("querier-1")
+	t.Cleanup(func() { queue.UnregisterQuerierConnection("querier-1") })
+	return queue
+}
+
+func dequeueIDs(t *testing.T, queue *RequestQueue, n int) []string {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	ids := make([]string, 0, n)
+	last := FirstUser()
+	for i := 0; i < n; i++ {
+		req, idx, err := queue.GetNextRequestForQuerier(ctx, last, "querier-1")
+		require.NoError(t, err)
+		last = idx
+		r := req.(costAwareRequest)
+		ids = append(ids, r.id)
+		queue.ReleaseRequest(r.id[:1], r)
+	}
+	return ids
+}
+
+func TestRequestQueue_CostFairness(t *testing.T) {
+	queue := newTestRequestQueue(t, 0)
+
+	// Tenant "a" enqueues heavy requests, tenant "b" enqueues light ones:
+	// "b" is served several times for every request of "a".
+	for i := 0; i < 3; i++ {
+		require.NoError(t, queue.EnqueueRequest("a", costAwareRequest{id: fmt.Sprintf("a%d", i), cost: 10}, 0, 0, nil))
+	}
+	for i := 0; i < 8; i++ {
+		require.NoError(t, queue.EnqueueRequest("b", costAwareRequest{id: fmt.Sprintf("b%d", i), cost: 3}, 0, 0, nil))
+	}
+
+	assert.Equal(t,
+		[]string{"a0", "b0", "b1", "b2", "b3", "a1", "b4", "b5", "b6", "a2", "b7"},
+		dequeueIDs(t, queue, 11))
+}
+
+func TestRequestQueue_IdleThenBurst(t *testing.T) {
+	queue := newTestRequestQueue(t, 0)
+
+	// Tenant "a" is charged for a heavy request, which empties its queue.
+	require.NoError(t, queue.EnqueueRequest("a", costAwareRequest{id: "a0", cost: 30}, 0, 0, nil))
+	assert.Equal(t, []string{"a0"}, dequeueIDs(t, queue, 1))
+
+	// The charge is kept while the tenant is idle: when it comes back with
+	// a burst, tenant "b" is served until it catches up.
+	for i := 0; i < 6; i++ {
+		require.NoError(t, queue.EnqueueRequest("b", costAwareRequest{id: fmt.Sprintf("b%d", i), cost: 5}, 0, 0, nil))
+	}
+	for i := 1; i < 3; i++ {
+		require.NoError(t, queue.EnqueueRequest("a", costAwareRequest{id: fmt.Sprintf("a%d", i), cost: 5}, 0, 0, nil))
+	}
+	assert.Equal(t,
+		[]string{"b0", "b1", "b2", "b3", "b4", "b5", "a1", "a2"},
+		dequeueIDs(t, queue, 8))
+
+	// Once other tenants have caught up, the charge is no longer tracked.
+	for i := 6; i < 9; i++ {
+		require.NoError(t, queue.EnqueueRequest("b", costAwareRequest{id: fmt.Sprintf("b%d", i), cost: 5}, 0, 0, nil))
+	}
+	assert.Equal(t, []string{"b6", "b7", "b8"}, dequeueIDs(t, queue, 3))
+	require.NoError(t, queue.forgetDisconnectedQueriers(context.Background()))
+	queue.mtx.Lock()
+	assert.NotContains(t, queue.queues.idleVtime, "a")
+	queue.mtx.Unlock()
+}
+
+func TestRequestQueue_Priority(t *testing.T) {
+	queue := newTestRequestQueue(t, 4)
+
+	require.NoError(t, queue.EnqueueRequest("a", costAwareRequest{id: "a0", cost: 1, priority: PriorityBatch}, 0, 0, nil))
+	require.NoError(t, queue.EnqueueRequest("a", costAwareRequest{id: "a1", cost: 1, priority: PriorityBatch}, 0, 0, nil))
+	require.NoError(t, queue.EnqueueRequest("a", costAwareRequest{id: "a2", cost: 1}, 0, 0, nil))
+	for i := 0; i < 4; i++ {
+		require.NoError(t, queue.EnqueueRequest("b", costAwareRequest{id: fmt.Sprintf("b%d", i), cost: 1}, 0, 0, nil))
+	}
+
+	// Interactive requests of a tenant go first; batch requests are charged
+	// more, therefore the other tenant is served more often.
+	assert.Equal(t,
+		[]string{"a2", "b0", "a0", "b1", "b2", "b3", "a1"},
+		dequeueIDs(t, queue, 7))
+}
+
+func TestRequestQueue_CostInFlightBudget(t *testing.T) {
+	queue := newTestRequestQueue(t, 0)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// The first request exceeds the budget on its own, but
+	// it is dispatched because nothing else is in flight.
+	require.NoError(t, queue.EnqueueRequest("a", costAwareRequest{id: "a0", cost: 20}, 0, 10, nil))
+	require.NoError(t, queue.EnqueueRequest("a", costAwareRequest{id: "a1", cost: 5}, 0, 10, nil))
+	require.NoError(t, queue.EnqueueRequest("b", costAwareRequest{id: "b0", cost: 5}, 0, 10, nil))
+
+	a0, last, err := queue.GetNextRequestForQuerier(ctx, FirstUser(), "querier-1")
+	require.NoError(t, err)
+	assert.Equal(t, "a0", a0.(costAwareRequest).id)
+
+	// Tenant "a" is over budget: only "b" can be served.
+	b0, last, err := queue.GetNextRequestForQuerier(ctx, last, "querier-1")
+	require.NoError(t, err)
+	assert.Equal(t, "b0", b0.(costAwareRequest).id)
+
+	waitCtx, waitCancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer waitCancel()
+	_, _, err = queue.GetNextRequestForQuerier(waitCtx, last, "querier-1")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// Once the request is handled, the next one is dispatched.
+	queue.ReleaseRequest("a", a0)
+	a1, _, err := queue.GetNextRequestForQuerier(ctx, last, "querier-1")
+	require.NoError(t, err)
+	assert.Equal(t, "a1", a1.(costAwareRequest).id)
+	queue.ReleaseRequest("a", a1)
+	queue.ReleaseRequest("b", b0)
+}
+
 func TestContextCond(t *testing.T) {
 	t.Run("wait until broadcast", func(t *testing.T) {
 		t.Parallel()
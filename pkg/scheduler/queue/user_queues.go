@@ -47,16 +47,43 @@ type queues struct {
 
 	// Sorted list of querier names, used when creating per-user shard.
 	sortedQueriers []string
+
+	// Total cost of the requests handled by queriers, per user.
+	costInFlight map[string]int64
+
+	// Cost multiplier applied to batch requests when charging users.
+	batchCostFactor float64
+
+	// Virtual time of the most recently served user. Users that
+	// (re)appear in the queue start from it, therefore they can't
+	// claim the time they have been idle.
+	vclock float64
+
+	// Virtual time of the users whose queues have been removed, if it is
+	// ahead of vclock. A user that reappears resumes from it, therefore
+	// the cost charged is not dropped when the user queue empties.
+	idleVtime map[string]float64
 }
 
 type userQueue struct {
-	ch chan Request
+	// Pending requests, per priority class.
+	requests  [numPriorities][]Request
+	length    int
+	maxLength int
 
 	// If not nil, only these queriers can handle user requests. If nil, all queriers can.
 	// We set this to nil if number of available queriers <= maxQueriers.
 	queriers    map[string]struct{}
 	maxQueriers int
 
+	// Maximum total cost of the user requests being handled by queriers at once.
+	// Zero or negative value means no limit.
+	maxCostInFlight int64
+
+	// Virtual time of the user: the total weighted cost of the user requests
+	// dispatched to queriers. The user with the smallest virtual time is served first.
+	vtime float64
+
 	// Seed for shuffle sharding of queriers. This seed is based on userID only and is therefore consistent
 	// between different frontends.
 	seed int64
@@ -65,7 +92,41 @@ type userQueue struct {
 	index int
 }
 
-func newUserQueues(maxUserQueueSize int, forgetDelay time.Duration) *queues {
+func (uq *userQueue) push(req Request) bool {
+	if uq.length >= uq.maxLength {
+		return false
+	}
+	p := priorityOf(req)
+	uq.requests[p] = append(uq.requests[p], req)
+	uq.length++
+	return true
+}
+
+// peek returns the next request of the user: interactive requests
+// are always served before the batch ones.
+func (uq *userQueue) peek() (Request, Priority) {
+	for p := range uq.requests {
+		if len(uq.requests[p]) > 0 {
+			return uq.requests[p][0], Priority(p)
+		}
+	}
+	return nil, 0
+}
+
+func (uq *userQueue) pop() (Request, Priority) {
+	req, p := uq.peek()
+	if req != nil {
+		uq.requests[p][0] = nil
+		uq.requests[p] = uq.requests[p][1:]
+		uq.length--
+	}
+	return req, p
+}
+
+func newUserQueues(maxUserQueueSize int, forgetDelay time.Duration, batchCostFactor float64) *queues {
+	if batchCostFactor <= 0 {
+		batchCostFactor = 1
+	}
 	return &queues{
 		userQueues:       map[string]*userQueue{},
 		users:            nil,
@@ -73,6 +134,9 @@ func newUserQueues(maxUserQueueSize int, forgetDelay time.Duration) *queues {
 		forgetDelay:      forgetDelay,
 		queriers:         map[string]*querier{},
 		sortedQueriers:   nil,
+		costInFlight:     map[string]int64{},
+		batchCostFactor:  batchCostFactor,
+		idleVtime:        map[string]float64{},
 	}
 }
 
@@ -88,6 +152,9 @@ func (q *queues) deleteQueue(userID string) {
 
 	delete(q.userQueues, userID)
 	q.users[uq.index] = ""
+	if uq.vtime > q.vclock {
+		q.idleVtime[userID] = uq.vtime
+	}
 
 	// Shrink users list size if possible. This is safe, and no users will be skipped during iteration.
 	for ix := len(q.users) - 1; ix >= 0 && q.users[ix] == ""; ix-- {
@@ -99,7 +166,7 @@ func (q *queues) deleteQueue(userID string) {
 // MaxQueriers is used to compute which queriers should handle requests for this user.
 // If maxQueriers is <= 0, all queriers can handle this user's requests.
 // If maxQueriers has changed since the last call, queriers for this are recomputed.
-func (q *queues) getOrAddQueue(userID string, maxQueriers int) chan Request {
+func (q *queues) getOrAddQueue(userID string, maxQueriers int) *userQueue {
 	// Empty user is not allowed, as that would break our users list ("" is used for free spot).
 	if userID == "" {
 		return nil
@@ -113,11 +180,13 @@ func (q *queues) getOrAddQueue(userID string, maxQueriers int) chan Request {
 
 	if uq == nil {
 		uq = &userQueue{
-			ch:    make(chan Request, q.maxUserQueueSize),
-			seed:  util.ShuffleShardSeed(userID, ""),
-			index: -1,
+			maxLength: q.maxUserQueueSize,
+			vtime:     max(q.vclock, q.idleVtime[userID]),
+			seed:      util.ShuffleShardSeed(userID, ""),
+			index:     -1,
 		}
 		q.userQueues[userID] = uq
+		delete(q.idleVtime, userID)
 
 		// Add user to the list of users... find first free spot, and put it there.
 		for ix, u := range q.users {
@@ -140,13 +209,15 @@ func (q *queues) getOrAddQueue(userID string, maxQueriers int) chan Request {
 		uq.queriers = shuffleQueriersForUser(uq.seed, maxQueriers, q.sortedQueriers, nil)
 	}
 
-	return uq.ch
+	return uq
 }
 
-// Finds next queue for the querier. To support fair scheduling between users, client is expected
-// to pass last user index returned by this function as argument. Is there was no previous
-// last user index, use -1.
-func (q *queues) getNextQueueForQuerier(lastUserIndex int, querierID string) (chan Request, string, int) {
+// Finds next queue for the querier. The queue of the user with the smallest virtual time
+// is chosen, skipping users that have exhausted their in-flight cost budget. To support
+// fair scheduling between users with equal virtual time, client is expected to pass last
+// user index returned by this function as argument. Is there was no previous last user
+// index, use -1.
+func (q *queues) getNextQueueForQuerier(lastUserIndex int, querierID string) (*userQueue, string, int) {
 	uid := lastUserIndex
 
 	// Ensure the querier is not shutting down. If the querier is shutting down, we shouldn't forward
@@ -155,6 +226,8 @@ func (q *queues) getNextQueueForQuerier(lastUserIndex int, querierID string) (ch
 		return nil, "", uid
 	}
 
+	next := -1
+	var nextQueue *userQueue
 	for iters := 0; iters < len(q.users); iters++ {
 		uid = uid + 1
 
@@ -169,18 +242,82 @@ func (q *queues) getNextQueueForQuerier(lastUserIndex int, querierID string) (ch
 			continue
 		}
 
-		q := q.userQueues[u]
+		uq := q.userQueues[u]
 
-		if q.queriers != nil {
-			if _, ok := q.queriers[querierID]; !ok {
+		if uq.queriers != nil {
+			if _, ok := uq.queriers[querierID]; !ok {
 				// This querier is not handling the user.
 				continue
 			}
 		}
 
-		return q.ch, u, uid
+		if !q.withinCostBudget(u, uq) {
+			continue
+		}
+
+		// Ties are resolved in favour of the user that comes first after the last one.
+		if nextQueue == nil || uq.vtime < nextQueue.vtime {
+			next, nextQueue = uid, uq
+		}
+	}
+	if nextQueue == nil {
+		return nil, "", uid
+	}
+	return nextQueue, q.users[next], next
+}
+
+// withinCostBudget reports whether the next request of the user can be
+// dispatched without exceeding the user's in-flight cost budget. A user
+// with no requests in flight is always allowed to proceed, so that
+// requests that cost more than the budget are not stuck forever.
+func (q *queues) withinCostBudget(userID string, uq *userQueue) bool {
+	if uq.maxCostInFlight <= 0 {
+		return true
+	}
+	inflight := q.costInFlight[userID]
+	if inflight <= 0 {
+		return true
+	}
+	req, _ := uq.peek()
+	return inflight+requestCost(req) <= uq.maxCostInFlight
+}
+
+// dequeue takes the next request off the user queue and charges the user
+// for it. The queue is removed once it is empty.
+func (q *queues) dequeue(userID string, uq *userQueue) Request {
+	req, p := uq.pop()
+	cost := requestCost(req)
+	q.costInFlight[userID] += cost
+	q.vclock = max(q.vclock, uq.vtime)
+	w := float64(cost)
+	if p == PriorityBatch {
+		w *= q.batchCostFactor
+	}
+	uq.vtime += w
+	if uq.length == 0 {
+		q.deleteQueue(userID)
+	}
+	return req
+}
+
+// pruneIdleVtime removes the virtual time of idle users that is no longer
+// ahead of vclock: such users start from vclock anyway.
+func (q *queues) pruneIdleVtime() {
+	for userID, vtime := range q.idleVtime {
+		if vtime <= q.vclock {
+			delete(q.idleVtime, userID)
+		}
+	}
+}
+
+// release returns the cost of the request handled by a querier to the user budget.
+func (q *queues) release(userID string, req Request) {
+	inflight := q.costInFlight[userID] - requestCost(req)
+	if inflight > 0 {
+		q.costInFlight[userID] = inflight
+		return
 	}
-	return nil, "", uid
+	delete(q.costInFlight, userID)
 }
 
 func (q *queues) addQuerierConnection(querierID string) {
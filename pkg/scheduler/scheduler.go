@@ -69,6 +69,7 @@ type Scheduler struct {
 	queueLength              *prometheus.GaugeVec
 	discardedRequests        *prometheus.CounterVec
 	cancelledRequests        *prometheus.CounterVec
+	costInFlight             *prometheus.GaugeVec
 	connectedQuerierClients  prometheus.GaugeFunc
 	connectedFrontendClients prometheus.GaugeFunc
 	queueDuration            prometheus.Histogram
@@ -95,18 +96,28 @@ type connectedFrontend struct {
 type Config struct {
 	MaxOutstandingPerTenant int                       `yaml:"max_outstanding_requests_per_tenant"`
 	QuerierForgetDelay      time.Duration             `yaml:"querier_forget_delay" category:"experimental"`
+	DefaultQueryCost        uint64                    `yaml:"default_query_cost" category:"experimental"`
+	BatchQueryCostFactor    float64                   `yaml:"batch_query_cost_factor" category:"experimental"`
 	GRPCClientConfig        grpcclient.Config         `yaml:"grpc_client_config" doc:"description=This configures the gRPC client used to report errors back to the query-frontend."`
 	ServiceDiscovery        schedulerdiscovery.Config `yaml:",inline"`
+
+	// This configuration is injected internally.
+	FrontendClientInterceptors []grpc.UnaryClientInterceptor `yaml:"-"`
 }
 
 func (cfg *Config) RegisterFlags(f *flag.FlagSet, logger log.Logger) {
 	f.IntVar(&cfg.MaxOutstandingPerTenant, "query-scheduler.max-outstanding-requests-per-tenant", 100, "Maximum number of outstanding requests per tenant per query-scheduler. In-flight requests above this limit will fail with HTTP response status code 429.")
 	f.DurationVar(&cfg.QuerierForgetDelay, "query-scheduler.querier-forget-delay", 0, "If a querier disconnects without sending notification about graceful shutdown, the query-scheduler will keep the querier in the tenant's shard until the forget delay has passed. This feature is useful to reduce the blast radius when shuffle-sharding is enabled.")
+	f.Uint64Var(&cfg.DefaultQueryCost, "query-scheduler.default-query-cost", 1<<20, "Cost of queries, in bytes, assumed when the query-frontend provides no estimate. The cost is used to share queriers fairly between tenants.")
+	f.Float64Var(&cfg.BatchQueryCostFactor, "query-scheduler.batch-query-cost-factor", 4, "Multiplier applied to the cost of batch queries when tenants are charged for them. Values greater than 1 give interactive queries precedence over the batch ones.")
 	cfg.GRPCClientConfig.RegisterFlagsWithPrefix("query-scheduler.grpc-client-config", f)
 	cfg.ServiceDiscovery.RegisterFlags(f, logger)
 }
 
 func (cfg *Config) Validate() error {
+	if cfg.BatchQueryCostFactor < 1 {
+		return errors.New("the batch query cost factor must be greater than or equal to 1")
+	}
 	return cfg.ServiceDiscovery.Validate()
 }
 
@@ -137,7 +148,11 @@ func NewScheduler(cfg Config, limits Limits, log log.Logger, registerer promethe
 		Name: "pyroscope_query_scheduler_discarded_requests_total",
 		Help: "Total number of query requests discarded.",
 	}, []string{"tenant"})
-	s.requestQueue = queue.NewRequestQueue(cfg.MaxOutstandingPerTenant, cfg.QuerierForgetDelay, s.queueLength, s.discardedRequests)
+	s.costInFlight = promauto.With(registerer).NewGaugeVec(prometheus.GaugeOpts{
+		Name: "pyroscope_query_scheduler_cost_in_flight_bytes",
+		Help: "Estimated cost of the queries being handled by queriers.",
+	}, []string{"tenant"})
+	s.requestQueue = queue.NewRequestQueue(cfg.MaxOutstandingPerTenant, cfg.QuerierForgetDelay, cfg.BatchQueryCostFactor, s.queueLength, s.discardedRequests)
 
 	s.queueDuration = promauto.With(registerer).NewHistogram(prometheus.HistogramOpts{
 		Name:    "pyroscope_query_scheduler_queue_duration_seconds",
@@ -187,6 +202,8 @@ func NewScheduler(cfg Config, limits Limits, log log.Logger, registerer promethe
 type Limits interface {
 	// MaxQueriersPerTenant returns max queriers to use per tenant, or 0 if shuffle sharding is disabled.
 	MaxQueriersPerTenant(tenant string) int
+	// MaxQueryCostInFlight returns max total cost of tenant queries handled by queriers at once, or 0 if unlimited.
+	MaxQueryCostInFlight(tenant string) int
 }
 
 type schedulerRequest struct {
@@ -195,6 +212,8 @@ type schedulerRequest struct {
 	queryID         uint64
 	request         *httpgrpc.HTTPRequest
 	statsEnabled    bool
+	priority        queue.Priority
+	cost            int64
 
 	enqueueTime time.Time
 
@@ -206,6 +225,9 @@ type schedulerRequest struct {
 	parentSpanContext opentracing.SpanContext
 }
 
+func (r *schedulerRequest) Cost() int64              { return r.cost }
+func (r *schedulerRequest) Priority() queue.Priority { return r.priority }
+
 // FrontendLoop handles connection from frontend.
 func (s *Scheduler) FrontendLoop(ctx context.Context, frontend *connect.BidiStream[schedulerpb.FrontendToScheduler, schedulerpb.SchedulerToFrontend]) error {
 	frontendAddress, frontendCtx, err := s.frontendConnected(frontend)
@@ -334,6 +356,14 @@ func (s *Scheduler) enqueueRequest(frontendContext context.Context, frontendAddr
 		queryID:         msg.QueryID,
 		request:         msg.HttpRequest,
 		statsEnabled:    msg.StatsEnabled,
+		priority:        queue.PriorityInteractive,
+		cost:            int64(msg.Cost),
+	}
+	if msg.Priority == schedulerpb.QueryPriority_BATCH {
+		req.priority = queue.PriorityBatch
+	}
+	if req.cost <= 0 {
+		req.cost = int64(s.cfg.DefaultQueryCost)
 	}
 
 	now := time.Now()
@@ -349,9 +379,10 @@ func (s *Scheduler) enqueueRequest(frontendContext context.Context, frontendAddr
 		return err
 	}
 	maxQueriers := validation.SmallestPositiveNonZeroIntPerTenant(tenantIDs, s.limits.MaxQueriersPerTenant)
+	maxCostInFlight := validation.SmallestPositiveNonZeroIntPerTenant(tenantIDs, s.limits.MaxQueryCostInFlight)
 
 	s.activeUsers.UpdateUserTimestamp(userID, now)
-	return s.requestQueue.EnqueueRequest(userID, req, maxQueriers, func() {
+	return s.requestQueue.EnqueueRequest(userID, req, maxQueriers, int64(maxCostInFlight), func() {
 		shouldCancel = false
 
 		s.pendingRequestsMu.Lock()
@@ -443,6 +474,7 @@ func (s *Scheduler) QuerierLoop(ctx context.Context, bidi *connect.BidiStream[sc
 		lastUserIndex = idx
 
 		r := req.(*schedulerRequest)
+		s.costInFlight.WithLabelValues(r.userID).Add(float64(r.cost))
 
 		s.queueDuration.Observe(time.Since(r.enqueueTime).Seconds())
 		r.queueSpan.Finish()
@@ -462,12 +494,15 @@ func (s *Scheduler) QuerierLoop(ctx context.Context, bidi *connect.BidiStream[sc
 		if r.ctx.Err() != nil {
 			// Remove from pending requests.
 			s.cancelRequestAndRemoveFromPending(r.frontendAddress, r.queryID)
+			s.releaseRequest(r)
 
 			lastUserIndex = lastUserIndex.ReuseLastUser()
 			continue
 		}
 
-		if err := s.forwardRequestToQuerier(querier, r); err != nil {
+		err = s.forwardRequestToQuerier(querier, r)
+		s.releaseRequest(r)
+		if err != nil {
 			return err
 		}
 	}
@@ -475,6 +510,11 @@ func (s *Scheduler) QuerierLoop(ctx context.Context, bidi *connect.BidiStream[sc
 	return schedulerpb.ErrSchedulerIsNotRunning
 }
 
+func (s *Scheduler) releaseRequest(r *schedulerRequest) {
+	s.requestQueue.ReleaseRequest(r.userID, r)
+	s.costInFlight.WithLabelValues(r.userID).Sub(float64(r.cost))
+}
+
 func (s *Scheduler) NotifyQuerierShutdown(ctx context.Context, req *connect.Request[schedulerpb.NotifyQuerierShutdownRequest]) (*connect.Response[schedulerpb.NotifyQuerierShutdownResponse], error) {
 	level.Info(s.log).Log("msg", "received shutdown notification from querier", "querier", req.Msg.GetQuerierID())
 	s.requestQueue.NotifyQuerierShutdown(req.Msg.GetQuerierID())
@@ -526,10 +566,10 @@ func (s *Scheduler) forwardRequestToQuerier(querier *BidiStreamCloser[schedulerp
 }
 
 func (s *Scheduler) forwardErrorToFrontend(ctx context.Context, req *schedulerRequest, requestErr error) {
-	opts, err := s.cfg.GRPCClientConfig.DialOption([]grpc.UnaryClientInterceptor{
+	opts, err := s.cfg.GRPCClientConfig.DialOption(append([]grpc.UnaryClientInterceptor{
 		otgrpc.OpenTracingClientInterceptor(opentracing.GlobalTracer()),
 		middleware.ClientUserHeaderInterceptor,
-	},
+	}, s.cfg.FrontendClientInterceptors...),
 		nil)
 	if err != nil {
 		level.Warn(s.log).Log("msg", "failed to create gRPC options for the connection to frontend to report error", "frontend", req.frontendAddress, "err", err, "requestErr", requestErr)
@@ -617,6 +657,7 @@ func (s *Scheduler) cleanupMetricsForInactiveUser(user string) {
 	s.queueLength.DeleteLabelValues(user)
 	s.discardedRequests.DeleteLabelValues(user)
 	s.cancelledRequests.DeleteLabelValues(user)
+	s.costInFlight.DeleteLabelValues(user)
 }
 
 func (s *Scheduler) getConnectedFrontendClientsMetric() float64 {
@@ -520,6 +520,10 @@ func (l limits) MaxQueriersPerTenant(_ string) int {
 	return l.queriers
 }
 
+func (l limits) MaxQueryCostInFlight(_ string) int {
+	return 0
+}
+
 type frontendMock struct {
 	mu   sync.Mutex
 	resp map[uint64]*httpgrpc.HTTPResponse
@@ -75,6 +75,54 @@ func (FrontendToSchedulerType) EnumDescriptor() ([]byte, []int) {
 	return file_scheduler_schedulerpb_scheduler_proto_rawDescGZIP(), []int{0}
 }
 
+type QueryPriority int32
+
+const (
+	// Requests issued by users interactively, e.g. from the UI.
+	QueryPriority_INTERACTIVE QueryPriority = 0
+	// Requests issued programmatically via the API or by batch jobs.
+	QueryPriority_BATCH QueryPriority = 1
+)
+
+// Enum value maps for QueryPriority.
+var (
+	QueryPriority_name = map[int32]string{
+		0: "INTERACTIVE",
+		1: "BATCH",
+	}
+	QueryPriority_value = map[string]int32{
+		"INTERACTIVE": 0,
+		"BATCH":       1,
+	}
+)
+
+func (x QueryPriority) Enum() *QueryPriority {
+	p := new(QueryPriority)
+	*p = x
+	return p
+}
+
+func (x QueryPriority) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (QueryPriority) Descriptor() protoreflect.EnumDescriptor {
+	return file_scheduler_schedulerpb_scheduler_proto_enumTypes[1].Descriptor()
+}
+
+func (QueryPriority) Type() protoreflect.EnumType {
+	return &file_scheduler_schedulerpb_scheduler_proto_enumTypes[1]
+}
+
+func (x QueryPriority) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use QueryPriority.Descriptor instead.
+func (QueryPriority) EnumDescriptor() ([]byte, []int) {
+	return file_scheduler_schedulerpb_scheduler_proto_rawDescGZIP(), []int{1}
+}
+
 type SchedulerToFrontendStatus int32
 
 const (
@@ -111,11 +159,11 @@ func (x SchedulerToFrontendStatus) String() string {
 }
 
 func (SchedulerToFrontendStatus) Descriptor() protoreflect.EnumDescriptor {
-	return file_scheduler_schedulerpb_scheduler_proto_enumTypes[1].Descriptor()
+	return file_scheduler_schedulerpb_scheduler_proto_enumTypes[2].Descriptor()
 }
 
 func (SchedulerToFrontendStatus) Type() protoreflect.EnumType {
-	return &file_scheduler_schedulerpb_scheduler_proto_enumTypes[1]
+	return &file_scheduler_schedulerpb_scheduler_proto_enumTypes[2]
 }
 
 func (x SchedulerToFrontendStatus) Number() protoreflect.EnumNumber {
@@ -124,7 +172,7 @@ func (x SchedulerToFrontendStatus) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use SchedulerToFrontendStatus.Descriptor instead.
 func (SchedulerToFrontendStatus) EnumDescriptor() ([]byte, []int) {
-	return file_scheduler_schedulerpb_scheduler_proto_rawDescGZIP(), []int{1}
+	return file_scheduler_schedulerpb_scheduler_proto_rawDescGZIP(), []int{2}
 }
 
 // Querier reports its own clientID when it connects, so that scheduler knows how many *different* queriers are connected.
@@ -276,6 +324,11 @@ type FrontendToScheduler struct {
 	UserID       string                `protobuf:"bytes,4,opt,name=userID,proto3" json:"userID,omitempty"`
 	HttpRequest  *httpgrpc.HTTPRequest `protobuf:"bytes,5,opt,name=httpRequest,proto3" json:"httpRequest,omitempty"`
 	StatsEnabled bool                  `protobuf:"varint,6,opt,name=statsEnabled,proto3" json:"statsEnabled,omitempty"`
+	// Priority class of the request.
+	Priority QueryPriority `protobuf:"varint,7,opt,name=priority,proto3,enum=schedulerpb.QueryPriority" json:"priority,omitempty"`
+	// Estimated cost of the request, in bytes to be scanned.
+	// Zero means that the cost is unknown.
+	Cost uint64 `protobuf:"varint,8,opt,name=cost,proto3" json:"cost,omitempty"`
 }
 
 func (x *FrontendToScheduler) Reset() {
@@ -352,6 +405,20 @@ func (x *FrontendToScheduler) GetStatsEnabled() bool {
 	return false
 }
 
+func (x *FrontendToScheduler) GetPriority() QueryPriority {
+	if x != nil {
+		return x.Priority
+	}
+	return QueryPriority_INTERACTIVE
+}
+
+func (x *FrontendToScheduler) GetCost() uint64 {
+	if x != nil {
+		return x.Cost
+	}
+	return 0
+}
+
 type SchedulerToFrontend struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -516,7 +583,7 @@ var file_scheduler_schedulerpb_scheduler_proto_rawDesc = []byte{
 	0x65, 0x72, 0x49, 0x44, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72,
 	0x49, 0x44, 0x12, 0x22, 0x0a, 0x0c, 0x73, 0x74, 0x61, 0x74, 0x73, 0x45, 0x6e, 0x61, 0x62, 0x6c,
 	0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x73, 0x74, 0x61, 0x74, 0x73, 0x45,
-	0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x22, 0x88, 0x02, 0x0a, 0x13, 0x46, 0x72, 0x6f, 0x6e, 0x74,
+	0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x22, 0xd4, 0x02, 0x0a, 0x13, 0x46, 0x72, 0x6f, 0x6e, 0x74,
 	0x65, 0x6e, 0x64, 0x54, 0x6f, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x12, 0x38,
 	0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x24, 0x2e, 0x73,
 	0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x70, 0x62, 0x2e, 0x46, 0x72, 0x6f, 0x6e, 0x74,
@@ -533,62 +600,69 @@ var file_scheduler_schedulerpb_scheduler_proto_rawDesc = []byte{
 	0x52, 0x0b, 0x68, 0x74, 0x74, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x22, 0x0a,
 	0x0c, 0x73, 0x74, 0x61, 0x74, 0x73, 0x45, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x18, 0x06, 0x20,
 	0x01, 0x28, 0x08, 0x52, 0x0c, 0x73, 0x74, 0x61, 0x74, 0x73, 0x45, 0x6e, 0x61, 0x62, 0x6c, 0x65,
-	0x64, 0x22, 0x6b, 0x0a, 0x13, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x54, 0x6f,
-	0x46, 0x72, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x64, 0x12, 0x3e, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74,
-	0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x26, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x64,
-	0x75, 0x6c, 0x65, 0x72, 0x70, 0x62, 0x2e, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72,
-	0x54, 0x6f, 0x46, 0x72, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
-	0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f,
-	0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x3c,
-	0x0a, 0x1c, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x51, 0x75, 0x65, 0x72, 0x69, 0x65, 0x72, 0x53,
-	0x68, 0x75, 0x74, 0x64, 0x6f, 0x77, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1c,
-	0x0a, 0x09, 0x71, 0x75, 0x65, 0x72, 0x69, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x09, 0x71, 0x75, 0x65, 0x72, 0x69, 0x65, 0x72, 0x49, 0x44, 0x22, 0x1f, 0x0a, 0x1d,
+	0x64, 0x12, 0x36, 0x0a, 0x08, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x70,
+	0x62, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x50, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x52,
+	0x08, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x73,
+	0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x63, 0x6f, 0x73, 0x74, 0x22, 0x6b, 0x0a,
+	0x13, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x54, 0x6f, 0x46, 0x72, 0x6f, 0x6e,
+	0x74, 0x65, 0x6e, 0x64, 0x12, 0x3e, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x26, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72,
+	0x70, 0x62, 0x2e, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x54, 0x6f, 0x46, 0x72,
+	0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x3c, 0x0a, 0x1c, 0x4e, 0x6f,
+	0x74, 0x69, 0x66, 0x79, 0x51, 0x75, 0x65, 0x72, 0x69, 0x65, 0x72, 0x53, 0x68, 0x75, 0x74, 0x64,
+	0x6f, 0x77, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x71, 0x75,
+	0x65, 0x72, 0x69, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x71,
+	0x75, 0x65, 0x72, 0x69, 0x65, 0x72, 0x49, 0x44, 0x22, 0x1f, 0x0a, 0x1d, 0x4e, 0x6f, 0x74, 0x69,
+	0x66, 0x79, 0x51, 0x75, 0x65, 0x72, 0x69, 0x65, 0x72, 0x53, 0x68, 0x75, 0x74, 0x64, 0x6f, 0x77,
+	0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2a, 0x3c, 0x0a, 0x17, 0x46, 0x72, 0x6f,
+	0x6e, 0x74, 0x65, 0x6e, 0x64, 0x54, 0x6f, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72,
+	0x54, 0x79, 0x70, 0x65, 0x12, 0x08, 0x0a, 0x04, 0x49, 0x4e, 0x49, 0x54, 0x10, 0x00, 0x12, 0x0b,
+	0x0a, 0x07, 0x45, 0x4e, 0x51, 0x55, 0x45, 0x55, 0x45, 0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06, 0x43,
+	0x41, 0x4e, 0x43, 0x45, 0x4c, 0x10, 0x02, 0x2a, 0x2b, 0x0a, 0x0d, 0x51, 0x75, 0x65, 0x72, 0x79,
+	0x50, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x12, 0x0f, 0x0a, 0x0b, 0x49, 0x4e, 0x54, 0x45,
+	0x52, 0x41, 0x43, 0x54, 0x49, 0x56, 0x45, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x42, 0x41, 0x54,
+	0x43, 0x48, 0x10, 0x01, 0x2a, 0x63, 0x0a, 0x19, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65,
+	0x72, 0x54, 0x6f, 0x46, 0x72, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x12, 0x06, 0x0a, 0x02, 0x4f, 0x4b, 0x10, 0x00, 0x12, 0x20, 0x0a, 0x1c, 0x54, 0x4f, 0x4f,
+	0x5f, 0x4d, 0x41, 0x4e, 0x59, 0x5f, 0x52, 0x45, 0x51, 0x55, 0x45, 0x53, 0x54, 0x53, 0x5f, 0x50,
+	0x45, 0x52, 0x5f, 0x54, 0x45, 0x4e, 0x41, 0x4e, 0x54, 0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x45,
+	0x52, 0x52, 0x4f, 0x52, 0x10, 0x02, 0x12, 0x11, 0x0a, 0x0d, 0x53, 0x48, 0x55, 0x54, 0x54, 0x49,
+	0x4e, 0x47, 0x5f, 0x44, 0x4f, 0x57, 0x4e, 0x10, 0x03, 0x32, 0xdc, 0x01, 0x0a, 0x13, 0x53, 0x63,
+	0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x46, 0x6f, 0x72, 0x51, 0x75, 0x65, 0x72, 0x69, 0x65,
+	0x72, 0x12, 0x55, 0x0a, 0x0b, 0x51, 0x75, 0x65, 0x72, 0x69, 0x65, 0x72, 0x4c, 0x6f, 0x6f, 0x70,
+	0x12, 0x1f, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x70, 0x62, 0x2e, 0x51,
+	0x75, 0x65, 0x72, 0x69, 0x65, 0x72, 0x54, 0x6f, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65,
+	0x72, 0x1a, 0x1f, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x70, 0x62, 0x2e,
+	0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x54, 0x6f, 0x51, 0x75, 0x65, 0x72, 0x69,
+	0x65, 0x72, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x12, 0x6e, 0x0a, 0x15, 0x4e, 0x6f, 0x74, 0x69,
+	0x66, 0x79, 0x51, 0x75, 0x65, 0x72, 0x69, 0x65, 0x72, 0x53, 0x68, 0x75, 0x74, 0x64, 0x6f, 0x77,
+	0x6e, 0x12, 0x29, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x70, 0x62, 0x2e,
 	0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x51, 0x75, 0x65, 0x72, 0x69, 0x65, 0x72, 0x53, 0x68, 0x75,
-	0x74, 0x64, 0x6f, 0x77, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2a, 0x3c, 0x0a,
-	0x17, 0x46, 0x72, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x64, 0x54, 0x6f, 0x53, 0x63, 0x68, 0x65, 0x64,
-	0x75, 0x6c, 0x65, 0x72, 0x54, 0x79, 0x70, 0x65, 0x12, 0x08, 0x0a, 0x04, 0x49, 0x4e, 0x49, 0x54,
-	0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x45, 0x4e, 0x51, 0x55, 0x45, 0x55, 0x45, 0x10, 0x01, 0x12,
-	0x0a, 0x0a, 0x06, 0x43, 0x41, 0x4e, 0x43, 0x45, 0x4c, 0x10, 0x02, 0x2a, 0x63, 0x0a, 0x19, 0x53,
-	0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x54, 0x6f, 0x46, 0x72, 0x6f, 0x6e, 0x74, 0x65,
-	0x6e, 0x64, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x06, 0x0a, 0x02, 0x4f, 0x4b, 0x10, 0x00,
-	0x12, 0x20, 0x0a, 0x1c, 0x54, 0x4f, 0x4f, 0x5f, 0x4d, 0x41, 0x4e, 0x59, 0x5f, 0x52, 0x45, 0x51,
-	0x55, 0x45, 0x53, 0x54, 0x53, 0x5f, 0x50, 0x45, 0x52, 0x5f, 0x54, 0x45, 0x4e, 0x41, 0x4e, 0x54,
-	0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x02, 0x12, 0x11, 0x0a,
-	0x0d, 0x53, 0x48, 0x55, 0x54, 0x54, 0x49, 0x4e, 0x47, 0x5f, 0x44, 0x4f, 0x57, 0x4e, 0x10, 0x03,
-	0x32, 0xdc, 0x01, 0x0a, 0x13, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x46, 0x6f,
-	0x72, 0x51, 0x75, 0x65, 0x72, 0x69, 0x65, 0x72, 0x12, 0x55, 0x0a, 0x0b, 0x51, 0x75, 0x65, 0x72,
-	0x69, 0x65, 0x72, 0x4c, 0x6f, 0x6f, 0x70, 0x12, 0x1f, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75,
-	0x6c, 0x65, 0x72, 0x70, 0x62, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x69, 0x65, 0x72, 0x54, 0x6f, 0x53,
-	0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x1a, 0x1f, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x64,
-	0x75, 0x6c, 0x65, 0x72, 0x70, 0x62, 0x2e, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72,
-	0x54, 0x6f, 0x51, 0x75, 0x65, 0x72, 0x69, 0x65, 0x72, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x12,
-	0x6e, 0x0a, 0x15, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x51, 0x75, 0x65, 0x72, 0x69, 0x65, 0x72,
-	0x53, 0x68, 0x75, 0x74, 0x64, 0x6f, 0x77, 0x6e, 0x12, 0x29, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x64,
-	0x75, 0x6c, 0x65, 0x72, 0x70, 0x62, 0x2e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x51, 0x75, 0x65,
-	0x72, 0x69, 0x65, 0x72, 0x53, 0x68, 0x75, 0x74, 0x64, 0x6f, 0x77, 0x6e, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x2a, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x70,
-	0x62, 0x2e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x51, 0x75, 0x65, 0x72, 0x69, 0x65, 0x72, 0x53,
-	0x68, 0x75, 0x74, 0x64, 0x6f, 0x77, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32,
-	0x70, 0x0a, 0x14, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x46, 0x6f, 0x72, 0x46,
-	0x72, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x64, 0x12, 0x58, 0x0a, 0x0c, 0x46, 0x72, 0x6f, 0x6e, 0x74,
-	0x65, 0x6e, 0x64, 0x4c, 0x6f, 0x6f, 0x70, 0x12, 0x20, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75,
-	0x6c, 0x65, 0x72, 0x70, 0x62, 0x2e, 0x46, 0x72, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x64, 0x54, 0x6f,
-	0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x1a, 0x20, 0x2e, 0x73, 0x63, 0x68, 0x65,
-	0x64, 0x75, 0x6c, 0x65, 0x72, 0x70, 0x62, 0x2e, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65,
-	0x72, 0x54, 0x6f, 0x46, 0x72, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x64, 0x22, 0x00, 0x28, 0x01, 0x30,
-	0x01, 0x42, 0xa5, 0x01, 0x0a, 0x0f, 0x63, 0x6f, 0x6d, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75,
-	0x6c, 0x65, 0x72, 0x70, 0x62, 0x42, 0x0e, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72,
-	0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x36, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
-	0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x72, 0x61, 0x66, 0x61, 0x6e, 0x61, 0x2f, 0x70, 0x79, 0x72, 0x6f,
-	0x73, 0x63, 0x6f, 0x70, 0x65, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75,
-	0x6c, 0x65, 0x72, 0x2f, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x70, 0x62, 0xa2,
-	0x02, 0x03, 0x53, 0x58, 0x58, 0xaa, 0x02, 0x0b, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65,
-	0x72, 0x70, 0x62, 0xca, 0x02, 0x0b, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x70,
-	0x62, 0xe2, 0x02, 0x17, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x70, 0x62, 0x5c,
-	0x47, 0x50, 0x42, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0xea, 0x02, 0x0b, 0x53, 0x63,
-	0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x33,
+	0x74, 0x64, 0x6f, 0x77, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2a, 0x2e, 0x73,
+	0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x70, 0x62, 0x2e, 0x4e, 0x6f, 0x74, 0x69, 0x66,
+	0x79, 0x51, 0x75, 0x65, 0x72, 0x69, 0x65, 0x72, 0x53, 0x68, 0x75, 0x74, 0x64, 0x6f, 0x77, 0x6e,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0x70, 0x0a, 0x14, 0x53, 0x63, 0x68, 0x65,
+	0x64, 0x75, 0x6c, 0x65, 0x72, 0x46, 0x6f, 0x72, 0x46, 0x72, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x64,
+	0x12, 0x58, 0x0a, 0x0c, 0x46, 0x72, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x64, 0x4c, 0x6f, 0x6f, 0x70,
+	0x12, 0x20, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x70, 0x62, 0x2e, 0x46,
+	0x72, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x64, 0x54, 0x6f, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c,
+	0x65, 0x72, 0x1a, 0x20, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x70, 0x62,
+	0x2e, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x54, 0x6f, 0x46, 0x72, 0x6f, 0x6e,
+	0x74, 0x65, 0x6e, 0x64, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x42, 0xa5, 0x01, 0x0a, 0x0f, 0x63,
+	0x6f, 0x6d, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x70, 0x62, 0x42, 0x0e,
+	0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01,
+	0x5a, 0x36, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x72, 0x61,
+	0x66, 0x61, 0x6e, 0x61, 0x2f, 0x70, 0x79, 0x72, 0x6f, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x2f, 0x70,
+	0x6b, 0x67, 0x2f, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x2f, 0x73, 0x63, 0x68,
+	0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x70, 0x62, 0xa2, 0x02, 0x03, 0x53, 0x58, 0x58, 0xaa, 0x02,
+	0x0b, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x70, 0x62, 0xca, 0x02, 0x0b, 0x53,
+	0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x70, 0x62, 0xe2, 0x02, 0x17, 0x53, 0x63, 0x68,
+	0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x70, 0x62, 0x5c, 0x47, 0x50, 0x42, 0x4d, 0x65, 0x74, 0x61,
+	0x64, 0x61, 0x74, 0x61, 0xea, 0x02, 0x0b, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72,
+	0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -603,35 +677,37 @@ func file_scheduler_schedulerpb_scheduler_proto_rawDescGZIP() []byte {
 	return file_scheduler_schedulerpb_scheduler_proto_rawDescData
 }
 
-var file_scheduler_schedulerpb_scheduler_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_scheduler_schedulerpb_scheduler_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
 var file_scheduler_schedulerpb_scheduler_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
 var file_scheduler_schedulerpb_scheduler_proto_goTypes = []any{
 	(FrontendToSchedulerType)(0),          // 0: schedulerpb.FrontendToSchedulerType
-	(SchedulerToFrontendStatus)(0),        // 1: schedulerpb.SchedulerToFrontendStatus
-	(*QuerierToScheduler)(nil),            // 2: schedulerpb.QuerierToScheduler
-	(*SchedulerToQuerier)(nil),            // 3: schedulerpb.SchedulerToQuerier
-	(*FrontendToScheduler)(nil),           // 4: schedulerpb.FrontendToScheduler
-	(*SchedulerToFrontend)(nil),           // 5: schedulerpb.SchedulerToFrontend
-	(*NotifyQuerierShutdownRequest)(nil),  // 6: schedulerpb.NotifyQuerierShutdownRequest
-	(*NotifyQuerierShutdownResponse)(nil), // 7: schedulerpb.NotifyQuerierShutdownResponse
-	(*httpgrpc.HTTPRequest)(nil),          // 8: httpgrpc.HTTPRequest
+	(QueryPriority)(0),                    // 1: schedulerpb.QueryPriority
+	(SchedulerToFrontendStatus)(0),        // 2: schedulerpb.SchedulerToFrontendStatus
+	(*QuerierToScheduler)(nil),            // 3: schedulerpb.QuerierToScheduler
+	(*SchedulerToQuerier)(nil),            // 4: schedulerpb.SchedulerToQuerier
+	(*FrontendToScheduler)(nil),           // 5: schedulerpb.FrontendToScheduler
+	(*SchedulerToFrontend)(nil),           // 6: schedulerpb.SchedulerToFrontend
+	(*NotifyQuerierShutdownRequest)(nil),  // 7: schedulerpb.NotifyQuerierShutdownRequest
+	(*NotifyQuerierShutdownResponse)(nil), // 8: schedulerpb.NotifyQuerierShutdownResponse
+	(*httpgrpc.HTTPRequest)(nil),          // 9: httpgrpc.HTTPRequest
 }
 var file_scheduler_schedulerpb_scheduler_proto_depIdxs = []int32{
-	8, // 0: schedulerpb.SchedulerToQuerier.httpRequest:type_name -> httpgrpc.HTTPRequest
+	9, // 0: schedulerpb.SchedulerToQuerier.httpRequest:type_name -> httpgrpc.HTTPRequest
 	0, // 1: schedulerpb.FrontendToScheduler.type:type_name -> schedulerpb.FrontendToSchedulerType
-	8, // 2: schedulerpb.FrontendToScheduler.httpRequest:type_name -> httpgrpc.HTTPRequest
-	1, // 3: schedulerpb.SchedulerToFrontend.status:type_name -> schedulerpb.SchedulerToFrontendStatus
-	2, // 4: schedulerpb.SchedulerForQuerier.QuerierLoop:input_type -> schedulerpb.QuerierToScheduler
-	6, // 5: schedulerpb.SchedulerForQuerier.NotifyQuerierShutdown:input_type -> schedulerpb.NotifyQuerierShutdownRequest
-	4, // 6: schedulerpb.SchedulerForFrontend.FrontendLoop:input_type -> schedulerpb.FrontendToScheduler
-	3, // 7: schedulerpb.SchedulerForQuerier.QuerierLoop:output_type -> schedulerpb.SchedulerToQuerier
-	7, // 8: schedulerpb.SchedulerForQuerier.NotifyQuerierShutdown:output_type -> schedulerpb.NotifyQuerierShutdownResponse
-	5, // 9: schedulerpb.SchedulerForFrontend.FrontendLoop:output_type -> schedulerpb.SchedulerToFrontend
-	7, // [7:10] is the sub-list for method output_type
-	4, // [4:7] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	9, // 2: schedulerpb.FrontendToScheduler.httpRequest:type_name -> httpgrpc.HTTPRequest
+	1, // 3: schedulerpb.FrontendToScheduler.priority:type_name -> schedulerpb.QueryPriority
+	2, // 4: schedulerpb.SchedulerToFrontend.status:type_name -> schedulerpb.SchedulerToFrontendStatus
+	3, // 5: schedulerpb.SchedulerForQuerier.QuerierLoop:input_type -> schedulerpb.QuerierToScheduler
+	7, // 6: schedulerpb.SchedulerForQuerier.NotifyQuerierShutdown:input_type -> schedulerpb.NotifyQuerierShutdownRequest
+	5, // 7: schedulerpb.SchedulerForFrontend.FrontendLoop:input_type -> schedulerpb.FrontendToScheduler
+	4, // 8: schedulerpb.SchedulerForQuerier.QuerierLoop:output_type -> schedulerpb.SchedulerToQuerier
+	8, // 9: schedulerpb.SchedulerForQuerier.NotifyQuerierShutdown:output_type -> schedulerpb.NotifyQuerierShutdownResponse
+	6, // 10: schedulerpb.SchedulerForFrontend.FrontendLoop:output_type -> schedulerpb.SchedulerToFrontend
+	8, // [8:11] is the sub-list for method output_type
+	5, // [5:8] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
 }
 
 func init() { file_scheduler_schedulerpb_scheduler_proto_init() }
@@ -718,7 +794,7 @@ func file_scheduler_schedulerpb_scheduler_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_scheduler_schedulerpb_scheduler_proto_rawDesc,
-			NumEnums:      2,
+			NumEnums:      3,
 			NumMessages:   6,
 			NumExtensions: 0,
 			NumServices:   2,
@@ -1,9 +1,7 @@
 package settings
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"slices"
 	"strings"
 	"sync"
@@ -12,6 +10,7 @@ import (
 	"github.com/thanos-io/objstore"
 
 	settingsv1 "github.com/grafana/pyroscope/api/gen/proto/go/settings/v1"
+	phlareobj "github.com/grafana/pyroscope/pkg/objstore"
 )
 
 var (
@@ -109,38 +108,12 @@ func (s *bucketStore) Close() error {
 // unsafeFlush will flush the store to object storage. This is not thread-safe,
 // the store's write mutex should be acquired first.
 func (s *bucketStore) unsafeFlush(ctx context.Context) error {
-	data, err := json.Marshal(s.store)
-	if err != nil {
-		return err
-	}
-
-	err = s.bucket.Upload(ctx, settingsFilename, bytes.NewReader(data))
-	if err != nil {
-		return err
-	}
-	return nil
+	return phlareobj.UploadJSON(ctx, s.bucket, settingsFilename, s.store)
 }
 
 // unsafeLoad will read the store in object storage into memory, if it exists.
 // This is not thread-safe, the store's write mutex should be acquired first.
 func (s *bucketStore) unsafeLoad(ctx context.Context) error {
-	reader, err := s.bucket.Get(ctx, settingsFilename)
-	if err != nil {
-		if s.bucket.IsObjNotFoundErr(err) {
-			// It is OK if we don't find the file.
-			return nil
-		}
-		return err
-	}
-
-	err = json.NewDecoder(reader).Decode(&s.store)
-	if err != nil {
-		return err
-	}
-
-	err = reader.Close()
-	if err != nil {
-		return err
-	}
-	return nil
+	_, err := phlareobj.ReadJSON(ctx, s.bucket, settingsFilename, &s.store)
+	return err
 }
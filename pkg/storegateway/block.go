@@ -5,8 +5,11 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
 	"github.com/pkg/errors"
 
 	"github.com/grafana/pyroscope/pkg/phlaredb"
@@ -22,6 +25,80 @@ type Block struct {
 	BlockCloser
 	meta   *block.Meta
 	logger log.Logger
+
+	openBlocks *openBlocks
+	mu         sync.Mutex
+	opened     bool
+	inflight   int
+	lastUsed   time.Time
+}
+
+// acquire marks the block as used by the query until the context is done.
+// A block in use can't be offloaded.
+func (b *Block) acquire(ctx context.Context) {
+	b.mu.Lock()
+	b.inflight++
+	b.lastUsed = time.Now()
+	b.mu.Unlock()
+	context.AfterFunc(ctx, func() {
+		b.mu.Lock()
+		b.inflight--
+		b.lastUsed = time.Now()
+		b.mu.Unlock()
+	})
+}
+
+// Open opens the block files, if they are not opened yet.
+func (b *Block) Open(ctx context.Context) error {
+	b.mu.Lock()
+	if b.opened {
+		b.mu.Unlock()
+		b.openBlocks.touch(b)
+		return nil
+	}
+	if err := b.BlockCloser.Open(ctx); err != nil {
+		b.mu.Unlock()
+		b.openBlocks.metrics.openFailures.Inc()
+		return err
+	}
+	b.opened = true
+	b.lastUsed = time.Now()
+	b.mu.Unlock()
+	b.openBlocks.add(b)
+	return nil
+}
+
+// Close closes the block files. The block can be opened again.
+func (b *Block) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.close()
+}
+
+func (b *Block) close() error {
+	if !b.opened {
+		return nil
+	}
+	b.opened = false
+	b.openBlocks.remove(b)
+	return b.BlockCloser.Close()
+}
+
+// offload closes the block if it is not in use and has not been used
+// since the given time. It reports whether the block has been closed.
+func (b *Block) offload(usedBefore time.Time) bool {
+	if !b.mu.TryLock() {
+		// The block is being opened or closed.
+		return false
+	}
+	defer b.mu.Unlock()
+	if !b.opened || b.inflight > 0 || b.lastUsed.After(usedBefore) {
+		return false
+	}
+	if err := b.close(); err != nil {
+		level.Warn(b.logger).Log("msg", "failed to close block", "id", b.meta.ULID, "err", err)
+	}
+	return true
 }
 
 func (bs *BucketStore) createBlock(ctx context.Context, meta *block.Meta) (*Block, error) {
@@ -70,6 +147,7 @@ func (bs *BucketStore) createBlock(ctx context.Context, meta *block.Meta) (*Bloc
 	return &Block{
 		meta:        outMeta,
 		logger:      bs.logger,
+		openBlocks:  bs.openBlocks,
 		BlockCloser: phlaredb.NewSingleBlockQuerierFromMeta(ctx, bs.bucket, outMeta),
 	}, nil
 }
@@ -34,6 +34,8 @@ type BucketStore struct {
 	fetcher block.MetadataFetcher
 
 	tenantID, syncDir string
+	lazyLoading       bool
+	openBlocks        *openBlocks
 
 	logger log.Logger
 
@@ -45,15 +47,17 @@ type BucketStore struct {
 	stats   BucketStoreStats
 }
 
-func NewBucketStore(bucket phlareobj.Bucket, fetcher block.MetadataFetcher, tenantID string, syncDir string, logger log.Logger, reg prometheus.Registerer) (*BucketStore, error) {
+func NewBucketStore(bucket phlareobj.Bucket, fetcher block.MetadataFetcher, tenantID string, syncDir string, lazyLoading bool, openBlocks *openBlocks, logger log.Logger, reg prometheus.Registerer) (*BucketStore, error) {
 	s := &BucketStore{
-		fetcher:  fetcher,
-		bucket:   phlareobj.NewTenantBucketClient(tenantID, bucket, nil),
-		tenantID: tenantID,
-		syncDir:  syncDir,
-		logger:   log.With(logger, "tenant", tenantID),
-		blockSet: newBucketBlockSet(),
-		blocks:   map[ulid.ULID]*Block{},
+		fetcher:     fetcher,
+		bucket:      phlareobj.NewTenantBucketClient(tenantID, bucket, nil),
+		tenantID:    tenantID,
+		syncDir:     syncDir,
+		lazyLoading: lazyLoading,
+		openBlocks:  openBlocks,
+		logger:      log.With(logger, "tenant", tenantID),
+		blockSet:    newBucketBlockSet(),
+		blocks:      map[ulid.ULID]*Block{},
 		metrics: NewBucketStoreMetrics(prometheus.WrapRegistererWith(
 			prometheus.Labels{"tenant": tenantID},
 			reg,
@@ -192,9 +196,10 @@ func (bs *BucketStore) addBlock(ctx context.Context, meta *block.Meta) (err erro
 	if err != nil {
 		return err
 	}
-	// Load the block into memory if it's within the last 24 hours.
+	// With lazy loading, the block is opened on the first query.
+	// Otherwise, load the block into memory if it's within the last 24 hours.
 	// Todo make this configurable
-	if phlaredb.InRange(b, model.Now().Add(-24*time.Hour), model.Now()) {
+	if !bs.lazyLoading && phlaredb.InRange(b, model.Now().Add(-24*time.Hour), model.Now()) {
 		level.Debug(bs.logger).Log("msg", "opening block",
 			"id", meta.ULID.String(),
 			"min", b.meta.MinTime.Time().Format(time.RFC3339),
@@ -254,6 +259,13 @@ func (s *BucketStore) localPath(id string) string {
 
 // RemoveBlocksAndClose remove all blocks from local disk and releases all resources associated with the BucketStore.
 func (s *BucketStore) RemoveBlocksAndClose() error {
+	s.blocksMx.RLock()
+	for _, b := range s.blocks {
+		if err := b.Close(); err != nil {
+			level.Warn(s.logger).Log("msg", "failed to close block", "block", b.meta.ULID, "err", err)
+		}
+	}
+	s.blocksMx.RUnlock()
 	if err := os.RemoveAll(s.syncDir); err != nil {
 		return errors.Wrap(err, "delete block")
 	}
@@ -31,13 +31,17 @@ type BucketStoreConfig struct {
 	IgnoreBlocksWithin       time.Duration `yaml:"ignore_blocks_within" category:"advanced"`
 	MetaSyncConcurrency      int           `yaml:"meta_sync_concurrency" category:"advanced"`
 	IgnoreDeletionMarksDelay time.Duration `yaml:"ignore_deletion_mark_delay" category:"advanced"`
+
+	BlockLazyLoadingEnabled     bool          `yaml:"block_lazy_loading_enabled" category:"advanced"`
+	BlockLazyLoadingIdleTimeout time.Duration `yaml:"block_lazy_loading_idle_timeout" category:"advanced"`
+	MaxOpenBlocks               int           `yaml:"max_open_blocks" category:"advanced"`
+
+	Cache phlareobj.CachingBucketConfig `yaml:"cache" category:"experimental"`
 }
 
 // RegisterFlags registers the BucketStore flags
 func (cfg *BucketStoreConfig) RegisterFlags(f *flag.FlagSet, logger log.Logger) {
-	// cfg.IndexCache.RegisterFlagsWithPrefix(f, "blocks-storage.bucket-store.index-cache.")
-	// cfg.ChunksCache.RegisterFlagsWithPrefix(f, "blocks-storage.bucket-store.chunks-cache.", logger)
-	// cfg.MetadataCache.RegisterFlagsWithPrefix(f, "blocks-storage.bucket-store.metadata-cache.")
+	cfg.Cache.RegisterFlagsWithPrefix("blocks-storage.bucket-store.cache.", f)
 	// cfg.BucketIndex.RegisterFlagsWithPrefix(f, "blocks-storage.bucket-store.bucket-index.")
 	// cfg.IndexHeader.RegisterFlagsWithPrefix(f, "blocks-storage.bucket-store.index-header.")
 
@@ -57,8 +61,9 @@ func (cfg *BucketStoreConfig) RegisterFlags(f *flag.FlagSet, logger log.Logger)
 	f.DurationVar(&cfg.IgnoreDeletionMarksDelay, "blocks-storage.bucket-store.ignore-deletion-marks-delay", 30*time.Minute, "Duration after which the blocks marked for deletion will be filtered out while fetching blocks. "+
 		"The idea of ignore-deletion-marks-delay is to ignore blocks that are marked for deletion with some delay. This ensures store can still serve blocks that are meant to be deleted but do not have a replacement yet.")
 	// f.IntVar(&cfg.PostingOffsetsInMemSampling, "blocks-storage.bucket-store.posting-offsets-in-mem-sampling", DefaultPostingOffsetInMemorySampling, "Controls what is the ratio of postings offsets that the store will hold in memory.")
-	// f.BoolVar(&cfg.IndexHeaderLazyLoadingEnabled, "blocks-storage.bucket-store.index-header-lazy-loading-enabled", true, "If enabled, store-gateway will lazy load an index-header only once required by a query.")
-	// f.DurationVar(&cfg.IndexHeaderLazyLoadingIdleTimeout, "blocks-storage.bucket-store.index-header-lazy-loading-idle-timeout", 60*time.Minute, "If index-header lazy loading is enabled and this setting is > 0, the store-gateway will offload unused index-headers after 'idle timeout' inactivity.")
+	f.BoolVar(&cfg.BlockLazyLoadingEnabled, "blocks-storage.bucket-store.block-lazy-loading-enabled", false, "If enabled, store-gateway will open a block (TSDB index, symbols and parquet footers) only once required by a query. Otherwise, blocks from the last 24 hours are opened when synced.")
+	f.DurationVar(&cfg.BlockLazyLoadingIdleTimeout, "blocks-storage.bucket-store.block-lazy-loading-idle-timeout", 60*time.Minute, "If block lazy loading is enabled and this setting is > 0, the store-gateway will close blocks not queried for the idle timeout.")
+	f.IntVar(&cfg.MaxOpenBlocks, "blocks-storage.bucket-store.max-open-blocks", 0, "If block lazy loading is enabled and this setting is > 0, the store-gateway will close the least recently used blocks to keep the number of open blocks within the limit. The limit is shared across all tenants, and it may be exceeded temporarily if all open blocks are in use.")
 	// f.Uint64Var(&cfg.PartitionerMaxGapBytes, "blocks-storage.bucket-store.partitioner-max-gap-bytes", DefaultPartitionerMaxGapSize, "Max size - in bytes - of a gap for which the partitioner aggregates together two bucket GET object requests.")
 	// f.IntVar(&cfg.StreamingBatchSize, "blocks-storage.bucket-store.batch-series-size", 5000, "This option controls how many series to fetch per batch. The batch size must be greater than 0.")
 	// f.IntVar(&cfg.ChunkRangesPerSeries, "blocks-storage.bucket-store.fine-grained-chunks-caching-ranges-per-series", 1, "This option controls into how many ranges the chunks of each series from each block are split. This value is effectively the number of chunks cache items per series per block when -blocks-storage.bucket-store.chunks-cache.fine-grained-chunks-caching-enabled is enabled.")
@@ -70,15 +75,12 @@ func (cfg *BucketStoreConfig) Validate(logger log.Logger) error {
 	// if cfg.StreamingBatchSize <= 0 {
 	// 	return errInvalidStreamingBatchSize
 	// }
-	// if err := cfg.IndexCache.Validate(); err != nil {
-	// 	return errors.Wrap(err, "index-cache configuration")
-	// }
-	// if err := cfg.ChunksCache.Validate(); err != nil {
-	// 	return errors.Wrap(err, "chunks-cache configuration")
-	// }
-	// if err := cfg.MetadataCache.Validate(); err != nil {
-	// 	return errors.Wrap(err, "metadata-cache configuration")
-	// }
+	if err := cfg.Cache.Validate(); err != nil {
+		return errors.Wrap(err, "cache configuration")
+	}
+	if cfg.MaxOpenBlocks < 0 {
+		return errors.New("max open blocks must not be negative")
+	}
 	// if cfg.DeprecatedConsistencyDelay > 0 {
 	// 	util.WarnDeprecatedConfig(consistencyDelayFlag, logger)
 	// }
@@ -111,6 +113,8 @@ type BucketStores struct {
 	// Keeps a bucket store for each tenant.
 	storesMu sync.RWMutex
 	stores   map[string]*BucketStore
+	// Blocks open across all the tenants.
+	openBlocks *openBlocks
 
 	// Metrics.
 	syncTimes         prometheus.Histogram
@@ -134,6 +138,7 @@ func NewBucketStores(cfg BucketStoreConfig, shardingStrategy ShardingStrategy, s
 		shardingStrategy: shardingStrategy,
 		reg:              reg,
 		limits:           limits,
+		openBlocks:       newOpenBlocks(cfg, reg),
 	}
 	// Register metrics.
 	bs.syncTimes = promauto.With(reg).NewHistogram(prometheus.HistogramOpts{
@@ -160,6 +165,11 @@ func NewBucketStores(cfg BucketStoreConfig, shardingStrategy ShardingStrategy, s
 	return bs, nil
 }
 
+// OffloadIdleBlocks closes blocks that have not been queried for the idle timeout.
+func (bs *BucketStores) OffloadIdleBlocks() {
+	bs.openBlocks.offloadIdle()
+}
+
 // SyncBlocks synchronizes the stores state with the Bucket store for every user.
 func (bs *BucketStores) SyncBlocks(ctx context.Context) error {
 	return bs.syncUsersBlocksWithRetries(ctx, func(ctx context.Context, s *BucketStore) error {
@@ -341,6 +351,8 @@ func (bs *BucketStores) getOrCreateStore(userID string) (*BucketStore, error) {
 		fetcher,
 		userID,
 		bs.syncDirForUser(userID),
+		bs.cfg.BlockLazyLoadingEnabled,
+		bs.openBlocks,
 		userLogger,
 		bs.reg,
 	)
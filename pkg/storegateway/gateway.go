@@ -35,6 +35,7 @@ var errInvalidTenantShardSize = errors.New("invalid tenant shard size, the value
 type Limits interface {
 	ShardingLimits
 	phlareobj.TenantConfigProvider
+	validation.QueryImpactLimits
 }
 
 // ShardingLimits is the interface that should be implemented by the limits provider,
@@ -236,6 +237,14 @@ func (g *StoreGateway) running(ctx context.Context) error {
 	syncTicker := time.NewTicker(util.DurationWithJitter(g.gatewayCfg.BucketStoreConfig.SyncInterval, 0.2))
 	defer syncTicker.Stop()
 
+	// Idle blocks are offloaded only if lazy loading is enabled.
+	var offloadTickerChan <-chan time.Time
+	if cfg := g.gatewayCfg.BucketStoreConfig; cfg.BlockLazyLoadingEnabled && cfg.BlockLazyLoadingIdleTimeout > 0 {
+		offloadTicker := time.NewTicker(util.DurationWithJitter(min(cfg.BlockLazyLoadingIdleTimeout, time.Minute), 0.2))
+		defer offloadTicker.Stop()
+		offloadTickerChan = offloadTicker.C
+	}
+
 	ringLastState, _ := g.ring.GetAllHealthy(BlocksOwnerSync) // nolint:errcheck
 	ringTicker := time.NewTicker(util.DurationWithJitter(g.gatewayCfg.ShardingRing.RingCheckPeriod, 0.2))
 	defer ringTicker.Stop()
@@ -244,6 +253,8 @@ func (g *StoreGateway) running(ctx context.Context) error {
 		select {
 		case <-syncTicker.C:
 			g.syncStores(ctx, syncReasonPeriodic)
+		case <-offloadTickerChan:
+			g.stores.OffloadIdleBlocks()
 		case <-ringTicker.C:
 			// We ignore the error because in case of error it will return an empty
 			// replication set which we use to compare with the previous state.
@@ -0,0 +1,127 @@
+package storegateway
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	offloadReasonIdle  = "idle"
+	offloadReasonLimit = "limit"
+)
+
+// openBlocks keeps track of the blocks opened across all the tenants
+// in the least recently used order. It is used to offload idle blocks
+// and to limit the number of concurrently open blocks.
+type openBlocks struct {
+	maxOpen     int
+	idleTimeout time.Duration
+
+	mu     sync.Mutex
+	lru    *list.List
+	blocks map[*Block]*list.Element
+
+	metrics *openBlocksMetrics
+}
+
+type openBlocksMetrics struct {
+	openFailures prometheus.Counter
+	offloads     *prometheus.CounterVec
+}
+
+func newOpenBlocks(cfg BucketStoreConfig, reg prometheus.Registerer) *openBlocks {
+	o := &openBlocks{
+		lru:    list.New(),
+		blocks: make(map[*Block]*list.Element),
+		metrics: &openBlocksMetrics{
+			openFailures: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "pyroscope_bucket_store_block_open_failures_total",
+				Help: "Total number of failed attempts to open a block.",
+			}),
+			offloads: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "pyroscope_bucket_store_block_offloads_total",
+				Help: "Total number of open blocks that were closed to release resources.",
+			}, []string{"reason"}),
+		},
+	}
+	if cfg.BlockLazyLoadingEnabled {
+		o.maxOpen = cfg.MaxOpenBlocks
+		o.idleTimeout = cfg.BlockLazyLoadingIdleTimeout
+	}
+	promauto.With(reg).NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "pyroscope_bucket_store_blocks_open",
+		Help: "Number of currently open blocks.",
+	}, func() float64 {
+		o.mu.Lock()
+		defer o.mu.Unlock()
+		return float64(o.lru.Len())
+	})
+	return o
+}
+
+func (o *openBlocks) add(b *Block) {
+	o.mu.Lock()
+	if e, ok := o.blocks[b]; ok {
+		o.lru.MoveToFront(e)
+	} else {
+		o.blocks[b] = o.lru.PushFront(b)
+	}
+	var candidates []*Block
+	if o.maxOpen > 0 && o.lru.Len() > o.maxOpen {
+		// Least recently used blocks go first.
+		for e := o.lru.Back(); e != nil && len(candidates) < o.lru.Len()-o.maxOpen; e = e.Prev() {
+			if c := e.Value.(*Block); c != b {
+				candidates = append(candidates, c)
+			}
+		}
+	}
+	o.mu.Unlock()
+	// The limit may be exceeded temporarily,
+	// if all the open blocks are in use.
+	now := time.Now()
+	for _, c := range candidates {
+		if c.offload(now) {
+			o.metrics.offloads.WithLabelValues(offloadReasonLimit).Inc()
+		}
+	}
+}
+
+func (o *openBlocks) touch(b *Block) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if e, ok := o.blocks[b]; ok {
+		o.lru.MoveToFront(e)
+	}
+}
+
+func (o *openBlocks) remove(b *Block) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if e, ok := o.blocks[b]; ok {
+		o.lru.Remove(e)
+		delete(o.blocks, b)
+	}
+}
+
+// offloadIdle closes the blocks that have not been used for the idle timeout.
+func (o *openBlocks) offloadIdle() {
+	if o.idleTimeout <= 0 {
+		return
+	}
+	usedBefore := time.Now().Add(-o.idleTimeout)
+	o.mu.Lock()
+	candidates := make([]*Block, 0, o.lru.Len())
+	for e := o.lru.Back(); e != nil; e = e.Prev() {
+		candidates = append(candidates, e.Value.(*Block))
+	}
+	o.mu.Unlock()
+	for _, c := range candidates {
+		if c.offload(usedBefore) {
+			o.metrics.offloads.WithLabelValues(offloadReasonIdle).Inc()
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package storegateway
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/oklog/ulid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/pyroscope/pkg/phlaredb"
+	"github.com/grafana/pyroscope/pkg/phlaredb/block"
+)
+
+type fakeBlockCloser struct {
+	phlaredb.Querier
+	opens  int
+	closes int
+}
+
+func (f *fakeBlockCloser) Open(context.Context) error { f.opens++; return nil }
+func (f *fakeBlockCloser) Close() error               { f.closes++; return nil }
+
+func newTestBlocks(o *openBlocks, n int) ([]*Block, []*fakeBlockCloser) {
+	blocks := make([]*Block, n)
+	closers := make([]*fakeBlockCloser, n)
+	for i := range blocks {
+		closers[i] = new(fakeBlockCloser)
+		blocks[i] = &Block{
+			BlockCloser: closers[i],
+			meta:        &block.Meta{ULID: ulid.MustNew(uint64(i), nil)},
+			logger:      log.NewNopLogger(),
+			openBlocks:  o,
+		}
+	}
+	return blocks, closers
+}
+
+func Test_OpenBlocks_IdleOffload(t *testing.T) {
+	o := newOpenBlocks(BucketStoreConfig{
+		BlockLazyLoadingEnabled:     true,
+		BlockLazyLoadingIdleTimeout: time.Millisecond,
+	}, prometheus.NewRegistry())
+	blocks, closers := newTestBlocks(o, 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	for _, b := range blocks {
+		b.acquire(ctx)
+		require.NoError(t, b.Open(ctx))
+		require.NoError(t, b.Open(ctx))
+	}
+	assert.Equal(t, 1, closers[0].opens)
+	assert.Equal(t, 2, o.lru.Len())
+
+	// Blocks in use are not offloaded.
+	time.Sleep(5 * time.Millisecond)
+	o.offloadIdle()
+	assert.Equal(t, 0, closers[0].closes)
+	assert.Equal(t, 2, o.lru.Len())
+
+	cancel()
+	require.Eventually(t, func() bool {
+		time.Sleep(2 * time.Millisecond)
+		o.offloadIdle()
+		return closers[0].closes == 1 && closers[1].closes == 1
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, 0, o.lru.Len())
+	assert.Equal(t, float64(2), testutil.ToFloat64(o.metrics.offloads.WithLabelValues(offloadReasonIdle)))
+
+	// The block is opened again on the next query.
+	require.NoError(t, blocks[0].Open(context.Background()))
+	assert.Equal(t, 2, closers[0].opens)
+	assert.Equal(t, 1, o.lru.Len())
+}
+
+func Test_OpenBlocks_MaxOpen(t *testing.T) {
+	o := newOpenBlocks(BucketStoreConfig{
+		BlockLazyLoadingEnabled: true,
+		MaxOpenBlocks:           2,
+	}, prometheus.NewRegistry())
+	blocks, closers := newTestBlocks(o, 4)
+	ctx := context.Background()
+
+	require.NoError(t, blocks[0].Open(ctx))
+	require.NoError(t, blocks[1].Open(ctx))
+	// Block 0 is used more recently than block 1.
+	require.NoError(t, blocks[0].Open(ctx))
+	require.NoError(t, blocks[2].Open(ctx))
+	assert.Equal(t, []int{0, 1, 0, 0}, []int{closers[0].closes, closers[1].closes, closers[2].closes, closers[3].closes})
+
+	// Blocks in use are not offloaded: the limit is exceeded.
+	queryCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	blocks[0].acquire(queryCtx)
+	blocks[2].acquire(queryCtx)
+	require.NoError(t, blocks[3].Open(ctx))
+	assert.Equal(t, 3, o.lru.Len())
+	assert.Equal(t, float64(1), testutil.ToFloat64(o.metrics.offloads.WithLabelValues(offloadReasonLimit)))
+
+	require.NoError(t, blocks[0].Close())
+	assert.Equal(t, 2, o.lru.Len())
+}
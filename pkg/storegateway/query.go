@@ -17,7 +17,7 @@ import (
 
 func (s *StoreGateway) MergeProfilesStacktraces(ctx context.Context, stream *connect.BidiStream[ingestv1.MergeProfilesStacktracesRequest, ingestv1.MergeProfilesStacktracesResponse]) error {
 	found, err := s.forBucketStore(ctx, func(bs *BucketStore) error {
-		return bs.MergeProfilesStacktraces(ctx, stream)
+		return bs.MergeProfilesStacktraces(s.withQueryLimiter(ctx, bs.tenantID), stream)
 	})
 	if err != nil || found {
 		return err
@@ -27,7 +27,7 @@ func (s *StoreGateway) MergeProfilesStacktraces(ctx context.Context, stream *con
 
 func (s *StoreGateway) MergeProfilesLabels(ctx context.Context, stream *connect.BidiStream[ingestv1.MergeProfilesLabelsRequest, ingestv1.MergeProfilesLabelsResponse]) error {
 	found, err := s.forBucketStore(ctx, func(bs *BucketStore) error {
-		return bs.MergeProfilesLabels(ctx, stream)
+		return bs.MergeProfilesLabels(s.withQueryLimiter(ctx, bs.tenantID), stream)
 	})
 	if err != nil || found {
 		return err
@@ -37,7 +37,7 @@ func (s *StoreGateway) MergeProfilesLabels(ctx context.Context, stream *connect.
 
 func (s *StoreGateway) MergeProfilesPprof(ctx context.Context, stream *connect.BidiStream[ingestv1.MergeProfilesPprofRequest, ingestv1.MergeProfilesPprofResponse]) error {
 	found, err := s.forBucketStore(ctx, func(bs *BucketStore) error {
-		return bs.MergeProfilesPprof(ctx, stream)
+		return bs.MergeProfilesPprof(s.withQueryLimiter(ctx, bs.tenantID), stream)
 	})
 	if err != nil || found {
 		return err
@@ -116,7 +116,7 @@ func (s *StoreGateway) Series(ctx context.Context, req *connect.Request[ingestv1
 
 func (s *StoreGateway) MergeSpanProfile(ctx context.Context, stream *connect.BidiStream[ingestv1.MergeSpanProfileRequest, ingestv1.MergeSpanProfileResponse]) error {
 	found, err := s.forBucketStore(ctx, func(bs *BucketStore) error {
-		return bs.MergeSpanProfile(ctx, stream)
+		return bs.MergeSpanProfile(s.withQueryLimiter(ctx, bs.tenantID), stream)
 	})
 	if err != nil || found {
 		return err
@@ -186,6 +186,12 @@ func (s *StoreGateway) forBucketStore(ctx context.Context, f func(*BucketStore)
 	return false, nil
 }
 
+// withQueryLimiter attaches the limiter of the resources
+// the query is allowed to consume to the context.
+func (s *StoreGateway) withQueryLimiter(ctx context.Context, tenantID string) context.Context {
+	return phlaredb.ContextWithQueryLimiter(ctx, phlaredb.NewQueryLimiter(s.stores.limits, tenantID))
+}
+
 func (s *BucketStore) openBlocksForReading(ctx context.Context, minT, maxT model.Time, hints *ingestv1.Hints) (phlaredb.Queriers, error) {
 	skipBlock := phlaredb.HintsToBlockSkipper(hints)
 	blks := s.blockSet.getFor(minT, maxT)
@@ -194,6 +200,7 @@ func (s *BucketStore) openBlocksForReading(ctx context.Context, minT, maxT model
 		if skipBlock(b.BlockID()) {
 			continue
 		}
+		b.acquire(ctx)
 		querier = append(querier, b)
 	}
 	if err := querier.Open(ctx); err != nil {
@@ -95,7 +95,9 @@ func ExtractTenantIDFromHeaders(ctx context.Context, headers http.Header) (strin
 
 	tenantID, err := defaultResolver.TenantID(ctx)
 	if err != nil {
-		return "", nil, err
+		// The context is still returned, as handlers supporting multiple
+		// tenants resolve them from it.
+		return "", ctx, err
 	}
 
 	return tenantID, ctx, nil
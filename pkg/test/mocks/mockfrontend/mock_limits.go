@@ -113,6 +113,52 @@ func (_c *MockLimits_MaxFlameGraphNodesMax_Call) RunAndReturn(run func(string) i
 	return _c
 }
 
+// MaxQueryBytesScanned provides a mock function with given fields: tenantID
+func (_m *MockLimits) MaxQueryBytesScanned(tenantID string) int {
+	ret := _m.Called(tenantID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MaxQueryBytesScanned")
+	}
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(string) int); ok {
+		r0 = rf(tenantID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	return r0
+}
+
+// MockLimits_MaxQueryBytesScanned_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MaxQueryBytesScanned'
+type MockLimits_MaxQueryBytesScanned_Call struct {
+	*mock.Call
+}
+
+// MaxQueryBytesScanned is a helper method to define mock.On call
+//   - tenantID string
+func (_e *MockLimits_Expecter) MaxQueryBytesScanned(tenantID interface{}) *MockLimits_MaxQueryBytesScanned_Call {
+	return &MockLimits_MaxQueryBytesScanned_Call{Call: _e.mock.On("MaxQueryBytesScanned", tenantID)}
+}
+
+func (_c *MockLimits_MaxQueryBytesScanned_Call) Run(run func(tenantID string)) *MockLimits_MaxQueryBytesScanned_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockLimits_MaxQueryBytesScanned_Call) Return(_a0 int) *MockLimits_MaxQueryBytesScanned_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockLimits_MaxQueryBytesScanned_Call) RunAndReturn(run func(string) int) *MockLimits_MaxQueryBytesScanned_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // MaxQueryLength provides a mock function with given fields: tenantID
 func (_m *MockLimits) MaxQueryLength(tenantID string) time.Duration {
 	ret := _m.Called(tenantID)
@@ -251,6 +297,98 @@ func (_c *MockLimits_MaxQueryParallelism_Call) RunAndReturn(run func(string) int
 	return _c
 }
 
+// MaxQueryProfiles provides a mock function with given fields: tenantID
+func (_m *MockLimits) MaxQueryProfiles(tenantID string) int {
+	ret := _m.Called(tenantID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MaxQueryProfiles")
+	}
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(string) int); ok {
+		r0 = rf(tenantID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	return r0
+}
+
+// MockLimits_MaxQueryProfiles_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MaxQueryProfiles'
+type MockLimits_MaxQueryProfiles_Call struct {
+	*mock.Call
+}
+
+// MaxQueryProfiles is a helper method to define mock.On call
+//   - tenantID string
+func (_e *MockLimits_Expecter) MaxQueryProfiles(tenantID interface{}) *MockLimits_MaxQueryProfiles_Call {
+	return &MockLimits_MaxQueryProfiles_Call{Call: _e.mock.On("MaxQueryProfiles", tenantID)}
+}
+
+func (_c *MockLimits_MaxQueryProfiles_Call) Run(run func(tenantID string)) *MockLimits_MaxQueryProfiles_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockLimits_MaxQueryProfiles_Call) Return(_a0 int) *MockLimits_MaxQueryProfiles_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockLimits_MaxQueryProfiles_Call) RunAndReturn(run func(string) int) *MockLimits_MaxQueryProfiles_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MaxQuerySeries provides a mock function with given fields: tenantID
+func (_m *MockLimits) MaxQuerySeries(tenantID string) int {
+	ret := _m.Called(tenantID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MaxQuerySeries")
+	}
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(string) int); ok {
+		r0 = rf(tenantID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	return r0
+}
+
+// MockLimits_MaxQuerySeries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MaxQuerySeries'
+type MockLimits_MaxQuerySeries_Call struct {
+	*mock.Call
+}
+
+// MaxQuerySeries is a helper method to define mock.On call
+//   - tenantID string
+func (_e *MockLimits_Expecter) MaxQuerySeries(tenantID interface{}) *MockLimits_MaxQuerySeries_Call {
+	return &MockLimits_MaxQuerySeries_Call{Call: _e.mock.On("MaxQuerySeries", tenantID)}
+}
+
+func (_c *MockLimits_MaxQuerySeries_Call) Run(run func(tenantID string)) *MockLimits_MaxQuerySeries_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockLimits_MaxQuerySeries_Call) Return(_a0 int) *MockLimits_MaxQuerySeries_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockLimits_MaxQuerySeries_Call) RunAndReturn(run func(string) int) *MockLimits_MaxQuerySeries_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // QueryAnalysisEnabled provides a mock function with given fields: _a0
 func (_m *MockLimits) QueryAnalysisEnabled(_a0 string) bool {
 	ret := _m.Called(_a0)
@@ -297,6 +435,98 @@ func (_c *MockLimits_QueryAnalysisEnabled_Call) RunAndReturn(run func(string) bo
 	return _c
 }
 
+// QueryAnalysisSeriesEnabled provides a mock function with given fields: _a0
+func (_m *MockLimits) QueryAnalysisSeriesEnabled(_a0 string) bool {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueryAnalysisSeriesEnabled")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// MockLimits_QueryAnalysisSeriesEnabled_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryAnalysisSeriesEnabled'
+type MockLimits_QueryAnalysisSeriesEnabled_Call struct {
+	*mock.Call
+}
+
+// QueryAnalysisSeriesEnabled is a helper method to define mock.On call
+//   - _a0 string
+func (_e *MockLimits_Expecter) QueryAnalysisSeriesEnabled(_a0 interface{}) *MockLimits_QueryAnalysisSeriesEnabled_Call {
+	return &MockLimits_QueryAnalysisSeriesEnabled_Call{Call: _e.mock.On("QueryAnalysisSeriesEnabled", _a0)}
+}
+
+func (_c *MockLimits_QueryAnalysisSeriesEnabled_Call) Run(run func(_a0 string)) *MockLimits_QueryAnalysisSeriesEnabled_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockLimits_QueryAnalysisSeriesEnabled_Call) Return(_a0 bool) *MockLimits_QueryAnalysisSeriesEnabled_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockLimits_QueryAnalysisSeriesEnabled_Call) RunAndReturn(run func(string) bool) *MockLimits_QueryAnalysisSeriesEnabled_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// QueryCostEstimationEnabled provides a mock function with given fields: _a0
+func (_m *MockLimits) QueryCostEstimationEnabled(_a0 string) bool {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueryCostEstimationEnabled")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// MockLimits_QueryCostEstimationEnabled_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryCostEstimationEnabled'
+type MockLimits_QueryCostEstimationEnabled_Call struct {
+	*mock.Call
+}
+
+// QueryCostEstimationEnabled is a helper method to define mock.On call
+//   - _a0 string
+func (_e *MockLimits_Expecter) QueryCostEstimationEnabled(_a0 interface{}) *MockLimits_QueryCostEstimationEnabled_Call {
+	return &MockLimits_QueryCostEstimationEnabled_Call{Call: _e.mock.On("QueryCostEstimationEnabled", _a0)}
+}
+
+func (_c *MockLimits_QueryCostEstimationEnabled_Call) Run(run func(_a0 string)) *MockLimits_QueryCostEstimationEnabled_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockLimits_QueryCostEstimationEnabled_Call) Return(_a0 bool) *MockLimits_QueryCostEstimationEnabled_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockLimits_QueryCostEstimationEnabled_Call) RunAndReturn(run func(string) bool) *MockLimits_QueryCostEstimationEnabled_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // QuerySplitDuration provides a mock function with given fields: _a0
 func (_m *MockLimits) QuerySplitDuration(_a0 string) time.Duration {
 	ret := _m.Called(_a0)
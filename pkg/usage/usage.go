@@ -42,10 +42,9 @@ func Usage(printAll bool, configs ...interface{}) error {
 			return
 		}
 
-		if override, ok := fieldcategory.GetOverride(fl.Name); ok {
-			fieldCat = override
-		} else if v.Kind() == reflect.Ptr {
+		if v.Kind() == reflect.Ptr {
 			ptr := v.Pointer()
+			var ok bool
 			field, ok = fields[ptr]
 			if ok {
 				catStr := field.Tag.Get("category")
@@ -57,6 +56,11 @@ func Usage(printAll bool, configs ...interface{}) error {
 				}
 			}
 		}
+		// The field is looked up regardless of the override,
+		// as it also documents the default value of the flag.
+		if override, ok := fieldcategory.GetOverride(fl.Name); ok {
+			fieldCat = override
+		}
 
 		if fieldCat != fieldcategory.Basic && !printAll {
 			// Don't print help for this flag since we're supposed to print only basic flags
@@ -47,7 +47,7 @@ type OverridesExporter struct {
 
 	// OverridesExporter can optionally use a ring to uniquely shard tenants to
 	// instances and avoid export of duplicate metrics.
-	ring *overridesExporterRing
+	ring *LeaderRing
 }
 
 // NewOverridesExporter creates an OverridesExporter that reads updates to per-tenant
@@ -181,7 +181,7 @@ func (oe *OverridesExporter) isLeader() bool {
 		// We haven't finished startup yet, likely waiting for ring stability.
 		return false
 	}
-	isLeaderNow, err := oe.ring.isLeader()
+	isLeaderNow, err := oe.ring.IsLeader()
 	if err != nil {
 		// If there was an error establishing ownership using the ring, log a warning and
 		// default to not exporting metrics to keep series churn low for transient ring
@@ -23,8 +23,8 @@ const (
 	// ringKey is the key under which we store the overrides-exporter's ring in the KVStore.
 	ringKey = "overrides-exporter"
 
-	// ringNumTokens is how many tokens each overrides-exporter should have in the
-	// ring. Overrides-exporter uses tokens to establish a ring leader, therefore
+	// ringNumTokens is how many tokens each instance should have in a leader
+	// ring. The tokens are only used to establish a ring leader, therefore
 	// only one token is needed.
 	ringNumTokens = 1
 
@@ -43,7 +43,8 @@ const (
 // `KeepInstanceInTheRingOnShutdown`).
 var ringOp = ring.NewOp([]ring.InstanceState{ring.ACTIVE, ring.LEAVING}, nil)
 
-// RingConfig holds the configuration for the overrides-exporter ring.
+// RingConfig holds the configuration for the overrides-exporter ring, or
+// any other LeaderRing.
 type RingConfig struct {
 	Ring util.CommonRingConfig `yaml:",inline"`
 
@@ -54,13 +55,17 @@ type RingConfig struct {
 
 // RegisterFlags configures this RingConfig to the given flag set and sets defaults.
 func (c *RingConfig) RegisterFlags(f *flag.FlagSet, logger log.Logger) {
-	const flagNamePrefix = "overrides-exporter.ring."
+	c.RegisterFlagsWithPrefix("overrides-exporter.ring.", "overrides-exporters", f, logger)
+}
+
+// RegisterFlagsWithPrefix registers the flags of a leader ring of the
+// given component, with the flag names starting with the prefix.
+func (c *RingConfig) RegisterFlagsWithPrefix(flagNamePrefix, componentPlural string, f *flag.FlagSet, logger log.Logger) {
 	const kvStorePrefix = "collectors/"
-	const componentPlural = "overrides-exporters"
 	c.Ring.RegisterFlags(flagNamePrefix, kvStorePrefix, componentPlural, f, logger)
 	// Ring stability flags.
 	f.DurationVar(&c.WaitStabilityMinDuration, flagNamePrefix+"wait-stability-min-duration", 0, "Minimum time to wait for ring stability at startup, if set to positive value. Set to 0 to disable.")
-	f.DurationVar(&c.WaitStabilityMaxDuration, flagNamePrefix+"wait-stability-max-duration", 5*time.Minute, "Maximum time to wait for ring stability at startup. If the overrides-exporter ring keeps changing after this period of time, it will start anyway.")
+	f.DurationVar(&c.WaitStabilityMaxDuration, flagNamePrefix+"wait-stability-max-duration", 5*time.Minute, "Maximum time to wait for ring stability at startup. If the ring keeps changing after this period of time, the instance will start anyway.")
 }
 
 // toBasicLifecyclerConfig transforms a RingConfig into configuration that can be used to create a BasicLifecycler.
@@ -97,18 +102,20 @@ func (cfg *RingConfig) ToRingConfig() ring.Config {
 func (c *RingConfig) Validate() error {
 	if c.WaitStabilityMinDuration > 0 {
 		if c.WaitStabilityMinDuration > c.WaitStabilityMaxDuration {
-			return errors.New("-overrides-exporter.ring.wait-stability-max-duration must be greater or equal " +
-				"to -overrides-exporter.ring.wait-stability-min-duration")
+			return errors.New("ring wait-stability-max-duration must be greater or equal " +
+				"to wait-stability-min-duration")
 		}
 	}
 	return nil
 }
 
-// overridesExporterRing is a ring client that overrides-exporters can use to
-// establish a leader replica that is the unique exporter of per-tenant limit metrics.
-type overridesExporterRing struct {
+// LeaderRing is a ring client that the replicas of a component can use to
+// establish a leader replica, e.g. the unique exporter of per-tenant limit
+// metrics of the overrides-exporters.
+type LeaderRing struct {
 	services.Service
 
+	name   string
 	config RingConfig
 
 	client     *ring.Ring
@@ -119,17 +126,24 @@ type overridesExporterRing struct {
 	logger            log.Logger
 }
 
-// newRing creates a new overridesExporterRing from the given configuration.
-func newRing(config RingConfig, logger log.Logger, reg prometheus.Registerer) (*overridesExporterRing, error) {
+// newRing creates the ring of the overrides-exporters from the given configuration.
+func newRing(config RingConfig, logger log.Logger, reg prometheus.Registerer) (*LeaderRing, error) {
+	return NewLeaderRing(ringKey, config, logger, reg)
+}
+
+// NewLeaderRing creates a new LeaderRing from the given configuration. The
+// name identifies the ring: it is the key the ring is stored under in the
+// KVStore.
+func NewLeaderRing(name string, config RingConfig, logger log.Logger, reg prometheus.Registerer) (*LeaderRing, error) {
 	reg = prometheus.WrapRegistererWithPrefix("pyroscope_", reg)
 	kvStore, err := kv.NewClient(
 		config.Ring.KVStore,
 		ring.GetCodec(),
-		kv.RegistererWithKVName(reg, "overrides-exporter-lifecycler"),
+		kv.RegistererWithKVName(reg, name+"-lifecycler"),
 		logger,
 	)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to initialize overrides-exporter's KV store")
+		return nil, errors.Wrapf(err, "failed to initialize %s's KV store", name)
 	}
 
 	delegate := ring.BasicLifecyclerDelegate(ring.NewInstanceRegisterDelegate(ring.ACTIVE, ringNumTokens))
@@ -141,15 +155,14 @@ func newRing(config RingConfig, logger log.Logger, reg prometheus.Registerer) (*
 		return nil, err
 	}
 
-	const ringName = "overrides-exporter"
-	lifecycler, err := ring.NewBasicLifecycler(lifecyclerConfig, ringName, ringKey, kvStore, delegate, logger, reg)
+	lifecycler, err := ring.NewBasicLifecycler(lifecyclerConfig, name, name, kvStore, delegate, logger, reg)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to initialize overrides-exporter's lifecycler")
+		return nil, errors.Wrapf(err, "failed to initialize %s's lifecycler", name)
 	}
 
-	ringClient, err := ring.New(config.ToRingConfig(), ringName, ringKey, logger, reg)
+	ringClient, err := ring.New(config.ToRingConfig(), name, name, logger, reg)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to create a overrides-exporter ring client")
+		return nil, errors.Wrapf(err, "failed to create a %s ring client", name)
 	}
 
 	manager, err := services.NewManager(lifecycler, ringClient)
@@ -157,7 +170,8 @@ func newRing(config RingConfig, logger log.Logger, reg prometheus.Registerer) (*
 		return nil, errors.Wrap(err, "failed to create service manager")
 	}
 
-	r := &overridesExporterRing{
+	r := &LeaderRing{
+		name:              name,
 		config:            config,
 		client:            ringClient,
 		lifecycler:        lifecycler,
@@ -169,8 +183,8 @@ func newRing(config RingConfig, logger log.Logger, reg prometheus.Registerer) (*
 	return r, nil
 }
 
-// isLeader checks whether this instance is the leader replica that exports metrics for all tenants.
-func (r *overridesExporterRing) isLeader() (bool, error) {
+// IsLeader checks whether this instance is the leader replica.
+func (r *LeaderRing) IsLeader() (bool, error) {
 	// Get the leader from the ring and check whether it's this replica.
 	rl, err := ringLeader(r.client)
 	if err != nil {
@@ -180,6 +194,11 @@ func (r *overridesExporterRing) isLeader() (bool, error) {
 	return rl.Addr == r.lifecycler.GetInstanceAddr(), nil
 }
 
+// Leader returns the ring member that is the leader replica.
+func (r *LeaderRing) Leader() (*ring.InstanceDesc, error) {
+	return ringLeader(r.client)
+}
+
 // ringLeader returns the ring member that owns the special token.
 func ringLeader(r ring.ReadRing) (*ring.InstanceDesc, error) {
 	rs, err := r.Get(leaderToken, ringOp, nil, nil, nil)
@@ -193,48 +212,48 @@ func ringLeader(r ring.ReadRing) (*ring.InstanceDesc, error) {
 	return &rs.Instances[0], nil
 }
 
-func (r *overridesExporterRing) starting(ctx context.Context) error {
+func (r *LeaderRing) starting(ctx context.Context) error {
 	r.subserviceWatcher.WatchManager(r.subserviceManager)
 	if err := services.StartManagerAndAwaitHealthy(ctx, r.subserviceManager); err != nil {
-		return errors.Wrap(err, "unable to start overrides-exporter ring subservice manager")
+		return errors.Wrapf(err, "unable to start %s ring subservice manager", r.name)
 	}
 
-	level.Info(r.logger).Log("msg", "waiting until overrides-exporter is ACTIVE in the ring")
+	level.Info(r.logger).Log("msg", "waiting until instance is ACTIVE in the ring", "ring", r.name)
 	if err := ring.WaitInstanceState(ctx, r.client, r.lifecycler.GetInstanceID(), ring.ACTIVE); err != nil {
-		return errors.Wrap(err, "overrides-exporter failed to become ACTIVE in the ring")
+		return errors.Wrapf(err, "%s instance failed to become ACTIVE in the ring", r.name)
 	}
-	level.Info(r.logger).Log("msg", "overrides-exporter is ACTIVE in the ring")
+	level.Info(r.logger).Log("msg", "instance is ACTIVE in the ring", "ring", r.name)
 
-	// In the event of a cluster cold start or scale up of 2+ overrides-exporter
-	// instances at the same time, the leader token may hop from one instance to
-	// another, creating high series churn for the limit metrics. Waiting for a
-	// stable ring helps to counteract that.
+	// In the event of a cluster cold start or scale up of 2+ instances at the
+	// same time, the leader token may hop from one instance to another, e.g.
+	// creating high series churn for the limit metrics of the overrides-exporter.
+	// Waiting for a stable ring helps to counteract that.
 	if r.config.WaitStabilityMinDuration > 0 {
 		minWaiting := r.config.WaitStabilityMinDuration
 		maxWaiting := r.config.WaitStabilityMaxDuration
 
-		level.Info(r.logger).Log("msg", "waiting until overrides-exporter ring topology is stable", "min_waiting", minWaiting.String(), "max_waiting", maxWaiting.String())
+		level.Info(r.logger).Log("msg", "waiting until ring topology is stable", "ring", r.name, "min_waiting", minWaiting.String(), "max_waiting", maxWaiting.String())
 		if err := ring.WaitRingTokensStability(ctx, r.client, ringOp, minWaiting, maxWaiting); err != nil {
-			level.Warn(r.logger).Log("msg", "overrides-exporter ring topology is not stable after the max waiting time, proceeding anyway")
+			level.Warn(r.logger).Log("msg", "ring topology is not stable after the max waiting time, proceeding anyway", "ring", r.name)
 		} else {
-			level.Info(r.logger).Log("msg", "overrides-exporter ring topology is stable")
+			level.Info(r.logger).Log("msg", "ring topology is stable", "ring", r.name)
 		}
 	}
 	return nil
 }
 
-func (r *overridesExporterRing) running(ctx context.Context) error {
+func (r *LeaderRing) running(ctx context.Context) error {
 	select {
 	case <-ctx.Done():
 		return nil
 	case err := <-r.subserviceWatcher.Chan():
-		return errors.Wrap(err, "a subservice of overrides-exporter ring has failed")
+		return errors.Wrapf(err, "a subservice of %s ring has failed", r.name)
 	}
 }
 
-func (r *overridesExporterRing) stopping(_ error) error {
+func (r *LeaderRing) stopping(_ error) error {
 	return errors.Wrap(
 		services.StopManagerAndAwaitStopped(context.Background(), r.subserviceManager),
-		"failed to stop overrides-exporter's ring subservice manager",
+		fmt.Sprintf("failed to stop %s's ring subservice manager", r.name),
 	)
 }
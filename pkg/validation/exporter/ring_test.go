@@ -36,7 +36,7 @@ func TestOverridesExporter_emptyRing(t *testing.T) {
 	require.NoError(t, services.StartAndAwaitRunning(ctx, i1.client))
 	t.Cleanup(func() { require.NoError(t, services.StopAndAwaitTerminated(ctx, i1.client)) })
 
-	_, err = i1.isLeader()
+	_, err = i1.IsLeader()
 	require.ErrorIs(t, err, ring.ErrEmptyRing)
 }
 
@@ -85,9 +85,9 @@ func TestOverridesExporterRing_scaleDown(t *testing.T) {
 	})
 
 	// instance-1 should be the leader
-	i1IsLeader, err := i1.isLeader()
+	i1IsLeader, err := i1.IsLeader()
 	require.NoError(t, err)
-	i2IsLeader, err := i2.isLeader()
+	i2IsLeader, err := i2.IsLeader()
 	require.NoError(t, err)
 
 	require.True(t, i1IsLeader)
@@ -109,7 +109,7 @@ func TestOverridesExporterRing_scaleDown(t *testing.T) {
 		return nil
 	})
 
-	i2IsLeader, err = i2.isLeader()
+	i2IsLeader, err = i2.IsLeader()
 	require.NoError(t, err)
 	// Since the previous leader is still in the ring but in state ring.LEAVING,
 	// no other instance should be the leader now.
@@ -133,7 +133,7 @@ func TestOverridesExporterRing_scaleDown(t *testing.T) {
 	// Once the previous leader has been removed from the ring, instance-2 should
 	// become the new leader.
 	test.Poll(t, 5*time.Second, true, func() interface{} {
-		isLeader, _ := i2.isLeader()
+		isLeader, _ := i2.IsLeader()
 		return isLeader
 	})
 }
@@ -60,6 +60,14 @@ type Limits struct {
 	// can be calculated correctly.
 	IngestionTenantShardSize int `yaml:"ingestion_tenant_shard_size" json:"ingestion_tenant_shard_size"`
 
+	// Segment writer placement limits. The distributor uses them to determine
+	// how many segment writer shards a tenant and its datasets are spread across.
+	SegmentWriterTenantShards           int            `yaml:"segment_writer_tenant_shards" json:"segment_writer_tenant_shards" category:"experimental"`
+	SegmentWriterDatasetShards          int            `yaml:"segment_writer_dataset_shards" json:"segment_writer_dataset_shards" category:"experimental"`
+	SegmentWriterDatasetShardsMax       int            `yaml:"segment_writer_dataset_shards_max" json:"segment_writer_dataset_shards_max" category:"experimental"`
+	SegmentWriterDatasetShardRateBytes  int            `yaml:"segment_writer_dataset_shard_rate_bytes" json:"segment_writer_dataset_shard_rate_bytes" category:"experimental"`
+	SegmentWriterDatasetShardsOverrides map[string]int `yaml:"segment_writer_dataset_shards_overrides,omitempty" json:"segment_writer_dataset_shards_overrides,omitempty" category:"experimental" doc:"nocli|description=Fixed number of segment writer shards per dataset (service name). Datasets listed here are not subject to the adaptive shard count."`
+
 	// Ingester enforced limits.
 	MaxLocalSeriesPerTenant  int `yaml:"max_local_series_per_tenant" json:"max_local_series_per_tenant"`
 	MaxGlobalSeriesPerTenant int `yaml:"max_global_series_per_tenant" json:"max_global_series_per_tenant"`
@@ -70,6 +78,11 @@ type Limits struct {
 	MaxQueryParallelism        int            `yaml:"max_query_parallelism" json:"max_query_parallelism"`
 	QueryAnalysisEnabled       bool           `yaml:"query_analysis_enabled" json:"query_analysis_enabled"`
 	QueryAnalysisSeriesEnabled bool           `yaml:"query_analysis_series_enabled" json:"query_analysis_series_enabled"`
+	QueryCostEstimationEnabled bool           `yaml:"query_cost_estimation_enabled" json:"query_cost_estimation_enabled" category:"experimental"`
+	MaxQueryCostInFlight       int            `yaml:"max_query_cost_in_flight_bytes" json:"max_query_cost_in_flight_bytes" category:"experimental"`
+	MaxQueryBytesScanned       int            `yaml:"max_query_bytes_scanned" json:"max_query_bytes_scanned"`
+	MaxQuerySeries             int            `yaml:"max_query_series" json:"max_query_series"`
+	MaxQueryProfiles           int            `yaml:"max_query_profiles" json:"max_query_profiles"`
 
 	// Flame graph enforced limits.
 	MaxFlameGraphNodesDefault int `yaml:"max_flamegraph_nodes_default" json:"max_flamegraph_nodes_default"`
@@ -78,6 +91,14 @@ type Limits struct {
 	// Store-gateway.
 	StoreGatewayTenantShardSize int `yaml:"store_gateway_tenant_shard_size" json:"store_gateway_tenant_shard_size"`
 
+	// Ad hoc profiles.
+	AdHocProfilesMaxTotalSizeBytes int            `yaml:"adhoc_profiles_max_total_size_bytes" json:"adhoc_profiles_max_total_size_bytes"`
+	AdHocProfilesRetentionPeriod   model.Duration `yaml:"adhoc_profiles_retention_period" json:"adhoc_profiles_retention_period"`
+
+	// VCS integration.
+	VCSLocalRepositoriesDir           string         `yaml:"vcs_local_repositories_dir" json:"vcs_local_repositories_dir"`
+	VCSLocalRepositoriesFetchInterval model.Duration `yaml:"vcs_local_repositories_fetch_interval" json:"vcs_local_repositories_fetch_interval"`
+
 	// Query frontend.
 	QuerySplitDuration model.Duration `yaml:"split_queries_by_interval" json:"split_queries_by_interval"`
 
@@ -119,6 +140,11 @@ func (l *Limits) RegisterFlags(f *flag.FlagSet) {
 	f.Float64Var(&l.IngestionRateMB, "distributor.ingestion-rate-limit-mb", 4, "Per-tenant ingestion rate limit in sample size per second. Units in MB.")
 	f.Float64Var(&l.IngestionBurstSizeMB, "distributor.ingestion-burst-size-mb", 2, "Per-tenant allowed ingestion burst size (in sample size). Units in MB. The burst size refers to the per-distributor local rate limiter, and should be set at least to the maximum profile size expected in a single push request.")
 
+	f.IntVar(&l.SegmentWriterTenantShards, "segment-writer.tenant-shards", 0, "Number of segment writer shards the tenant data is distributed across. 0 to use all shards.")
+	f.IntVar(&l.SegmentWriterDatasetShards, "segment-writer.dataset-shards", 2, "Number of segment writer shards a tenant dataset is distributed across. If the adaptive shard count is enabled, this is the minimum number of shards.")
+	f.IntVar(&l.SegmentWriterDatasetShardsMax, "segment-writer.dataset-shards-max", 0, "Maximum number of segment writer shards a tenant dataset can be distributed across, depending on the observed ingestion rate. The adaptive shard count is enabled, if the value is greater than the dataset shards. 0 to disable.")
+	f.IntVar(&l.SegmentWriterDatasetShardRateBytes, "segment-writer.dataset-shard-rate-bytes", 1<<20, "Ingestion rate, in bytes per second, a single segment writer shard should receive for a tenant dataset. Used to determine the adaptive shard count.")
+
 	f.IntVar(&l.IngestionTenantShardSize, "distributor.ingestion-tenant-shard-size", 0, "The tenant's shard size used by shuffle-sharding. Must be set both on ingesters and distributors. 0 disables shuffle sharding.")
 
 	f.IntVar(&l.MaxLabelNameLength, "validation.max-length-label-name", 1024, "Maximum length accepted for label names.")
@@ -145,6 +171,11 @@ func (l *Limits) RegisterFlags(f *flag.FlagSet) {
 
 	f.BoolVar(&l.QueryAnalysisEnabled, "querier.query-analysis-enabled", true, "Whether query analysis is enabled in the query frontend. If disabled, the /AnalyzeQuery endpoint will return an empty response.")
 	f.BoolVar(&l.QueryAnalysisSeriesEnabled, "querier.query-analysis-series-enabled", false, "Whether the series portion of query analysis is enabled. If disabled, no series data (e.g., series count) will be calculated by the /AnalyzeQuery endpoint.")
+	f.BoolVar(&l.QueryCostEstimationEnabled, "querier.query-cost-estimation-enabled", false, "Whether the query frontend estimates the cost of queries using query analysis before scheduling them. The estimate is used by the query-scheduler to share queriers fairly between tenants. Requires query analysis to be enabled.")
+	f.IntVar(&l.MaxQueryCostInFlight, "query-scheduler.max-query-cost-in-flight-bytes", 0, "Maximum total estimated cost, in bytes, of the tenant queries handled by queriers at once. A query that exceeds the limit on its own is handled when no other tenant queries are in flight. 0 to disable.")
+	f.IntVar(&l.MaxQueryBytesScanned, "querier.max-query-bytes-scanned", 0, "Maximum number of bytes a single query may scan. The query frontend checks the whole query against the limit using query analysis, which requires the series portion of query analysis to be enabled (the v2 read path uses the size of the datasets in the query plan). Ingesters and store-gateways enforce the limit on their own part of each query split, therefore it never applies to the query as a whole there. 0 to disable.")
+	f.IntVar(&l.MaxQuerySeries, "querier.max-query-series", 0, "Maximum number of series a single query may select. The query frontend checks the whole query against the limit using query analysis, which requires the series portion of query analysis to be enabled. Ingesters and store-gateways enforce the limit on their own part of each query split, therefore it never applies to the query as a whole there. 0 to disable.")
+	f.IntVar(&l.MaxQueryProfiles, "querier.max-query-profiles", 0, "Maximum number of profiles a single query may select. The query frontend checks the whole query against the limit using query analysis, which requires the series portion of query analysis to be enabled. Ingesters and store-gateways enforce the limit on their own part of each query split, therefore it never applies to the query as a whole there. 0 to disable.")
 
 	f.IntVar(&l.MaxProfileSizeBytes, "validation.max-profile-size-bytes", 4*1024*1024, "Maximum size of a profile in bytes. This is based off the uncompressed size. 0 to disable.")
 	f.IntVar(&l.MaxProfileStacktraceSamples, "validation.max-profile-stacktrace-samples", 16000, "Maximum number of samples in a profile. 0 to disable.")
@@ -155,6 +186,12 @@ func (l *Limits) RegisterFlags(f *flag.FlagSet) {
 	f.IntVar(&l.MaxFlameGraphNodesDefault, "querier.max-flamegraph-nodes-default", 8<<10, "Maximum number of flame graph nodes by default. 0 to disable.")
 	f.IntVar(&l.MaxFlameGraphNodesMax, "querier.max-flamegraph-nodes-max", 0, "Maximum number of flame graph nodes allowed. 0 to disable.")
 
+	f.IntVar(&l.AdHocProfilesMaxTotalSizeBytes, "adhoc-profiles.max-total-size-bytes", 0, "Maximum total size of the ad hoc profiles stored for a tenant in bytes. Uploads exceeding the limit are rejected. 0 to disable.")
+	f.Var(&l.AdHocProfilesRetentionPeriod, "adhoc-profiles.retention-period", "Delete ad hoc profiles uploaded longer ago than the specified retention period. 0 to disable.")
+
+	f.StringVar(&l.VCSLocalRepositoriesDir, "vcs.local-repositories-dir", "", "Directory with local git repositories, laid out as <dir>/<host>/<owner>/<repo>. If set, source files and commits are served from the local repositories instead of the VCS provider API, and no OAuth token is required. Mirrors created with 'git clone --mirror' are recommended.")
+	f.Var(&l.VCSLocalRepositoriesFetchInterval, "vcs.local-repositories-fetch-interval", "Minimum interval between fetches of a local git repository from its remotes. A fetch is triggered in the background when the repository is accessed. 0 to disable.")
+
 	f.Var(&l.DistributorAggregationWindow, "distributor.aggregation-window", "Duration of the distributor aggregation window. Requires aggregation period to be specified. 0 to disable.")
 	f.Var(&l.DistributorAggregationPeriod, "distributor.aggregation-period", "Duration of the distributor aggregation period. Requires aggregation window to be specified. 0 to disable.")
 
@@ -269,6 +306,34 @@ func (o *Overrides) IngestionTenantShardSize(tenantID string) int {
 	return o.getOverridesForTenant(tenantID).IngestionTenantShardSize
 }
 
+// SegmentWriterTenantShards returns the number of segment writer shards
+// available to the tenant. 0 means all shards.
+func (o *Overrides) SegmentWriterTenantShards(tenantID string) int {
+	return o.getOverridesForTenant(tenantID).SegmentWriterTenantShards
+}
+
+// SegmentWriterDatasetShards returns the number of segment writer shards
+// for the tenant dataset, and whether the value is fixed for the dataset.
+func (o *Overrides) SegmentWriterDatasetShards(tenantID, dataset string) (shards int, fixed bool) {
+	l := o.getOverridesForTenant(tenantID)
+	if n, ok := l.SegmentWriterDatasetShardsOverrides[dataset]; ok && n > 0 {
+		return n, true
+	}
+	return l.SegmentWriterDatasetShards, false
+}
+
+// SegmentWriterDatasetShardsMax returns the maximum number of segment
+// writer shards a tenant dataset can be distributed across.
+func (o *Overrides) SegmentWriterDatasetShardsMax(tenantID string) int {
+	return o.getOverridesForTenant(tenantID).SegmentWriterDatasetShardsMax
+}
+
+// SegmentWriterDatasetShardRateBytes returns the target ingestion rate
+// of a single segment writer shard, in bytes per second.
+func (o *Overrides) SegmentWriterDatasetShardRateBytes(tenantID string) int {
+	return o.getOverridesForTenant(tenantID).SegmentWriterDatasetShardRateBytes
+}
+
 // MaxLabelNameLength returns maximum length a label name can be.
 func (o *Overrides) MaxLabelNameLength(tenantID string) int {
 	return o.getOverridesForTenant(tenantID).MaxLabelNameLength
@@ -365,6 +430,26 @@ func (o *Overrides) MaxFlameGraphNodesMax(tenantID string) int {
 	return o.getOverridesForTenant(tenantID).MaxFlameGraphNodesMax
 }
 
+// AdHocProfilesMaxTotalSizeBytes returns the maximum total size of the ad hoc profiles stored for a tenant.
+func (o *Overrides) AdHocProfilesMaxTotalSizeBytes(tenantID string) int {
+	return o.getOverridesForTenant(tenantID).AdHocProfilesMaxTotalSizeBytes
+}
+
+// AdHocProfilesRetentionPeriod returns the period after which ad hoc profiles are deleted.
+func (o *Overrides) AdHocProfilesRetentionPeriod(tenantID string) time.Duration {
+	return time.Duration(o.getOverridesForTenant(tenantID).AdHocProfilesRetentionPeriod)
+}
+
+// VCSLocalRepositoriesDir returns the directory with the local git repositories of the tenant.
+func (o *Overrides) VCSLocalRepositoriesDir(tenantID string) string {
+	return o.getOverridesForTenant(tenantID).VCSLocalRepositoriesDir
+}
+
+// VCSLocalRepositoriesFetchInterval returns the minimum interval between fetches of a local git repository.
+func (o *Overrides) VCSLocalRepositoriesFetchInterval(tenantID string) time.Duration {
+	return time.Duration(o.getOverridesForTenant(tenantID).VCSLocalRepositoriesFetchInterval)
+}
+
 // StoreGatewayTenantShardSize returns the store-gateway shard size for a given user.
 func (o *Overrides) StoreGatewayTenantShardSize(userID string) int {
 	return o.getOverridesForTenant(userID).StoreGatewayTenantShardSize
@@ -460,6 +545,32 @@ func (o *Overrides) QueryAnalysisSeriesEnabled(tenantID string) bool {
 	return o.getOverridesForTenant(tenantID).QueryAnalysisSeriesEnabled
 }
 
+// QueryCostEstimationEnabled can be used to enable query cost estimation in the query frontend.
+func (o *Overrides) QueryCostEstimationEnabled(tenantID string) bool {
+	return o.getOverridesForTenant(tenantID).QueryCostEstimationEnabled
+}
+
+// MaxQueryCostInFlight returns the limit of the total estimated cost of the tenant queries
+// handled by queriers at once. 0 means no limit.
+func (o *Overrides) MaxQueryCostInFlight(tenantID string) int {
+	return o.getOverridesForTenant(tenantID).MaxQueryCostInFlight
+}
+
+// MaxQueryBytesScanned returns the limit of the number of bytes a single query may scan.
+func (o *Overrides) MaxQueryBytesScanned(tenantID string) int {
+	return o.getOverridesForTenant(tenantID).MaxQueryBytesScanned
+}
+
+// MaxQuerySeries returns the limit of the number of series a single query may select.
+func (o *Overrides) MaxQuerySeries(tenantID string) int {
+	return o.getOverridesForTenant(tenantID).MaxQuerySeries
+}
+
+// MaxQueryProfiles returns the limit of the number of profiles a single query may select.
+func (o *Overrides) MaxQueryProfiles(tenantID string) int {
+	return o.getOverridesForTenant(tenantID).MaxQueryProfiles
+}
+
 func (o *Overrides) WritePathOverrides(tenantID string) writepath.Config {
 	return o.getOverridesForTenant(tenantID).WritePathOverrides
 }
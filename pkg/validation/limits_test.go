@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"reflect"
 	"testing"
+	"time"
 
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
@@ -162,3 +164,34 @@ func TestOverwriteMarshalingStringMapYAML(t *testing.T) {
 	require.Nil(t, yaml.Unmarshal(out, &back))
 	require.Equal(t, m, back)
 }
+
+func TestMergeLimits(t *testing.T) {
+	base := MockDefaultLimits()
+	base.IngestionRateMB = 10
+	base.MaxQueryLength = model.Duration(time.Hour)
+
+	merged, err := MergeLimits(base, []byte(`
+ingestion_burst_size_mb: 20
+max_query_length: 2h
+ingestion_relabeling_default_rules_position: last
+`))
+	require.NoError(t, err)
+	require.NoError(t, merged.Validate())
+	// The fields not set are preserved.
+	assert.Equal(t, float64(10), merged.IngestionRateMB)
+	assert.Equal(t, float64(20), merged.IngestionBurstSizeMB)
+	assert.Equal(t, model.Duration(2*time.Hour), merged.MaxQueryLength)
+	assert.Equal(t, RelabelRulePositionLast, merged.IngestionRelabelingDefaultRulesPosition)
+	// The base limits are not modified.
+	assert.Equal(t, model.Duration(time.Hour), base.MaxQueryLength)
+
+	merged, err = MergeLimits(base, nil)
+	require.NoError(t, err)
+	assert.Equal(t, base, merged)
+
+	_, err = MergeLimits(base, []byte(`unknown_limit: 1`))
+	assert.Error(t, err)
+
+	_, err = MergeLimits(base, []byte(`ingestion_rate_mb: fast`))
+	assert.Error(t, err)
+}
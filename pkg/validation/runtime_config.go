@@ -1,6 +1,8 @@
 package validation
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
 
@@ -42,3 +44,26 @@ func LoadRuntimeConfig(r io.Reader) (*RuntimeConfigValues, error) {
 	}
 	return overrides, nil
 }
+
+// MergeLimits returns a copy of the limits with the fields set in the YAML
+// document overridden. Unlike the limits loaded from the runtime config,
+// the fields not set are not reset to the defaults. Unknown fields are
+// rejected.
+func MergeLimits(base *Limits, overrides []byte) (*Limits, error) {
+	// The type indirection prevents UnmarshalYAML from being called.
+	type plain Limits
+	b, err := yaml.Marshal(base)
+	if err != nil {
+		return nil, fmt.Errorf("cloning limits (marshaling): %w", err)
+	}
+	merged := new(Limits)
+	if err = yaml.Unmarshal(b, (*plain)(merged)); err != nil {
+		return nil, fmt.Errorf("cloning limits (unmarshaling): %w", err)
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(overrides))
+	decoder.KnownFields(true)
+	if err = decoder.Decode((*plain)(merged)); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return merged, nil
+}
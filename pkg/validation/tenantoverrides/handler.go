@@ -0,0 +1,200 @@
+package tenantoverrides
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	stdhttputil "net/http/httputil"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	dskittenant "github.com/grafana/dskit/tenant"
+
+	"github.com/grafana/pyroscope/pkg/auth"
+	"github.com/grafana/pyroscope/pkg/util"
+	httputil "github.com/grafana/pyroscope/pkg/util/http"
+	"github.com/grafana/pyroscope/pkg/validation"
+)
+
+const (
+	// maxRequestBodySize limits the size of the overrides document.
+	maxRequestBodySize = 1 << 20
+
+	// forwardedHeader marks the update requests forwarded to the leader,
+	// so that they are not forwarded again if the leader has changed.
+	forwardedHeader = "X-Pyroscope-Tenant-Overrides-Forwarded"
+)
+
+type tenantOverridesResponse struct {
+	Tenant    string                 `json:"tenant"`
+	Version   int64                  `json:"version"`
+	Overrides map[string]interface{} `json:"overrides"`
+	// Limits are the effective limits of the tenant.
+	Limits *validation.Limits `json:"limits,omitempty"`
+}
+
+// OverridesHandler lists the overrides of all the tenants
+// the principal has access to.
+func (m *Manager) OverridesHandler(w http.ResponseWriter, r *http.Request) {
+	p, hasPrincipal := auth.PrincipalFromContext(r.Context())
+	all := m.List()
+	resp := make([]tenantOverridesResponse, 0, len(all))
+	for tenantID, o := range all {
+		if hasPrincipal && !p.CanAccess(tenantID) {
+			continue
+		}
+		resp = append(resp, tenantOverridesResponse{
+			Tenant:    tenantID,
+			Version:   o.Version,
+			Overrides: o.Overrides,
+		})
+	}
+	util.WriteJSONResponse(w, resp)
+}
+
+// TenantOverridesHandler gets (GET), sets (PUT), or deletes (DELETE)
+// the overrides of the tenant. The overrides are specified as a YAML or
+// JSON document, in the same format as in the runtime config file.
+//
+// The version of the overrides is returned in the ETag header, and can
+// be specified in the If-Match header of PUT and DELETE requests: the
+// request fails with 412 Precondition Failed if the overrides have been
+// modified in the meantime.
+//
+// PUT and DELETE requests are forwarded to the leader replica.
+func (m *Manager) TenantOverridesHandler(w http.ResponseWriter, r *http.Request) {
+	tenantID := mux.Vars(r)["tenant"]
+	if err := dskittenant.ValidTenantID(tenantID); err != nil {
+		httputil.ErrorWithStatus(w, err, http.StatusBadRequest)
+		return
+	}
+	if p, ok := auth.PrincipalFromContext(r.Context()); ok && !p.CanAccess(tenantID) {
+		httputil.ErrorWithStatus(w, fmt.Errorf("%s is not allowed to access tenant %s", p.Name, tenantID), http.StatusForbidden)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		m.getTenantOverrides(w, tenantID)
+	case http.MethodPut:
+		if !m.forwardToLeader(w, r) {
+			m.setTenantOverrides(w, r, tenantID)
+		}
+	case http.MethodDelete:
+		if !m.forwardToLeader(w, r) {
+			m.deleteTenantOverrides(w, r, tenantID)
+		}
+	default:
+		w.Header().Set("Allow", "GET, PUT, DELETE")
+		httputil.ErrorWithStatus(w, fmt.Errorf("method %s not allowed", r.Method), http.StatusMethodNotAllowed)
+	}
+}
+
+// forwardToLeader forwards the request to the leader replica, unless the
+// instance is the leader. It reports whether the request has been handled.
+func (m *Manager) forwardToLeader(w http.ResponseWriter, r *http.Request) bool {
+	leader, err := m.leader()
+	if err != nil {
+		httputil.ErrorWithStatus(w, err, http.StatusServiceUnavailable)
+		return true
+	}
+	if leader == "" {
+		return false
+	}
+	if r.Header.Get(forwardedHeader) != "" {
+		httputil.ErrorWithStatus(w, fmt.Errorf("%w: the leader is %s", ErrNotLeader, leader), http.StatusServiceUnavailable)
+		return true
+	}
+	r.Header.Set(forwardedHeader, "true")
+	scheme := m.cfg.LeaderScheme
+	if scheme == "" {
+		scheme = "http"
+	}
+	proxy := stdhttputil.NewSingleHostReverseProxy(&url.URL{Scheme: scheme, Host: leader})
+	proxy.ErrorHandler = func(w http.ResponseWriter, _ *http.Request, err error) {
+		httputil.ErrorWithStatus(w, fmt.Errorf("forwarding to the leader %s: %w", leader, err), http.StatusBadGateway)
+	}
+	proxy.ServeHTTP(w, r)
+	return true
+}
+
+func (m *Manager) getTenantOverrides(w http.ResponseWriter, tenantID string) {
+	o, err := m.Get(tenantID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	m.writeTenantOverrides(w, tenantID, o)
+}
+
+func (m *Manager) setTenantOverrides(w http.ResponseWriter, r *http.Request, tenantID string) {
+	version, err := ifMatch(r)
+	if err != nil {
+		httputil.ErrorWithStatus(w, err, http.StatusBadRequest)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBodySize))
+	if err != nil {
+		httputil.ErrorWithStatus(w, err, http.StatusBadRequest)
+		return
+	}
+	o, err := m.Set(r.Context(), tenantID, body, version)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	m.writeTenantOverrides(w, tenantID, o)
+}
+
+func (m *Manager) deleteTenantOverrides(w http.ResponseWriter, r *http.Request, tenantID string) {
+	version, err := ifMatch(r)
+	if err != nil {
+		httputil.ErrorWithStatus(w, err, http.StatusBadRequest)
+		return
+	}
+	if err = m.Delete(r.Context(), tenantID, version); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *Manager) writeTenantOverrides(w http.ResponseWriter, tenantID string, o *TenantOverrides) {
+	w.Header().Set("ETag", strconv.Quote(strconv.FormatInt(o.Version, 10)))
+	util.WriteJSONResponse(w, tenantOverridesResponse{
+		Tenant:    tenantID,
+		Version:   o.Version,
+		Overrides: o.Overrides,
+		Limits:    m.TenantLimits(tenantID),
+	})
+}
+
+// ifMatch returns the version specified in the If-Match header, if any.
+func ifMatch(r *http.Request) (*int64, error) {
+	v := r.Header.Get("If-Match")
+	if v == "" {
+		return nil, nil
+	}
+	version, err := strconv.ParseInt(strings.Trim(v, `"`), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid If-Match header %q", v)
+	}
+	return &version, nil
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		httputil.ErrorWithStatus(w, err, http.StatusNotFound)
+	case errors.Is(err, ErrVersionMismatch):
+		httputil.ErrorWithStatus(w, err, http.StatusPreconditionFailed)
+	case errors.Is(err, ErrInvalidOverrides):
+		httputil.ErrorWithStatus(w, err, http.StatusBadRequest)
+	case errors.Is(err, ErrNotLeader):
+		httputil.ErrorWithStatus(w, err, http.StatusServiceUnavailable)
+	default:
+		httputil.Error(w, err)
+	}
+}
@@ -0,0 +1,375 @@
+// Package tenantoverrides implements per-tenant overrides of the limits
+// that are managed at runtime through the admin API and persisted in
+// the object storage.
+//
+// The limits of a tenant are resolved in the following order, each
+// layer overriding the fields set in the previous one:
+//
+//  1. The default limits, configured with the CLI flags or the config file.
+//  2. The overrides of the tenant in the runtime config file.
+//  3. The overrides of the tenant set through the API.
+//
+// Every component polls the object storage for changes, therefore an
+// update is applied by all the components within the poll interval.
+//
+// The overrides of each tenant are stored in a separate object. The object
+// storage does not support conditional writes, therefore the updates are
+// applied by a single replica, the leader of the tenant overrides ring:
+// the other replicas forward the update requests to the leader. Deleted
+// overrides are kept as tombstones, so that the versions never repeat.
+package tenantoverrides
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/services"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/thanos-io/objstore"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/pyroscope/pkg/validation"
+	"github.com/grafana/pyroscope/pkg/validation/exporter"
+)
+
+var (
+	ErrNotFound         = errors.New("tenant overrides not found")
+	ErrVersionMismatch  = errors.New("tenant overrides version mismatch")
+	ErrInvalidOverrides = errors.New("invalid tenant overrides")
+	ErrNotLeader        = errors.New("tenant overrides can only be updated by the leader replica")
+)
+
+// ringName is the name of the ring used to elect the replica that
+// updates the overrides, and the key it is stored under in the KVStore.
+const ringName = "tenant-overrides"
+
+type Config struct {
+	Enabled      bool                `yaml:"enabled"`
+	PollInterval time.Duration       `yaml:"poll_interval"`
+	Ring         exporter.RingConfig `yaml:"ring"`
+
+	// LeaderScheme is the URL scheme of the update requests forwarded
+	// to the leader: https, if the HTTP server uses TLS. Defaults to http.
+	LeaderScheme string `yaml:"-"`
+}
+
+func (cfg *Config) RegisterFlags(f *flag.FlagSet, logger log.Logger) {
+	f.BoolVar(&cfg.Enabled, "tenant-overrides.enabled", false, "Enables the admin API to manage per-tenant overrides at runtime. The overrides are persisted in the object storage and take precedence over the runtime config file.")
+	f.DurationVar(&cfg.PollInterval, "tenant-overrides.poll-interval", 10*time.Second, "How often the tenant overrides are reloaded from the object storage. This is the maximum delay for an update to be applied by all components.")
+	cfg.Ring.RegisterFlagsWithPrefix("tenant-overrides.ring.", "tenant overrides instances", f, logger)
+}
+
+func (cfg *Config) Validate() error {
+	if cfg.Enabled && cfg.PollInterval <= 0 {
+		return errors.New("tenant overrides poll interval must be greater than 0")
+	}
+	return cfg.Ring.Validate()
+}
+
+// Manager keeps track of the tenant overrides stored in the bucket,
+// and merges them with the limits from the runtime config.
+// Manager implements validation.TenantLimits.
+type Manager struct {
+	services.Service
+
+	cfg      Config
+	logger   log.Logger
+	bucket   objstore.Bucket
+	defaults validation.Limits
+	// base are the limits from the runtime config, if any.
+	base validation.TenantLimits
+
+	// ring is used to elect the replica that updates the overrides.
+	// If nil, the instance is considered the leader.
+	ring *exporter.LeaderRing
+	// updateMu serializes the updates applied by the leader.
+	updateMu sync.Mutex
+
+	mu      sync.RWMutex
+	tenants map[string]*TenantOverrides
+	merged  map[string]mergedLimits
+}
+
+// mergedLimits caches the result of merging the overrides of the tenant
+// with the given base limits.
+type mergedLimits struct {
+	base    *validation.Limits
+	version int64
+	limits  *validation.Limits
+}
+
+func NewManager(
+	cfg Config,
+	defaults validation.Limits,
+	base validation.TenantLimits,
+	bucket objstore.Bucket,
+	logger log.Logger,
+	reg prometheus.Registerer,
+) (*Manager, error) {
+	m := &Manager{
+		cfg:      cfg,
+		logger:   logger,
+		bucket:   bucket,
+		defaults: defaults,
+		base:     base,
+		tenants:  make(map[string]*TenantOverrides),
+		merged:   make(map[string]mergedLimits),
+	}
+	var err error
+	if m.ring, err = exporter.NewLeaderRing(ringName, cfg.Ring, logger, reg); err != nil {
+		return nil, fmt.Errorf("failed to create tenant overrides ring: %w", err)
+	}
+	m.Service = services.NewTimerService(cfg.PollInterval, m.starting, m.iteration, m.stopping)
+	return m, nil
+}
+
+func (m *Manager) starting(ctx context.Context) error {
+	if err := services.StartAndAwaitRunning(ctx, m.ring); err != nil {
+		return err
+	}
+	return m.reload(ctx)
+}
+
+func (m *Manager) stopping(_ error) error {
+	return services.StopAndAwaitTerminated(context.Background(), m.ring)
+}
+
+func (m *Manager) iteration(ctx context.Context) error {
+	if err := m.reload(ctx); err != nil {
+		level.Warn(m.logger).Log("msg", "failed to reload tenant overrides", "err", err)
+	}
+	return nil
+}
+
+func (m *Manager) reload(ctx context.Context) error {
+	tenants, err := readAll(ctx, m.bucket)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tenants = tenants
+	for tenantID := range m.merged {
+		if _, ok := tenants[tenantID]; !ok {
+			delete(m.merged, tenantID)
+		}
+	}
+	return nil
+}
+
+// leader returns the address of the leader replica, or an empty
+// string, if the instance is the leader.
+func (m *Manager) leader() (string, error) {
+	if m.ring == nil {
+		return "", nil
+	}
+	isLeader, err := m.ring.IsLeader()
+	if err != nil || isLeader {
+		return "", err
+	}
+	leader, err := m.ring.Leader()
+	if err != nil {
+		return "", err
+	}
+	return leader.Addr, nil
+}
+
+// TenantLimits returns the limits of the tenant, or nil,
+// if there are no tenant-specific limits.
+func (m *Manager) TenantLimits(tenantID string) *validation.Limits {
+	var base *validation.Limits
+	if m.base != nil {
+		base = m.base.TenantLimits(tenantID)
+	}
+	m.mu.RLock()
+	overrides, ok := m.tenants[tenantID]
+	cached := m.merged[tenantID]
+	m.mu.RUnlock()
+	if !ok {
+		return base
+	}
+	if cached.limits != nil && cached.base == base && cached.version == overrides.Version {
+		return cached.limits
+	}
+	limits, err := m.merge(base, overrides)
+	if err != nil {
+		// The overrides might be incompatible with the runtime config
+		// updated after the overrides have been validated.
+		level.Warn(m.logger).Log("msg", "ignoring invalid tenant overrides", "tenant", tenantID, "version", overrides.Version, "err", err)
+		limits = base
+	}
+	m.mu.Lock()
+	m.merged[tenantID] = mergedLimits{base: base, version: overrides.Version, limits: limits}
+	m.mu.Unlock()
+	return limits
+}
+
+// AllByTenantID returns the limits of all the tenants with
+// tenant-specific limits.
+func (m *Manager) AllByTenantID() map[string]*validation.Limits {
+	all := make(map[string]*validation.Limits)
+	if m.base != nil {
+		for tenantID, limits := range m.base.AllByTenantID() {
+			all[tenantID] = limits
+		}
+	}
+	m.mu.RLock()
+	tenants := make([]string, 0, len(m.tenants))
+	for tenantID := range m.tenants {
+		tenants = append(tenants, tenantID)
+	}
+	m.mu.RUnlock()
+	for _, tenantID := range tenants {
+		all[tenantID] = m.TenantLimits(tenantID)
+	}
+	return all
+}
+
+func (m *Manager) merge(base *validation.Limits, overrides *TenantOverrides) (*validation.Limits, error) {
+	b, err := overrides.yaml()
+	if err != nil {
+		return nil, err
+	}
+	return m.mergeYAML(base, b)
+}
+
+func (m *Manager) mergeYAML(base *validation.Limits, overrides []byte) (*validation.Limits, error) {
+	if base == nil {
+		base = &m.defaults
+	}
+	limits, err := validation.MergeLimits(base, overrides)
+	if err != nil {
+		return nil, err
+	}
+	if err = limits.Validate(); err != nil {
+		return nil, err
+	}
+	return limits, nil
+}
+
+// Get returns the overrides of the tenant.
+func (m *Manager) Get(tenantID string) (*TenantOverrides, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	overrides, ok := m.tenants[tenantID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return overrides, nil
+}
+
+// List returns the overrides of all the tenants.
+func (m *Manager) List() map[string]*TenantOverrides {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	tenants := make(map[string]*TenantOverrides, len(m.tenants))
+	for tenantID, overrides := range m.tenants {
+		tenants[tenantID] = overrides
+	}
+	return tenants
+}
+
+// Set replaces the overrides of the tenant with the given YAML document.
+// If the expected version is not nil, the overrides are only updated if
+// the current version matches it: version 0 means that the tenant has
+// no overrides, either never set or deleted.
+func (m *Manager) Set(ctx context.Context, tenantID string, overrides []byte, expectedVersion *int64) (*TenantOverrides, error) {
+	var values map[string]interface{}
+	if err := yaml.Unmarshal(overrides, &values); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidOverrides, err)
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("%w: no limits specified", ErrInvalidOverrides)
+	}
+	var base *validation.Limits
+	if m.base != nil {
+		base = m.base.TenantLimits(tenantID)
+	}
+	if _, err := m.mergeYAML(base, overrides); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidOverrides, err)
+	}
+	var updated *TenantOverrides
+	err := m.update(ctx, tenantID, expectedVersion, func(current *TenantOverrides) error {
+		updated = &TenantOverrides{Overrides: values, Version: 1}
+		if current != nil {
+			updated.Version = current.Version + 1
+		}
+		return write(ctx, m.bucket, tenantID, updated)
+	})
+	if err != nil {
+		return nil, err
+	}
+	m.setTenant(tenantID, updated)
+	return updated, nil
+}
+
+// Delete removes the overrides of the tenant.
+func (m *Manager) Delete(ctx context.Context, tenantID string, expectedVersion *int64) error {
+	err := m.update(ctx, tenantID, expectedVersion, func(current *TenantOverrides) error {
+		if current == nil || current.Deleted {
+			return ErrNotFound
+		}
+		return write(ctx, m.bucket, tenantID, &TenantOverrides{
+			Version: current.Version + 1,
+			Deleted: true,
+		})
+	})
+	if err != nil {
+		return err
+	}
+	m.setTenant(tenantID, nil)
+	return nil
+}
+
+// update applies the change to the latest version of the tenant overrides
+// in the bucket, which might be a tombstone. Only the leader replica updates
+// the overrides, and the updates are serialized, therefore the version check
+// and the write can't interleave with another update.
+func (m *Manager) update(ctx context.Context, tenantID string, expectedVersion *int64, fn func(*TenantOverrides) error) error {
+	leader, err := m.leader()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrNotLeader, err)
+	}
+	if leader != "" {
+		return fmt.Errorf("%w: the leader is %s", ErrNotLeader, leader)
+	}
+	m.updateMu.Lock()
+	defer m.updateMu.Unlock()
+	current, err := read(ctx, m.bucket, tenantID)
+	if err != nil {
+		return err
+	}
+	var version int64
+	if current != nil && !current.Deleted {
+		version = current.Version
+	}
+	if expectedVersion != nil && *expectedVersion != version {
+		return fmt.Errorf("%w: expected %d, current %d", ErrVersionMismatch, *expectedVersion, version)
+	}
+	return fn(current)
+}
+
+// setTenant updates the overrides of the tenant in memory,
+// without waiting for the next reload.
+func (m *Manager) setTenant(tenantID string, overrides *TenantOverrides) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// The map is shared with List callers.
+	tenants := make(map[string]*TenantOverrides, len(m.tenants)+1)
+	for k, v := range m.tenants {
+		tenants[k] = v
+	}
+	if overrides != nil {
+		tenants[tenantID] = overrides
+	} else {
+		delete(tenants, tenantID)
+		delete(m.merged, tenantID)
+	}
+	m.tenants = tenants
+}
@@ -0,0 +1,298 @@
+package tenantoverrides
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/gorilla/mux"
+	"github.com/grafana/dskit/kv"
+	"github.com/grafana/dskit/kv/consul"
+	"github.com/grafana/dskit/ring"
+	"github.com/grafana/dskit/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/pyroscope/pkg/validation"
+)
+
+type staticTenantLimits map[string]*validation.Limits
+
+func (l staticTenantLimits) TenantLimits(tenantID string) *validation.Limits { return l[tenantID] }
+
+func (l staticTenantLimits) AllByTenantID() map[string]*validation.Limits { return l }
+
+func newTestRingStore(t *testing.T) kv.Client {
+	ringStore, closer := consul.NewInMemoryClient(ring.GetCodec(), log.NewNopLogger(), nil)
+	t.Cleanup(func() { assert.NoError(t, closer.Close()) })
+	return ringStore
+}
+
+func newTestManager(t *testing.T, bucket objstore.Bucket, base validation.TenantLimits) *Manager {
+	t.Helper()
+	return newTestReplica(t, bucket, base, newTestRingStore(t), "instance", 0)
+}
+
+func newTestReplica(t *testing.T, bucket objstore.Bucket, base validation.TenantLimits, ringStore kv.Client, instanceID string, port int) *Manager {
+	t.Helper()
+	cfg := Config{Enabled: true, PollInterval: time.Hour}
+	cfg.Ring.Ring.KVStore.Mock = ringStore
+	cfg.Ring.Ring.HeartbeatPeriod = time.Second
+	cfg.Ring.Ring.HeartbeatTimeout = time.Minute
+	cfg.Ring.Ring.InstanceID = instanceID
+	cfg.Ring.Ring.InstanceAddr = "127.0.0.1"
+	cfg.Ring.Ring.InstancePort = port
+	m, err := NewManager(cfg, *validation.MockDefaultLimits(), base, bucket, log.NewNopLogger(), nil)
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), m))
+	t.Cleanup(func() {
+		require.NoError(t, services.StopAndAwaitTerminated(context.Background(), m))
+	})
+	return m
+}
+
+func Test_Manager(t *testing.T) {
+	ctx := context.Background()
+	bucket := objstore.NewInMemBucket()
+
+	fileLimits := validation.MockDefaultLimits()
+	fileLimits.IngestionRateMB = 10
+	fileLimits.IngestionBurstSizeMB = 10
+	m := newTestManager(t, bucket, staticTenantLimits{"a": fileLimits})
+
+	assert.Same(t, fileLimits, m.TenantLimits("a"))
+	assert.Nil(t, m.TenantLimits("b"))
+
+	// The overrides take precedence over the runtime config,
+	// and the fields not overridden are preserved.
+	o, err := m.Set(ctx, "a", []byte(`ingestion_rate_mb: 20`), nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), o.Version)
+	limits := m.TenantLimits("a")
+	assert.Equal(t, float64(20), limits.IngestionRateMB)
+	assert.Equal(t, float64(10), limits.IngestionBurstSizeMB)
+	assert.Same(t, limits, m.TenantLimits("a"))
+
+	// Tenants without runtime config are based on the defaults.
+	_, err = m.Set(ctx, "b", []byte(`{"max_query_length": "1h"}`), nil)
+	require.NoError(t, err)
+	limits = m.TenantLimits("b")
+	assert.Equal(t, time.Hour, time.Duration(limits.MaxQueryLength))
+	assert.Equal(t, validation.MockDefaultLimits().IngestionRateMB, limits.IngestionRateMB)
+	assert.Len(t, m.AllByTenantID(), 2)
+
+	// Optimistic concurrency control.
+	_, err = m.Set(ctx, "a", []byte(`ingestion_rate_mb: 30`), ptr(int64(0)))
+	assert.ErrorIs(t, err, ErrVersionMismatch)
+	o, err = m.Set(ctx, "a", []byte(`ingestion_rate_mb: 30`), ptr(int64(1)))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), o.Version)
+	assert.Equal(t, float64(30), m.TenantLimits("a").IngestionRateMB)
+
+	// Invalid overrides are rejected.
+	for _, body := range []string{
+		`unknown_limit: 1`,
+		`ingestion_rate_mb: fast`,
+		`ingestion_relabeling_default_rules_position: middle`,
+		``,
+	} {
+		_, err = m.Set(ctx, "a", []byte(body), nil)
+		assert.ErrorIs(t, err, ErrInvalidOverrides, body)
+	}
+
+	// Other replicas pick up the changes from the bucket.
+	replica := newTestManager(t, bucket, nil)
+	assert.Equal(t, float64(30), replica.TenantLimits("a").IngestionRateMB)
+
+	assert.ErrorIs(t, m.Delete(ctx, "a", ptr(int64(1))), ErrVersionMismatch)
+	require.NoError(t, m.Delete(ctx, "a", nil))
+	assert.ErrorIs(t, m.Delete(ctx, "a", nil), ErrNotFound)
+	assert.Same(t, fileLimits, m.TenantLimits("a"))
+
+	require.NoError(t, replica.reload(ctx))
+	assert.Nil(t, replica.TenantLimits("a"))
+	_, err = replica.Get("a")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func Test_Manager_DeleteAndRecreate(t *testing.T) {
+	ctx := context.Background()
+	bucket := objstore.NewInMemBucket()
+	m := newTestManager(t, bucket, nil)
+	replica := newTestManager(t, bucket, nil)
+
+	_, err := m.Set(ctx, "a", []byte(`ingestion_rate_mb: 20`), nil)
+	require.NoError(t, err)
+	require.NoError(t, replica.reload(ctx))
+	assert.Equal(t, float64(20), replica.TenantLimits("a").IngestionRateMB)
+
+	// The overrides are deleted and created again between two
+	// reloads of the replica: the version does not start over.
+	require.NoError(t, m.Delete(ctx, "a", ptr(int64(1))))
+	_, err = m.Get("a")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = m.Set(ctx, "a", []byte(`ingestion_rate_mb: 30`), ptr(int64(1)))
+	assert.ErrorIs(t, err, ErrVersionMismatch)
+	o, err := m.Set(ctx, "a", []byte(`ingestion_rate_mb: 30`), ptr(int64(0)))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), o.Version)
+
+	// A stale version is not accepted.
+	_, err = m.Set(ctx, "a", []byte(`ingestion_rate_mb: 40`), ptr(int64(1)))
+	assert.ErrorIs(t, err, ErrVersionMismatch)
+
+	// The replica does not serve the limits cached for the old version.
+	require.NoError(t, replica.reload(ctx))
+	assert.Equal(t, float64(30), replica.TenantLimits("a").IngestionRateMB)
+	assert.Len(t, replica.List(), 1)
+}
+
+func Test_Manager_InvalidStoredOverrides(t *testing.T) {
+	bucket := objstore.NewInMemBucket()
+	require.NoError(t, bucket.Upload(context.Background(), objectName("a"), strings.NewReader(`
+version: 1
+overrides:
+  unknown_limit: 1
+`)))
+	fileLimits := validation.MockDefaultLimits()
+	m := newTestManager(t, bucket, staticTenantLimits{"a": fileLimits})
+	// The overrides that can't be applied are ignored.
+	assert.Same(t, fileLimits, m.TenantLimits("a"))
+}
+
+func Test_TenantOverridesHandler(t *testing.T) {
+	m := newTestManager(t, objstore.NewInMemBucket(), nil)
+	router := mux.NewRouter()
+	router.HandleFunc("/ops/overrides", m.OverridesHandler)
+	router.HandleFunc("/ops/overrides/{tenant}", m.TenantOverridesHandler)
+
+	do := func(method, path, ifMatch, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusNotFound, do(http.MethodGet, "/ops/overrides/a", "", "").Code)
+
+	rec := do(http.MethodPut, "/ops/overrides/a", `"0"`, "ingestion_rate_mb: 20\n")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, `"1"`, rec.Header().Get("ETag"))
+
+	rec = do(http.MethodGet, "/ops/overrides/a", "", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"1"`, rec.Header().Get("ETag"))
+	assert.Contains(t, rec.Body.String(), `"overrides":{"ingestion_rate_mb":20}`)
+	assert.Contains(t, rec.Body.String(), `"limits":{"ingestion_rate_mb":20,`)
+
+	assert.Equal(t, http.StatusPreconditionFailed, do(http.MethodPut, "/ops/overrides/a", `"0"`, "ingestion_rate_mb: 30").Code)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPut, "/ops/overrides/a", "", "unknown_limit: 1").Code)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPut, "/ops/overrides/a", "invalid", "ingestion_rate_mb: 30").Code)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodGet, "/ops/overrides/a%20b", "", "").Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, do(http.MethodPost, "/ops/overrides/a", "", "").Code)
+
+	rec = do(http.MethodGet, "/ops/overrides", "", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `[{"tenant":"a","version":1,"overrides":{"ingestion_rate_mb":20}}]`, rec.Body.String())
+
+	assert.Equal(t, http.StatusNoContent, do(http.MethodDelete, "/ops/overrides/a", `"1"`, "").Code)
+	assert.Equal(t, http.StatusNotFound, do(http.MethodDelete, "/ops/overrides/a", "", "").Code)
+}
+
+func Test_TenantOverridesHandler_ForwardsUpdatesToLeader(t *testing.T) {
+	bucket := objstore.NewInMemBucket()
+	ringStore := newTestRingStore(t)
+
+	type replica struct {
+		m      *Manager
+		server *httptest.Server
+	}
+	replicas := make([]*replica, 2)
+	for i := range replicas {
+		r := new(replica)
+		router := mux.NewRouter()
+		router.HandleFunc("/ops/overrides/{tenant}", func(w http.ResponseWriter, req *http.Request) {
+			r.m.TenantOverridesHandler(w, req)
+		})
+		r.server = httptest.NewServer(router)
+		t.Cleanup(r.server.Close)
+		port, err := strconv.Atoi(r.server.URL[strings.LastIndex(r.server.URL, ":")+1:])
+		require.NoError(t, err)
+		r.m = newTestReplica(t, bucket, nil, ringStore, fmt.Sprintf("instance-%d", i), port)
+		replicas[i] = r
+	}
+
+	// Wait for both replicas to see the same leader.
+	var leader, follower *replica
+	require.Eventually(t, func() bool {
+		a, errA := replicas[0].m.leader()
+		b, errB := replicas[1].m.leader()
+		if errA != nil || errB != nil || (a == "") == (b == "") {
+			return false
+		}
+		leader, follower = replicas[0], replicas[1]
+		if b == "" {
+			leader, follower = replicas[1], replicas[0]
+		}
+		return true
+	}, 10*time.Second, 10*time.Millisecond)
+
+	_, err := follower.m.Set(context.Background(), "a", []byte(`ingestion_rate_mb: 20`), nil)
+	require.ErrorIs(t, err, ErrNotLeader)
+
+	do := func(method, body string) *http.Response {
+		req, err := http.NewRequest(method, follower.server.URL+"/ops/overrides/a", strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("If-Match", `"0"`)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		return resp
+	}
+
+	// Concurrent updates of the same tenant through the follower: the leader
+	// applies them one by one, therefore only one of them succeeds.
+	codes := make(chan int, 2)
+	var wg sync.WaitGroup
+	for _, body := range []string{"ingestion_rate_mb: 20", "ingestion_rate_mb: 30"} {
+		wg.Add(1)
+		go func(body string) {
+			defer wg.Done()
+			codes <- do(http.MethodPut, body).StatusCode
+		}(body)
+	}
+	wg.Wait()
+	close(codes)
+	var statuses []int
+	for c := range codes {
+		statuses = append(statuses, c)
+	}
+	assert.ElementsMatch(t, []int{http.StatusOK, http.StatusPreconditionFailed}, statuses)
+
+	o, err := leader.m.Get("a")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), o.Version)
+	require.NoError(t, follower.m.reload(context.Background()))
+	o, err = follower.m.Get("a")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), o.Version)
+
+	// Updates of other tenants are stored separately.
+	_, err = leader.m.Set(context.Background(), "b", []byte(`ingestion_rate_mb: 40`), nil)
+	require.NoError(t, err)
+	require.NoError(t, follower.m.reload(context.Background()))
+	assert.Len(t, follower.m.List(), 2)
+}
+
+func ptr[T any](v T) *T { return &v }
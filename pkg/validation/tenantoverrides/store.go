@@ -0,0 +1,96 @@
+package tenantoverrides
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/thanos-io/objstore"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// overridesPrefix is the directory holding the overrides, one object
+	// per tenant, at the root of the bucket, next to the tenant directories.
+	overridesPrefix = "tenant-overrides/"
+	overridesSuffix = ".yaml"
+)
+
+// TenantOverrides are the limits overridden for a tenant through the API.
+type TenantOverrides struct {
+	// Version is incremented on every update of the overrides,
+	// including deletion.
+	Version int64 `yaml:"version" json:"version"`
+	// Overrides are the fields of the limits that are overridden,
+	// in the same format as in the runtime config file.
+	Overrides map[string]interface{} `yaml:"overrides" json:"overrides"`
+	// Deleted marks the overrides that have been deleted. The object is
+	// kept as a tombstone, so that the version of the overrides created
+	// afterwards does not start over: a version identifies the overrides
+	// for the lifetime of the tenant.
+	Deleted bool `yaml:"deleted,omitempty" json:"-"`
+}
+
+func (o *TenantOverrides) yaml() ([]byte, error) {
+	return yaml.Marshal(o.Overrides)
+}
+
+func objectName(tenantID string) string {
+	return overridesPrefix + tenantID + overridesSuffix
+}
+
+// readAll returns the overrides of all the tenants.
+func readAll(ctx context.Context, bucket objstore.Bucket) (map[string]*TenantOverrides, error) {
+	tenants := make(map[string]*TenantOverrides)
+	err := bucket.Iter(ctx, overridesPrefix, func(name string) error {
+		tenantID, ok := strings.CutSuffix(strings.TrimPrefix(name, overridesPrefix), overridesSuffix)
+		if !ok {
+			return nil
+		}
+		o, err := read(ctx, bucket, tenantID)
+		if err != nil || o == nil || o.Deleted {
+			return err
+		}
+		tenants[tenantID] = o
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tenants, nil
+}
+
+// read returns the overrides of the tenant, or nil, if the
+// tenant has no overrides.
+func read(ctx context.Context, bucket objstore.Bucket, tenantID string) (*TenantOverrides, error) {
+	name := objectName(tenantID)
+	r, err := bucket.Get(ctx, name)
+	if err != nil {
+		if bucket.IsObjNotFoundErr(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var o TenantOverrides
+	if err = yaml.Unmarshal(b, &o); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", name, err)
+	}
+	return &o, nil
+}
+
+func write(ctx context.Context, bucket objstore.Bucket, tenantID string, o *TenantOverrides) error {
+	b, err := yaml.Marshal(o)
+	if err != nil {
+		return err
+	}
+	return bucket.Upload(ctx, objectName(tenantID), bytes.NewReader(b))
+}
@@ -11,6 +11,11 @@ type MockLimits struct {
 	MaxQueryLookbackValue           time.Duration
 	QueryAnalysisEnabledValue       bool
 	QueryAnalysisSeriesEnabledValue bool
+	QueryCostEstimationEnabledValue bool
+	MaxQueryCostInFlightValue       int
+	MaxQueryBytesScannedValue       int
+	MaxQuerySeriesValue             int
+	MaxQueryProfilesValue           int
 	MaxLabelNameLengthValue         int
 	MaxLabelValueLengthValue        int
 	MaxLabelNamesPerSeriesValue     int
@@ -31,6 +36,12 @@ type MockLimits struct {
 	MaxProfileSymbolValueLengthValue      int
 
 	MaxQueriersPerTenantValue int
+
+	AdHocProfilesMaxTotalSizeBytesValue int
+	AdHocProfilesRetentionPeriodValue   time.Duration
+
+	VCSLocalRepositoriesDirValue           string
+	VCSLocalRepositoriesFetchIntervalValue time.Duration
 }
 
 func (m MockLimits) QuerySplitDuration(string) time.Duration        { return m.QuerySplitDurationValue }
@@ -42,9 +53,29 @@ func (m MockLimits) QueryAnalysisSeriesEnabled(tenantID string) bool {
 	return m.QueryAnalysisSeriesEnabledValue
 }
 
+func (m MockLimits) QueryCostEstimationEnabled(tenantID string) bool {
+	return m.QueryCostEstimationEnabledValue
+}
+func (m MockLimits) MaxQueryCostInFlight(tenantID string) int { return m.MaxQueryCostInFlightValue }
+func (m MockLimits) MaxQueryBytesScanned(tenantID string) int { return m.MaxQueryBytesScannedValue }
+func (m MockLimits) MaxQuerySeries(tenantID string) int       { return m.MaxQuerySeriesValue }
+func (m MockLimits) MaxQueryProfiles(tenantID string) int     { return m.MaxQueryProfilesValue }
+
 func (m MockLimits) MaxFlameGraphNodesDefault(string) int { return m.MaxFlameGraphNodesDefaultValue }
 func (m MockLimits) MaxFlameGraphNodesMax(string) int     { return m.MaxFlameGraphNodesMaxValue }
 
+func (m MockLimits) AdHocProfilesMaxTotalSizeBytes(string) int {
+	return m.AdHocProfilesMaxTotalSizeBytesValue
+}
+func (m MockLimits) AdHocProfilesRetentionPeriod(string) time.Duration {
+	return m.AdHocProfilesRetentionPeriodValue
+}
+
+func (m MockLimits) VCSLocalRepositoriesDir(string) string { return m.VCSLocalRepositoriesDirValue }
+func (m MockLimits) VCSLocalRepositoriesFetchInterval(string) time.Duration {
+	return m.VCSLocalRepositoriesFetchIntervalValue
+}
+
 func (m MockLimits) MaxLabelNameLength(userID string) int     { return m.MaxLabelNameLengthValue }
 func (m MockLimits) MaxLabelValueLength(userID string) int    { return m.MaxLabelValueLengthValue }
 func (m MockLimits) MaxLabelNamesPerSeries(userID string) int { return m.MaxLabelNamesPerSeriesValue }
@@ -74,6 +74,9 @@ const (
 	MaxFlameGraphNodesErrorMsg          = "max flamegraph nodes limit %d is greater than allowed %d"
 	MaxFlameGraphNodesUnlimitedErrorMsg = "max flamegraph nodes limit must be set (max allowed %d)"
 	QueryMissingTimeRangeErrorMsg       = "missing time range in the query"
+	QueryTooManyBytesErrorMsg           = "the query exceeds the limit of bytes scanned (max_query_bytes_scanned, actual: %d, limit: %d); narrow down the query with a shorter time range or a more specific label selector"
+	QueryTooManySeriesErrorMsg          = "the query exceeds the limit of series (max_query_series, actual: %d, limit: %d); narrow down the query with a shorter time range or a more specific label selector"
+	QueryTooManyProfilesErrorMsg        = "the query exceeds the limit of profiles (max_query_profiles, actual: %d, limit: %d); narrow down the query with a shorter time range or a more specific label selector"
 )
 
 var (
@@ -410,3 +413,39 @@ func ValidateMaxNodes(l FlameGraphLimits, tenantIDs []string, n int64) (int64, e
 	}
 	return n, nil
 }
+
+type QueryImpactLimits interface {
+	MaxQueryBytesScanned(tenantID string) int
+	MaxQuerySeries(tenantID string) int
+	MaxQueryProfiles(tenantID string) int
+}
+
+// QueryImpact describes the amount of data a query selects.
+type QueryImpact struct {
+	Bytes    uint64
+	Series   uint64
+	Profiles uint64
+}
+
+// QueryImpactLimitsEnabled reports whether any of the query impact
+// limits is set for the tenants.
+func QueryImpactLimitsEnabled(l QueryImpactLimits, tenantIDs []string) bool {
+	return validation.SmallestPositiveNonZeroIntPerTenant(tenantIDs, l.MaxQueryBytesScanned) > 0 ||
+		validation.SmallestPositiveNonZeroIntPerTenant(tenantIDs, l.MaxQuerySeries) > 0 ||
+		validation.SmallestPositiveNonZeroIntPerTenant(tenantIDs, l.MaxQueryProfiles) > 0
+}
+
+// ValidateQueryImpact returns an error if the query exceeds any of the
+// query impact limits of the tenants.
+func ValidateQueryImpact(l QueryImpactLimits, tenantIDs []string, impact QueryImpact) error {
+	if limit := validation.SmallestPositiveNonZeroIntPerTenant(tenantIDs, l.MaxQuerySeries); limit > 0 && impact.Series > uint64(limit) {
+		return NewErrorf(QueryLimit, QueryTooManySeriesErrorMsg, impact.Series, limit)
+	}
+	if limit := validation.SmallestPositiveNonZeroIntPerTenant(tenantIDs, l.MaxQueryProfiles); limit > 0 && impact.Profiles > uint64(limit) {
+		return NewErrorf(QueryLimit, QueryTooManyProfilesErrorMsg, impact.Profiles, limit)
+	}
+	if limit := validation.SmallestPositiveNonZeroIntPerTenant(tenantIDs, l.MaxQueryBytesScanned); limit > 0 && impact.Bytes > uint64(limit) {
+		return NewErrorf(QueryLimit, QueryTooManyBytesErrorMsg, impact.Bytes, limit)
+	}
+	return nil
+}
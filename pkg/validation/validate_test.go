@@ -438,6 +438,54 @@ func TestValidateFlamegraphMaxNodes(t *testing.T) {
 	}
 }
 
+func TestValidateQueryImpact(t *testing.T) {
+	limits := MockLimits{
+		MaxQueryBytesScannedValue: 1000,
+		MaxQuerySeriesValue:       10,
+		MaxQueryProfilesValue:     100,
+	}
+	for _, tc := range []struct {
+		name   string
+		limits QueryImpactLimits
+		impact QueryImpact
+		err    error
+	}{
+		{
+			name:   "within limits",
+			limits: limits,
+			impact: QueryImpact{Bytes: 1000, Series: 10, Profiles: 100},
+		},
+		{
+			name:   "limits disabled",
+			limits: MockLimits{},
+			impact: QueryImpact{Bytes: 1 << 40, Series: 1 << 20, Profiles: 1 << 30},
+		},
+		{
+			name:   "too many series",
+			limits: limits,
+			impact: QueryImpact{Bytes: 1, Series: 11, Profiles: 1},
+			err:    NewErrorf(QueryLimit, QueryTooManySeriesErrorMsg, 11, 10),
+		},
+		{
+			name:   "too many profiles",
+			limits: limits,
+			impact: QueryImpact{Bytes: 1, Series: 1, Profiles: 101},
+			err:    NewErrorf(QueryLimit, QueryTooManyProfilesErrorMsg, 101, 100),
+		},
+		{
+			name:   "too many bytes",
+			limits: limits,
+			impact: QueryImpact{Bytes: 1001, Series: 1, Profiles: 1},
+			err:    NewErrorf(QueryLimit, QueryTooManyBytesErrorMsg, 1001, 1000),
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.err, ValidateQueryImpact(tc.limits, []string{"tenant"}, tc.impact))
+		})
+	}
+}
+
 func Test_SanitizeLabelName(t *testing.T) {
 	for _, tc := range []struct {
 		input    string